@@ -1,7 +1,9 @@
 package cdk
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
@@ -26,36 +28,89 @@ func New() *CDK {
 
 // CDKApp represents an FTL application being built
 type CDKApp struct {
-	Name        string         `json:"name"`
-	Version     string         `json:"version"`
-	Description string         `json:"description,omitempty"`
-	Components  []CDKComponent `json:"components,omitempty"`
-	Access      string         `json:"access,omitempty"`
-	Auth        *CDKAuth       `json:"auth,omitempty"`
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description,omitempty"`
+	Components  []CDKComponent  `json:"components,omitempty"`
+	Access      string          `json:"access,omitempty"`
+	Auth        *CDKAuth        `json:"auth,omitempty"`
+	Regions     []string        `json:"regions,omitempty"`
+	Middleware  []CDKMiddleware `json:"middleware,omitempty"`
 }
 
 // CDKComponent represents a Wasm component in the application
 type CDKComponent struct {
+	ID          string                       `json:"id"`
+	Source      interface{}                  `json:"source,omitempty"` // string for local, map for registry
+	Build       *CDKBuildConfig              `json:"build,omitempty"`
+	Variables   map[string]string            `json:"variables,omitempty"`
+	Transforms  map[string]*CDKToolTransform `json:"transforms,omitempty"` // keyed by tool name, or "*" for all tools
+	Gateway     *CDKGatewayRoute             `json:"gateway,omitempty"`
+	CallTools   bool                         `json:"call_tools,omitempty"`
+	Sampling    bool                         `json:"sampling,omitempty"`
+	Elicitation bool                         `json:"elicitation,omitempty"`
+	Idempotency bool                         `json:"idempotency,omitempty"`
+	Secrets     []string                     `json:"secrets,omitempty"`
+
+	KeyValueStores  []string `json:"key_value_stores,omitempty"`
+	SQLiteDatabases []string `json:"sqlite_databases,omitempty"`
+
+	ServiceDependencies []string `json:"service_dependencies,omitempty"`
+
+	refs map[string]componentRef // variables set from other components
+}
+
+// CDKMiddleware represents a component in the gateway's request chain,
+// which sees every request before the tool components
+type CDKMiddleware struct {
 	ID        string            `json:"id"`
-	Source    interface{}       `json:"source"` // string for local, map for registry
+	Source    interface{}       `json:"source,omitempty"` // string for local, map for registry
 	Build     *CDKBuildConfig   `json:"build,omitempty"`
 	Variables map[string]string `json:"variables,omitempty"`
+
+	refs map[string]componentRef // variables set from other components
+}
+
+// CDKToolTransform represents input transformations the gateway applies to
+// a tool's arguments before calling the component
+type CDKToolTransform struct {
+	Rename   map[string]string      `json:"rename,omitempty"`
+	Defaults map[string]interface{} `json:"defaults,omitempty"`
+	Set      map[string]interface{} `json:"set,omitempty"`
 }
 
-// CDKBuildConfig represents build configuration
+// CDKGatewayRoute represents how the gateway calls a component's tools
+type CDKGatewayRoute struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	Retries        int `json:"retries,omitempty"`
+	BackoffMS      int `json:"backoff_ms,omitempty"`
+}
+
+// CDKBuildConfig represents build configuration. A Profile derives the
+// command, watch patterns and source that are not set.
 type CDKBuildConfig struct {
-	Command string   `json:"command"`
+	Profile string   `json:"profile,omitempty"`
+	Flags   []string `json:"flags,omitempty"`
+	Command string   `json:"command,omitempty"`
 	WorkDir string   `json:"workdir,omitempty"`
 	Watch   []string `json:"watch,omitempty"`
 }
 
 // CDKAuth represents authentication configuration for custom access mode
 type CDKAuth struct {
-	JWTIssuer         string   `json:"jwt_issuer"`
-	JWTAudience       string   `json:"jwt_audience"`
-	JWTRequiredScopes []string `json:"jwt_required_scopes,omitempty"`
+	JWTIssuer         string      `json:"jwt_issuer"`
+	JWTAudience       string      `json:"jwt_audience"`
+	JWTJwksURI        string      `json:"jwt_jwks_uri,omitempty"`
+	JWTRequiredScopes []string    `json:"jwt_required_scopes,omitempty"`
+	Policy            string      `json:"policy,omitempty"`
+	PolicyData        interface{} `json:"policy_data,omitempty"` // string JSON or object
 }
 
+// ExportEnv names the environment variable that switches Synthesize from
+// producing a Spin manifest to exporting the application as JSON
+// configuration. 'ftl config convert' sets it to read Go configuration.
+const ExportEnv = "FTL_CDK_EXPORT"
+
 // AppBuilder provides a fluent interface for building applications
 type AppBuilder struct {
 	cdk *CDK
@@ -95,6 +150,12 @@ func (ab *AppBuilder) SetAccess(access string) *AppBuilder {
 	return ab
 }
 
+// SetRegions sets the regions the platform deploys the application to
+func (ab *AppBuilder) SetRegions(regions ...string) *AppBuilder {
+	ab.app.Regions = regions
+	return ab
+}
+
 // SetPrivateAccess enables FTL platform authentication (user-only access)
 func (ab *AppBuilder) SetPrivateAccess() *AppBuilder {
 	ab.app.Access = "private"
@@ -113,14 +174,39 @@ func (ab *AppBuilder) SetOrgAccess() *AppBuilder {
 
 // SetCustomAuth enables custom JWT authentication
 func (ab *AppBuilder) SetCustomAuth(issuer, audience string) *AppBuilder {
-	ab.app.Auth = &CDKAuth{
-		JWTIssuer:   issuer,
-		JWTAudience: audience,
-	}
+	auth := ab.auth()
+	auth.JWTIssuer = issuer
+	auth.JWTAudience = audience
 	ab.app.Access = "custom"
 	return ab
 }
 
+// SetJWKSURI sets the JWKS endpoint used to discover custom JWT signing keys
+func (ab *AppBuilder) SetJWKSURI(uri string) *AppBuilder {
+	ab.auth().JWTJwksURI = uri
+	return ab
+}
+
+// SetPolicy sets the Rego authorization policy for custom access
+func (ab *AppBuilder) SetPolicy(policy string) *AppBuilder {
+	ab.auth().Policy = policy
+	return ab
+}
+
+// SetPolicyData sets the data the authorization policy is evaluated with,
+// either a JSON string or an object
+func (ab *AppBuilder) SetPolicyData(data interface{}) *AppBuilder {
+	ab.auth().PolicyData = data
+	return ab
+}
+
+func (ab *AppBuilder) auth() *CDKAuth {
+	if ab.app.Auth == nil {
+		ab.app.Auth = &CDKAuth{}
+	}
+	return ab.app.Auth
+}
+
 // AddComponent adds a Wasm component to the application
 func (ab *AppBuilder) AddComponent(id string) *ComponentBuilder {
 	return &ComponentBuilder{
@@ -132,6 +218,16 @@ func (ab *AppBuilder) AddComponent(id string) *ComponentBuilder {
 	}
 }
 
+// AddMiddleware adds a component to the gateway's request chain. Requests
+// pass through middleware in the order it is added, before reaching the
+// tool components.
+func (ab *AppBuilder) AddMiddleware(id string) *MiddlewareBuilder {
+	return &MiddlewareBuilder{
+		app:        ab,
+		middleware: CDKMiddleware{ID: id},
+	}
+}
+
 // Build finalizes the application and returns the CDK
 func (ab *AppBuilder) Build() *CDK {
 	ab.cdk.app = ab.app
@@ -169,6 +265,28 @@ func (cb *ComponentBuilder) WithBuild(command string) *ComponentBuilder {
 	return cb
 }
 
+// WithBuildProfile builds the component with a language build profile:
+// "rust", "typescript", "python" or "go". The profile derives the build
+// command, the watch patterns and, without FromLocal, the source, from
+// the component's workdir. Flags are passed to the profile's compiler.
+func (cb *ComponentBuilder) WithBuildProfile(profile string, flags ...string) *ComponentBuilder {
+	if cb.component.Build == nil {
+		cb.component.Build = &CDKBuildConfig{}
+	}
+	cb.component.Build.Profile = profile
+	cb.component.Build.Flags = append(cb.component.Build.Flags, flags...)
+	return cb
+}
+
+// WithWorkdir sets the directory the build command runs in
+func (cb *ComponentBuilder) WithWorkdir(dir string) *ComponentBuilder {
+	if cb.component.Build == nil {
+		cb.component.Build = &CDKBuildConfig{}
+	}
+	cb.component.Build.WorkDir = dir
+	return cb
+}
+
 // WithWatch adds watch patterns for development
 func (cb *ComponentBuilder) WithWatch(patterns ...string) *ComponentBuilder {
 	if cb.component.Build == nil {
@@ -184,23 +302,248 @@ func (cb *ComponentBuilder) WithEnv(key, value string) *ComponentBuilder {
 		cb.component.Variables = make(map[string]string)
 	}
 	cb.component.Variables[key] = value
+	delete(cb.component.refs, key)
+	return cb
+}
+
+// WithRenamedArgument renames a tool's argument from the name clients send
+// to the name the component expects. Use "*" as the tool for all tools.
+func (cb *ComponentBuilder) WithRenamedArgument(tool, from, to string) *ComponentBuilder {
+	t := cb.transform(tool)
+	if t.Rename == nil {
+		t.Rename = make(map[string]string)
+	}
+	t.Rename[from] = to
+	return cb
+}
+
+// WithDefaultArgument sets a value for a tool's argument when clients leave
+// it out. Strings may reference token claims as ${claims.NAME}.
+func (cb *ComponentBuilder) WithDefaultArgument(tool, name string, value interface{}) *ComponentBuilder {
+	t := cb.transform(tool)
+	if t.Defaults == nil {
+		t.Defaults = make(map[string]interface{})
+	}
+	t.Defaults[name] = value
+	return cb
+}
+
+// WithFixedArgument sets a tool's argument whatever clients send, such as
+// a tenant ID from the token: WithFixedArgument("*", "tenant", "${claims.org_id}")
+func (cb *ComponentBuilder) WithFixedArgument(tool, name string, value interface{}) *ComponentBuilder {
+	t := cb.transform(tool)
+	if t.Set == nil {
+		t.Set = make(map[string]interface{})
+	}
+	t.Set[name] = value
+	return cb
+}
+
+// WithCallTimeout makes the gateway fail calls of the component's tools
+// that run longer than the given number of seconds
+func (cb *ComponentBuilder) WithCallTimeout(seconds int) *ComponentBuilder {
+	cb.gateway().TimeoutSeconds = seconds
+	return cb
+}
+
+// WithCallRetries makes the gateway retry calls that could not reach the
+// component up to retries times, waiting backoffMS milliseconds before the
+// first retry and twice as long before each further one. Calls that
+// reached the component are never retried.
+func (cb *ComponentBuilder) WithCallRetries(retries, backoffMS int) *ComponentBuilder {
+	route := cb.gateway()
+	route.Retries = retries
+	route.BackoffMS = backoffMS
 	return cb
 }
 
+// WithToolCalls allows the component's tools to call other tools through
+// the gateway, e.g. with the Go SDK's ftl.CallTool
+func (cb *ComponentBuilder) WithToolCalls() *ComponentBuilder {
+	cb.component.CallTools = true
+	return cb
+}
+
+// WithSampling allows the component's tools to ask the client's model for
+// completions through the gateway, e.g. with the Go SDK's ftl.RequestSampling
+func (cb *ComponentBuilder) WithSampling() *ComponentBuilder {
+	cb.component.Sampling = true
+	return cb
+}
+
+// WithElicitation allows the component's tools to ask the user for input
+// through the gateway, e.g. with the Go SDK's ftl.Elicit
+func (cb *ComponentBuilder) WithElicitation() *ComponentBuilder {
+	cb.component.Elicitation = true
+	return cb
+}
+
+// WithIdempotency gives the component the default key-value store, where
+// the Go SDK keeps responses of tools using ftl.WithIdempotency
+func (cb *ComponentBuilder) WithIdempotency() *ComponentBuilder {
+	cb.component.Idempotency = true
+	return cb
+}
+
+// WithKeyValueStores gives the component the key-value stores with the
+// given labels, or the default store when none are given. 'ftl up'
+// configures local stores for labels other than "default".
+func (cb *ComponentBuilder) WithKeyValueStores(labels ...string) *ComponentBuilder {
+	if len(labels) == 0 {
+		labels = []string{"default"}
+	}
+	cb.component.KeyValueStores = append(cb.component.KeyValueStores, labels...)
+	return cb
+}
+
+// WithSQLiteDatabases gives the component the SQLite databases with the
+// given labels, or the default database when none are given. 'ftl up'
+// configures local databases for labels other than "default".
+func (cb *ComponentBuilder) WithSQLiteDatabases(labels ...string) *ComponentBuilder {
+	if len(labels) == 0 {
+		labels = []string{"default"}
+	}
+	cb.component.SQLiteDatabases = append(cb.component.SQLiteDatabases, labels...)
+	return cb
+}
+
+// WithSecrets lets the component read secrets as variables of the same
+// name. Values are provided by the runtime, not the configuration.
+func (cb *ComponentBuilder) WithSecrets(names ...string) *ComponentBuilder {
+	cb.component.Secrets = append(cb.component.Secrets, names...)
+	return cb
+}
+
+func (cb *ComponentBuilder) transform(tool string) *CDKToolTransform {
+	if cb.component.Transforms == nil {
+		cb.component.Transforms = make(map[string]*CDKToolTransform)
+	}
+	if cb.component.Transforms[tool] == nil {
+		cb.component.Transforms[tool] = &CDKToolTransform{}
+	}
+	return cb.component.Transforms[tool]
+}
+
+func (cb *ComponentBuilder) gateway() *CDKGatewayRoute {
+	if cb.component.Gateway == nil {
+		cb.component.Gateway = &CDKGatewayRoute{}
+	}
+	return cb.component.Gateway
+}
+
 // Build completes the component and returns to the app builder
 func (cb *ComponentBuilder) Build() *AppBuilder {
 	cb.app.app.Components = append(cb.app.app.Components, cb.component)
 	return cb.app
 }
 
+// MiddlewareBuilder provides a fluent interface for building middleware
+type MiddlewareBuilder struct {
+	app        *AppBuilder
+	middleware CDKMiddleware
+}
+
+// FromLocal sets the middleware source as a local path
+func (mb *MiddlewareBuilder) FromLocal(path string) *MiddlewareBuilder {
+	mb.middleware.Source = path
+	return mb
+}
+
+// FromRegistry sets the middleware source from a registry
+func (mb *MiddlewareBuilder) FromRegistry(registry, pkg, version string) *MiddlewareBuilder {
+	mb.middleware.Source = map[string]string{
+		"registry": registry,
+		"package":  pkg,
+		"version":  version,
+	}
+	return mb
+}
+
+// WithBuild sets the build configuration
+func (mb *MiddlewareBuilder) WithBuild(command string) *MiddlewareBuilder {
+	mb.build().Command = command
+	return mb
+}
+
+// WithBuildProfile builds the middleware with a language build profile,
+// as ComponentBuilder.WithBuildProfile does
+func (mb *MiddlewareBuilder) WithBuildProfile(profile string, flags ...string) *MiddlewareBuilder {
+	mb.build().Profile = profile
+	mb.build().Flags = append(mb.build().Flags, flags...)
+	return mb
+}
+
+// WithWorkdir sets the directory the build command runs in
+func (mb *MiddlewareBuilder) WithWorkdir(dir string) *MiddlewareBuilder {
+	mb.build().WorkDir = dir
+	return mb
+}
+
+// WithWatch adds watch patterns for development
+func (mb *MiddlewareBuilder) WithWatch(patterns ...string) *MiddlewareBuilder {
+	mb.build().Watch = append(mb.build().Watch, patterns...)
+	return mb
+}
+
+// WithEnv adds environment variables
+func (mb *MiddlewareBuilder) WithEnv(key, value string) *MiddlewareBuilder {
+	if mb.middleware.Variables == nil {
+		mb.middleware.Variables = make(map[string]string)
+	}
+	mb.middleware.Variables[key] = value
+	delete(mb.middleware.refs, key)
+	return mb
+}
+
+func (mb *MiddlewareBuilder) build() *CDKBuildConfig {
+	if mb.middleware.Build == nil {
+		mb.middleware.Build = &CDKBuildConfig{}
+	}
+	return mb.middleware.Build
+}
+
+// Build completes the middleware and returns to the app builder
+func (mb *MiddlewareBuilder) Build() *AppBuilder {
+	mb.app.app.Middleware = append(mb.app.app.Middleware, mb.middleware)
+	return mb.app
+}
+
 // Synthesize produces a Spin manifest from the CDK application
 func (cdk *CDK) Synthesize() (string, error) {
 	if cdk.app == nil {
 		return "", fmt.Errorf("no application defined - call Build() first")
 	}
 
+	if os.Getenv(ExportEnv) == "json" {
+		return cdk.ToJSON()
+	}
+
+	app, err := cdk.resolveRefs()
+	if err != nil {
+		return "", err
+	}
+
 	// Use the synthesizer to transform the struct to a Spin manifest
-	return cdk.synthesizer.SynthesizeFromStruct(cdk.app)
+	return cdk.synthesizer.SynthesizeFromStruct(app)
+}
+
+// ToJSON exports the current application as FTL JSON configuration
+func (cdk *CDK) ToJSON() (string, error) {
+	if cdk.app == nil {
+		return "", fmt.Errorf("no application defined - call Build() first")
+	}
+
+	// JSON configuration has no references, so they are exported resolved
+	app, err := cdk.resolveRefs()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(app, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode app: %w", err)
+	}
+	return string(data) + "\n", nil
 }
 
 // ToCUE exports the current application as CUE source
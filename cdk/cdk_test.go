@@ -1,6 +1,7 @@
 package cdk
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -162,6 +163,31 @@ func TestCDK_BuildConfig(t *testing.T) {
 	}
 }
 
+func TestCDK_BuildProfile(t *testing.T) {
+	cdk := New()
+	app := cdk.NewApp("geo-app")
+
+	app.AddComponent("geo-tool").
+		WithBuildProfile("go", "-opt=2").
+		WithWorkdir("geo-tool").
+		Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	if !strings.Contains(manifest, `source = 'geo-tool/main.wasm'`) {
+		t.Errorf("Missing source derived from the profile:\n%s", manifest)
+	}
+	if !strings.Contains(manifest, `command = 'tinygo build -target=wasip1 -gc=leaking -scheduler=none -no-debug -opt=2 -o main.wasm .'`) {
+		t.Errorf("Missing command derived from the profile:\n%s", manifest)
+	}
+	if strings.Contains(manifest, "profile =") {
+		t.Error("The profile should not reach spin.toml")
+	}
+}
+
 func TestCDK_ToCUE(t *testing.T) {
 	cdk := New()
 	app := cdk.NewApp("cue-test").
@@ -243,3 +269,343 @@ func TestCDK_WithEnv(t *testing.T) {
 		t.Error("LOG_LEVEL environment variable not found")
 	}
 }
+
+func TestCDK_ExportJSON(t *testing.T) {
+	t.Setenv(ExportEnv, "json")
+
+	app := New().NewApp("export-test").
+		SetCustomAuth("https://auth.example.com", "api").
+		SetJWKSURI("https://auth.example.com/.well-known/jwks.json").
+		SetPolicy("package mcp.authorization\ndefault allow := true").
+		SetPolicyData(map[string]interface{}{"admins": []string{"alice"}})
+
+	app.AddComponent("tool").
+		FromLocal("./tool/tool.wasm").
+		WithBuild("make build").
+		WithWorkdir("tool").
+		Build()
+
+	out, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if strings.Contains(out, "spin_manifest_version") {
+		t.Fatal("Export should produce configuration, not a manifest")
+	}
+
+	var config CDKApp
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		t.Fatalf("Export is not valid JSON: %v", err)
+	}
+	if config.Access != "custom" || config.Auth.JWTJwksURI == "" || config.Auth.Policy == "" || config.Auth.PolicyData == nil {
+		t.Errorf("Auth not exported: %+v", config.Auth)
+	}
+	if config.Components[0].Build.WorkDir != "tool" {
+		t.Errorf("Build workdir not exported: %+v", config.Components[0].Build)
+	}
+}
+
+func TestCDK_SetRegions(t *testing.T) {
+	app := New().NewApp("regional").SetRegions("us-east-1", "eu-west-1")
+	app.AddComponent("tool").FromLocal("./tool.wasm").Build()
+	built := app.Build()
+
+	// Regions are deployment settings and do not change the manifest
+	if _, err := built.Synthesize(); err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	out, err := built.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	var config CDKApp
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		t.Fatalf("Export is not valid JSON: %v", err)
+	}
+	if strings.Join(config.Regions, ",") != "us-east-1,eu-west-1" {
+		t.Errorf("Regions not exported: %v", config.Regions)
+	}
+
+	app.SetRegions("Not A Region")
+	if _, err := built.Synthesize(); err == nil {
+		t.Error("Invalid region should fail validation")
+	}
+}
+
+func TestCDK_ToolTransforms(t *testing.T) {
+	app := New().NewApp("transforms").SetAccess("private")
+	app.AddComponent("search").
+		FromRegistry("ghcr.io", "acme:search", "1.0.0").
+		WithRenamedArgument("query", "query", "q").
+		WithDefaultArgument("query", "limit", 10).
+		WithFixedArgument("*", "tenant", "${claims.org_id}").
+		Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	want := `tool_transforms = '{"search":{"*":{"set":{"tenant":"${claims.org_id}"}},"query":{"rename":{"query":"q"},"defaults":{"limit":10}}}}'`
+	if !strings.Contains(manifest, want) {
+		t.Errorf("Transforms not passed to the gateway:\n%s", manifest)
+	}
+}
+
+func TestCDK_CallTimeoutAndRetries(t *testing.T) {
+	app := New().NewApp("routes")
+	app.AddComponent("search").
+		FromLocal("./search.wasm").
+		WithCallTimeout(10).
+		WithCallRetries(2, 200).
+		Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	want := `component_routes = '{"search":{"timeout_seconds":10,"retries":2,"backoff_ms":200}}'`
+	if !strings.Contains(manifest, want) {
+		t.Errorf("Call policy not passed to the gateway:\n%s", manifest)
+	}
+}
+
+func TestCDK_WithToolCalls(t *testing.T) {
+	app := New().NewApp("composite")
+	app.AddComponent("planner").FromLocal("./planner.wasm").WithToolCalls().Build()
+	app.AddComponent("worker").FromLocal("./worker.wasm").Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	planner, worker, _ := strings.Cut(strings.SplitN(manifest, "[component.planner]", 2)[1], "[component.worker]")
+	if !strings.Contains(planner, "allowed_outbound_hosts = ['http://mcp-gateway.spin.internal']") {
+		t.Errorf("planner should be allowed to reach the gateway:\n%s", planner)
+	}
+	if strings.Contains(worker, "allowed_outbound_hosts") {
+		t.Errorf("worker should not reach the gateway:\n%s", worker)
+	}
+}
+
+func TestCDK_WithSampling(t *testing.T) {
+	app := New().NewApp("writer")
+	app.AddComponent("summarize").FromLocal("./summarize.wasm").WithSampling().Build()
+	app.AddComponent("planner").FromLocal("./planner.wasm").WithToolCalls().WithSampling().Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	for _, id := range []string{"summarize", "planner"} {
+		section, _, _ := strings.Cut(strings.SplitN(manifest, "[component."+id+"]", 2)[1], "[component.")
+		if !strings.Contains(section, "allowed_outbound_hosts = ['http://mcp-gateway.spin.internal']") {
+			t.Errorf("%s should be allowed to reach the gateway:\n%s", id, section)
+		}
+	}
+}
+
+func TestCDK_WithElicitation(t *testing.T) {
+	app := New().NewApp("booking")
+	app.AddComponent("book").FromLocal("./book.wasm").WithElicitation().Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	book, _, _ := strings.Cut(strings.SplitN(manifest, "[component.book]", 2)[1], "[component.")
+	if !strings.Contains(book, "allowed_outbound_hosts = ['http://mcp-gateway.spin.internal']") {
+		t.Errorf("book should be allowed to reach the gateway:\n%s", book)
+	}
+}
+
+func TestCDK_WithIdempotency(t *testing.T) {
+	app := New().NewApp("payments")
+	app.AddComponent("charge").FromLocal("./charge.wasm").WithIdempotency().Build()
+	app.AddComponent("lookup").FromLocal("./lookup.wasm").Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	charge, rest, _ := strings.Cut(strings.SplitN(manifest, "[component.charge]", 2)[1], "[component.lookup]")
+	lookup, _, _ := strings.Cut(rest, "[component.mcp-gateway]")
+	if !strings.Contains(charge, "key_value_stores = ['default']") {
+		t.Errorf("charge should have the default store:\n%s", charge)
+	}
+	if strings.Contains(lookup, "key_value_stores") {
+		t.Errorf("lookup should not have a store:\n%s", lookup)
+	}
+}
+
+func TestCDK_WithStores(t *testing.T) {
+	app := New().NewApp("analytics")
+	app.AddComponent("events").FromLocal("./events.wasm").
+		WithIdempotency().
+		WithKeyValueStores("default", "sessions").
+		WithSQLiteDatabases().
+		Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	events, _, _ := strings.Cut(strings.SplitN(manifest, "[component.events]", 2)[1], "[component.")
+	if !strings.Contains(events, "key_value_stores = ['default', 'sessions']") {
+		t.Errorf("events should have the default and sessions stores once:\n%s", events)
+	}
+	if !strings.Contains(events, "sqlite_databases = ['default']") {
+		t.Errorf("events should have the default database:\n%s", events)
+	}
+}
+
+func TestCDK_WithSecrets(t *testing.T) {
+	app := New().NewApp("weather")
+	app.AddComponent("forecast").FromLocal("./forecast.wasm").
+		WithEnv("units", "metric").
+		WithSecrets("weather_api_key").
+		Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	forecast, _, _ := strings.Cut(strings.SplitN(manifest, "[component.forecast]", 2)[1], "[component.mcp-gateway]")
+	for _, want := range []string{"units = 'metric'", "weather_api_key = '{{ weather_api_key }}'"} {
+		if !strings.Contains(forecast, want) {
+			t.Errorf("forecast missing %s:\n%s", want, forecast)
+		}
+	}
+	if !strings.Contains(manifest, "[variables.weather_api_key]") {
+		t.Errorf("secret should be an application variable:\n%s", manifest)
+	}
+}
+
+func TestCDK_AddMiddleware(t *testing.T) {
+	app := New().NewApp("guarded")
+	app.AddMiddleware("rate-limiter").
+		FromRegistry("ghcr.io", "acme:rate-limiter", "1.0.0").
+		WithEnv("limit", "100").
+		Build()
+	app.AddMiddleware("audit-log").FromLocal("./audit.wasm").WithBuild("make").Build()
+	app.AddComponent("echo").FromLocal("./echo.wasm").Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	for _, want := range []string{
+		"middleware_names = 'rate-limiter,audit-log'",
+		"component_names = 'echo'",
+		"[component.rate-limiter.source]",
+		"limit = '100'",
+		"[component.audit-log.build]",
+		"component = 'audit-log'",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("manifest missing %s:\n%s", want, manifest)
+		}
+	}
+}
+
+func TestCDK_WithEnvFromComponent(t *testing.T) {
+	app := New().NewApp("wired")
+	app.AddComponent("orchestrator").FromLocal("./orchestrator.wasm").
+		WithEnvFromComponent("gateway_url", "mcp-gateway", OutputURL).
+		WithEnvFromComponent("gateway_route", "mcp-gateway", OutputRoute).
+		WithEnvFromComponent("tools", "mcp-gateway", "variables.component_names").
+		WithEnvFromComponent("worker", "worker", OutputID).
+		Build()
+	app.AddComponent("worker").FromLocal("./worker.wasm").Build()
+	cdk := app.Build()
+
+	manifest, err := cdk.Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+	for _, want := range []string{
+		"gateway_url = 'http://mcp-gateway.spin.internal'",
+		"gateway_route = '/...'",
+		"tools = 'orchestrator,worker'",
+		"worker = 'worker'",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("manifest missing %s:\n%s", want, manifest)
+		}
+	}
+
+	exported, err := cdk.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if !strings.Contains(exported, `"gateway_url": "http://mcp-gateway.spin.internal"`) {
+		t.Errorf("exported JSON should hold resolved values:\n%s", exported)
+	}
+}
+
+func TestCDK_WithServiceDependency(t *testing.T) {
+	app := New().NewApp("weather")
+	app.AddComponent("forecast").FromLocal("./forecast.wasm").
+		WithToolCalls().
+		WithServiceDependency("geo-lookup").
+		Build()
+	app.AddComponent("geo-lookup").FromLocal("./geo.wasm").Build()
+
+	manifest, err := app.Build().Synthesize()
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+	forecast, _, _ := strings.Cut(strings.SplitN(manifest, "[component.forecast]", 2)[1], "[component.geo-lookup")
+	for _, want := range []string{
+		"allowed_outbound_hosts = ['http://mcp-gateway.spin.internal', 'http://geo-lookup.spin.internal']",
+		"geo_lookup_url = 'http://geo-lookup.spin.internal'",
+	} {
+		if !strings.Contains(forecast, want) {
+			t.Errorf("forecast missing %s:\n%s", want, forecast)
+		}
+	}
+
+	// Dependencies must be other components of the app
+	app = New().NewApp("weather")
+	app.AddComponent("forecast").FromLocal("./forecast.wasm").WithServiceDependency("geo-lookup").Build()
+	if _, err := app.Build().Synthesize(); err == nil || !strings.Contains(err.Error(), "depends on geo-lookup") {
+		t.Errorf("expected unknown dependency error, got %v", err)
+	}
+}
+
+func TestCDK_WithEnvFromComponentErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string
+		output    string
+		access    string
+		want      string
+	}{
+		{"unknown component", "missing", OutputURL, "public", "no component 'missing'"},
+		{"unknown output", "mcp-gateway", "port", "public", "unknown output 'port'"},
+		{"unknown variable", "mcp-gateway", "variables.nope", "public", "has no variable 'nope'"},
+		{"private route", "mcp-gateway", OutputRoute, "private", "private route"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New().NewApp("wired").SetAccess(tt.access)
+			app.AddComponent("tool").FromLocal("./tool.wasm").
+				WithEnvFromComponent("value", tt.component, tt.output).
+				Build()
+
+			_, err := app.Build().Synthesize()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
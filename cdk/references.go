@@ -0,0 +1,192 @@
+package cdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Outputs a component can be referenced by, with WithEnvFromComponent
+const (
+	// OutputID is the component's ID in the Spin manifest
+	OutputID = "id"
+	// OutputURL is the URL other components reach the component at,
+	// e.g. http://mcp-gateway.spin.internal
+	OutputURL = "url"
+	// OutputRoute is the component's public HTTP route, e.g. /...
+	OutputRoute = "route"
+	// OutputVariablePrefix prefixes the name of one of the component's
+	// synthesized variables, e.g. "variables.component_names"
+	OutputVariablePrefix = "variables."
+)
+
+// componentRef is a variable whose value is an output of another component
+type componentRef struct {
+	Component string
+	Output    string
+}
+
+// synthesizedManifest is the part of a Spin manifest references resolve
+// against
+type synthesizedManifest struct {
+	Component map[string]struct {
+		Variables map[string]string `toml:"variables"`
+	} `toml:"component"`
+	Trigger struct {
+		HTTP []struct {
+			Component string      `toml:"component"`
+			Route     interface{} `toml:"route"`
+		} `toml:"http"`
+	} `toml:"trigger"`
+}
+
+// WithEnvFromComponent sets a variable to an output of another component
+// of the synthesized app, such as the gateway's URL:
+// WithEnvFromComponent("gateway_url", "mcp-gateway", cdk.OutputURL).
+// The value is resolved when the app is synthesized, so it follows
+// changes to routes and names.
+func (cb *ComponentBuilder) WithEnvFromComponent(key, component, output string) *ComponentBuilder {
+	cb.component.refs = setRef(cb.component.refs, key, component, output)
+	delete(cb.component.Variables, key)
+	return cb
+}
+
+// WithEnvFromComponent sets a variable to an output of another component
+// of the synthesized app, resolved when the app is synthesized
+func (mb *MiddlewareBuilder) WithEnvFromComponent(key, component, output string) *MiddlewareBuilder {
+	mb.middleware.refs = setRef(mb.middleware.refs, key, component, output)
+	delete(mb.middleware.Variables, key)
+	return mb
+}
+
+// WithServiceDependency lets the component call another component of the
+// app. Synthesis allows the component's URL as an outbound host and sets
+// it in the variable <component>_url, with hyphens replaced by
+// underscores, e.g. geo_lookup_url for "geo-lookup".
+func (cb *ComponentBuilder) WithServiceDependency(component string) *ComponentBuilder {
+	cb.component.ServiceDependencies = append(cb.component.ServiceDependencies, component)
+	return cb
+}
+
+func setRef(refs map[string]componentRef, key, component, output string) map[string]componentRef {
+	if refs == nil {
+		refs = make(map[string]componentRef)
+	}
+	refs[key] = componentRef{Component: component, Output: output}
+	return refs
+}
+
+// hasRefs reports whether any variable of the app references a component
+func (app *CDKApp) hasRefs() bool {
+	for _, c := range app.Components {
+		if len(c.refs) > 0 {
+			return true
+		}
+	}
+	for _, mw := range app.Middleware {
+		if len(mw.refs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRefs returns a copy of the app with references to components
+// replaced by their values in the app synthesized without them
+func (cdk *CDK) resolveRefs() (*CDKApp, error) {
+	if !cdk.app.hasRefs() {
+		return cdk.app, nil
+	}
+
+	spinTOML, err := cdk.synthesizer.SynthesizeFromStruct(cdk.app)
+	if err != nil {
+		return nil, err
+	}
+	var manifest synthesizedManifest
+	if _, err := toml.Decode(spinTOML, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse synthesized manifest: %w", err)
+	}
+
+	resolved := *cdk.app
+	resolved.Components = make([]CDKComponent, len(cdk.app.Components))
+	for i, c := range cdk.app.Components {
+		if c.Variables, err = manifest.resolve(c.ID, c.Variables, c.refs); err != nil {
+			return nil, err
+		}
+		resolved.Components[i] = c
+	}
+	resolved.Middleware = make([]CDKMiddleware, len(cdk.app.Middleware))
+	for i, mw := range cdk.app.Middleware {
+		if mw.Variables, err = manifest.resolve(mw.ID, mw.Variables, mw.refs); err != nil {
+			return nil, err
+		}
+		resolved.Middleware[i] = mw
+	}
+	if len(resolved.Middleware) == 0 {
+		resolved.Middleware = nil
+	}
+	return &resolved, nil
+}
+
+// resolve returns the variables of a component with its references added
+func (m *synthesizedManifest) resolve(id string, variables map[string]string, refs map[string]componentRef) (map[string]string, error) {
+	if len(refs) == 0 {
+		return variables, nil
+	}
+
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	merged := make(map[string]string, len(variables)+len(refs))
+	for k, v := range variables {
+		merged[k] = v
+	}
+	for _, key := range keys {
+		value, err := m.output(refs[key])
+		if err != nil {
+			return nil, fmt.Errorf("component '%s' variable '%s': %w", id, key, err)
+		}
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// output returns the value of a component output
+func (m *synthesizedManifest) output(ref componentRef) (string, error) {
+	component, ok := m.Component[ref.Component]
+	if !ok {
+		return "", fmt.Errorf("no component '%s' in the synthesized app", ref.Component)
+	}
+
+	switch {
+	case ref.Output == OutputID:
+		return ref.Component, nil
+	case ref.Output == OutputURL:
+		return fmt.Sprintf("http://%s.spin.internal", ref.Component), nil
+	case ref.Output == OutputRoute:
+		for _, trigger := range m.Trigger.HTTP {
+			if trigger.Component != ref.Component {
+				continue
+			}
+			if route, ok := trigger.Route.(string); ok {
+				return route, nil
+			}
+			return "", fmt.Errorf("component '%s' has a private route; reference its url instead", ref.Component)
+		}
+		return "", fmt.Errorf("component '%s' has no route", ref.Component)
+	case strings.HasPrefix(ref.Output, OutputVariablePrefix):
+		name := strings.TrimPrefix(ref.Output, OutputVariablePrefix)
+		value, ok := component.Variables[name]
+		if !ok {
+			return "", fmt.Errorf("component '%s' has no variable '%s'", ref.Component, name)
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("unknown output '%s': must be id, url, route or variables.NAME", ref.Output)
+	}
+}
@@ -19,6 +19,6 @@ func main() {
 
 	// Execute the root command
 	if err := cli.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
@@ -4,6 +4,8 @@ use anyhow::Result;
 use serde::{Deserialize, Serialize};
 use spin_sdk::variables;
 
+use crate::mock::MockIdentity;
+
 /// Main configuration structure
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct Config {
@@ -18,6 +20,10 @@ pub struct Config {
 
     /// Policy-based authorization configuration
     pub authorization: Option<PolicyAuthorization>,
+
+    /// Identity to authenticate every request as, for local development
+    #[serde(skip)]
+    pub mock_identity: Option<MockIdentity>,
 }
 
 /// Provider type enumeration
@@ -115,11 +121,19 @@ impl Config {
         // Load policy authorization if configured
         let authorization = PolicyAuthorization::load().ok();
 
+        // Mock identity, declared only in the manifest `ftl up --mock-auth` writes
+        let mock_identity = variables::get("mcp_mock_identity")
+            .ok()
+            .filter(|s| !s.trim().is_empty())
+            .map(|json| MockIdentity::parse(&json))
+            .transpose()?;
+
         Ok(Self {
             gateway_url,
             trace_header,
             provider,
             authorization,
+            mock_identity,
         })
     }
 }
@@ -48,6 +48,22 @@ impl fmt::Display for AuthError {
     }
 }
 
+impl AuthError {
+    /// Reason label for the authentication failure metric, or `None` for
+    /// errors that are not the client's fault
+    pub const fn failure_reason(&self) -> Option<&'static str> {
+        match self {
+            Self::Unauthorized(_) => Some("unauthorized"),
+            Self::InvalidToken(_) => Some("invalid_token"),
+            Self::ExpiredToken => Some("expired_token"),
+            Self::InvalidIssuer => Some("invalid_issuer"),
+            Self::InvalidAudience => Some("invalid_audience"),
+            Self::InvalidSignature => Some("invalid_signature"),
+            Self::Configuration(_) | Self::Internal(_) => None,
+        }
+    }
+}
+
 impl std::error::Error for AuthError {}
 
 impl From<spin_sdk::key_value::Error> for AuthError {
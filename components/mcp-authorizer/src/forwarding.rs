@@ -115,6 +115,10 @@ pub async fn forward_to_gateway(
     ))
 }
 
+/// Prefix of the headers describing the caller. The gateway trusts them,
+/// so only the authorizer may set them.
+const AUTH_HEADER_PREFIX: &str = "x-auth-";
+
 /// Build headers for forwarding request
 fn build_forwarding_headers(
     req: &Request,
@@ -122,32 +126,54 @@ fn build_forwarding_headers(
     trace_id: Option<&String>,
     config: &Config,
 ) -> anyhow::Result<Headers> {
+    let client_headers = req
+        .headers()
+        .map(|(name, value)| (name.to_string(), value.as_bytes().to_vec()));
+
     let headers = Headers::new();
+    for (name, value) in forwarding_headers(client_headers, auth_context) {
+        headers.append(&name, &value)?;
+    }
 
-    // Copy request headers
-    for (name, value) in req.headers() {
-        headers.append(&name.to_string(), &value.as_bytes().to_vec())?;
+    // Add trace ID if present
+    if let Some(trace_id) = trace_id {
+        headers.append(&config.trace_header, &trace_id.as_bytes().to_vec())?;
     }
 
+    Ok(headers)
+}
+
+/// The client's headers followed by the authentication context. Any
+/// `x-auth-*` header sent by the client is dropped, so callers cannot pass
+/// off an identity of their own as verified.
+fn forwarding_headers(
+    client_headers: impl IntoIterator<Item = (String, Vec<u8>)>,
+    auth_context: &AuthContext,
+) -> Vec<(String, Vec<u8>)> {
+    let mut headers: Vec<(String, Vec<u8>)> = client_headers
+        .into_iter()
+        .filter(|(name, _)| !name.to_ascii_lowercase().starts_with(AUTH_HEADER_PREFIX))
+        .collect();
+
     // Add standard authentication context headers
-    headers.append(
-        &"x-auth-client-id".to_string(),
-        &auth_context.client_id.as_bytes().to_vec(),
-    )?;
-    headers.append(
-        &"x-auth-user-id".to_string(),
-        &auth_context.user_id.as_bytes().to_vec(),
-    )?;
-    headers.append(
-        &"x-auth-issuer".to_string(),
-        &auth_context.issuer.as_bytes().to_vec(),
-    )?;
+    headers.push((
+        "x-auth-client-id".to_string(),
+        auth_context.client_id.as_bytes().to_vec(),
+    ));
+    headers.push((
+        "x-auth-user-id".to_string(),
+        auth_context.user_id.as_bytes().to_vec(),
+    ));
+    headers.push((
+        "x-auth-issuer".to_string(),
+        auth_context.issuer.as_bytes().to_vec(),
+    ));
 
     if !auth_context.scopes.is_empty() {
-        headers.append(
-            &"x-auth-scopes".to_string(),
-            &auth_context.scopes.join(" ").as_bytes().to_vec(),
-        )?;
+        headers.push((
+            "x-auth-scopes".to_string(),
+            auth_context.scopes.join(" ").as_bytes().to_vec(),
+        ));
     }
 
     // Note: Claim forwarding has been removed in favor of policy-based authorization
@@ -155,19 +181,12 @@ fn build_forwarding_headers(
     // in the policy evaluation result or as part of the auth context
 
     // Forward the original authorization header
-    headers.append(
-        &"authorization".to_string(),
-        &format!("Bearer {}", auth_context.raw_token)
-            .as_bytes()
-            .to_vec(),
-    )?;
-
-    // Add trace ID if present
-    if let Some(trace_id) = trace_id {
-        headers.append(&config.trace_header, &trace_id.as_bytes().to_vec())?;
-    }
+    headers.push((
+        "authorization".to_string(),
+        format!("Bearer {}", auth_context.raw_token).as_bytes().to_vec(),
+    ));
 
-    Ok(headers)
+    headers
 }
 
 /// Build gateway response with CORS headers
@@ -206,3 +225,58 @@ fn build_gateway_response(
     // Build the response with body
     response_builder.body(body).build()
 }
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn auth_context(scopes: &[&str]) -> AuthContext {
+        AuthContext {
+            client_id: "app456".to_string(),
+            user_id: "user123".to_string(),
+            scopes: scopes.iter().map(ToString::to_string).collect(),
+            issuer: "https://test.authkit.app".to_string(),
+            raw_token: "token".to_string(),
+            additional_claims: std::collections::HashMap::new(),
+        }
+    }
+
+    fn values<'a>(headers: &'a [(String, Vec<u8>)], name: &str) -> Vec<&'a [u8]> {
+        headers
+            .iter()
+            .filter(|(header, _)| header.eq_ignore_ascii_case(name))
+            .map(|(_, value)| value.as_slice())
+            .collect()
+    }
+
+    #[test]
+    fn test_forwarding_headers_drop_spoofed_identity() {
+        let client_headers = vec![
+            ("content-type".to_string(), b"application/json".to_vec()),
+            ("X-Auth-User-Id".to_string(), b"admin".to_vec()),
+            ("x-auth-scopes".to_string(), b"admin".to_vec()),
+            ("X-Auth-Claims".to_string(), b"eyJyb2xlIjoiYWRtaW4ifQ".to_vec()),
+        ];
+
+        // A token without scopes must not leave the client's scopes in place
+        let headers = forwarding_headers(client_headers, &auth_context(&[]));
+
+        assert_eq!(values(&headers, "x-auth-user-id"), vec![b"user123".as_slice()]);
+        assert!(values(&headers, "x-auth-scopes").is_empty());
+        assert!(values(&headers, "x-auth-claims").is_empty());
+        assert_eq!(values(&headers, "content-type"), vec![b"application/json".as_slice()]);
+    }
+
+    #[test]
+    fn test_forwarding_headers_add_verified_identity() {
+        let headers = forwarding_headers(Vec::new(), &auth_context(&["read", "write"]));
+
+        assert_eq!(values(&headers, "x-auth-client-id"), vec![b"app456".as_slice()]);
+        assert_eq!(
+            values(&headers, "x-auth-issuer"),
+            vec![b"https://test.authkit.app".as_slice()]
+        );
+        assert_eq!(values(&headers, "x-auth-scopes"), vec![b"read write".as_slice()]);
+        assert_eq!(values(&headers, "authorization"), vec![b"Bearer token".as_slice()]);
+    }
+}
@@ -12,6 +12,7 @@ mod discovery;
 mod error;
 mod forwarding;
 mod jwks;
+mod mock;
 mod policy;
 mod token;
 
@@ -111,6 +112,7 @@ async fn handle_request(req: Request) -> anyhow::Result<impl IntoResponse> {
             } else {
                 log::info!("Auth failed: {auth_error}");
             }
+            record_auth_failure(&auth_error);
             // Return authentication error
             Ok(create_error_response(&auth_error, &req, &config, trace_id))
         }
@@ -123,6 +125,11 @@ async fn authenticate_with_policy(
     config: &Config,
     body: Option<&[u8]>,
 ) -> Result<auth::Context> {
+    // Local development: authenticate as the mock identity without a token
+    if let Some(identity) = &config.mock_identity {
+        return authenticate_mock(identity, req, config, body);
+    }
+
     // Extract bearer token
     let token = auth::extract_bearer_token(req)?;
 
@@ -163,6 +170,31 @@ async fn authenticate_with_policy(
     })
 }
 
+/// Authenticate a request as the configured mock identity. Policies still
+/// apply, so their decisions can be tried out locally.
+fn authenticate_mock(
+    identity: &mock::MockIdentity,
+    req: &Request,
+    config: &Config,
+    body: Option<&[u8]>,
+) -> Result<auth::Context> {
+    log::warn!("Mock authentication is enabled; not verifying tokens");
+    let token_info = identity.token_info();
+
+    if let Some(policy_config) = &config.authorization {
+        apply_policy_authorization(&token_info, req, body, policy_config)?;
+    }
+
+    Ok(auth::Context {
+        client_id: token_info.client_id,
+        user_id: token_info.sub,
+        scopes: token_info.scopes,
+        issuer: token_info.iss,
+        raw_token: identity.unsigned_token(),
+        additional_claims: token_info.claims,
+    })
+}
+
 /// Apply policy-based authorization using Regorous
 fn apply_policy_authorization(
     token_info: &token::TokenInfo,
@@ -224,6 +256,28 @@ async fn forward_request(
     forwarding::forward_to_gateway(req, config, auth_context, trace_id).await
 }
 
+/// Count a rejected request in the key-value store, where the gateway's
+/// `/metrics` endpoint reports it as `ftl_auth_failures_total`
+fn record_auth_failure(error: &AuthError) {
+    let Some(reason) = error.failure_reason() else {
+        return;
+    };
+    let Ok(store) = Store::open_default() else {
+        return;
+    };
+    let key = format!("metrics:auth_failures:{reason}");
+    let count = store
+        .get(&key)
+        .ok()
+        .flatten()
+        .and_then(|data| String::from_utf8(data).ok())
+        .and_then(|value| value.trim().parse::<u64>().ok())
+        .unwrap_or(0);
+    if let Err(e) = store.set(&key, (count + 1).to_string().as_bytes()) {
+        log::warn!("Failed to record auth failure metric: {e}");
+    }
+}
+
 /// Create authentication error response
 fn create_error_response(
     error: &AuthError,
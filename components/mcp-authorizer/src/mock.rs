@@ -0,0 +1,151 @@
+//! Mock authentication for local development
+//!
+//! When `mcp_mock_identity` is set, every request is authenticated as the
+//! identity it describes instead of verifying a bearer token, so apps with
+//! org or custom access can be exercised locally without an identity
+//! provider. The variable is not declared in the shipped `spin.toml`; only the
+//! local manifest written by `ftl up --mock-auth` sets it on this component.
+//!
+//! The variable holds the identity's claims as a JSON object:
+//!
+//! ```json
+//! {"sub": "dev-user", "org_id": "org_123", "scope": "read write"}
+//! ```
+//!
+//! Authorization policies still apply to the mock identity, and the gateway
+//! receives an unsigned token carrying its claims.
+
+use std::collections::HashMap;
+
+use base64::Engine;
+use base64::engine::general_purpose::URL_SAFE_NO_PAD;
+use serde_json::{Map, Value};
+
+use crate::token::TokenInfo;
+
+/// Issuer of mock identities
+pub const MOCK_ISSUER: &str = "ftl-mock-auth";
+
+/// Subject used when the mock identity has none
+pub const DEFAULT_SUBJECT: &str = "dev-user";
+
+/// Identity every request is authenticated as in mock mode
+#[derive(Debug, Clone)]
+pub struct MockIdentity {
+    claims: Map<String, Value>,
+}
+
+impl MockIdentity {
+    /// Parse the claims of the `mcp_mock_identity` variable
+    pub fn parse(json: &str) -> anyhow::Result<Self> {
+        let value: Value = serde_json::from_str(json)
+            .map_err(|e| anyhow::anyhow!("mcp_mock_identity is not valid JSON: {e}"))?;
+        let Value::Object(mut claims) = value else {
+            return Err(anyhow::anyhow!(
+                "mcp_mock_identity must be a JSON object of claims"
+            ));
+        };
+        claims
+            .entry("sub")
+            .or_insert_with(|| Value::String(DEFAULT_SUBJECT.to_string()));
+        claims
+            .entry("iss")
+            .or_insert_with(|| Value::String(MOCK_ISSUER.to_string()));
+        Ok(Self { claims })
+    }
+
+    /// The token info a verified token with these claims would produce
+    pub fn token_info(&self) -> TokenInfo {
+        let string_claim = |name: &str| {
+            self.claims
+                .get(name)
+                .and_then(Value::as_str)
+                .map(str::to_string)
+        };
+        let sub = string_claim("sub").unwrap_or_else(|| DEFAULT_SUBJECT.to_string());
+
+        let mut scopes: Vec<String> = string_claim("scope")
+            .map(|scope| scope.split_whitespace().map(str::to_string).collect())
+            .unwrap_or_default();
+        if let Some(Value::Array(scp)) = self.claims.get("scp") {
+            scopes.extend(scp.iter().filter_map(Value::as_str).map(str::to_string));
+        }
+
+        TokenInfo {
+            client_id: string_claim("client_id").unwrap_or_else(|| sub.clone()),
+            iss: string_claim("iss").unwrap_or_else(|| MOCK_ISSUER.to_string()),
+            sub,
+            scopes,
+            claims: self
+                .claims
+                .iter()
+                .map(|(k, v)| (k.clone(), v.clone()))
+                .collect::<HashMap<_, _>>(),
+        }
+    }
+
+    /// An unsigned JWT carrying the identity's claims, forwarded to the
+    /// gateway in place of a verified token
+    pub fn unsigned_token(&self) -> String {
+        let header = URL_SAFE_NO_PAD.encode(br#"{"alg":"none","typ":"JWT"}"#);
+        let payload = URL_SAFE_NO_PAD
+            .encode(serde_json::to_vec(&self.claims).unwrap_or_else(|_| b"{}".to_vec()));
+        format!("{header}.{payload}.")
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    #[allow(clippy::expect_used)]
+    fn test_defaults_subject_and_issuer() {
+        let info = MockIdentity::parse("{}")
+            .expect("should parse empty identity")
+            .token_info();
+        assert_eq!(info.sub, DEFAULT_SUBJECT);
+        assert_eq!(info.client_id, DEFAULT_SUBJECT);
+        assert_eq!(info.iss, MOCK_ISSUER);
+        assert!(info.scopes.is_empty());
+    }
+
+    #[test]
+    #[allow(clippy::expect_used)]
+    fn test_reads_identity_claims() {
+        let info = MockIdentity::parse(
+            r#"{"sub": "alice", "client_id": "cli", "org_id": "org_1", "scope": "read write", "scp": ["admin"]}"#,
+        )
+        .expect("should parse identity")
+        .token_info();
+        assert_eq!(info.sub, "alice");
+        assert_eq!(info.client_id, "cli");
+        assert_eq!(info.scopes, vec!["read", "write", "admin"]);
+        assert_eq!(info.claims.get("org_id"), Some(&Value::from("org_1")));
+    }
+
+    #[test]
+    fn test_rejects_invalid_identities() {
+        assert!(MockIdentity::parse("not json").is_err());
+        assert!(MockIdentity::parse(r#"["alice"]"#).is_err());
+    }
+
+    #[test]
+    #[allow(clippy::expect_used)]
+    fn test_unsigned_token_carries_claims() {
+        let token = MockIdentity::parse(r#"{"sub": "alice"}"#)
+            .expect("should parse identity")
+            .unsigned_token();
+        let payload = token
+            .split('.')
+            .nth(1)
+            .expect("token should have a payload");
+        let bytes = URL_SAFE_NO_PAD
+            .decode(payload)
+            .expect("payload should be base64url");
+        let claims: Value = serde_json::from_slice(&bytes).expect("payload should be JSON");
+        assert_eq!(claims["sub"], "alice");
+        assert_eq!(claims["iss"], MOCK_ISSUER);
+        assert!(token.ends_with('.'));
+    }
+}
@@ -346,6 +346,13 @@ fn test_expired_token() {
     let response = spin_test_sdk::perform_request(request);
 
     assert_eq!(response.status(), 401);
+
+    // Rejections are counted for the gateway's /metrics endpoint
+    let kv = spin_test_sdk::bindings::fermyon::spin_test_virt::key_value::Store::open("default");
+    assert_eq!(
+        kv.get("metrics:auth_failures:expired_token"),
+        Some(b"1".to_vec())
+    );
 }
 
 // Test: Multiple audiences in token
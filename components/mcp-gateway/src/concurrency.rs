@@ -0,0 +1,258 @@
+//! Per-tool concurrency limits
+//!
+//! Tools declare how many calls they handle at once with `maxConcurrency`
+//! in their `_meta`, to protect rate-limited upstream APIs or heavy
+//! computations:
+//!
+//! ```json
+//! { "name": "render", "inputSchema": {...}, "_meta": { "maxConcurrency": 2 } }
+//! ```
+//!
+//! Each gateway request runs in its own instance, so the calls in flight
+//! are leases in the key-value store, one key per lease holding when it
+//! expires. A call that finds the tool busy fails right away with a
+//! retriable error. Leases expire, so a call whose instance dies does not
+//! hold its slot forever. The store has no atomic updates, so calls racing
+//! for the last slot may briefly exceed a limit.
+
+use std::collections::BTreeMap;
+use std::time::{Duration, SystemTime, UNIX_EPOCH};
+
+use spin_sdk::key_value::Store;
+
+use crate::mcp_types::ToolMetadata;
+
+/// Key in a tool's `_meta` holding how many calls it handles at once
+pub const MAX_CONCURRENCY_META_KEY: &str = "maxConcurrency";
+
+/// Error code for calls rejected because the tool is busy
+pub const CONCURRENCY_LIMITED: i32 = -32003;
+
+/// Error data type for calls rejected because the tool is busy
+pub const CONCURRENCY_LIMITED_TYPE: &str = "concurrency_limited";
+
+/// How long a slot is held at most, in case its call never releases it.
+/// Another call may take the slot of a call that runs longer.
+const LEASE: Duration = Duration::from_secs(60);
+
+/// The concurrency limit a tool declares in its `_meta`
+pub fn max_concurrency(tool: &ToolMetadata) -> Option<u64> {
+    tool.meta
+        .as_ref()
+        .and_then(|meta| meta.get(MAX_CONCURRENCY_META_KEY))
+        .and_then(serde_json::Value::as_u64)
+        .filter(|max| *max > 0)
+}
+
+fn now_millis() -> u64 {
+    SystemTime::now()
+        .duration_since(UNIX_EPOCH)
+        .map_or(0, |d| u64::try_from(d.as_millis()).unwrap_or(u64::MAX))
+}
+
+/// Count the leases that are still held, given when they expire in
+/// milliseconds since the epoch, and return the keys of the expired ones
+fn held_leases(leases: Vec<(String, u64)>, now: u64) -> (u64, Vec<String>) {
+    let (held, expired): (Vec<_>, Vec<_>) =
+        leases.into_iter().partition(|(_, expires)| *expires > now);
+    (
+        u64::try_from(held.len()).unwrap_or(u64::MAX),
+        expired.into_iter().map(|(key, _)| key).collect(),
+    )
+}
+
+/// The concurrency limits of the components' tools and their slots, in the
+/// key-value store. Without a store no limits apply.
+pub struct Limits {
+    store: Option<Store>,
+}
+
+impl Limits {
+    pub fn open() -> Self {
+        let store = Store::open_default()
+            .map_err(|e| {
+                eprintln!("Concurrency limits disabled: failed to open key-value store: {e}");
+            })
+            .ok();
+        Self { store }
+    }
+
+    fn limits_key(component: &str) -> String {
+        format!("gateway:concurrency:{component}")
+    }
+
+    /// Prefix of the keys of a tool's leases, which end with a random
+    /// token
+    fn lease_prefix(component: &str, tool: &str) -> String {
+        format!("gateway:concurrency:{component}:{tool}:")
+    }
+
+    fn load<T: serde::de::DeserializeOwned + Default>(&self, key: &str) -> T {
+        self.store
+            .as_ref()
+            .and_then(|store| store.get(key).ok().flatten())
+            .and_then(|data| serde_json::from_slice(&data).ok())
+            .unwrap_or_default()
+    }
+
+    fn save(&self, key: &str, value: &impl serde::Serialize) {
+        if let Some(store) = &self.store
+            && let Ok(data) = serde_json::to_vec(value)
+            && let Err(e) = store.set(key, &data)
+        {
+            eprintln!("Failed to save concurrency state '{key}': {e}");
+        }
+    }
+
+    /// Remember the limits of a component's tools, so calls know them
+    /// without fetching the tools' metadata
+    pub fn remember(&self, component: &str, tools: &[ToolMetadata]) {
+        if self.store.is_none() {
+            return;
+        }
+        let limits: BTreeMap<&str, u64> = tools
+            .iter()
+            .filter_map(|tool| max_concurrency(tool).map(|max| (tool.name.as_str(), max)))
+            .collect();
+        let known: BTreeMap<String, u64> = self.load(&Self::limits_key(component));
+        if limits.len() != known.len()
+            || limits
+                .iter()
+                .any(|(tool, max)| known.get(*tool) != Some(max))
+        {
+            self.save(&Self::limits_key(component), &limits);
+        }
+    }
+
+    /// The last known limit of a tool
+    pub fn limit(&self, component: &str, tool: &str) -> Option<u64> {
+        let limits: BTreeMap<String, u64> = self.load(&Self::limits_key(component));
+        limits.get(tool).copied()
+    }
+
+    /// Take one of the tool's `max` slots. Returns `None` when they are all
+    /// held.
+    pub fn acquire(&self, component: &str, tool: &str, max: u64) -> Option<Slot<'_>> {
+        let Some(store) = &self.store else {
+            return Some(Slot::untracked());
+        };
+        let prefix = Self::lease_prefix(component, tool);
+        let leases = store
+            .get_keys()
+            .unwrap_or_default()
+            .into_iter()
+            // Tokens have no colon, unlike the leases of tools whose name
+            // extends this one after a colon
+            .filter(|key| key.strip_prefix(&prefix).is_some_and(|token| !token.contains(':')))
+            .map(|key| {
+                let expires = store
+                    .get(&key)
+                    .ok()
+                    .flatten()
+                    .and_then(|data| String::from_utf8(data).ok())
+                    .and_then(|value| value.trim().parse::<u64>().ok())
+                    .unwrap_or(0);
+                (key, expires)
+            })
+            .collect();
+
+        let now = now_millis();
+        let (held, stale) = held_leases(leases, now);
+        for key in stale {
+            if let Err(e) = store.delete(&key) {
+                eprintln!("Failed to delete expired concurrency lease '{key}': {e}");
+            }
+        }
+        if held >= max {
+            return None;
+        }
+
+        let key = format!("{prefix}{}", crate::trace::random_hex(8));
+        let expires = now.saturating_add(u64::try_from(LEASE.as_millis()).unwrap_or(u64::MAX));
+        if let Err(e) = store.set(&key, expires.to_string().as_bytes()) {
+            eprintln!("Failed to save concurrency lease '{key}': {e}");
+            return Some(Slot::untracked());
+        }
+        Some(Slot {
+            store: Some(store),
+            key,
+        })
+    }
+}
+
+/// A slot taken for a call, released when dropped
+pub struct Slot<'a> {
+    store: Option<&'a Store>,
+    /// Key of the slot's lease
+    key: String,
+}
+
+impl Slot<'_> {
+    const fn untracked() -> Self {
+        Self {
+            store: None,
+            key: String::new(),
+        }
+    }
+}
+
+impl Drop for Slot<'_> {
+    fn drop(&mut self) {
+        if let Some(store) = self.store
+            && let Err(e) = store.delete(&self.key)
+        {
+            eprintln!("Failed to release concurrency slot '{}': {e}", self.key);
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn tool(meta: serde_json::Value) -> ToolMetadata {
+        ToolMetadata {
+            name: "render".to_string(),
+            title: None,
+            description: None,
+            input_schema: serde_json::json!({"type": "object"}),
+            output_schema: None,
+            annotations: None,
+            meta: Some(meta),
+        }
+    }
+
+    #[test]
+    fn reads_limits_from_meta() {
+        assert_eq!(
+            max_concurrency(&tool(serde_json::json!({"maxConcurrency": 2}))),
+            Some(2)
+        );
+        assert_eq!(
+            max_concurrency(&tool(serde_json::json!({"maxConcurrency": 0}))),
+            None
+        );
+        assert_eq!(
+            max_concurrency(&tool(serde_json::json!({"maxConcurrency": "2"}))),
+            None
+        );
+        assert_eq!(
+            max_concurrency(&tool(serde_json::json!({"tags": ["x"]}))),
+            None
+        );
+    }
+
+    #[test]
+    fn counts_held_leases() {
+        let leases = vec![
+            ("a".to_string(), 2_000),
+            ("b".to_string(), 1_000),
+            ("c".to_string(), 1_500),
+        ];
+        let (held, expired) = held_leases(leases, 1_000);
+        assert_eq!(held, 2);
+        assert_eq!(expired, vec!["b".to_string()]);
+
+        assert_eq!(held_leases(Vec::new(), 1_000), (0, Vec::new()));
+    }
+}
@@ -0,0 +1,223 @@
+//! CORS for browser clients
+//!
+//! Browser-based MCP clients and web playgrounds call deployed apps from
+//! other origins. The gateway answers their preflight requests and adds
+//! `Access-Control-Allow-Origin` to every response for the origins allowed
+//! by `cors_allowed_origins`: `*` (the default) allows any origin, otherwise
+//! it is a comma-separated list of origins such as `https://app.example.com`
+//! or patterns such as `https://*.example.com`. Responses to other origins
+//! carry no CORS headers, so browsers refuse to read them.
+
+use spin_sdk::http::Response;
+use spin_sdk::variables;
+
+/// Request headers browsers may send, those MCP clients use
+pub const DEFAULT_ALLOWED_HEADERS: &[&str] = &[
+    "Content-Type",
+    "Accept",
+    "Authorization",
+    "Mcp-Session-Id",
+    "Mcp-Protocol-Version",
+    "Last-Event-ID",
+    "X-MCP-Toolsets",
+    "X-MCP-Readonly",
+    "X-Request-Id",
+    "Idempotency-Key",
+    "traceparent",
+    "tracestate",
+];
+
+/// How long browsers may cache a preflight response by default, in seconds
+pub const DEFAULT_MAX_AGE_SECONDS: u64 = 600;
+
+/// The gateway's CORS policy
+#[derive(Debug, Clone)]
+pub struct Cors {
+    /// Allowed origins and origin patterns; `*` allows any
+    origins: Vec<String>,
+    /// Request headers browsers may send
+    headers: Vec<String>,
+    /// Seconds browsers may cache a preflight response
+    max_age: u64,
+}
+
+impl Cors {
+    /// A policy allowing `origins` (comma-separated, empty for any) to send
+    /// `extra_headers` on top of the default ones
+    pub fn new(origins: &str, extra_headers: &str, max_age: u64) -> Self {
+        let mut allowed: Vec<String> = split_list(origins);
+        if allowed.is_empty() {
+            allowed.push("*".to_string());
+        }
+        let mut headers: Vec<String> = DEFAULT_ALLOWED_HEADERS
+            .iter()
+            .map(|h| (*h).to_string())
+            .collect();
+        for header in split_list(extra_headers) {
+            if !headers.iter().any(|h| h.eq_ignore_ascii_case(&header)) {
+                headers.push(header);
+            }
+        }
+        Self {
+            origins: allowed,
+            headers,
+            max_age,
+        }
+    }
+
+    /// The policy configured with the `cors_*` Spin variables
+    pub fn from_variables() -> Self {
+        let max_age = variables::get("cors_max_age_seconds")
+            .ok()
+            .and_then(|value| value.trim().parse().ok())
+            .unwrap_or(DEFAULT_MAX_AGE_SECONDS);
+        Self::new(
+            &variables::get("cors_allowed_origins").unwrap_or_default(),
+            &variables::get("cors_allowed_headers").unwrap_or_default(),
+            max_age,
+        )
+    }
+
+    fn any_origin(&self) -> bool {
+        self.origins.iter().any(|o| o == "*")
+    }
+
+    /// The `Access-Control-Allow-Origin` value for a request from `origin`,
+    /// or `None` when the origin is not allowed
+    pub fn allow_origin(&self, origin: Option<&str>) -> Option<String> {
+        if self.any_origin() {
+            return Some("*".to_string());
+        }
+        let origin = origin?;
+        self.origins
+            .iter()
+            .any(|pattern| origin_matches(pattern, origin))
+            .then(|| origin.to_string())
+    }
+
+    /// The CORS headers of a response to a request from `origin`
+    pub fn response_headers(&self, origin: Option<&str>) -> Vec<(String, String)> {
+        let mut headers = Vec::new();
+        if let Some(allowed) = self.allow_origin(origin) {
+            headers.push(("access-control-allow-origin".to_string(), allowed));
+        }
+        if !self.any_origin() {
+            // The response depends on the origin, so caches must not share it
+            headers.push(("vary".to_string(), "Origin".to_string()));
+        }
+        headers
+    }
+
+    /// The response to a preflight request for an endpoint accepting
+    /// `methods`. The allowed origin is added when the response is sent.
+    pub fn preflight(&self, methods: &str) -> Response {
+        Response::builder()
+            .status(200)
+            .header("Access-Control-Allow-Methods", methods)
+            .header("Access-Control-Allow-Headers", self.headers.join(", "))
+            .header("Access-Control-Max-Age", self.max_age.to_string())
+            .build()
+    }
+}
+
+/// The `Origin` header among a request's headers
+pub fn origin(headers: &[(String, Vec<u8>)]) -> Option<String> {
+    headers
+        .iter()
+        .find(|(name, _)| name.eq_ignore_ascii_case("origin"))
+        .and_then(|(_, value)| std::str::from_utf8(value).ok())
+        .map(|value| value.trim().to_string())
+        .filter(|value| !value.is_empty() && value != "null")
+}
+
+/// Whether an origin matches an allowed origin or a pattern with a `*`
+/// standing for one or more subdomain labels
+fn origin_matches(pattern: &str, origin: &str) -> bool {
+    let pattern = pattern.trim_end_matches('/');
+    match pattern.split_once('*') {
+        None => pattern.eq_ignore_ascii_case(origin),
+        Some((prefix, suffix)) => {
+            let origin = origin.to_ascii_lowercase();
+            let (prefix, suffix) = (prefix.to_ascii_lowercase(), suffix.to_ascii_lowercase());
+            origin.len() > prefix.len() + suffix.len()
+                && origin.starts_with(&prefix)
+                && origin.ends_with(&suffix)
+                && origin
+                    .get(prefix.len()..origin.len() - suffix.len())
+                    .is_some_and(|labels| !labels.contains(['/', ':']))
+        }
+    }
+}
+
+fn split_list(list: &str) -> Vec<String> {
+    list.split(',')
+        .map(str::trim)
+        .filter(|item| !item.is_empty())
+        .map(str::to_string)
+        .collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn allows_any_origin_by_default() {
+        let cors = Cors::new("", "", DEFAULT_MAX_AGE_SECONDS);
+        assert_eq!(cors.allow_origin(None).as_deref(), Some("*"));
+        assert_eq!(
+            cors.allow_origin(Some("https://app.example.com"))
+                .as_deref(),
+            Some("*")
+        );
+        assert!(
+            cors.response_headers(None)
+                .iter()
+                .all(|(name, _)| name != "vary")
+        );
+    }
+
+    #[test]
+    fn echoes_allowed_origins() {
+        let cors = Cors::new(
+            "https://playground.example.com, https://*.acme.dev",
+            "",
+            DEFAULT_MAX_AGE_SECONDS,
+        );
+        assert_eq!(
+            cors.allow_origin(Some("https://playground.example.com"))
+                .as_deref(),
+            Some("https://playground.example.com")
+        );
+        assert_eq!(
+            cors.allow_origin(Some("https://docs.acme.dev")).as_deref(),
+            Some("https://docs.acme.dev")
+        );
+        assert_eq!(cors.allow_origin(Some("https://acme.dev")), None);
+        assert_eq!(cors.allow_origin(Some("https://evil.com/.acme.dev")), None);
+        assert_eq!(cors.allow_origin(Some("http://docs.acme.dev")), None);
+        assert_eq!(cors.allow_origin(None), None);
+        assert!(
+            cors.response_headers(Some("https://evil.com"))
+                .iter()
+                .all(|(name, _)| name != "access-control-allow-origin")
+        );
+    }
+
+    #[test]
+    fn adds_extra_headers_once() {
+        let cors = Cors::new("*", "X-Api-Key, content-type", 60);
+        assert_eq!(cors.headers.len(), DEFAULT_ALLOWED_HEADERS.len() + 1);
+        assert!(cors.headers.iter().any(|h| h == "X-Api-Key"));
+    }
+
+    #[test]
+    fn reads_the_origin_header() {
+        let headers = vec![
+            ("content-type".to_string(), b"application/json".to_vec()),
+            ("Origin".to_string(), b"https://app.example.com".to_vec()),
+        ];
+        assert_eq!(origin(&headers).as_deref(), Some("https://app.example.com"));
+        assert_eq!(origin(&[("origin".to_string(), b"null".to_vec())]), None);
+    }
+}
@@ -0,0 +1,231 @@
+//! Filtering and pagination for `tools/list`
+//!
+//! Clients can narrow the listing with a `filter` in the request params and
+//! page through it with the standard MCP `cursor`:
+//!
+//! ```json
+//! {
+//!   "cursor": "50",
+//!   "limit": 25,
+//!   "filter": { "component": "text", "prefix": "text__", "tags": ["search"] }
+//! }
+//! ```
+
+use serde::Deserialize;
+
+use crate::mcp_types::ToolMetadata;
+
+/// Key in a tool's `_meta` holding the tags components attach for discovery
+pub const TAGS_META_KEY: &str = "tags";
+
+/// Parameters accepted by `tools/list`
+#[derive(Debug, Clone, Default, Deserialize)]
+pub struct ListToolsParams {
+    /// Opaque cursor returned as `nextCursor` by the previous page
+    #[serde(default)]
+    pub cursor: Option<String>,
+
+    /// Maximum number of tools to return, capped by the gateway's page size
+    #[serde(default)]
+    pub limit: Option<usize>,
+
+    #[serde(default)]
+    pub filter: ToolFilter,
+}
+
+impl ListToolsParams {
+    /// Parse the params of a `tools/list` request. Missing params list
+    /// everything.
+    pub fn from_value(params: Option<serde_json::Value>) -> Result<Self, String> {
+        match params {
+            None | Some(serde_json::Value::Null) => Ok(Self::default()),
+            Some(value) => {
+                serde_json::from_value(value).map_err(|e| format!("Invalid params: {e}"))
+            }
+        }
+    }
+}
+
+/// Criteria a tool must match to be listed. Empty criteria match everything.
+#[derive(Debug, Clone, Default, Deserialize)]
+pub struct ToolFilter {
+    /// Only list tools from this component
+    #[serde(default)]
+    pub component: Option<String>,
+
+    /// Only list tools whose listed name starts with this prefix
+    #[serde(default)]
+    pub prefix: Option<String>,
+
+    /// Only list tools carrying all of these tags
+    #[serde(default)]
+    pub tags: Vec<String>,
+}
+
+impl ToolFilter {
+    /// Whether tools from the component can match; used to skip fetching
+    /// metadata from components that are filtered out
+    pub fn matches_component(&self, component: &str) -> bool {
+        self.component.as_deref().is_none_or(|c| c == component)
+    }
+
+    /// Whether the tool, listed under its (possibly prefixed) name, matches
+    pub fn matches_tool(&self, tool: &ToolMetadata) -> bool {
+        if let Some(prefix) = &self.prefix
+            && !tool.name.starts_with(prefix.as_str())
+        {
+            return false;
+        }
+
+        if self.tags.is_empty() {
+            return true;
+        }
+        let tags = tool_tags(tool);
+        self.tags
+            .iter()
+            .all(|wanted| tags.contains(&wanted.as_str()))
+    }
+}
+
+/// Tags a component attached to a tool in its `_meta`
+pub fn tool_tags(tool: &ToolMetadata) -> Vec<&str> {
+    tool.meta
+        .as_ref()
+        .and_then(|meta| meta.get(TAGS_META_KEY))
+        .and_then(serde_json::Value::as_array)
+        .map(|tags| tags.iter().filter_map(serde_json::Value::as_str).collect())
+        .unwrap_or_default()
+}
+
+/// Return one page of tools and the cursor of the next page.
+///
+/// Paging only applies when the gateway has a page size, or the client sent
+/// a cursor or limit; otherwise every tool is returned as before. Tools are
+/// sorted by name while paging so pages stay stable between requests.
+pub fn paginate(
+    mut tools: Vec<ToolMetadata>,
+    params: &ListToolsParams,
+    page_size: usize,
+) -> Result<(Vec<ToolMetadata>, Option<String>), String> {
+    let limit = match (params.limit, page_size) {
+        (Some(0), _) => return Err("Invalid params: limit must be positive".to_string()),
+        (Some(limit), 0) => limit,
+        (Some(limit), max) => limit.min(max),
+        (None, size) => size,
+    };
+    if limit == 0 && params.cursor.is_none() {
+        return Ok((tools, None));
+    }
+
+    let offset = match params.cursor.as_deref() {
+        Some(cursor) => cursor
+            .parse::<usize>()
+            .map_err(|_| format!("Invalid params: invalid cursor '{cursor}'"))?,
+        None => 0,
+    };
+    if offset > tools.len() {
+        return Err(format!("Invalid params: invalid cursor '{offset}'"));
+    }
+
+    tools.sort_by(|a, b| a.name.cmp(&b.name));
+    let mut page: Vec<ToolMetadata> = tools.into_iter().skip(offset).collect();
+    if limit == 0 || page.len() <= limit {
+        return Ok((page, None));
+    }
+    page.truncate(limit);
+    Ok((page, Some((offset + limit).to_string())))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn tool(name: &str, tags: &[&str]) -> ToolMetadata {
+        ToolMetadata {
+            name: name.to_string(),
+            title: None,
+            description: None,
+            input_schema: serde_json::json!({"type": "object"}),
+            output_schema: None,
+            annotations: None,
+            meta: if tags.is_empty() {
+                None
+            } else {
+                Some(serde_json::json!({ TAGS_META_KEY: tags }))
+            },
+        }
+    }
+
+    fn names(tools: &[ToolMetadata]) -> Vec<&str> {
+        tools.iter().map(|t| t.name.as_str()).collect()
+    }
+
+    #[test]
+    fn filter_matches_prefix_and_tags() {
+        let filter = ToolFilter {
+            component: Some("text".to_string()),
+            prefix: Some("text__".to_string()),
+            tags: vec!["search".to_string()],
+        };
+
+        assert!(filter.matches_component("text"));
+        assert!(!filter.matches_component("math"));
+        assert!(filter.matches_tool(&tool("text__find", &["search", "read"])));
+        assert!(!filter.matches_tool(&tool("text__find", &["read"])));
+        assert!(!filter.matches_tool(&tool("math__find", &["search"])));
+        assert!(ToolFilter::default().matches_tool(&tool("anything", &[])));
+    }
+
+    #[test]
+    fn params_default_when_missing() {
+        let params = ListToolsParams::from_value(None);
+        assert!(params.is_ok_and(|p| p.cursor.is_none() && p.filter.tags.is_empty()));
+
+        let params = ListToolsParams::from_value(Some(serde_json::json!({"limit": "many"})));
+        assert!(params.is_err());
+    }
+
+    #[test]
+    fn paginate_without_page_size_returns_everything() {
+        let tools = vec![tool("b", &[]), tool("a", &[])];
+        let result = paginate(tools, &ListToolsParams::default(), 0);
+        assert!(result.is_ok_and(|(page, next)| names(&page) == ["b", "a"] && next.is_none()));
+    }
+
+    #[test]
+    fn paginate_walks_pages_in_name_order() {
+        let tools = || vec![tool("c", &[]), tool("a", &[]), tool("b", &[])];
+
+        let first = paginate(tools(), &ListToolsParams::default(), 2);
+        assert!(first
+            .as_ref()
+            .is_ok_and(|(page, next)| names(page) == ["a", "b"] && next.as_deref() == Some("2")));
+
+        let params = ListToolsParams {
+            cursor: first.ok().and_then(|(_, next)| next),
+            ..ListToolsParams::default()
+        };
+        let second = paginate(tools(), &params, 2);
+        assert!(second.is_ok_and(|(page, next)| names(&page) == ["c"] && next.is_none()));
+    }
+
+    #[test]
+    fn paginate_caps_limit_and_rejects_bad_cursors() {
+        let tools = || vec![tool("a", &[]), tool("b", &[]), tool("c", &[])];
+
+        let params = ListToolsParams {
+            limit: Some(10),
+            ..ListToolsParams::default()
+        };
+        let result = paginate(tools(), &params, 1);
+        assert!(result.is_ok_and(|(page, next)| page.len() == 1 && next.as_deref() == Some("1")));
+
+        for cursor in ["abc", "4"] {
+            let params = ListToolsParams {
+                cursor: Some(cursor.to_string()),
+                ..ListToolsParams::default()
+            };
+            assert!(paginate(tools(), &params, 1).is_err());
+        }
+    }
+}
@@ -1,24 +1,148 @@
+use std::cell::RefCell;
+use std::collections::BTreeMap;
+use std::time::{Duration, Instant, SystemTime, UNIX_EPOCH};
+
+use base64::Engine;
+use base64::engine::general_purpose::URL_SAFE_NO_PAD;
+use futures::{SinkExt, StreamExt};
 use serde::{Deserialize, Serialize};
-use spin_sdk::http::{Method, Request, Response};
+use spin_sdk::http::{
+    Fields, IncomingRequest, Method, OutgoingResponse, Request, RequestBuilder, Response,
+    ResponseOutparam,
+};
+use spin_sdk::key_value::Store;
 use spin_sdk::variables;
 
+use crate::concurrency::{self, Limits};
+use crate::cors::{self, Cors};
+use crate::discovery::{self, ListToolsParams};
+use crate::health::{
+    self, BreakerConfig, CircuitState, CircuitStatus, ComponentHealth, HealthReport,
+};
 use crate::mcp_types::{
     CallToolRequest, ErrorCode, InitializeRequest, InitializeResponse, JsonRpcRequest,
-    JsonRpcResponse, ListToolsResponse, McpProtocolVersion, ServerCapabilities, ServerInfo,
-    ToolContent, ToolMetadata, ToolResponse,
+    JsonRpcResponse, JsonRpcResult, ListToolsResponse, McpProtocolVersion, ServerCapabilities,
+    ServerInfo, ToolContent, ToolMetadata, ToolResponse,
 };
+use crate::metrics::{self, ToolCall};
+use crate::middleware::{self, MIDDLEWARE_REJECTED};
+use crate::resources::{self, Resource, StaticFile};
+use crate::route::{self, RoutePolicies, RoutePolicy};
+use crate::sampling::{self, SAMPLING_CALL_HEADER, SamplingRelay};
+use crate::sse;
+use crate::trace::{self, FinishedSpan, Span, SpanKind, TraceContext};
+use crate::transform::{self, Claims, ToolTransform, ToolTransforms};
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct GatewayConfig {
     pub server_info: ServerInfo,
     #[serde(default = "default_validate_arguments")]
     pub validate_arguments: bool,
+    /// Number of tools per `tools/list` page; 0 lists every tool at once
+    #[serde(default)]
+    pub tools_page_size: usize,
+    #[serde(skip)]
+    pub breaker: BreakerConfig,
+    /// Record request and tool call metrics for `GET /metrics`. Off by
+    /// default, since it writes to the key-value store on every request.
+    #[serde(default)]
+    pub metrics_enabled: bool,
+    /// Per-tool input transformations, or the reason they are invalid
+    #[serde(skip, default = "default_transforms")]
+    pub transforms: Result<ToolTransforms, String>,
+    /// Read token claims for transforms from the `Authorization` header.
+    /// Only safe when the authorizer has verified the token.
+    #[serde(default)]
+    pub trust_auth_claims: bool,
+    /// Middleware components each request passes through, in order
+    #[serde(default)]
+    pub middleware: Vec<String>,
+    /// Static components whose files are served as resources
+    #[serde(default)]
+    pub resource_components: Vec<String>,
+    /// Per-component call timeouts and retries, or the reason they are
+    /// invalid
+    #[serde(skip, default = "default_routes")]
+    pub routes: Result<RoutePolicies, String>,
 }
 
 fn default_validate_arguments() -> bool {
     true
 }
 
+/// Header carrying how deeply nested a tool call made by another tool is
+pub const CALL_DEPTH_HEADER: &str = "x-ftl-call-depth";
+
+/// Deepest a chain of tools calling tools may nest, matching the SDKs
+pub const MAX_CALL_DEPTH: u32 = 8;
+
+/// Header identifying a client's request to components, so they can
+/// recognize retries of the same call
+pub const REQUEST_ID_HEADER: &str = "x-ftl-request-id";
+
+/// Client headers the request ID is taken from, in order of preference
+const CLIENT_REQUEST_ID_HEADERS: [&str; 2] = ["idempotency-key", "x-request-id"];
+
+/// Identity headers set by the authorizer, forwarded to components
+const AUTH_IDENTITY_HEADERS: [&str; 4] = [
+    "x-auth-user-id",
+    "x-auth-client-id",
+    "x-auth-issuer",
+    "x-auth-scopes",
+];
+
+/// Header carrying the caller's token claims to components
+pub const AUTH_CLAIMS_HEADER: &str = "x-auth-claims";
+
+/// Header carrying the `_meta` of a tool call to components, as base64url
+/// JSON
+pub const META_HEADER: &str = "x-ftl-meta";
+
+/// Header listing the features of the gateway to components, so the SDKs
+/// can tell what they may rely on
+pub const GATEWAY_FEATURES_HEADER: &str = "x-ftl-gateway-features";
+
+/// Features the gateway offers components, as listed in
+/// `GATEWAY_FEATURES_HEADER`. JSON-RPC batches are not supported.
+pub const GATEWAY_FEATURES: &str = "call_tools,sampling,elicitation,streaming";
+
+/// Largest request body the gateway reads by default, in bytes
+pub const DEFAULT_MAX_REQUEST_BYTES: usize = 4 * 1024 * 1024;
+
+/// Error type reported when a request body exceeds `max_request_bytes`
+pub const PAYLOAD_TOO_LARGE: &str = "payload_too_large";
+
+/// Prefix of the `_meta` keys the gateway adds to tool calls. Clients
+/// cannot set keys with this prefix.
+pub const GATEWAY_META_PREFIX: &str = "ftl/";
+
+/// Headers describing the caller, as verified by the authorizer
+fn identity_headers(
+    auth_identity: [Option<String>; 4],
+    claims: Option<&Claims>,
+) -> Vec<(String, String)> {
+    let mut headers: Vec<(String, String)> = AUTH_IDENTITY_HEADERS
+        .iter()
+        .zip(auth_identity)
+        .filter_map(|(name, value)| value.map(|value| ((*name).to_string(), value)))
+        .collect();
+    if let Some(claims) = claims {
+        headers.push((
+            AUTH_CLAIMS_HEADER.to_string(),
+            transform::encode_claims(claims),
+        ));
+    }
+    headers
+}
+
+fn default_transforms() -> Result<ToolTransforms, String> {
+    Ok(ToolTransforms::default())
+}
+
+fn default_routes() -> Result<RoutePolicies, String> {
+    Ok(RoutePolicies::default())
+}
+
 #[derive(Debug, Clone)]
 pub struct ToolScope {
     pub component: Option<String>,
@@ -84,10 +208,92 @@ impl ToolScope {
     }
 }
 
+/// Circuit breakers for the configured components, persisted in the
+/// key-value store. Without a store every circuit stays closed.
+struct Circuits {
+    config: BreakerConfig,
+    store: Option<Store>,
+}
+
+impl Circuits {
+    fn open(config: BreakerConfig) -> Self {
+        let store = if config.enabled() {
+            Store::open_default()
+                .map_err(|e| {
+                    eprintln!("Circuit breaking disabled: failed to open key-value store: {e}");
+                })
+                .ok()
+        } else {
+            None
+        };
+        Self { config, store }
+    }
+
+    fn now() -> u64 {
+        SystemTime::now()
+            .duration_since(UNIX_EPOCH)
+            .map_or(0, |d| d.as_secs())
+    }
+
+    fn load(&self, component: &str) -> CircuitState {
+        self.store
+            .as_ref()
+            .and_then(|store| store.get(&health::circuit_key(component)).ok().flatten())
+            .and_then(|data| serde_json::from_slice(&data).ok())
+            .unwrap_or_default()
+    }
+
+    fn save(&self, component: &str, state: &CircuitState) {
+        if let Some(store) = &self.store
+            && let Ok(data) = serde_json::to_vec(state)
+            && let Err(e) = store.set(&health::circuit_key(component), &data)
+        {
+            eprintln!("Failed to save circuit state for component '{component}': {e}");
+        }
+    }
+
+    fn status(&self, component: &str) -> (CircuitStatus, CircuitState) {
+        let state = self.load(component);
+        (state.status(&self.config, Self::now()), state)
+    }
+
+    /// Whether requests may be routed to the component
+    fn allows(&self, component: &str) -> bool {
+        self.store.is_none() || self.load(component).allows(&self.config, Self::now())
+    }
+
+    /// Record the outcome of a request to the component: `None` for
+    /// success, or the error that made it fail
+    fn record(&self, component: &str, error: Option<&str>) {
+        if self.store.is_none() {
+            return;
+        }
+        let mut state = self.load(component);
+        match error {
+            None if state == CircuitState::default() => return,
+            None => state.record_success(),
+            Some(error) => state.record_failure(&self.config, Self::now(), error),
+        }
+        self.save(component, &state);
+    }
+}
+
 pub struct McpGateway {
     config: GatewayConfig,
     scope: Option<ToolScope>,
     allowed_toolsets: Option<Vec<String>>,
+    circuits: Circuits,
+    limits: Limits,
+    trace: TraceContext,
+    spans: RefCell<Vec<FinishedSpan>>,
+    tool_calls: RefCell<Vec<ToolCall>>,
+    claims: Option<Claims>,
+    call_depth: u32,
+    request_id: Option<String>,
+    identity: Vec<(String, String)>,
+    user_agent: Option<String>,
+    sampling_call: Option<String>,
+    sampling: Option<SamplingRelay>,
 }
 
 impl McpGateway {
@@ -96,10 +302,181 @@ impl McpGateway {
         scope: Option<ToolScope>,
         allowed_toolsets: Option<Vec<String>>,
     ) -> Self {
+        let circuits = Circuits::open(config.breaker);
         Self {
             config,
             scope,
             allowed_toolsets,
+            circuits,
+            limits: Limits::open(),
+            trace: TraceContext::new_root(),
+            spans: RefCell::new(Vec::new()),
+            tool_calls: RefCell::new(Vec::new()),
+            claims: None,
+            call_depth: 0,
+            request_id: None,
+            identity: Vec::new(),
+            user_agent: None,
+            sampling_call: None,
+            sampling: None,
+        }
+    }
+
+    /// Relay sampling requests of the tools called to the client's stream
+    pub fn with_sampling(mut self, relay: Option<SamplingRelay>) -> Self {
+        self.sampling = relay;
+        self
+    }
+
+    /// Pass on the streamed call a tool calling other tools was made in,
+    /// so the tools it calls may request sampling too
+    pub fn with_sampling_call(mut self, call: Option<String>) -> Self {
+        self.sampling_call = call;
+        self
+    }
+
+    /// The streamed call the request's sampling requests are relayed in
+    fn sampling_call(&self) -> Option<&str> {
+        self.sampling
+            .as_ref()
+            .map(|relay| relay.call.as_str())
+            .or(self.sampling_call.as_deref())
+    }
+
+    /// Set how deeply nested the request's tool calls are, when a tool
+    /// calls other tools through the gateway
+    pub fn with_call_depth(mut self, call_depth: u32) -> Self {
+        self.call_depth = call_depth;
+        self
+    }
+
+    /// Forward the client's request ID to the components it calls
+    pub fn with_request_id(mut self, request_id: Option<String>) -> Self {
+        self.request_id = request_id;
+        self
+    }
+
+    /// Tell components which client made the request
+    pub fn with_user_agent(mut self, user_agent: Option<String>) -> Self {
+        self.user_agent = user_agent;
+        self
+    }
+
+    /// Forward the caller's identity to the components it calls
+    pub fn with_identity(mut self, identity: Vec<(String, String)>) -> Self {
+        self.identity = identity;
+        self
+    }
+
+    /// Use the claims of the caller's token in input transformations
+    pub fn with_claims(mut self, claims: Option<Claims>) -> Self {
+        self.claims = claims;
+        self
+    }
+
+    /// The input transformation for a component's tool, if any
+    fn transform_for(
+        &self,
+        component_name: &str,
+        tool_name: &str,
+    ) -> Result<Option<&ToolTransform>, String> {
+        self.config
+            .transforms
+            .as_ref()
+            .map(|transforms| transforms.get(component_name, tool_name))
+            .map_err(Clone::clone)
+    }
+
+    /// The policy for calls of a component's tools
+    fn route_for(&self, component_name: &str) -> Result<RoutePolicy, String> {
+        self.config
+            .routes
+            .as_ref()
+            .map(|routes| routes.get(component_name))
+            .map_err(Clone::clone)
+    }
+
+    /// Record component calls as children of the given trace context
+    pub fn with_trace(mut self, trace: TraceContext) -> Self {
+        self.trace = trace;
+        self
+    }
+
+    /// Start a client span for a call to a component
+    /// The `_meta` forwarded with a tool call: the client's, plus what
+    /// the gateway knows about the call so components can correlate it
+    fn call_meta(
+        &self,
+        client_meta: Option<serde_json::Value>,
+    ) -> serde_json::Map<String, serde_json::Value> {
+        let mut meta = match client_meta {
+            Some(serde_json::Value::Object(meta)) => meta,
+            _ => serde_json::Map::new(),
+        };
+        meta.retain(|key, _| !key.starts_with(GATEWAY_META_PREFIX));
+        meta.insert(
+            format!("{GATEWAY_META_PREFIX}traceId"),
+            self.trace.trace_id.clone().into(),
+        );
+        if let Some(request_id) = &self.request_id {
+            meta.insert(
+                format!("{GATEWAY_META_PREFIX}requestId"),
+                request_id.clone().into(),
+            );
+        }
+        if let Some(user_agent) = &self.user_agent {
+            meta.insert(
+                format!("{GATEWAY_META_PREFIX}userAgent"),
+                user_agent.clone().into(),
+            );
+        }
+        meta
+    }
+
+    fn start_span(&self, name: String, component_name: &str) -> Span {
+        let mut span = Span::start(&self.trace, name, SpanKind::Client);
+        span.set_attribute("mcp.component", component_name);
+        span
+    }
+
+    fn end_span(&self, span: Span, error: Option<&str>) {
+        self.spans.borrow_mut().push(span.finish(error));
+    }
+
+    /// Take the spans recorded for component calls so far
+    pub fn take_spans(&self) -> Vec<FinishedSpan> {
+        self.spans.take()
+    }
+
+    /// Take the tool calls made so far, for metrics
+    pub fn take_tool_calls(&self) -> Vec<ToolCall> {
+        self.tool_calls.take()
+    }
+
+    fn record_tool_call(
+        &self,
+        component_name: &str,
+        tool_name: &str,
+        error: bool,
+        started: Instant,
+        request_bytes: usize,
+        response_bytes: usize,
+    ) {
+        self.tool_calls.borrow_mut().push(ToolCall {
+            component: component_name.to_string(),
+            tool: tool_name.to_string(),
+            error,
+            duration_seconds: started.elapsed().as_secs_f64(),
+            request_bytes,
+            response_bytes,
+        });
+    }
+
+    /// Propagate the span's trace context to a component request
+    fn propagate(builder: &mut RequestBuilder, span: &Span) {
+        builder.header(trace::TRACEPARENT_HEADER, span.traceparent());
+        if let Some(state) = span.trace_state() {
+            builder.header(trace::TRACESTATE_HEADER, state);
         }
     }
 
@@ -108,21 +485,41 @@ impl McpGateway {
         name.replace('_', "-")
     }
 
-    /// Fetch metadata for all tools in a component
+    /// Fetch metadata for all tools in a component. Components whose
+    /// circuit is open are skipped.
     async fn fetch_component_tools(&self, component_name: &str) -> Vec<ToolMetadata> {
+        if !self.circuits.allows(component_name) {
+            eprintln!("Skipping component '{component_name}': circuit is open");
+            return vec![];
+        }
+
         let component_name_kebab = Self::snake_to_kebab(component_name);
         let component_url = format!("http://{component_name_kebab}.spin.internal/");
+        let span = self.start_span(format!("tools/list {component_name}"), component_name);
 
-        let req = Request::builder()
-            .method(Method::Get)
-            .uri(&component_url)
-            .build();
+        let mut builder = Request::builder();
+        builder.method(Method::Get).uri(&component_url);
+        Self::propagate(&mut builder, &span);
+        let req = builder.build();
 
         match spin_sdk::http::send::<_, spin_sdk::http::Response>(req).await {
             Ok(resp) => {
-                if *resp.status() == 200 {
+                let status = *resp.status();
+                if status >= 500 {
+                    self.circuits
+                        .record(component_name, Some(&format!("status {status}")));
+                } else {
+                    self.circuits.record(component_name, None);
+                }
+                let error = (status != 200).then(|| format!("status {status}"));
+                self.end_span(span, error.as_deref());
+
+                if status == 200 {
                     match serde_json::from_slice::<Vec<ToolMetadata>>(resp.body()) {
-                        Ok(tools) => tools,
+                        Ok(tools) => {
+                            self.limits.remember(component_name, &tools);
+                            tools
+                        }
                         Err(e) => {
                             eprintln!(
                                 "Failed to parse metadata from component '{component_name}': {e}"
@@ -141,11 +538,64 @@ impl McpGateway {
             }
             Err(e) => {
                 eprintln!("Failed to fetch metadata from component '{component_name}': {e}");
+                self.circuits.record(component_name, Some(&e.to_string()));
+                self.end_span(span, Some(&e.to_string()));
                 vec![]
             }
         }
     }
 
+    /// Probe a component's health endpoint. Components built with SDKs that
+    /// lack the endpoint are checked through their metadata route instead.
+    async fn probe_component(component_name: &str) -> Result<(), String> {
+        let component_name_kebab = Self::snake_to_kebab(component_name);
+
+        for path in [health::HEALTH_PATH, "/"] {
+            let req = Request::builder()
+                .method(Method::Get)
+                .uri(format!("http://{component_name_kebab}.spin.internal{path}"))
+                .build();
+
+            let status = match spin_sdk::http::send::<_, spin_sdk::http::Response>(req).await {
+                Ok(resp) => *resp.status(),
+                Err(e) => return Err(e.to_string()),
+            };
+            match status {
+                200..=299 => return Ok(()),
+                404 | 405 if path == health::HEALTH_PATH => {}
+                _ => return Err(format!("status {status}")),
+            }
+        }
+        Err("no health or metadata endpoint".to_string())
+    }
+
+    /// Check every configured component, updating their circuits
+    pub async fn check_health(&self) -> Result<HealthReport, String> {
+        let component_names_str = variables::get("component_names")
+            .map_err(|e| format!("Failed to get components configuration: {e}"))?;
+        let component_names: Vec<&str> = component_names_str
+            .split(',')
+            .map(str::trim)
+            .filter(|name| !name.is_empty())
+            .collect();
+
+        let probes = component_names.iter().map(|component_name| async move {
+            let outcome = Self::probe_component(component_name).await;
+            self.circuits
+                .record(component_name, outcome.as_ref().err().map(String::as_str));
+            let (circuit, state) = self.circuits.status(component_name);
+            ComponentHealth {
+                component: (*component_name).to_string(),
+                healthy: outcome.is_ok() && circuit != CircuitStatus::Open,
+                circuit,
+                consecutive_failures: state.consecutive_failures,
+                error: outcome.err(),
+            }
+        });
+
+        Ok(HealthReport::new(futures::future::join_all(probes).await))
+    }
+
     /// Validate tool arguments against the tool's input schema
     fn validate_arguments(
         tool_name: &str,
@@ -175,6 +625,89 @@ impl McpGateway {
         }
     }
 
+    /// Pass a request through the app's middleware, in order. Returns the
+    /// request to handle, or the error response of a rejected request.
+    /// Tool calls made by other tools have passed the middleware already.
+    pub async fn run_middleware(
+        &self,
+        mut request: JsonRpcRequest,
+    ) -> Result<JsonRpcRequest, JsonRpcResponse> {
+        if self.call_depth > 0 {
+            return Ok(request);
+        }
+        for name in &self.config.middleware {
+            let id = request.id.clone();
+            request = self
+                .call_middleware(name, request)
+                .await
+                .map_err(|rejection| {
+                    let mut response = JsonRpcResponse::error(
+                        id,
+                        ErrorCode::INVALID_REQUEST.0,
+                        &rejection.message,
+                    );
+                    if let JsonRpcResult::Error { error } = &mut response.result {
+                        error.data = Some(serde_json::json!({
+                            "type": MIDDLEWARE_REJECTED,
+                            "middleware": rejection.middleware,
+                            "status": rejection.status,
+                        }));
+                    }
+                    response
+                })?;
+        }
+        Ok(request)
+    }
+
+    /// Post a request to one middleware component and apply its answer
+    async fn call_middleware(
+        &self,
+        name: &str,
+        request: JsonRpcRequest,
+    ) -> Result<JsonRpcRequest, middleware::Rejection> {
+        let span = self.start_span(format!("middleware {name}"), name);
+        let url = format!("http://{}.spin.internal/", Self::snake_to_kebab(name));
+        let body = serde_json::to_vec(&request).unwrap_or_default();
+
+        let mut builder = Request::builder();
+        builder
+            .method(Method::Post)
+            .uri(&url)
+            .header("Content-Type", "application/json")
+            .body(body);
+        if let Some(request_id) = &self.request_id {
+            builder.header(REQUEST_ID_HEADER, request_id.as_str());
+        }
+        if let Some(user_agent) = &self.user_agent {
+            builder.header("User-Agent", user_agent.as_str());
+        }
+        for (header, value) in &self.identity {
+            builder.header(header.as_str(), value.as_str());
+        }
+        Self::propagate(&mut builder, &span);
+
+        match spin_sdk::http::send::<_, spin_sdk::http::Response>(builder.build()).await {
+            Ok(resp) => {
+                let status = *resp.status();
+                let result = middleware::apply(name, status, resp.body(), request);
+                let error = result
+                    .as_ref()
+                    .err()
+                    .map(|rejection| rejection.message.clone());
+                self.end_span(span, error.as_deref());
+                result
+            }
+            Err(e) => {
+                self.end_span(span, Some(&e.to_string()));
+                Err(middleware::Rejection {
+                    middleware: name.to_string(),
+                    status: 502,
+                    message: format!("Middleware '{name}' is unavailable: {e}"),
+                })
+            }
+        }
+    }
+
     pub async fn handle_request(&self, request: JsonRpcRequest) -> Option<JsonRpcResponse> {
         match request.method.as_str() {
             "initialize" => Some(self.handle_initialize(request)),
@@ -185,7 +718,8 @@ impl McpGateway {
             "tools/list" => Some(self.handle_list_tools(request).await),
             "tools/call" => Some(self.handle_call_tool(request).await),
             "prompts/list" => Some(Self::handle_list_prompts(request)),
-            "resources/list" => Some(Self::handle_list_resources(request)),
+            "resources/list" => Some(self.handle_list_resources(request).await),
+            "resources/read" => Some(self.handle_read_resource(request).await),
             "ping" => Some(Self::handle_ping(self, request)),
             _ => Some(JsonRpcResponse::error(
                 request.id,
@@ -261,6 +795,13 @@ impl McpGateway {
     }
 
     async fn handle_list_tools(&self, request: JsonRpcRequest) -> JsonRpcResponse {
+        let params = match ListToolsParams::from_value(request.params) {
+            Ok(params) => params,
+            Err(e) => {
+                return JsonRpcResponse::error(request.id, ErrorCode::INVALID_PARAMS.0, &e);
+            }
+        };
+
         // Get the list of components from the spin variable
         let component_names_str = match variables::get("component_names") {
             Ok(components) => components,
@@ -297,6 +838,9 @@ impl McpGateway {
             component_names.retain(|name| allowed.iter().any(|a| a == name));
         }
 
+        // Skip components excluded by the request's filter before fetching
+        component_names.retain(|name| params.filter.matches_component(name));
+
         // Create futures for fetching metadata from selected components in parallel
         let metadata_futures: Vec<_> = component_names
             .iter()
@@ -315,15 +859,29 @@ impl McpGateway {
 
         for (component_name, component_tools) in results {
             for mut tool in component_tools {
+                // Show the arguments clients send, not those the component receives
+                if let Ok(Some(transform)) = self.transform_for(&component_name, &tool.name) {
+                    tool.input_schema = transform.adapt_schema(&tool.input_schema);
+                }
                 // Only prefix tool names when unscoped (at /mcp root)
                 if !is_scoped {
                     tool.name = format!("{}__{}", component_name, tool.name);
                 }
-                tools.push(tool);
+                if params.filter.matches_tool(&tool) {
+                    tools.push(tool);
+                }
             }
         }
 
-        let response = ListToolsResponse { tools };
+        let (tools, next_cursor) =
+            match discovery::paginate(tools, &params, self.config.tools_page_size) {
+                Ok(page) => page,
+                Err(e) => {
+                    return JsonRpcResponse::error(request.id, ErrorCode::INVALID_PARAMS.0, &e);
+                }
+            };
+
+        let response = ListToolsResponse { tools, next_cursor };
         match serde_json::to_value(response) {
             Ok(value) => JsonRpcResponse::success(request.id, value),
             Err(e) => JsonRpcResponse::error(
@@ -339,44 +897,156 @@ impl McpGateway {
         component_name: &str,
         tool_name: &str,
         tool_arguments: serde_json::Value,
-    ) -> Result<ToolResponse, String> {
+        client_meta: Option<serde_json::Value>,
+    ) -> Result<(ToolResponse, Option<serde_json::Value>), String> {
+        if !self.circuits.allows(component_name) {
+            return Err(format!(
+                "Component '{component_name}' is temporarily unavailable after repeated failures"
+            ));
+        }
+
+        let policy = self.route_for(component_name)?;
+
         let component_name_kebab = Self::snake_to_kebab(component_name);
         let tool_url = format!("http://{component_name_kebab}.spin.internal/{tool_name}");
+        let mut span = self.start_span(
+            format!("tools/call {component_name}/{tool_name}"),
+            component_name,
+        );
+        span.set_attribute("mcp.tool", tool_name);
 
-        let req = Request::builder()
-            .method(Method::Post)
-            .uri(&tool_url)
-            .header("Content-Type", "application/json")
-            .body(
-                serde_json::to_vec(&tool_arguments)
-                    .unwrap_or_else(|_| br#"{"error":"Failed to serialize request"}"#.to_vec()),
-            )
-            .build();
+        let request_body = serde_json::to_vec(&tool_arguments)
+            .unwrap_or_else(|_| br#"{"error":"Failed to serialize request"}"#.to_vec());
+        let request_bytes = request_body.len();
+        let meta = URL_SAFE_NO_PAD
+            .encode(serde_json::to_vec(&self.call_meta(client_meta)).unwrap_or_default());
 
-        match spin_sdk::http::send::<_, spin_sdk::http::Response>(req).await {
+        // Requests are consumed by sending them, so each attempt builds its own
+        let build_request = || {
+            let mut builder = Request::builder();
+            builder
+                .method(Method::Post)
+                .uri(&tool_url)
+                .header("Content-Type", "application/json")
+                .body(request_body.clone());
+            if self.call_depth > 0 {
+                builder.header(CALL_DEPTH_HEADER, self.call_depth.to_string());
+            }
+            if let Some(request_id) = &self.request_id {
+                builder.header(REQUEST_ID_HEADER, request_id.as_str());
+            }
+            if let Some(call) = self.sampling_call() {
+                builder.header(SAMPLING_CALL_HEADER, call);
+            }
+            for (name, value) in &self.identity {
+                builder.header(name.as_str(), value.as_str());
+            }
+            builder.header(META_HEADER, meta.as_str());
+            builder.header(GATEWAY_FEATURES_HEADER, GATEWAY_FEATURES);
+            Self::propagate(&mut builder, &span);
+            builder.build()
+        };
+
+        let started = Instant::now();
+        let mut attempt = 0;
+        let result = loop {
+            let sent = route::with_timeout(
+                spin_sdk::http::send::<_, spin_sdk::http::Response>(build_request()),
+                policy.timeout(),
+            );
+            let result = match &self.sampling {
+                Some(relay) => relay.relay_while(sent).await,
+                None => sent.await,
+            };
+            // Only calls that never reached the component are retried
+            match result {
+                Some(Err(e)) if attempt < policy.retries() => {
+                    attempt += 1;
+                    eprintln!(
+                        "Retrying call of {component_name}/{tool_name} ({attempt}/{}): {e}",
+                        policy.retries()
+                    );
+                    route::sleep(policy.backoff(attempt)).await;
+                }
+                result => break result,
+            }
+        };
+        let Some(result) = result else {
+            let seconds = policy.timeout().map_or(0, |timeout| timeout.as_secs());
+            let error = format!("timed out after {seconds}s");
+            self.circuits.record(component_name, Some(&error));
+            self.end_span(span, Some(&error));
+            self.record_tool_call(component_name, tool_name, true, started, request_bytes, 0);
+            return Err(format!(
+                "Tool '{tool_name}' did not respond within {seconds} seconds"
+            ));
+        };
+        match result {
             Ok(resp) => {
                 let status = resp.status();
                 let body = resp.body();
+                self.record_tool_call(
+                    component_name,
+                    tool_name,
+                    *status != 200,
+                    started,
+                    request_bytes,
+                    body.len(),
+                );
+
+                if *status >= 500 {
+                    self.circuits
+                        .record(component_name, Some(&format!("status {status}")));
+                } else {
+                    self.circuits.record(component_name, None);
+                }
+                let error = (*status != 200).then(|| format!("status {status}"));
+                self.end_span(span, error.as_deref());
 
                 if *status == 200 {
-                    serde_json::from_slice::<ToolResponse>(body)
-                        .map_err(|e| format!("Tool returned invalid response format: {e}"))
+                    Self::parse_tool_response(body)
                 } else {
                     let error_text = String::from_utf8_lossy(body);
-                    Ok(ToolResponse {
-                        content: vec![ToolContent::Text {
-                            text: format!("Tool execution failed (status {status}): {error_text}"),
-                            annotations: None,
-                        }],
-                        structured_content: None,
-                        is_error: Some(true),
-                    })
+                    Ok((
+                        ToolResponse {
+                            content: vec![ToolContent::Text {
+                                text: format!(
+                                    "Tool execution failed (status {status}): {error_text}"
+                                ),
+                                annotations: None,
+                            }],
+                            structured_content: None,
+                            is_error: Some(true),
+                        },
+                        None,
+                    ))
                 }
             }
-            Err(e) => Err(format!("Failed to call tool '{tool_name}': {e}")),
+            Err(e) => {
+                self.circuits.record(component_name, Some(&e.to_string()));
+                self.end_span(span, Some(&e.to_string()));
+                self.record_tool_call(component_name, tool_name, true, started, request_bytes, 0);
+                Err(format!("Failed to call tool '{tool_name}': {e}"))
+            }
         }
     }
 
+    /// Parse a component's tool response, keeping the `_meta` the tool
+    /// attached to pass it on to the client
+    fn parse_tool_response(
+        body: &[u8],
+    ) -> Result<(ToolResponse, Option<serde_json::Value>), String> {
+        let mut value: serde_json::Value = serde_json::from_slice(body)
+            .map_err(|e| format!("Tool returned invalid response format: {e}"))?;
+        let meta = value
+            .as_object_mut()
+            .and_then(|response| response.remove("_meta"))
+            .filter(serde_json::Value::is_object);
+        let response = serde_json::from_value::<ToolResponse>(value)
+            .map_err(|e| format!("Tool returned invalid response format: {e}"))?;
+        Ok((response, meta))
+    }
+
     /// Parse and validate the tool call parameters
     fn parse_tool_params(
         request_id: Option<serde_json::Value>,
@@ -427,6 +1097,14 @@ impl McpGateway {
             );
         }
 
+        if self.call_depth > MAX_CALL_DEPTH {
+            return JsonRpcResponse::error(
+                request.id,
+                ErrorCode::INVALID_REQUEST.0,
+                &format!("Tool call depth limit of {MAX_CALL_DEPTH} exceeded"),
+            );
+        }
+
         // Parse and validate parameters
         let params = match Self::parse_tool_params(request.id.clone(), request.params) {
             Ok(p) => p,
@@ -479,9 +1157,30 @@ impl McpGateway {
             );
         }
 
-        // Validate arguments if validation is enabled
-        let tool_arguments = params.arguments.unwrap_or_else(|| serde_json::json!({}));
+        // Apply the app's input transformations before validating, so the
+        // component's schema sees the arguments it will receive
+        let mut tool_arguments = params.arguments.unwrap_or_else(|| serde_json::json!({}));
+        match self.transform_for(&component_name, &actual_tool_name) {
+            Ok(Some(transform)) => match transform.apply(tool_arguments, self.claims.as_ref()) {
+                Ok(arguments) => tool_arguments = arguments,
+                Err(e) => {
+                    return JsonRpcResponse::error(
+                        request.id,
+                        ErrorCode::INVALID_PARAMS.0,
+                        &format!("Invalid params: {e}"),
+                    );
+                }
+            },
+            Ok(None) => {}
+            Err(e) => {
+                return JsonRpcResponse::error(request.id, ErrorCode::INTERNAL_ERROR.0, &e);
+            }
+        }
 
+        // Validate arguments if validation is enabled. Without validation
+        // the tool's metadata is not fetched, so its concurrency limit is the
+        // one last seen when its component's tools were listed.
+        let mut max_concurrency = None;
         if self.config.validate_arguments {
             // Fetch the tool metadata from the specific component for validation
             let tools = self.fetch_component_tools(&component_name).await;
@@ -489,6 +1188,7 @@ impl McpGateway {
 
             match tool_metadata {
                 Some(metadata) => {
+                    max_concurrency = concurrency::max_concurrency(&metadata);
                     // Validate arguments against the tool's input schema
                     if let Err(validation_error) = Self::validate_arguments(
                         &params.name,
@@ -512,15 +1212,36 @@ impl McpGateway {
                     );
                 }
             }
+        } else {
+            max_concurrency = self.limits.limit(&component_name, &actual_tool_name);
         }
 
+        // Hold one of the tool's slots while it runs
+        let _slot = match max_concurrency {
+            Some(max) => match self.limits.acquire(&component_name, &actual_tool_name, max) {
+                Some(slot) => Some(slot),
+                None => return Self::concurrency_limited(request.id, &params.name, max),
+            },
+            None => None,
+        };
+
         // Execute the tool call
         match self
-            .execute_tool_call(&component_name, &actual_tool_name, tool_arguments)
+            .execute_tool_call(
+                &component_name,
+                &actual_tool_name,
+                tool_arguments,
+                params.meta,
+            )
             .await
         {
-            Ok(tool_response) => match serde_json::to_value(tool_response) {
-                Ok(value) => JsonRpcResponse::success(request.id, value),
+            Ok((tool_response, meta)) => match serde_json::to_value(tool_response) {
+                Ok(mut value) => {
+                    if let (Some(meta), Some(result)) = (meta, value.as_object_mut()) {
+                        result.insert("_meta".to_string(), meta);
+                    }
+                    JsonRpcResponse::success(request.id, value)
+                }
                 Err(e) => JsonRpcResponse::error(
                     request.id,
                     ErrorCode::INTERNAL_ERROR.0,
@@ -535,6 +1256,29 @@ impl McpGateway {
         }
     }
 
+    /// The retriable error for a call to a tool at its concurrency limit
+    fn concurrency_limited(
+        request_id: Option<serde_json::Value>,
+        tool_name: &str,
+        max_concurrency: u64,
+    ) -> JsonRpcResponse {
+        let mut response = JsonRpcResponse::error(
+            request_id,
+            concurrency::CONCURRENCY_LIMITED,
+            &format!(
+                "Tool '{tool_name}' is busy: {max_concurrency} calls are already running, try again later"
+            ),
+        );
+        if let JsonRpcResult::Error { error } = &mut response.result {
+            error.data = Some(serde_json::json!({
+                "type": concurrency::CONCURRENCY_LIMITED_TYPE,
+                "retryable": true,
+                "max_concurrency": max_concurrency,
+            }));
+        }
+        response
+    }
+
     fn handle_ping(_gateway: &Self, request: JsonRpcRequest) -> JsonRpcResponse {
         JsonRpcResponse::success(request.id, serde_json::json!({}))
     }
@@ -549,30 +1293,601 @@ impl McpGateway {
         )
     }
 
-    fn handle_list_resources(request: JsonRpcRequest) -> JsonRpcResponse {
-        // Return empty resources list - this gateway doesn't support resources
-        JsonRpcResponse::success(
-            request.id,
-            serde_json::json!({
-                "resources": []
-            }),
+    /// The static components a request may read resources from
+    fn resource_components(&self) -> Vec<&str> {
+        let scoped = self.scope.as_ref().and_then(|s| s.component.as_deref());
+        self.config
+            .resource_components
+            .iter()
+            .map(String::as_str)
+            .filter(|name| scoped.is_none_or(|scoped| scoped == *name))
+            .collect()
+    }
+
+    /// Fetch the files a static component serves
+    async fn fetch_component_files(&self, component_name: &str) -> Vec<StaticFile> {
+        let component_name_kebab = Self::snake_to_kebab(component_name);
+        let span = self.start_span(format!("resources/list {component_name}"), component_name);
+
+        let mut builder = Request::builder();
+        builder
+            .method(Method::Get)
+            .uri(format!("http://{component_name_kebab}.spin.internal/"));
+        Self::propagate(&mut builder, &span);
+
+        match spin_sdk::http::send::<_, spin_sdk::http::Response>(builder.build()).await {
+            Ok(resp) if *resp.status() == 200 => {
+                self.end_span(span, None);
+                serde_json::from_slice::<Vec<StaticFile>>(resp.body()).unwrap_or_else(|e| {
+                    eprintln!("Failed to parse files of component '{component_name}': {e}");
+                    vec![]
+                })
+            }
+            Ok(resp) => {
+                let error = format!("status {}", resp.status());
+                eprintln!("Component '{component_name}' returned {error} for its files");
+                self.end_span(span, Some(&error));
+                vec![]
+            }
+            Err(e) => {
+                eprintln!("Failed to fetch files from component '{component_name}': {e}");
+                self.end_span(span, Some(&e.to_string()));
+                vec![]
+            }
+        }
+    }
+
+    async fn handle_list_resources(&self, request: JsonRpcRequest) -> JsonRpcResponse {
+        let component_names = self.resource_components();
+        let listings =
+            futures::future::join_all(component_names.iter().map(|component_name| async move {
+                let files = self.fetch_component_files(component_name).await;
+                (*component_name, files)
+            }))
+            .await;
+
+        let resources: Vec<Resource> = listings
+            .into_iter()
+            .flat_map(|(component_name, files)| {
+                files
+                    .into_iter()
+                    .map(move |file| Resource::from_file(component_name, file))
+            })
+            .collect();
+
+        JsonRpcResponse::success(request.id, serde_json::json!({ "resources": resources }))
+    }
+
+    async fn handle_read_resource(&self, request: JsonRpcRequest) -> JsonRpcResponse {
+        let uri = match request
+            .params
+            .as_ref()
+            .and_then(|params| params.get("uri"))
+            .and_then(serde_json::Value::as_str)
+        {
+            Some(uri) => uri.to_string(),
+            None => {
+                return JsonRpcResponse::error(
+                    request.id,
+                    ErrorCode::INVALID_PARAMS.0,
+                    "Missing resource uri",
+                );
+            }
+        };
+        let (component_name, path) = match resources::parse_uri(&uri) {
+            Ok(parts) => parts,
+            Err(e) => {
+                return JsonRpcResponse::error(request.id, ErrorCode::INVALID_PARAMS.0, &e);
+            }
+        };
+        if !self
+            .resource_components()
+            .contains(&component_name.as_str())
+        {
+            return JsonRpcResponse::error(
+                request.id,
+                resources::RESOURCE_NOT_FOUND,
+                &format!("Resource '{uri}' not found"),
+            );
+        }
+
+        let component_name_kebab = Self::snake_to_kebab(&component_name);
+        let span = self.start_span(format!("resources/read {component_name}"), &component_name);
+        let mut builder = Request::builder();
+        builder.method(Method::Get).uri(format!(
+            "http://{component_name_kebab}.spin.internal/{}",
+            resources::encode_path(&path)
+        ));
+        Self::propagate(&mut builder, &span);
+
+        match spin_sdk::http::send::<_, spin_sdk::http::Response>(builder.build()).await {
+            Ok(resp) if *resp.status() == 200 => {
+                self.end_span(span, None);
+                let mime_type = resp
+                    .header("content-type")
+                    .and_then(|value| value.as_str())
+                    .unwrap_or("application/octet-stream")
+                    .to_string();
+                JsonRpcResponse::success(
+                    request.id,
+                    serde_json::json!({
+                        "contents": [resources::contents(&uri, &mime_type, resp.body())]
+                    }),
+                )
+            }
+            Ok(resp) if *resp.status() == 404 => {
+                self.end_span(span, Some("status 404"));
+                JsonRpcResponse::error(
+                    request.id,
+                    resources::RESOURCE_NOT_FOUND,
+                    &format!("Resource '{uri}' not found"),
+                )
+            }
+            Ok(resp) => {
+                let error = format!("status {}", resp.status());
+                self.end_span(span, Some(&error));
+                JsonRpcResponse::error(
+                    request.id,
+                    ErrorCode::INTERNAL_ERROR.0,
+                    &format!("Failed to read resource '{uri}': {error}"),
+                )
+            }
+            Err(e) => {
+                self.end_span(span, Some(&e.to_string()));
+                JsonRpcResponse::error(
+                    request.id,
+                    ErrorCode::INTERNAL_ERROR.0,
+                    &format!("Failed to read resource '{uri}': {e}"),
+                )
+            }
+        }
+    }
+}
+
+/// Read a numeric Spin variable, falling back to a default when it is
+/// missing or invalid
+fn numeric_variable<T: std::str::FromStr>(name: &str, default: T) -> T {
+    variables::get(name)
+        .ok()
+        .and_then(|value| value.trim().parse::<T>().ok())
+        .unwrap_or(default)
+}
+
+/// Build the gateway configuration from Spin variables
+fn gateway_config() -> GatewayConfig {
+    let validate_arguments = variables::get("validate_arguments")
+        .unwrap_or_else(|_| "true".to_string())
+        .parse::<bool>()
+        .unwrap_or(true);
+
+    let defaults = BreakerConfig::default();
+
+    GatewayConfig {
+        server_info: ServerInfo {
+            name: "mcp-gateway".to_string(),
+            version: "0.0.1".to_string(),
+        },
+        validate_arguments,
+        tools_page_size: numeric_variable("tools_page_size", 0),
+        breaker: BreakerConfig {
+            failure_threshold: numeric_variable(
+                "circuit_failure_threshold",
+                defaults.failure_threshold,
+            ),
+            cooldown_secs: numeric_variable("circuit_cooldown_seconds", defaults.cooldown_secs),
+        },
+        metrics_enabled: variables::get("metrics_enabled")
+            .ok()
+            .and_then(|value| value.trim().parse::<bool>().ok())
+            .unwrap_or(false),
+        transforms: ToolTransforms::parse(&variables::get("tool_transforms").unwrap_or_default()),
+        trust_auth_claims: variables::get("trust_auth_claims")
+            .ok()
+            .and_then(|value| value.trim().parse::<bool>().ok())
+            .unwrap_or(false),
+        middleware: middleware::parse_names(
+            &variables::get("middleware_names").unwrap_or_default(),
+        ),
+        resource_components: middleware::parse_names(
+            &variables::get("resource_components").unwrap_or_default(),
+        ),
+        routes: RoutePolicies::parse(&variables::get("component_routes").unwrap_or_default()),
+    }
+}
+
+/// Add a handled request and its tool calls to the stored metrics
+fn update_metrics(method: &str, error: bool, tool_calls: &[ToolCall]) {
+    let store = match Store::open_default() {
+        Ok(store) => store,
+        Err(e) => {
+            eprintln!("Failed to record metrics: failed to open key-value store: {e}");
+            return;
+        }
+    };
+    for (key, amount) in metrics::increments(method, error, tool_calls) {
+        let value = load_metric(&store, &key).unwrap_or(0.0) + amount;
+        if let Err(e) = store.set(&key, value.to_string().as_bytes()) {
+            eprintln!("Failed to save metric {key}: {e}");
+        }
+    }
+}
+
+fn load_metric(store: &Store, key: &str) -> Option<f64> {
+    store
+        .get(key)
+        .ok()
+        .flatten()
+        .and_then(|data| String::from_utf8(data).ok())
+        .and_then(|value| value.trim().parse::<f64>().ok())
+}
+
+/// Every stored metric, including the authentication failures recorded by
+/// the MCP authorizer, by key
+fn load_metrics(store: &Store) -> BTreeMap<String, f64> {
+    store
+        .get_keys()
+        .unwrap_or_default()
+        .into_iter()
+        .filter(|key| key.starts_with(metrics::PREFIX))
+        .filter_map(|key| {
+            let value = load_metric(store, &key)?;
+            Some((key, value))
+        })
+        .collect()
+}
+
+/// Serve the stored metrics in the Prometheus text format
+fn handle_metrics_request() -> Response {
+    let values = match Store::open_default() {
+        Ok(store) => load_metrics(&store),
+        Err(e) => {
+            return Response::builder()
+                .status(500)
+                .header("Content-Type", "text/plain")
+                .body(format!("Failed to open key-value store: {e}").into_bytes())
+                .build();
+        }
+    };
+
+    Response::builder()
+        .status(200)
+        .header("Content-Type", metrics::CONTENT_TYPE)
+        .body(metrics::render(&values).into_bytes())
+        .build()
+}
+
+/// Send finished spans to the OTLP/HTTP endpoint in the
+/// `otlp_traces_endpoint` variable. Tracing is off when it is unset.
+async fn export_spans(spans: &[FinishedSpan]) {
+    let endpoint = variables::get("otlp_traces_endpoint").unwrap_or_default();
+    let endpoint = endpoint.trim();
+    if endpoint.is_empty() || !spans.iter().any(FinishedSpan::sampled) {
+        return;
+    }
+
+    let Ok(body) = serde_json::to_vec(&trace::otlp_request(spans)) else {
+        return;
+    };
+    let req = Request::builder()
+        .method(Method::Post)
+        .uri(endpoint)
+        .header("Content-Type", "application/json")
+        .body(body)
+        .build();
+
+    match spin_sdk::http::send::<_, spin_sdk::http::Response>(req).await {
+        Ok(resp) if !(200..300).contains(resp.status()) => {
+            eprintln!("OTLP endpoint returned status {}", resp.status());
+        }
+        Ok(_) => {}
+        Err(e) => eprintln!("Failed to export traces: {e}"),
+    }
+}
+
+/// Report the health of every component. Responds 503 when any component
+/// is unhealthy so load balancers and monitors can act on the status alone.
+async fn handle_health_request() -> Response {
+    let gateway = McpGateway::new(gateway_config(), None, None);
+
+    let (status, body) = match gateway.check_health().await {
+        Ok(report) => (
+            if report.healthy { 200 } else { 503 },
+            serde_json::to_vec(&report),
+        ),
+        Err(e) => (500, serde_json::to_vec(&serde_json::json!({ "error": e }))),
+    };
+
+    Response::builder()
+        .status(status)
+        .header("Content-Type", "application/json")
+        .header("Access-Control-Allow-Origin", "*")
+        .body(body.unwrap_or_else(|_| b"{\"error\":\"Internal serialization error\"}".to_vec()))
+        .build()
+}
+
+/// Read an incoming request's body as it streams in and handle it. Bodies
+/// larger than `max_request_bytes` are rejected as soon as the limit is
+/// passed, without buffering the rest or forwarding any of it to
+/// components. Tool calls from clients accepting `text/event-stream` are
+/// answered with an SSE stream, so their tools may request sampling.
+/// Every response carries the CORS headers for the request's origin.
+pub async fn handle_incoming_request(req: IncomingRequest, response_out: ResponseOutparam) {
+    let origin = cors::origin(&req.headers().entries());
+    let cors_headers = Cors::from_variables().response_headers(origin.as_deref());
+    let max_bytes = numeric_variable("max_request_bytes", DEFAULT_MAX_REQUEST_BYTES);
+    match read_request(req, max_bytes).await {
+        Ok(req) => match sse_route(&req) {
+            Some(sse::Route::Stream { endpoint }) => {
+                stream_sse_session(&endpoint, response_out, &cors_headers).await;
+            }
+            Some(sse::Route::Message { endpoint, session }) => {
+                let response = handle_sse_message(req, &endpoint, &session).await;
+                send_response(response_out, response, &cors_headers).await;
+            }
+            None if sampling::wants_stream(&req) => {
+                stream_mcp_request(req, response_out, &cors_headers).await;
+            }
+            None => {
+                let response = handle_mcp_request(req, None).await;
+                send_response(response_out, response, &cors_headers).await;
+            }
+        },
+        Err(response) => send_response(response_out, response, &cors_headers).await,
+    }
+}
+
+/// Response headers with the CORS headers in place of any set by handlers
+fn with_cors_headers(
+    headers: impl Iterator<Item = (String, Vec<u8>)>,
+    cors_headers: &[(String, String)],
+) -> Vec<(String, Vec<u8>)> {
+    headers
+        .filter(|(name, _)| !name.eq_ignore_ascii_case("access-control-allow-origin"))
+        .chain(
+            cors_headers
+                .iter()
+                .map(|(name, value)| (name.clone(), value.as_bytes().to_vec())),
         )
+        .collect()
+}
+
+/// Send a complete response
+async fn send_response(
+    response_out: ResponseOutparam,
+    response: Response,
+    cors_headers: &[(String, String)],
+) {
+    let headers = with_cors_headers(
+        response
+            .headers()
+            .map(|(name, value)| (name.to_string(), value.as_bytes().to_vec())),
+        cors_headers,
+    );
+    let outgoing = OutgoingResponse::new(Fields::from_list(&headers).unwrap_or_else(|e| {
+        eprintln!("Dropped invalid response headers: {e:?}");
+        Fields::new()
+    }));
+    let _ = outgoing.set_status_code(*response.status());
+    let mut body = outgoing.take_body();
+    response_out.set(outgoing);
+    if let Err(e) = body.send(response.body().to_vec()).await {
+        eprintln!("Failed to send response: {e:?}");
+    }
+}
+
+/// Handle a tool call with an SSE stream, relaying the sampling requests
+/// of the tool to the client before the call's response
+async fn stream_mcp_request(
+    req: Request,
+    response_out: ResponseOutparam,
+    cors_headers: &[(String, String)],
+) {
+    let body = open_event_stream(response_out, cors_headers);
+    futures::pin_mut!(body);
+
+    // The relay sends events until the call is handled and the gateway,
+    // which owns the relay, is dropped
+    let (events, relayed) = futures::channel::mpsc::unbounded();
+    let relay = SamplingRelay::open(sampling::new_call_id(), events);
+    let (response, forwarded) = futures::future::join(
+        handle_mcp_request(req, Some(relay)),
+        relayed.map(Ok).forward(body.as_mut()),
+    )
+    .await;
+    if let Err(e) = forwarded {
+        eprintln!("Failed to relay sampling requests: {e:?}");
+    }
+    if let Err(e) = body.send(sampling::sse_event(response.body())).await {
+        eprintln!("Failed to send response: {e:?}");
+    }
+}
+
+/// Start a `200` response with an event stream body
+fn open_event_stream(
+    response_out: ResponseOutparam,
+    cors_headers: &[(String, String)],
+) -> impl futures::Sink<Vec<u8>, Error = impl std::fmt::Debug> {
+    let headers = with_cors_headers(
+        [
+            ("content-type".to_string(), b"text/event-stream".to_vec()),
+            ("cache-control".to_string(), b"no-cache".to_vec()),
+        ]
+        .into_iter(),
+        cors_headers,
+    );
+    let outgoing =
+        OutgoingResponse::new(Fields::from_list(&headers).unwrap_or_else(|_| Fields::new()));
+    let _ = outgoing.set_status_code(200);
+    let body = outgoing.take_body();
+    response_out.set(outgoing);
+    body
+}
+
+/// Whether the HTTP+SSE transport is enabled
+fn sse_enabled() -> bool {
+    variables::get("sse_transport")
+        .ok()
+        .and_then(|value| value.trim().parse::<bool>().ok())
+        .unwrap_or(false)
+}
+
+/// The HTTP+SSE transport request `req` is, if the transport is enabled
+fn sse_route(req: &Request) -> Option<sse::Route> {
+    if !sse_enabled() {
+        return None;
+    }
+    sse::route(req, |endpoint| ToolScope::from_path(endpoint).is_ok())
+}
+
+/// Open an HTTP+SSE session and stream the responses to its messages
+async fn stream_sse_session(
+    endpoint: &str,
+    response_out: ResponseOutparam,
+    cors_headers: &[(String, String)],
+) {
+    let Some(session) = sse::Session::create() else {
+        let response = Response::builder()
+            .status(503)
+            .body(b"SSE sessions are unavailable".to_vec())
+            .build();
+        send_response(response_out, response, cors_headers).await;
+        return;
+    };
+    let body = open_event_stream(response_out, cors_headers);
+    futures::pin_mut!(body);
+    if let Err(e) = body.send(sse::endpoint_event(endpoint, session.id())).await {
+        eprintln!("Failed to open SSE session: {e:?}");
+        return;
+    }
+    let lifetime = Duration::from_secs(numeric_variable(
+        "sse_session_seconds",
+        sse::DEFAULT_SESSION_SECONDS,
+    ));
+    session.stream(body.as_mut(), lifetime).await;
+}
+
+/// Handle a message posted to an HTTP+SSE session like a request to its
+/// endpoint, queueing the response for the session's stream
+async fn handle_sse_message(req: Request, endpoint: &str, session: &str) -> Response {
+    let Some(session) = sse::Session::open(session) else {
+        return Response::builder()
+            .status(404)
+            .header("Content-Type", "application/json")
+            .body(br#"{"error":"Unknown or expired SSE session"}"#.to_vec())
+            .build();
+    };
+
+    let mut builder = Request::builder();
+    builder
+        .method(Method::Post)
+        .uri(if endpoint.is_empty() { "/" } else { endpoint })
+        .body(req.body().to_vec());
+    for (name, value) in req.headers() {
+        builder.header(name, String::from_utf8_lossy(value.as_bytes()).as_ref());
+    }
+    let response = handle_mcp_request(builder.build(), None).await;
+    if *response.status() != 200 {
+        return response;
+    }
+    if !response.body().is_empty() {
+        session.push(response.body());
+    }
+    Response::builder().status(202).build()
+}
+
+/// Buffer an incoming request, up to `max_bytes` of body (0 for no limit)
+async fn read_request(req: IncomingRequest, max_bytes: usize) -> Result<Request, Response> {
+    let too_large = |len: usize| max_bytes > 0 && len > max_bytes;
+    let headers = req.headers().entries();
+    let content_length = headers
+        .iter()
+        .find(|(name, _)| name.eq_ignore_ascii_case("content-length"))
+        .and_then(|(_, value)| std::str::from_utf8(value).ok())
+        .and_then(|value| value.trim().parse::<usize>().ok());
+    if content_length.is_some_and(too_large) {
+        return Err(payload_too_large_response(max_bytes));
+    }
+
+    let method = req.method();
+    let uri = req.uri();
+    let mut body = Vec::new();
+    let mut stream = req.into_body_stream();
+    while let Some(chunk) = stream.next().await {
+        let chunk = chunk.map_err(|e| {
+            Response::builder()
+                .status(400)
+                .header("Access-Control-Allow-Origin", "*")
+                .body(format!("Failed to read request body: {e:?}").into_bytes())
+                .build()
+        })?;
+        if too_large(body.len() + chunk.len()) {
+            return Err(payload_too_large_response(max_bytes));
+        }
+        body.extend_from_slice(&chunk);
+    }
+
+    let mut builder = Request::builder();
+    builder.method(method).uri(uri).body(body);
+    for (name, value) in &headers {
+        builder.header(name.as_str(), String::from_utf8_lossy(value).as_ref());
     }
+    Ok(builder.build())
+}
+
+/// The `413` response to a request body over the limit, as a JSON-RPC
+/// error whose data names the limit
+fn payload_too_large_response(max_bytes: usize) -> Response {
+    let mut response = JsonRpcResponse::error(
+        None,
+        ErrorCode::INVALID_REQUEST.0,
+        &format!("Request body exceeds the limit of {max_bytes} bytes"),
+    );
+    if let JsonRpcResult::Error { error } = &mut response.result {
+        error.data = Some(serde_json::json!({
+            "type": PAYLOAD_TOO_LARGE,
+            "max_bytes": max_bytes,
+        }));
+    }
+    Response::builder()
+        .status(413)
+        .header("Content-Type", "application/json")
+        .header("Access-Control-Allow-Origin", "*")
+        .body(serde_json::to_vec(&response).unwrap_or_else(|_| {
+            br#"{"jsonrpc":"2.0","error":{"code":-32600,"message":"Request body too large"}}"#
+                .to_vec()
+        }))
+        .build()
 }
 
 #[allow(clippy::too_many_lines)] // This function handles the entire MCP request flow
-pub async fn handle_mcp_request(req: Request) -> Response {
+pub async fn handle_mcp_request(req: Request, relay: Option<SamplingRelay>) -> Response {
     // Handle CORS preflight first
     if *req.method() == Method::Options {
-        return Response::builder()
-            .status(200)
-            .header("Access-Control-Allow-Origin", "*")
-            .header("Access-Control-Allow-Methods", "POST, OPTIONS")
-            .header(
-                "Access-Control-Allow-Headers",
-                "Content-Type, X-MCP-Toolsets, X-MCP-Readonly",
-            )
-            .build();
+        let methods = if sse_enabled() {
+            "GET, POST, OPTIONS"
+        } else {
+            "POST, OPTIONS"
+        };
+        return Cors::from_variables().preflight(methods);
+    }
+
+    // Sampling requests of tools, and the gateway's long-polls for them
+    let sampling_timeout = Duration::from_secs(numeric_variable(
+        "sampling_timeout_seconds",
+        sampling::DEFAULT_TIMEOUT_SECONDS,
+    ));
+    if let Some(response) = sampling::handle_request(&req, sampling_timeout) {
+        return response;
+    }
+
+    // Component health and metrics for monitors; the only other non-POST
+    // routes
+    if *req.method() == Method::Get {
+        match req.path().trim_end_matches('/') {
+            "/health" => return handle_health_request().await,
+            metrics::METRICS_PATH => return handle_metrics_request(),
+            _ => {}
+        }
     }
 
     // Only accept POST requests for MCP operations
@@ -607,9 +1922,54 @@ pub async fn handle_mcp_request(req: Request) -> Response {
 
     // Parse headers to augment scope
     let mut allowed_toolsets: Option<Vec<String>> = None;
+    let mut traceparent: Option<&str> = None;
+    let mut tracestate: Option<&str> = None;
+    let mut authorization: Option<&str> = None;
+    let mut user_agent: Option<String> = None;
+    let mut call_depth: u32 = 0;
+    let mut sampling_call: Option<String> = None;
+    let mut request_ids: [Option<String>; 2] = [None, None];
+    let mut auth_identity: [Option<String>; 4] = [None, None, None, None];
 
     for (name, value) in req.headers() {
-        if name.eq_ignore_ascii_case("x-mcp-toolsets") {
+        if name.eq_ignore_ascii_case(trace::TRACEPARENT_HEADER) {
+            traceparent = std::str::from_utf8(value.as_bytes()).ok();
+        } else if name.eq_ignore_ascii_case(trace::TRACESTATE_HEADER) {
+            tracestate = std::str::from_utf8(value.as_bytes()).ok();
+        } else if name.eq_ignore_ascii_case("authorization") {
+            authorization = std::str::from_utf8(value.as_bytes()).ok();
+        } else if name.eq_ignore_ascii_case("user-agent") {
+            user_agent = std::str::from_utf8(value.as_bytes())
+                .ok()
+                .map(str::to_string);
+        } else if name.eq_ignore_ascii_case(CALL_DEPTH_HEADER) {
+            call_depth = std::str::from_utf8(value.as_bytes())
+                .ok()
+                .and_then(|depth| depth.trim().parse().ok())
+                .unwrap_or(0);
+        } else if name.eq_ignore_ascii_case(SAMPLING_CALL_HEADER) {
+            sampling_call = std::str::from_utf8(value.as_bytes())
+                .ok()
+                .map(str::to_string);
+        } else if let Some(i) = CLIENT_REQUEST_ID_HEADERS
+            .iter()
+            .position(|header| name.eq_ignore_ascii_case(header))
+        {
+            request_ids[i] = std::str::from_utf8(value.as_bytes())
+                .ok()
+                .map(str::trim)
+                .filter(|id| !id.is_empty())
+                .map(str::to_string);
+        } else if let Some(i) = AUTH_IDENTITY_HEADERS
+            .iter()
+            .position(|header| name.eq_ignore_ascii_case(header))
+        {
+            // The authorizer appends its headers after the client's, so
+            // the last value wins
+            auth_identity[i] = std::str::from_utf8(value.as_bytes())
+                .ok()
+                .map(str::to_string);
+        } else if name.eq_ignore_ascii_case("x-mcp-toolsets") {
             if let Ok(toolsets_str) = std::str::from_utf8(value.as_bytes()) {
                 // Parse comma-separated list of allowed toolsets/components
                 allowed_toolsets = Some(
@@ -636,6 +1996,14 @@ pub async fn handle_mcp_request(req: Request) -> Response {
         }
     }
 
+    // Clients answer relayed sampling requests with a JSON-RPC response
+    if sampling::accept_reply(req.body()) {
+        return Response::builder()
+            .status(202)
+            .header("Access-Control-Allow-Origin", "*")
+            .build();
+    }
+
     // Parse JSON-RPC request
     let request: JsonRpcRequest = match serde_json::from_slice::<JsonRpcRequest>(req.body()) {
         Ok(r) => {
@@ -674,24 +2042,56 @@ pub async fn handle_mcp_request(req: Request) -> Response {
         }
     };
 
-    // Create gateway with config
-    let validate_arguments = variables::get("validate_arguments")
-        .unwrap_or_else(|_| "true".to_string())
-        .parse::<bool>()
-        .unwrap_or(true);
+    // Join the caller's trace; component calls become children of this span
+    let mut server_span = Span::start(
+        &TraceContext::from_headers(traceparent, tracestate),
+        format!("mcp {}", request.method),
+        SpanKind::Server,
+    );
+    server_span.set_attribute("mcp.method", request.method.clone());
 
-    let config = GatewayConfig {
-        server_info: ServerInfo {
-            name: "mcp-gateway".to_string(),
-            version: "0.0.1".to_string(),
-        },
-        validate_arguments,
+    let config = gateway_config();
+    let metrics_enabled = config.metrics_enabled;
+    // Calls from other tools come from components rather than the
+    // authorizer, so their tokens are not verified
+    let trust_identity = config.trust_auth_claims && call_depth == 0;
+    let claims = authorization
+        .filter(|_| trust_identity)
+        .and_then(transform::claims_from_authorization);
+    let identity = if trust_identity {
+        identity_headers(auth_identity, claims.as_ref())
+    } else {
+        Vec::new()
+    };
+    let method = request.method.clone();
+    let gateway = McpGateway::new(config, scope, allowed_toolsets)
+        .with_trace(server_span.context())
+        .with_claims(claims)
+        .with_identity(identity)
+        .with_user_agent(user_agent)
+        .with_call_depth(call_depth)
+        .with_request_id(request_ids.into_iter().flatten().next())
+        .with_sampling(relay)
+        .with_sampling_call(sampling_call.filter(|_| call_depth > 0));
+
+    // Handle the request once the app's middleware has passed it on
+    let response = match gateway.run_middleware(request).await {
+        Ok(request) => gateway.handle_request(request).await,
+        Err(rejection) => Some(rejection),
     };
 
-    let gateway = McpGateway::new(config, scope, allowed_toolsets);
+    let error = match response.as_ref().map(|r| &r.result) {
+        Some(JsonRpcResult::Error { error }) => Some(error.message.as_str()),
+        _ => None,
+    };
+    if metrics_enabled {
+        update_metrics(&method, error.is_some(), &gateway.take_tool_calls());
+    }
+    let mut spans = gateway.take_spans();
+    spans.push(server_span.finish(error));
+    export_spans(&spans).await;
 
-    // Handle the request
-    gateway.handle_request(request).await.map_or_else(
+    response.map_or_else(
         || {
             // Notification - return empty response
             Response::builder()
@@ -0,0 +1,194 @@
+//! Component health and circuit breaking
+//!
+//! Each component has a circuit kept in the key-value store so it survives
+//! across requests. Failed calls and health checks count against the
+//! circuit; once `failure_threshold` consecutive failures are recorded it
+//! opens and the component is left out of routing. After `cooldown_secs` it
+//! is half-open: the next request is let through as a trial, and its
+//! outcome closes the circuit or opens it again.
+
+use serde::{Deserialize, Serialize};
+
+/// Endpoint the FTL SDKs answer so the gateway can check a component is up
+pub const HEALTH_PATH: &str = "/.well-known/ftl/health";
+
+/// Circuit breaker settings
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub struct BreakerConfig {
+    /// Consecutive failures that open a circuit; 0 disables circuit breaking
+    pub failure_threshold: u32,
+    /// Seconds an open circuit waits before letting a trial request through
+    pub cooldown_secs: u64,
+}
+
+impl Default for BreakerConfig {
+    fn default() -> Self {
+        Self {
+            failure_threshold: 3,
+            cooldown_secs: 30,
+        }
+    }
+}
+
+impl BreakerConfig {
+    pub const fn enabled(&self) -> bool {
+        self.failure_threshold > 0
+    }
+}
+
+/// Whether requests are routed to a component
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize)]
+#[serde(rename_all = "snake_case")]
+pub enum CircuitStatus {
+    /// Healthy; requests are routed normally
+    Closed,
+    /// Unhealthy; the component is removed from routing
+    Open,
+    /// Cooling down is over; the next request is a trial
+    HalfOpen,
+}
+
+/// Persisted state of a component's circuit
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize)]
+pub struct CircuitState {
+    pub consecutive_failures: u32,
+    /// Unix time in seconds at which the circuit opened
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub opened_at: Option<u64>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub last_error: Option<String>,
+}
+
+impl CircuitState {
+    pub fn status(&self, config: &BreakerConfig, now: u64) -> CircuitStatus {
+        match self.opened_at {
+            _ if !config.enabled() => CircuitStatus::Closed,
+            None => CircuitStatus::Closed,
+            Some(opened_at) if now.saturating_sub(opened_at) >= config.cooldown_secs => {
+                CircuitStatus::HalfOpen
+            }
+            Some(_) => CircuitStatus::Open,
+        }
+    }
+
+    /// Whether a request may be routed to the component
+    pub fn allows(&self, config: &BreakerConfig, now: u64) -> bool {
+        self.status(config, now) != CircuitStatus::Open
+    }
+
+    /// Record a successful request, closing the circuit
+    pub fn record_success(&mut self) {
+        *self = Self::default();
+    }
+
+    /// Record a failed request. Opens the circuit once the threshold is
+    /// reached, or again straight away when a half-open trial fails.
+    pub fn record_failure(&mut self, config: &BreakerConfig, now: u64, error: &str) {
+        let was_half_open = self.status(config, now) == CircuitStatus::HalfOpen;
+        self.consecutive_failures = self.consecutive_failures.saturating_add(1);
+        self.last_error = Some(error.to_string());
+        if config.enabled()
+            && (was_half_open || self.consecutive_failures >= config.failure_threshold)
+        {
+            self.opened_at = Some(now);
+        }
+    }
+}
+
+/// Health of one component as reported by the `/health` route
+#[derive(Debug, Clone, Serialize)]
+pub struct ComponentHealth {
+    pub component: String,
+    pub healthy: bool,
+    pub circuit: CircuitStatus,
+    pub consecutive_failures: u32,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub error: Option<String>,
+}
+
+/// Body of the `/health` route
+#[derive(Debug, Clone, Serialize)]
+pub struct HealthReport {
+    pub healthy: bool,
+    pub components: Vec<ComponentHealth>,
+}
+
+impl HealthReport {
+    pub fn new(components: Vec<ComponentHealth>) -> Self {
+        Self {
+            healthy: components.iter().all(|c| c.healthy),
+            components,
+        }
+    }
+}
+
+/// Key under which a component's circuit is stored
+pub fn circuit_key(component: &str) -> String {
+    format!("gateway:circuit:{component}")
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    const CONFIG: BreakerConfig = BreakerConfig {
+        failure_threshold: 2,
+        cooldown_secs: 30,
+    };
+
+    #[test]
+    fn circuit_opens_after_threshold() {
+        let mut state = CircuitState::default();
+        state.record_failure(&CONFIG, 100, "connection refused");
+        assert_eq!(state.status(&CONFIG, 100), CircuitStatus::Closed);
+
+        state.record_failure(&CONFIG, 101, "connection refused");
+        assert_eq!(state.status(&CONFIG, 101), CircuitStatus::Open);
+        assert!(!state.allows(&CONFIG, 120));
+        assert_eq!(state.last_error.as_deref(), Some("connection refused"));
+    }
+
+    #[test]
+    fn half_open_trial_closes_or_reopens() {
+        let mut state = CircuitState {
+            consecutive_failures: 2,
+            opened_at: Some(100),
+            last_error: None,
+        };
+        assert_eq!(state.status(&CONFIG, 130), CircuitStatus::HalfOpen);
+        assert!(state.allows(&CONFIG, 130));
+
+        let mut failed = state.clone();
+        failed.record_failure(&CONFIG, 130, "timeout");
+        assert_eq!(failed.status(&CONFIG, 131), CircuitStatus::Open);
+
+        state.record_success();
+        assert_eq!(state, CircuitState::default());
+    }
+
+    #[test]
+    fn disabled_breaker_never_opens() {
+        let config = BreakerConfig {
+            failure_threshold: 0,
+            cooldown_secs: 30,
+        };
+        let mut state = CircuitState::default();
+        for now in 0..10 {
+            state.record_failure(&config, now, "down");
+        }
+        assert!(state.allows(&config, 10));
+    }
+
+    #[test]
+    fn report_is_healthy_only_when_all_components_are() {
+        let component = |healthy| ComponentHealth {
+            component: "echo".to_string(),
+            healthy,
+            circuit: CircuitStatus::Closed,
+            consecutive_failures: 0,
+            error: None,
+        };
+        assert!(HealthReport::new(vec![component(true)]).healthy);
+        assert!(!HealthReport::new(vec![component(true), component(false)]).healthy);
+    }
+}
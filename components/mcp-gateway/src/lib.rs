@@ -1,10 +1,22 @@
+mod concurrency;
+mod cors;
+mod discovery;
 mod gateway;
+mod health;
 mod mcp_types;
+mod metrics;
+mod middleware;
+mod resources;
+mod route;
+mod sampling;
+mod sse;
+mod trace;
+mod transform;
 
-use spin_sdk::http::{IntoResponse, Request};
+use spin_sdk::http::{IncomingRequest, ResponseOutparam};
 use spin_sdk::http_component;
 
 #[http_component]
-async fn handle_mcp_gateway(req: Request) -> anyhow::Result<impl IntoResponse> {
-    Ok(gateway::handle_mcp_request(req).await)
+async fn handle_mcp_gateway(req: IncomingRequest, response_out: ResponseOutparam) {
+    gateway::handle_incoming_request(req, response_out).await;
 }
@@ -0,0 +1,326 @@
+//! Prometheus metrics for the gateway.
+//!
+//! Spin creates a fresh component instance for every request, so metric
+//! values are accumulated in the key-value store and rendered in the
+//! Prometheus text exposition format by `GET /metrics`. Every counter,
+//! histogram bucket and histogram sum has its own key under `metrics:`, so
+//! a request only rewrites the values it adds to. Concurrent requests
+//! update them without locking, so counts are approximate under heavy load.
+
+use std::collections::BTreeMap;
+use std::fmt::Write as _;
+
+pub const METRICS_PATH: &str = "/metrics";
+
+/// Prefix of every metric key in the key-value store. The MCP authorizer
+/// counts authentication failures under it too, e.g.
+/// `metrics:auth_failures:expired_token`.
+pub const PREFIX: &str = "metrics:";
+
+/// Content type of the Prometheus text exposition format
+pub const CONTENT_TYPE: &str = "text/plain; version=0.0.4; charset=utf-8";
+
+/// Upper bounds of the tool call duration buckets, in seconds
+const DURATION_BUCKETS: [f64; 11] = [
+    0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0,
+];
+
+/// Upper bounds of the payload size buckets, in bytes
+const SIZE_BUCKETS: [f64; 7] = [64.0, 256.0, 1_024.0, 4_096.0, 16_384.0, 65_536.0, 262_144.0];
+
+/// How the suffix of a counter's keys maps to labels
+#[derive(Debug, Clone, Copy)]
+enum Labels {
+    /// `<method>`
+    Method,
+    /// `<component>:<tool>`
+    Tool,
+    /// `<reason>`
+    Reason,
+}
+
+/// Counters as key name, metric name, help and labels. Tool calls also
+/// serve as the count of every tool histogram.
+const COUNTERS: [(&str, &str, &str, Labels); 5] = [
+    (
+        "requests",
+        "ftl_gateway_requests_total",
+        "MCP requests handled by the gateway, by JSON-RPC method.",
+        Labels::Method,
+    ),
+    (
+        "request_errors",
+        "ftl_gateway_request_errors_total",
+        "MCP requests answered with a JSON-RPC error, by method.",
+        Labels::Method,
+    ),
+    (
+        "tool_calls",
+        "ftl_gateway_tool_calls_total",
+        "Tool invocations forwarded to components.",
+        Labels::Tool,
+    ),
+    (
+        "tool_errors",
+        "ftl_gateway_tool_errors_total",
+        "Tool invocations that failed or returned a non-200 status.",
+        Labels::Tool,
+    ),
+    (
+        "auth_failures",
+        "ftl_auth_failures_total",
+        "Requests rejected by the MCP authorizer, by reason.",
+        Labels::Reason,
+    ),
+];
+
+/// Histograms of tool calls as key name, metric name, help and bucket
+/// bounds. A histogram's keys are `<name>:<component>:<tool>:<part>`, where
+/// the part is `sum` or the index of a bucket, which counts the
+/// observations up to its bound but above the previous one.
+const HISTOGRAMS: [(&str, &str, &str, &[f64]); 3] = [
+    (
+        "tool_call_duration_seconds",
+        "ftl_gateway_tool_call_duration_seconds",
+        "Time taken by tool invocations.",
+        &DURATION_BUCKETS,
+    ),
+    (
+        "tool_request_bytes",
+        "ftl_gateway_tool_request_bytes",
+        "Size of tool call arguments sent to components.",
+        &SIZE_BUCKETS,
+    ),
+    (
+        "tool_response_bytes",
+        "ftl_gateway_tool_response_bytes",
+        "Size of tool responses returned by components.",
+        &SIZE_BUCKETS,
+    ),
+];
+
+/// A finished call from the gateway to a tool
+#[derive(Debug, Clone, PartialEq)]
+pub struct ToolCall {
+    pub component: String,
+    pub tool: String,
+    pub error: bool,
+    pub duration_seconds: f64,
+    pub request_bytes: usize,
+    pub response_bytes: usize,
+}
+
+/// The amounts a handled request and its tool calls add to stored metrics,
+/// by key
+#[allow(clippy::cast_precision_loss)] // payload sizes fit comfortably in f64
+pub fn increments(method: &str, error: bool, tool_calls: &[ToolCall]) -> BTreeMap<String, f64> {
+    let mut increments = BTreeMap::new();
+    let mut add = |key: String, amount: f64| *increments.entry(key).or_default() += amount;
+
+    add(format!("{PREFIX}requests:{method}"), 1.0);
+    if error {
+        add(format!("{PREFIX}request_errors:{method}"), 1.0);
+    }
+    for call in tool_calls {
+        let tool = format!("{}:{}", call.component, call.tool);
+        add(format!("{PREFIX}tool_calls:{tool}"), 1.0);
+        if call.error {
+            add(format!("{PREFIX}tool_errors:{tool}"), 1.0);
+        }
+        let values = [
+            call.duration_seconds,
+            call.request_bytes as f64,
+            call.response_bytes as f64,
+        ];
+        for ((name, _, _, bounds), value) in HISTOGRAMS.iter().zip(values) {
+            let bucket = bounds
+                .iter()
+                .position(|bound| value <= *bound)
+                .unwrap_or(bounds.len());
+            add(format!("{PREFIX}{name}:{tool}:{bucket}"), 1.0);
+            add(format!("{PREFIX}{name}:{tool}:sum"), value);
+        }
+    }
+    increments
+}
+
+/// Render stored metric values by key in the Prometheus text format,
+/// including the authorizer's authentication failure counts
+pub fn render(values: &BTreeMap<String, f64>) -> String {
+    let mut out = String::new();
+
+    for (key, name, help, labels) in COUNTERS {
+        header(&mut out, name, "counter", help);
+        for (suffix, value) in with_prefix(values, key) {
+            let _ = writeln!(out, "{name}{{{}}} {value}", label_text(labels, suffix));
+        }
+    }
+
+    for (key, name, help, bounds) in HISTOGRAMS {
+        header(&mut out, name, "histogram", help);
+        for (tool, count) in with_prefix(values, "tool_calls") {
+            let labels = label_text(Labels::Tool, tool);
+            let part = |part: &str| {
+                values
+                    .get(&format!("{PREFIX}{key}:{tool}:{part}"))
+                    .copied()
+                    .unwrap_or(0.0)
+            };
+            let mut cumulative = 0.0;
+            for (i, bound) in bounds.iter().enumerate() {
+                cumulative += part(&i.to_string());
+                let _ = writeln!(out, "{name}_bucket{{{labels},le=\"{bound}\"}} {cumulative}");
+            }
+            let _ = writeln!(out, "{name}_bucket{{{labels},le=\"+Inf\"}} {count}");
+            let _ = writeln!(out, "{name}_sum{{{labels}}} {}", part("sum"));
+            let _ = writeln!(out, "{name}_count{{{labels}}} {count}");
+        }
+    }
+
+    out
+}
+
+/// Stored values whose key starts with `metrics:<name>:`, by the rest of
+/// the key
+fn with_prefix<'a>(
+    values: &'a BTreeMap<String, f64>,
+    name: &str,
+) -> impl Iterator<Item = (&'a str, f64)> {
+    let prefix = format!("{PREFIX}{name}:");
+    values
+        .iter()
+        .filter_map(move |(key, value)| Some((key.strip_prefix(&prefix)?, *value)))
+}
+
+fn header(out: &mut String, name: &str, kind: &str, help: &str) {
+    let _ = writeln!(out, "# HELP {name} {help}");
+    let _ = writeln!(out, "# TYPE {name} {kind}");
+}
+
+fn label_text(labels: Labels, suffix: &str) -> String {
+    match labels {
+        Labels::Method => format!("method=\"{}\"", escape(suffix)),
+        Labels::Reason => format!("reason=\"{}\"", escape(suffix)),
+        Labels::Tool => {
+            // Component IDs never contain a colon, tool names may
+            let (component, tool) = suffix.split_once(':').unwrap_or((suffix, ""));
+            format!(
+                "component=\"{}\",tool=\"{}\"",
+                escape(component),
+                escape(tool)
+            )
+        }
+    }
+}
+
+/// Escape a label value for the text exposition format
+fn escape(value: &str) -> String {
+    value
+        .replace('\\', "\\\\")
+        .replace('"', "\\\"")
+        .replace('\n', "\\n")
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn call(tool: &str, error: bool, duration_seconds: f64) -> ToolCall {
+        ToolCall {
+            component: "weather".to_string(),
+            tool: tool.to_string(),
+            error,
+            duration_seconds,
+            request_bytes: 100,
+            response_bytes: 2_000,
+        }
+    }
+
+    /// Add the increments of a request to stored values, as the gateway does
+    fn record(values: &mut BTreeMap<String, f64>, method: &str, error: bool, calls: &[ToolCall]) {
+        for (key, amount) in increments(method, error, calls) {
+            *values.entry(key).or_default() += amount;
+        }
+    }
+
+    #[test]
+    fn increments_one_key_per_counter() {
+        let increments = increments(
+            "tools/call",
+            true,
+            &[call("forecast", false, 0.02), call("forecast", true, 3.0)],
+        );
+
+        assert_eq!(increments.get("metrics:requests:tools/call"), Some(&1.0));
+        assert_eq!(
+            increments.get("metrics:request_errors:tools/call"),
+            Some(&1.0)
+        );
+        assert_eq!(
+            increments.get("metrics:tool_calls:weather:forecast"),
+            Some(&2.0)
+        );
+        assert_eq!(
+            increments.get("metrics:tool_errors:weather:forecast"),
+            Some(&1.0)
+        );
+        assert_eq!(
+            increments.get("metrics:tool_call_duration_seconds:weather:forecast:2"),
+            Some(&1.0)
+        );
+        assert_eq!(
+            increments.get("metrics:tool_call_duration_seconds:weather:forecast:9"),
+            Some(&1.0)
+        );
+        assert_eq!(
+            increments.get("metrics:tool_request_bytes:weather:forecast:sum"),
+            Some(&200.0)
+        );
+        assert!(increments.keys().all(|key| key.starts_with(PREFIX)));
+    }
+
+    #[test]
+    fn renders_prometheus_text() {
+        let mut values = BTreeMap::new();
+        record(
+            &mut values,
+            "tools/call",
+            false,
+            &[call("forecast", false, 0.02)],
+        );
+        record(&mut values, "tools/call", true, &[]);
+        values.insert("metrics:auth_failures:expired_token".to_string(), 4.0);
+
+        let text = render(&values);
+        for line in [
+            "# TYPE ftl_gateway_requests_total counter",
+            "ftl_gateway_requests_total{method=\"tools/call\"} 2",
+            "ftl_gateway_request_errors_total{method=\"tools/call\"} 1",
+            "ftl_gateway_tool_calls_total{component=\"weather\",tool=\"forecast\"} 1",
+            "# TYPE ftl_gateway_tool_call_duration_seconds histogram",
+            "ftl_gateway_tool_call_duration_seconds_bucket{component=\"weather\",tool=\"forecast\",le=\"0.01\"} 0",
+            "ftl_gateway_tool_call_duration_seconds_bucket{component=\"weather\",tool=\"forecast\",le=\"0.025\"} 1",
+            "ftl_gateway_tool_call_duration_seconds_bucket{component=\"weather\",tool=\"forecast\",le=\"+Inf\"} 1",
+            "ftl_gateway_tool_call_duration_seconds_count{component=\"weather\",tool=\"forecast\"} 1",
+            "ftl_gateway_tool_request_bytes_sum{component=\"weather\",tool=\"forecast\"} 100",
+            "ftl_gateway_tool_response_bytes_bucket{component=\"weather\",tool=\"forecast\",le=\"4096\"} 1",
+            "ftl_auth_failures_total{reason=\"expired_token\"} 4",
+        ] {
+            assert!(text.lines().any(|l| l == line), "missing {line}\n{text}");
+        }
+        assert!(!text.contains("ftl_gateway_tool_errors_total{"));
+    }
+
+    #[test]
+    fn escapes_label_values() {
+        assert_eq!(escape("a\"b\\c\nd"), "a\\\"b\\\\c\\nd");
+    }
+
+    #[test]
+    fn splits_tool_labels_at_the_first_colon() {
+        assert_eq!(
+            label_text(Labels::Tool, "weather:ns:forecast"),
+            "component=\"weather\",tool=\"ns:forecast\""
+        );
+    }
+}
@@ -0,0 +1,160 @@
+//! App-level middleware
+//!
+//! Apps can declare middleware components that see every MCP request
+//! before the gateway handles it, for cross-cutting concerns such as rate
+//! limiting, request logging or filtering. The gateway calls them in the
+//! order of the `middleware_names` variable, posting the JSON-RPC request
+//! to `http://<name>.spin.internal/` with the caller's identity headers.
+//! Each middleware answers with:
+//!
+//! - `204`, or `200` with an empty body, to pass the request on unchanged
+//! - `200` with a JSON-RPC request, to pass that request on instead. The
+//!   request ID cannot be changed.
+//! - any other status to reject the request. A JSON body with a `message`
+//!   string, or a text body, becomes the error message.
+//!
+//! A middleware that cannot be reached rejects the request, so a failing
+//! filter never lets requests through. Tool calls made by other tools
+//! through the gateway have already passed the middleware and skip it.
+
+use serde_json::Value;
+
+use crate::mcp_types::JsonRpcRequest;
+
+/// Error type reported when a middleware rejects a request
+pub const MIDDLEWARE_REJECTED: &str = "middleware_rejected";
+
+/// A request rejected by a middleware
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct Rejection {
+    pub middleware: String,
+    pub status: u16,
+    pub message: String,
+}
+
+/// Parse the comma-separated `middleware_names` variable, keeping its order
+pub fn parse_names(value: &str) -> Vec<String> {
+    value
+        .split(',')
+        .map(str::trim)
+        .filter(|name| !name.is_empty())
+        .map(str::to_string)
+        .collect()
+}
+
+/// Apply a middleware's response to the request it was given
+pub fn apply(
+    middleware: &str,
+    status: u16,
+    body: &[u8],
+    request: JsonRpcRequest,
+) -> Result<JsonRpcRequest, Rejection> {
+    let reject = |message: String| Rejection {
+        middleware: middleware.to_string(),
+        status,
+        message,
+    };
+
+    match status {
+        204 => Ok(request),
+        200 if body.iter().all(u8::is_ascii_whitespace) => Ok(request),
+        200 => match serde_json::from_slice::<JsonRpcRequest>(body) {
+            Ok(replaced) => Ok(JsonRpcRequest {
+                jsonrpc: request.jsonrpc,
+                id: request.id,
+                method: replaced.method,
+                params: replaced.params,
+            }),
+            Err(e) => Err(reject(format!(
+                "Middleware '{middleware}' returned an invalid request: {e}"
+            ))),
+        },
+        _ => Err(reject(rejection_message(middleware, body))),
+    }
+}
+
+/// The message of a rejection: the `message` of a JSON body, the text of
+/// a plain body, or a generic message
+fn rejection_message(middleware: &str, body: &[u8]) -> String {
+    if let Ok(Value::Object(object)) = serde_json::from_slice::<Value>(body)
+        && let Some(Value::String(message)) = object.get("message")
+    {
+        return message.clone();
+    }
+    let text = String::from_utf8_lossy(body);
+    let text = text.trim();
+    if text.is_empty() {
+        format!("Request rejected by middleware '{middleware}'")
+    } else {
+        text.to_string()
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use serde_json::json;
+
+    fn request() -> JsonRpcRequest {
+        JsonRpcRequest {
+            jsonrpc: "2.0".to_string(),
+            id: Some(json!(7)),
+            method: "tools/call".to_string(),
+            params: Some(json!({"name": "echo", "arguments": {"message": "hi"}})),
+        }
+    }
+
+    #[test]
+    fn parse_names_keeps_order() {
+        assert_eq!(
+            parse_names(" rate-limiter, waf,,logger "),
+            vec!["rate-limiter", "waf", "logger"]
+        );
+        assert!(parse_names("").is_empty());
+    }
+
+    #[test]
+    fn passes_request_on_unchanged() {
+        let passed = apply("waf", 204, b"", request()).unwrap();
+        assert_eq!(passed.params, request().params);
+
+        let passed = apply("waf", 200, b"\n", request()).unwrap();
+        assert_eq!(passed.method, "tools/call");
+    }
+
+    #[test]
+    fn replaces_request_keeping_id() {
+        let body = json!({
+            "jsonrpc": "2.0",
+            "id": 99,
+            "method": "tools/call",
+            "params": {"name": "echo", "arguments": {"message": "HI"}}
+        });
+        let replaced = apply("upper", 200, &serde_json::to_vec(&body).unwrap(), request()).unwrap();
+        assert_eq!(replaced.id, Some(json!(7)));
+        assert_eq!(replaced.params.unwrap()["arguments"]["message"], "HI");
+    }
+
+    #[test]
+    fn rejects_request() {
+        let rejection = apply(
+            "rate-limiter",
+            429,
+            br#"{"message": "Too many requests"}"#,
+            request(),
+        )
+        .unwrap_err();
+        assert_eq!(rejection.middleware, "rate-limiter");
+        assert_eq!(rejection.status, 429);
+        assert_eq!(rejection.message, "Too many requests");
+
+        let rejection = apply("waf", 403, b"blocked\n", request()).unwrap_err();
+        assert_eq!(rejection.message, "blocked");
+
+        let rejection = apply("waf", 500, b"", request()).unwrap_err();
+        assert_eq!(rejection.message, "Request rejected by middleware 'waf'");
+
+        let rejection = apply("waf", 200, b"not json", request()).unwrap_err();
+        assert!(rejection.message.contains("invalid request"));
+    }
+}
@@ -0,0 +1,172 @@
+//! Static resources
+//!
+//! Static components serve bundled files, such as markdown docs or JSON
+//! datasets, which the gateway exposes as MCP resources. The gateway finds
+//! them in the comma-separated `resource_components` variable, lists a
+//! component's files with `GET http://<name>.spin.internal/` and reads one
+//! with `GET http://<name>.spin.internal/<path>`, percent-encoding each
+//! segment of the path.
+//!
+//! Resources are identified by `ftl://<component>/<path>` URIs.
+
+use std::fmt::Write as _;
+
+use base64::Engine;
+use base64::engine::general_purpose::STANDARD;
+use serde::{Deserialize, Serialize};
+use serde_json::Value;
+
+/// Scheme of the URIs of static resources
+pub const URI_SCHEME: &str = "ftl://";
+
+/// Error code for a resource that does not exist, as defined by MCP
+pub const RESOURCE_NOT_FOUND: i32 = -32002;
+
+/// A file as listed by a static component
+#[derive(Debug, Clone, PartialEq, Eq, Deserialize)]
+pub struct StaticFile {
+    pub path: String,
+    #[serde(rename = "mimeType", default)]
+    pub mime_type: Option<String>,
+    #[serde(default)]
+    pub size: Option<u64>,
+}
+
+/// A resource in a `resources/list` response
+#[derive(Debug, Clone, PartialEq, Eq, Serialize)]
+pub struct Resource {
+    pub uri: String,
+    pub name: String,
+    #[serde(rename = "mimeType", skip_serializing_if = "Option::is_none")]
+    pub mime_type: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub size: Option<u64>,
+}
+
+impl Resource {
+    /// The resource for a file of a static component
+    pub fn from_file(component: &str, file: StaticFile) -> Self {
+        Self {
+            uri: uri(component, &file.path),
+            name: file.path,
+            mime_type: file.mime_type,
+            size: file.size,
+        }
+    }
+}
+
+/// The URI of a file of a static component
+pub fn uri(component: &str, path: &str) -> String {
+    format!("{URI_SCHEME}{component}/{}", path.trim_start_matches('/'))
+}
+
+/// Split a resource URI into its component and file path
+pub fn parse_uri(uri: &str) -> Result<(String, String), String> {
+    let rest = uri
+        .strip_prefix(URI_SCHEME)
+        .ok_or_else(|| format!("Unsupported resource URI '{uri}'"))?;
+    match rest.split_once('/') {
+        Some((component, path))
+            if !component.is_empty()
+                && !path.is_empty()
+                && !path.split('/').any(|segment| segment == "..") =>
+        {
+            Ok((component.to_string(), path.to_string()))
+        }
+        _ => Err(format!("Invalid resource URI '{uri}'")),
+    }
+}
+
+/// Percent-encode each segment of a file path for a request URL, keeping
+/// the `/` separators
+pub fn encode_path(path: &str) -> String {
+    let mut encoded = String::with_capacity(path.len());
+    for byte in path.bytes() {
+        if byte.is_ascii_alphanumeric() || matches!(byte, b'/' | b'-' | b'.' | b'_' | b'~') {
+            encoded.push(char::from(byte));
+        } else {
+            let _ = write!(encoded, "%{byte:02X}");
+        }
+    }
+    encoded
+}
+
+/// Whether content of a MIME type is returned as text rather than base64
+pub fn is_text(mime_type: &str) -> bool {
+    let essence = mime_type.split(';').next().unwrap_or_default().trim();
+    essence.starts_with("text/")
+        || essence.ends_with("+json")
+        || essence.ends_with("+xml")
+        || matches!(
+            essence,
+            "application/json" | "application/xml" | "application/yaml" | "application/toml"
+        )
+}
+
+/// The `contents` entry of a `resources/read` response
+pub fn contents(uri: &str, mime_type: &str, body: &[u8]) -> Value {
+    if is_text(mime_type)
+        && let Ok(text) = std::str::from_utf8(body)
+    {
+        return serde_json::json!({"uri": uri, "mimeType": mime_type, "text": text});
+    }
+    serde_json::json!({"uri": uri, "mimeType": mime_type, "blob": STANDARD.encode(body)})
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use serde_json::json;
+
+    #[test]
+    fn builds_and_parses_uris() {
+        assert_eq!(uri("docs", "/guide/intro.md"), "ftl://docs/guide/intro.md");
+        assert_eq!(
+            parse_uri("ftl://docs/guide/intro.md").unwrap(),
+            ("docs".to_string(), "guide/intro.md".to_string())
+        );
+        assert!(parse_uri("file:///etc/passwd").is_err());
+        assert!(parse_uri("ftl://docs").is_err());
+        assert!(parse_uri("ftl://docs/").is_err());
+        assert!(parse_uri("ftl://docs/../secrets.json").is_err());
+    }
+
+    #[test]
+    fn encodes_path_segments() {
+        assert_eq!(encode_path("guide/intro.md"), "guide/intro.md");
+        assert_eq!(encode_path("my notes/café.md"), "my%20notes/caf%C3%A9.md");
+        assert_eq!(encode_path("a?b#c%d.md"), "a%3Fb%23c%25d.md");
+    }
+
+    #[test]
+    fn lists_files_as_resources() {
+        let file: StaticFile = serde_json::from_value(
+            json!({"path": "data/cities.json", "mimeType": "application/json", "size": 42}),
+        )
+        .unwrap();
+        let resource = Resource::from_file("datasets", file);
+        assert_eq!(
+            serde_json::to_value(resource).unwrap(),
+            json!({
+                "uri": "ftl://datasets/data/cities.json",
+                "name": "data/cities.json",
+                "mimeType": "application/json",
+                "size": 42
+            })
+        );
+    }
+
+    #[test]
+    fn returns_text_or_blob_contents() {
+        assert_eq!(
+            contents("ftl://docs/a.md", "text/markdown; charset=utf-8", b"# A"),
+            json!({"uri": "ftl://docs/a.md", "mimeType": "text/markdown; charset=utf-8", "text": "# A"})
+        );
+        assert_eq!(
+            contents("ftl://docs/logo.png", "image/png", &[0, 1, 2]),
+            json!({"uri": "ftl://docs/logo.png", "mimeType": "image/png", "blob": "AAEC"})
+        );
+        assert!(is_text("application/geo+json"));
+        assert!(!is_text("application/octet-stream"));
+    }
+}
@@ -0,0 +1,159 @@
+//! Per-component call policies
+//!
+//! Apps set how the gateway calls each component's tools with the
+//! `component_routes` variable, keyed by component name:
+//!
+//! ```json
+//! {
+//!   "search": { "timeout_seconds": 10, "retries": 2, "backoff_ms": 200 }
+//! }
+//! ```
+//!
+//! A call still running after `timeout_seconds` fails with an error
+//! instead of holding the client's connection until it gives up.
+//! Calls that could not reach the component are retried up to `retries`
+//! times, waiting `backoff_ms` before the first retry and twice as long
+//! before each further one. Calls that reached the component, including
+//! ones that timed out, are never retried, as the tool may have run.
+
+use std::collections::BTreeMap;
+use std::future::Future;
+use std::task::Poll;
+use std::time::Duration;
+
+use futures::future::Either;
+use serde::Deserialize;
+use spin_sdk::wit::wasi::clocks::monotonic_clock;
+
+/// Wait before the first retry when a route does not set `backoff_ms`
+pub const DEFAULT_BACKOFF_MS: u64 = 100;
+
+/// Most retries a route can ask for
+pub const MAX_RETRIES: u32 = 5;
+
+/// Call policies for each component
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(transparent)]
+pub struct RoutePolicies(BTreeMap<String, RoutePolicy>);
+
+impl RoutePolicies {
+    /// Parse policies from the JSON of the `component_routes` variable.
+    /// An empty value has no policies.
+    pub fn parse(json: &str) -> Result<Self, String> {
+        if json.trim().is_empty() {
+            return Ok(Self::default());
+        }
+        serde_json::from_str(json).map_err(|e| format!("Invalid component routes: {e}"))
+    }
+
+    /// The policy for calls of a component's tools
+    pub fn get(&self, component: &str) -> RoutePolicy {
+        self.0.get(component).cloned().unwrap_or_default()
+    }
+}
+
+/// How the gateway calls a component's tools
+#[derive(Debug, Clone, Default, PartialEq, Eq, Deserialize)]
+pub struct RoutePolicy {
+    /// Seconds a call may run; unset waits for as long as the client does
+    #[serde(default)]
+    pub timeout_seconds: Option<u64>,
+
+    /// Times a call is retried when it could not reach the component
+    #[serde(default)]
+    pub retries: u32,
+
+    /// Milliseconds before the first retry
+    #[serde(default)]
+    pub backoff_ms: Option<u64>,
+}
+
+impl RoutePolicy {
+    /// How long a call may run, if limited
+    pub fn timeout(&self) -> Option<Duration> {
+        self.timeout_seconds
+            .filter(|seconds| *seconds > 0)
+            .map(Duration::from_secs)
+    }
+
+    /// How many times a call is retried, at most `MAX_RETRIES`
+    pub fn retries(&self) -> u32 {
+        self.retries.min(MAX_RETRIES)
+    }
+
+    /// How long to wait before retry `attempt`, counting from 1
+    pub fn backoff(&self, attempt: u32) -> Duration {
+        let base = self.backoff_ms.unwrap_or(DEFAULT_BACKOFF_MS);
+        let factor = 1_u64 << attempt.saturating_sub(1).min(16);
+        Duration::from_millis(base.saturating_mul(factor))
+    }
+}
+
+/// Wait for `duration` while other futures of the request keep running
+pub async fn sleep(duration: Duration) {
+    let nanos = u64::try_from(duration.as_nanos()).unwrap_or(u64::MAX);
+    let deadline = monotonic_clock::now().saturating_add(nanos);
+    futures::future::poll_fn(|cx| {
+        if monotonic_clock::now() >= deadline {
+            return Poll::Ready(());
+        }
+        spin_executor::push_waker(
+            monotonic_clock::subscribe_instant(deadline),
+            cx.waker().clone(),
+        );
+        Poll::Pending
+    })
+    .await;
+}
+
+/// Run `future` for at most `timeout`, returning `None` when it ran out
+pub async fn with_timeout<F: Future>(future: F, timeout: Option<Duration>) -> Option<F::Output> {
+    let Some(timeout) = timeout else {
+        return Some(future.await);
+    };
+    let timer = sleep(timeout);
+    futures::pin_mut!(future, timer);
+    match futures::future::select(future, timer).await {
+        Either::Left((output, _)) => Some(output),
+        Either::Right(((), _)) => None,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn parses_policies_by_component() {
+        let policies = RoutePolicies::parse(
+            r#"{"search": {"timeout_seconds": 10, "retries": 2, "backoff_ms": 200}}"#,
+        )
+        .unwrap_or_default();
+        let search = policies.get("search");
+        assert_eq!(search.timeout(), Some(Duration::from_secs(10)));
+        assert_eq!(search.retries(), 2);
+        assert_eq!(search.backoff(1), Duration::from_millis(200));
+        assert_eq!(search.backoff(3), Duration::from_millis(800));
+
+        assert_eq!(policies.get("other"), RoutePolicy::default());
+        assert!(RoutePolicies::parse("").is_ok());
+        assert!(RoutePolicies::parse("[1]").is_err());
+    }
+
+    #[test]
+    fn defaults_do_not_limit_calls() {
+        let policy = RoutePolicy::default();
+        assert_eq!(policy.timeout(), None);
+        assert_eq!(policy.retries(), 0);
+        assert_eq!(policy.backoff(1), Duration::from_millis(DEFAULT_BACKOFF_MS));
+    }
+
+    #[test]
+    fn caps_retries() {
+        let policy = RoutePolicy {
+            retries: 50,
+            ..RoutePolicy::default()
+        };
+        assert_eq!(policy.retries(), MAX_RETRIES);
+    }
+}
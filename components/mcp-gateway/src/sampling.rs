@@ -0,0 +1,440 @@
+//! Sampling relay
+//!
+//! Tools ask the client's model for completions with MCP sampling
+//! (`sampling/createMessage`), and the user for input with elicitation
+//! (`elicitation/create`). Components cannot reach the client, so the
+//! gateway relays their requests:
+//!
+//! 1. A client calls a tool accepting `text/event-stream`. The gateway gives
+//!    the call an ID, passes it to the component in `x-ftl-sampling-call`
+//!    and answers with an SSE stream.
+//! 2. The tool posts its request to `POST /sampling/<call>`. The gateway queues it in the key-value store
+//!    and waits for the client's answer.
+//! 3. While the tool runs, the gateway streaming the call long-polls the
+//!    queue with `GET /sampling/<call>/<seq>` and writes each request to the
+//!    client's stream, with an ID starting with `ftl-sampling:`.
+//! 4. The client posts its JSON-RPC response to the MCP endpoint. The
+//!    gateway stores it for the waiting request, which returns it to the
+//!    tool.
+//!
+//! Each gateway request runs in its own instance, so the queue and the
+//! answers live in the key-value store. Call IDs are random and only known
+//! to the gateway and the called component.
+
+use std::future::Future;
+use std::time::{Duration, Instant};
+
+use futures::channel::mpsc::UnboundedSender;
+use futures::future::Either;
+use serde_json::Value;
+use spin_sdk::http::{Method, Request, Response};
+use spin_sdk::key_value::Store;
+
+use crate::mcp_types::{ErrorCode, JsonRpcRequest, JsonRpcResponse};
+
+/// Header passing the ID of a streamed tool call to components
+pub const SAMPLING_CALL_HEADER: &str = "x-ftl-sampling-call";
+
+/// Path of the gateway's sampling endpoints
+pub const SAMPLING_PATH: &str = "/sampling";
+
+/// Requests tools may relay to the client
+const RELAYED_METHODS: &[&str] = &["sampling/createMessage", "elicitation/create"];
+
+/// Prefix of the IDs of sampling requests relayed to clients
+pub const REPLY_ID_PREFIX: &str = "ftl-sampling:";
+
+/// Error code for sampling requests that cannot reach a client
+pub const SAMPLING_UNAVAILABLE: i32 = -32001;
+
+/// How long the gateway waits for a client to answer a sampling request by
+/// default, in seconds
+pub const DEFAULT_TIMEOUT_SECONDS: u64 = 60;
+
+/// How long a long-poll for the next sampling request waits
+const LONG_POLL: Duration = Duration::from_secs(20);
+
+/// How often waiting requests check the key-value store
+const POLL_INTERVAL: Duration = Duration::from_millis(100);
+
+/// Where the gateway reaches its own sampling endpoints
+const GATEWAY_URL: &str = "http://mcp-gateway.spin.internal";
+
+/// Whether a request asks for a streamed response, so tools it calls may
+/// request sampling. Only client calls to tools are streamed.
+pub fn wants_stream(req: &Request) -> bool {
+    if *req.method() != Method::Post {
+        return false;
+    }
+    let header = |wanted: &str| {
+        req.headers()
+            .filter(|(name, _)| name.eq_ignore_ascii_case(wanted))
+            .filter_map(|(_, value)| std::str::from_utf8(value.as_bytes()).ok())
+            .collect::<Vec<_>>()
+    };
+    let accepts_sse = header("accept")
+        .iter()
+        .any(|accept| accept.contains("text/event-stream"));
+    let nested = !header(crate::gateway::CALL_DEPTH_HEADER).is_empty();
+    accepts_sse
+        && !nested
+        && serde_json::from_slice::<JsonRpcRequest>(req.body())
+            .is_ok_and(|request| request.method == "tools/call")
+}
+
+/// A new random call ID
+pub fn new_call_id() -> String {
+    crate::trace::random_hex(16)
+}
+
+/// An SSE `message` event carrying a JSON-RPC message
+pub fn sse_event(message: &[u8]) -> Vec<u8> {
+    let mut event = b"event: message\ndata: ".to_vec();
+    event.extend_from_slice(message);
+    event.extend_from_slice(b"\n\n");
+    event
+}
+
+/// The ID a relayed sampling request is sent to the client with
+fn reply_id(call: &str, seq: u64) -> String {
+    format!("{REPLY_ID_PREFIX}{call}:{seq}")
+}
+
+/// The call and sequence number of a relayed sampling request's ID
+fn parse_reply_id(id: &str) -> Option<(&str, u64)> {
+    let (call, seq) = id.strip_prefix(REPLY_ID_PREFIX)?.rsplit_once(':')?;
+    if call.is_empty() || !call.bytes().all(|b| b.is_ascii_hexdigit()) {
+        return None;
+    }
+    Some((call, seq.parse().ok()?))
+}
+
+/// If the body is a client's answer to a relayed sampling request, store
+/// it for the waiting tool. Returns whether it was one.
+pub fn accept_reply(body: &[u8]) -> bool {
+    let Ok(message) = serde_json::from_slice::<Value>(body) else {
+        return false;
+    };
+    if message.get("method").is_some() {
+        return false;
+    }
+    let Some((call, seq)) = message
+        .get("id")
+        .and_then(Value::as_str)
+        .and_then(parse_reply_id)
+    else {
+        return false;
+    };
+    match Mailbox::open(call) {
+        Some(mailbox) => mailbox.set(&Mailbox::response_key(call, seq), &message),
+        None => eprintln!("Dropped sampling response: key-value store unavailable"),
+    }
+    true
+}
+
+/// The sampling queue and answers of one streamed call, in the key-value
+/// store
+struct Mailbox {
+    store: Store,
+    call: String,
+}
+
+impl Mailbox {
+    fn open(call: &str) -> Option<Self> {
+        Store::open_default()
+            .map_err(|e| eprintln!("Failed to open key-value store for sampling: {e}"))
+            .ok()
+            .map(|store| Self {
+                store,
+                call: call.to_string(),
+            })
+    }
+
+    fn call_key(call: &str) -> String {
+        format!("gateway:sampling:{call}")
+    }
+
+    fn request_key(call: &str, seq: u64) -> String {
+        format!("gateway:sampling:{call}:request:{seq}")
+    }
+
+    fn response_key(call: &str, seq: u64) -> String {
+        format!("gateway:sampling:{call}:response:{seq}")
+    }
+
+    fn set(&self, key: &str, value: &Value) {
+        if let Ok(data) = serde_json::to_vec(value)
+            && let Err(e) = self.store.set(key, &data)
+        {
+            eprintln!(
+                "Failed to store sampling message for call '{}': {e}",
+                self.call
+            );
+        }
+    }
+
+    /// Take the value of a key, deleting it
+    fn take(&self, key: &str) -> Option<Value> {
+        let data = self.store.get(key).ok().flatten()?;
+        let _ = self.store.delete(key);
+        serde_json::from_slice(&data).ok()
+    }
+
+    /// Number of sampling requests the call has made, or `None` when the
+    /// call is not streamed
+    fn count(&self) -> Option<u64> {
+        let data = self.store.get(&Self::call_key(&self.call)).ok().flatten()?;
+        std::str::from_utf8(&data).ok()?.parse().ok()
+    }
+
+    fn set_count(&self, count: u64) {
+        if let Err(e) = self
+            .store
+            .set(&Self::call_key(&self.call), count.to_string().as_bytes())
+        {
+            eprintln!("Failed to open sampling for call '{}': {e}", self.call);
+        }
+    }
+
+    /// Queue a request for the client. A call's requests are numbered in
+    /// order; tools make them one at a time.
+    fn post(&self, method: &str, params: Option<Value>) -> Option<u64> {
+        let seq = self.count()?;
+        let mut request = serde_json::json!({
+            "jsonrpc": "2.0",
+            "id": reply_id(&self.call, seq),
+            "method": method,
+        });
+        if let (Some(params), Some(request)) = (params, request.as_object_mut()) {
+            request.insert("params".to_string(), params);
+        }
+        self.set(&Self::request_key(&self.call, seq), &request);
+        self.set_count(seq + 1);
+        Some(seq)
+    }
+
+    /// Delete what is left of the call once its response is sent
+    fn close(&self) {
+        let count = self.count().unwrap_or(0);
+        for seq in 0..count {
+            let _ = self.store.delete(&Self::request_key(&self.call, seq));
+            let _ = self.store.delete(&Self::response_key(&self.call, seq));
+        }
+        let _ = self.store.delete(&Self::call_key(&self.call));
+    }
+}
+
+/// Check every `POLL_INTERVAL` until `check` finds something or `timeout`
+/// passes. Blocks the instance, which has nothing else to do meanwhile.
+fn wait_for<T>(timeout: Duration, mut check: impl FnMut() -> Option<T>) -> Option<T> {
+    let deadline = Instant::now() + timeout;
+    loop {
+        if let Some(found) = check() {
+            return Some(found);
+        }
+        if Instant::now() >= deadline {
+            return None;
+        }
+        std::thread::sleep(POLL_INTERVAL);
+    }
+}
+
+fn json_response(status: u16, body: &impl serde::Serialize) -> Response {
+    Response::builder()
+        .status(status)
+        .header("Content-Type", "application/json")
+        .body(serde_json::to_vec(body).unwrap_or_default())
+        .build()
+}
+
+/// Handle the gateway's sampling endpoints: `POST /sampling/<call>` from
+/// tools and `GET /sampling/<call>/<seq>` from the gateway streaming the
+/// call. Returns `None` for other paths.
+pub fn handle_request(req: &Request, timeout: Duration) -> Option<Response> {
+    let rest = req.path().strip_prefix(SAMPLING_PATH)?.strip_prefix('/')?;
+    let response = match (req.method(), rest.split_once('/')) {
+        (Method::Post, None) => request_sampling(rest, req.body(), timeout),
+        (Method::Get, Some((call, seq))) => match seq.parse() {
+            Ok(seq) => next_request(call, seq),
+            Err(_) => Response::builder().status(404).build(),
+        },
+        _ => Response::builder().status(404).build(),
+    };
+    Some(response)
+}
+
+/// Relay a tool's sampling or elicitation request to the client and answer
+/// with the client's response
+fn request_sampling(call: &str, body: &[u8], timeout: Duration) -> Response {
+    let request: JsonRpcRequest = match serde_json::from_slice(body) {
+        Ok(request) => request,
+        Err(e) => {
+            return json_response(
+                200,
+                &JsonRpcResponse::error(
+                    None,
+                    ErrorCode::PARSE_ERROR.0,
+                    &format!("Invalid JSON-RPC request: {e}"),
+                ),
+            );
+        }
+    };
+    if !RELAYED_METHODS.contains(&request.method.as_str()) {
+        return json_response(
+            200,
+            &JsonRpcResponse::error(
+                request.id,
+                ErrorCode::METHOD_NOT_FOUND.0,
+                &format!("Method '{}' not found", request.method),
+            ),
+        );
+    }
+    let unavailable = |message: &str| {
+        json_response(
+            200,
+            &JsonRpcResponse::error(request.id.clone(), SAMPLING_UNAVAILABLE, message),
+        )
+    };
+
+    let Some(mailbox) = Mailbox::open(call) else {
+        return unavailable("The client cannot be asked: key-value store unavailable");
+    };
+    let Some(seq) = mailbox.post(&request.method, request.params.clone()) else {
+        return unavailable("The client did not accept a streamed response");
+    };
+    match wait_for(timeout, || mailbox.take(&Mailbox::response_key(call, seq))) {
+        Some(mut reply) => {
+            if let Some(reply) = reply.as_object_mut() {
+                reply.insert("id".to_string(), request.id.clone().unwrap_or(Value::Null));
+            }
+            json_response(200, &reply)
+        }
+        None => {
+            // The client may still be connected, but the tool has waited
+            // long enough
+            let _ = mailbox.take(&Mailbox::request_key(call, seq));
+            unavailable(&format!(
+                "The client did not answer the {} request within {} seconds",
+                request.method,
+                timeout.as_secs()
+            ))
+        }
+    }
+}
+
+/// Answer a long-poll for a call's next sampling request: `200` with the
+/// request, or `204` when none arrived in time
+fn next_request(call: &str, seq: u64) -> Response {
+    let Some(mailbox) = Mailbox::open(call) else {
+        return Response::builder().status(503).build();
+    };
+    match wait_for(LONG_POLL, || mailbox.take(&Mailbox::request_key(call, seq))) {
+        Some(request) => json_response(200, &request),
+        None => Response::builder().status(204).build(),
+    }
+}
+
+/// Relays sampling requests of a streamed tool call to the client
+pub struct SamplingRelay {
+    pub call: String,
+    mailbox: Option<Mailbox>,
+    events: UnboundedSender<Vec<u8>>,
+}
+
+impl SamplingRelay {
+    /// Open sampling for a call, sending the requests to relay as SSE
+    /// events to `events`
+    pub fn open(call: String, events: UnboundedSender<Vec<u8>>) -> Self {
+        let mailbox = Mailbox::open(&call);
+        if let Some(mailbox) = &mailbox {
+            mailbox.set_count(0);
+        }
+        Self {
+            call,
+            mailbox,
+            events,
+        }
+    }
+
+    /// Run `future`, relaying the sampling requests made meanwhile
+    pub async fn relay_while<F: Future>(&self, future: F) -> F::Output {
+        let relay = self.relay();
+        futures::pin_mut!(future, relay);
+        match futures::future::select(future, relay).await {
+            Either::Left((output, _)) => output,
+            Either::Right(((), future)) => future.await,
+        }
+    }
+
+    /// Long-poll for sampling requests and send them on. Stops when the
+    /// requests cannot be fetched or sent.
+    async fn relay(&self) {
+        if self.mailbox.is_none() {
+            return;
+        }
+        let mut seq = 0;
+        loop {
+            let mut builder = Request::builder();
+            builder
+                .method(Method::Get)
+                .uri(format!("{GATEWAY_URL}{SAMPLING_PATH}/{}/{seq}", self.call));
+            match spin_sdk::http::send::<_, Response>(builder.build()).await {
+                Ok(resp) if *resp.status() == 200 => {
+                    if self.events.unbounded_send(sse_event(resp.body())).is_err() {
+                        return;
+                    }
+                    seq += 1;
+                }
+                Ok(resp) if *resp.status() == 204 => {}
+                Ok(resp) => {
+                    eprintln!("Sampling relay stopped: status {}", resp.status());
+                    return;
+                }
+                Err(e) => {
+                    eprintln!("Sampling relay stopped: {e}");
+                    return;
+                }
+            }
+        }
+    }
+}
+
+impl Drop for SamplingRelay {
+    fn drop(&mut self) {
+        if let Some(mailbox) = &self.mailbox {
+            mailbox.close();
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn reply_ids_round_trip() {
+        let id = reply_id("4bf92f3577b34da6", 3);
+        assert_eq!(id, "ftl-sampling:4bf92f3577b34da6:3");
+        assert_eq!(parse_reply_id(&id), Some(("4bf92f3577b34da6", 3)));
+        assert_eq!(parse_reply_id("ftl-sampling:4bf92f3577b34da6"), None);
+        assert_eq!(parse_reply_id("ftl-sampling:../x:1"), None);
+        assert_eq!(parse_reply_id("request-1"), None);
+    }
+
+    #[test]
+    fn frames_sse_events() {
+        assert_eq!(
+            sse_event(br#"{"jsonrpc":"2.0"}"#),
+            b"event: message\ndata: {\"jsonrpc\":\"2.0\"}\n\n".to_vec()
+        );
+    }
+
+    #[test]
+    fn ignores_messages_that_are_not_replies() {
+        assert!(!accept_reply(
+            br#"{"jsonrpc":"2.0","id":1,"method":"ping"}"#
+        ));
+        assert!(!accept_reply(br#"{"jsonrpc":"2.0","id":1,"result":{}}"#));
+        assert!(!accept_reply(b"not json"));
+    }
+}
@@ -0,0 +1,264 @@
+//! HTTP+SSE transport
+//!
+//! Browser clients built on `EventSource`, and clients of the MCP HTTP+SSE
+//! transport (protocol version 2024-11-05), receive the gateway's messages
+//! on a long-lived event stream instead of in the responses to their
+//! requests. With `sse_transport` enabled:
+//!
+//! 1. The client opens the stream with `GET <endpoint>/sse`, where the
+//!    endpoint is any MCP path such as `/mcp` or `/mcp/x/<component>`. The
+//!    gateway opens a session and sends an `endpoint` event with the URL to
+//!    post messages to, `<endpoint>/messages?session_id=<session>`.
+//! 2. The client posts JSON-RPC messages to that URL. The gateway handles
+//!    them like requests to the endpoint, queues the responses for the
+//!    session in the key-value store and answers `202 Accepted`.
+//! 3. The gateway streaming the session polls the queue and sends each
+//!    response as a `message` event, with a comment every 15 seconds to keep
+//!    the connection open, until `sse_session_seconds` pass. Clients then
+//!    reconnect and start a new session.
+//!
+//! Each gateway request runs in its own instance, so sessions live in the
+//! key-value store. Session IDs are random and only known to the client.
+
+use std::time::{Duration, Instant};
+
+use futures::{Sink, SinkExt};
+use spin_sdk::http::{Method, Request};
+use spin_sdk::key_value::Store;
+
+/// Query parameter naming the session of a posted message
+pub const SESSION_PARAM: &str = "session_id";
+
+/// How long a session's stream stays open by default, in seconds
+pub const DEFAULT_SESSION_SECONDS: u64 = 300;
+
+/// How often the stream checks for queued messages
+const POLL_INTERVAL: Duration = Duration::from_millis(100);
+
+/// How often an idle stream sends a comment to keep the connection open
+const KEEPALIVE: Duration = Duration::from_secs(15);
+
+/// A request of the SSE transport
+#[derive(Debug, PartialEq, Eq)]
+pub enum Route {
+    /// `GET <endpoint>/sse` opens a session's stream
+    Stream { endpoint: String },
+    /// `POST <endpoint>/messages?session_id=<session>` posts a message
+    Message { endpoint: String, session: String },
+}
+
+/// The SSE transport request `req` is, if any. `valid_endpoint` tells
+/// whether a path is an MCP endpoint.
+pub fn route(req: &Request, valid_endpoint: impl Fn(&str) -> bool) -> Option<Route> {
+    let path = req.path().trim_end_matches('/');
+    match req.method() {
+        Method::Get => {
+            let endpoint = path.strip_suffix("/sse")?;
+            valid_endpoint(endpoint).then(|| Route::Stream {
+                endpoint: endpoint.to_string(),
+            })
+        }
+        Method::Post => {
+            let endpoint = path.strip_suffix("/messages")?;
+            if !valid_endpoint(endpoint) {
+                return None;
+            }
+            let query = req.uri().split_once('?').map_or("", |(_, query)| query);
+            let session = query
+                .split('&')
+                .filter_map(|pair| pair.split_once('='))
+                .find(|(name, _)| *name == SESSION_PARAM)
+                .map(|(_, value)| value.to_string())
+                .unwrap_or_default();
+            Some(Route::Message {
+                endpoint: endpoint.to_string(),
+                session,
+            })
+        }
+        _ => None,
+    }
+}
+
+/// The `endpoint` event telling the client where to post its messages
+pub fn endpoint_event(endpoint: &str, session: &str) -> Vec<u8> {
+    format!("event: endpoint\ndata: {endpoint}/messages?{SESSION_PARAM}={session}\n\n").into_bytes()
+}
+
+/// A session's queue of messages for the client, in the key-value store
+pub struct Session {
+    store: Store,
+    id: String,
+}
+
+impl Session {
+    /// Open a new session
+    pub fn create() -> Option<Self> {
+        let session = Self {
+            store: open_store()?,
+            id: crate::trace::random_hex(16),
+        };
+        if let Err(e) = session.store.set(&session.key(), b"open") {
+            eprintln!("Failed to open SSE session: {e}");
+            return None;
+        }
+        Some(session)
+    }
+
+    /// Open an existing session, or `None` when it is unknown or over
+    pub fn open(id: &str) -> Option<Self> {
+        if id.is_empty() || !id.bytes().all(|b| b.is_ascii_hexdigit()) {
+            return None;
+        }
+        let session = Self {
+            store: open_store()?,
+            id: id.to_string(),
+        };
+        session
+            .store
+            .get(&session.key())
+            .ok()
+            .flatten()
+            .map(|_| session)
+    }
+
+    pub fn id(&self) -> &str {
+        &self.id
+    }
+
+    fn key(&self) -> String {
+        format!("gateway:sse:{}", self.id)
+    }
+
+    fn message_prefix(&self) -> String {
+        format!("gateway:sse:{}:message:", self.id)
+    }
+
+    /// Queue a message for the client. Keys start with the time, so the
+    /// stream sends messages in order, and end with a random suffix, so
+    /// concurrent requests do not overwrite each other's messages.
+    pub fn push(&self, message: &[u8]) {
+        let key = format!(
+            "{}{:020}-{}",
+            self.message_prefix(),
+            crate::trace::now_nanos(),
+            crate::trace::random_hex(4)
+        );
+        if let Err(e) = self.store.set(&key, message) {
+            eprintln!("Failed to queue message for SSE session '{}': {e}", self.id);
+        }
+    }
+
+    /// Take the queued messages, oldest first
+    fn take(&self) -> Vec<Vec<u8>> {
+        let prefix = self.message_prefix();
+        let mut keys: Vec<String> = self
+            .store
+            .get_keys()
+            .unwrap_or_default()
+            .into_iter()
+            .filter(|key| key.starts_with(&prefix))
+            .collect();
+        keys.sort();
+        keys.iter()
+            .filter_map(|key| {
+                let message = self.store.get(key).ok().flatten();
+                let _ = self.store.delete(key);
+                message
+            })
+            .collect()
+    }
+
+    /// Send queued messages to the client as `message` events until
+    /// `lifetime` passes or the client goes away, then end the session
+    pub async fn stream<S: Sink<Vec<u8>> + Unpin>(self, mut body: S, lifetime: Duration) {
+        let deadline = Instant::now() + lifetime;
+        let mut last_sent = Instant::now();
+        while Instant::now() < deadline {
+            let messages = self.take();
+            if messages.is_empty() {
+                if last_sent.elapsed() >= KEEPALIVE {
+                    if body.send(b": keepalive\n\n".to_vec()).await.is_err() {
+                        break;
+                    }
+                    last_sent = Instant::now();
+                }
+                std::thread::sleep(POLL_INTERVAL);
+                continue;
+            }
+            for message in messages {
+                if body
+                    .send(crate::sampling::sse_event(&message))
+                    .await
+                    .is_err()
+                {
+                    self.close();
+                    return;
+                }
+            }
+            last_sent = Instant::now();
+        }
+        self.close();
+    }
+
+    /// Delete the session and the messages left in its queue
+    fn close(&self) {
+        let _ = self.store.delete(&self.key());
+        for _ in self.take() {}
+    }
+}
+
+fn open_store() -> Option<Store> {
+    Store::open_default()
+        .map_err(|e| eprintln!("Failed to open key-value store for SSE: {e}"))
+        .ok()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn request(method: Method, uri: &str) -> Request {
+        Request::builder().method(method).uri(uri).build()
+    }
+
+    fn valid(endpoint: &str) -> bool {
+        endpoint.is_empty() || endpoint == "/mcp" || endpoint.starts_with("/mcp/x/")
+    }
+
+    #[test]
+    fn routes_streams_and_messages() {
+        assert_eq!(
+            route(&request(Method::Get, "/mcp/sse"), valid),
+            Some(Route::Stream {
+                endpoint: "/mcp".to_string()
+            })
+        );
+        assert_eq!(
+            route(&request(Method::Get, "/sse"), valid),
+            Some(Route::Stream {
+                endpoint: String::new()
+            })
+        );
+        assert_eq!(
+            route(
+                &request(Method::Post, "/mcp/x/geo/messages?session_id=ab12"),
+                valid
+            ),
+            Some(Route::Message {
+                endpoint: "/mcp/x/geo".to_string(),
+                session: "ab12".to_string()
+            })
+        );
+        assert_eq!(route(&request(Method::Post, "/mcp"), valid), None);
+        assert_eq!(route(&request(Method::Get, "/other/sse"), valid), None);
+        assert_eq!(route(&request(Method::Delete, "/mcp/sse"), valid), None);
+    }
+
+    #[test]
+    fn frames_endpoint_events() {
+        assert_eq!(
+            endpoint_event("/mcp", "ab12"),
+            b"event: endpoint\ndata: /mcp/messages?session_id=ab12\n\n".to_vec()
+        );
+    }
+}
@@ -0,0 +1,339 @@
+//! W3C trace context propagation and spans for gateway requests.
+//!
+//! The gateway joins the caller's trace when a `traceparent` header is
+//! present, records a server span for each MCP request and a client span
+//! for every call it makes to a component, and forwards `traceparent` so
+//! component SDKs continue the same trace. Finished spans are encoded as
+//! OTLP/HTTP JSON.
+
+use std::collections::hash_map::RandomState;
+use std::fmt::Write as _;
+use std::hash::{BuildHasher, Hasher};
+use std::time::{SystemTime, UNIX_EPOCH};
+
+use serde_json::{Value, json};
+
+pub const TRACEPARENT_HEADER: &str = "traceparent";
+pub const TRACESTATE_HEADER: &str = "tracestate";
+
+/// Service name reported on exported spans
+pub const SERVICE_NAME: &str = "mcp-gateway";
+
+/// OTLP span kinds used by the gateway
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum SpanKind {
+    Server,
+    Client,
+}
+
+impl SpanKind {
+    const fn otlp(self) -> u8 {
+        match self {
+            Self::Server => 2,
+            Self::Client => 3,
+        }
+    }
+}
+
+/// The position of a span within a distributed trace
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct TraceContext {
+    pub trace_id: String,
+    /// Span that new spans are children of; `None` for a new root
+    pub parent_id: Option<String>,
+    pub sampled: bool,
+    pub trace_state: Option<String>,
+}
+
+impl TraceContext {
+    /// Parse a version 00 `traceparent` header value
+    pub fn parse(traceparent: &str) -> Option<Self> {
+        let mut parts = traceparent.trim().split('-');
+        let version = parts.next()?;
+        let trace_id = parts.next()?;
+        let parent_id = parts.next()?;
+        let flags = parts.next()?;
+        if parts.next().is_some()
+            || version != "00"
+            || !is_hex_id(trace_id, 32)
+            || !is_hex_id(parent_id, 16)
+            || flags.len() != 2
+        {
+            return None;
+        }
+        let flags = u8::from_str_radix(flags, 16).ok()?;
+        Some(Self {
+            trace_id: trace_id.to_ascii_lowercase(),
+            parent_id: Some(parent_id.to_ascii_lowercase()),
+            sampled: flags & 1 == 1,
+            trace_state: None,
+        })
+    }
+
+    /// Continue the trace described by incoming headers, or start a new one
+    /// when there is no valid `traceparent`
+    pub fn from_headers(traceparent: Option<&str>, tracestate: Option<&str>) -> Self {
+        traceparent
+            .and_then(Self::parse)
+            .map_or_else(Self::new_root, |ctx| Self {
+                trace_state: tracestate
+                    .map(str::trim)
+                    .filter(|s| !s.is_empty())
+                    .map(str::to_string),
+                ..ctx
+            })
+    }
+
+    /// Start a new, sampled trace
+    pub fn new_root() -> Self {
+        Self {
+            trace_id: random_hex(16),
+            parent_id: None,
+            sampled: true,
+            trace_state: None,
+        }
+    }
+}
+
+/// An in-progress span
+#[derive(Debug, Clone)]
+pub struct Span {
+    trace: TraceContext,
+    id: String,
+    name: String,
+    kind: SpanKind,
+    start_nanos: u128,
+    attributes: Vec<(String, String)>,
+}
+
+impl Span {
+    /// Start a span as a child of `parent`
+    pub fn start(parent: &TraceContext, name: impl Into<String>, kind: SpanKind) -> Self {
+        Self {
+            trace: parent.clone(),
+            id: random_hex(8),
+            name: name.into(),
+            kind,
+            start_nanos: now_nanos(),
+            attributes: Vec::new(),
+        }
+    }
+
+    pub fn set_attribute(&mut self, key: &str, value: impl Into<String>) {
+        self.attributes.push((key.to_string(), value.into()));
+    }
+
+    /// Context for spans started beneath this one
+    pub fn context(&self) -> TraceContext {
+        TraceContext {
+            parent_id: Some(self.id.clone()),
+            ..self.trace.clone()
+        }
+    }
+
+    /// The `traceparent` header that makes a downstream request a child of
+    /// this span
+    pub fn traceparent(&self) -> String {
+        let flags = if self.trace.sampled { "01" } else { "00" };
+        format!("00-{}-{}-{flags}", self.trace.trace_id, self.id)
+    }
+
+    pub fn trace_state(&self) -> Option<&str> {
+        self.trace.trace_state.as_deref()
+    }
+
+    /// End the span, marking it failed when `error` is set
+    pub fn finish(self, error: Option<&str>) -> FinishedSpan {
+        FinishedSpan {
+            end_nanos: now_nanos().max(self.start_nanos),
+            error: error.map(str::to_string),
+            span: self,
+        }
+    }
+}
+
+/// A completed span, ready to be exported
+#[derive(Debug, Clone)]
+pub struct FinishedSpan {
+    span: Span,
+    end_nanos: u128,
+    error: Option<String>,
+}
+
+impl FinishedSpan {
+    pub fn sampled(&self) -> bool {
+        self.span.trace.sampled
+    }
+
+    /// Encode the span in the OTLP JSON format
+    pub fn to_otlp(&self) -> Value {
+        let mut span = json!({
+            "traceId": self.span.trace.trace_id,
+            "spanId": self.span.id,
+            "name": self.span.name,
+            "kind": self.span.kind.otlp(),
+            "startTimeUnixNano": self.span.start_nanos.to_string(),
+            "endTimeUnixNano": self.end_nanos.to_string(),
+            "attributes": self.span.attributes.iter().map(|(key, value)| json!({
+                "key": key,
+                "value": { "stringValue": value },
+            })).collect::<Vec<_>>(),
+        });
+        if let Some(object) = span.as_object_mut() {
+            if let Some(parent) = &self.span.trace.parent_id {
+                object.insert("parentSpanId".to_string(), json!(parent));
+            }
+            if let Some(state) = &self.span.trace.trace_state {
+                object.insert("traceState".to_string(), json!(state));
+            }
+            if let Some(error) = &self.error {
+                object.insert("status".to_string(), json!({ "code": 2, "message": error }));
+            }
+        }
+        span
+    }
+}
+
+/// Build an OTLP `ExportTraceServiceRequest` body for sampled spans
+pub fn otlp_request(spans: &[FinishedSpan]) -> Value {
+    json!({
+        "resourceSpans": [{
+            "resource": {
+                "attributes": [{
+                    "key": "service.name",
+                    "value": { "stringValue": SERVICE_NAME },
+                }],
+            },
+            "scopeSpans": [{
+                "scope": { "name": SERVICE_NAME },
+                "spans": spans
+                    .iter()
+                    .filter(|span| span.sampled())
+                    .map(FinishedSpan::to_otlp)
+                    .collect::<Vec<_>>(),
+            }],
+        }],
+    })
+}
+
+fn is_hex_id(value: &str, len: usize) -> bool {
+    value.len() == len
+        && value.bytes().all(|b| b.is_ascii_hexdigit())
+        && value.bytes().any(|b| b != b'0')
+}
+
+pub fn now_nanos() -> u128 {
+    SystemTime::now()
+        .duration_since(UNIX_EPOCH)
+        .map_or(0, |d| d.as_nanos())
+}
+
+/// Random lowercase hex of `bytes` bytes. `RandomState` is seeded from the
+/// host's random source, which avoids a dependency on a random crate.
+pub fn random_hex(bytes: usize) -> String {
+    let mut out = String::with_capacity(bytes * 2);
+    while out.len() < bytes * 2 {
+        let mut hasher = RandomState::new().build_hasher();
+        hasher.write_u128(now_nanos());
+        let _ = write!(out, "{:016x}", hasher.finish());
+    }
+    out.truncate(bytes * 2);
+    out
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    const PARENT: &str = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01";
+
+    #[test]
+    fn parses_traceparent() {
+        let ctx = TraceContext::parse(PARENT);
+        assert_eq!(
+            ctx,
+            Some(TraceContext {
+                trace_id: "4bf92f3577b34da6a3ce929d0e0e4736".to_string(),
+                parent_id: Some("00f067aa0ba902b7".to_string()),
+                sampled: true,
+                trace_state: None,
+            })
+        );
+    }
+
+    #[test]
+    fn rejects_invalid_traceparent() {
+        for value in [
+            "",
+            "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+            "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+            "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+            "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+            "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+            "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+        ] {
+            assert_eq!(TraceContext::parse(value), None, "{value}");
+        }
+    }
+
+    #[test]
+    fn starts_new_trace_without_parent() {
+        let ctx = TraceContext::from_headers(Some("garbage"), Some("vendor=1"));
+        assert_eq!(ctx.trace_id.len(), 32);
+        assert!(ctx.parent_id.is_none());
+        assert!(ctx.sampled);
+        assert!(ctx.trace_state.is_none());
+    }
+
+    #[test]
+    fn child_spans_continue_the_trace() {
+        let incoming = TraceContext::from_headers(Some(PARENT), Some("vendor=1"));
+        let server = Span::start(&incoming, "mcp tools/call", SpanKind::Server);
+        let mut client = Span::start(&server.context(), "tools/call weather", SpanKind::Client);
+        client.set_attribute("mcp.component", "weather");
+
+        let header = client.traceparent();
+        let forwarded = TraceContext::parse(&header);
+        assert_eq!(
+            forwarded.as_ref().map(|c| c.trace_id.as_str()),
+            Some("4bf92f3577b34da6a3ce929d0e0e4736")
+        );
+        assert_eq!(client.trace_state(), Some("vendor=1"));
+
+        let client = client.finish(Some("status 500"));
+        let otlp = client.to_otlp();
+        assert_eq!(otlp["parentSpanId"], json!(server.id));
+        assert_eq!(otlp["kind"], json!(3));
+        assert_eq!(otlp["status"]["code"], json!(2));
+        assert_eq!(otlp["attributes"][0]["key"], json!("mcp.component"));
+
+        let server = server.finish(None);
+        let otlp = server.to_otlp();
+        assert_eq!(otlp["parentSpanId"], json!("00f067aa0ba902b7"));
+        assert!(otlp.get("status").is_none());
+    }
+
+    #[test]
+    fn unsampled_spans_are_not_exported() {
+        let ctx = TraceContext::from_headers(
+            Some("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"),
+            None,
+        );
+        let span = Span::start(&ctx, "mcp ping", SpanKind::Server);
+        assert!(span.traceparent().ends_with("-00"));
+
+        let request = otlp_request(&[span.finish(None)]);
+        assert_eq!(
+            request["resourceSpans"][0]["scopeSpans"][0]["spans"],
+            json!([])
+        );
+    }
+
+    #[test]
+    fn random_ids_are_unique() {
+        let a = random_hex(8);
+        let b = random_hex(8);
+        assert_eq!(a.len(), 16);
+        assert_ne!(a, b);
+    }
+}
@@ -0,0 +1,371 @@
+//! Per-tool input transformations
+//!
+//! Apps can adapt generic components without forking them by declaring
+//! transformations for a component's tools, which the gateway applies to
+//! the arguments of each call before validating and forwarding them:
+//!
+//! ```json
+//! {
+//!   "search": {
+//!     "*": { "set": { "tenant_id": "${claims.org_id}" } },
+//!     "query": {
+//!       "rename": { "query": "q" },
+//!       "defaults": { "limit": 10 }
+//!     }
+//!   }
+//! }
+//! ```
+//!
+//! Keys are renamed first, then defaults fill in missing arguments and
+//! `set` overwrites arguments whatever the client sent. Strings in
+//! `defaults` and `set` may reference claims of the caller's token as
+//! `${claims.NAME}`. Transforms for a tool replace the component's `*`
+//! transforms rather than adding to them.
+//!
+//! `tools/list` shows each tool as clients call it: renamed arguments
+//! appear under their new names, arguments fixed by `set` disappear and
+//! defaulted arguments become optional.
+
+use std::collections::BTreeMap;
+
+use base64::Engine;
+use base64::engine::general_purpose::URL_SAFE_NO_PAD;
+use serde::Deserialize;
+use serde_json::{Map, Value};
+
+/// Key of the transforms applying to every tool of a component
+pub const ALL_TOOLS: &str = "*";
+
+/// Claims of the caller's token
+pub type Claims = Map<String, Value>;
+
+/// Transforms for each tool of each component
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(transparent)]
+pub struct ToolTransforms(BTreeMap<String, BTreeMap<String, ToolTransform>>);
+
+impl ToolTransforms {
+    /// Parse transforms from the JSON of the `tool_transforms` variable.
+    /// An empty value has no transforms.
+    pub fn parse(json: &str) -> Result<Self, String> {
+        if json.trim().is_empty() {
+            return Ok(Self::default());
+        }
+        serde_json::from_str(json).map_err(|e| format!("Invalid tool transforms: {e}"))
+    }
+
+    /// The transform applying to a component's tool, if any
+    pub fn get(&self, component: &str, tool: &str) -> Option<&ToolTransform> {
+        let tools = self.0.get(component)?;
+        tools.get(tool).or_else(|| tools.get(ALL_TOOLS))
+    }
+}
+
+/// Transformation of a tool's arguments
+#[derive(Debug, Clone, Default, Deserialize)]
+pub struct ToolTransform {
+    /// Arguments to rename, from the name clients send to the name the
+    /// component expects
+    #[serde(default)]
+    pub rename: BTreeMap<String, String>,
+
+    /// Values for arguments the client left out
+    #[serde(default)]
+    pub defaults: Map<String, Value>,
+
+    /// Values that replace whatever the client sent
+    #[serde(default)]
+    pub set: Map<String, Value>,
+}
+
+impl ToolTransform {
+    /// Transform a call's arguments. Claims are `None` when the caller was
+    /// not authenticated, and templates referencing them fail.
+    pub fn apply(&self, arguments: Value, claims: Option<&Claims>) -> Result<Value, String> {
+        let Value::Object(mut arguments) = arguments else {
+            return Err("arguments must be an object".to_string());
+        };
+
+        for (from, to) in &self.rename {
+            if let Some(value) = arguments.remove(from) {
+                arguments.insert(to.clone(), value);
+            }
+        }
+        for (key, value) in &self.defaults {
+            if !arguments.contains_key(key) {
+                arguments.insert(key.clone(), render(value, claims)?);
+            }
+        }
+        for (key, value) in &self.set {
+            arguments.insert(key.clone(), render(value, claims)?);
+        }
+        Ok(Value::Object(arguments))
+    }
+
+    /// Adapt a tool's input schema to the arguments clients send
+    pub fn adapt_schema(&self, schema: &Value) -> Value {
+        let mut schema = schema.clone();
+        let Some(object) = schema.as_object_mut() else {
+            return schema;
+        };
+
+        if let Some(Value::Object(properties)) = object.get_mut("properties") {
+            for key in self.set.keys() {
+                properties.remove(key);
+            }
+            for (key, value) in &self.defaults {
+                // Templated defaults depend on the caller, so are not shown
+                if let Some(Value::Object(property)) = properties.get_mut(key)
+                    && !has_template(value)
+                {
+                    property.insert("default".to_string(), value.clone());
+                }
+            }
+            for (from, to) in &self.rename {
+                if let Some(property) = properties.remove(to) {
+                    properties.insert(from.clone(), property);
+                }
+            }
+        }
+
+        if let Some(Value::Array(required)) = object.get_mut("required") {
+            required.retain(|name| {
+                name.as_str().is_none_or(|name| {
+                    !self.set.contains_key(name) && !self.defaults.contains_key(name)
+                })
+            });
+            for name in required.iter_mut() {
+                if let Some(from) = name
+                    .as_str()
+                    .and_then(|to| self.rename.iter().find(|(_, t)| t.as_str() == to))
+                    .map(|(from, _)| from.clone())
+                {
+                    *name = Value::String(from);
+                }
+            }
+        }
+        schema
+    }
+}
+
+/// Read the claims of the bearer token in an `Authorization` header. The
+/// signature is not checked: the authorizer in front of the gateway has
+/// already verified the token.
+pub fn claims_from_authorization(header: &str) -> Option<Claims> {
+    let (scheme, token) = header.trim().split_once(' ')?;
+    if !scheme.eq_ignore_ascii_case("bearer") {
+        return None;
+    }
+    let payload = token.trim().split('.').nth(1)?;
+    let bytes = URL_SAFE_NO_PAD.decode(payload.trim_end_matches('=')).ok()?;
+    serde_json::from_slice(&bytes).ok()
+}
+
+/// Encode claims for the `x-auth-claims` header sent to components, as
+/// base64url JSON like a token payload
+pub fn encode_claims(claims: &Claims) -> String {
+    URL_SAFE_NO_PAD.encode(serde_json::to_vec(claims).unwrap_or_default())
+}
+
+/// Replace templates in the strings of a value
+fn render(value: &Value, claims: Option<&Claims>) -> Result<Value, String> {
+    match value {
+        Value::String(s) => render_str(s, claims),
+        Value::Array(items) => items
+            .iter()
+            .map(|item| render(item, claims))
+            .collect::<Result<_, _>>()
+            .map(Value::Array),
+        Value::Object(fields) => fields
+            .iter()
+            .map(|(key, field)| Ok((key.clone(), render(field, claims)?)))
+            .collect::<Result<_, String>>()
+            .map(Value::Object),
+        other => Ok(other.clone()),
+    }
+}
+
+/// Replace templates in a string. A string that is a single template takes
+/// the claim's value as is, so non-string claims keep their type.
+fn render_str(template: &str, claims: Option<&Claims>) -> Result<Value, String> {
+    if let Some(expr) = template
+        .strip_prefix("${")
+        .and_then(|rest| rest.strip_suffix('}'))
+        && !expr.contains('}')
+    {
+        return lookup(expr, claims).cloned();
+    }
+
+    let mut rendered = String::new();
+    let mut rest = template;
+    while let Some((before, after)) = rest.split_once("${") {
+        let (expr, tail) = after
+            .split_once('}')
+            .ok_or_else(|| format!("Unterminated template in '{template}'"))?;
+        rendered.push_str(before);
+        match lookup(expr, claims)? {
+            Value::String(s) => rendered.push_str(s),
+            other => rendered.push_str(&other.to_string()),
+        }
+        rest = tail;
+    }
+    rendered.push_str(rest);
+    Ok(Value::String(rendered))
+}
+
+/// Look up the claim a template expression refers to
+fn lookup<'a>(expr: &str, claims: Option<&'a Claims>) -> Result<&'a Value, String> {
+    let expr = expr.trim();
+    let name = expr
+        .strip_prefix("claims.")
+        .ok_or_else(|| format!("Unsupported template '${{{expr}}}'"))?;
+    let claims = claims
+        .ok_or_else(|| format!("Claim '{name}' is not available for unauthenticated requests"))?;
+    claims
+        .get(name)
+        .ok_or_else(|| format!("Token has no '{name}' claim"))
+}
+
+fn has_template(value: &Value) -> bool {
+    match value {
+        Value::String(s) => s.contains("${"),
+        Value::Array(items) => items.iter().any(has_template),
+        Value::Object(fields) => fields.values().any(has_template),
+        _ => false,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use serde_json::json;
+
+    fn transform(value: Value) -> ToolTransform {
+        serde_json::from_value(value).unwrap_or_default()
+    }
+
+    fn claims() -> Claims {
+        match json!({"sub": "user_1", "org_id": "org_42", "tier": 3}) {
+            Value::Object(claims) => claims,
+            _ => Claims::new(),
+        }
+    }
+
+    #[test]
+    fn renames_defaults_and_sets_arguments() {
+        let t = transform(json!({
+            "rename": {"query": "q"},
+            "defaults": {"limit": 10, "q": "ignored"},
+            "set": {"tenant": "${claims.org_id}", "level": "${claims.tier}"}
+        }));
+        let arguments = json!({"query": "rust", "tenant": "someone-else"});
+        assert_eq!(
+            t.apply(arguments, Some(&claims())),
+            Ok(json!({"q": "rust", "limit": 10, "tenant": "org_42", "level": 3}))
+        );
+    }
+
+    #[test]
+    fn interpolates_claims_into_strings() {
+        let t = transform(json!({"set": {"path": "/tenants/${claims.org_id}/${ claims.sub }"}}));
+        assert_eq!(
+            t.apply(json!({}), Some(&claims())),
+            Ok(json!({"path": "/tenants/org_42/user_1"}))
+        );
+    }
+
+    #[test]
+    fn template_errors() {
+        let missing = transform(json!({"set": {"tenant": "${claims.missing}"}}));
+        assert_eq!(
+            missing.apply(json!({}), Some(&claims())),
+            Err("Token has no 'missing' claim".to_string())
+        );
+        assert_eq!(
+            missing.apply(json!({}), None),
+            Err("Claim 'missing' is not available for unauthenticated requests".to_string())
+        );
+
+        let unsupported = transform(json!({"set": {"tenant": "${env.TENANT}"}}));
+        assert_eq!(
+            unsupported.apply(json!({}), Some(&claims())),
+            Err("Unsupported template '${env.TENANT}'".to_string())
+        );
+
+        assert_eq!(
+            transform(json!({})).apply(json!([1, 2]), None),
+            Err("arguments must be an object".to_string())
+        );
+    }
+
+    #[test]
+    fn adapts_schema_to_client_arguments() {
+        let t = transform(json!({
+            "rename": {"query": "q"},
+            "defaults": {"limit": 10, "owner": "${claims.sub}"},
+            "set": {"tenant": "acme"}
+        }));
+        let schema = json!({
+            "type": "object",
+            "properties": {
+                "q": {"type": "string"},
+                "limit": {"type": "integer"},
+                "owner": {"type": "string"},
+                "tenant": {"type": "string"}
+            },
+            "required": ["q", "limit", "owner", "tenant"]
+        });
+        assert_eq!(
+            t.adapt_schema(&schema),
+            json!({
+                "type": "object",
+                "properties": {
+                    "query": {"type": "string"},
+                    "limit": {"type": "integer", "default": 10},
+                    "owner": {"type": "string"}
+                },
+                "required": ["query"]
+            })
+        );
+    }
+
+    #[test]
+    fn tool_transforms_fall_back_to_all_tools() {
+        let transforms = ToolTransforms::parse(
+            r#"{"search": {"*": {"defaults": {"limit": 5}}, "lookup": {"rename": {"id": "key"}}}}"#,
+        )
+        .unwrap_or_default();
+        assert!(
+            transforms
+                .get("search", "lookup")
+                .is_some_and(|t| t.defaults.is_empty())
+        );
+        assert!(
+            transforms
+                .get("search", "query")
+                .is_some_and(|t| t.rename.is_empty())
+        );
+        assert!(transforms.get("other", "query").is_none());
+        assert!(ToolTransforms::parse("").is_ok_and(|t| t.get("search", "query").is_none()));
+        assert!(ToolTransforms::parse("{").is_err());
+    }
+
+    #[test]
+    fn reads_claims_from_bearer_token() {
+        let payload = URL_SAFE_NO_PAD.encode(br#"{"sub":"user_1","org_id":"org_42"}"#);
+        let header = format!("Bearer header.{payload}.signature");
+        let claims = claims_from_authorization(&header);
+        assert_eq!(
+            claims.as_ref().and_then(|c| c.get("org_id")),
+            Some(&json!("org_42"))
+        );
+        assert!(claims_from_authorization("Basic dXNlcjpwYXNz").is_none());
+        assert!(claims_from_authorization("Bearer not-a-jwt").is_none());
+    }
+
+    #[test]
+    fn encodes_claims_like_a_token_payload() {
+        let header = format!("Bearer header.{}.signature", encode_claims(&claims()));
+        assert_eq!(claims_from_authorization(&header), Some(claims()));
+    }
+}
@@ -0,0 +1,80 @@
+use crate::{test_helpers::*, ResponseData};
+use spin_test_sdk::{
+    bindings::fermyon::spin_test_virt::{key_value, variables},
+    spin_test,
+};
+
+fn setup_limited_tool() {
+    variables::set("component_names", "renderer");
+    variables::set("validate_arguments", "true");
+
+    mock_tool_component(
+        "renderer",
+        vec![ToolMetadata {
+            name: "render".to_string(),
+            title: None,
+            description: Some("Render a report".to_string()),
+            input_schema: serde_json::json!({"type": "object"}),
+            output_schema: None,
+            annotations: None,
+            meta: Some(serde_json::json!({"maxConcurrency": 1})),
+        }],
+    );
+    mock_tool_execution(
+        "renderer",
+        "render",
+        ToolResponse {
+            content: vec![ToolContent::Text {
+                text: "Rendered".to_string(),
+                annotations: None,
+            }],
+            structured_content: None,
+            is_error: None,
+        },
+    );
+}
+
+fn call_render() -> serde_json::Value {
+    let request_json = create_json_rpc_request(
+        "tools/call",
+        Some(serde_json::json!({"name": "renderer__render", "arguments": {}})),
+        Some(serde_json::json!(1)),
+    );
+    let response = spin_test_sdk::perform_request(create_mcp_request(request_json));
+    ResponseData::from_response(response)
+        .body_json()
+        .expect("Expected JSON response")
+}
+
+#[spin_test]
+fn test_tool_under_concurrency_limit_runs() {
+    setup_limited_tool();
+
+    let response_json = call_render();
+    assert_json_rpc_success(&response_json, Some(serde_json::json!(1)));
+    assert_eq!(response_json["result"]["content"][0]["text"], "Rendered");
+
+    // The call's slot is released once it finishes. Mocked responses are
+    // used up, so mock the component again
+    setup_limited_tool();
+    assert_json_rpc_success(&call_render(), Some(serde_json::json!(1)));
+}
+
+#[spin_test]
+fn test_tool_at_concurrency_limit_rejected() {
+    setup_limited_tool();
+
+    // Another instance holds the only slot
+    let kv = key_value::Store::open("default");
+    kv.set(
+        "gateway:concurrency:renderer:render:0a1b2c3d4e5f6a7b",
+        b"99999999999999",
+    );
+
+    let response_json = call_render();
+    assert_json_rpc_error(&response_json, -32003, Some(serde_json::json!(1)));
+    let data = &response_json["error"]["data"];
+    assert_eq!(data["type"], "concurrency_limited");
+    assert_eq!(data["retryable"], true);
+    assert_eq!(data["max_concurrency"], 1);
+}
@@ -1,5 +1,8 @@
 use crate::{test_helpers::*, ResponseData};
-use spin_test_sdk::{bindings::wasi::http, spin_test};
+use spin_test_sdk::{
+    bindings::{fermyon::spin_test_virt::variables, wasi::http},
+    spin_test,
+};
 
 #[spin_test]
 fn test_cors_preflight_options() {
@@ -35,8 +38,110 @@ fn test_cors_preflight_options() {
     );
     assert_eq!(
         response_data.find_header("access-control-allow-headers"),
-        Some(&b"Content-Type, X-MCP-Toolsets, X-MCP-Readonly".to_vec())
+        Some(
+            &[
+                "Content-Type",
+                "Accept",
+                "Authorization",
+                "Mcp-Session-Id",
+                "Mcp-Protocol-Version",
+                "Last-Event-ID",
+                "X-MCP-Toolsets",
+                "X-MCP-Readonly",
+                "X-Request-Id",
+                "Idempotency-Key",
+                "traceparent",
+                "tracestate",
+            ]
+            .join(", ")
+            .into_bytes()
+        )
     );
+    assert_eq!(
+        response_data.find_header("access-control-max-age"),
+        Some(&b"600".to_vec())
+    );
+}
+
+#[spin_test]
+fn test_cors_preflight_configured() {
+    setup_default_test_env();
+    variables::set("cors_allowed_headers", "X-Api-Key");
+    variables::set("cors_max_age_seconds", "86400");
+
+    let request = http::types::OutgoingRequest::new(http::types::Headers::new());
+    request.set_method(&http::types::Method::Options).unwrap();
+    request.set_path_with_query(Some("/mcp")).unwrap();
+
+    let response_data = ResponseData::from_response(spin_test_sdk::perform_request(request));
+
+    assert_eq!(response_data.status, 200);
+    let allowed = String::from_utf8(
+        response_data
+            .find_header("access-control-allow-headers")
+            .unwrap()
+            .clone(),
+    )
+    .unwrap();
+    assert!(allowed.ends_with(", X-Api-Key"));
+    assert_eq!(
+        response_data.find_header("access-control-max-age"),
+        Some(&b"86400".to_vec())
+    );
+}
+
+fn ping_from(origin: &str) -> ResponseData {
+    let request_json = create_json_rpc_request("ping", None, Some(serde_json::json!(1)));
+    let headers = http::types::Headers::new();
+    headers.append("content-type", b"application/json").unwrap();
+    headers.append("origin", origin.as_bytes()).unwrap();
+
+    let request = http::types::OutgoingRequest::new(headers);
+    request.set_method(&http::types::Method::Post).unwrap();
+    request.set_path_with_query(Some("/mcp")).unwrap();
+    request
+        .body()
+        .unwrap()
+        .write_bytes(&serde_json::to_vec(&request_json).unwrap());
+
+    ResponseData::from_response(spin_test_sdk::perform_request(request))
+}
+
+#[spin_test]
+fn test_cors_allowed_origins_echoed() {
+    setup_default_test_env();
+    variables::set(
+        "cors_allowed_origins",
+        "https://playground.example.com, https://*.acme.dev",
+    );
+
+    let response_data = ping_from("https://playground.example.com");
+    assert_eq!(response_data.status, 200);
+    assert_eq!(
+        response_data.find_header("access-control-allow-origin"),
+        Some(&b"https://playground.example.com".to_vec())
+    );
+    assert_eq!(response_data.find_header("vary"), Some(&b"Origin".to_vec()));
+
+    let response_data = ping_from("https://docs.acme.dev");
+    assert_eq!(
+        response_data.find_header("access-control-allow-origin"),
+        Some(&b"https://docs.acme.dev".to_vec())
+    );
+}
+
+#[spin_test]
+fn test_cors_other_origins_not_allowed() {
+    setup_default_test_env();
+    variables::set("cors_allowed_origins", "https://playground.example.com");
+
+    // The request is handled, but browsers may not read the response
+    let response_data = ping_from("https://evil.example.org");
+    assert_eq!(response_data.status, 200);
+    assert!(response_data
+        .find_header("access-control-allow-origin")
+        .is_none());
+    assert_eq!(response_data.find_header("vary"), Some(&b"Origin".to_vec()));
 }
 
 #[spin_test]
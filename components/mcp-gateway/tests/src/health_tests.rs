@@ -0,0 +1,68 @@
+use crate::ResponseData;
+use spin_test_sdk::{
+    bindings::{
+        fermyon::{spin_test_virt::variables, spin_wasi_virt::http_handler},
+        wasi::http,
+    },
+    spin_test,
+};
+
+// Mock a component's health endpoint with the given status
+fn mock_health(component_name: &str, status: u16) {
+    let headers = http::types::Headers::new();
+    headers.append("content-type", b"application/json").unwrap();
+
+    let response = http::types::OutgoingResponse::new(headers);
+    response.set_status_code(status).unwrap();
+    let body = response.body().unwrap();
+    body.write_bytes(br#"{"status":"ok"}"#);
+
+    let url = format!("http://{component_name}.spin.internal/.well-known/ftl/health");
+    http_handler::set_response(&url, http_handler::ResponseHandler::Response(response));
+}
+
+fn get_health() -> ResponseData {
+    let request = http::types::OutgoingRequest::new(http::types::Headers::new());
+    request.set_method(&http::types::Method::Get).unwrap();
+    request.set_path_with_query(Some("/health")).unwrap();
+
+    ResponseData::from_response(spin_test_sdk::perform_request(request))
+}
+
+#[spin_test]
+fn test_health_all_components_up() {
+    variables::set("component_names", "echo,weather");
+    mock_health("echo", 200);
+    mock_health("weather", 200);
+
+    let response = get_health();
+    assert_eq!(response.status, 200);
+
+    let report = response.body_json().expect("Expected JSON response");
+    assert_eq!(report["healthy"], true);
+    let components = report["components"].as_array().unwrap();
+    assert_eq!(components.len(), 2);
+    assert_eq!(components[0]["component"], "echo");
+    assert_eq!(components[0]["circuit"], "closed");
+}
+
+#[spin_test]
+fn test_health_opens_circuit_for_failing_component() {
+    variables::set("component_names", "echo,weather");
+    variables::set("circuit_failure_threshold", "2");
+    mock_health("echo", 200);
+    mock_health("weather", 500);
+
+    let first = get_health();
+    assert_eq!(first.status, 503);
+    let report = first.body_json().expect("Expected JSON response");
+    assert_eq!(report["healthy"], false);
+    assert_eq!(report["components"][1]["circuit"], "closed");
+    assert_eq!(report["components"][1]["error"], "status 500");
+
+    let second = get_health();
+    let report = second.body_json().expect("Expected JSON response");
+    assert_eq!(report["components"][0]["healthy"], true);
+    assert_eq!(report["components"][1]["circuit"], "open");
+    assert_eq!(report["components"][1]["consecutive_failures"], 2);
+}
@@ -5,13 +5,19 @@ use spin_test_sdk::{
 
 mod basic_test;
 mod clean_scoping_tests;
+mod concurrency_tests;
 mod cors_tests;
 mod error_handling_tests;
+mod health_tests;
 mod integration_tests;
 mod json_rpc_tests;
+mod metrics_tests;
 mod performance_tests;
 mod protocol_tests;
+mod resource_tests;
 mod routing_tests;
+mod sampling_tests;
+mod sse_tests;
 mod test_helpers;
 mod tool_discovery_tests;
 mod validation_tests;
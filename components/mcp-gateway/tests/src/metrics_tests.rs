@@ -0,0 +1,105 @@
+use crate::test_helpers::*;
+use crate::ResponseData;
+use spin_test_sdk::{
+    bindings::{
+        fermyon::spin_test_virt::{key_value, variables},
+        wasi::http,
+    },
+    spin_test,
+};
+
+fn get_metrics() -> ResponseData {
+    let request = http::types::OutgoingRequest::new(http::types::Headers::new());
+    request.set_method(&http::types::Method::Get).unwrap();
+    request.set_path_with_query(Some("/metrics")).unwrap();
+
+    ResponseData::from_response(spin_test_sdk::perform_request(request))
+}
+
+fn call_echo(id: i64) {
+    let request = create_json_rpc_request(
+        "tools/call",
+        Some(serde_json::json!({
+            "name": "echo__echo_message",
+            "arguments": { "message": "hello" }
+        })),
+        Some(serde_json::json!(id)),
+    );
+    let response =
+        ResponseData::from_response(spin_test_sdk::perform_request(create_mcp_request(request)));
+    assert_eq!(response.status, 200);
+}
+
+#[spin_test]
+fn test_metrics_count_tool_calls() {
+    variables::set("component_names", "echo");
+    variables::set("metrics_enabled", "true");
+    variables::set("validate_arguments", "false");
+    mock_tool_execution(
+        "echo",
+        "echo_message",
+        ToolResponse {
+            content: vec![ToolContent::Text {
+                text: "hello".to_string(),
+                annotations: None,
+            }],
+            structured_content: None,
+            is_error: None,
+        },
+    );
+
+    call_echo(1);
+    mock_tool_execution(
+        "echo",
+        "echo_message",
+        ToolResponse {
+            content: vec![ToolContent::Text {
+                text: "hello".to_string(),
+                annotations: None,
+            }],
+            structured_content: None,
+            is_error: None,
+        },
+    );
+    call_echo(2);
+
+    let response = get_metrics();
+    assert_eq!(response.status, 200);
+    let content_type = response.find_header("content-type").unwrap();
+    assert!(String::from_utf8_lossy(content_type).starts_with("text/plain"));
+
+    let text = String::from_utf8(response.body).unwrap();
+    let labels = r#"component="echo",tool="echo_message""#;
+    assert!(text.contains(r#"ftl_gateway_requests_total{method="tools/call"} 2"#));
+    assert!(text.contains(&format!("ftl_gateway_tool_calls_total{{{labels}}} 2")));
+    assert!(!text.contains(&format!("ftl_gateway_tool_errors_total{{{labels}}}")));
+    assert!(text.contains(&format!(
+        "ftl_gateway_tool_call_duration_seconds_count{{{labels}}} 2"
+    )));
+    assert!(text.contains("# TYPE ftl_gateway_tool_request_bytes histogram"));
+}
+
+#[spin_test]
+fn test_metrics_include_auth_failures() {
+    let kv = key_value::Store::open("default");
+    kv.set("metrics:auth_failures:expired_token", b"3");
+    kv.set("metrics:requests:tools/list", b"5");
+
+    let response = get_metrics();
+    assert_eq!(response.status, 200);
+
+    let text = String::from_utf8(response.body).unwrap();
+    assert!(text.contains(r#"ftl_auth_failures_total{reason="expired_token"} 3"#));
+    assert!(text.contains(r#"ftl_gateway_requests_total{method="tools/list"} 5"#));
+}
+
+#[spin_test]
+fn test_metrics_disabled_by_default() {
+    setup_default_test_env();
+
+    let request = create_json_rpc_request("ping", None, Some(serde_json::json!(1)));
+    spin_test_sdk::perform_request(create_mcp_request(request));
+
+    let text = String::from_utf8(get_metrics().body).unwrap();
+    assert!(!text.contains("ftl_gateway_requests_total{"));
+}
@@ -0,0 +1,98 @@
+use crate::{test_helpers::*, ResponseData};
+use spin_test_sdk::{
+    bindings::{
+        fermyon::{spin_test_virt::variables, spin_wasi_virt::http_handler},
+        wasi::http,
+    },
+    spin_test,
+};
+
+// Mock a static component URL returning a body with a content type
+fn mock_static_response(url: &str, content_type: &str, body: &[u8]) {
+    let headers = http::types::Headers::new();
+    headers
+        .append("content-type", content_type.as_bytes())
+        .unwrap();
+
+    let response = http::types::OutgoingResponse::new(headers);
+    response.set_status_code(200).unwrap();
+    response.body().unwrap().write_bytes(body);
+
+    http_handler::set_response(url, http_handler::ResponseHandler::Response(response));
+}
+
+fn setup_static_env() {
+    setup_default_test_env();
+    variables::set("resource_components", "docs");
+
+    let files = serde_json::json!([
+        {"path": "guide.md", "mimeType": "text/markdown", "size": 7},
+        {"path": "data/cities.json", "mimeType": "application/json", "size": 2}
+    ]);
+    let listing = serde_json::to_vec(&files).unwrap();
+    mock_static_response("http://docs.spin.internal/", "application/json", &listing);
+    mock_static_response("http://docs.spin.internal", "application/json", &listing);
+    mock_static_response(
+        "http://docs.spin.internal/guide.md",
+        "text/markdown",
+        b"# Guide",
+    );
+}
+
+#[spin_test]
+fn test_resources_list_static_component() {
+    setup_static_env();
+
+    let request_json = create_json_rpc_request("resources/list", None, Some(serde_json::json!(1)));
+    let response = spin_test_sdk::perform_request(create_mcp_request(request_json));
+    let response_data = ResponseData::from_response(response);
+
+    assert_eq!(response_data.status, 200);
+    let response_json = response_data.body_json().expect("Expected JSON response");
+    assert_json_rpc_success(&response_json, Some(serde_json::json!(1)));
+
+    let resources = response_json["result"]["resources"].as_array().unwrap();
+    assert_eq!(resources.len(), 2);
+    assert_eq!(resources[0]["uri"], "ftl://docs/guide.md");
+    assert_eq!(resources[0]["name"], "guide.md");
+    assert_eq!(resources[0]["mimeType"], "text/markdown");
+    assert_eq!(resources[1]["uri"], "ftl://docs/data/cities.json");
+}
+
+#[spin_test]
+fn test_resources_read_static_file() {
+    setup_static_env();
+
+    let request_json = create_json_rpc_request(
+        "resources/read",
+        Some(serde_json::json!({"uri": "ftl://docs/guide.md"})),
+        Some(serde_json::json!(2)),
+    );
+    let response = spin_test_sdk::perform_request(create_mcp_request(request_json));
+    let response_data = ResponseData::from_response(response);
+
+    assert_eq!(response_data.status, 200);
+    let response_json = response_data.body_json().expect("Expected JSON response");
+    assert_json_rpc_success(&response_json, Some(serde_json::json!(2)));
+
+    let contents = &response_json["result"]["contents"][0];
+    assert_eq!(contents["uri"], "ftl://docs/guide.md");
+    assert_eq!(contents["mimeType"], "text/markdown");
+    assert_eq!(contents["text"], "# Guide");
+}
+
+#[spin_test]
+fn test_resources_read_unknown_component() {
+    setup_static_env();
+
+    let request_json = create_json_rpc_request(
+        "resources/read",
+        Some(serde_json::json!({"uri": "ftl://echo/guide.md"})),
+        Some(serde_json::json!(3)),
+    );
+    let response = spin_test_sdk::perform_request(create_mcp_request(request_json));
+    let response_data = ResponseData::from_response(response);
+
+    let response_json = response_data.body_json().expect("Expected JSON response");
+    assert_json_rpc_error(&response_json, -32002, Some(serde_json::json!(3)));
+}
@@ -0,0 +1,176 @@
+use crate::{test_helpers::*, ResponseData};
+use spin_test_sdk::{
+    bindings::{fermyon::spin_test_virt::variables, wasi::http},
+    spin_test,
+};
+
+fn send_sampling(path: &str, method: &http::types::Method, body: &[u8]) -> ResponseData {
+    let headers = http::types::Headers::new();
+    headers.append("content-type", b"application/json").unwrap();
+
+    let request = http::types::OutgoingRequest::new(headers);
+    request.set_method(method).unwrap();
+    request.set_path_with_query(Some(path)).unwrap();
+    request.body().unwrap().write_bytes(body);
+
+    ResponseData::from_response(spin_test_sdk::perform_request(request))
+}
+
+fn create_message_request() -> Vec<u8> {
+    serde_json::to_vec(&create_json_rpc_request(
+        "sampling/createMessage",
+        Some(serde_json::json!({
+            "messages": [{"role": "user", "content": {"type": "text", "text": "Summarize"}}],
+            "maxTokens": 100
+        })),
+        Some(serde_json::json!(1)),
+    ))
+    .unwrap()
+}
+
+#[spin_test]
+fn test_sampling_reply_accepted() {
+    setup_default_test_env();
+
+    let reply = serde_json::json!({
+        "jsonrpc": "2.0",
+        "id": "ftl-sampling:0a1b2c:0",
+        "result": {
+            "role": "assistant",
+            "content": {"type": "text", "text": "A summary"},
+            "model": "test-model"
+        }
+    });
+    let response = spin_test_sdk::perform_request(create_mcp_request(reply));
+    let response_data = ResponseData::from_response(response);
+
+    assert_eq!(response_data.status, 202);
+    assert!(response_data.body.is_empty());
+}
+
+#[spin_test]
+fn test_sampling_unavailable_without_stream() {
+    setup_default_test_env();
+
+    let response = send_sampling(
+        "/sampling/0a1b2c",
+        &http::types::Method::Post,
+        &create_message_request(),
+    );
+
+    assert_eq!(response.status, 200);
+    let response_json = response.body_json().expect("Expected JSON response");
+    assert_json_rpc_error(&response_json, -32001, Some(serde_json::json!(1)));
+}
+
+#[spin_test]
+fn test_elicitation_unavailable_without_stream() {
+    setup_default_test_env();
+
+    let request = create_json_rpc_request(
+        "elicitation/create",
+        Some(serde_json::json!({
+            "message": "Which city?",
+            "requestedSchema": {
+                "type": "object",
+                "properties": {"city": {"type": "string"}}
+            }
+        })),
+        Some(serde_json::json!(2)),
+    );
+    let response = send_sampling(
+        "/sampling/0a1b2c",
+        &http::types::Method::Post,
+        &serde_json::to_vec(&request).unwrap(),
+    );
+
+    let response_json = response.body_json().expect("Expected JSON response");
+    assert_json_rpc_error(&response_json, -32001, Some(serde_json::json!(2)));
+}
+
+#[spin_test]
+fn test_sampling_rejects_other_methods() {
+    setup_default_test_env();
+
+    let request = create_json_rpc_request("tools/list", None, Some(serde_json::json!(1)));
+    let response = send_sampling(
+        "/sampling/0a1b2c",
+        &http::types::Method::Post,
+        &serde_json::to_vec(&request).unwrap(),
+    );
+
+    let response_json = response.body_json().expect("Expected JSON response");
+    assert_json_rpc_error(&response_json, -32601, Some(serde_json::json!(1)));
+}
+
+#[spin_test]
+fn test_sampling_unknown_route() {
+    setup_default_test_env();
+
+    let response = send_sampling("/sampling/0a1b2c/next", &http::types::Method::Get, b"");
+    assert_eq!(response.status, 404);
+}
+
+#[spin_test]
+fn test_tool_call_streamed_to_sse_clients() {
+    setup_default_test_env();
+    variables::set("component_names", "writer");
+    mock_tool_component(
+        "writer",
+        vec![ToolMetadata {
+            name: "summarize".to_string(),
+            title: None,
+            description: Some("Summarize text".to_string()),
+            input_schema: serde_json::json!({"type": "object"}),
+            output_schema: None,
+            annotations: None,
+            meta: None,
+        }],
+    );
+    mock_tool_execution(
+        "writer",
+        "summarize",
+        ToolResponse {
+            content: vec![ToolContent::Text {
+                text: "A summary".to_string(),
+                annotations: None,
+            }],
+            structured_content: None,
+            is_error: None,
+        },
+    );
+
+    let request_json = create_json_rpc_request(
+        "tools/call",
+        Some(serde_json::json!({"name": "summarize", "arguments": {}})),
+        Some(serde_json::json!(7)),
+    );
+    let headers = http::types::Headers::new();
+    headers.append("content-type", b"application/json").unwrap();
+    headers
+        .append("accept", b"application/json, text/event-stream")
+        .unwrap();
+    let request = http::types::OutgoingRequest::new(headers);
+    request.set_method(&http::types::Method::Post).unwrap();
+    request.set_path_with_query(Some("/mcp")).unwrap();
+    request
+        .body()
+        .unwrap()
+        .write_bytes(&serde_json::to_vec(&request_json).unwrap());
+    let response_data = ResponseData::from_response(spin_test_sdk::perform_request(request));
+
+    assert_eq!(response_data.status, 200);
+    assert_eq!(
+        response_data.find_header("content-type"),
+        Some(&b"text/event-stream".to_vec())
+    );
+    let body = String::from_utf8(response_data.body).unwrap();
+    let data = body
+        .lines()
+        .filter_map(|line| line.strip_prefix("data: "))
+        .last()
+        .expect("Expected an SSE event");
+    let response_json: serde_json::Value = serde_json::from_str(data).unwrap();
+    assert_json_rpc_success(&response_json, Some(serde_json::json!(7)));
+    assert_eq!(response_json["result"]["content"][0]["text"], "A summary");
+}
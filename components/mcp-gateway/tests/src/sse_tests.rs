@@ -0,0 +1,100 @@
+use crate::{test_helpers::*, ResponseData};
+use spin_test_sdk::{
+    bindings::{fermyon::spin_test_virt::variables, wasi::http},
+    spin_test,
+};
+
+fn open_stream(path: &str) -> ResponseData {
+    let headers = http::types::Headers::new();
+    headers.append("accept", b"text/event-stream").unwrap();
+
+    let request = http::types::OutgoingRequest::new(headers);
+    request.set_method(&http::types::Method::Get).unwrap();
+    request.set_path_with_query(Some(path)).unwrap();
+
+    ResponseData::from_response(spin_test_sdk::perform_request(request))
+}
+
+fn post_message(path: &str) -> ResponseData {
+    let request_json = create_json_rpc_request("ping", None, Some(serde_json::json!(1)));
+    let headers = http::types::Headers::new();
+    headers.append("content-type", b"application/json").unwrap();
+
+    let request = http::types::OutgoingRequest::new(headers);
+    request.set_method(&http::types::Method::Post).unwrap();
+    request.set_path_with_query(Some(path)).unwrap();
+    request
+        .body()
+        .unwrap()
+        .write_bytes(&serde_json::to_vec(&request_json).unwrap());
+
+    ResponseData::from_response(spin_test_sdk::perform_request(request))
+}
+
+#[spin_test]
+fn test_sse_transport_disabled_by_default() {
+    setup_default_test_env();
+
+    let response_data = open_stream("/mcp/sse");
+    assert_eq!(response_data.status, 405);
+}
+
+#[spin_test]
+fn test_sse_stream_sends_endpoint() {
+    setup_default_test_env();
+    variables::set("sse_transport", "true");
+    // End the stream right after the endpoint event
+    variables::set("sse_session_seconds", "0");
+
+    let response_data = open_stream("/mcp/x/echo/sse");
+    assert_eq!(response_data.status, 200);
+    assert_eq!(
+        response_data.find_header("content-type"),
+        Some(&b"text/event-stream".to_vec())
+    );
+    assert_eq!(
+        response_data.find_header("access-control-allow-origin"),
+        Some(&b"*".to_vec())
+    );
+
+    let body = String::from_utf8(response_data.body).unwrap();
+    assert!(body.starts_with("event: endpoint\ndata: /mcp/x/echo/messages?session_id="));
+}
+
+#[spin_test]
+fn test_sse_stream_invalid_endpoint() {
+    setup_default_test_env();
+    variables::set("sse_transport", "true");
+
+    let response_data = open_stream("/other/sse");
+    assert_eq!(response_data.status, 405);
+}
+
+#[spin_test]
+fn test_sse_message_unknown_session() {
+    setup_default_test_env();
+    variables::set("sse_transport", "true");
+
+    let response_data = post_message("/mcp/messages?session_id=0a1b2c3d");
+    assert_eq!(response_data.status, 404);
+
+    let response_data = post_message("/mcp/messages");
+    assert_eq!(response_data.status, 404);
+}
+
+#[spin_test]
+fn test_sse_preflight_allows_get() {
+    setup_default_test_env();
+    variables::set("sse_transport", "true");
+
+    let request = http::types::OutgoingRequest::new(http::types::Headers::new());
+    request.set_method(&http::types::Method::Options).unwrap();
+    request.set_path_with_query(Some("/mcp/sse")).unwrap();
+
+    let response_data = ResponseData::from_response(spin_test_sdk::perform_request(request));
+    assert_eq!(response_data.status, 200);
+    assert_eq!(
+        response_data.find_header("access-control-allow-methods"),
+        Some(&b"GET, POST, OPTIONS".to_vec())
+    );
+}
@@ -198,3 +198,83 @@ fn test_tool_metadata_completeness() {
     assert!(schema["required"].is_array());
     assert_eq!(schema["additionalProperties"], false);
 }
+
+fn tagged_tool(name: &str, tags: &[&str]) -> ToolMetadata {
+    ToolMetadata {
+        name: name.to_string(),
+        title: None,
+        description: None,
+        input_schema: serde_json::json!({"type": "object"}),
+        output_schema: None,
+        annotations: None,
+        meta: Some(serde_json::json!({ "tags": tags })),
+    }
+}
+
+fn list_tools(params: Option<serde_json::Value>) -> serde_json::Value {
+    let request_json = create_json_rpc_request("tools/list", params, Some(serde_json::json!(1)));
+    let request = create_mcp_request(request_json);
+    let response = spin_test_sdk::perform_request(request);
+    let response_data = ResponseData::from_response(response);
+
+    assert_eq!(response_data.status, 200);
+    response_data.body_json().expect("Expected JSON response")
+}
+
+fn tool_names(response_json: &serde_json::Value) -> Vec<String> {
+    response_json["result"]["tools"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|t| t["name"].as_str().unwrap().to_string())
+        .collect()
+}
+
+#[spin_test]
+fn test_list_tools_filtered() {
+    variables::set("component_names", "text,math");
+    variables::set("validate_arguments", "true");
+
+    mock_tool_component(
+        "text",
+        vec![
+            tagged_tool("search", &["read", "search"]),
+            tagged_tool("replace", &["write"]),
+        ],
+    );
+    mock_tool_component("math", vec![tagged_tool("add", &["read"])]);
+
+    let by_component = list_tools(Some(serde_json::json!({"filter": {"component": "math"}})));
+    assert_eq!(tool_names(&by_component), ["math__add"]);
+
+    let by_tag = list_tools(Some(serde_json::json!({"filter": {"tags": ["read"]}})));
+    assert_eq!(tool_names(&by_tag), ["text__search", "math__add"]);
+
+    let by_prefix = list_tools(Some(serde_json::json!({"filter": {"prefix": "text__re"}})));
+    assert_eq!(tool_names(&by_prefix), ["text__replace"]);
+}
+
+#[spin_test]
+fn test_list_tools_paginated() {
+    variables::set("component_names", "text,math");
+    variables::set("validate_arguments", "true");
+    variables::set("tools_page_size", "2");
+
+    mock_tool_component(
+        "text",
+        vec![tagged_tool("search", &[]), tagged_tool("replace", &[])],
+    );
+    mock_tool_component("math", vec![tagged_tool("add", &[])]);
+
+    let first = list_tools(None);
+    assert_eq!(tool_names(&first), ["math__add", "text__replace"]);
+    let cursor = first["result"]["nextCursor"].clone();
+    assert!(cursor.is_string());
+
+    let second = list_tools(Some(serde_json::json!({ "cursor": cursor })));
+    assert_eq!(tool_names(&second), ["text__search"]);
+    assert!(second["result"].get("nextCursor").is_none());
+
+    let invalid = list_tools(Some(serde_json::json!({"cursor": "not-a-cursor"})));
+    assert_json_rpc_error(&invalid, -32602, Some(serde_json::json!(1)));
+}
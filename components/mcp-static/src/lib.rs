@@ -0,0 +1,235 @@
+//! Static resources component
+//!
+//! Serves the files mounted into the component to the MCP gateway, which
+//! exposes them as resources:
+//!
+//! - `GET /` lists the files as a JSON array of `{"path", "mimeType", "size"}`
+//! - `GET /<path>` returns a file, with its MIME type as `Content-Type`;
+//!   the gateway percent-encodes each segment of the path
+//!
+//! FTL mounts the `dir` of a `type: static` component at `/`.
+
+use std::fs;
+use std::path::{Component, Path, PathBuf};
+
+use serde::Serialize;
+use spin_sdk::http::{IntoResponse, Method, Request, Response};
+use spin_sdk::http_component;
+
+/// Where the component's files are mounted
+const FILES_ROOT: &str = "/";
+
+/// A file as listed for the gateway
+#[derive(Debug, Clone, PartialEq, Eq, Serialize)]
+struct StaticFile {
+    path: String,
+    #[serde(rename = "mimeType")]
+    mime_type: &'static str,
+    size: u64,
+}
+
+#[http_component]
+fn handle_static(req: Request) -> anyhow::Result<impl IntoResponse> {
+    if *req.method() != Method::Get {
+        return Ok(Response::builder()
+            .status(405)
+            .header("Allow", "GET")
+            .body("Method not allowed")
+            .build());
+    }
+
+    let path = req.path().trim_start_matches('/');
+    if path.is_empty() {
+        let files = list_files(Path::new(FILES_ROOT));
+        return Ok(Response::builder()
+            .status(200)
+            .header("Content-Type", "application/json")
+            .body(serde_json::to_vec(&files)?)
+            .build());
+    }
+
+    let Some(file) = decode_path(path).and_then(|path| resolve(Path::new(FILES_ROOT), &path))
+    else {
+        return Ok(not_found());
+    };
+    match fs::read(&file) {
+        Ok(body) => Ok(Response::builder()
+            .status(200)
+            .header("Content-Type", mime_type(&file))
+            .body(body)
+            .build()),
+        Err(_) => Ok(not_found()),
+    }
+}
+
+fn not_found() -> Response {
+    Response::builder()
+        .status(404)
+        .header("Content-Type", "text/plain")
+        .body("Not found")
+        .build()
+}
+
+/// The file a request path refers to, or `None` when the path leaves the
+/// root or names a hidden file, which are not listed either
+fn resolve(root: &Path, path: &str) -> Option<PathBuf> {
+    let relative = Path::new(path);
+    if relative.components().any(|component| match component {
+        Component::Normal(name) => name.to_string_lossy().starts_with('.'),
+        _ => true,
+    }) {
+        return None;
+    }
+    Some(root.join(relative))
+}
+
+/// Decode the percent-encoding of a request path, or `None` when it is
+/// malformed
+fn decode_path(path: &str) -> Option<String> {
+    let bytes = path.as_bytes();
+    let mut decoded = Vec::with_capacity(bytes.len());
+    let mut i = 0;
+    while let Some(&byte) = bytes.get(i) {
+        if byte == b'%' {
+            let hex = bytes.get(i + 1..i + 3)?;
+            if !hex.iter().all(u8::is_ascii_hexdigit) {
+                return None;
+            }
+            decoded.push(u8::from_str_radix(std::str::from_utf8(hex).ok()?, 16).ok()?);
+            i += 3;
+        } else {
+            decoded.push(byte);
+            i += 1;
+        }
+    }
+    String::from_utf8(decoded).ok()
+}
+
+/// Every file under root, sorted by path. Hidden files and directories
+/// are skipped.
+fn list_files(root: &Path) -> Vec<StaticFile> {
+    let mut files = Vec::new();
+    collect_files(root, root, &mut files);
+    files.sort_by(|a, b| a.path.cmp(&b.path));
+    files
+}
+
+fn collect_files(root: &Path, dir: &Path, files: &mut Vec<StaticFile>) {
+    let Ok(entries) = fs::read_dir(dir) else {
+        return;
+    };
+    for entry in entries.flatten() {
+        if entry.file_name().to_string_lossy().starts_with('.') {
+            continue;
+        }
+        let path = entry.path();
+        let Ok(metadata) = entry.metadata() else {
+            continue;
+        };
+        if metadata.is_dir() {
+            collect_files(root, &path, files);
+        } else if let Ok(relative) = path.strip_prefix(root) {
+            files.push(StaticFile {
+                path: relative.to_string_lossy().replace('\\', "/"),
+                mime_type: mime_type(&path),
+                size: metadata.len(),
+            });
+        }
+    }
+}
+
+/// The MIME type of a file, from its extension
+fn mime_type(path: &Path) -> &'static str {
+    let extension = path
+        .extension()
+        .map(|ext| ext.to_string_lossy().to_ascii_lowercase())
+        .unwrap_or_default();
+    match extension.as_str() {
+        "md" | "markdown" => "text/markdown",
+        "txt" | "text" => "text/plain",
+        "csv" => "text/csv",
+        "html" | "htm" => "text/html",
+        "css" => "text/css",
+        "json" => "application/json",
+        "jsonl" | "ndjson" => "application/x-ndjson",
+        "yaml" | "yml" => "application/yaml",
+        "toml" => "application/toml",
+        "xml" => "application/xml",
+        "pdf" => "application/pdf",
+        "png" => "image/png",
+        "jpg" | "jpeg" => "image/jpeg",
+        "gif" => "image/gif",
+        "svg" => "image/svg+xml",
+        "webp" => "image/webp",
+        _ => "application/octet-stream",
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn resolves_paths_under_root() {
+        assert_eq!(
+            resolve(Path::new("/"), "guide/intro.md"),
+            Some(PathBuf::from("/guide/intro.md"))
+        );
+        assert_eq!(resolve(Path::new("/"), "../etc/passwd"), None);
+        assert_eq!(resolve(Path::new("/"), "guide/../../etc/passwd"), None);
+        assert_eq!(resolve(Path::new("/"), "./guide.md"), None);
+        assert_eq!(resolve(Path::new("/"), ".env"), None);
+        assert_eq!(resolve(Path::new("/"), ".git/config"), None);
+        assert_eq!(resolve(Path::new("/"), "guide/.hidden/notes.md"), None);
+    }
+
+    #[test]
+    fn decodes_paths() {
+        assert_eq!(
+            decode_path("my%20notes/caf%C3%A9.md").as_deref(),
+            Some("my notes/café.md")
+        );
+        assert_eq!(decode_path("plain.md").as_deref(), Some("plain.md"));
+        assert_eq!(decode_path("bad%2").as_deref(), None);
+        assert_eq!(decode_path("bad%zz").as_deref(), None);
+        assert_eq!(decode_path("bad%+1").as_deref(), None);
+    }
+
+    #[test]
+    fn guesses_mime_types() {
+        assert_eq!(mime_type(Path::new("README.md")), "text/markdown");
+        assert_eq!(mime_type(Path::new("data/cities.JSON")), "application/json");
+        assert_eq!(mime_type(Path::new("logo.png")), "image/png");
+        assert_eq!(mime_type(Path::new("archive")), "application/octet-stream");
+    }
+
+    #[test]
+    fn lists_files_recursively() {
+        let root = std::env::temp_dir().join(format!("mcp-static-{}", std::process::id()));
+        let _ = fs::remove_dir_all(&root);
+        fs::create_dir_all(root.join("data")).unwrap();
+        fs::create_dir_all(root.join(".git")).unwrap();
+        fs::write(root.join("guide.md"), "# Guide").unwrap();
+        fs::write(root.join("data/cities.json"), "[]").unwrap();
+        fs::write(root.join(".git/HEAD"), "ref").unwrap();
+
+        let files = list_files(&root);
+        fs::remove_dir_all(&root).unwrap();
+
+        assert_eq!(
+            files,
+            vec![
+                StaticFile {
+                    path: "data/cities.json".to_string(),
+                    mime_type: "application/json",
+                    size: 2,
+                },
+                StaticFile {
+                    path: "guide.md".to_string(),
+                    mime_type: "text/markdown",
+                    size: 7,
+                },
+            ]
+        );
+    }
+}
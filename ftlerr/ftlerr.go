@@ -0,0 +1,162 @@
+// Package ftlerr defines the structured errors shared by the FTL CLI and
+// its libraries. An Error carries a stable machine-readable code, a
+// user-facing message, a hint on how to fix the problem and the
+// underlying cause, so failures print consistent, actionable messages and
+// structured output can report the code.
+package ftlerr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Code identifies a class of failure. Codes are part of the CLI's
+// machine-readable output and must not change once released.
+type Code string
+
+const (
+	// NotLoggedIn means no credentials are stored or configured
+	NotLoggedIn Code = "not_logged_in"
+	// SessionExpired means the stored credentials can no longer be refreshed
+	SessionExpired Code = "session_expired"
+	// AuthFailed means credentials were rejected
+	AuthFailed Code = "auth_failed"
+	// PermissionDenied means the caller may not perform the operation
+	PermissionDenied Code = "permission_denied"
+	// NotFound means the requested resource does not exist
+	NotFound Code = "not_found"
+	// Conflict means the request conflicts with the resource's state
+	Conflict Code = "conflict"
+	// InvalidRequest means the platform rejected the request as malformed
+	InvalidRequest Code = "invalid_request"
+	// RateLimited means too many requests were made
+	RateLimited Code = "rate_limited"
+	// Unavailable means the platform failed or could not be reached
+	Unavailable Code = "unavailable"
+	// APIError is any other unexpected platform response
+	APIError Code = "api_error"
+	// RegistryAuthFailed means registry credentials could not be used
+	RegistryAuthFailed Code = "registry_auth_failed"
+	// RegistryPullFailed means a component could not be pulled
+	RegistryPullFailed Code = "registry_pull_failed"
+	// RegistryPushFailed means a component could not be pushed
+	RegistryPushFailed Code = "registry_push_failed"
+	// DeployFailed means the platform reported a failed deployment
+	DeployFailed Code = "deploy_failed"
+	// DeployTimeout means a deployment did not finish in time
+	DeployTimeout Code = "deploy_timeout"
+	// InputRequired means a command needed an answer it could not prompt
+	// for in non-interactive mode
+	InputRequired Code = "input_required"
+)
+
+// Error is a failure with a code, a user-facing message, an optional
+// remediation hint and an optional wrapped cause
+type Error struct {
+	Code    Code
+	Message string
+	Hint    string
+	Err     error
+}
+
+// New returns an Error with the given code and message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap returns an Error with the given code and message caused by err
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// WithHint sets the remediation hint and returns e
+func (e *Error) WithHint(hint string) *Error {
+	e.Hint = hint
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// CodeOf returns the code of the outermost Error in err's chain, or ""
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// HintOf returns the first remediation hint in err's chain, or ""
+func HintOf(err error) string {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Hint != "" {
+			return e.Hint
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// IsAuth reports whether err is an authentication or authorization failure
+func IsAuth(err error) bool {
+	switch CodeOf(err) {
+	case NotLoggedIn, SessionExpired, AuthFailed, PermissionDenied:
+		return true
+	}
+	return false
+}
+
+// Login hints shared by authentication failures
+const (
+	LoginHint   = "Run 'ftl auth login' to log in, or set FTL_CLIENT_ID and FTL_CLIENT_SECRET for machine access"
+	ReloginHint = "Run 'ftl auth login' to log in again"
+)
+
+// FromStatus classifies a failed HTTP response from the FTL platform. The
+// message is prefixed to the error returned by the platform, which is read
+// from a JSON error body when present.
+func FromStatus(status int, body []byte, message string) *Error {
+	detail := strings.TrimSpace(string(body))
+	var resp struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &resp) == nil {
+		if resp.Message != "" {
+			detail = resp.Message
+		} else if resp.Error != "" {
+			detail = resp.Error
+		}
+	}
+	if detail == "" {
+		detail = http.StatusText(status)
+	}
+
+	e := New(APIError, message+": "+detail)
+	switch {
+	case status == http.StatusUnauthorized:
+		e.Code, e.Hint = AuthFailed, ReloginHint
+	case status == http.StatusForbidden:
+		e.Code, e.Hint = PermissionDenied, "Check that you are a member of the organization that owns the app ('ftl org list')"
+	case status == http.StatusNotFound:
+		e.Code, e.Hint = NotFound, "Run 'ftl list' to see your apps"
+	case status == http.StatusConflict:
+		e.Code = Conflict
+	case status == http.StatusTooManyRequests:
+		e.Code, e.Hint = RateLimited, "Wait a moment and try again"
+	case status >= 500:
+		e.Code, e.Hint = Unavailable, "The FTL platform is having problems; try again shortly"
+	case status >= 400:
+		e.Code = InvalidRequest
+	}
+	return e
+}
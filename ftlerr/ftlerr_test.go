@@ -0,0 +1,58 @@
+package ftlerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := Wrap(cause, RegistryPullFailed, "failed to pull ghcr.io/acme/tool:1.0.0").WithHint("Check the registry")
+
+	assert.Equal(t, "failed to pull ghcr.io/acme/tool:1.0.0: connection reset", err.Error())
+	assert.ErrorIs(t, err, cause)
+
+	wrapped := fmt.Errorf("add component: %w", err)
+	assert.Equal(t, RegistryPullFailed, CodeOf(wrapped))
+	assert.Equal(t, "Check the registry", HintOf(wrapped))
+	assert.False(t, IsAuth(wrapped))
+
+	assert.Equal(t, Code(""), CodeOf(cause))
+	assert.Empty(t, HintOf(cause))
+}
+
+func TestHintOf_Inner(t *testing.T) {
+	inner := New(NotLoggedIn, "not logged in").WithHint(LoginHint)
+	outer := Wrap(inner, SessionExpired, "failed to refresh token")
+
+	assert.Equal(t, SessionExpired, CodeOf(outer))
+	assert.Equal(t, LoginHint, HintOf(outer))
+	assert.True(t, IsAuth(outer))
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		code    Code
+		message string
+	}{
+		{http.StatusUnauthorized, `{"error":"token expired"}`, AuthFailed, "API error: token expired"},
+		{http.StatusForbidden, `{"message":"not a member"}`, PermissionDenied, "API error: not a member"},
+		{http.StatusNotFound, "no such app", NotFound, "API error: no such app"},
+		{http.StatusConflict, "", Conflict, "API error: Conflict"},
+		{http.StatusBadRequest, `{"error":"bad name"}`, InvalidRequest, "API error: bad name"},
+		{http.StatusTooManyRequests, "", RateLimited, "API error: Too Many Requests"},
+		{http.StatusBadGateway, "upstream", Unavailable, "API error: upstream"},
+		{http.StatusFound, "", APIError, "API error: Found"},
+	}
+	for _, tt := range tests {
+		err := FromStatus(tt.status, []byte(tt.body), "API error")
+		assert.Equal(t, tt.code, err.Code, "status %d", tt.status)
+		assert.Equal(t, tt.message, err.Error(), "status %d", tt.status)
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import "context"
+
+// FTLAPI is the FTL platform API used by the CLI. FTLClient implements it
+// against the platform; apitest.Fake implements it in memory for tests.
+type FTLAPI interface {
+	// Apps
+	ListApps(ctx context.Context, params *ListAppsParams) (*ListAppsResponseBody, error)
+	CreateApp(ctx context.Context, request CreateAppRequest) (*CreateAppResponseBody, error)
+	GetApp(ctx context.Context, appID string) (*App, error)
+	DeleteApp(ctx context.Context, appID string) error
+
+	// Components and deployments
+	ListAppComponents(ctx context.Context, appID string, params *ListAppComponentsParams) (*ListComponentsResponseBody, error)
+	UpdateComponents(ctx context.Context, appID string, request UpdateComponentsRequest) (*UpdateComponentsResponseBody, error)
+	CreateDeployCredentials(ctx context.Context, appID string, components []string) (*CreateDeployCredentialsResponseBody, error)
+
+	// Users and organizations
+	GetUserInfo(ctx context.Context) (*GetUserInfoResponseBody, error)
+}
+
+var _ FTLAPI = (*FTLClient)(nil)
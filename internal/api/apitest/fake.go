@@ -0,0 +1,468 @@
+// Package apitest provides an in-memory FTL platform for tests. Fake
+// implements api.FTLAPI without any network; Server serves a Fake over HTTP
+// together with an OCI registry and a deployment endpoint, so commands that
+// push components and stream deployments can run end to end.
+package apitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/internal/api"
+)
+
+// Fake is an in-memory FTL platform. The exported fields configure the
+// actor deploying and must be set before the fake is used.
+type Fake struct {
+	// UserID, UserName and UserEmail identify the logged-in user
+	UserID    string
+	UserName  string
+	UserEmail string
+
+	// ActorType is "user" or "machine"
+	ActorType api.CreateDeployCredentialsResponseBodyDeploymentContextActorType
+
+	// OrgIDs are the organizations the actor can deploy to
+	OrgIDs []string
+
+	// RegistryURI and FunctionURL are handed out with deploy credentials.
+	// Server points them at itself.
+	RegistryURI string
+	FunctionURL string
+
+	mu          sync.Mutex
+	apps        []*fakeApp
+	deployments []Deployment
+}
+
+// fakeApp is an app with everything stored about it
+type fakeApp struct {
+	app        api.App
+	components []api.ListedComponent
+}
+
+// Deployment is a deployment received by the fake platform
+type Deployment struct {
+	ID    string
+	AppID string
+
+	// Request is the deployment request sent by the CLI
+	Request map[string]interface{}
+
+	// Query options of the deployment
+	Environment string
+	Region      string
+
+	// OrgID is the organization selected for org-scoped apps
+	OrgID string
+}
+
+// NewFake returns an empty platform with a user who belongs to one
+// organization
+func NewFake() *Fake {
+	return &Fake{
+		UserID:      "user_test",
+		UserName:    "Test User",
+		UserEmail:   "test@example.com",
+		ActorType:   api.User,
+		OrgIDs:      []string{"org_test"},
+		RegistryURI: "123456789012.dkr.ecr.us-west-2.amazonaws.com",
+		FunctionURL: "https://deploy.lambda-url.us-west-2.on.aws",
+	}
+}
+
+// AddApp stores an app as if it had been created earlier
+func (f *Fake) AddApp(name string, access api.AppAccessControl) api.App {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addApp(name, access).app
+}
+
+// Apps returns every app, deleted ones included, in creation order
+func (f *Fake) Apps() []api.App {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	apps := make([]api.App, 0, len(f.apps))
+	for _, a := range f.apps {
+		apps = append(apps, a.app)
+	}
+	return apps
+}
+
+// Deployments returns the deployments received, oldest first
+func (f *Fake) Deployments() []Deployment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Deployment(nil), f.deployments...)
+}
+
+// Deploy records a deployment of an app and makes it current: the app is
+// marked deployed with the request's access mode
+func (f *Fake) Deploy(d Deployment) (Deployment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.find(d.AppID)
+	if a == nil {
+		return Deployment{}, notFound("app", d.AppID)
+	}
+
+	d.ID = "dep_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+	f.deployments = append(f.deployments, d)
+
+	now := float32(time.Now().Unix())
+	environment := d.Environment
+	if environment == "" {
+		environment = "production"
+	}
+	url := fmt.Sprintf("https://%s.ftl.example.com", a.app.AppName)
+	a.app.Status = api.AppStatusACTIVE
+	a.app.ProviderUrl = &url
+	a.app.UpdatedAt = timestamp()
+	a.app.LatestDeployment = &struct {
+		CreatedAt          *float32                      `json:"createdAt,omitempty"`
+		DeployedAt         *float32                      `json:"deployedAt,omitempty"`
+		DeploymentDuration *float32                      `json:"deploymentDuration,omitempty"`
+		DeploymentId       string                        `json:"deploymentId"`
+		Environment        *string                       `json:"environment,omitempty"`
+		Status             api.AppLatestDeploymentStatus `json:"status"`
+		StatusMessage      *string                       `json:"statusMessage,omitempty"`
+	}{
+		CreatedAt:    &now,
+		DeployedAt:   &now,
+		DeploymentId: d.ID,
+		Environment:  &environment,
+		Status:       api.AppLatestDeploymentStatusDeployed,
+	}
+	if access, ok := d.Request["access"].(string); ok && access != "" {
+		control := api.AppAccessControl(access)
+		a.app.AccessControl = &control
+	}
+	if d.OrgID != "" {
+		orgID := d.OrgID
+		a.app.OrgId = &orgID
+	}
+	return d, nil
+}
+
+// ListApps lists apps, filtered by name and paginated like the platform
+func (f *Fake) ListApps(ctx context.Context, params *api.ListAppsParams) (*api.ListAppsResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if params == nil {
+		params = &api.ListAppsParams{}
+	}
+
+	var matching []api.App
+	for _, a := range f.apps {
+		if a.app.Status == api.AppStatusDELETED && (params.IncludeDeleted == nil || *params.IncludeDeleted != api.True) {
+			continue
+		}
+		if params.Name != nil && !strings.Contains(a.app.AppName, *params.Name) {
+			continue
+		}
+		matching = append(matching, a.app)
+	}
+
+	page, next, err := paginate(matching, params.Limit, params.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	resp := &api.ListAppsResponseBody{NextToken: next}
+	if err := convert(page, &resp.Apps); err != nil {
+		return nil, err
+	}
+	if resp.Apps == nil {
+		resp.Apps = []api.ListedApp{}
+	}
+	return resp, nil
+}
+
+// CreateApp creates an app, failing when the name is taken
+func (f *Fake) CreateApp(ctx context.Context, request api.CreateAppRequest) (*api.CreateAppResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if request.AppName == "" {
+		return nil, apiError(http.StatusBadRequest, "appName is required")
+	}
+	for _, a := range f.apps {
+		if a.app.AppName == request.AppName && a.app.Status != api.AppStatusDELETED {
+			return nil, apiError(http.StatusConflict, fmt.Sprintf("app %s already exists", request.AppName))
+		}
+	}
+
+	access := api.AppAccessControlPublic
+	if request.AccessControl != nil {
+		access = api.AppAccessControl(*request.AccessControl)
+	}
+	app := f.addApp(request.AppName, access).app
+	return &api.CreateAppResponseBody{
+		AppId:     app.AppId,
+		AppName:   app.AppName,
+		CreatedAt: app.CreatedAt,
+		UpdatedAt: app.UpdatedAt,
+		Status:    api.CreateAppResponseBodyStatus(app.Status),
+	}, nil
+}
+
+// GetApp returns an app by ID
+func (f *Fake) GetApp(ctx context.Context, appID string) (*api.App, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.find(appID)
+	if a == nil {
+		return nil, notFound("app", appID)
+	}
+	app := a.app
+	return &app, nil
+}
+
+// DeleteApp marks an app deleted
+func (f *Fake) DeleteApp(ctx context.Context, appID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.find(appID)
+	if a == nil {
+		return notFound("app", appID)
+	}
+	a.app.Status = api.AppStatusDELETED
+	a.app.UpdatedAt = timestamp()
+	return nil
+}
+
+// ListAppComponents lists one page of an app's components
+func (f *Fake) ListAppComponents(ctx context.Context, appID string, params *api.ListAppComponentsParams) (*api.ListComponentsResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.find(appID)
+	if a == nil {
+		return nil, notFound("app", appID)
+	}
+	if params == nil {
+		params = &api.ListAppComponentsParams{}
+	}
+
+	page, next, err := paginate(a.components, params.Limit, params.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		page = []api.ListedComponent{}
+	}
+	return &api.ListComponentsResponseBody{
+		AppId:      a.app.AppId,
+		AppName:    a.app.AppName,
+		Components: page,
+		NextToken:  next,
+	}, nil
+}
+
+// UpdateComponents replaces the components of an app, reporting what changed
+func (f *Fake) UpdateComponents(ctx context.Context, appID string, request api.UpdateComponentsRequest) (*api.UpdateComponentsResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.find(appID)
+	if a == nil {
+		return nil, notFound("app", appID)
+	}
+
+	resp := &api.UpdateComponentsResponseBody{}
+	resp.Changes.Created, resp.Changes.Updated, resp.Changes.Removed = []string{}, []string{}, []string{}
+	existing := map[string]bool{}
+	for _, c := range a.components {
+		existing[c.ComponentName] = true
+	}
+
+	components := make([]api.ListedComponent, 0, len(request.Components))
+	wanted := map[string]bool{}
+	for _, c := range request.Components {
+		wanted[c.ComponentName] = true
+		if existing[c.ComponentName] {
+			resp.Changes.Updated = append(resp.Changes.Updated, c.ComponentName)
+		} else {
+			resp.Changes.Created = append(resp.Changes.Created, c.ComponentName)
+		}
+		components = append(components, f.component(a, c.ComponentName, c.Description))
+	}
+	for _, c := range a.components {
+		if !wanted[c.ComponentName] {
+			resp.Changes.Removed = append(resp.Changes.Removed, c.ComponentName)
+		}
+	}
+
+	a.components = components
+	resp.Components = components
+	return resp, nil
+}
+
+// CreateDeployCredentials hands out credentials for the fake registry and
+// deployment endpoint, creating a repository for each component
+func (f *Fake) CreateDeployCredentials(ctx context.Context, appID string, components []string) (*api.CreateDeployCredentialsResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.find(appID)
+	if a == nil {
+		return nil, notFound("app", appID)
+	}
+
+	existing := map[string]bool{}
+	for _, c := range a.components {
+		existing[c.ComponentName] = true
+	}
+	for _, name := range components {
+		if !existing[name] {
+			a.components = append(a.components, f.component(a, name, nil))
+		}
+	}
+
+	expires := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	creds := &api.CreateDeployCredentialsResponseBody{AppId: a.app.AppId}
+	creds.Registry.RegistryUri = f.RegistryURI
+	creds.Registry.ProxyEndpoint = "https://" + f.RegistryURI
+	creds.Registry.PackageNamespace = a.app.AppId.String()
+	creds.Registry.Region = "us-west-2"
+	creds.Registry.ExpiresAt = expires
+	// base64("AWS:password"), the format of ECR authorization tokens
+	creds.Registry.AuthorizationToken = "QVdTOnBhc3N3b3Jk"
+
+	creds.Deployment.FunctionUrl = strings.TrimSuffix(f.FunctionURL, "/") + "/" + a.app.AppId.String()
+	creds.Deployment.Credentials.AccessKeyId = "AKIAFAKE"
+	creds.Deployment.Credentials.SecretAccessKey = "fake-secret"
+	creds.Deployment.Credentials.SessionToken = "fake-session"
+	creds.Deployment.Credentials.ExpiresAt = expires
+	creds.Deployment.Context.ActorType = f.ActorType
+	creds.Deployment.Context.OrgIds = append([]string{}, f.OrgIDs...)
+	if f.ActorType != api.Machine {
+		creds.Deployment.Context.UserId = f.UserID
+	}
+	return creds, nil
+}
+
+// GetUserInfo returns the user and the organizations they belong to
+func (f *Fake) GetUserInfo(ctx context.Context) (*api.GetUserInfoResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info := &api.GetUserInfoResponseBody{}
+	info.User.Id = f.UserID
+	if f.UserName != "" {
+		name := f.UserName
+		info.User.Name = &name
+	}
+	if f.UserEmail != "" {
+		email := f.UserEmail
+		info.User.Email = &email
+	}
+	for _, id := range f.OrgIDs {
+		info.Organizations = append(info.Organizations, struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		}{Id: id, Name: id})
+	}
+	return info, nil
+}
+
+func (f *Fake) addApp(name string, access api.AppAccessControl) *fakeApp {
+	now := timestamp()
+	a := &fakeApp{
+		app: api.App{
+			AppId:         uuid.New(),
+			AppName:       name,
+			AccessControl: &access,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			Status:        api.AppStatusACTIVE,
+		},
+	}
+	if access == api.AppAccessControlOrg && len(f.OrgIDs) > 0 {
+		orgID := f.OrgIDs[0]
+		a.app.OrgId = &orgID
+	}
+	f.apps = append(f.apps, a)
+	return a
+}
+
+func (f *Fake) find(appID string) *fakeApp {
+	for _, a := range f.apps {
+		if a.app.AppId.String() == appID {
+			return a
+		}
+	}
+	return nil
+}
+
+func (f *Fake) component(a *fakeApp, name string, description *string) api.ListedComponent {
+	repository := a.app.AppId.String() + "/" + name
+	uri := f.RegistryURI + "/" + repository
+	return api.ListedComponent{
+		ComponentName:  name,
+		Description:    description,
+		RepositoryName: &repository,
+		RepositoryUri:  &uri,
+	}
+}
+
+func (f *Fake) inOrg(orgID string) bool {
+	for _, id := range f.OrgIDs {
+		if id == orgID {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate returns the page of items starting at the cursor, which is the
+// offset of its first item
+func paginate[T any](items []T, limit *int, cursor *string) ([]T, *string, error) {
+	start := 0
+	if cursor != nil && *cursor != "" {
+		n, err := strconv.Atoi(*cursor)
+		if err != nil || n < 0 || n > len(items) {
+			return nil, nil, apiError(http.StatusBadRequest, fmt.Sprintf("invalid nextToken %q", *cursor))
+		}
+		start = n
+	}
+	end := len(items)
+	if limit != nil && *limit > 0 && start+*limit < end {
+		end = start + *limit
+	}
+
+	var next *string
+	if end < len(items) {
+		token := strconv.Itoa(end)
+		next = &token
+	}
+	return items[start:end], next, nil
+}
+
+// convert copies between the generated types that describe the same JSON
+func convert(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// apiError fails like the platform does with the given status
+func apiError(status int, message string) error {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	return ftlerr.FromStatus(status, body, "API error")
+}
+
+func notFound(kind, id string) error {
+	return apiError(http.StatusNotFound, fmt.Sprintf("%s %s not found", kind, id))
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+var _ api.FTLAPI = (*Fake)(nil)
@@ -0,0 +1,278 @@
+package apitest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+// Server serves a Fake over HTTP like the FTL platform: the REST API under
+// /v1, an OCI registry components are pushed to under /v2, and the
+// streaming deployment endpoint deploy credentials point at.
+type Server struct {
+	*Fake
+
+	// URL is the base URL of the server, to use as FTL_API_URL
+	URL string
+
+	server *httptest.Server
+}
+
+// NewServer starts a Server with an empty Fake, closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{Fake: NewFake()}
+	s.server = httptest.NewServer(s.routes())
+	t.Cleanup(s.server.Close)
+
+	s.URL = s.server.URL
+	s.RegistryURI = strings.TrimPrefix(s.URL, "http://")
+	s.FunctionURL = s.URL + "/deploy"
+	return s
+}
+
+// Client returns an API client for the server, authenticated as the user
+func (s *Server) Client(t testing.TB) *api.FTLClient {
+	t.Helper()
+	client, err := api.NewFTLClient(s.AuthManager(), s.URL)
+	if err != nil {
+		t.Fatalf("failed to create API client: %v", err)
+	}
+	return client
+}
+
+// AuthManager returns an auth manager holding a valid token for the server
+func (s *Server) AuthManager() *auth.Manager {
+	expires := time.Now().Add(time.Hour)
+	store := auth.NewMockStore(&auth.Credentials{
+		AccessToken: "test-token",
+		ExpiresAt:   &expires,
+	}, nil)
+	return auth.NewManager(store, nil)
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/v2/", registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	mux.HandleFunc("POST /deploy/{appId}", s.deploy)
+
+	mux.HandleFunc("GET /v1/apps", s.authorized(s.listApps))
+	mux.HandleFunc("POST /v1/apps", s.authorized(s.createApp))
+	mux.HandleFunc("GET /v1/apps/{appId}", s.authorized(s.getApp))
+	mux.HandleFunc("DELETE /v1/apps/{appId}", s.authorized(s.deleteApp))
+	mux.HandleFunc("GET /v1/apps/{appId}/components", s.authorized(s.listComponents))
+	mux.HandleFunc("PUT /v1/apps/{appId}/components", s.authorized(s.updateComponents))
+	mux.HandleFunc("POST /v1/apps/{appId}/deploy-credentials", s.authorized(s.deployCredentials))
+	mux.HandleFunc("GET /v1/user/info", s.authorized(s.userInfo))
+	return mux
+}
+
+// authorized rejects API calls without a bearer token
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing bearer token"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := &api.ListAppsParams{}
+	if name := query.Get("name"); name != "" {
+		params.Name = &name
+	}
+	if deleted := query.Get("includeDeleted"); deleted != "" {
+		include := api.ListAppsParamsIncludeDeleted(deleted)
+		params.IncludeDeleted = &include
+	}
+	if token := query.Get("nextToken"); token != "" {
+		params.NextToken = &token
+	}
+	if limit := query.Get("limit"); limit != "" {
+		var n int
+		if _, err := fmt.Sscan(limit, &n); err != nil {
+			writeError(w, apiError(http.StatusBadRequest, "invalid limit"))
+			return
+		}
+		params.Limit = &n
+	}
+	respond(w, http.StatusOK)(s.ListApps(r.Context(), params))
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateAppRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	respond(w, http.StatusCreated)(s.CreateApp(r.Context(), req))
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK)(s.GetApp(r.Context(), r.PathValue("appId")))
+}
+
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request) {
+	if err := s.DeleteApp(r.Context(), r.PathValue("appId")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) listComponents(w http.ResponseWriter, r *http.Request) {
+	params := &api.ListAppComponentsParams{}
+	if token := r.URL.Query().Get("nextToken"); token != "" {
+		params.NextToken = &token
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var n int
+		if _, err := fmt.Sscan(limit, &n); err != nil {
+			writeError(w, apiError(http.StatusBadRequest, "invalid limit"))
+			return
+		}
+		params.Limit = &n
+	}
+	respond(w, http.StatusOK)(s.ListAppComponents(r.Context(), r.PathValue("appId"), params))
+}
+
+func (s *Server) updateComponents(w http.ResponseWriter, r *http.Request) {
+	var req api.UpdateComponentsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	respond(w, http.StatusOK)(s.UpdateComponents(r.Context(), r.PathValue("appId"), req))
+}
+
+func (s *Server) deployCredentials(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateDeployCredentialsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	var components []string
+	if req.Components != nil {
+		components = *req.Components
+	}
+	respond(w, http.StatusOK)(s.CreateDeployCredentials(r.Context(), r.PathValue("appId"), components))
+}
+
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK)(s.GetUserInfo(r.Context()))
+}
+
+// deploy serves the deployment function: it records the deployment and
+// streams its progress as NDJSON, like the platform
+func (s *Server) deploy(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		writeJSON(w, http.StatusForbidden, map[string]string{"message": "request is not signed"})
+		return
+	}
+	var req map[string]interface{}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	query := r.URL.Query()
+	d, err := s.Deploy(Deployment{
+		AppID:       r.PathValue("appId"),
+		Request:     req,
+		Environment: query.Get("environment"),
+		Region:      query.Get("region"),
+		OrgID:       r.Header.Get("X-FTL-Org-ID"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	now := time.Now().UnixMilli()
+	events := []map[string]interface{}{
+		{"type": "progress", "message": "Deployment accepted", "deploymentId": d.ID, "timestamp": now},
+		{"type": "stage", "stage": "deployed", "message": "Application deployed", "deploymentId": d.ID, "timestamp": now},
+		{"type": "complete", "message": "Deployment complete", "deploymentId": d.ID, "url": s.deploymentURL(d.AppID), "timestamp": now},
+	}
+	for _, event := range events {
+		_ = enc.Encode(event)
+	}
+}
+
+func (s *Server) deploymentURL(appID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.find(appID); a != nil && a.app.ProviderUrl != nil {
+		return *a.app.ProviderUrl
+	}
+	return ""
+}
+
+// respond writes the result of a Fake call
+func respond(w http.ResponseWriter, status int) func(interface{}, error) {
+	return func(v interface{}, err error) {
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, status, v)
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		writeError(w, apiError(http.StatusBadRequest, "invalid request body: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+// writeError answers with the status the platform fails with for err
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch ftlerr.CodeOf(err) {
+	case ftlerr.NotFound:
+		status = http.StatusNotFound
+	case ftlerr.Conflict:
+		status = http.StatusConflict
+	case ftlerr.InvalidRequest:
+		status = http.StatusBadRequest
+	case ftlerr.PermissionDenied:
+		status = http.StatusForbidden
+	case ftlerr.AuthFailed:
+		status = http.StatusUnauthorized
+	}
+
+	// The Fake's errors already carry the "API error: " prefix the client adds
+	message := err.Error()
+	var ferr *ftlerr.Error
+	if errors.As(err, &ferr) {
+		message = strings.TrimPrefix(ferr.Message, "API error: ")
+	}
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,99 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/internal/api"
+)
+
+func TestServer_Apps(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(t)
+	client := s.Client(t)
+
+	access := api.CreateAppRequestAccessControlPrivate
+	created, err := client.CreateApp(ctx, api.CreateAppRequest{AppName: "demo", AccessControl: &access})
+	require.NoError(t, err)
+	assert.Equal(t, "demo", created.AppName)
+
+	_, err = client.CreateApp(ctx, api.CreateAppRequest{AppName: "demo"})
+	assert.Equal(t, ftlerr.Conflict, ftlerr.CodeOf(err))
+
+	app, err := client.GetApp(ctx, created.AppId.String())
+	require.NoError(t, err)
+	require.NotNil(t, app.AccessControl)
+	assert.Equal(t, api.AppAccessControlPrivate, *app.AccessControl)
+
+	_, err = client.GetApp(ctx, "00000000-0000-0000-0000-000000000000")
+	assert.Equal(t, ftlerr.NotFound, ftlerr.CodeOf(err))
+
+	require.NoError(t, client.DeleteApp(ctx, created.AppId.String()))
+	apps, err := client.ListApps(ctx, &api.ListAppsParams{})
+	require.NoError(t, err)
+	assert.Empty(t, apps.Apps, "deleted apps are not listed")
+}
+
+func TestServer_Pagination(t *testing.T) {
+	s := NewServer(t)
+	for i := 0; i < 5; i++ {
+		s.AddApp(fmt.Sprintf("app-%d", i), api.AppAccessControlPublic)
+	}
+
+	limit := 2
+	apps, err := api.IterateApps(context.Background(), s.Client(t), &api.ListAppsParams{Limit: &limit}).Collect()
+	require.NoError(t, err)
+	require.Len(t, apps, 5)
+	assert.Equal(t, "app-0", apps[0].AppName)
+	assert.Equal(t, "app-4", apps[4].AppName)
+}
+
+func TestServer_DeployCredentials(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(t)
+	client := s.Client(t)
+	appID := s.AddApp("demo", api.AppAccessControlPublic).AppId.String()
+
+	creds, err := client.CreateDeployCredentials(ctx, appID, []string{"geo", "weather"})
+	require.NoError(t, err)
+	assert.Equal(t, s.URL+"/deploy/"+appID, creds.Deployment.FunctionUrl)
+	assert.Equal(t, api.User, creds.Deployment.Context.ActorType)
+	assert.Equal(t, []string{"org_test"}, creds.Deployment.Context.OrgIds)
+
+	components, err := api.IterateComponents(ctx, client, appID).Collect()
+	require.NoError(t, err)
+	require.Len(t, components, 2, "credentials create a repository per component")
+	assert.Equal(t, "geo", components[0].ComponentName)
+}
+
+func TestFake_Deploy(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake()
+	appID := f.AddApp("demo", api.AppAccessControlPublic).AppId.String()
+
+	d, err := f.Deploy(Deployment{
+		AppID: appID,
+		Request: map[string]interface{}{
+			"version": "1.2.0",
+			"access":  "private",
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, f.Deployments(), 1)
+
+	app, err := f.GetApp(ctx, appID)
+	require.NoError(t, err)
+	require.NotNil(t, app.LatestDeployment)
+	assert.Equal(t, d.ID, app.LatestDeployment.DeploymentId)
+	assert.Equal(t, api.AppLatestDeploymentStatusDeployed, app.LatestDeployment.Status)
+	require.NotNil(t, app.AccessControl)
+	assert.Equal(t, api.AppAccessControlPrivate, *app.AccessControl)
+
+	_, err = f.Deploy(Deployment{AppID: "missing"})
+	assert.Equal(t, ftlerr.NotFound, ftlerr.CodeOf(err))
+}
@@ -314,6 +314,9 @@ type ListAppsResponseBody struct {
 		Status        ListAppsResponseBodyAppsStatus `json:"status"`
 		UpdatedAt     string                         `json:"updatedAt"`
 	} `json:"apps"`
+
+	// NextToken Cursor for the next page, absent on the last page
+	NextToken *string `json:"nextToken,omitempty"`
 }
 
 // ListAppsResponseBodyAppsAccessControl defines model for ListAppsResponseBody.Apps.AccessControl.
@@ -335,6 +338,9 @@ type ListComponentsResponseBody struct {
 		RepositoryName *string `json:"repositoryName,omitempty"`
 		RepositoryUri  *string `json:"repositoryUri,omitempty"`
 	} `json:"components"`
+
+	// NextToken Cursor for the next page, absent on the last page
+	NextToken *string `json:"nextToken,omitempty"`
 }
 
 // UpdateComponentsRequest Request body for updating components
@@ -368,6 +374,12 @@ type ListAppsParams struct {
 	// IncludeDeleted Include deleted apps in the response
 	IncludeDeleted *ListAppsParamsIncludeDeleted `form:"includeDeleted,omitempty" json:"includeDeleted,omitempty"`
 
+	// Limit Maximum number of apps to return in one page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// NextToken Cursor returned as nextToken by the previous page
+	NextToken *string `form:"nextToken,omitempty" json:"nextToken,omitempty"`
+
 	// Authorization Bearer token for authentication
 	Authorization string `json:"Authorization"`
 }
@@ -395,6 +407,12 @@ type GetAppParams struct {
 
 // ListAppComponentsParams defines parameters for ListAppComponents.
 type ListAppComponentsParams struct {
+	// Limit Maximum number of components to return in one page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// NextToken Cursor returned as nextToken by the previous page
+	NextToken *string `form:"nextToken,omitempty" json:"nextToken,omitempty"`
+
 	// Authorization Bearer token for authentication
 	Authorization string `json:"Authorization"`
 }
@@ -743,6 +761,38 @@ func NewListAppsRequest(server string, params *ListAppsParams) (*http.Request, e
 
 		}
 
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.NextToken != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "nextToken", runtime.ParamLocationQuery, *params.NextToken); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
 		queryURL.RawQuery = queryValues.Encode()
 	}
 
@@ -940,6 +990,44 @@ func NewListAppComponentsRequest(server string, appId openapi_types.UUID, params
 		return nil, err
 	}
 
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.NextToken != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "nextToken", runtime.ParamLocationQuery, *params.NextToken); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
 	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
+	"github.com/fastertools/ftl/ftlerr"
 	"github.com/fastertools/ftl/internal/auth"
+	"github.com/fastertools/ftl/internal/config"
 )
 
 const (
@@ -24,18 +27,33 @@ type FTLClient struct {
 	baseURL     string
 }
 
+// BaseURL returns the API endpoint: FTL_API_URL if set, else the one of
+// the active profile
+func BaseURL() string {
+	if url := os.Getenv("FTL_API_URL"); url != "" {
+		return url
+	}
+	if _, profile, err := config.ActiveProfile(); err == nil && profile.APIURL != "" {
+		return profile.APIURL
+	}
+	return DefaultAPIBaseURL
+}
+
 // NewFTLClient creates a new FTL API client with authentication
 func NewFTLClient(authManager *auth.Manager, baseURL string) (*FTLClient, error) {
 	if baseURL == "" {
-		baseURL = DefaultAPIBaseURL
+		baseURL = BaseURL()
 	}
 
-	// Create HTTP client with auth interceptor
-	httpClient := &authHTTPClient{
-		authManager: authManager,
-		underlying: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	// Every call gets a request ID, idempotent calls are retried on transient
+	// failures, and each attempt is authenticated with a fresh token
+	httpClient := &requestIDClient{
+		next: newRetryClient(&authHTTPClient{
+			authManager: authManager,
+			underlying: &http.Client{
+				Timeout: 30 * time.Second,
+			},
+		}),
 	}
 
 	// Create the generated client
@@ -85,6 +103,30 @@ func (c *authHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	}
 
 	// Execute the request
+	resp, err := c.underlying.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	// The token was rejected even though it looked valid locally (revoked,
+	// clock skew): refresh it once and replay the request
+	refreshed, refreshErr := c.authManager.ForceRefresh(req.Context())
+	if refreshErr != nil || refreshed == token {
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		req.Body = body
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", refreshed))
 	return c.underlying.Do(req)
 }
 
@@ -112,6 +154,11 @@ func parseUUID(s string) (openapi_types.UUID, error) {
 	return openapi_types.UUID(u), nil
 }
 
+// apiError classifies an unexpected response from the platform
+func apiError(resp *http.Response, body []byte) error {
+	return ftlerr.FromStatus(resp.StatusCode, body, "API error")
+}
+
 // Apps API methods
 
 // ListApps retrieves a list of applications
@@ -122,7 +169,7 @@ func (c *FTLClient) ListApps(ctx context.Context, params *ListAppsParams) (*List
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+		return nil, apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON200 == nil {
@@ -141,7 +188,7 @@ func (c *FTLClient) CreateApp(ctx context.Context, request CreateAppRequest) (*C
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+		return nil, apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON201 == nil {
@@ -164,7 +211,7 @@ func (c *FTLClient) GetApp(ctx context.Context, appID string) (*App, error) {
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+		return nil, apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON200 == nil {
@@ -187,7 +234,7 @@ func (c *FTLClient) DeleteApp(ctx context.Context, appID string) error {
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusAccepted && resp.HTTPResponse.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("API error: %s", string(resp.Body))
+		return apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	return nil
@@ -213,7 +260,7 @@ func (c *FTLClient) CreateDeployCredentials(ctx context.Context, appID string, c
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+		return nil, apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON200 == nil {
@@ -239,7 +286,7 @@ func (c *FTLClient) UpdateComponents(ctx context.Context, appID string, request
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+		return nil, apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON200 == nil {
@@ -263,7 +310,7 @@ func (c *FTLClient) GetUserInfo(ctx context.Context) (*GetUserInfoResponseBody,
 	}
 
 	if resp.HTTPResponse.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+		return nil, apiError(resp.HTTPResponse, resp.Body)
 	}
 
 	if resp.JSON200 == nil {
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries a unique ID for each API call, shared by its retries
+	RequestIDHeader = "X-Request-Id"
+
+	// defaultMaxRetries is how many times an idempotent request is retried
+	defaultMaxRetries = 3
+
+	// defaultRetryBaseDelay is the first backoff delay, doubled on every retry
+	defaultRetryBaseDelay = 500 * time.Millisecond
+
+	// defaultRetryMaxDelay caps a single backoff delay, including Retry-After
+	defaultRetryMaxDelay = 10 * time.Second
+)
+
+// requestIDClient attaches a request ID to every request that lacks one
+type requestIDClient struct {
+	next HttpRequestDoer
+}
+
+// Do implements HttpRequestDoer
+func (c *requestIDClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(RequestIDHeader) == "" {
+		req.Header.Set(RequestIDHeader, uuid.NewString())
+	}
+	return c.next.Do(req)
+}
+
+// retryClient retries idempotent requests that fail with a network error,
+// 429 or a retryable 5xx, using exponential backoff with jitter
+type retryClient struct {
+	next       HttpRequestDoer
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func newRetryClient(next HttpRequestDoer) *retryClient {
+	return &retryClient{
+		next:       next,
+		maxRetries: defaultMaxRetries,
+		baseDelay:  defaultRetryBaseDelay,
+		maxDelay:   defaultRetryMaxDelay,
+	}
+}
+
+// Do implements HttpRequestDoer
+func (c *retryClient) Do(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req.Method) {
+		return c.next.Do(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		resp, err := c.next.Do(req)
+		if attempt >= c.maxRetries || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The body has been consumed and cannot be replayed
+			return resp, err
+		}
+
+		delay := c.backoff(attempt, resp)
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt, honoring Retry-After
+func (c *retryClient) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.maxDelay)
+		}
+	}
+
+	delay := min(c.baseDelay<<attempt, c.maxDelay)
+	// Jitter between half and the whole delay spreads out retrying clients
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half) // #nosec G404 -- jitter does not need a secure source
+}
+
+// isIdempotent reports whether a request with the method may safely be repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether the outcome of an attempt is worth retrying
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// Only transport failures are retried; errors such as a missing
+		// login come from our own middleware and will not go away
+		var urlErr *url.Error
+		return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
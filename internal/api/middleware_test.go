@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+// recordingServer answers with the given status codes in turn, recording requests
+type recordingServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, string(body))
+
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "0")
+	}
+	w.WriteHeader(status)
+}
+
+func fastRetryClient(next HttpRequestDoer) *retryClient {
+	c := newRetryClient(next)
+	c.baseDelay = time.Millisecond
+	c.maxDelay = 5 * time.Millisecond
+	return c
+}
+
+func TestRetryClient_RetriesIdempotentRequests(t *testing.T) {
+	rec := &recordingServer{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	client := &requestIDClient{next: fastRetryClient(http.DefaultClient)}
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, rec.requests, 3)
+	requestID := rec.requests[0].Header.Get(RequestIDHeader)
+	assert.NotEmpty(t, requestID)
+	for i, r := range rec.requests {
+		assert.Equal(t, requestID, r.Header.Get(RequestIDHeader), "retries share the request ID")
+		assert.Equal(t, `{"a":1}`, rec.bodies[i], "body is replayed on retry")
+	}
+}
+
+func TestRetryClient_GivesUp(t *testing.T) {
+	rec := &recordingServer{statuses: []int{500, 500, 500, 500, 500}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	resp, err := fastRetryClient(http.DefaultClient).Do(mustRequest(t, http.MethodGet, server.URL))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Len(t, rec.requests, defaultMaxRetries+1)
+}
+
+func TestRetryClient_DoesNotRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{"post is not idempotent", http.MethodPost, http.StatusServiceUnavailable},
+		{"client errors are final", http.MethodGet, http.StatusBadRequest},
+		{"not implemented is final", http.MethodGet, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingServer{statuses: []int{tt.status, http.StatusOK}}
+			server := httptest.NewServer(rec)
+			defer server.Close()
+
+			resp, err := fastRetryClient(http.DefaultClient).Do(mustRequest(t, tt.method, server.URL))
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Len(t, rec.requests, 1)
+		})
+	}
+}
+
+type failingDoer struct{ calls int }
+
+func (d *failingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls++
+	return nil, errors.New("not logged in")
+}
+
+func TestRetryClient_MiddlewareErrorsAreFinal(t *testing.T) {
+	doer := &failingDoer{}
+	_, err := fastRetryClient(doer).Do(mustRequest(t, http.MethodGet, "http://example.invalid"))
+	assert.Error(t, err)
+	assert.Equal(t, 1, doer.calls)
+}
+
+func TestRetryClient_ContextCanceled(t *testing.T) {
+	rec := &recordingServer{statuses: []int{503, 503, 503, 503}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	client := newRetryClient(http.DefaultClient)
+	client.baseDelay = time.Hour
+	client.maxDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := mustRequest(t, http.MethodGet, server.URL).WithContext(ctx)
+
+	_, err := client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, rec.requests, 1)
+}
+
+func TestAuthHTTPClient_RefreshesOnUnauthorized(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"name":"app"}`, string(body))
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer mock-refreshed-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	store := &mockCredentialStore{creds: &auth.Credentials{
+		AccessToken:  "revoked-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    timePtr(time.Now().Add(time.Hour)),
+	}}
+	provider := &auth.MockOAuthProvider{}
+	client := &authHTTPClient{
+		authManager: auth.NewManagerWithProvider(store, provider, nil),
+		underlying:  http.DefaultClient,
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"name":"app"}`))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer revoked-token", "Bearer mock-refreshed-token"}, tokens)
+	assert.Len(t, provider.RefreshTokenCalls, 1)
+	assert.Equal(t, "mock-refreshed-token", store.creds.AccessToken)
+}
+
+func TestAuthHTTPClient_UnauthorizedWithoutRefreshToken(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	store := &mockCredentialStore{creds: &auth.Credentials{
+		AccessToken: "machine-token",
+		ExpiresAt:   timePtr(time.Now().Add(time.Hour)),
+	}}
+	client := &authHTTPClient{
+		authManager: auth.NewManagerWithProvider(store, &auth.MockOAuthProvider{}, nil),
+		underlying:  http.DefaultClient,
+	}
+
+	resp, err := client.Do(mustRequest(t, http.MethodGet, server.URL))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func mustRequest(t *testing.T, method, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	return req
+}
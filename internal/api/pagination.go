@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// DefaultPageSize is the number of items requested per page by iterators
+const DefaultPageSize = 100
+
+// ListedApp is an app as returned by ListApps
+type ListedApp = struct {
+	AccessControl *ListAppsResponseBodyAppsAccessControl `json:"accessControl,omitempty"`
+	AllowedRoles  *[]string                              `json:"allowedRoles,omitempty"`
+	AppId         openapi_types.UUID                     `json:"appId"`
+	AppName       string                                 `json:"appName"`
+	CreatedAt     string                                 `json:"createdAt"`
+	CustomAuth    *struct {
+		Audience string `json:"audience"`
+		Issuer   string `json:"issuer"`
+	} `json:"customAuth,omitempty"`
+
+	// LatestDeployment Latest deployment information for this app
+	LatestDeployment *struct {
+		CreatedAt          *float32                                       `json:"createdAt,omitempty"`
+		DeployedAt         *float32                                       `json:"deployedAt,omitempty"`
+		DeploymentDuration *float32                                       `json:"deploymentDuration,omitempty"`
+		DeploymentId       string                                         `json:"deploymentId"`
+		Environment        *string                                        `json:"environment,omitempty"`
+		Status             ListAppsResponseBodyAppsLatestDeploymentStatus `json:"status"`
+		StatusMessage      *string                                        `json:"statusMessage,omitempty"`
+	} `json:"latestDeployment"`
+	OrgId         *string                        `json:"orgId,omitempty"`
+	ProviderError *string                        `json:"providerError,omitempty"`
+	ProviderUrl   *string                        `json:"providerUrl,omitempty"`
+	Status        ListAppsResponseBodyAppsStatus `json:"status"`
+	UpdatedAt     string                         `json:"updatedAt"`
+}
+
+// ListedComponent is a component as returned by ListAppComponents
+type ListedComponent = struct {
+	ComponentName  string  `json:"componentName"`
+	Description    *string `json:"description,omitempty"`
+	RepositoryName *string `json:"repositoryName,omitempty"`
+	RepositoryUri  *string `json:"repositoryUri,omitempty"`
+}
+
+// pageFetcher fetches the page starting at the cursor (nil for the first
+// page) and returns its items and the cursor of the next page, if any
+type pageFetcher[T any] func(ctx context.Context, cursor *string) ([]T, *string, error)
+
+// Iterator walks a paginated listing one item at a time, fetching pages
+// lazily:
+//
+//	it := client.IterateApps(ctx, nil)
+//	for it.Next() {
+//		app := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx     context.Context
+	fetch   pageFetcher[T]
+	page    []T
+	index   int
+	current T
+	cursor  *string
+	started bool
+	err     error
+}
+
+func newIterator[T any](ctx context.Context, fetch pageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, index: -1}
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false when the listing is exhausted or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index+1 >= len(it.page) {
+		// The last page has been consumed
+		if it.started && it.cursor == nil {
+			return false
+		}
+
+		page, next, err := it.fetch(it.ctx, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		// Guard against a server that hands back the same cursor forever
+		if it.started && next != nil && it.cursor != nil && *next == *it.cursor {
+			it.err = fmt.Errorf("pagination cursor did not advance")
+			return false
+		}
+		if next != nil && *next == "" {
+			next = nil
+		}
+
+		it.started = true
+		it.page, it.index, it.cursor = page, -1, next
+	}
+
+	it.index++
+	it.current = it.page[it.index]
+	return true
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect drains the iterator into a slice
+func (it *Iterator[T]) Collect() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+// IterateApps lists every app matching params across all pages. Limit and
+// NextToken in params are managed by the iterator.
+func (c *FTLClient) IterateApps(ctx context.Context, params *ListAppsParams) *Iterator[ListedApp] {
+	return IterateApps(ctx, c, params)
+}
+
+// IterateApps lists every app matching params across all pages of any
+// FTLAPI implementation
+func IterateApps(ctx context.Context, c FTLAPI, params *ListAppsParams) *Iterator[ListedApp] {
+	base := ListAppsParams{}
+	if params != nil {
+		base = *params
+	}
+	if base.Limit == nil {
+		limit := DefaultPageSize
+		base.Limit = &limit
+	}
+
+	return newIterator(ctx, func(ctx context.Context, cursor *string) ([]ListedApp, *string, error) {
+		pageParams := base
+		pageParams.NextToken = cursor
+		resp, err := c.ListApps(ctx, &pageParams)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Apps, resp.NextToken, nil
+	})
+}
+
+// ListAppComponents retrieves one page of an app's components
+func (c *FTLClient) ListAppComponents(ctx context.Context, appID string, params *ListAppComponentsParams) (*ListComponentsResponseBody, error) {
+	appUUID, err := parseUUID(appID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid app ID: %w", err)
+	}
+	if params == nil {
+		params = &ListAppComponentsParams{}
+	}
+
+	resp, err := c.client.ListAppComponentsWithResponse(ctx, appUUID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list components: %w", err)
+	}
+
+	if resp.HTTPResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %s", string(resp.Body))
+	}
+
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+
+	return resp.JSON200, nil
+}
+
+// IterateComponents lists every component of an app across all pages
+func (c *FTLClient) IterateComponents(ctx context.Context, appID string) *Iterator[ListedComponent] {
+	return IterateComponents(ctx, c, appID)
+}
+
+// IterateComponents lists every component of an app across all pages of
+// any FTLAPI implementation
+func IterateComponents(ctx context.Context, c FTLAPI, appID string) *Iterator[ListedComponent] {
+	limit := DefaultPageSize
+	return newIterator(ctx, func(ctx context.Context, cursor *string) ([]ListedComponent, *string, error) {
+		resp, err := c.ListAppComponents(ctx, appID, &ListAppComponentsParams{
+			Limit:     &limit,
+			NextToken: cursor,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Components, resp.NextToken, nil
+	})
+}
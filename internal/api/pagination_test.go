@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+func newTestFTLClient(t *testing.T, handler http.Handler) *FTLClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	authManager := auth.NewManager(&mockCredentialStore{
+		creds: &auth.Credentials{
+			AccessToken: "test-token",
+			ExpiresAt:   timePtr(time.Now().Add(time.Hour)),
+		},
+	}, nil)
+	client, err := NewFTLClient(authManager, server.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func TestIterateApps(t *testing.T) {
+	var cursors []string
+	client := newTestFTLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/apps", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		assert.Equal(t, "demo", r.URL.Query().Get("name"))
+		cursor := r.URL.Query().Get("nextToken")
+		cursors = append(cursors, cursor)
+
+		pages := map[string]struct {
+			names []string
+			next  string
+		}{
+			"":      {[]string{"a", "b"}, "page2"},
+			"page2": {nil, "page3"}, // empty pages are skipped
+			"page3": {[]string{"c"}, ""},
+		}
+		page := pages[cursor]
+
+		apps := []map[string]interface{}{}
+		for _, name := range page.names {
+			apps = append(apps, map[string]interface{}{
+				"appId": uuid.NewString(), "appName": name, "status": "ACTIVE",
+				"createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-01-01T00:00:00Z",
+			})
+		}
+		body := map[string]interface{}{"apps": apps}
+		if page.next != "" {
+			body["nextToken"] = page.next
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+
+	name := "demo"
+	limit := 2
+	it := client.IterateApps(context.Background(), &ListAppsParams{Name: &name, Limit: &limit})
+
+	var names []string
+	for it.Next() {
+		names = append(names, it.Value().AppName)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+	assert.Equal(t, []string{"", "page2", "page3"}, cursors)
+
+	// Exhausted iterators stay exhausted
+	assert.False(t, it.Next())
+}
+
+func TestIterateComponents(t *testing.T) {
+	appID := uuid.NewString()
+	client := newTestFTLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/v1/apps/%s/components", appID), r.URL.Path)
+		assert.Equal(t, fmt.Sprint(DefaultPageSize), r.URL.Query().Get("limit"))
+
+		body := map[string]interface{}{"appId": appID, "appName": "demo"}
+		if r.URL.Query().Get("nextToken") == "" {
+			body["components"] = []map[string]interface{}{{"componentName": "api"}}
+			body["nextToken"] = "next"
+		} else {
+			body["components"] = []map[string]interface{}{{"componentName": "worker"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+
+	components, err := client.IterateComponents(context.Background(), appID).Collect()
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+	assert.Equal(t, "api", components[0].ComponentName)
+	assert.Equal(t, "worker", components[1].ComponentName)
+
+	_, err = client.IterateComponents(context.Background(), "not-a-uuid").Collect()
+	assert.Error(t, err)
+}
+
+func TestIterator_Errors(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	it := newIterator(context.Background(), func(ctx context.Context, cursor *string) ([]int, *string, error) {
+		calls++
+		if cursor != nil {
+			return nil, nil, boom
+		}
+		next := "2"
+		return []int{1}, &next, nil
+	})
+
+	assert.True(t, it.Next())
+	assert.Equal(t, 1, it.Value())
+	assert.False(t, it.Next())
+	assert.ErrorIs(t, it.Err(), boom)
+	assert.False(t, it.Next())
+	assert.Equal(t, 2, calls)
+
+	// A cursor that never advances is reported instead of looping forever
+	stuck := newIterator(context.Background(), func(ctx context.Context, cursor *string) ([]int, *string, error) {
+		next := "same"
+		return nil, &next, nil
+	})
+	_, err := stuck.Collect()
+	assert.Error(t, err)
+}
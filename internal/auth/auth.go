@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/fastertools/ftl/ftlerr"
 	"github.com/fastertools/ftl/internal/config"
 	"github.com/pkg/browser"
 )
@@ -27,10 +28,7 @@ func (d *defaultBrowserOpener) OpenURL(url string) error {
 // NewManager creates a new authentication manager
 func NewManager(store CredentialStore, config *LoginConfig) *Manager {
 	if config == nil {
-		config = &LoginConfig{
-			AuthKitDomain: DefaultAuthKitDomain,
-			ClientID:      DefaultClientID,
-		}
+		config = DefaultLoginConfig()
 	}
 
 	return &Manager{
@@ -45,10 +43,7 @@ func NewManager(store CredentialStore, config *LoginConfig) *Manager {
 // This is primarily for testing but can be used for custom OAuth implementations
 func NewManagerWithProvider(store CredentialStore, provider OAuthProvider, config *LoginConfig) *Manager {
 	if config == nil {
-		config = &LoginConfig{
-			AuthKitDomain: DefaultAuthKitDomain,
-			ClientID:      DefaultClientID,
-		}
+		config = DefaultLoginConfig()
 	}
 
 	return &Manager{
@@ -63,10 +58,7 @@ func NewManagerWithProvider(store CredentialStore, provider OAuthProvider, confi
 // This is specifically for testing to prevent any external interactions
 func NewManagerWithMocks(store CredentialStore, provider OAuthProvider, browser BrowserOpener, config *LoginConfig) *Manager {
 	if config == nil {
-		config = &LoginConfig{
-			AuthKitDomain: DefaultAuthKitDomain,
-			ClientID:      DefaultClientID,
-		}
+		config = DefaultLoginConfig()
 	}
 
 	// Always disable browser in tests, regardless of config
@@ -194,17 +186,17 @@ func (m *Manager) Status() *AuthStatus {
 func (m *Manager) GetToken(ctx context.Context) (string, error) {
 	creds, err := m.store.Load()
 	if err != nil || creds == nil {
-		return "", fmt.Errorf("not logged in")
+		return "", ftlerr.New(ftlerr.NotLoggedIn, "not logged in").WithHint(ftlerr.LoginHint)
 	}
 
 	// Check if token needs refresh
 	if creds.IsExpired() {
 		if creds.RefreshToken == "" {
-			return "", fmt.Errorf("token expired and no refresh token available")
+			return "", ftlerr.New(ftlerr.SessionExpired, "token expired and no refresh token available").WithHint(ftlerr.ReloginHint)
 		}
 		refreshed, err := m.Refresh(ctx, creds)
 		if err != nil {
-			return "", fmt.Errorf("failed to refresh token: %w", err)
+			return "", ftlerr.Wrap(err, ftlerr.SessionExpired, "failed to refresh token").WithHint(ftlerr.ReloginHint)
 		}
 		creds = refreshed
 	}
@@ -250,6 +242,21 @@ func (m *Manager) Refresh(ctx context.Context, creds *Credentials) (*Credentials
 	return newCreds, nil
 }
 
+// ForceRefresh refreshes the stored token even if it has not expired
+// locally, for example after the server rejected it
+func (m *Manager) ForceRefresh(ctx context.Context) (string, error) {
+	creds, err := m.store.Load()
+	if err != nil || creds == nil {
+		return "", ftlerr.New(ftlerr.NotLoggedIn, "not logged in").WithHint(ftlerr.LoginHint)
+	}
+
+	refreshed, err := m.Refresh(ctx, creds)
+	if err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
 // GetOrRefreshToken gets a valid token, refreshing if necessary
 func (m *Manager) GetOrRefreshToken(ctx context.Context) (string, error) {
 	return m.GetToken(ctx)
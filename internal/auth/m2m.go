@@ -10,8 +10,13 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/fastertools/ftl/ftlerr"
 )
 
+// machineClientHint is shown when the platform rejects machine credentials
+const machineClientHint = "Check that FTL_CLIENT_ID and FTL_CLIENT_SECRET belong to an active machine client"
+
 // M2MConfig holds configuration for machine-to-machine authentication
 type M2MConfig struct {
 	ClientID     string `json:"client_id"`
@@ -59,7 +64,7 @@ func (m *M2MManager) LoadM2MConfig() (*M2MConfig, error) {
 
 		// Use default issuer if not provided
 		if config.Issuer == "" {
-			config.Issuer = DefaultAuthKitDomain
+			config.Issuer = DefaultLoginConfig().AuthKitDomain
 		}
 
 		return config, nil
@@ -71,7 +76,7 @@ func (m *M2MManager) LoadM2MConfig() (*M2MConfig, error) {
 		return storedConfig, nil
 	}
 
-	return nil, fmt.Errorf("no M2M credentials found. Set FTL_CLIENT_ID and FTL_CLIENT_SECRET environment variables")
+	return nil, ftlerr.New(ftlerr.NotLoggedIn, "no M2M credentials found").WithHint("Set the FTL_CLIENT_ID and FTL_CLIENT_SECRET environment variables")
 }
 
 // ExchangeCredentials exchanges client credentials for an access token
@@ -80,20 +85,22 @@ func (m *M2MManager) ExchangeCredentials(ctx context.Context, config *M2MConfig)
 		return nil, fmt.Errorf("M2M config is required")
 	}
 
-	// Construct token endpoint URL
-	issuer := config.Issuer
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+	data.Set("client_id", config.ClientID)
+	data.Set("client_secret", config.ClientSecret)
+
+	return m.requestToken(ctx, config.Issuer, data, "failed to exchange credentials")
+}
+
+// requestToken posts a token request to the issuer's token endpoint
+func (m *M2MManager) requestToken(ctx context.Context, issuer string, data url.Values, failure string) (*TokenResponse, error) {
 	// Ensure issuer has https:// scheme
 	if !strings.HasPrefix(issuer, "http://") && !strings.HasPrefix(issuer, "https://") {
 		issuer = "https://" + issuer
 	}
 	tokenURL := fmt.Sprintf("%s/oauth2/token", strings.TrimSuffix(issuer, "/"))
 
-	// Prepare request body
-	data := url.Values{}
-	data.Set("grant_type", "client_credentials")
-	data.Set("client_id", config.ClientID)
-	data.Set("client_secret", config.ClientSecret)
-
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -106,7 +113,7 @@ func (m *M2MManager) ExchangeCredentials(ctx context.Context, config *M2MConfig)
 	// Make request
 	resp, err := m.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to exchange credentials: %w", err)
+		return nil, fmt.Errorf("%s: %w", failure, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -124,10 +131,10 @@ func (m *M2MManager) ExchangeCredentials(ctx context.Context, config *M2MConfig)
 		}
 
 		if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Error != "" {
-			return nil, fmt.Errorf("authentication failed: %s - %s", errorResp.Error, errorResp.ErrorDescription)
+			return nil, ftlerr.New(ftlerr.AuthFailed, fmt.Sprintf("authentication failed: %s - %s", errorResp.Error, errorResp.ErrorDescription)).WithHint(machineClientHint)
 		}
 
-		return nil, fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, ftlerr.New(ftlerr.AuthFailed, fmt.Sprintf("authentication failed with status %d: %s", resp.StatusCode, string(body))).WithHint(machineClientHint)
 	}
 
 	// Parse successful response
@@ -215,7 +222,7 @@ func (m *Manager) ConfigureM2M(config *M2MConfig) error {
 
 	// Set default issuer if not provided
 	if config.Issuer == "" {
-		config.Issuer = DefaultAuthKitDomain
+		config.Issuer = DefaultLoginConfig().AuthKitDomain
 	}
 
 	// Store the configuration
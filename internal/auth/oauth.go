@@ -23,11 +23,12 @@ var _ OAuthProvider = (*OAuthClient)(nil)
 
 // NewOAuthClient creates a new OAuth client
 func NewOAuthClient(authKitDomain, clientID string) *OAuthClient {
+	defaults := DefaultLoginConfig()
 	if authKitDomain == "" {
-		authKitDomain = DefaultAuthKitDomain
+		authKitDomain = defaults.AuthKitDomain
 	}
 	if clientID == "" {
-		clientID = DefaultClientID
+		clientID = defaults.ClientID
 	}
 
 	return &OAuthClient{
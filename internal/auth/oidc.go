@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultOIDCAudience is the audience CI identity tokens are requested for
+	DefaultOIDCAudience = "ftl"
+
+	// tokenExchangeGrantType is the RFC 8693 token exchange grant
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// idTokenType identifies an OpenID Connect ID token as the subject token
+	idTokenType = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// IsOIDCConfigured checks if the CI environment can authenticate with a
+// workload identity token. It requires FTL_CLIENT_ID, naming the machine
+// client that trusts the CI provider, and either an FTL_OIDC_TOKEN (GitLab
+// id_tokens) or a GitHub Actions job with the id-token permission.
+func IsOIDCConfigured() bool {
+	if os.Getenv("FTL_CLIENT_ID") == "" {
+		return false
+	}
+	if os.Getenv("FTL_OIDC_TOKEN") != "" {
+		return true
+	}
+	return os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != "" && os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN") != ""
+}
+
+// OIDCAudience returns the audience to request CI identity tokens for
+func OIDCAudience() string {
+	if audience := os.Getenv("FTL_OIDC_AUDIENCE"); audience != "" {
+		return audience
+	}
+	return DefaultOIDCAudience
+}
+
+// CIIdentityToken returns the CI provider's OIDC token for this job, either
+// from FTL_OIDC_TOKEN or by requesting one from GitHub Actions
+func (m *M2MManager) CIIdentityToken(ctx context.Context, audience string) (string, error) {
+	if token := os.Getenv("FTL_OIDC_TOKEN"); token != "" {
+		return strings.TrimSpace(token), nil
+	}
+
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", fmt.Errorf("no CI identity token available. Set FTL_OIDC_TOKEN or grant the GitHub Actions job 'id-token: write' permission")
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request GitHub Actions identity token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub Actions identity token request failed with status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse identity token response: %w", err)
+	}
+	if tokenResp.Value == "" {
+		return "", fmt.Errorf("GitHub Actions returned an empty identity token")
+	}
+	return tokenResp.Value, nil
+}
+
+// ExchangeOIDCToken exchanges a CI identity token for an FTL access token
+// using the RFC 8693 token exchange grant
+func (m *M2MManager) ExchangeOIDCToken(ctx context.Context, issuer, clientID, idToken string) (*TokenResponse, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("client ID is required")
+	}
+	if issuer == "" {
+		issuer = DefaultLoginConfig().AuthKitDomain
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", tokenExchangeGrantType)
+	data.Set("client_id", clientID)
+	data.Set("subject_token", idToken)
+	data.Set("subject_token_type", idTokenType)
+
+	return m.requestToken(ctx, issuer, data, "failed to exchange identity token")
+}
+
+// LoginOIDC performs machine login by exchanging the CI provider's identity
+// token, so pipelines can deploy without a stored client secret
+func (m *Manager) LoginOIDC(ctx context.Context) error {
+	m2mManager := NewM2MManager(m.store)
+
+	idToken, err := m2mManager.CIIdentityToken(ctx, OIDCAudience())
+	if err != nil {
+		return err
+	}
+
+	tokenResp, err := m2mManager.ExchangeOIDCToken(ctx, os.Getenv("FTL_ISSUER"), os.Getenv("FTL_CLIENT_ID"), idToken)
+	if err != nil {
+		return fmt.Errorf("failed to exchange identity token: %w", err)
+	}
+
+	if err := m.store.StoreToken(tokenResp.AccessToken, tokenResp.ExpiresIn); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+
+	// Store that this is a machine token
+	if err := m.store.SetActorType("machine"); err != nil {
+		// Non-fatal, just log it
+		fmt.Printf("Warning: failed to store actor type: %v\n", err)
+	}
+
+	return nil
+}
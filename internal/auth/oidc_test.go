@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsOIDCConfigured(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"nothing set", map[string]string{}, false},
+		{"token without client", map[string]string{"FTL_OIDC_TOKEN": "jwt"}, false},
+		{"gitlab id token", map[string]string{"FTL_CLIENT_ID": "client", "FTL_OIDC_TOKEN": "jwt"}, true},
+		{"github actions", map[string]string{
+			"FTL_CLIENT_ID":                  "client",
+			"ACTIONS_ID_TOKEN_REQUEST_URL":   "https://example.com/token",
+			"ACTIONS_ID_TOKEN_REQUEST_TOKEN": "request",
+		}, true},
+		{"github actions without permission", map[string]string{
+			"FTL_CLIENT_ID":                "client",
+			"ACTIONS_ID_TOKEN_REQUEST_URL": "https://example.com/token",
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"FTL_CLIENT_ID", "FTL_OIDC_TOKEN", "ACTIONS_ID_TOKEN_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_TOKEN"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := IsOIDCConfigured(); got != tt.want {
+				t.Errorf("IsOIDCConfigured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCIIdentityToken_GitHubActions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer request-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.URL.Query().Get("audience"); got != "ftl" {
+			t.Errorf("audience = %q", got)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2.0" {
+			t.Errorf("existing query parameters were dropped: %q", r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": "github-jwt"})
+	}))
+	defer server.Close()
+
+	t.Setenv("FTL_OIDC_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+	token, err := NewM2MManager(nil).CIIdentityToken(context.Background(), "ftl")
+	if err != nil {
+		t.Fatalf("CIIdentityToken() error = %v", err)
+	}
+	if token != "github-jwt" {
+		t.Errorf("token = %q, want github-jwt", token)
+	}
+}
+
+func TestCIIdentityToken_PrefersEnvironment(t *testing.T) {
+	t.Setenv("FTL_OIDC_TOKEN", " gitlab-jwt\n")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "http://127.0.0.1:0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+	token, err := NewM2MManager(nil).CIIdentityToken(context.Background(), "ftl")
+	if err != nil {
+		t.Fatalf("CIIdentityToken() error = %v", err)
+	}
+	if token != "gitlab-jwt" {
+		t.Errorf("token = %q, want gitlab-jwt", token)
+	}
+}
+
+func TestExchangeOIDCToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/token" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			"grant_type":         tokenExchangeGrantType,
+			"client_id":          "client",
+			"subject_token":      "ci-jwt",
+			"subject_token_type": idTokenType,
+		}
+		for key, value := range want {
+			if got := r.PostForm.Get(key); got != value {
+				t.Errorf("%s = %q, want %q", key, got, value)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(M2MTokenResponse{AccessToken: "ftl-token", TokenType: "Bearer", ExpiresIn: 900})
+	}))
+	defer server.Close()
+
+	resp, err := NewM2MManager(nil).ExchangeOIDCToken(context.Background(), server.URL, "client", "ci-jwt")
+	if err != nil {
+		t.Fatalf("ExchangeOIDCToken() error = %v", err)
+	}
+	if resp.AccessToken != "ftl-token" || resp.ExpiresIn != 900 {
+		t.Errorf("unexpected token response %+v", resp)
+	}
+}
+
+func TestExchangeOIDCToken_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"subject not trusted"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewM2MManager(nil).ExchangeOIDCToken(context.Background(), server.URL, "client", "ci-jwt")
+	if err == nil || err.Error() != "authentication failed: invalid_grant - subject not trusted" {
+		t.Errorf("ExchangeOIDCToken() error = %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"github.com/fastertools/ftl/internal/config"
+)
+
+// DefaultLoginConfig returns the login configuration of the active
+// profile, falling back to the FTL platform's identity provider
+func DefaultLoginConfig() *LoginConfig {
+	loginConfig := &LoginConfig{
+		AuthKitDomain: DefaultAuthKitDomain,
+		ClientID:      DefaultClientID,
+	}
+	if _, profile, err := config.ActiveProfile(); err == nil {
+		if profile.AuthKitDomain != "" {
+			loginConfig.AuthKitDomain = profile.AuthKitDomain
+		}
+		if profile.ClientID != "" {
+			loginConfig.ClientID = profile.ClientID
+		}
+	}
+	return loginConfig
+}
+
+// keyringKey scopes a keyring entry to the active profile, so each
+// platform keeps its own login. The default profile uses the unscoped
+// entries, which keeps logins from before profiles existed.
+func keyringKey(key string) string {
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return key
+	}
+	if name := profiles.ActiveName(); name != config.DefaultProfile {
+		return key + "@" + name
+	}
+	return key
+}
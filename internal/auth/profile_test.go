@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/fastertools/ftl/internal/config"
+)
+
+func TestProfileSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.ProfileEnv, "")
+
+	loginConfig := DefaultLoginConfig()
+	if loginConfig.AuthKitDomain != DefaultAuthKitDomain || loginConfig.ClientID != DefaultClientID {
+		t.Errorf("Expected built-in login settings, got %+v", loginConfig)
+	}
+	if got := keyringKey(KeyringUsername); got != KeyringUsername {
+		t.Errorf("Expected the default profile to keep %s, got %s", KeyringUsername, got)
+	}
+
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		t.Fatalf("Failed to load profiles: %v", err)
+	}
+	profiles.Profiles["self-hosted"] = config.Profile{AuthKitDomain: "auth.example.com", ClientID: "client_123"}
+	if err := profiles.Use("self-hosted"); err != nil {
+		t.Fatalf("Failed to use profile: %v", err)
+	}
+
+	loginConfig = DefaultLoginConfig()
+	if loginConfig.AuthKitDomain != "auth.example.com" || loginConfig.ClientID != "client_123" {
+		t.Errorf("Expected the profile's login settings, got %+v", loginConfig)
+	}
+	if got := keyringKey(KeyringUsername); got != KeyringUsername+"@self-hosted" {
+		t.Errorf("Expected credentials scoped to the profile, got %s", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/zalando/go-keyring"
+
+	"github.com/fastertools/ftl/ftlerr"
 )
 
 // CredentialStore provides secure storage for authentication credentials
@@ -36,12 +38,22 @@ func NewKeyringStore() (*KeyringStore, error) {
 	return &KeyringStore{}, nil
 }
 
+// CheckKeyring reports whether the OS keyring can be reached. Having no
+// stored credentials is not an error.
+func CheckKeyring() error {
+	_, err := keyring.Get(KeyringService, keyringKey(KeyringUsername))
+	if err != nil && err != keyring.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
 // Load retrieves stored credentials from the keyring
 func (s *KeyringStore) Load() (*Credentials, error) {
-	data, err := keyring.Get(KeyringService, KeyringUsername)
+	data, err := keyring.Get(KeyringService, keyringKey(KeyringUsername))
 	if err != nil {
 		if err == keyring.ErrNotFound {
-			return nil, fmt.Errorf("not logged in")
+			return nil, ftlerr.New(ftlerr.NotLoggedIn, "not logged in").WithHint(ftlerr.LoginHint)
 		}
 		return nil, fmt.Errorf("failed to load credentials: %w", err)
 	}
@@ -65,7 +77,7 @@ func (s *KeyringStore) Save(creds *Credentials) error {
 		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
 
-	if err := keyring.Set(KeyringService, KeyringUsername, string(data)); err != nil {
+	if err := keyring.Set(KeyringService, keyringKey(KeyringUsername), string(data)); err != nil {
 		return fmt.Errorf("failed to save credentials: %w", err)
 	}
 
@@ -74,7 +86,7 @@ func (s *KeyringStore) Save(creds *Credentials) error {
 
 // Delete removes stored credentials from the keyring
 func (s *KeyringStore) Delete() error {
-	err := keyring.Delete(KeyringService, KeyringUsername)
+	err := keyring.Delete(KeyringService, keyringKey(KeyringUsername))
 	if err != nil && err != keyring.ErrNotFound {
 		return fmt.Errorf("failed to delete credentials: %w", err)
 	}
@@ -83,7 +95,7 @@ func (s *KeyringStore) Delete() error {
 
 // Exists checks if credentials are stored
 func (s *KeyringStore) Exists() bool {
-	_, err := keyring.Get(KeyringService, KeyringUsername)
+	_, err := keyring.Get(KeyringService, keyringKey(KeyringUsername))
 	return err == nil
 }
 
@@ -102,7 +114,7 @@ func (s *KeyringStore) StoreToken(token string, expiresIn int) error {
 
 // GetM2MConfig retrieves stored M2M configuration
 func (s *KeyringStore) GetM2MConfig() (*M2MConfig, error) {
-	data, err := keyring.Get(KeyringService, "m2m-config")
+	data, err := keyring.Get(KeyringService, keyringKey("m2m-config"))
 	if err != nil {
 		if err == keyring.ErrNotFound {
 			return nil, fmt.Errorf("no M2M configuration found")
@@ -125,7 +137,7 @@ func (s *KeyringStore) StoreM2MConfig(config *M2MConfig) error {
 		return fmt.Errorf("failed to marshal M2M config: %w", err)
 	}
 
-	if err := keyring.Set(KeyringService, "m2m-config", string(data)); err != nil {
+	if err := keyring.Set(KeyringService, keyringKey("m2m-config"), string(data)); err != nil {
 		return fmt.Errorf("failed to store M2M config: %w", err)
 	}
 
@@ -134,12 +146,12 @@ func (s *KeyringStore) StoreM2MConfig(config *M2MConfig) error {
 
 // SetActorType stores whether the current actor is a user or machine
 func (s *KeyringStore) SetActorType(actorType string) error {
-	return keyring.Set(KeyringService, "actor-type", actorType)
+	return keyring.Set(KeyringService, keyringKey("actor-type"), actorType)
 }
 
 // GetActorType retrieves the stored actor type
 func (s *KeyringStore) GetActorType() (string, error) {
-	actorType, err := keyring.Get(KeyringService, "actor-type")
+	actorType, err := keyring.Get(KeyringService, keyringKey("actor-type"))
 	if err != nil {
 		if err == keyring.ErrNotFound {
 			return "", fmt.Errorf("actor type not set")
@@ -0,0 +1,200 @@
+// Package buildcache tracks component build inputs so unchanged components can skip rebuilding
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPath is the project-relative location of the build cache
+	DefaultPath = ".ftl/build-cache.json"
+
+	// cacheVersion is bumped whenever the hashing scheme changes
+	cacheVersion = 1
+)
+
+// skipDirs are never walked when hashing inputs
+var skipDirs = map[string]bool{
+	".git": true,
+	".ftl": true,
+}
+
+// Entry records the inputs of the last successful build of a component
+type Entry struct {
+	Hash    string    `json:"hash"`
+	BuiltAt time.Time `json:"built_at"`
+}
+
+// Cache maps component IDs to their last successful build
+type Cache struct {
+	Version    int               `json:"version"`
+	Components map[string]*Entry `json:"components"`
+
+	path string
+}
+
+// Load reads the cache at path. A missing or outdated cache yields an empty cache.
+func Load(path string) (*Cache, error) {
+	c := &Cache{
+		Version:    cacheVersion,
+		Components: make(map[string]*Entry),
+		path:       path,
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build cache: %w", err)
+	}
+
+	var stored Cache
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != cacheVersion {
+		// Corrupt or incompatible cache: start over rather than fail the build
+		return c, nil
+	}
+	if stored.Components != nil {
+		c.Components = stored.Components
+	}
+	return c, nil
+}
+
+// Save writes the cache back to the path it was loaded from
+func (c *Cache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal build cache: %w", err)
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
+
+// Fresh reports whether the component was last built from the given inputs
+func (c *Cache) Fresh(id, hash string) bool {
+	entry, ok := c.Components[id]
+	return ok && entry.Hash == hash
+}
+
+// Update records a successful build of the component
+func (c *Cache) Update(id, hash string) {
+	c.Components[id] = &Entry{Hash: hash, BuiltAt: time.Now().UTC()}
+}
+
+// Invalidate forgets the component's last build
+func (c *Cache) Invalidate(id string) {
+	delete(c.Components, id)
+}
+
+// HashInputs hashes every file under dir matching one of the watch patterns,
+// together with any extra values (e.g. the build command). Patterns are
+// slash-separated globs relative to dir and may use ** to match any number
+// of directories.
+func HashInputs(dir string, patterns []string, extra ...string) (string, error) {
+	files, err := MatchFiles(dir, patterns)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, e := range extra {
+		_, _ = fmt.Fprintf(h, "extra:%s\n", e)
+	}
+	for _, rel := range files {
+		_, _ = fmt.Fprintf(h, "file:%s\n", rel)
+		if err := hashFile(h, filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// MatchFiles returns the sorted slash-separated paths under dir that match any pattern
+func MatchFiles(dir string, patterns []string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if Match(pattern, rel) {
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// Match reports whether the slash-separated path matches the glob pattern.
+// In addition to path.Match syntax, a "**" segment matches zero or more directories.
+func Match(pattern, path string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(rest, path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(path) == 0 {
+			return false
+		}
+		if ok, err := filepath.Match(pattern[0], path[0]); err != nil || !ok {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "pkg/sub/main.go", true},
+		{"src/**/*.rs", "src/lib.rs", true},
+		{"src/**/*.rs", "src/a/b/lib.rs", true},
+		{"src/**/*.rs", "tests/lib.rs", false},
+		{"./Cargo.toml", "Cargo.toml", true},
+		{"go.mod", "go.sum", false},
+		{"src/**", "src/a/b.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, Match(tt.pattern, tt.path))
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestHashInputs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main")
+	writeFile(t, filepath.Join(dir, "pkg", "util.go"), "package pkg")
+	writeFile(t, filepath.Join(dir, "README.md"), "docs")
+	writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ref")
+
+	files, err := MatchFiles(dir, []string{"**/*.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go", "pkg/util.go"}, files)
+
+	patterns := []string{"**/*.go"}
+	h1, err := HashInputs(dir, patterns, "make build")
+	require.NoError(t, err)
+
+	// Unwatched files do not affect the hash
+	writeFile(t, filepath.Join(dir, "README.md"), "changed docs")
+	h2, err := HashInputs(dir, patterns, "make build")
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+
+	// Watched files do
+	writeFile(t, filepath.Join(dir, "pkg", "util.go"), "package pkg // changed")
+	h3, err := HashInputs(dir, patterns, "make build")
+	require.NoError(t, err)
+	assert.NotEqual(t, h2, h3)
+
+	// So does the build command
+	h4, err := HashInputs(dir, patterns, "make release")
+	require.NoError(t, err)
+	assert.NotEqual(t, h3, h4)
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ftl", "build-cache.json")
+
+	cache, err := Load(path)
+	require.NoError(t, err)
+	assert.False(t, cache.Fresh("app", "abc"))
+
+	cache.Update("app", "abc")
+	require.NoError(t, cache.Save())
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, loaded.Fresh("app", "abc"))
+	assert.False(t, loaded.Fresh("app", "def"))
+
+	loaded.Invalidate("app")
+	assert.False(t, loaded.Fresh("app", "abc"))
+}
+
+func TestLoadCorruptCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build-cache.json")
+	writeFile(t, path, "not json")
+
+	cache, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, cache.Components)
+}
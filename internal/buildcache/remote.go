@@ -0,0 +1,207 @@
+package buildcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ErrCacheMiss is returned by Remote.Get when no artifact is stored under the key
+var ErrCacheMiss = errors.New("build cache miss")
+
+// Remote stores prebuilt component artifacts keyed by their inputs
+type Remote interface {
+	// Get returns the artifact stored under key, or ErrCacheMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores the artifact under key
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// ArtifactKey derives the remote cache key for a component build
+func ArtifactKey(componentID, inputHash, toolchain string) string {
+	sum := sha256.Sum256([]byte(componentID + "\n" + inputHash + "\n" + toolchain))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRemote creates a remote cache from a URL. Supported schemes:
+//   - http:// and https:// - plain GET/PUT, with FTL_BUILD_CACHE_TOKEN sent as a bearer token
+//   - s3://bucket/prefix - requests signed with the default AWS credential chain
+//   - gs://bucket/prefix - GCS XML API using GOOGLE_OAUTH_ACCESS_TOKEN (or FTL_BUILD_CACHE_TOKEN)
+func NewRemote(ctx context.Context, rawURL string) (Remote, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote cache URL: %w", err)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+
+	switch u.Scheme {
+	case "http", "https":
+		return &httpRemote{
+			base:      strings.TrimRight(rawURL, "/"),
+			client:    client,
+			authorize: bearerAuth(os.Getenv("FTL_BUILD_CACHE_TOKEN")),
+		}, nil
+
+	case "s3":
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load AWS config: %w", err)
+		}
+		region := cfg.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		base := fmt.Sprintf("https://%s.s3.%s.amazonaws.com%s", u.Host, region, strings.TrimRight(u.Path, "/"))
+		signer := v4.NewSigner()
+		return &httpRemote{
+			base:   base,
+			client: client,
+			authorize: func(ctx context.Context, req *http.Request, payload []byte) error {
+				creds, err := cfg.Credentials.Retrieve(ctx)
+				if err != nil {
+					return fmt.Errorf("retrieve AWS credentials: %w", err)
+				}
+				sum := sha256.Sum256(payload)
+				payloadHash := hex.EncodeToString(sum[:])
+				req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+				return signer.SignHTTP(ctx, creds, req, payloadHash, "s3", region, time.Now())
+			},
+		}, nil
+
+	case "gs":
+		token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+		if token == "" {
+			token = os.Getenv("FTL_BUILD_CACHE_TOKEN")
+		}
+		return &httpRemote{
+			base:      "https://storage.googleapis.com/" + u.Host + strings.TrimRight(u.Path, "/"),
+			client:    client,
+			authorize: bearerAuth(token),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported remote cache scheme '%s': must be http, https, s3 or gs", u.Scheme)
+}
+
+// httpRemote stores artifacts as objects under a base URL
+type httpRemote struct {
+	base      string
+	client    *http.Client
+	authorize func(ctx context.Context, req *http.Request, payload []byte) error
+}
+
+func bearerAuth(token string) func(context.Context, *http.Request, []byte) error {
+	return func(_ context.Context, req *http.Request, _ []byte) error {
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return nil
+	}
+}
+
+func (r *httpRemote) objectURL(key string) string {
+	return r.base + "/" + key + ".wasm"
+}
+
+// Get downloads the artifact stored under key
+func (r *httpRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.authorize(ctx, req, nil); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch cached artifact: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// S3 answers 403 for missing objects when the caller cannot list the bucket
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrCacheMiss
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("fetch cached artifact: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read cached artifact: %w", err)
+	}
+	return data, nil
+}
+
+// Put uploads the artifact under key
+func (r *httpRemote) Put(ctx context.Context, key string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/wasm")
+	if err := r.authorize(ctx, req, data); err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload artifact: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload artifact: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// toolchainProbes maps project marker files to the commands reporting the
+// versions of the toolchain that builds them
+var toolchainProbes = []struct {
+	marker   string
+	commands [][]string
+}{
+	{"Cargo.toml", [][]string{{"rustc", "--version"}}},
+	{"go.mod", [][]string{{"tinygo", "version"}, {"go", "version"}}},
+	{"package.json", [][]string{{"node", "--version"}}},
+	{"pyproject.toml", [][]string{{"python3", "--version"}, {"componentize-py", "--version"}}},
+}
+
+// ToolchainVersion describes the toolchain used to build the component in
+// dir, based on the project files it contains. Unavailable tools are skipped.
+func ToolchainVersion(dir string) string {
+	var versions []string
+	for _, probe := range toolchainProbes {
+		if _, err := os.Stat(filepath.Join(dir, probe.marker)); err != nil {
+			continue
+		}
+		for _, command := range probe.commands {
+			out, err := exec.Command(command[0], command[1:]...).Output() // #nosec G204 -- probe commands come from a fixed table
+			if err != nil {
+				continue
+			}
+			firstLine, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+			versions = append(versions, firstLine)
+		}
+	}
+	return strings.Join(versions, "; ")
+}
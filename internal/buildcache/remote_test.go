@@ -0,0 +1,94 @@
+package buildcache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactKey(t *testing.T) {
+	key := ArtifactKey("tool", "abc", "rustc 1.89.0")
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, ArtifactKey("tool", "abc", "rustc 1.89.0"))
+	assert.NotEqual(t, key, ArtifactKey("tool", "abc", "rustc 1.90.0"))
+	assert.NotEqual(t, key, ArtifactKey("other", "abc", "rustc 1.89.0"))
+}
+
+func TestHTTPRemote(t *testing.T) {
+	t.Setenv("FTL_BUILD_CACHE_TOKEN", "secret")
+
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	remote, err := NewRemote(ctx, server.URL+"/cache/")
+	require.NoError(t, err)
+
+	_, err = remote.Get(ctx, "key1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, remote.Put(ctx, "key1", []byte("wasm")))
+	assert.Contains(t, objects, "/cache/key1.wasm")
+
+	data, err := remote.Get(ctx, "key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wasm"), data)
+}
+
+func TestHTTPRemote_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	remote, err := NewRemote(ctx, server.URL)
+	require.NoError(t, err)
+
+	_, err = remote.Get(ctx, "key")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCacheMiss)
+	assert.True(t, strings.Contains(err.Error(), "500"))
+
+	assert.Error(t, remote.Put(ctx, "key", []byte("x")))
+}
+
+func TestNewRemote_Schemes(t *testing.T) {
+	ctx := context.Background()
+
+	remote, err := NewRemote(ctx, "gs://bucket/prefix/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com/bucket/prefix/k.wasm", remote.(*httpRemote).objectURL("k"))
+
+	_, err = NewRemote(ctx, "ftp://example.com")
+	assert.ErrorContains(t, err, "unsupported remote cache scheme")
+}
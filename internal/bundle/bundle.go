@@ -0,0 +1,512 @@
+// Package bundle packages a synthesized FTL application together with every
+// WASM artifact it references, so it can be deployed without registry access
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// FormatVersion is bumped whenever the bundle layout changes incompatibly
+	FormatVersion = 2
+
+	// ManifestFile describes the bundle and the digests of its artifacts
+	ManifestFile = "bundle.json"
+
+	// SpinManifestFile is the synthesized spin.toml, pointing at bundled artifacts
+	SpinManifestFile = "spin.toml"
+
+	// ConfigFile is the FTL application config, pointing at bundled artifacts
+	ConfigFile = "ftl.json"
+
+	// ArtifactsDir holds one WASM file per component
+	ArtifactsDir = "artifacts"
+
+	// StaticDir holds one directory per static component, with the files
+	// it serves
+	StaticDir = "static"
+
+	// maxFileSize guards extraction against decompression bombs
+	maxFileSize = 1 << 30
+)
+
+// Manifest is the table of contents of a bundle
+type Manifest struct {
+	FormatVersion int        `json:"format_version"`
+	Name          string     `json:"name"`
+	Version       string     `json:"version,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Artifacts     []Artifact `json:"artifacts"`
+	// Files are the other files of the bundle: spin.toml, ftl.json and the
+	// files of static components
+	Files []File `json:"files"`
+}
+
+// File is a file inside a bundle that is not a component's WASM
+type File struct {
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+// Artifact is a component's WASM file inside a bundle
+type Artifact struct {
+	Component string `json:"component"`
+	Path      string `json:"path"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	Origin    string `json:"origin"`
+}
+
+// Source is where a component's WASM comes from in a spin.toml: either a
+// local path or a registry package
+type Source struct {
+	Path     string
+	Registry string
+	Package  string
+	Version  string
+}
+
+// IsRegistry reports whether the source is a registry package
+func (s Source) IsRegistry() bool {
+	return s.Registry != ""
+}
+
+// String formats the source for display and for the bundle manifest
+func (s Source) String() string {
+	if s.IsRegistry() {
+		return fmt.Sprintf("%s/%s@%s", s.Registry, s.Package, s.Version)
+	}
+	return s.Path
+}
+
+// Contents is everything written into a bundle
+type Contents struct {
+	Name         string
+	Version      string
+	SpinManifest []byte
+	Config       []byte
+	// Artifacts maps component IDs to local WASM files
+	Artifacts map[string]string
+	// Origins maps component IDs to where their WASM came from
+	Origins map[string]Source
+	// StaticDirs maps the IDs of static components to the local
+	// directories they serve
+	StaticDirs map[string]string
+}
+
+// ArtifactPath is the bundle-relative path of a component's WASM file
+func ArtifactPath(componentID string) string {
+	return path.Join(ArtifactsDir, componentID+".wasm")
+}
+
+// StaticPath is the bundle-relative directory of a static component's files
+func StaticPath(componentID string) string {
+	return path.Join(StaticDir, componentID)
+}
+
+// ComponentSources reads the source of every component in a spin.toml
+func ComponentSources(spinManifest []byte) (map[string]Source, error) {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(spinManifest, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spin.toml: %w", err)
+	}
+
+	components, _ := doc["component"].(map[string]interface{})
+	sources := make(map[string]Source, len(components))
+	for id, raw := range components {
+		comp, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid component %s in spin.toml", id)
+		}
+		switch src := comp["source"].(type) {
+		case string:
+			sources[id] = Source{Path: src}
+		case map[string]interface{}:
+			s := Source{}
+			s.Registry, _ = src["registry"].(string)
+			s.Package, _ = src["package"].(string)
+			s.Version, _ = src["version"].(string)
+			if s.Registry == "" || s.Package == "" || s.Version == "" {
+				return nil, fmt.Errorf("component %s has an unsupported source; only local paths and registry packages can be bundled", id)
+			}
+			sources[id] = s
+		default:
+			return nil, fmt.Errorf("component %s has no source", id)
+		}
+	}
+	return sources, nil
+}
+
+// RewriteSpinManifest points every component at its bundled artifact and
+// drops build sections, since bundled components are already built. Static
+// components serve their bundled files instead of their directory.
+func RewriteSpinManifest(spinManifest []byte, staticDirs map[string]string) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(spinManifest, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spin.toml: %w", err)
+	}
+
+	components, _ := doc["component"].(map[string]interface{})
+	for id, raw := range components {
+		comp, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid component %s in spin.toml", id)
+		}
+		comp["source"] = ArtifactPath(id)
+		delete(comp, "build")
+		if _, ok := staticDirs[id]; ok {
+			comp["files"] = []map[string]interface{}{{"source": StaticPath(id), "destination": "/"}}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode spin.toml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Write packages the contents as a gzipped tarball and returns its manifest
+func Write(w io.Writer, c *Contents) (*Manifest, error) {
+	spinManifest, err := RewriteSpinManifest(c.SpinManifest, c.StaticDirs)
+	if err != nil {
+		return nil, err
+	}
+	staticFiles, err := collectStaticFiles(c.StaticDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		Name:          c.Name,
+		Version:       c.Version,
+		CreatedAt:     time.Now().UTC(),
+		Artifacts:     make([]Artifact, 0, len(c.Artifacts)),
+		Files:         []File{digestData(SpinManifestFile, spinManifest)},
+	}
+	if len(c.Config) > 0 {
+		manifest.Files = append(manifest.Files, digestData(ConfigFile, c.Config))
+	}
+
+	ids := make([]string, 0, len(c.Artifacts))
+	for id := range c.Artifacts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		digest, size, err := digestFile(c.Artifacts[id])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact for %s: %w", id, err)
+		}
+		manifest.Artifacts = append(manifest.Artifacts, Artifact{
+			Component: id,
+			Path:      ArtifactPath(id),
+			Digest:    digest,
+			Size:      size,
+			Origin:    c.Origins[id].String(),
+		})
+	}
+
+	staticPaths := make([]string, 0, len(staticFiles))
+	for name := range staticFiles {
+		staticPaths = append(staticPaths, name)
+	}
+	sort.Strings(staticPaths)
+	for _, name := range staticPaths {
+		digest, size, err := digestFile(staticFiles[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", staticFiles[name], err)
+		}
+		manifest.Files = append(manifest.Files, File{Path: name, Digest: digest, Size: size})
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeTarFile(tw, ManifestFile, manifestJSON, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, SpinManifestFile, spinManifest, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	if len(c.Config) > 0 {
+		if err := writeTarFile(tw, ConfigFile, c.Config, manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	for _, artifact := range manifest.Artifacts {
+		if err := copyTarFile(tw, artifact.Path, c.Artifacts[artifact.Component], artifact.Size, manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range manifest.Files {
+		src, ok := staticFiles[file.Path]
+		if !ok {
+			continue
+		}
+		if err := copyTarFile(tw, file.Path, src, file.Size, manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// Extract unpacks a bundle into dir and verifies every artifact and file
+// against the digests recorded in its manifest
+func Extract(r io.Reader, dir string) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		target, err := safeJoin(dir, hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if hdr.Size > maxFileSize {
+				return nil, fmt.Errorf("bundle entry %s is too large", hdr.Name)
+			}
+			if err := extractFile(tr, target); err != nil {
+				return nil, fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported bundle entry %s", hdr.Name)
+		}
+	}
+
+	return Verify(dir)
+}
+
+// Verify reads the manifest of an extracted bundle and checks every artifact
+// and file against its recorded digest
+func Verify(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile)) // #nosec G304 -- dir is chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: missing %s", ManifestFile)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d (expected %d)", manifest.FormatVersion, FormatVersion)
+	}
+	if _, err := os.Stat(filepath.Join(dir, SpinManifestFile)); err != nil {
+		return nil, fmt.Errorf("bundle is missing %s", SpinManifestFile)
+	}
+
+	// The configuration is checked along with the artifacts, so it cannot
+	// be changed to run something else
+	digested := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		digested[file.Path] = true
+	}
+	for _, name := range []string{SpinManifestFile, ConfigFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil && !digested[name] {
+			return nil, fmt.Errorf("bundle manifest has no digest for %s", name)
+		}
+	}
+	for _, file := range manifest.Files {
+		target, err := safeJoin(dir, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		digest, _, err := digestFile(target)
+		if err != nil {
+			return nil, fmt.Errorf("bundle is missing %s: %w", file.Path, err)
+		}
+		if digest != file.Digest {
+			return nil, fmt.Errorf("%s is corrupt: digest %s does not match %s", file.Path, digest, file.Digest)
+		}
+	}
+
+	for _, artifact := range manifest.Artifacts {
+		file, err := safeJoin(dir, artifact.Path)
+		if err != nil {
+			return nil, err
+		}
+		digest, _, err := digestFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("bundle is missing the artifact for %s: %w", artifact.Component, err)
+		}
+		if digest != artifact.Digest {
+			return nil, fmt.Errorf("artifact for %s is corrupt: digest %s does not match %s", artifact.Component, digest, artifact.Digest)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// safeJoin resolves a bundle entry inside dir, rejecting entries that would
+// escape it
+func safeJoin(dir, name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid bundle entry %s", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+func extractFile(r io.Reader, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) // #nosec G304 -- target is checked by safeJoin
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, io.LimitReader(r, maxFileSize)); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// collectStaticFiles lists the files of every static directory by their
+// bundle path. Hidden files and directories are left out, as static
+// components do not serve them.
+func collectStaticFiles(staticDirs map[string]string) (map[string]string, error) {
+	files := make(map[string]string)
+	for id, dir := range staticDirs {
+		err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if file != dir && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return fmt.Errorf("%s is not a regular file", file)
+			}
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			files[path.Join(StaticPath(id), filepath.ToSlash(rel))] = file
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read files of static component %s: %w", id, err)
+		}
+	}
+	return files, nil
+}
+
+func digestData(name string, data []byte) File {
+	sum := sha256.Sum256(data)
+	return File{Path: name, Digest: "sha256:" + hex.EncodeToString(sum[:]), Size: int64(len(data))}
+}
+
+func digestFile(file string) (string, int64, error) {
+	f, err := os.Open(file) // #nosec G304 -- artifact paths come from the bundle or the build
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func copyTarFile(tw *tar.Writer, name, src string, size int64, modTime time.Time) error {
+	f, err := os.Open(src) // #nosec G304 -- artifact paths come from the build
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.CopyN(tw, f, size); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpinManifest = `spin_manifest_version = 2
+
+[application]
+name = 'demo'
+version = '0.1.0'
+
+[component.echo]
+source = './echo'
+
+[component.echo.build]
+command = 'cargo build --release'
+
+[component.mcp-gateway]
+allowed_outbound_hosts = ['http://*.spin.internal']
+
+[component.mcp-gateway.source]
+package = 'fastertools:mcp-gateway'
+registry = 'ghcr.io'
+version = '0.0.13-alpha.0'
+
+[[trigger.http]]
+component = 'mcp-gateway'
+route = '/...'
+`
+
+func TestComponentSources(t *testing.T) {
+	sources, err := ComponentSources([]byte(testSpinManifest))
+	require.NoError(t, err)
+
+	assert.Equal(t, Source{Path: "./echo"}, sources["echo"])
+	assert.Equal(t, Source{Registry: "ghcr.io", Package: "fastertools:mcp-gateway", Version: "0.0.13-alpha.0"}, sources["mcp-gateway"])
+	assert.True(t, sources["mcp-gateway"].IsRegistry())
+	assert.Equal(t, "ghcr.io/fastertools:mcp-gateway@0.0.13-alpha.0", sources["mcp-gateway"].String())
+}
+
+func TestComponentSources_Unsupported(t *testing.T) {
+	_, err := ComponentSources([]byte(`[component.x.source]
+url = "https://example.com/x.wasm"
+digest = "sha256:abc"
+`))
+	assert.ErrorContains(t, err, "unsupported source")
+}
+
+func TestRewriteSpinManifest(t *testing.T) {
+	out, err := RewriteSpinManifest([]byte(testSpinManifest), map[string]string{"echo": "./docs"})
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, toml.Unmarshal(out, &doc))
+	components := doc["component"].(map[string]interface{})
+
+	echo := components["echo"].(map[string]interface{})
+	assert.Equal(t, "artifacts/echo.wasm", echo["source"])
+	assert.NotContains(t, echo, "build")
+	assert.Equal(t, []map[string]interface{}{{"source": "static/echo", "destination": "/"}}, echo["files"])
+
+	gateway := components["mcp-gateway"].(map[string]interface{})
+	assert.Equal(t, "artifacts/mcp-gateway.wasm", gateway["source"])
+	assert.Equal(t, []interface{}{"http://*.spin.internal"}, gateway["allowed_outbound_hosts"])
+	assert.NotContains(t, gateway, "files")
+	assert.NotEmpty(t, doc["trigger"])
+}
+
+func writeTestBundle(t *testing.T) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	echo := filepath.Join(dir, "echo.wasm")
+	gateway := filepath.Join(dir, "gateway.wasm")
+	require.NoError(t, os.WriteFile(echo, []byte("echo-wasm"), 0600))
+	require.NoError(t, os.WriteFile(gateway, []byte("gateway-wasm"), 0600))
+	docs := filepath.Join(dir, "docs")
+	require.NoError(t, os.MkdirAll(filepath.Join(docs, "guides"), 0750))
+	require.NoError(t, os.MkdirAll(filepath.Join(docs, ".git"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(docs, "guides", "intro.md"), []byte("# Intro"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(docs, ".git", "config"), []byte("secret"), 0600))
+
+	var buf bytes.Buffer
+	manifest, err := Write(&buf, &Contents{
+		Name:         "demo",
+		Version:      "0.1.0",
+		SpinManifest: []byte(testSpinManifest),
+		Config:       []byte(`{"name":"demo"}`),
+		Artifacts:    map[string]string{"echo": echo, "mcp-gateway": gateway},
+		Origins: map[string]Source{
+			"echo":        {Path: "./echo"},
+			"mcp-gateway": {Registry: "ghcr.io", Package: "fastertools:mcp-gateway", Version: "0.0.13-alpha.0"},
+		},
+		StaticDirs: map[string]string{"echo": docs},
+	})
+	require.NoError(t, err)
+	require.Len(t, manifest.Artifacts, 2)
+	assert.Equal(t, "echo", manifest.Artifacts[0].Component)
+	assert.Equal(t, int64(len("echo-wasm")), manifest.Artifacts[0].Size)
+	assert.Contains(t, manifest.Artifacts[0].Digest, "sha256:")
+	assert.Equal(t, "ghcr.io/fastertools:mcp-gateway@0.0.13-alpha.0", manifest.Artifacts[1].Origin)
+
+	paths := make([]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{SpinManifestFile, ConfigFile, "static/echo/guides/intro.md"}, paths)
+
+	return buf.Bytes()
+}
+
+func TestWriteAndExtract(t *testing.T) {
+	data := writeTestBundle(t)
+
+	dir := t.TempDir()
+	manifest, err := Extract(bytes.NewReader(data), dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "demo", manifest.Name)
+	assert.Equal(t, "0.1.0", manifest.Version)
+	assert.Len(t, manifest.Artifacts, 2)
+
+	wasm, err := os.ReadFile(filepath.Join(dir, "artifacts", "echo.wasm"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo-wasm", string(wasm))
+
+	config, err := os.ReadFile(filepath.Join(dir, ConfigFile))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"demo"}`, string(config))
+
+	spin, err := os.ReadFile(filepath.Join(dir, SpinManifestFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(spin), "artifacts/mcp-gateway.wasm")
+
+	intro, err := os.ReadFile(filepath.Join(dir, "static", "echo", "guides", "intro.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Intro", string(intro))
+	assert.NoDirExists(t, filepath.Join(dir, "static", "echo", ".git"))
+}
+
+func TestVerify_CorruptArtifact(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Extract(bytes.NewReader(writeTestBundle(t)), dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "artifacts", "echo.wasm"), []byte("tampered"), 0600))
+
+	_, err = Verify(dir)
+	assert.ErrorContains(t, err, "artifact for echo is corrupt")
+}
+
+func TestVerify_TamperedFiles(t *testing.T) {
+	for _, name := range []string{SpinManifestFile, ConfigFile, "static/echo/guides/intro.md"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			_, err := Extract(bytes.NewReader(writeTestBundle(t)), dir)
+			require.NoError(t, err)
+
+			require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte("tampered"), 0600))
+
+			_, err = Verify(dir)
+			assert.ErrorContains(t, err, name+" is corrupt")
+		})
+	}
+}
+
+func TestVerify_UndigestedConfig(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Extract(bytes.NewReader(writeTestBundle(t)), dir)
+	require.NoError(t, err)
+
+	manifest, err := Verify(dir)
+	require.NoError(t, err)
+	manifest.Files = manifest.Files[:1]
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), data, 0600))
+
+	_, err = Verify(dir)
+	assert.ErrorContains(t, err, "no digest for ftl.json")
+}
+
+func TestExtract_RejectsPathTraversal(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0600, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	parent := t.TempDir()
+	_, err = Extract(&buf, filepath.Join(parent, "out"))
+	assert.ErrorContains(t, err, "invalid bundle entry")
+	assert.NoFileExists(t, filepath.Join(parent, "evil"))
+}
+
+func TestExtract_NotABundle(t *testing.T) {
+	_, err := Extract(bytes.NewReader([]byte("plain text")), t.TempDir())
+	assert.ErrorContains(t, err, "not a bundle")
+}
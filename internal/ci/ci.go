@@ -0,0 +1,275 @@
+// Package ci generates CI/CD pipelines that build, validate and deploy FTL applications
+package ci
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/fastertools/ftl/internal/auth"
+	"github.com/fastertools/ftl/internal/buildcache"
+)
+
+// Provider is a CI/CD service a pipeline can be generated for
+type Provider string
+
+const (
+	// GitHub generates a GitHub Actions workflow
+	GitHub Provider = "github"
+	// GitLab generates a GitLab CI/CD pipeline
+	GitLab Provider = "gitlab"
+)
+
+// Providers lists the supported providers
+var Providers = []Provider{GitHub, GitLab}
+
+// ParseProvider validates a provider name
+func ParseProvider(name string) (Provider, error) {
+	for _, p := range Providers {
+		if string(p) == strings.ToLower(name) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported CI provider %q (supported: github, gitlab)", name)
+}
+
+// Path returns the project-relative location of the provider's pipeline file
+func (p Provider) Path() string {
+	if p == GitLab {
+		return ".gitlab-ci.yml"
+	}
+	return ".github/workflows/ftl.yml"
+}
+
+// Language is a component implementation language
+type Language string
+
+const (
+	Rust       Language = "rust"
+	TypeScript Language = "typescript"
+	Python     Language = "python"
+	Go         Language = "go"
+)
+
+// language describes how a component language is recognised and what its
+// builds leave behind that is worth caching between pipeline runs
+type language struct {
+	// marker is the file that identifies a component's language
+	marker string
+	// lockfiles pin dependencies and key the cache
+	lockfiles []string
+	// outputs are component-relative directories kept between runs
+	outputs []string
+}
+
+// languages are checked in order and the first marker found wins
+var languages = []struct {
+	name Language
+	language
+}{
+	{Rust, language{marker: "Cargo.toml", lockfiles: []string{"Cargo.lock"}, outputs: []string{"target"}}},
+	{TypeScript, language{marker: "package.json", lockfiles: []string{"package-lock.json"}, outputs: []string{"node_modules"}}},
+	{Python, language{marker: "pyproject.toml", lockfiles: []string{"pyproject.toml"}, outputs: []string{"venv"}}},
+	{Go, language{marker: "go.mod", lockfiles: []string{"go.sum"}}},
+}
+
+func lookup(lang Language) language {
+	for _, l := range languages {
+		if l.name == lang {
+			return l.language
+		}
+	}
+	return language{}
+}
+
+// DetectLanguage returns the language of the component in dir, or "" when
+// no marker file is found
+func DetectLanguage(dir string) Language {
+	for _, l := range languages {
+		if _, err := os.Stat(filepath.Join(dir, l.marker)); err == nil {
+			return l.name
+		}
+	}
+	return ""
+}
+
+// Component is a locally built component the pipeline must build
+type Component struct {
+	ID string
+	// Dir is the slash-separated, project-relative directory the component
+	// is built in
+	Dir string
+	// Output is the project-relative path of the built WebAssembly module
+	Output string
+	// Language is empty when it could not be detected
+	Language Language
+}
+
+// ScanComponents finds components in the immediate subdirectories of root
+// by their language marker files. It is used when the project configuration
+// cannot be read directly, such as app.cue or Go configuration.
+func ScanComponents(root string) ([]Component, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []Component
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if lang := DetectLanguage(filepath.Join(root, entry.Name())); lang != "" {
+			components = append(components, Component{ID: entry.Name(), Dir: entry.Name(), Language: lang})
+		}
+	}
+	return components, nil
+}
+
+// Options configures a generated pipeline
+type Options struct {
+	Provider Provider
+	// Environment is the FTL deployment environment and the name of the
+	// provider's deployment environment
+	Environment string
+	Components  []Component
+}
+
+// pipeline is the data the provider templates are rendered with
+type pipeline struct {
+	Provider    Provider
+	Environment string
+	Audience    string
+	Rust        bool
+	TypeScript  bool
+	Python      bool
+	Go          bool
+	// CachePaths are kept between runs so unchanged components are not rebuilt
+	CachePaths []string
+	// KeyFiles are the dependency lockfiles the cache is keyed on
+	KeyFiles []string
+	// GoSums are the go.sum files of Go components
+	GoSums []string
+}
+
+// Languages returns the distinct detected languages of components, sorted
+func Languages(components []Component) []Language {
+	seen := make(map[Language]bool)
+	var langs []Language
+	for _, c := range components {
+		if c.Language != "" && !seen[c.Language] {
+			seen[c.Language] = true
+			langs = append(langs, c.Language)
+		}
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+// Generate renders the pipeline configuration for opts.Provider
+func Generate(opts Options) ([]byte, error) {
+	if opts.Environment == "" {
+		opts.Environment = "production"
+	}
+
+	data := pipeline{
+		Provider:    opts.Provider,
+		Environment: opts.Environment,
+		Audience:    auth.DefaultOIDCAudience,
+	}
+
+	// The build cache records which component inputs were last built, so
+	// restoring it with the outputs lets 'ftl build' skip unchanged components
+	cachePaths := map[string]bool{buildcache.DefaultPath: true}
+	keyFiles := make(map[string]bool)
+	goSums := make(map[string]bool)
+	for _, c := range opts.Components {
+		switch c.Language {
+		case Rust:
+			data.Rust = true
+		case TypeScript:
+			data.TypeScript = true
+		case Python:
+			data.Python = true
+		case Go:
+			data.Go = true
+		}
+
+		if c.Output != "" {
+			cachePaths[path.Clean(filepath.ToSlash(c.Output))] = true
+		}
+		lang := lookup(c.Language)
+		for _, out := range lang.outputs {
+			cachePaths[path.Join(c.Dir, out)] = true
+		}
+		for _, lock := range lang.lockfiles {
+			keyFiles[path.Join(c.Dir, lock)] = true
+			if c.Language == Go {
+				goSums[path.Join(c.Dir, lock)] = true
+			}
+		}
+	}
+	data.CachePaths = prunePaths(sortedKeys(cachePaths))
+	data.KeyFiles = sortedKeys(keyFiles)
+	data.GoSums = sortedKeys(goSums)
+
+	var source string
+	switch opts.Provider {
+	case GitHub:
+		source = githubTemplate
+	case GitLab:
+		source = gitlabTemplate
+	default:
+		return nil, fmt.Errorf("unsupported CI provider %q", opts.Provider)
+	}
+
+	// GitHub expressions use {{ }}, so templates are delimited with [[ ]]
+	tmpl, err := template.New(string(opts.Provider)).Delims("[[", "]]").Funcs(template.FuncMap{
+		"quoteJoin": quoteJoin,
+	}).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", opts.Provider, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s pipeline: %w", opts.Provider, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// quoteJoin formats values as a comma separated list of single-quoted
+// strings, as GitHub's hashFiles expects
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// prunePaths drops sorted paths that lie inside an earlier path
+func prunePaths(paths []string) []string {
+	var pruned []string
+	for _, p := range paths {
+		if n := len(pruned); n > 0 && strings.HasPrefix(p, pruned[n-1]+"/") {
+			continue
+		}
+		pruned = append(pruned, p)
+	}
+	return pruned
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
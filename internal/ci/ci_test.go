@@ -0,0 +1,220 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseProvider(t *testing.T) {
+	for name, want := range map[string]Provider{"github": GitHub, "GitLab": GitLab} {
+		got, err := ParseProvider(name)
+		if err != nil || got != want {
+			t.Errorf("ParseProvider(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseProvider("jenkins"); err == nil {
+		t.Error("ParseProvider(jenkins) should fail")
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	root := t.TempDir()
+	for dir, marker := range map[string]string{
+		"weather":  "Cargo.toml",
+		"search":   "package.json",
+		"analysis": "pyproject.toml",
+		"convert":  "go.mod",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, marker), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	components, err := ScanComponents(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]Language)
+	for _, c := range components {
+		got[c.ID] = c.Language
+	}
+	want := map[string]Language{"weather": Rust, "search": TypeScript, "analysis": Python, "convert": Go}
+	if len(got) != len(want) {
+		t.Fatalf("ScanComponents() = %v, want %v", got, want)
+	}
+	for id, lang := range want {
+		if got[id] != lang {
+			t.Errorf("%s detected as %q, want %q", id, got[id], lang)
+		}
+	}
+}
+
+var testComponents = []Component{
+	{ID: "weather", Dir: "weather", Output: "weather/target/wasm32-wasip1/release/weather.wasm", Language: Rust},
+	{ID: "convert", Dir: "convert", Output: "convert/main.wasm", Language: Go},
+}
+
+func TestGenerate_GitHub(t *testing.T) {
+	out, err := Generate(Options{Provider: GitHub, Environment: "staging", Components: testComponents})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var workflow struct {
+		Env  map[string]string `yaml:"env"`
+		Jobs map[string]struct {
+			If          string            `yaml:"if"`
+			Environment string            `yaml:"environment"`
+			Permissions map[string]string `yaml:"permissions"`
+			Steps       []struct {
+				Name string            `yaml:"name"`
+				Uses string            `yaml:"uses"`
+				Run  string            `yaml:"run"`
+				With map[string]string `yaml:"with"`
+			} `yaml:"steps"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(out, &workflow); err != nil {
+		t.Fatalf("generated workflow is not valid YAML: %v\n%s", err, out)
+	}
+	if workflow.Env["FTL_NONINTERACTIVE"] != "1" {
+		t.Errorf("workflow does not disable prompts: env = %v", workflow.Env)
+	}
+
+	build := workflow.Jobs["build"]
+	var runs, uses []string
+	var cache map[string]string
+	for _, step := range build.Steps {
+		runs = append(runs, step.Run)
+		uses = append(uses, step.Uses)
+		if step.Uses == "actions/cache@v4" {
+			cache = step.With
+		}
+	}
+	for _, want := range []string{"ftl build", "ftl deploy --dry-run"} {
+		if !contains(runs, want) {
+			t.Errorf("build job does not run %q", want)
+		}
+	}
+	for _, want := range []string{"dtolnay/rust-toolchain@stable", "actions/setup-go@v5", "acifani/setup-tinygo@v2"} {
+		if !contains(uses, want) {
+			t.Errorf("build job does not use %s", want)
+		}
+	}
+	if contains(uses, "actions/setup-node@v4") || contains(uses, "actions/setup-python@v5") {
+		t.Error("build job sets up languages no component uses")
+	}
+
+	for _, path := range []string{".ftl/build-cache.json", "weather/target", "convert/main.wasm"} {
+		if !strings.Contains(cache["path"], path+"\n") {
+			t.Errorf("cache paths missing %s:\n%s", path, cache["path"])
+		}
+	}
+	if !strings.Contains(cache["key"], "hashFiles('convert/go.sum', 'weather/Cargo.lock')") {
+		t.Errorf("cache key = %s", cache["key"])
+	}
+
+	deploy := workflow.Jobs["deploy"]
+	if deploy.Permissions["id-token"] != "write" {
+		t.Error("deploy job cannot request an OIDC token")
+	}
+	if deploy.Environment != "staging" || !strings.Contains(deploy.If, "default_branch") {
+		t.Errorf("deploy job environment = %q, if = %q", deploy.Environment, deploy.If)
+	}
+	last := deploy.Steps[len(deploy.Steps)-1]
+	if last.Run != "ftl deploy --yes --environment staging" {
+		t.Errorf("deploy step runs %q", last.Run)
+	}
+}
+
+func TestGenerate_GitLab(t *testing.T) {
+	out, err := Generate(Options{Provider: GitLab, Components: []Component{
+		{ID: "search", Dir: "search", Language: TypeScript},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type job struct {
+		Stage    string              `yaml:"stage"`
+		Script   []string            `yaml:"script"`
+		IDTokens map[string]struct{} `yaml:"id_tokens"`
+		Cache    struct {
+			Paths []string `yaml:"paths"`
+		} `yaml:"cache"`
+		BeforeScript []string `yaml:"before_script"`
+	}
+	var config struct {
+		Stages    []string          `yaml:"stages"`
+		Variables map[string]string `yaml:"variables"`
+		Base      job               `yaml:".ftl"`
+		Build     job               `yaml:"build"`
+		Validate  job               `yaml:"validate"`
+		Deploy    job               `yaml:"deploy"`
+	}
+	if err := yaml.Unmarshal(out, &config); err != nil {
+		t.Fatalf("generated pipeline is not valid YAML: %v\n%s", err, out)
+	}
+	if config.Variables["FTL_NONINTERACTIVE"] != "1" {
+		t.Errorf("pipeline does not disable prompts: variables = %v", config.Variables)
+	}
+	pipeline := map[string]job{"build": config.Build, "validate": config.Validate, "deploy": config.Deploy}
+
+	for job, script := range map[string]string{
+		"build":    "ftl build",
+		"validate": "ftl deploy --dry-run",
+		"deploy":   "ftl deploy --yes --environment production",
+	} {
+		if !contains(pipeline[job].Script, script) {
+			t.Errorf("%s job does not run %q", job, script)
+		}
+	}
+	if len(config.Stages) != 3 {
+		t.Errorf("stages = %v", config.Stages)
+	}
+	if _, ok := config.Deploy.IDTokens["FTL_OIDC_TOKEN"]; !ok {
+		t.Error("deploy job does not request FTL_OIDC_TOKEN")
+	}
+
+	base := config.Base
+	if !contains(base.Cache.Paths, "search/node_modules") {
+		t.Errorf("cache paths = %v", base.Cache.Paths)
+	}
+	setup := strings.Join(base.BeforeScript, "\n")
+	if !strings.Contains(setup, "nodejs") || strings.Contains(setup, "rustup") {
+		t.Errorf("before_script is not tailored to TypeScript:\n%s", setup)
+	}
+}
+
+func TestGenerate_NoComponents(t *testing.T) {
+	out, err := Generate(Options{Provider: GitHub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "key: ftl-${{ runner.os }}-${{ github.sha }}") {
+		t.Errorf("expected a commit-only cache key:\n%s", out)
+	}
+	var workflow map[string]interface{}
+	if err := yaml.Unmarshal(out, &workflow); err != nil {
+		t.Fatalf("generated workflow is not valid YAML: %v", err)
+	}
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
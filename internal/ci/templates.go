@@ -0,0 +1,198 @@
+package ci
+
+// githubTemplate is a GitHub Actions workflow. Pushes and pull requests are
+// built and validated; pushes to the default branch are then deployed using
+// the job's OIDC token in place of a stored client secret.
+const githubTemplate = `# Generated by 'ftl ci init --provider [[.Provider]]'.
+#
+# Builds and validates the FTL application on every push and pull request,
+# and deploys pushes to the default branch. Deploys authenticate with the
+# job's OIDC token: set the FTL_CLIENT_ID repository variable to an FTL
+# machine client that trusts this repository.
+name: FTL
+
+on:
+  push:
+  pull_request:
+
+permissions:
+  contents: read
+
+env:
+  FTL_NONINTERACTIVE: "1"
+
+jobs:
+  build:
+    name: Build and validate
+    runs-on: ubuntu-latest
+    steps:
+[[- template "setup" .]]
+
+      - name: Build
+        run: ftl build
+
+      - name: Validate
+        run: ftl deploy --dry-run
+
+  deploy:
+    name: Deploy
+    needs: build
+    if: github.event_name == 'push' && github.ref == format('refs/heads/{0}', github.event.repository.default_branch)
+    runs-on: ubuntu-latest
+    environment: [[.Environment]]
+    concurrency: ftl-deploy-[[.Environment]]
+    permissions:
+      contents: read
+      id-token: write
+    env:
+      FTL_CLIENT_ID: ${{ vars.FTL_CLIENT_ID }}
+      FTL_OIDC_AUDIENCE: [[.Audience]]
+    steps:
+[[- template "setup" .]]
+
+      - name: Deploy
+        run: ftl deploy --yes --environment [[.Environment]]
+[[- define "setup"]]
+      - uses: actions/checkout@v4
+[[- if .Rust]]
+
+      - name: Set up Rust
+        uses: dtolnay/rust-toolchain@stable
+        with:
+          targets: wasm32-wasip1
+
+      - name: Install cargo-component
+        run: cargo install cargo-component --locked
+[[- end]]
+[[- if .TypeScript]]
+
+      - name: Set up Node.js
+        uses: actions/setup-node@v4
+        with:
+          node-version: 22
+[[- end]]
+[[- if .Python]]
+
+      - name: Set up Python
+        uses: actions/setup-python@v5
+        with:
+          python-version: "3.12"
+[[- end]]
+[[- if .Go]]
+
+      - name: Set up Go
+        uses: actions/setup-go@v5
+        with:
+          go-version: "1.24"
+          cache-dependency-path: |
+[[- range .GoSums]]
+            [[.]]
+[[- end]]
+
+      - name: Set up TinyGo
+        uses: acifani/setup-tinygo@v2
+        with:
+          tinygo-version: "0.38.0"
+[[- end]]
+
+      - name: Install Spin
+        run: |
+          curl -fsSL https://developer.fermyon.com/downloads/install.sh | bash
+          sudo mv spin /usr/local/bin/
+
+      - name: Install FTL
+        run: curl -fsSL https://raw.githubusercontent.com/fastertools/ftl/main/install.sh | bash
+
+      - name: Cache component builds
+        uses: actions/cache@v4
+        with:
+          path: |
+[[- range .CachePaths]]
+            [[.]]
+[[- end]]
+[[- if .KeyFiles]]
+          key: ftl-${{ runner.os }}-${{ hashFiles([[quoteJoin .KeyFiles]]) }}-${{ github.sha }}
+          restore-keys: |
+            ftl-${{ runner.os }}-${{ hashFiles([[quoteJoin .KeyFiles]]) }}-
+            ftl-${{ runner.os }}-
+[[- else]]
+          key: ftl-${{ runner.os }}-${{ github.sha }}
+          restore-keys: ftl-${{ runner.os }}-
+[[- end]]
+[[- end]]
+`
+
+// gitlabTemplate is a GitLab CI/CD pipeline with build, validate and deploy
+// stages. The deploy job requests an ID token for the FTL platform.
+const gitlabTemplate = `# Generated by 'ftl ci init --provider [[.Provider]]'.
+#
+# Builds and validates the FTL application in every pipeline, and deploys
+# the default branch. Deploys authenticate with a GitLab ID token: set the
+# FTL_CLIENT_ID CI/CD variable to an FTL machine client that trusts this
+# project.
+stages:
+  - build
+  - validate
+  - deploy
+
+variables:
+  FTL_NONINTERACTIVE: "1"
+
+.ftl:
+  image: debian:bookworm
+  cache:
+    key: ftl-$CI_COMMIT_REF_SLUG
+    fallback_keys:
+      - ftl-$CI_DEFAULT_BRANCH
+    paths:
+[[- range .CachePaths]]
+      - [[.]]
+[[- end]]
+  before_script:
+    - apt-get update && apt-get install -y --no-install-recommends build-essential ca-certificates curl git
+[[- if .Rust]]
+    - curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y --profile minimal --target wasm32-wasip1
+    - . "$HOME/.cargo/env"
+    - cargo install cargo-component --locked
+[[- end]]
+[[- if .TypeScript]]
+    - curl -fsSL https://deb.nodesource.com/setup_22.x | bash -
+    - apt-get install -y --no-install-recommends nodejs
+[[- end]]
+[[- if .Python]]
+    - apt-get install -y --no-install-recommends python3 python3-pip python3-venv
+[[- end]]
+[[- if .Go]]
+    - curl -fsSL https://go.dev/dl/go1.24.6.linux-amd64.tar.gz | tar -C /usr/local -xz
+    - export PATH="$PATH:/usr/local/go/bin"
+    - curl -fsSLo /tmp/tinygo.deb https://github.com/tinygo-org/tinygo/releases/download/v0.38.0/tinygo_0.38.0_amd64.deb
+    - dpkg -i /tmp/tinygo.deb
+[[- end]]
+    - (cd /tmp && curl -fsSL https://developer.fermyon.com/downloads/install.sh | bash && mv spin /usr/local/bin/)
+    - curl -fsSL https://raw.githubusercontent.com/fastertools/ftl/main/install.sh | bash
+
+build:
+  extends: .ftl
+  stage: build
+  script:
+    - ftl build
+
+validate:
+  extends: .ftl
+  stage: validate
+  script:
+    - ftl deploy --dry-run
+
+deploy:
+  extends: .ftl
+  stage: deploy
+  rules:
+    - if: $CI_COMMIT_BRANCH == $CI_DEFAULT_BRANCH
+  environment: [[.Environment]]
+  resource_group: ftl-[[.Environment]]
+  id_tokens:
+    FTL_OIDC_TOKEN:
+      aud: [[.Audience]]
+  script:
+    - ftl deploy --yes --environment [[.Environment]]
+`
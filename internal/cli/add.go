@@ -14,6 +14,8 @@ import (
 type AddOptions struct {
 	Name     string
 	Language string
+	Static   bool // Scaffold a static resources component instead of a tool
+	DryRun   bool // Print the planned changes instead of writing them
 }
 
 // newAddCmd creates the add command
@@ -34,6 +36,9 @@ Supported languages:
   - python     Python with ftl-sdk and Pydantic
   - go         Go with ftl-sdk-go
 
+With --static, it scaffolds a directory of files, such as markdown docs or
+JSON datasets, that the gateway serves as MCP resources instead.
+
 Examples:
   # Interactive mode
   ftl add
@@ -42,7 +47,15 @@ Examples:
   ftl add my-tool
 
   # With name and language
-  ftl add my-tool --language rust`,
+  ftl add my-tool --language rust
+
+  # Static resources
+  ftl add docs --static
+
+  # Preview the files and configuration change
+  ftl add my-tool --language go --dry-run
+
+In a Go CDK project, the component is added to the app in main.go.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -53,6 +66,9 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", "programming language (rust, typescript, python, go)")
+	cmd.Flags().BoolVar(&opts.Static, "static", false, "scaffold static files served as MCP resources")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the files and updated configuration instead of writing them")
+	cmd.MarkFlagsMutuallyExclusive("language", "static")
 
 	return cmd
 }
@@ -70,7 +86,7 @@ func runAdd(opts *AddOptions) error {
 			Message: "Component name:",
 			Help:    "The name of your component (lowercase, hyphens allowed)",
 		}
-		if err := survey.AskOne(prompt, &opts.Name, survey.WithValidator(survey.Required)); err != nil {
+		if err := ask(prompt, &opts.Name, "Pass the component name: ftl add <name>", survey.WithValidator(survey.Required)); err != nil {
 			return err
 		}
 	}
@@ -80,6 +96,10 @@ func runAdd(opts *AddOptions) error {
 		return err
 	}
 
+	if opts.Static {
+		return runAddStatic(scaffolder, opts.Name, opts.DryRun)
+	}
+
 	// Get language if not provided
 	if opts.Language == "" {
 		languageOptions := []string{
@@ -96,7 +116,7 @@ func runAdd(opts *AddOptions) error {
 		}
 
 		var choice string
-		if err := survey.AskOne(prompt, &choice); err != nil {
+		if err := ask(prompt, &choice, "Pass the language with --language"); err != nil {
 			return err
 		}
 
@@ -117,19 +137,77 @@ func runAdd(opts *AddOptions) error {
 		return fmt.Errorf("invalid language '%s': must be one of %v", opts.Language, validLanguages)
 	}
 
-	Info("Creating %s component '%s'", opts.Language, opts.Name)
-
-	// Generate the component
-	if err := scaffolder.GenerateComponent(opts.Name, opts.Language); err != nil {
-		return fmt.Errorf("failed to generate component: %w", err)
+	plan, err := planComponent(scaffolder, opts.Name, opts.Language, opts.DryRun)
+	if err != nil || plan == nil {
+		return err
 	}
 
 	// Print success message
 	printSuccessMessage(opts.Name, opts.Language)
+	if plan.ConfigFile == "main.go" {
+		fmt.Println()
+		fmt.Printf("📝 Added '%s' to the app in main.go\n", opts.Name)
+	}
 
 	return nil
 }
 
+// planComponent renders a component and writes it, or prints what would be
+// written when dryRun is set. The plan is nil after a dry run.
+func planComponent(scaffolder *scaffold.Scaffolder, name, language string, dryRun bool) (*scaffold.ComponentPlan, error) {
+	if dryRun {
+		Info("Planning %s component '%s'", language, name)
+	} else {
+		Info("Creating %s component '%s'", language, name)
+	}
+
+	plan, err := scaffolder.PlanComponent(name, language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate component: %w", err)
+	}
+
+	if dryRun {
+		fmt.Println("Files to create:")
+		for _, path := range plan.Paths() {
+			fmt.Printf("  %s\n", path)
+		}
+		if plan.ConfigFile != "" {
+			fmt.Println()
+			fmt.Printf("Updated %s:\n", plan.ConfigFile)
+			fmt.Print(string(plan.Config))
+		}
+		return nil, nil
+	}
+
+	if err := plan.Write(); err != nil {
+		return nil, fmt.Errorf("failed to generate component: %w", err)
+	}
+	return plan, nil
+}
+
+// runAddStatic scaffolds a static resources component
+func runAddStatic(scaffolder *scaffold.Scaffolder, name string, dryRun bool) error {
+	plan, err := planComponent(scaffolder, name, scaffold.StaticTemplate, dryRun)
+	if err != nil || plan == nil {
+		return err
+	}
+
+	Success("Component '%s' created successfully!", name)
+	fmt.Println()
+	fmt.Println("📁 Component structure:")
+	fmt.Printf("  %s/\n", name)
+	fmt.Printf("  ├── index.md\n")
+	fmt.Printf("  └── data/example.json\n")
+	fmt.Println()
+	fmt.Printf("💡 Files in %s/ are served as ftl://%s/<path> resources\n", name, name)
+	fmt.Println()
+	fmt.Println("🔨 Next steps:")
+	fmt.Println("  1. Add markdown docs, JSON datasets or other files to", name)
+	fmt.Println("  2. Run 'ftl up' to start the MCP server")
+	fmt.Println("  3. List the files with the 'resources/list' MCP method")
+	return nil
+}
+
 func printSuccessMessage(name, language string) {
 	// Determine main file based on language
 	var mainFile string
@@ -149,7 +227,6 @@ func printSuccessMessage(name, language string) {
 	fmt.Println("📁 Component structure:")
 	fmt.Printf("  %s/\n", name)
 	fmt.Printf("  ├── %s\n", getMainFileName(language))
-	fmt.Printf("  ├── Makefile\n")
 	fmt.Printf("  └── %s\n", getConfigFileName(language))
 	fmt.Println()
 	fmt.Printf("💡 Edit %s to implement your tool logic\n", mainFile)
@@ -157,9 +234,11 @@ func printSuccessMessage(name, language string) {
 	fmt.Println("🔨 Next steps:")
 	fmt.Println("  1. cd", name)
 	fmt.Println("  2. Edit the source files to implement your tools")
-	fmt.Println("  3. Run 'make build' to compile")
-	fmt.Println("  4. Return to project root and run 'ftl build'")
-	fmt.Println("  5. Run 'ftl up' to start the MCP server")
+	fmt.Println("  3. Return to project root and run 'ftl build'")
+	fmt.Println("  4. Run 'ftl up' to start the MCP server")
+	fmt.Println()
+	fmt.Printf("⚙️  'ftl build' compiles the component with the %s build profile;\n", language)
+	fmt.Println("  set build.flags, build.command or build.watch in ftl.yaml to adjust it")
 	fmt.Println()
 	fmt.Println("📚 Learn more about the FTL SDK for", language+":")
 	fmt.Printf("  https://github.com/fastertools/ftl-sdk-%s\n", getSdkSuffix(language))
@@ -94,7 +94,7 @@ func TestPrintSuccessMessage(t *testing.T) {
 	assert.Contains(t, output, "test-component/src/lib.rs")
 	assert.Contains(t, output, "ftl-sdk-rust")
 	assert.Contains(t, output, "cd test-component")
-	assert.Contains(t, output, "make build")
+	assert.Contains(t, output, "rust build profile")
 	assert.Contains(t, output, "ftl build")
 	assert.Contains(t, output, "ftl up")
 }
@@ -196,13 +196,73 @@ access: public
 	assert.DirExists(t, "my-tool")
 	assert.FileExists(t, filepath.Join("my-tool", "Cargo.toml"))
 	assert.FileExists(t, filepath.Join("my-tool", "src", "lib.rs"))
-	assert.FileExists(t, filepath.Join("my-tool", "Makefile"))
+	assert.NoFileExists(t, filepath.Join("my-tool", "Makefile"))
 
 	// Check output messages
 	assert.Contains(t, output, "Creating rust component 'my-tool'")
 	assert.Contains(t, output, "Component 'my-tool' created successfully!")
 }
 
+func TestRunAdd_Static(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	_ = os.Chdir(tmpDir)
+
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: test-app\nversion: \"0.1.0\"\n"), 0600))
+
+	// Capture output
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runAdd(&AddOptions{Name: "docs", Static: true})
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	out, _ := io.ReadAll(r)
+
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join("docs", "index.md"))
+	assert.Contains(t, string(out), "ftl://docs/<path>")
+
+	config, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "type: static")
+	assert.Contains(t, string(config), "dir: ./docs")
+}
+
+func TestRunAdd_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	_ = os.Chdir(tmpDir)
+
+	original := "name: test-app\nversion: \"0.1.0\"\n"
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(original), 0600))
+
+	// Capture output
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runAdd(&AddOptions{Name: "my-tool", Language: "rust", DryRun: true})
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	out, _ := io.ReadAll(r)
+
+	require.NoError(t, err)
+	assert.Contains(t, string(out), filepath.Join("my-tool", "Cargo.toml"))
+	assert.Contains(t, string(out), "Updated ftl.yaml:")
+	assert.Contains(t, string(out), "profile: rust")
+	assert.NoDirExists(t, "my-tool")
+
+	config, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, original, string(config))
+}
+
 func TestNewAddCmd(t *testing.T) {
 	cmd := newAddCmd()
 
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+// newAuthManager returns an auth manager backed by the system keyring.
+// Allow overriding for tests, which run without a keyring.
+var newAuthManager = func() (*auth.Manager, error) {
+	store, err := auth.NewKeyringStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credential store: %w", err)
+	}
+	return auth.NewManager(store, nil), nil
+}
+
+// newAPIClient returns a client for the FTL platform. Allow overriding for
+// tests, which use an apitest fake instead.
+var newAPIClient = func(authManager *auth.Manager) (api.FTLAPI, error) {
+	client, err := api.NewFTLClient(authManager, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/fastertools/ftl/internal/api/apitest"
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+// usePlatform points commands at a fake FTL platform, logged in
+func usePlatform(t *testing.T) *apitest.Server {
+	t.Helper()
+	server := apitest.NewServer(t)
+	t.Setenv("FTL_API_URL", server.URL)
+
+	oldAuthManager := newAuthManager
+	newAuthManager = func() (*auth.Manager, error) { return server.AuthManager(), nil }
+	t.Cleanup(func() { newAuthManager = oldAuthManager })
+	return server
+}
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/internal/convert"
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// clonedPolicy stands in for the authorization policy of a cloned app with
+// custom access, which the platform does not return. It denies every
+// request until it is replaced.
+const clonedPolicy = `package mcp.authorization
+
+# The policy of the cloned app is not available; replace this one.
+default allow := false
+`
+
+// clonedVersion is used for components whose deployed version cannot be
+// read from the registry, matching the version 'ftl deploy' defaults to
+const clonedVersion = "0.1.0"
+
+// AppCloneOptions holds options for the app clone command
+type AppCloneOptions struct {
+	Dir    string
+	Format string
+	Force  bool
+}
+
+// appCloneResult is the machine-readable form of 'ftl app clone'
+type appCloneResult struct {
+	App        string   `json:"app"`
+	File       string   `json:"file"`
+	Components []string `json:"components"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+func newAppCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app",
+		Short: "Work with deployed FTL applications",
+	}
+
+	cmd.AddCommand(newAppCloneCmd())
+
+	return cmd
+}
+
+func newAppCloneCmd() *cobra.Command {
+	opts := &AppCloneOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "clone <app-id|app-name> [dir]",
+		Short: "Create a local project from a deployed application",
+		Long: `Create a local project from a deployed application, to fork it or pick
+up work on it from another machine.
+
+The configuration is rebuilt from the platform: the app's name, access mode,
+JWT settings and its deployed components, which are referenced in the FTL
+Engine Registry at their newest version. Variables and custom authorization
+policies are not returned by the platform and must be added back by hand;
+a deny-all placeholder policy is written for apps with custom access.
+
+The project is written to dir, which defaults to the app's name.
+
+Example:
+  ftl app clone my-app
+  ftl app clone my-app my-fork --format go`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeAppNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				opts.Dir = args[1]
+			}
+			return runAppClone(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Format, "format", "f", "yaml", "Configuration format (yaml, json, cue, go)")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Overwrite an existing configuration file")
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFixed("yaml", "json", "cue", "go"))
+
+	return cmd
+}
+
+// Allow overriding for tests
+var runAppClone = runAppCloneImpl
+
+func runAppCloneImpl(ctx context.Context, appIdentifier string, opts *AppCloneOptions) error {
+	authManager, err := newAuthManager()
+	if err != nil {
+		return err
+	}
+	if _, err := authManager.GetToken(ctx); err != nil {
+		return err
+	}
+
+	apiClient, err := newAPIClient(authManager)
+	if err != nil {
+		return err
+	}
+
+	app, err := getApp(ctx, apiClient, appIdentifier)
+	if err != nil {
+		return err
+	}
+	components, err := api.IterateComponents(ctx, apiClient, app.AppId.String()).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list components: %w", err)
+	}
+
+	return cloneApp(ctx, app, components, opts)
+}
+
+// getApp looks up an app by ID or name
+func getApp(ctx context.Context, apiClient api.FTLAPI, appIdentifier string) (*api.App, error) {
+	if _, err := uuid.Parse(appIdentifier); err == nil {
+		app, err := apiClient.GetApp(ctx, appIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get app: %w", err)
+		}
+		return app, nil
+	}
+
+	response, err := apiClient.ListApps(ctx, &api.ListAppsParams{Name: &appIdentifier})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list apps: %w", err)
+	}
+	if len(response.Apps) == 0 {
+		return nil, fmt.Errorf("application '%s' not found", appIdentifier)
+	}
+	app, err := apiClient.GetApp(ctx, response.Apps[0].AppId.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app details: %w", err)
+	}
+	return app, nil
+}
+
+// appClient returns an API client and the ID of the app a command
+// works on
+func appClient(ctx context.Context, appIdentifier string) (api.FTLAPI, string, error) {
+	authManager, err := newAuthManager()
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := authManager.GetToken(ctx); err != nil {
+		return nil, "", fmt.Errorf("authentication required (run 'ftl auth login'): %w", err)
+	}
+
+	apiClient, err := newAPIClient(authManager)
+	if err != nil {
+		return nil, "", err
+	}
+
+	app, err := getApp(ctx, apiClient, appIdentifier)
+	if err != nil {
+		return nil, "", err
+	}
+	return apiClient, app.AppId.String(), nil
+}
+
+// latestComponentVersion returns the newest version of a component in a
+// registry. Overridable for tests.
+var latestComponentVersion = func(ctx context.Context, registry, pkg string) (string, error) {
+	info, err := oci.InspectComponent(ctx, registry, pkg, "")
+	if err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// cloneApp writes the configuration of a deployed app to a local project
+func cloneApp(ctx context.Context, app *api.App, components []api.ListedComponent, opts *AppCloneOptions) error {
+	format, err := convert.ParseFormat(opts.Format)
+	if err != nil {
+		return &usageError{err}
+	}
+
+	cloned, warnings := clonedApplication(ctx, app, components)
+	if err := validation.Check(cloned); err != nil {
+		return fmt.Errorf("cloned configuration is invalid: %w", err)
+	}
+	data, err := convert.Encode(cloned, format)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = app.AppName
+	}
+	file := filepath.Join(dir, format.DefaultFile())
+	if _, err := os.Stat(file); err == nil && !opts.Force {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", file)
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+
+	ids := make([]string, 0, len(cloned.Components))
+	for _, comp := range cloned.Components {
+		ids = append(ids, comp.ID)
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(appCloneResult{App: app.AppName, File: file, Components: ids, Warnings: warnings})
+	}
+	Success("Cloned %s to %s", app.AppName, file)
+	for _, w := range warnings {
+		Warn("%s", w)
+	}
+	Info("Variables are not returned by the platform; add any the app needs to %s", file)
+	if format == convert.Go {
+		Info("Create a go.mod for %s: cd %s && go mod init %s && go get github.com/fastertools/ftl", file, dir, app.AppName)
+	}
+	return nil
+}
+
+// clonedApplication rebuilds an app's configuration from what the platform
+// returns, with warnings about what could not be recovered
+func clonedApplication(ctx context.Context, app *api.App, components []api.ListedComponent) (*validation.Application, []string) {
+	var warnings []string
+	cloned := &validation.Application{
+		Name:    app.AppName,
+		Version: "0.1.0",
+		Access:  "public",
+	}
+	if app.AccessControl != nil {
+		cloned.Access = string(*app.AccessControl)
+	}
+	if cloned.Access == string(api.AppAccessControlCustom) && app.CustomAuth != nil {
+		cloned.Auth = &validation.AuthConfig{
+			JWTIssuer:   app.CustomAuth.Issuer,
+			JWTAudience: app.CustomAuth.Audience,
+			Policy:      clonedPolicy,
+		}
+		warnings = append(warnings, "the authorization policy is not returned by the platform; replace the placeholder policy, which denies every request")
+	}
+
+	for _, comp := range components {
+		if comp.RepositoryUri == nil || *comp.RepositoryUri == "" {
+			warnings = append(warnings, fmt.Sprintf("component %s has not been pushed and was skipped", comp.ComponentName))
+			continue
+		}
+		registry, repo, _ := strings.Cut(*comp.RepositoryUri, "/")
+		pkg := strings.Replace(repo, "/", ":", 1)
+
+		version, err := latestComponentVersion(ctx, registry, pkg)
+		if err != nil {
+			version = clonedVersion
+			warnings = append(warnings, fmt.Sprintf("could not read the versions of %s (%v); set its version by hand", comp.ComponentName, err))
+		}
+		cloned.Components = append(cloned.Components, &validation.Component{
+			ID:     comp.ComponentName,
+			Source: &validation.RegistrySource{Registry: registry, Package: pkg, Version: version},
+		})
+	}
+	return cloned, warnings
+}
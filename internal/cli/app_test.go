@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/internal/convert"
+	"github.com/fastertools/ftl/validation"
+)
+
+func stubLatestComponentVersion(t *testing.T, versions map[string]string) {
+	t.Helper()
+	original := latestComponentVersion
+	latestComponentVersion = func(_ context.Context, registry, pkg string) (string, error) {
+		if v, ok := versions[registry+"/"+pkg]; ok {
+			return v, nil
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() { latestComponentVersion = original })
+}
+
+func clonedTestApp(access api.AppAccessControl) *api.App {
+	app := &api.App{AppId: uuid.New(), AppName: "demo", AccessControl: &access}
+	if access == api.AppAccessControlCustom {
+		app.CustomAuth = &struct {
+			Audience string `json:"audience"`
+			Issuer   string `json:"issuer"`
+		}{Audience: "demo-api", Issuer: "https://auth.example.com"}
+	}
+	return app
+}
+
+func TestClonedApplication(t *testing.T) {
+	stubLatestComponentVersion(t, map[string]string{
+		"123.dkr.ecr.us-west-2.amazonaws.com/acme:weather": "1.2.0",
+	})
+
+	components := []api.ListedComponent{
+		{ComponentName: "weather", RepositoryUri: ptr("123.dkr.ecr.us-west-2.amazonaws.com/acme/weather")},
+		{ComponentName: "geo", RepositoryUri: ptr("ghcr.io/acme/geo")},
+		{ComponentName: "draft"},
+	}
+	app, warnings := clonedApplication(context.Background(), clonedTestApp(api.AppAccessControlCustom), components)
+
+	assert.Equal(t, "demo", app.Name)
+	assert.Equal(t, "custom", app.Access)
+	require.NotNil(t, app.Auth)
+	assert.Equal(t, "https://auth.example.com", app.Auth.JWTIssuer)
+	assert.Equal(t, "demo-api", app.Auth.JWTAudience)
+	assert.Contains(t, app.Auth.Policy, "default allow := false")
+
+	require.Len(t, app.Components, 2)
+	assert.Equal(t, "weather", app.Components[0].ID)
+	weather := app.Components[0].Source.(*validation.RegistrySource)
+	assert.Equal(t, "acme:weather", weather.Package)
+	assert.Equal(t, "1.2.0", weather.Version)
+	// Versions that cannot be read fall back to the deploy default
+	geo := app.Components[1].Source.(*validation.RegistrySource)
+	assert.Equal(t, "ghcr.io", geo.Registry)
+	assert.Equal(t, clonedVersion, geo.Version)
+
+	require.Len(t, warnings, 3)
+	assert.Contains(t, warnings[0], "policy")
+	assert.Contains(t, warnings[1], "geo")
+	assert.Contains(t, warnings[2], "draft")
+}
+
+func TestCloneApp(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "")
+	stubLatestComponentVersion(t, map[string]string{"ghcr.io/acme:geo": "2.0.0"})
+	components := []api.ListedComponent{{ComponentName: "geo", RepositoryUri: ptr("ghcr.io/acme/geo")}}
+
+	require.NoError(t, cloneApp(context.Background(), clonedTestApp(api.AppAccessControlPrivate), components, &AppCloneOptions{Format: "yaml"}))
+
+	app, err := convert.Load(filepath.Join("demo", "ftl.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "private", app.Access)
+	assert.Nil(t, app.Auth)
+	require.Len(t, app.Components, 1)
+	require.IsType(t, &validation.RegistrySource{}, app.Components[0].Source)
+	assert.Equal(t, "2.0.0", app.Components[0].Source.(*validation.RegistrySource).Version)
+
+	// An existing file is only replaced with --force
+	err = cloneApp(context.Background(), clonedTestApp(api.AppAccessControlPrivate), components, &AppCloneOptions{Format: "yaml"})
+	assert.ErrorContains(t, err, "already exists")
+	assert.NoError(t, cloneApp(context.Background(), clonedTestApp(api.AppAccessControlPrivate), components, &AppCloneOptions{Format: "yaml", Force: true}))
+}
+
+func TestCloneApp_JSON(t *testing.T) {
+	chdirTemp(t)
+	buf := setGlobalOutput(t, "json")
+	stubLatestComponentVersion(t, nil)
+
+	err := cloneApp(context.Background(), clonedTestApp(api.AppAccessControlPublic), nil, &AppCloneOptions{Dir: "fork", Format: "go"})
+	require.NoError(t, err)
+
+	var result appCloneResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "demo", result.App)
+	assert.Equal(t, filepath.Join("fork", "main.go"), result.File)
+	assert.FileExists(t, result.File)
+}
+
+func TestCloneApp_InvalidFormat(t *testing.T) {
+	chdirTemp(t)
+	err := cloneApp(context.Background(), clonedTestApp(api.AppAccessControlPublic), nil, &AppCloneOptions{Format: "toml"})
+	var usage *usageError
+	assert.ErrorAs(t, err, &usage)
+}
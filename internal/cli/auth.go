@@ -54,13 +54,11 @@ For machine authentication (CI/CD pipelines), use one of these methods:
 			}
 
 			// Create auth manager
-			loginConfig := &auth.LoginConfig{
-				NoBrowser:     noBrowser,
-				Force:         force,
-				AuthKitDomain: authKitDomain,
-			}
-			if authKitDomain == "" {
-				loginConfig.AuthKitDomain = auth.DefaultAuthKitDomain
+			loginConfig := auth.DefaultLoginConfig()
+			loginConfig.NoBrowser = noBrowser
+			loginConfig.Force = force
+			if authKitDomain != "" {
+				loginConfig.AuthKitDomain = authKitDomain
 			}
 
 			manager := auth.NewManager(store, loginConfig)
@@ -4,23 +4,46 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/fastertools/ftl/internal/buildcache"
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/internal/tracing"
 	"github.com/fastertools/ftl/spin"
 	"github.com/fastertools/ftl/synthesis"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func newBuildCmd() *cobra.Command {
 	var skipSynth bool
 	var configFile string
+	var force bool
+	var remoteCache string
+	var remoteCachePush bool
 
 	cmd := &cobra.Command{
 		Use:   "build",
 		Short: "Build the FTL application",
-		Long:  `Build compiles the FTL application and its components.`,
+		Long: `Build compiles the FTL application and its components.
+
+For ftl.yaml and ftl.json projects, builds are incremental: the files matched
+by each component's build.watch patterns are hashed and components whose
+inputs are unchanged since the last successful build are skipped. Build state
+is kept in .ftl/build-cache.json. Use --force to rebuild everything.
+
+With --remote-cache (or FTL_BUILD_CACHE), artifacts of changed components are
+first looked up in a shared cache keyed by input hash and toolchain version,
+so prebuilt WASM can be downloaded instead of compiled. Use --remote-cache-push
+(or FTL_BUILD_CACHE_PUSH=1) to upload freshly built artifacts, typically in CI.
+
+Remote cache URLs:
+  https://cache.example.com/ftl   HTTP GET/PUT (bearer token from FTL_BUILD_CACHE_TOKEN)
+  s3://bucket/prefix              S3, using the default AWS credential chain
+  gs://bucket/prefix              GCS, using GOOGLE_OAUTH_ACCESS_TOKEN`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+			ctx := cmd.Context()
 
 			// Color helpers
 			blue := color.New(color.FgBlue).SprintFunc()
@@ -52,7 +75,9 @@ func newBuildCmd() *cobra.Command {
 					fmt.Printf("%s Synthesizing spin.toml from %s\n", blue("→"), configFile)
 
 					// Use unified synthesis helper
+					_, span := tracing.Start(ctx, "build.synth", attribute.String("ftl.config", configFile))
 					manifest, err := synthesis.SynthesizeFromConfig(configFile)
+					tracing.End(span, err)
 					if err != nil {
 						return fmt.Errorf("synthesis failed: %w", err)
 					}
@@ -78,13 +103,98 @@ func newBuildCmd() *cobra.Command {
 				fmt.Printf("%s Using existing spin.toml\n", yellow("ℹ"))
 			}
 
+			// Work out which components need rebuilding
+			var plan *buildPlan
+			var cache *buildcache.Cache
+			var m *manifest.Manifest
+			if isManifestConfig(configFile) {
+				var err error
+				m, err = manifest.Load(configFile)
+				if err != nil {
+					return err
+				}
+				if err := m.ResolveBuilds(); err != nil {
+					return err
+				}
+				cache, err = buildcache.Load(buildcache.DefaultPath)
+				if err != nil {
+					return err
+				}
+				plan, err = planComponentBuilds(m, cache, force)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Try to download changed components from the remote cache
+			var remote buildcache.Remote
+			var remoteKeys map[string]string
+			if remoteCache == "" {
+				remoteCache = os.Getenv("FTL_BUILD_CACHE")
+			}
+			if plan != nil && remoteCache != "" {
+				var err error
+				remote, err = buildcache.NewRemote(ctx, remoteCache)
+				if err != nil {
+					return err
+				}
+				remoteKeys = restoreFromRemote(ctx, remote, m, plan, cache)
+				if err := cache.Save(); err != nil {
+					fmt.Printf("%s Failed to save build cache: %v\n", yellow("⚠"), err)
+				}
+			}
+
+			var buildArgs []string
+			if plan != nil {
+				for _, id := range plan.Skipped {
+					fmt.Printf("%s %s is up to date\n", green("✓"), id)
+				}
+				for _, id := range plan.Restored {
+					fmt.Printf("%s %s restored from remote cache\n", green("✓"), id)
+				}
+				if len(plan.Build) == 0 && len(plan.Skipped)+len(plan.Restored) > 0 {
+					fmt.Printf("%s All components are up to date\n", green("✓"))
+					return nil
+				}
+				if len(plan.Skipped)+len(plan.Restored) > 0 {
+					for _, id := range plan.Build {
+						buildArgs = append(buildArgs, "--component-id", id)
+					}
+				}
+			}
+
 			fmt.Printf("%s Building FTL application...\n", blue("→"))
 
-			// Use spin build
-			if err := spin.Build(ctx); err != nil {
+			// Use spin build, continuing the trace in the child process
+			var components []string
+			if plan != nil {
+				components = plan.Build
+			}
+			buildCtx, span := tracing.Start(ctx, "build.spin", attribute.StringSlice("ftl.components", components))
+			executor := spin.NewExecutor(spin.WithEnv(tracing.Environ(buildCtx)))
+			err := executor.Run(buildCtx, append([]string{"build"}, buildArgs...)...)
+			tracing.End(span, err)
+			if err != nil {
 				return fmt.Errorf("failed to build: %w", err)
 			}
 
+			// Record the inputs of what was just built
+			if plan != nil {
+				for _, id := range plan.Build {
+					if hash, ok := plan.Hashes[id]; ok {
+						cache.Update(id, hash)
+					}
+				}
+				if err := cache.Save(); err != nil {
+					fmt.Printf("%s Failed to save build cache: %v\n", yellow("⚠"), err)
+				}
+			}
+
+			// Share freshly built artifacts
+			if remote != nil && (remoteCachePush || os.Getenv("FTL_BUILD_CACHE_PUSH") == "1") {
+				pushToRemote(ctx, remote, m, plan, remoteKeys)
+			}
+
 			fmt.Printf("%s Build completed successfully\n", green("✓"))
 			return nil
 		},
@@ -92,6 +202,158 @@ func newBuildCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&skipSynth, "skip-synth", false, "Skip synthesis of spin.toml from FTL config")
 	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file to synthesize (auto-detects if not specified)")
+	_ = cmd.RegisterFlagCompletionFunc("config", completeConfigFiles)
+	cmd.Flags().BoolVar(&force, "force", false, "Rebuild all components, ignoring the build cache")
+	cmd.Flags().StringVar(&remoteCache, "remote-cache", "", "Remote build cache URL (http(s)://, s3://, gs://)")
+	cmd.Flags().BoolVar(&remoteCachePush, "remote-cache-push", false, "Upload built artifacts to the remote build cache")
 
 	return cmd
 }
+
+// buildPlan lists which local components need building
+type buildPlan struct {
+	// Build holds the components that must be rebuilt
+	Build []string
+	// Skipped holds the components whose inputs are unchanged
+	Skipped []string
+	// Restored holds the components downloaded from the remote cache
+	Restored []string
+	// Hashes holds the input hash of each component with watch patterns
+	Hashes map[string]string
+}
+
+// isManifestConfig reports whether the config file can be read as a manifest
+func isManifestConfig(configFile string) bool {
+	switch filepath.Ext(configFile) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// planComponentBuilds decides which components to rebuild. Components without
+// watch patterns, or whose output is missing, are always rebuilt.
+func planComponentBuilds(m *manifest.Manifest, cache *buildcache.Cache, force bool) (*buildPlan, error) {
+	plan := &buildPlan{Hashes: make(map[string]string)}
+
+	for _, comp := range m.Components {
+		source, ok := comp.Source.(string)
+		if !ok || comp.Build == nil || comp.Build.Command == "" {
+			continue
+		}
+
+		if len(comp.Build.Watch) == 0 {
+			plan.Build = append(plan.Build, comp.ID)
+			continue
+		}
+
+		dir := comp.Build.Workdir
+		if dir == "" {
+			dir = "."
+		}
+		hash, err := buildcache.HashInputs(dir, comp.Build.Watch, comp.Build.Command, comp.Build.Workdir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash inputs of %s: %w", comp.ID, err)
+		}
+		plan.Hashes[comp.ID] = hash
+
+		_, statErr := os.Stat(source)
+		if !force && statErr == nil && cache.Fresh(comp.ID, hash) {
+			plan.Skipped = append(plan.Skipped, comp.ID)
+			continue
+		}
+		plan.Build = append(plan.Build, comp.ID)
+	}
+
+	return plan, nil
+}
+
+// componentBuildDir returns the directory a component is built in
+func componentBuildDir(comp *manifest.Component) string {
+	if comp.Build != nil && comp.Build.Workdir != "" {
+		return comp.Build.Workdir
+	}
+	return "."
+}
+
+// restoreFromRemote downloads artifacts for planned components from the remote
+// cache, moving hits from plan.Build to plan.Restored. It returns the remote
+// key of every hashed component so built artifacts can be pushed later.
+func restoreFromRemote(ctx context.Context, remote buildcache.Remote, m *manifest.Manifest, plan *buildPlan, cache *buildcache.Cache) map[string]string {
+	keys := make(map[string]string)
+	var remaining []string
+
+	for _, id := range plan.Build {
+		hash, ok := plan.Hashes[id]
+		comp, _ := m.FindComponent(id)
+		if !ok || comp == nil {
+			remaining = append(remaining, id)
+			continue
+		}
+
+		key := buildcache.ArtifactKey(id, hash, buildcache.ToolchainVersion(componentBuildDir(comp)))
+		keys[id] = key
+
+		data, err := remote.Get(ctx, key)
+		if err != nil {
+			if err != buildcache.ErrCacheMiss {
+				Warn("Remote cache lookup for %s failed: %v", id, err)
+			}
+			remaining = append(remaining, id)
+			continue
+		}
+
+		source, _ := comp.Source.(string)
+		if err := writeArtifact(source, data); err != nil {
+			Warn("Failed to restore %s from remote cache: %v", id, err)
+			remaining = append(remaining, id)
+			continue
+		}
+
+		cache.Update(id, hash)
+		plan.Restored = append(plan.Restored, id)
+	}
+
+	plan.Build = remaining
+	return keys
+}
+
+// pushToRemote uploads the artifacts of built components to the remote cache.
+// Failures are reported but never fail the build.
+func pushToRemote(ctx context.Context, remote buildcache.Remote, m *manifest.Manifest, plan *buildPlan, keys map[string]string) {
+	for _, id := range plan.Build {
+		key, ok := keys[id]
+		comp, _ := m.FindComponent(id)
+		if !ok || comp == nil {
+			continue
+		}
+
+		source, _ := comp.Source.(string)
+		data, err := os.ReadFile(filepath.Clean(source))
+		if err != nil {
+			Warn("Failed to read artifact of %s: %v", id, err)
+			continue
+		}
+		if err := remote.Put(ctx, key, data); err != nil {
+			Warn("Failed to upload %s to remote cache: %v", id, err)
+			continue
+		}
+		Debug("Uploaded %s to remote cache", id)
+	}
+}
+
+// writeArtifact atomically writes a downloaded artifact to its source path
+func writeArtifact(path string, data []byte) error {
+	if path == "" {
+		return fmt.Errorf("component has no local source path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -2,12 +2,16 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/buildcache"
+	"github.com/fastertools/ftl/internal/manifest"
 )
 
 func TestBuildCommand(t *testing.T) {
@@ -363,3 +367,137 @@ func TestBuildCommand_AllFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanComponentBuilds(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	require.NoError(t, os.MkdirAll("tool", 0750))
+	require.NoError(t, os.WriteFile("tool/main.go", []byte("package main"), 0600))
+	require.NoError(t, os.WriteFile("tool/app.wasm", []byte("wasm"), 0600))
+
+	m := &manifest.Manifest{
+		Name: "test-app",
+		Components: []manifest.Component{
+			{
+				ID:     "tool",
+				Source: "tool/app.wasm",
+				Build:  &manifest.BuildConfig{Command: "make", Workdir: "tool", Watch: []string{"**/*.go"}},
+			},
+			{
+				ID:     "unwatched",
+				Source: "unwatched/app.wasm",
+				Build:  &manifest.BuildConfig{Command: "make"},
+			},
+			{
+				ID:     "remote",
+				Source: manifest.SourceRegistry{Registry: "ghcr.io", Package: "ns:pkg", Version: "1.0.0"},
+			},
+		},
+	}
+
+	cache, err := buildcache.Load(buildcache.DefaultPath)
+	require.NoError(t, err)
+
+	// First build: everything local is built
+	plan, err := planComponentBuilds(m, cache, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool", "unwatched"}, plan.Build)
+	assert.Empty(t, plan.Skipped)
+	require.Contains(t, plan.Hashes, "tool")
+
+	cache.Update("tool", plan.Hashes["tool"])
+
+	// Unchanged inputs are skipped; unwatched components always build
+	plan, err = planComponentBuilds(m, cache, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unwatched"}, plan.Build)
+	assert.Equal(t, []string{"tool"}, plan.Skipped)
+
+	// --force rebuilds everything
+	plan, err = planComponentBuilds(m, cache, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool", "unwatched"}, plan.Build)
+
+	// Changed inputs trigger a rebuild
+	require.NoError(t, os.WriteFile("tool/main.go", []byte("package main // changed"), 0600))
+	plan, err = planComponentBuilds(m, cache, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool", "unwatched"}, plan.Build)
+
+	// A missing output triggers a rebuild even when inputs match
+	require.NoError(t, os.WriteFile("tool/main.go", []byte("package main"), 0600))
+	require.NoError(t, os.Remove("tool/app.wasm"))
+	plan, err = planComponentBuilds(m, cache, false)
+	require.NoError(t, err)
+	assert.Contains(t, plan.Build, "tool")
+}
+
+// fakeRemote is an in-memory buildcache.Remote
+type fakeRemote struct {
+	objects map[string][]byte
+}
+
+func (f *fakeRemote) Get(_ context.Context, key string) ([]byte, error) {
+	data, ok := f.objects[key]
+	if !ok {
+		return nil, buildcache.ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (f *fakeRemote) Put(_ context.Context, key string, data []byte) error {
+	f.objects[key] = data
+	return nil
+}
+
+func TestRemoteBuildCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	require.NoError(t, os.MkdirAll("tool", 0750))
+	require.NoError(t, os.WriteFile("tool/main.go", []byte("package main"), 0600))
+
+	m := &manifest.Manifest{
+		Name: "test-app",
+		Components: []manifest.Component{{
+			ID:     "tool",
+			Source: "tool/app.wasm",
+			Build:  &manifest.BuildConfig{Command: "make", Workdir: "tool", Watch: []string{"*.go"}},
+		}},
+	}
+	remote := &fakeRemote{objects: make(map[string][]byte)}
+	ctx := context.Background()
+
+	// Machine A: cache miss, builds and pushes
+	cacheA, err := buildcache.Load(filepath.Join(tmpDir, "a.json"))
+	require.NoError(t, err)
+	plan, err := planComponentBuilds(m, cacheA, false)
+	require.NoError(t, err)
+	keys := restoreFromRemote(ctx, remote, m, plan, cacheA)
+	assert.Equal(t, []string{"tool"}, plan.Build)
+	assert.Empty(t, plan.Restored)
+
+	require.NoError(t, os.WriteFile("tool/app.wasm", []byte("built"), 0600))
+	pushToRemote(ctx, remote, m, plan, keys)
+	assert.Len(t, remote.objects, 1)
+
+	// Machine B: artifact is downloaded instead of built
+	require.NoError(t, os.Remove("tool/app.wasm"))
+	cacheB, err := buildcache.Load(filepath.Join(tmpDir, "b.json"))
+	require.NoError(t, err)
+	plan, err = planComponentBuilds(m, cacheB, false)
+	require.NoError(t, err)
+	restoreFromRemote(ctx, remote, m, plan, cacheB)
+	assert.Empty(t, plan.Build)
+	assert.Equal(t, []string{"tool"}, plan.Restored)
+
+	data, err := os.ReadFile("tool/app.wasm")
+	require.NoError(t, err)
+	assert.Equal(t, "built", string(data))
+	assert.True(t, cacheB.Fresh("tool", plan.Hashes["tool"]))
+}
@@ -0,0 +1,408 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/bundle"
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// bundleExtension is appended to the app name for default bundle file names
+const bundleExtension = ".ftl.tar.gz"
+
+// pullBundleComponent fetches a registry component; overridden in tests
+var pullBundleComponent = func(ctx context.Context, registry, pkg, version string) (string, error) {
+	return oci.NewWASMPuller().Pull(ctx, registry, pkg, version)
+}
+
+// BundleExportOptions holds options for the bundle export command
+type BundleExportOptions struct {
+	ConfigFile string
+	Output     string
+	SkipBuild  bool
+}
+
+// bundleResult is the machine-readable form of bundle export and import
+type bundleResult struct {
+	File      string            `json:"file,omitempty"`
+	Dir       string            `json:"dir,omitempty"`
+	Name      string            `json:"name"`
+	Version   string            `json:"version,omitempty"`
+	Artifacts []bundle.Artifact `json:"artifacts"`
+}
+
+func newBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Package an application for offline deployment",
+		Long: `Package an application for offline or air-gapped deployment.
+
+A bundle is a single archive holding the synthesized spin.toml, the FTL
+configuration, the WASM of every component, including registry
+components such as the MCP gateway, and the files of static components. Deploying a bundle needs no access
+to the component registries.`,
+	}
+
+	cmd.AddCommand(
+		newBundleExportCmd(),
+		newBundleImportCmd(),
+		newBundleDeployCmd(),
+	)
+
+	return cmd
+}
+
+func newBundleExportCmd() *cobra.Command {
+	opts := &BundleExportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export [bundle-file]",
+		Short: "Export the application and all of its artifacts as a bundle",
+		Long: `Export the application and all of its artifacts as a bundle.
+
+Local components are built with 'spin build' unless --skip-build is set,
+and registry components are pulled. The bundle is written to
+<name>-<version>` + bundleExtension + ` unless a file is given.
+
+Example:
+  ftl bundle export
+  ftl bundle export -f ftl.yaml release.ftl.tar.gz
+  ftl bundle export --skip-build`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.Output = args[0]
+			}
+			return runBundleExport(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ConfigFile, "file", "f", "", "FTL configuration file (auto-detects if not specified)")
+	cmd.Flags().BoolVar(&opts.SkipBuild, "skip-build", false, "Bundle existing build output without running 'spin build'")
+	_ = cmd.RegisterFlagCompletionFunc("file", completeConfigFiles)
+
+	return cmd
+}
+
+func newBundleImportCmd() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle-file>",
+		Short: "Extract and verify a bundle",
+		Long: `Extract a bundle into a directory and verify every artifact, the
+configuration and the files of static components against the digests
+recorded when it was exported.
+
+The extracted spin.toml can be run directly with 'spin up --from <dir>',
+or the directory can be deployed with 'ftl bundle deploy <dir>'.
+
+Example:
+  ftl bundle import demo-0.1.0.ftl.tar.gz
+  ftl bundle import demo-0.1.0.ftl.tar.gz -d /srv/demo`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundleImport(args[0], dir)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory to extract into (default: the bundle name)")
+
+	return cmd
+}
+
+func newBundleDeployCmd() *cobra.Command {
+	opts := &DeployOptions{
+		Variables: make(map[string]string),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "deploy <bundle>",
+		Short: "Deploy a bundle to the platform",
+		Long: `Deploy a bundle file or an extracted bundle directory to the platform.
+
+The bundled artifacts are verified and pushed as-is: nothing is
+synthesized, built or pulled from a registry.
+
+Example:
+  ftl bundle deploy demo-0.1.0.ftl.tar.gz
+  ftl bundle deploy ./demo --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundleDeploy(cmd.Context(), args[0], opts)
+		},
+	}
+
+	addDeployFlags(cmd, opts)
+
+	return cmd
+}
+
+func runBundleExport(ctx context.Context, opts *BundleExportOptions) error {
+	configFile := opts.ConfigFile
+	if configFile == "" {
+		var err error
+		if configFile, err = findConfigFile(); err != nil {
+			return err
+		}
+	}
+	configFile = filepath.Clean(configFile)
+
+	input, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	Info("Synthesizing Spin manifest from %s", configFile)
+	spinManifest, err := synthesizeFromInput(input, []string{configFile})
+	if err != nil {
+		return fmt.Errorf("failed to synthesize spin.toml: %w", err)
+	}
+
+	app, err := loadDeployManifest(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if !opts.SkipBuild {
+		if err := os.WriteFile("spin.toml", []byte(spinManifest), 0600); err != nil {
+			return fmt.Errorf("failed to write spin.toml: %w", err)
+		}
+		Info("Building local components with 'spin build'")
+		cmd := ExecCommand("spin", "build")
+		cmd.Stdout = messageOutput()
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to build components: %w", err)
+		}
+		Success("All local components built successfully")
+	}
+
+	sources, err := bundle.ComponentSources([]byte(spinManifest))
+	if err != nil {
+		return err
+	}
+	artifacts, err := resolveBundleArtifacts(ctx, sources)
+	if err != nil {
+		return err
+	}
+
+	bundledConfig, err := bundleConfig(app)
+	if err != nil {
+		return err
+	}
+
+	output := opts.Output
+	if output == "" {
+		output = defaultBundleFile(app)
+	}
+
+	f, err := os.Create(output) // #nosec G304 -- output path is chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	manifest, err := bundle.Write(f, &bundle.Contents{
+		Name:         app.Name,
+		Version:      app.Version,
+		SpinManifest: []byte(spinManifest),
+		Config:       bundledConfig,
+		Artifacts:    artifacts,
+		Origins:      sources,
+		StaticDirs:   staticDirs(app, filepath.Dir(configFile)),
+	})
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(bundleResult{
+			File:      output,
+			Name:      manifest.Name,
+			Version:   manifest.Version,
+			Artifacts: manifest.Artifacts,
+		})
+	}
+
+	Success("Wrote %s with %d artifacts", output, len(manifest.Artifacts))
+	return nil
+}
+
+// resolveBundleArtifacts finds the built WASM of every local component and
+// pulls every registry component
+func resolveBundleArtifacts(ctx context.Context, sources map[string]bundle.Source) (map[string]string, error) {
+	ids := make([]string, 0, len(sources))
+	for id := range sources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	artifacts := make(map[string]string, len(sources))
+	for _, id := range ids {
+		src := sources[id]
+		if !src.IsRegistry() {
+			wasmPath, err := findBuiltWASM(src.Path, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find built WASM for %s: %w", id, err)
+			}
+			Info("Found local component %s at %s", id, wasmPath)
+			artifacts[id] = wasmPath
+			continue
+		}
+
+		Info("Pulling component %s from %s", id, src.Registry)
+		wasmPath, err := pullBundleComponent(ctx, src.Registry, src.Package, src.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull component %s: %w", id, err)
+		}
+		Success("Pulled %s", id)
+		artifacts[id] = wasmPath
+	}
+	return artifacts, nil
+}
+
+// bundleConfig renders the FTL configuration with every component and
+// middleware pointing at its bundled artifact, and static components at
+// their bundled files
+func bundleConfig(app *validation.Application) ([]byte, error) {
+	bundled := *app
+	bundled.Components = bundledComponents(app.Components)
+	bundled.Middleware = bundledComponents(app.Middleware)
+
+	data, err := json.MarshalIndent(bundled, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundled configuration: %w", err)
+	}
+	return data, nil
+}
+
+// bundledComponents copies components with their sources replaced by
+// bundled artifacts; their build sections are dropped, since the
+// artifacts are already built
+func bundledComponents(components []*validation.Component) []*validation.Component {
+	if components == nil {
+		return nil
+	}
+	bundled := make([]*validation.Component, 0, len(components))
+	for _, comp := range components {
+		c := *comp
+		c.Source = &validation.LocalSource{Path: bundle.ArtifactPath(comp.ID)}
+		c.Build = nil
+		if c.IsStatic() {
+			c.Dir = bundle.StaticPath(comp.ID)
+		}
+		bundled = append(bundled, &c)
+	}
+	return bundled
+}
+
+// staticDirs maps the static components of an application to the
+// directories they serve
+func staticDirs(app *validation.Application, baseDir string) map[string]string {
+	dirs := make(map[string]string)
+	for _, comp := range app.Components {
+		if !comp.IsStatic() {
+			continue
+		}
+		dir := comp.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		dirs[comp.ID] = dir
+	}
+	return dirs
+}
+
+// defaultBundleFile names a bundle after the application and its version
+func defaultBundleFile(app *validation.Application) string {
+	if app.Version == "" {
+		return app.Name + bundleExtension
+	}
+	return fmt.Sprintf("%s-%s%s", app.Name, app.Version, bundleExtension)
+}
+
+// defaultImportDir derives the extraction directory from a bundle file name
+func defaultImportDir(bundleFile string) string {
+	name := filepath.Base(bundleFile)
+	for _, ext := range []string{bundleExtension, ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return name + ".bundle"
+}
+
+// extractBundle opens a bundle file and extracts it into dir
+func extractBundle(bundleFile, dir string) (*bundle.Manifest, error) {
+	f, err := os.Open(filepath.Clean(bundleFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return bundle.Extract(f, dir)
+}
+
+func runBundleImport(bundleFile, dir string) error {
+	if dir == "" {
+		dir = defaultImportDir(bundleFile)
+	}
+
+	manifest, err := extractBundle(bundleFile, dir)
+	if err != nil {
+		return err
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(bundleResult{
+			Dir:       dir,
+			Name:      manifest.Name,
+			Version:   manifest.Version,
+			Artifacts: manifest.Artifacts,
+		})
+	}
+
+	Success("Extracted %s to %s, %d artifacts verified", manifest.Name, dir, len(manifest.Artifacts))
+	Info("Deploy it with 'ftl bundle deploy %s'", dir)
+	return nil
+}
+
+func runBundleDeploy(ctx context.Context, target string, opts *DeployOptions) error {
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+
+	dir := target
+	var manifest *bundle.Manifest
+	if info.IsDir() {
+		manifest, err = bundle.Verify(dir)
+	} else {
+		dir, err = os.MkdirTemp("", "ftl-bundle-")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		manifest, err = extractBundle(target, dir)
+	}
+	if err != nil {
+		return err
+	}
+	Success("Verified %d artifacts in bundle %s", len(manifest.Artifacts), manifest.Name)
+
+	opts.ConfigFile = filepath.Join(dir, bundle.ConfigFile)
+	opts.Prebuilt = true
+	return runDeploy(ctx, opts)
+}
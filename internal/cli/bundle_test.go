@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/bundle"
+	"github.com/fastertools/ftl/validation"
+)
+
+const bundleTestConfig = `name: demo
+version: 0.1.0
+components:
+  - id: echo
+    source: ./echo
+    build:
+      command: cargo build --release
+`
+
+func setupBundleProject(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+	require.NoError(t, os.Chdir(tmpDir))
+
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(bundleTestConfig), 0600))
+	require.NoError(t, os.MkdirAll("echo", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join("echo", "echo.wasm"), []byte("echo-wasm"), 0600))
+
+	pulled := filepath.Join(tmpDir, "pulled.wasm")
+	require.NoError(t, os.WriteFile(pulled, []byte("registry-wasm"), 0600))
+
+	oldPull := pullBundleComponent
+	t.Cleanup(func() { pullBundleComponent = oldPull })
+	pullBundleComponent = func(ctx context.Context, registry, pkg, version string) (string, error) {
+		return pulled, nil
+	}
+
+	return tmpDir
+}
+
+func TestBundleExportImport(t *testing.T) {
+	tmpDir := setupBundleProject(t)
+
+	err := runBundleExport(context.Background(), &BundleExportOptions{SkipBuild: true})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(tmpDir, "demo-0.1.0.ftl.tar.gz"))
+
+	dir := filepath.Join(tmpDir, "imported")
+	require.NoError(t, runBundleImport("demo-0.1.0.ftl.tar.gz", dir))
+
+	manifest, err := bundle.Verify(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "demo", manifest.Name)
+
+	// The gateway is bundled alongside the user's component
+	components := make([]string, 0, len(manifest.Artifacts))
+	for _, artifact := range manifest.Artifacts {
+		components = append(components, artifact.Component)
+	}
+	assert.Contains(t, components, "echo")
+	assert.Contains(t, components, "mcp-gateway")
+
+	wasm, err := os.ReadFile(filepath.Join(dir, "artifacts", "echo.wasm"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo-wasm", string(wasm))
+
+	// The bundled configuration deploys the extracted artifacts as they are
+	config, err := os.ReadFile(filepath.Join(dir, bundle.ConfigFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(config), "cargo build")
+
+	app, err := loadDeployManifest(filepath.Join(dir, bundle.ConfigFile))
+	require.NoError(t, err)
+	resolveLocalSources(app, dir)
+	require.Len(t, app.Components, 1)
+	src, ok := app.Components[0].Source.(*validation.LocalSource)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "artifacts", "echo.wasm"), src.Path)
+}
+
+func TestBundleExport_KeepsComponentSettings(t *testing.T) {
+	tmpDir := setupBundleProject(t)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(`name: demo
+version: 0.1.0
+components:
+  - id: echo
+    source: ./echo
+    build:
+      command: cargo build --release
+    secrets: [api_key]
+    key_value_stores: [cache]
+    call_tools: true
+  - id: docs
+    type: static
+    dir: ./docs
+middleware:
+  - id: limiter
+    source: ./limiter.wasm
+`), 0600))
+	require.NoError(t, os.WriteFile("limiter.wasm", []byte("limiter-wasm"), 0600))
+	require.NoError(t, os.MkdirAll("docs", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join("docs", "readme.md"), []byte("# Docs"), 0600))
+
+	require.NoError(t, runBundleExport(context.Background(), &BundleExportOptions{SkipBuild: true}))
+	dir := filepath.Join(tmpDir, "imported")
+	require.NoError(t, runBundleImport("demo-0.1.0.ftl.tar.gz", dir))
+
+	app, err := loadDeployManifest(filepath.Join(dir, bundle.ConfigFile))
+	require.NoError(t, err)
+	require.Len(t, app.Components, 2)
+
+	echo := app.Components[0]
+	assert.Equal(t, []string{"api_key"}, echo.Secrets)
+	assert.Equal(t, []string{"cache"}, echo.KeyValueStores)
+	assert.True(t, echo.CallTools)
+	assert.Equal(t, &validation.LocalSource{Path: "artifacts/echo.wasm"}, echo.Source)
+
+	static := app.Components[1]
+	assert.True(t, static.IsStatic())
+	assert.Equal(t, "static/docs", static.Dir)
+	docs, err := os.ReadFile(filepath.Join(dir, "static", "docs", "readme.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Docs", string(docs))
+
+	require.Len(t, app.Middleware, 1)
+	assert.Equal(t, &validation.LocalSource{Path: "artifacts/limiter.wasm"}, app.Middleware[0].Source)
+	limiter, err := os.ReadFile(filepath.Join(dir, "artifacts", "limiter.wasm"))
+	require.NoError(t, err)
+	assert.Equal(t, "limiter-wasm", string(limiter))
+}
+
+func TestBundleExport_MissingBuildOutput(t *testing.T) {
+	setupBundleProject(t)
+	require.NoError(t, os.Remove(filepath.Join("echo", "echo.wasm")))
+
+	err := runBundleExport(context.Background(), &BundleExportOptions{SkipBuild: true, Output: "out.ftl.tar.gz"})
+	assert.ErrorContains(t, err, "failed to find built WASM for echo")
+	assert.NoFileExists(t, "out.ftl.tar.gz")
+}
+
+func TestDefaultImportDir(t *testing.T) {
+	assert.Equal(t, "demo-0.1.0", defaultImportDir("/tmp/demo-0.1.0.ftl.tar.gz"))
+	assert.Equal(t, "demo", defaultImportDir("demo.tgz"))
+	assert.Equal(t, "demo.bin.bundle", defaultImportDir("demo.bin"))
+}
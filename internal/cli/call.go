@@ -0,0 +1,396 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+const (
+	// defaultCallURL is the MCP endpoint served by 'ftl up'
+	defaultCallURL = "http://localhost:3000/mcp"
+
+	// mcpProtocolVersion is the MCP protocol version spoken by the FTL gateway
+	mcpProtocolVersion = "2025-06-18"
+)
+
+// CallOptions holds options for the call command
+type CallOptions struct {
+	Tool    string
+	URL     string
+	Args    string
+	Arg     []string
+	Auth    bool
+	Timeout time.Duration
+}
+
+func newCallCmd() *cobra.Command {
+	opts := &CallOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "call <tool>",
+		Short: "Invoke an MCP tool",
+		Long: `Invoke a tool on a running FTL application.
+
+Connects to a locally running app (started with 'ftl up') or a deployed
+MCP URL, performs the MCP handshake, calls the tool and prints the result.
+
+Arguments can be passed as a JSON object with --args, or one at a time
+with --arg key=value. Values given with --arg are parsed as JSON when
+possible (numbers, booleans, objects) and treated as strings otherwise.
+
+Examples:
+  # Call a tool on the local app
+  ftl call echo --args '{"message":"hi"}'
+
+  # Pass arguments individually
+  ftl call add --arg a=1 --arg b=2
+
+  # Call a tool on a deployed app using your FTL credentials
+  ftl call echo --url https://my-app.example.com/mcp --auth --arg message=hi
+
+  # Print the raw tools/call result
+  ftl call echo --arg message=hi --output json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeToolNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Tool = args[0]
+			return runCall(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.URL, "url", "u", defaultCallURL, "MCP endpoint URL")
+	cmd.Flags().StringVar(&opts.Args, "args", "", "tool arguments as a JSON object")
+	cmd.Flags().StringArrayVar(&opts.Arg, "arg", nil, "tool argument as key=value (repeatable)")
+	cmd.Flags().BoolVar(&opts.Auth, "auth", false, "send FTL credentials with the request")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "request timeout")
+
+	return cmd
+}
+
+func runCall(ctx context.Context, opts *CallOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	arguments, err := parseCallArguments(opts.Args, opts.Arg)
+	if err != nil {
+		return err
+	}
+
+	client := newMCPCaller(opts.URL)
+	if opts.Auth {
+		store, err := auth.NewKeyringStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize credential store: %w", err)
+		}
+		token, err := auth.NewManager(store, nil).GetOrRefreshToken(ctx)
+		if err != nil {
+			return fmt.Errorf("not logged in to FTL. Run 'ftl auth login' first")
+		}
+		client.token = token
+	}
+
+	if err := client.initialize(ctx); err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", opts.URL, err)
+	}
+
+	result, err := client.callTool(ctx, opts.Tool, arguments)
+	if err != nil {
+		return err
+	}
+
+	if structuredFormat() != "" {
+		var doc interface{}
+		if err := json.Unmarshal(result.raw, &doc); err != nil {
+			return fmt.Errorf("failed to format result: %w", err)
+		}
+		if err := writeResult(doc); err != nil {
+			return err
+		}
+		if result.IsError {
+			return &reportedError{fmt.Errorf("tool '%s' returned an error", opts.Tool)}
+		}
+		return nil
+	}
+
+	printToolResult(colorOutput, result)
+	if result.IsError {
+		return fmt.Errorf("tool '%s' returned an error", opts.Tool)
+	}
+	return nil
+}
+
+// parseCallArguments merges the --args JSON object with individual --arg pairs.
+// Individual pairs take precedence over keys in the JSON object.
+func parseCallArguments(argsJSON string, pairs []string) (map[string]interface{}, error) {
+	arguments := make(map[string]interface{})
+
+	if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &arguments); err != nil {
+			return nil, fmt.Errorf("invalid --args: must be a JSON object: %w", err)
+		}
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --arg %q: expected key=value", pair)
+		}
+
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			parsed = value
+		}
+		arguments[key] = parsed
+	}
+
+	return arguments, nil
+}
+
+// toolContent is a single content item in a tool result
+type toolContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Data     string `json:"data,omitempty"`
+}
+
+// toolResult is the result of a tools/call request
+type toolResult struct {
+	Content           []toolContent   `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+
+	raw json.RawMessage
+}
+
+// printToolResult renders text content followed by any structured content
+func printToolResult(w io.Writer, result *toolResult) {
+	for _, c := range result.Content {
+		switch c.Type {
+		case "text":
+			if result.IsError {
+				_, _ = fmt.Fprintln(w, color.RedString(c.Text))
+			} else {
+				_, _ = fmt.Fprintln(w, c.Text)
+			}
+		default:
+			_, _ = fmt.Fprintf(w, "[%s content", c.Type)
+			if c.MimeType != "" {
+				_, _ = fmt.Fprintf(w, ", %s", c.MimeType)
+			}
+			_, _ = fmt.Fprintln(w, "]")
+		}
+	}
+
+	if len(result.StructuredContent) > 0 && string(result.StructuredContent) != "null" {
+		var out bytes.Buffer
+		if err := json.Indent(&out, result.StructuredContent, "", "  "); err == nil {
+			if len(result.Content) > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+			_, _ = fmt.Fprintln(w, color.CyanString("Structured content:"))
+			_, _ = fmt.Fprintln(w, out.String())
+		}
+	}
+}
+
+// mcpCaller is a minimal MCP client over streamable HTTP
+type mcpCaller struct {
+	url        string
+	token      string
+	sessionID  string
+	nextID     int
+	httpClient *http.Client
+}
+
+func newMCPCaller(url string) *mcpCaller {
+	return &mcpCaller{
+		url:        url,
+		nextID:     1,
+		httpClient: &http.Client{},
+	}
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCResponse struct {
+	ID     interface{}     `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRPCError   `json:"error"`
+}
+
+func (c *mcpCaller) initialize(ctx context.Context) error {
+	params := map[string]interface{}{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]interface{}{
+			"name":    "ftl-cli",
+			"version": version,
+		},
+	}
+	if _, err := c.request(ctx, "initialize", params); err != nil {
+		return err
+	}
+	return c.notify(ctx, "notifications/initialized")
+}
+
+func (c *mcpCaller) callTool(ctx context.Context, name string, arguments map[string]interface{}) (*toolResult, error) {
+	raw, err := c.request(ctx, "tools/call", map[string]interface{}{
+		"name":      name,
+		"arguments": arguments,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result toolResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid tool result: %w", err)
+	}
+	result.raw = raw
+	return &result, nil
+}
+
+// mcpTool describes a tool returned by tools/list
+type mcpTool struct {
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema,omitempty"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+}
+
+func (c *mcpCaller) listTools(ctx context.Context) ([]mcpTool, error) {
+	raw, err := c.request(ctx, "tools/list", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Tools []mcpTool `json:"tools"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid tools/list result: %w", err)
+	}
+	return result.Tools, nil
+}
+
+func (c *mcpCaller) request(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	id := c.nextID
+	c.nextID++
+
+	body, err := c.post(ctx, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp jsonRPCResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response to %s: %w", method, err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s failed: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+	}
+	return resp.Result, nil
+}
+
+func (c *mcpCaller) notify(ctx context.Context, method string) error {
+	_, err := c.post(ctx, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+	})
+	return err
+}
+
+// post sends a JSON-RPC message and returns the JSON body of the reply.
+// Servers may answer with plain JSON or a single-message event stream.
+func (c *mcpCaller) post(ctx context.Context, message interface{}) ([]byte, error) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("MCP-Protocol-Version", mcpProtocolVersion)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", c.sessionID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		c.sessionID = sid
+	}
+
+	if resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return lastSSEData(body), nil
+	}
+	return body, nil
+}
+
+// lastSSEData extracts the data of the last event in an event stream
+func lastSSEData(body []byte) []byte {
+	var data, last []byte
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+		case line == "" && len(data) > 0:
+			last, data = data, nil
+		}
+	}
+	if len(data) > 0 {
+		last = data
+	}
+	return last
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCallArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		argsJSON string
+		pairs    []string
+		want     map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name: "empty",
+			want: map[string]interface{}{},
+		},
+		{
+			name:     "json object",
+			argsJSON: `{"message":"hi","count":2}`,
+			want:     map[string]interface{}{"message": "hi", "count": float64(2)},
+		},
+		{
+			name:  "key value pairs are typed when valid json",
+			pairs: []string{"a=1", "flag=true", "name=bob", "empty="},
+			want:  map[string]interface{}{"a": float64(1), "flag": true, "name": "bob", "empty": ""},
+		},
+		{
+			name:     "pairs override json",
+			argsJSON: `{"message":"hi"}`,
+			pairs:    []string{"message=bye"},
+			want:     map[string]interface{}{"message": "bye"},
+		},
+		{
+			name:     "invalid json",
+			argsJSON: `[1,2]`,
+			wantErr:  true,
+		},
+		{
+			name:    "missing equals",
+			pairs:   []string{"novalue"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCallArguments(tt.argsJSON, tt.pairs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// newTestMCPServer returns a server that answers initialize and tools/call,
+// recording the methods it receives.
+func newTestMCPServer(t *testing.T, sse bool, methods *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		method, _ := msg["method"].(string)
+		*methods = append(*methods, method)
+
+		var result interface{}
+		switch method {
+		case "initialize":
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			result = map[string]interface{}{"protocolVersion": mcpProtocolVersion}
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case "tools/call":
+			assert.Equal(t, "session-1", r.Header.Get("Mcp-Session-Id"))
+			params := msg["params"].(map[string]interface{})
+			args := params["arguments"].(map[string]interface{})
+			result = map[string]interface{}{
+				"content":           []map[string]interface{}{{"type": "text", "text": fmt.Sprintf("echo: %v", args["message"])}},
+				"structuredContent": map[string]interface{}{"message": args["message"]},
+			}
+		}
+
+		body, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": msg["id"], "result": result})
+		if sse {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", body)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+}
+
+func TestRunCall(t *testing.T) {
+	color.NoColor = true
+	defer func() { color.NoColor = false }()
+
+	for _, sse := range []bool{false, true} {
+		t.Run(fmt.Sprintf("sse=%v", sse), func(t *testing.T) {
+			var methods []string
+			server := newTestMCPServer(t, sse, &methods)
+			defer server.Close()
+
+			var buf bytes.Buffer
+			oldOutput := colorOutput
+			colorOutput = &buf
+			defer func() { colorOutput = oldOutput }()
+
+			err := runCall(context.Background(), &CallOptions{
+				Tool:    "echo",
+				URL:     server.URL,
+				Arg:     []string{"message=hi"},
+				Timeout: 5 * time.Second,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"initialize", "notifications/initialized", "tools/call"}, methods)
+			assert.Contains(t, buf.String(), "echo: hi")
+			assert.Contains(t, buf.String(), "Structured content:")
+			assert.Contains(t, buf.String(), `"message": "hi"`)
+		})
+	}
+}
+
+func TestRunCall_ToolError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg["id"] == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		result := map[string]interface{}{}
+		if msg["method"] == "tools/call" {
+			result = map[string]interface{}{
+				"content": []map[string]interface{}{{"type": "text", "text": "boom"}},
+				"isError": true,
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": msg["id"], "result": result})
+	}))
+	defer server.Close()
+
+	buf := setGlobalOutput(t, "json")
+	err := runCall(context.Background(), &CallOptions{
+		Tool:    "fail",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+	})
+	var reported *reportedError
+	require.ErrorAs(t, err, &reported)
+	assert.EqualError(t, err, "tool 'fail' returned an error")
+	assert.Contains(t, buf.String(), `"isError": true`)
+
+	buf = setGlobalOutput(t, "yaml")
+	err = runCall(context.Background(), &CallOptions{
+		Tool:    "fail",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+	})
+	assert.EqualError(t, err, "tool 'fail' returned an error")
+	assert.Contains(t, buf.String(), "isError: true")
+}
+
+func TestRunCall_RPCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      msg["id"],
+			"error":   map[string]interface{}{"code": -32600, "message": "Unsupported protocol version"},
+		})
+	}))
+	defer server.Close()
+
+	err := runCall(context.Background(), &CallOptions{
+		Tool:    "echo",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported protocol version")
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/ci"
+	"github.com/fastertools/ftl/internal/generate"
+	"github.com/fastertools/ftl/validation"
+)
+
+// CIInitOptions holds options for the ci init command
+type CIInitOptions struct {
+	Provider    string
+	ConfigFile  string
+	Environment string
+	Force       bool
+	DryRun      bool
+}
+
+// ciInitResult is the machine-readable form of 'ftl ci init'
+type ciInitResult struct {
+	Provider   string        `json:"provider"`
+	File       string        `json:"file"`
+	Written    bool          `json:"written"`
+	Languages  []ci.Language `json:"languages"`
+	Components []ciComponent `json:"components"`
+}
+
+type ciComponent struct {
+	ID       string      `json:"id"`
+	Dir      string      `json:"dir"`
+	Language ci.Language `json:"language,omitempty"`
+}
+
+func newCICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ci",
+		Short: "Set up continuous integration and deployment",
+	}
+
+	cmd.AddCommand(newCIInitCmd())
+
+	return cmd
+}
+
+func newCIInitCmd() *cobra.Command {
+	opts := &CIInitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a CI pipeline that builds, validates and deploys the app",
+		Long: `Generate a GitHub Actions workflow or GitLab CI/CD pipeline for the
+current project.
+
+The pipeline builds the app with 'ftl build' and validates it with
+'ftl deploy --dry-run' on every change, then deploys the default branch.
+Toolchains are set up for the languages of the project's components, and
+component build outputs are cached between runs so unchanged components
+are not rebuilt.
+
+Deploys authenticate with the CI provider's OIDC identity token instead of
+a stored secret. Create an FTL machine client that trusts your repository
+and set FTL_CLIENT_ID as a repository (GitHub) or CI/CD (GitLab) variable.
+
+Example:
+  ftl ci init --provider github
+  ftl ci init --provider gitlab --environment staging
+  ftl ci init --provider github --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCIInit(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Provider, "provider", "p", "", "CI provider (github, gitlab)")
+	cmd.Flags().StringVarP(&opts.ConfigFile, "file", "f", "", "FTL configuration file (auto-detects if not specified)")
+	cmd.Flags().StringVarP(&opts.Environment, "environment", "e", "production", "Deployment environment")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Overwrite an existing pipeline file")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the pipeline instead of writing it")
+	_ = cmd.MarkFlagRequired("provider")
+	_ = cmd.RegisterFlagCompletionFunc("provider", completeFixed("github", "gitlab"))
+	_ = cmd.RegisterFlagCompletionFunc("file", completeConfigFiles)
+
+	return cmd
+}
+
+func runCIInit(opts *CIInitOptions) error {
+	provider, err := ci.ParseProvider(opts.Provider)
+	if err != nil {
+		return &usageError{err}
+	}
+
+	components, err := detectCIComponents(opts.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := ci.Generate(ci.Options{
+		Provider:    provider,
+		Environment: opts.Environment,
+		Components:  components,
+	})
+	if err != nil {
+		return err
+	}
+
+	file := provider.Path()
+	written := false
+	if !opts.DryRun {
+		if _, err := os.Stat(file); err == nil && !opts.Force {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", file)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(file), err)
+		}
+		if err := os.WriteFile(file, pipeline, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		written = true
+	}
+
+	languages := ci.Languages(components)
+	if structuredFormat() != "" {
+		result := ciInitResult{
+			Provider:   string(provider),
+			File:       file,
+			Written:    written,
+			Languages:  languages,
+			Components: []ciComponent{},
+		}
+		for _, c := range components {
+			result.Components = append(result.Components, ciComponent{ID: c.ID, Dir: c.Dir, Language: c.Language})
+		}
+		return writeResult(result)
+	}
+
+	if opts.DryRun {
+		_, _ = colorOutput.Write(pipeline)
+		return nil
+	}
+
+	for _, c := range components {
+		if c.Language == "" {
+			Warn("Could not detect the language of %s; set up its toolchain in %s", c.ID, file)
+		}
+	}
+	if len(languages) == 0 {
+		Warn("No components with a known language were found")
+	} else {
+		Info("Detected languages: %v", languages)
+	}
+	Success("Generated %s", file)
+	Info("Set FTL_CLIENT_ID to an FTL machine client that trusts this repository to enable deploys")
+	return nil
+}
+
+// detectCIComponents lists the locally built components of the project. YAML
+// and JSON configuration is read directly; otherwise component directories
+// are found by their language marker files.
+func detectCIComponents(configFile string) ([]ci.Component, error) {
+	if configFile == "" {
+		for _, file := range []string{"ftl.yaml", "ftl.yml", "ftl.json"} {
+			if _, err := os.Stat(file); err == nil {
+				configFile = file
+				break
+			}
+		}
+	}
+	if configFile == "" || !isManifestConfig(configFile) {
+		return ci.ScanComponents(".")
+	}
+
+	manifest, err := loadDeployManifest(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return ciComponents(manifest, filepath.Dir(configFile)), nil
+}
+
+// ciComponents converts the manifest's local components, resolving their
+// paths against the configuration's directory
+func ciComponents(manifest *validation.Application, baseDir string) []ci.Component {
+	var components []ci.Component
+	for _, comp := range manifest.Components {
+		src, ok := comp.Source.(*validation.LocalSource)
+		if !ok || comp.Build == nil || comp.Build.Command == "" {
+			continue
+		}
+		dir := path.Join(filepath.ToSlash(baseDir), filepath.ToSlash(generate.SourceDir(src.Path, comp.Build.Workdir)))
+		c := ci.Component{
+			ID:       comp.ID,
+			Dir:      dir,
+			Language: ci.DetectLanguage(filepath.FromSlash(dir)),
+		}
+		if strings.HasSuffix(src.Path, ".wasm") {
+			c.Output = path.Join(filepath.ToSlash(baseDir), filepath.ToSlash(src.Path))
+		}
+		components = append(components, c)
+	}
+	return components
+}
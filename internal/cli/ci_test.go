@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ciTestConfig = `name: demo
+version: "0.1.0"
+components:
+  - id: weather
+    source: weather/target/wasm32-wasip1/release/weather.wasm
+    build:
+      command: make build
+      workdir: weather
+  - id: search
+    source: search/dist/search.wasm
+    build:
+      command: npm run build
+      workdir: search
+  - id: remote
+    source:
+      registry: ghcr.io
+      package: example:remote
+      version: 1.0.0
+`
+
+func setupCIProject(t *testing.T) {
+	t.Helper()
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(ciTestConfig), 0600))
+	require.NoError(t, os.MkdirAll("weather", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join("weather", "Cargo.toml"), nil, 0600))
+	require.NoError(t, os.MkdirAll("search", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join("search", "package.json"), nil, 0600))
+}
+
+func TestRunCIInit_GitHub(t *testing.T) {
+	setupCIProject(t)
+	setGlobalOutput(t, "")
+
+	require.NoError(t, runCIInit(&CIInitOptions{Provider: "github", Environment: "production"}))
+
+	data, err := os.ReadFile(filepath.Join(".github", "workflows", "ftl.yml"))
+	require.NoError(t, err)
+	workflow := string(data)
+	assert.Contains(t, workflow, "dtolnay/rust-toolchain@stable")
+	assert.Contains(t, workflow, "actions/setup-node@v4")
+	assert.NotContains(t, workflow, "setup-python")
+	assert.Contains(t, workflow, "search/dist/search.wasm")
+	assert.Contains(t, workflow, "id-token: write")
+
+	// An existing pipeline is only replaced with --force
+	err = runCIInit(&CIInitOptions{Provider: "github", Environment: "production"})
+	assert.ErrorContains(t, err, "already exists")
+	assert.NoError(t, runCIInit(&CIInitOptions{Provider: "github", Environment: "production", Force: true}))
+}
+
+func TestRunCIInit_GitLabJSON(t *testing.T) {
+	setupCIProject(t)
+	buf := setGlobalOutput(t, "json")
+
+	require.NoError(t, runCIInit(&CIInitOptions{Provider: "gitlab", Environment: "staging", DryRun: true}))
+
+	var result ciInitResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "gitlab", result.Provider)
+	assert.Equal(t, ".gitlab-ci.yml", result.File)
+	assert.False(t, result.Written)
+	assert.Len(t, result.Components, 2)
+	assert.Equal(t, []string{"rust", "typescript"}, []string{string(result.Languages[0]), string(result.Languages[1])})
+
+	_, err := os.Stat(".gitlab-ci.yml")
+	assert.True(t, os.IsNotExist(err), "dry run must not write the pipeline")
+}
+
+func TestRunCIInit_UnknownProvider(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "")
+
+	err := runCIInit(&CIInitOptions{Provider: "jenkins"})
+	var usage *usageError
+	assert.ErrorAs(t, err, &usage)
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/internal/manifest"
+)
+
+// completionTimeout bounds network calls made while completing, so a slow
+// platform or app never blocks the shell
+const completionTimeout = 3 * time.Second
+
+// configFileExtensions are the extensions offered when completing config files
+var configFileExtensions = []string{"yaml", "yml", "json", "cue", "go"}
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a completion script for ftl in the given shell.
+
+Completions include component names from ftl.yaml, app names from the FTL
+platform, tool names from a running app and FTL config files.
+
+Examples:
+  # Bash (current session)
+  source <(ftl completion bash)
+
+  # Bash (all sessions, Linux)
+  ftl completion bash > /etc/bash_completion.d/ftl
+
+  # Zsh
+  ftl completion zsh > "${fpath[1]}/_ftl"
+
+  # Fish
+  ftl completion fish > ~/.config/fish/completions/ftl.fish
+
+  # PowerShell
+  ftl completion powershell | Out-String | Invoke-Expression`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell '%s'", args[0])
+		},
+	}
+}
+
+// completeConfigFiles completes FTL configuration files
+func completeConfigFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return configFileExtensions, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeConfigFileArg completes a single positional config file argument
+func completeConfigFileArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeConfigFiles(cmd, args, toComplete)
+}
+
+// completeFixed returns a completion function offering a fixed set of values
+func completeFixed(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeComponentNames completes component IDs from the project manifest
+func completeComponentNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	m, err := manifest.LoadAuto()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(m.Components))
+	for _, comp := range m.Components {
+		names = append(names, comp.ID)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeComponentArg completes a single positional component argument
+func completeComponentArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeComponentNames(cmd, args, toComplete)
+}
+
+// Allow overriding for tests
+var listAppNames = listAppNamesImpl
+
+func listAppNamesImpl(ctx context.Context) ([]string, error) {
+	authManager, err := newAuthManager()
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient, err := newAPIClient(authManager)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	apps := api.IterateApps(ctx, apiClient, &api.ListAppsParams{})
+	for apps.Next() {
+		names = append(names, apps.Value().AppName)
+	}
+	return names, apps.Err()
+}
+
+// completeAppNames completes a single app name argument from the platform.
+// Completion silently offers nothing when the user is not logged in.
+func completeAppNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	names, err := listAppNames(ctx)
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("failed to list apps: %v", err), false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePluginNames completes the names of installed plugins as top-level commands
+func completePluginNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, p := range discoverPlugins(cmd.Root(), pluginDirs()) {
+		if !p.Shadowed && strings.HasPrefix(p.Name, toComplete) {
+			names = append(names, cobra.CompletionWithDesc(p.Name, "Plugin "+p.Path))
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeToolNames completes tool names by listing the tools of the app at
+// the command's --url, which defaults to the local 'ftl up' endpoint
+func completeToolNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	url := defaultCallURL
+	if flag := cmd.Flags().Lookup("url"); flag != nil {
+		url = flag.Value.String()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	client := newMCPCaller(url)
+	if err := client.initialize(ctx); err != nil {
+		cobra.CompDebugln(fmt.Sprintf("failed to connect to %s: %v", url, err), false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	tools, err := client.listTools(ctx)
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("failed to list tools: %v", err), false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		completions = append(completions, cobra.CompletionWithDesc(tool.Name, tool.Description))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCompletion runs cobra's hidden __complete command and returns the
+// offered completions followed by the directive line
+func runCompletion(t *testing.T, args ...string) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs(append([]string{"__complete"}, args...))
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	require.NoError(t, rootCmd.Execute())
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestCompletion_AppNames(t *testing.T) {
+	oldList := listAppNames
+	defer func() { listAppNames = oldList }()
+	listAppNames = func(ctx context.Context) ([]string, error) {
+		return []string{"weather-app", "search-app"}, nil
+	}
+
+	for _, command := range []string{"status", "delete", "logs"} {
+		t.Run(command, func(t *testing.T) {
+			got := runCompletion(t, command, "")
+			assert.Equal(t, []string{"weather-app", "search-app", ":4"}, got)
+		})
+	}
+
+	// Only the first argument is an app name
+	assert.Equal(t, []string{":4"}, runCompletion(t, "status", "weather-app", ""))
+
+	listAppNames = func(ctx context.Context) ([]string, error) {
+		return nil, errors.New("not logged in")
+	}
+	assert.Equal(t, []string{":4"}, runCompletion(t, "status", ""))
+}
+
+func TestCompletion_ComponentNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "ftl.yaml"), []byte(`name: test-app
+version: 0.1.0
+components:
+  - id: api
+    source: ./api
+  - id: worker
+    source: ./worker
+`), 0600))
+
+	oldWd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	assert.Equal(t, []string{"api", "worker", ":4"}, runCompletion(t, "component", "remove", ""))
+	assert.Equal(t, []string{"api", "worker", ":4"}, runCompletion(t, "tools", "export", "--component", ""))
+}
+
+func TestCompletion_ConfigFiles(t *testing.T) {
+	got := runCompletion(t, "synth", "")
+	assert.Equal(t, append(append([]string{}, configFileExtensions...), ":8"), got)
+
+	got = runCompletion(t, "deploy", "--file", "")
+	assert.Equal(t, append(append([]string{}, configFileExtensions...), ":8"), got)
+}
+
+func TestCompletion_ToolNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg["id"] == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		result := map[string]interface{}{}
+		if msg["method"] == "tools/list" {
+			result["tools"] = testTools
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": msg["id"], "result": result})
+	}))
+	defer server.Close()
+
+	got := runCompletion(t, "call", "--url", server.URL, "")
+	assert.Equal(t, []string{
+		"weather__get_forecast\tGet the forecast for a city",
+		"echo__echo",
+		":4",
+	}, got)
+
+	// An unreachable app offers no completions
+	server.Close()
+	assert.Equal(t, []string{":4"}, runCompletion(t, "call", "--url", server.URL, ""))
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fastertools/ftl/internal/manifest"
@@ -13,7 +14,7 @@ func newComponentCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "component",
 		Short: "Manage FTL components",
-		Long:  `Manage FTL components including adding, removing, and listing components.`,
+		Long:  `Manage FTL components including adding, removing, listing and attaching metadata to components.`,
 	}
 
 	// Add subcommands
@@ -21,27 +22,74 @@ func newComponentCmd() *cobra.Command {
 		newComponentAddCmd(),
 		newComponentListCmd(),
 		newComponentRemoveCmd(),
+		newComponentInspectCmd(),
+		newComponentReleaseCmd(),
+		newComponentAttachCmd(),
+		newComponentListAttachmentsCmd(),
+		newComponentVerifyCmd(),
 	)
 
 	return cmd
 }
 
 func newComponentListCmd() *cobra.Command {
-	return &cobra.Command{
+	var format string
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all components",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return listComponents()
+			return listComponents(resolveOutputFormat(format))
 		},
 	}
+
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
+
+	return cmd
+}
+
+// componentSummary is the machine-readable form of a listed component
+type componentSummary struct {
+	ID        string   `json:"id"`
+	Source    string   `json:"source"`
+	Build     string   `json:"build,omitempty"`
+	Variables []string `json:"variables"`
 }
 
-func listComponents() error {
+func listComponents(format string) error {
 	// Load manifest (tries ftl.yaml, ftl.yml, ftl.json)
 	m, err := manifest.LoadAuto()
 	if err != nil {
 		return fmt.Errorf("failed to load manifest: %w", err)
 	}
+	if err := m.ResolveBuilds(); err != nil {
+		return err
+	}
+
+	switch format {
+	case "json", "yaml":
+		summaries := make([]componentSummary, 0, len(m.Components))
+		for _, comp := range m.Components {
+			summary := componentSummary{
+				ID:        comp.ID,
+				Source:    componentSourceString(comp.Source),
+				Variables: make([]string, 0, len(comp.Variables)),
+			}
+			if comp.Build != nil {
+				summary.Build = comp.Build.Command
+			}
+			for name := range comp.Variables {
+				summary.Variables = append(summary.Variables, name)
+			}
+			sort.Strings(summary.Variables)
+			summaries = append(summaries, summary)
+		}
+		return NewDataWriter(colorOutput, format).WriteStruct(summaries)
+	case "table":
+	default:
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
+	}
 
 	if len(m.Components) == 0 {
 		fmt.Println("No components found.")
@@ -60,27 +108,7 @@ func listComponents() error {
 		fmt.Printf("  • %s\n", comp.ID)
 
 		// Show source info
-		switch src := comp.Source.(type) {
-		case string:
-			fmt.Printf("    Source: %s\n", src)
-		case manifest.SourceRegistry:
-			fmt.Printf("    Source: %s/%s:%s\n",
-				src.Registry, src.Package, src.Version)
-		case map[string]interface{}:
-			// Handle case where source is still a map (shouldn't happen with proper unmarshaling)
-			if registry, ok := src["registry"].(string); ok {
-				fmt.Printf("    Source: %s/%s:%s\n",
-					registry, src["package"], src["version"])
-			}
-		case map[interface{}]interface{}:
-			// Handle case where source is still a map (shouldn't happen with proper unmarshaling)
-			if registry, ok := src["registry"].(string); ok {
-				fmt.Printf("    Source: %s/%s:%s\n",
-					registry, src["package"], src["version"])
-			}
-		default:
-			fmt.Printf("    Source: %v (type: %T)\n", src, src)
-		}
+		fmt.Printf("    Source: %s\n", componentSourceString(comp.Source))
 
 		// Show build info if present
 		if comp.Build != nil {
@@ -98,22 +126,47 @@ func listComponents() error {
 	return nil
 }
 
+// componentSourceString renders a component source as a path or registry reference
+func componentSourceString(source interface{}) string {
+	switch src := source.(type) {
+	case string:
+		return src
+	case manifest.SourceRegistry:
+		return fmt.Sprintf("%s/%s:%s", src.Registry, src.Package, src.Version)
+	case map[string]interface{}:
+		// Handle case where source is still a map (shouldn't happen with proper unmarshaling)
+		if registry, ok := src["registry"].(string); ok {
+			return fmt.Sprintf("%s/%s:%s", registry, src["package"], src["version"])
+		}
+	case map[interface{}]interface{}:
+		// Handle case where source is still a map (shouldn't happen with proper unmarshaling)
+		if registry, ok := src["registry"].(string); ok {
+			return fmt.Sprintf("%s/%s:%s", registry, src["package"], src["version"])
+		}
+	}
+	return fmt.Sprintf("%v (type: %T)", source, source)
+}
+
 func newComponentRemoveCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "remove [name]",
-		Short: "Remove a component",
-		Args:  cobra.MaximumNArgs(1),
+	var yes bool
+	cmd := &cobra.Command{
+		Use:               "remove [name]",
+		Short:             "Remove a component",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeComponentArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var name string
 			if len(args) > 0 {
 				name = args[0]
 			}
-			return removeComponent(name)
+			return removeComponent(name, yes)
 		},
 	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+	return cmd
 }
 
-func removeComponent(name string) error {
+func removeComponent(name string, yes bool) error {
 	// Load manifest (tries ftl.yaml, ftl.yml, ftl.json)
 	m, err := manifest.LoadAuto()
 	if err != nil {
@@ -135,7 +188,7 @@ func removeComponent(name string) error {
 			Message: "Select component to remove:",
 			Options: options,
 		}
-		if err := survey.AskOne(prompt, &name); err != nil {
+		if err := ask(prompt, &name, "Pass the component to remove: ftl component remove <name>"); err != nil {
 			return err
 		}
 	}
@@ -146,13 +199,15 @@ func removeComponent(name string) error {
 	}
 
 	// Confirm removal
-	confirm := false
-	prompt := &survey.Confirm{
-		Message: fmt.Sprintf("Remove component '%s'?", name),
-		Default: false,
-	}
-	if err := survey.AskOne(prompt, &confirm); err != nil {
-		return err
+	confirm := yes
+	if !confirm {
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Remove component '%s'?", name),
+			Default: false,
+		}
+		if err := ask(prompt, &confirm, "Pass --yes to remove the component without confirmation"); err != nil {
+			return err
+		}
 	}
 
 	if !confirm {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/validation"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -54,6 +55,7 @@ func newComponentAddCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&opts.Description, "description", "d", "", "Component description")
 	cmd.Flags().StringVarP(&opts.Template, "template", "t", "", "Use a template (go-http, rust-wasm, js-http, python-http)")
 	cmd.Flags().StringVarP(&opts.Build, "build", "b", "", "Build command")
+	cmd.MarkFlagsMutuallyExclusive("source", "registry", "template")
 
 	return cmd
 }
@@ -70,7 +72,7 @@ func runComponentAdd(opts *AddComponentOptions) error {
 		prompt := &survey.Input{
 			Message: "Component name:",
 		}
-		if err := survey.AskOne(prompt, &opts.Name, survey.WithValidator(survey.Required)); err != nil {
+		if err := ask(prompt, &opts.Name, "Pass the component name: ftl component add <name>", survey.WithValidator(survey.Required)); err != nil {
 			return err
 		}
 	}
@@ -100,6 +102,9 @@ func runComponentAdd(opts *AddComponentOptions) error {
 	if err := m.AddComponent(component); err != nil {
 		return err
 	}
+	if err := validation.Check(m); err != nil {
+		return fmt.Errorf("invalid component '%s': %w", opts.Name, err)
+	}
 
 	// Save manifest (to the same file format)
 	if err := m.SaveAuto(); err != nil {
@@ -163,63 +168,25 @@ func createFromTemplate(opts *AddComponentOptions) manifest.Component {
 }
 
 func createFromRegistry(opts *AddComponentOptions) manifest.Component {
-	comp := manifest.Component{
-		ID: opts.Name,
-	}
-
-	// Parse registry string
 	// Format: registry/namespace:package@version
 	// Examples:
 	//   - ghcr.io/fastertools:geo@0.0.1
-	//   - docker.io/library:nginx@latest
 	//   - myregistry.com/myorg:mypackage@1.0.0
-	registryStr := opts.Registry
-
-	// Find the @ to separate version
-	atIndex := strings.LastIndex(registryStr, "@")
-	if atIndex == -1 {
-		color.Yellow("⚠ Invalid format. Expected: registry/namespace:package@version")
-		comp.Source = opts.Registry
-		return comp
-	}
-
-	mainPart := registryStr[:atIndex]
-	version := registryStr[atIndex+1:]
-
-	// Split mainPart into registry/namespace and package
-	// Look for the last slash to separate registry from namespace:package
-	slashIndex := strings.Index(mainPart, "/")
-	if slashIndex == -1 {
-		color.Yellow("⚠ Invalid registry format. Using as-is.")
-		comp.Source = opts.Registry
-		return comp
+	// Missing parts are left empty and reported by validation
+	ref, version := opts.Registry, ""
+	if at := strings.LastIndex(ref, "@"); at != -1 {
+		ref, version = ref[:at], ref[at+1:]
 	}
+	registry, pkg, _ := strings.Cut(ref, "/")
 
-	registry := mainPart[:slashIndex]
-	namespacePackage := mainPart[slashIndex+1:]
-
-	// Split namespace:package
-	colonIndex := strings.Index(namespacePackage, ":")
-	if colonIndex == -1 {
-		// If no colon, treat the whole thing as the package (no namespace)
-		source := manifest.SourceRegistry{
+	return manifest.Component{
+		ID: opts.Name,
+		Source: manifest.SourceRegistry{
 			Registry: registry,
-			Package:  namespacePackage,
+			Package:  pkg, // Spin's namespace:package format
 			Version:  version,
-		}
-		comp.Source = source
-		return comp
-	}
-
-	// Store as namespace:package in the Package field (Spin's format)
-	source := manifest.SourceRegistry{
-		Registry: registry,
-		Package:  namespacePackage, // Already in namespace:package format
-		Version:  version,
+		},
 	}
-	comp.Source = source
-
-	return comp
 }
 
 func createFromLocal(opts *AddComponentOptions) manifest.Component {
@@ -249,7 +216,7 @@ func createInteractive(opts *AddComponentOptions) (manifest.Component, error) {
 		Message: "Component source:",
 		Options: []string{"Local path", "Registry", "Create from template"},
 	}
-	if err := survey.AskOne(sourcePrompt, &sourceType); err != nil {
+	if err := ask(sourcePrompt, &sourceType, "Pass the component's source with --source, --registry or --template"); err != nil {
 		return manifest.Component{}, err
 	}
 
@@ -259,7 +226,7 @@ func createInteractive(opts *AddComponentOptions) (manifest.Component, error) {
 			Message: "Path to component:",
 			Default: fmt.Sprintf("./components/%s", opts.Name),
 		}
-		if err := survey.AskOne(pathPrompt, &opts.Source); err != nil {
+		if err := ask(pathPrompt, &opts.Source, "Pass the path with --source"); err != nil {
 			return manifest.Component{}, err
 		}
 		return createFromLocal(opts), nil
@@ -268,7 +235,7 @@ func createInteractive(opts *AddComponentOptions) (manifest.Component, error) {
 		regPrompt := &survey.Input{
 			Message: "Registry source (registry/namespace:package@version):",
 		}
-		if err := survey.AskOne(regPrompt, &opts.Registry); err != nil {
+		if err := ask(regPrompt, &opts.Registry, "Pass the registry source with --registry"); err != nil {
 			return manifest.Component{}, err
 		}
 		return createFromRegistry(opts), nil
@@ -278,7 +245,7 @@ func createInteractive(opts *AddComponentOptions) (manifest.Component, error) {
 			Message: "Select template:",
 			Options: []string{"go-http", "rust-wasm", "js-http", "python-http"},
 		}
-		if err := survey.AskOne(templatePrompt, &opts.Template); err != nil {
+		if err := ask(templatePrompt, &opts.Template, "Pass the template with --template"); err != nil {
 			return manifest.Component{}, err
 		}
 		return createFromTemplate(opts), nil
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/oci"
+)
+
+// attachmentTypes maps the short names accepted by --type to artifact types
+var attachmentTypes = map[string]string{
+	"sbom":       oci.ArtifactTypeSPDX,
+	"spdx":       oci.ArtifactTypeSPDX,
+	"cyclonedx":  oci.ArtifactTypeCycloneDX,
+	"signature":  oci.ArtifactTypeSignature,
+	"schema":     oci.ArtifactTypeSchema,
+	"readme":     oci.ArtifactTypeReadme,
+	"provenance": oci.ArtifactTypeProvenance,
+}
+
+func newComponentAttachCmd() *cobra.Command {
+	var artifactType string
+
+	cmd := &cobra.Command{
+		Use:   "attach <component> <file>",
+		Short: "Attach a metadata file to a published component",
+		Long: `Attach a metadata file such as an SBOM, signature, schema or README to a
+component published in an OCI registry.
+
+The file is pushed as an artifact that refers to the component image through
+the OCI referrers API, so it travels with the component without changing it.
+The component is either the ID of a registry component in ftl.yaml or a
+reference in the form registry/namespace:package@version.
+
+The artifact type is inferred from the file name (*.spdx.json, *.cdx.json,
+*.schema.json, *.sigstore.json, README*.md) unless --type is given. --type
+accepts sbom, spdx, cyclonedx, signature, schema, readme or a media type.
+
+Example:
+  ftl component attach geo sbom.spdx.json
+  ftl component attach ghcr.io/acme:geo@1.0.0 tools.json --type schema`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeComponentArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runComponentAttach(cmd.Context(), args[0], args[1], artifactType)
+		},
+	}
+
+	cmd.Flags().StringVarP(&artifactType, "type", "t", "", "Artifact type (sbom, spdx, cyclonedx, signature, schema, readme or a media type)")
+	_ = cmd.RegisterFlagCompletionFunc("type", completeFixed("sbom", "spdx", "cyclonedx", "signature", "schema", "readme"))
+
+	return cmd
+}
+
+func newComponentListAttachmentsCmd() *cobra.Command {
+	var artifactType string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "list-attachments <component>",
+		Short: "List the metadata attached to a published component",
+		Long: `List the artifacts, such as SBOMs and signatures, attached to a component
+published in an OCI registry.
+
+Example:
+  ftl component list-attachments geo
+  ftl component list-attachments ghcr.io/acme:geo@1.0.0 --type sbom`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeComponentArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runComponentListAttachments(cmd.Context(), args[0], artifactType, resolveOutputFormat(format))
+		},
+	}
+
+	cmd.Flags().StringVarP(&artifactType, "type", "t", "", "Only list attachments of this type")
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("type", completeFixed("sbom", "spdx", "cyclonedx", "signature", "schema", "readme", "provenance"))
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
+
+	return cmd
+}
+
+func runComponentAttach(ctx context.Context, component, file, typeName string) error {
+	src, err := resolveRegistryComponent(component)
+	if err != nil {
+		return err
+	}
+
+	artifactType := attachmentType(typeName)
+	if artifactType == "" {
+		if artifactType = inferAttachmentType(file); artifactType == "" {
+			return &usageError{fmt.Errorf("cannot infer the artifact type of %s; use --type", file)}
+		}
+	}
+
+	attachment, err := oci.Attach(ctx, src.Registry, src.Package, src.Version, artifactType, file)
+	if err != nil {
+		return err
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(attachment)
+	}
+	Success("Attached %s to %s as %s", file, componentSourceString(*src), artifactType)
+	Info("Digest: %s", attachment.Digest)
+	return nil
+}
+
+func runComponentListAttachments(ctx context.Context, component, typeName, format string) error {
+	src, err := resolveRegistryComponent(component)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := oci.ListAttachments(ctx, src.Registry, src.Package, src.Version, attachmentType(typeName))
+	if err != nil {
+		return err
+	}
+
+	dw := NewDataWriter(colorOutput, format)
+	switch format {
+	case "json", "yaml":
+		return dw.WriteStruct(attachments)
+	case "table":
+	default:
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
+	}
+
+	if len(attachments) == 0 {
+		_, _ = fmt.Fprintf(colorOutput, "No attachments found for %s.\n", componentSourceString(*src))
+		return nil
+	}
+	tb := NewTableBuilder("TYPE", "TITLE", "SIZE", "DIGEST")
+	for _, a := range attachments {
+		title := a.Title
+		if title == "" {
+			title = "-"
+		}
+		tb.AddRow(a.ArtifactType, title, fmt.Sprintf("%d", a.Size), a.Digest)
+	}
+	return tb.Write(dw)
+}
+
+// resolveRegistryComponent finds the registry source of a component given
+// its ID in the project's manifest or a registry/namespace:package@version
+// reference
+func resolveRegistryComponent(component string) (*manifest.SourceRegistry, error) {
+	if m, err := manifest.LoadAuto(); err == nil {
+		for _, comp := range m.Components {
+			if comp.ID != component {
+				continue
+			}
+			if src, ok := comp.Source.(manifest.SourceRegistry); ok {
+				return &src, nil
+			}
+			return nil, &usageError{fmt.Errorf("component %s is not published to a registry; attach metadata to a registry component", component)}
+		}
+	}
+
+	ref, version, _ := strings.Cut(component, "@")
+	registry, pkg, _ := strings.Cut(ref, "/")
+	if registry == "" || pkg == "" || version == "" {
+		return nil, &usageError{fmt.Errorf("unknown component %q: use a component ID from ftl.yaml or registry/namespace:package@version", component)}
+	}
+	return &manifest.SourceRegistry{Registry: registry, Package: pkg, Version: version}, nil
+}
+
+// attachmentType resolves a --type value to an artifact type. Values that
+// are not short names are taken as media types.
+func attachmentType(name string) string {
+	if t, ok := attachmentTypes[strings.ToLower(name)]; ok {
+		return t
+	}
+	return name
+}
+
+// inferAttachmentType guesses the artifact type of a file from its name
+func inferAttachmentType(file string) string {
+	base := strings.ToLower(filepath.Base(file))
+	switch {
+	case strings.HasSuffix(base, ".spdx.json"):
+		return oci.ArtifactTypeSPDX
+	case strings.HasSuffix(base, ".cdx.json"), base == "bom.json":
+		return oci.ArtifactTypeCycloneDX
+	case strings.HasSuffix(base, ".schema.json"):
+		return oci.ArtifactTypeSchema
+	case strings.HasSuffix(base, ".sigstore.json"), strings.HasSuffix(base, ".sigstore"):
+		return oci.ArtifactTypeSignature
+	case strings.HasPrefix(base, "readme") && strings.HasSuffix(base, ".md"):
+		return oci.ArtifactTypeReadme
+	}
+	return ""
+}
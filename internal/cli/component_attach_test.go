@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestRunComponentAttach(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("geo.wasm", []byte("component"), 0600))
+	require.NoError(t, os.WriteFile("sbom.spdx.json", []byte(`{"spdxVersion":"SPDX-2.3"}`), 0600))
+	require.NoError(t, os.WriteFile("tools.json", []byte(`{"type":"object"}`), 0600))
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(`name: demo
+components:
+  - id: geo
+    source:
+      registry: `+regURL+`
+      package: acme:geo
+      version: 1.0.0
+  - id: local
+    source: ./local
+`), 0600))
+
+	ctx := context.Background()
+	pusher := oci.NewWASMPusher(&oci.ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.Push(ctx, "geo.wasm", "acme/geo", "1.0.0"))
+
+	setGlobalOutput(t, "")
+	require.NoError(t, runComponentAttach(ctx, "geo", "sbom.spdx.json", ""))
+	require.NoError(t, runComponentAttach(ctx, regURL+"/acme:geo@1.0.0", "tools.json", "schema"))
+
+	var usage *usageError
+	assert.ErrorAs(t, runComponentAttach(ctx, "geo", "tools.json", ""), &usage)
+	assert.ErrorAs(t, runComponentAttach(ctx, "local", "sbom.spdx.json", ""), &usage)
+	assert.ErrorAs(t, runComponentAttach(ctx, "missing", "sbom.spdx.json", ""), &usage)
+
+	buf := setGlobalOutput(t, "json")
+	require.NoError(t, runComponentListAttachments(ctx, "geo", "", "json"))
+	var attachments []oci.Attachment
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &attachments))
+	require.Len(t, attachments, 2)
+
+	buf.Reset()
+	require.NoError(t, runComponentListAttachments(ctx, "geo", "sbom", "json"))
+	attachments = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &attachments))
+	require.Len(t, attachments, 1)
+	assert.Equal(t, oci.ArtifactTypeSPDX, attachments[0].ArtifactType)
+	assert.Equal(t, "sbom.spdx.json", attachments[0].Title)
+}
+
+func TestResolveRegistryComponent(t *testing.T) {
+	chdirTemp(t)
+
+	src, err := resolveRegistryComponent("ghcr.io/acme:geo@1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, &manifest.SourceRegistry{Registry: "ghcr.io", Package: "acme:geo", Version: "1.0.0"}, src)
+
+	_, err = resolveRegistryComponent("ghcr.io/acme:geo")
+	assert.ErrorContains(t, err, "registry/namespace:package@version")
+}
+
+func TestInferAttachmentType(t *testing.T) {
+	tests := map[string]string{
+		"dist/sbom.spdx.json":  oci.ArtifactTypeSPDX,
+		"bom.json":             oci.ArtifactTypeCycloneDX,
+		"geo.cdx.json":         oci.ArtifactTypeCycloneDX,
+		"tools.schema.json":    oci.ArtifactTypeSchema,
+		"geo.wasm.sigstore":    oci.ArtifactTypeSignature,
+		"README.md":            oci.ArtifactTypeReadme,
+		"notes.txt":            "",
+		"geo.sigstore.json":    oci.ArtifactTypeSignature,
+		"docs/readme-usage.md": oci.ArtifactTypeReadme,
+	}
+	for file, want := range tests {
+		assert.Equal(t, want, inferAttachmentType(file), file)
+	}
+	assert.Equal(t, oci.ArtifactTypeSPDX, attachmentType("SBOM"))
+	assert.Equal(t, "application/vnd.acme.policy+json", attachmentType("application/vnd.acme.policy+json"))
+}
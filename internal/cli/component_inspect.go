@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/oci"
+)
+
+// componentInspectResult is the machine-readable form of 'ftl component
+// inspect' for a local component
+type componentInspectResult struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	oci.Metadata
+}
+
+func newComponentInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <component>",
+		Short: "Show a component's description, documentation, license and tools",
+		Long: `Show the metadata describing a component.
+
+Components are described by a component.yaml next to their source:
+
+  description: Geocoding tools backed by OpenStreetMap
+  documentation: https://github.com/acme/geo#readme
+  source: https://github.com/acme/geo
+  license: Apache-2.0
+  authors: Acme Inc.
+  tools: [geocode, reverse_geocode]
+
+'ftl deploy' pushes the metadata with the component as OCI annotations, so
+components pulled from a registry describe themselves. The component is the
+ID of a component in ftl.yaml or a registry/namespace:package@version
+reference.
+
+Example:
+  ftl component inspect geo
+  ftl component inspect ghcr.io/acme:geo@1.0.0`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeComponentArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runComponentInspect(cmd.Context(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runComponentInspect(ctx context.Context, component string) error {
+	if m, err := manifest.LoadAuto(); err == nil {
+		for _, comp := range m.Components {
+			if path, ok := comp.Source.(string); ok && comp.ID == component {
+				return inspectLocalComponent(comp.ID, path)
+			}
+		}
+	}
+
+	src, err := resolveRegistryComponent(component)
+	if err != nil {
+		return err
+	}
+	info, err := oci.InspectComponent(ctx, src.Registry, src.Package, src.Version)
+	if err != nil {
+		return err
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(info)
+	}
+	rows := [][2]string{
+		{"Component", info.Reference()},
+		{"Digest", info.Digest},
+		{"Size", formatSize(info.Size)},
+		{"Targets", strings.Join(info.Targets, ", ")},
+	}
+	printComponentMetadata(rows, info.Metadata)
+	return nil
+}
+
+func inspectLocalComponent(id, path string) error {
+	meta, err := oci.LoadMetadata(componentDir(path))
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		meta = &oci.Metadata{}
+		if structuredFormat() == "" {
+			Info("%s has no %s describing it", id, oci.MetadataFile)
+		}
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(componentInspectResult{ID: id, Source: path, Metadata: *meta})
+	}
+	printComponentMetadata([][2]string{{"Component", id}, {"Source", path}, {"Version", meta.Version}}, *meta)
+	return nil
+}
+
+// printComponentMetadata prints a component's details followed by its
+// metadata, skipping empty fields
+func printComponentMetadata(rows [][2]string, meta oci.Metadata) {
+	rows = append(rows,
+		[2]string{"Description", meta.Description},
+		[2]string{"Documentation", meta.Documentation},
+		[2]string{"Source code", meta.Source},
+		[2]string{"License", meta.License},
+		[2]string{"Authors", meta.Authors},
+		[2]string{"Tools", strings.Join(meta.Tools, ", ")},
+	)
+
+	w := tabwriter.NewWriter(colorOutput, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		if row[1] != "" {
+			_, _ = fmt.Fprintf(w, "%s:\t%s\n", row[0], row[1])
+		}
+	}
+	_ = w.Flush()
+}
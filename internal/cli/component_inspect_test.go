@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestRunComponentInspect(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	chdirTemp(t)
+	require.NoError(t, os.MkdirAll("geo", 0750))
+	require.NoError(t, os.WriteFile("geo/geo.wasm", []byte("component"), 0600))
+	require.NoError(t, os.WriteFile("geo/component.yaml", []byte(`description: Geocoding tools
+license: Apache-2.0
+tools: [geocode, reverse_geocode]
+`), 0600))
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: demo\ncomponents:\n  - id: geo\n    source: ./geo\n"), 0600))
+
+	buf := setGlobalOutput(t, "")
+	ctx := context.Background()
+	require.NoError(t, runComponentInspect(ctx, "geo"))
+	assert.Contains(t, buf.String(), "Description:")
+	assert.Contains(t, buf.String(), "Geocoding tools")
+	assert.Contains(t, buf.String(), "geocode, reverse_geocode")
+
+	// Pushed metadata is read back from the registry
+	meta, err := oci.LoadMetadata("geo")
+	require.NoError(t, err)
+	pusher := oci.NewWASMPusher(&oci.ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.PushWithMetadata(ctx, "geo/geo.wasm", "acme/geo", "1.0.0", meta))
+
+	buf = setGlobalOutput(t, "json")
+	require.NoError(t, runComponentInspect(ctx, regURL+"/acme:geo@1.0.0"))
+	var info oci.ComponentInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &info))
+	assert.Equal(t, "1.0.0", info.Version)
+	assert.Equal(t, "Geocoding tools", info.Description)
+	assert.Equal(t, "Apache-2.0", info.License)
+	assert.Equal(t, []string{"geocode", "reverse_geocode"}, info.Tools)
+}
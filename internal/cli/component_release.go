@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/oci"
+)
+
+// Version parts 'ftl component release' can bump
+const (
+	BumpPatch = "patch"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+// changelogFile is the file in a component's directory that records its
+// releases, newest first
+const changelogFile = "CHANGELOG.md"
+
+// ComponentReleaseOptions holds options for the component release command
+type ComponentReleaseOptions struct {
+	ID      string
+	Bump    string
+	Message string
+	NoGit   bool
+}
+
+func newComponentReleaseCmd() *cobra.Command {
+	opts := &ComponentReleaseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "release <component>",
+		Short: "Bump a component's version and record it in its changelog",
+		Long: `Release a new version of a local component.
+
+The version is kept in the component.yaml next to the component's source and
+is the version 'ftl deploy' pushes the component as. Releasing bumps it,
+adds an entry to the CHANGELOG.md next to the source and, in a git
+repository, commits both files and tags the commit <component>/v<version>.
+
+The changelog entry lists the subjects of the commits that changed the
+component since its previous release, unless --message is given. The first
+release of a component without a version is 0.1.0.
+
+Example:
+  ftl component release geo
+  ftl component release geo --bump minor -m "Add reverse geocoding"`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeComponentArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ID = args[0]
+			return runComponentRelease(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Bump, "bump", BumpPatch, "Version part to bump (patch, minor, major)")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Changelog entry instead of the commit subjects")
+	cmd.Flags().BoolVar(&opts.NoGit, "no-git", false, "Do not commit or tag the release")
+	_ = cmd.RegisterFlagCompletionFunc("bump", completeFixed(BumpPatch, BumpMinor, BumpMajor))
+
+	return cmd
+}
+
+func runComponentRelease(opts *ComponentReleaseOptions) error {
+	switch opts.Bump {
+	case BumpPatch, BumpMinor, BumpMajor:
+	default:
+		return &usageError{fmt.Errorf("invalid bump '%s': must be one of patch, minor, major", opts.Bump)}
+	}
+
+	m, err := manifest.LoadAuto()
+	if err != nil {
+		return fmt.Errorf("failed to load manifest: %w", err)
+	}
+	if err := m.ResolveBuilds(); err != nil {
+		return err
+	}
+	comp, _ := m.FindComponent(opts.ID)
+	if comp == nil {
+		return fmt.Errorf("component '%s' not found", opts.ID)
+	}
+	path, ok := comp.Source.(string)
+	if !ok {
+		return fmt.Errorf("component '%s' comes from a registry: release it from its own repository", opts.ID)
+	}
+	dir := componentDir(path)
+
+	meta, err := oci.LoadMetadata(dir)
+	if err != nil {
+		return err
+	}
+	current := ""
+	if meta != nil {
+		current = meta.Version
+	}
+	version, err := bumpVersion(current, opts.Bump)
+	if err != nil {
+		return fmt.Errorf("component '%s': %w", opts.ID, err)
+	}
+
+	useGit := !opts.NoGit && inGitRepository()
+	entries := changelogEntries(opts.Message)
+	if len(entries) == 0 && useGit {
+		entries = releaseCommits(componentTag(opts.ID, current), dir)
+	}
+
+	if err := oci.SaveMetadataVersion(dir, version); err != nil {
+		return err
+	}
+	changelog := filepath.Join(dir, changelogFile)
+	if err := prependChangelog(changelog, version, time.Now(), entries); err != nil {
+		return err
+	}
+	Success("Released %s %s", opts.ID, version)
+
+	if !useGit {
+		return nil
+	}
+	tag := componentTag(opts.ID, version)
+	files := []string{filepath.Join(dir, oci.MetadataFile), changelog}
+	if err := runGit(append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+	if err := runGit(append([]string{"commit", "-m", fmt.Sprintf("Release %s %s", opts.ID, version), "--"}, files...)...); err != nil {
+		return err
+	}
+	if err := runGit("tag", "-a", tag, "-m", fmt.Sprintf("%s %s", opts.ID, version)); err != nil {
+		return err
+	}
+	Success("Tagged %s", tag)
+	Info("Push the tag with 'git push origin %s'", tag)
+	return nil
+}
+
+// bumpVersion returns the version after bumping part of current. A
+// component without a version is released as 0.1.0.
+func bumpVersion(current, part string) (string, error) {
+	if current == "" {
+		return "0.1.0", nil
+	}
+	v := semver.Canonical("v" + strings.TrimPrefix(current, "v"))
+	if v == "" || semver.Prerelease(v) != "" || semver.Build(v) != "" {
+		return "", fmt.Errorf("version '%s' is not a MAJOR.MINOR.PATCH version", current)
+	}
+
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(v, "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return "", fmt.Errorf("version '%s' is not a MAJOR.MINOR.PATCH version", current)
+	}
+	switch part {
+	case BumpMajor:
+		major, minor, patch = major+1, 0, 0
+	case BumpMinor:
+		minor, patch = minor+1, 0
+	default:
+		patch++
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, patch), nil
+}
+
+// componentTag returns the git tag of a component release, or "" for a
+// component that has not been released
+func componentTag(id, version string) string {
+	if version == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/v%s", id, version)
+}
+
+// changelogEntries splits a --message into changelog entries, one per line
+func changelogEntries(message string) []string {
+	var entries []string
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return entries
+}
+
+// releaseCommits returns the subjects of the commits that changed dir
+// since the tag of the previous release, or since the start of history
+// when there is none
+func releaseCommits(previousTag, dir string) []string {
+	args := []string{"log", "--format=%s"}
+	if previousTag != "" && runGit("rev-parse", "--verify", "--quiet", previousTag) == nil {
+		args = append(args, previousTag+"..HEAD")
+	}
+	args = append(args, "--", dir)
+
+	out, err := ExecCommand("git", args...).Output()
+	if err != nil {
+		return nil
+	}
+	return changelogEntries(string(out))
+}
+
+// prependChangelog adds a release to the top of a changelog, below its
+// title, creating the file if needed
+func prependChangelog(path, version string, date time.Time, entries []string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var entry bytes.Buffer
+	fmt.Fprintf(&entry, "## %s - %s\n\n", version, date.Format("2006-01-02"))
+	if len(entries) == 0 {
+		entries = []string{"No changes recorded"}
+	}
+	for _, e := range entries {
+		fmt.Fprintf(&entry, "- %s\n", e)
+	}
+	entry.WriteString("\n")
+
+	existing := string(data)
+	if existing == "" {
+		existing = "# Changelog\n\n"
+	}
+	title, rest := "", existing
+	if strings.HasPrefix(existing, "# ") {
+		line, after, _ := strings.Cut(existing, "\n")
+		title = line + "\n\n"
+		rest = strings.TrimLeft(after, "\n")
+	}
+
+	out := title + entry.String() + rest
+	if err := os.WriteFile(path, []byte(out), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// inGitRepository reports whether the working directory is in a git
+// repository
+func inGitRepository() bool {
+	return runGit("rev-parse", "--is-inside-work-tree") == nil
+}
+
+// runGit runs a git command, returning its output in the error on failure
+func runGit(args ...string) error {
+	out, err := ExecCommand("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("'git %s' failed: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		current, part, want string
+	}{
+		{"", BumpMajor, "0.1.0"},
+		{"1.2.3", BumpPatch, "1.2.4"},
+		{"1.2.3", BumpMinor, "1.3.0"},
+		{"v1.2.3", BumpMajor, "2.0.0"},
+	}
+	for _, tt := range tests {
+		got, err := bumpVersion(tt.current, tt.part)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s %s", tt.current, tt.part)
+	}
+
+	for _, invalid := range []string{"latest", "1.2.3-rc.1"} {
+		_, err := bumpVersion(invalid, BumpPatch)
+		assert.ErrorContains(t, err, "not a MAJOR.MINOR.PATCH version")
+	}
+}
+
+func TestPrependChangelog(t *testing.T) {
+	path := t.TempDir() + "/CHANGELOG.md"
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, prependChangelog(path, "0.1.0", date, nil))
+	require.NoError(t, prependChangelog(path, "0.2.0", date.AddDate(0, 0, 1), []string{"Add reverse geocoding", "Fix rounding"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Changelog
+
+## 0.2.0 - 2025-03-02
+
+- Add reverse geocoding
+- Fix rounding
+
+## 0.1.0 - 2025-03-01
+
+- No changes recorded
+
+`, string(data))
+}
+
+func TestRunComponentRelease(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	chdirTemp(t)
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	require.NoError(t, os.MkdirAll("geo", 0750))
+	require.NoError(t, os.WriteFile("geo/component.yaml", []byte("description: Geocoding tools\nversion: 1.4.2\n"), 0600))
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: demo\ncomponents:\n  - id: geo\n    source: ./geo\n  - id: remote\n    source:\n      registry: ghcr.io\n      package: acme:remote\n      version: 1.0.0\n"), 0600))
+	git := func(args ...string) string {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add geocoding component")
+
+	setGlobalOutput(t, "")
+	require.NoError(t, runComponentRelease(&ComponentReleaseOptions{ID: "geo", Bump: BumpMinor}))
+
+	meta, err := oci.LoadMetadata("geo")
+	require.NoError(t, err)
+	assert.Equal(t, "1.5.0", meta.Version)
+	assert.Equal(t, "Geocoding tools", meta.Description)
+
+	changelog, err := os.ReadFile("geo/CHANGELOG.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(changelog), "## 1.5.0 - ")
+	assert.Contains(t, string(changelog), "- Add geocoding component")
+
+	assert.Equal(t, "geo/v1.5.0", git("tag", "--points-at", "HEAD"))
+	assert.Equal(t, "Release geo 1.5.0", git("log", "-1", "--format=%s"))
+
+	// Only commits since the previous release are listed
+	require.NoError(t, os.WriteFile("geo/geo.wasm", []byte("component"), 0600))
+	git("add", ".")
+	git("commit", "-q", "-m", "Build geocoding component")
+	require.NoError(t, runComponentRelease(&ComponentReleaseOptions{ID: "geo", Bump: BumpPatch}))
+	changelog, err = os.ReadFile("geo/CHANGELOG.md")
+	require.NoError(t, err)
+	latest, _, _ := strings.Cut(strings.SplitN(string(changelog), "## 1.5.1", 2)[1], "## 1.5.0")
+	assert.Contains(t, latest, "- Build geocoding component")
+	assert.NotContains(t, latest, "Add geocoding component")
+
+	err = runComponentRelease(&ComponentReleaseOptions{ID: "remote", Bump: BumpPatch})
+	assert.ErrorContains(t, err, "comes from a registry")
+
+	var usage *usageError
+	assert.ErrorAs(t, runComponentRelease(&ComponentReleaseOptions{ID: "geo", Bump: "huge"}), &usage)
+}
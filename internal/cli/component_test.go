@@ -18,7 +18,7 @@ func TestComponentCommands(t *testing.T) {
 	assert.Equal(t, "component", cmd.Use)
 
 	// Verify subcommands
-	subcommands := []string{"add", "list", "remove"}
+	subcommands := []string{"add", "list", "remove", "inspect", "attach", "list-attachments"}
 	for _, name := range subcommands {
 		found := false
 		for _, sub := range cmd.Commands() {
@@ -87,10 +87,18 @@ func TestAddComponentValidation(t *testing.T) {
 			name: "valid registry source",
 			opts: &AddComponentOptions{
 				Name:     "my-component",
-				Registry: "ghcr.io/user/package:1.0.0",
+				Registry: "ghcr.io/user:package@1.0.0",
 			},
 			wantErr: false,
 		},
+		{
+			name: "registry source without version",
+			opts: &AddComponentOptions{
+				Name:     "my-component",
+				Registry: "ghcr.io/user:package",
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid template",
 			opts: &AddComponentOptions{
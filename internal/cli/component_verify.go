@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/deploy"
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// verifyResult is the machine-readable result of 'ftl component verify'
+type verifyResult struct {
+	Component  string             `json:"component"`
+	Verified   bool               `json:"verified"`
+	Provenance *oci.Provenance    `json:"provenance,omitempty"`
+	Violations []deploy.Violation `json:"violations,omitempty"`
+}
+
+func newComponentVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <component>",
+		Short: "Check a published component's provenance against the deploy policies",
+		Long: `Verify how a component published in an OCI registry was built.
+
+Reads the provenance attestation attached to the component, which records the
+builder, the git commit it was built from, the build command and a digest of
+its inputs, and checks it against the require_provenance, trusted_builders
+and allowed_sources rules of the project's .ftl/policy.yaml and the
+organization's policy.yaml in the FTL config directory. 'ftl deploy' applies
+the same checks before deploying registry components.
+
+Verification fails when the component has no provenance or breaks a policy.
+The component is either the ID of a registry component in ftl.yaml or a
+reference in the form registry/namespace:package@version.
+
+Example:
+  ftl component verify geo
+  ftl component verify ghcr.io/acme:geo@1.0.0 -o json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeComponentArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runComponentVerify(cmd.Context(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runComponentVerify(ctx context.Context, component string) error {
+	src, err := resolveRegistryComponent(component)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	policies, err := loadDeployPolicies(cwd)
+	if err != nil {
+		return err
+	}
+
+	provenance, err := fetchArtifactProvenance(ctx, &validation.RegistrySource{
+		Registry: src.Registry,
+		Package:  src.Package,
+		Version:  src.Version,
+	})
+	if err != nil {
+		return err
+	}
+
+	result := verifyResult{Component: componentSourceString(*src), Provenance: provenance}
+	if provenance != nil {
+		for _, policy := range policies {
+			result.Violations = append(result.Violations, policy.CheckProvenance(component, provenance)...)
+		}
+	}
+	result.Verified = provenance != nil && len(result.Violations) == 0
+
+	var verifyErr error
+	switch {
+	case provenance == nil:
+		verifyErr = fmt.Errorf("%s has no provenance attestation", result.Component)
+	case !result.Verified:
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s breaks the deploy policy (%d violation(s)):", result.Component, len(result.Violations))
+		for _, v := range result.Violations {
+			fmt.Fprintf(&b, "\n  - %s [%s in %s]", v.Message, v.Rule, v.Source)
+		}
+		verifyErr = errors.New(b.String())
+	}
+
+	if structuredFormat() != "" {
+		if err := writeResult(result); err != nil {
+			return err
+		}
+		if verifyErr != nil {
+			return &reportedError{verifyErr}
+		}
+		return nil
+	}
+
+	if provenance != nil {
+		printProvenance(provenance)
+	}
+	if verifyErr != nil {
+		return verifyErr
+	}
+	if len(policies) == 0 {
+		Warn("No deploy policy applies; only the presence of provenance was checked")
+	}
+	Success("Verified %s", result.Component)
+	return nil
+}
+
+func printProvenance(p *oci.Provenance) {
+	Info("Digest:        %s", p.Digest)
+	Info("Builder:       %s", p.Builder)
+	if p.InvocationID != "" {
+		Info("Build run:     %s", p.InvocationID)
+	}
+	if p.SourceRepo != "" {
+		Info("Source:        %s", p.SourceRepo)
+	}
+	if p.SourceCommit != "" {
+		commit := p.SourceCommit
+		if p.SourceDirty {
+			commit += " (uncommitted changes)"
+		}
+		Info("Commit:        %s", commit)
+	}
+	if p.BuildCommand != "" {
+		Info("Build command: %s", p.BuildCommand)
+	}
+	if p.InputsDigest != "" {
+		Info("Inputs digest: sha256:%s", p.InputsDigest)
+	}
+	Info("Built:         %s", p.FinishedOn.Format("2006-01-02 15:04:05 MST"))
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/deploy"
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestRunComponentVerify(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("geo.wasm", []byte("component"), 0600))
+
+	ctx := context.Background()
+	pusher := oci.NewWASMPusher(&oci.ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.Push(ctx, "geo.wasm", "acme/geo", "1.0.0"))
+	ref := regURL + "/acme:geo@1.0.0"
+
+	setGlobalOutput(t, "")
+	assert.ErrorContains(t, runComponentVerify(ctx, ref), "has no provenance attestation")
+
+	_, err := pusher.AttachProvenance(ctx, "acme/geo", "1.0.0", &oci.Provenance{
+		Builder:      "https://github.com/acme/geo/.github/workflows/release.yml@refs/heads/main",
+		SourceRepo:   "https://github.com/acme/geo",
+		SourceCommit: "8d1f3c2a",
+		BuildCommand: "make build",
+		FinishedOn:   time.Now(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, runComponentVerify(ctx, ref))
+
+	require.NoError(t, os.MkdirAll(".ftl", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(".ftl", "policy.yaml"), []byte(`trusted_builders:
+  - "https://github.com/acme/*/.github/workflows/release.yml@refs/heads/main"
+allowed_sources:
+  - "https://github.com/other/*"
+`), 0600))
+	err = runComponentVerify(ctx, ref)
+	assert.ErrorContains(t, err, "breaks the deploy policy (1 violation(s))")
+	assert.ErrorContains(t, err, deploy.RuleAllowedSource)
+
+	buf := setGlobalOutput(t, "json")
+	var reported *reportedError
+	require.ErrorAs(t, runComponentVerify(ctx, ref), &reported)
+	var result verifyResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.False(t, result.Verified)
+	require.NotNil(t, result.Provenance)
+	assert.Equal(t, "make build", result.Provenance.BuildCommand)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, deploy.RuleAllowedSource, result.Violations[0].Rule)
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/convert"
+)
+
+// ConfigConvertOptions holds options for the config convert command
+type ConfigConvertOptions struct {
+	ConfigFile string
+	To         string
+	Force      bool
+	DryRun     bool
+}
+
+// configConvertResult is the machine-readable form of 'ftl config convert'
+type configConvertResult struct {
+	Source  string         `json:"source"`
+	From    convert.Format `json:"from"`
+	To      convert.Format `json:"to"`
+	File    string         `json:"file"`
+	Written bool           `json:"written"`
+}
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with the project's FTL configuration",
+	}
+
+	cmd.AddCommand(newConfigConvertCmd())
+
+	return cmd
+}
+
+func newConfigConvertCmd() *cobra.Command {
+	opts := &ConfigConvertOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "convert [file]",
+		Short: "Convert the FTL configuration to another format",
+		Long: `Convert the project's FTL configuration between YAML, JSON, CUE and Go.
+
+The configuration is validated and rewritten in the target format with its
+build settings, variables and authentication intact, so a project started
+from ftl.yaml can move to the Go CDK (main.go) and back. The new file is
+written next to the original, which is left in place; remove the original
+once you have checked the result, as ftl.yaml and ftl.json take precedence
+over main.go and app.cue.
+
+Reading Go configuration runs the program with the CDK's JSON export
+enabled.
+
+Example:
+  ftl config convert --to go
+  ftl config convert ftl.yaml --to cue
+  ftl config convert main.go --to yaml --dry-run`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeConfigFileArg,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.ConfigFile = args[0]
+			}
+			return runConfigConvert(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.To, "to", "t", "", "Target format (yaml, json, cue, go)")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Overwrite an existing configuration file")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the converted configuration instead of writing it")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.RegisterFlagCompletionFunc("to", completeFixed("yaml", "json", "cue", "go"))
+
+	return cmd
+}
+
+func runConfigConvert(opts *ConfigConvertOptions) error {
+	to, err := convert.ParseFormat(opts.To)
+	if err != nil {
+		return &usageError{err}
+	}
+
+	source := opts.ConfigFile
+	if source == "" {
+		if source, err = findConfigFile(); err != nil {
+			return err
+		}
+	}
+	source = filepath.Clean(source)
+	from, err := convert.FormatOf(source)
+	if err != nil {
+		return &usageError{err}
+	}
+	if from == to {
+		return &usageError{fmt.Errorf("%s is already %s configuration", source, to)}
+	}
+
+	app, err := convert.Load(source)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", source, err)
+	}
+	data, err := convert.Encode(app, to)
+	if err != nil {
+		return fmt.Errorf("failed to convert configuration: %w", err)
+	}
+
+	dir := filepath.Dir(source)
+	file := filepath.Join(dir, to.DefaultFile())
+	written := false
+	if !opts.DryRun {
+		if _, err := os.Stat(file); err == nil && !opts.Force {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", file)
+		}
+		if err := os.WriteFile(file, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		written = true
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(configConvertResult{
+			Source:  source,
+			From:    from,
+			To:      to,
+			File:    file,
+			Written: written,
+		})
+	}
+
+	if opts.DryRun {
+		_, _ = colorOutput.Write(data)
+		return nil
+	}
+
+	Success("Converted %s to %s", source, file)
+	if to == convert.Go {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			Info("Create a go.mod for %s: go mod init %s && go get github.com/fastertools/ftl", file, app.Name)
+		}
+	}
+	Info("Remove %s once you have checked %s", source, file)
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/convert"
+)
+
+const convertTestConfig = `name: demo
+access: custom
+auth:
+  jwt_issuer: https://auth.example.com
+  jwt_audience: demo-api
+  policy: |
+    package mcp.authorization
+    default allow := true
+components:
+  - id: weather
+    source: weather/weather.wasm
+    build:
+      command: make build
+      workdir: weather
+    variables:
+      LOG_LEVEL: debug
+`
+
+func TestRunConfigConvert(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(convertTestConfig), 0600))
+
+	require.NoError(t, runConfigConvert(&ConfigConvertOptions{To: "json"}))
+
+	app, err := convert.Load("ftl.json")
+	require.NoError(t, err)
+	assert.Equal(t, "demo-api", app.Auth.JWTAudience)
+	assert.Contains(t, app.Auth.Policy, "default allow := true")
+	require.Len(t, app.Components, 1)
+	assert.Equal(t, "weather", app.Components[0].Build.Workdir)
+	assert.Equal(t, "debug", app.Components[0].Variables["LOG_LEVEL"])
+
+	// An existing file is only replaced with --force
+	err = runConfigConvert(&ConfigConvertOptions{ConfigFile: "ftl.yaml", To: "json"})
+	assert.ErrorContains(t, err, "already exists")
+	assert.NoError(t, runConfigConvert(&ConfigConvertOptions{ConfigFile: "ftl.yaml", To: "json", Force: true}))
+}
+
+func TestRunConfigConvert_DryRunJSON(t *testing.T) {
+	chdirTemp(t)
+	buf := setGlobalOutput(t, "json")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(convertTestConfig), 0600))
+
+	require.NoError(t, runConfigConvert(&ConfigConvertOptions{ConfigFile: "ftl.yaml", To: "go", DryRun: true}))
+
+	var result configConvertResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, convert.YAML, result.From)
+	assert.Equal(t, convert.Go, result.To)
+	assert.Equal(t, "main.go", result.File)
+	assert.False(t, result.Written)
+
+	_, err := os.Stat("main.go")
+	assert.True(t, os.IsNotExist(err), "dry run must not write the configuration")
+}
+
+func TestRunConfigConvert_UsageErrors(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(convertTestConfig), 0600))
+
+	var usage *usageError
+	assert.ErrorAs(t, runConfigConvert(&ConfigConvertOptions{To: "toml"}), &usage)
+	assert.ErrorAs(t, runConfigConvert(&ConfigConvertOptions{ConfigFile: "ftl.yaml", To: "yml"}), &usage)
+}
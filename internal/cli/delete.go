@@ -3,7 +3,6 @@ package cli
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
@@ -11,17 +10,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/fastertools/ftl/internal/api"
-	"github.com/fastertools/ftl/internal/auth"
 )
 
 func newDeleteCmd() *cobra.Command {
 	var force bool
 
 	cmd := &cobra.Command{
-		Use:   "delete <app-id|app-name>",
-		Short: "Delete an FTL application",
-		Long:  `Delete an FTL application from the platform.`,
-		Args:  cobra.ExactArgs(1),
+		Use:               "delete <app-id|app-name>",
+		Short:             "Delete an FTL application",
+		Long:              `Delete an FTL application from the platform.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeAppNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			return runDelete(ctx, args[0], force)
@@ -38,11 +37,10 @@ var runDelete = runDeleteImpl
 
 func runDeleteImpl(ctx context.Context, appIdentifier string, force bool) error {
 	// Initialize auth manager
-	store, err := auth.NewKeyringStore()
+	authManager, err := newAuthManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize credential store: %w", err)
+		return err
 	}
-	authManager := auth.NewManager(store, nil)
 
 	// Check authentication
 	if _, err := authManager.GetToken(ctx); err != nil {
@@ -50,9 +48,9 @@ func runDeleteImpl(ctx context.Context, appIdentifier string, force bool) error
 	}
 
 	// Create API client
-	apiClient, err := api.NewFTLClient(authManager, "")
+	apiClient, err := newAPIClient(authManager)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
 	}
 
 	// Get app details first to show what will be deleted
@@ -101,11 +99,6 @@ func runDeleteImpl(ctx context.Context, appIdentifier string, force bool) error
 
 	// Ask for confirmation unless --force is used
 	if !force {
-		// Check if we're in an interactive terminal
-		if !isInteractive() {
-			return fmt.Errorf("deletion requires confirmation. Use --force to skip confirmation in non-interactive mode")
-		}
-
 		_, _ = color.New(color.FgRed, color.Bold).Println("⚠️  This action cannot be undone!")
 
 		// Ask user to type the app name to confirm
@@ -114,7 +107,7 @@ func runDeleteImpl(ctx context.Context, appIdentifier string, force bool) error
 		}
 
 		var confirmation string
-		if err := survey.AskOne(prompt, &confirmation); err != nil {
+		if err := ask(prompt, &confirmation, "Pass --force to delete the app without confirmation"); err != nil {
 			return fmt.Errorf("failed to get confirmation: %w", err)
 		}
 
@@ -136,13 +129,3 @@ func runDeleteImpl(ctx context.Context, appIdentifier string, force bool) error
 
 	return nil
 }
-
-// isInteractive checks if we're running in an interactive terminal
-func isInteractive() bool {
-	fileInfo, err := os.Stdin.Stat()
-	if err != nil {
-		return false
-	}
-	// Check if stdin is a terminal (not a pipe or file)
-	return fileInfo.Mode()&os.ModeCharDevice != 0
-}
@@ -3,9 +3,11 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,12 +15,15 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/fastertools/ftl/internal/api"
 	"github.com/fastertools/ftl/internal/auth"
 	"github.com/fastertools/ftl/internal/config"
 	"github.com/fastertools/ftl/internal/deploy"
+	"github.com/fastertools/ftl/internal/tracing"
 	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/platform"
 	"github.com/fastertools/ftl/validation"
 )
 
@@ -34,6 +39,32 @@ type DeployOptions struct {
 	AllowedRoles  []string
 	Variables     map[string]string
 	OrgID         string // Explicitly specify organization ID
+	Timeout       time.Duration
+	NoWait        bool
+
+	// AllowSensitiveChanges lets --yes deploy changes to the access mode
+	// and auth settings without confirmation
+	AllowSensitiveChanges bool
+
+	// Regions overrides the regions in the configuration
+	Regions []string
+
+	// Prebuilt deploys already-built artifacts, such as an extracted
+	// bundle, skipping synthesis and 'spin build'
+	Prebuilt bool
+
+	// Target selects where to deploy: the FTL platform, or spin directly
+	Target string
+
+	// Registry is pushed to before 'spin up' with --target spin
+	Registry string
+
+	// All deploys every app found in subdirectories, limited to Only
+	All  bool
+	Only []string
+
+	// loggedIn skips authentication when deploying several apps at once
+	loggedIn bool
 }
 
 func newDeployCmd() *cobra.Command {
@@ -58,128 +89,213 @@ Example:
   ftl deploy
   ftl deploy --access-control private
   ftl deploy --jwt-issuer https://auth.example.com --jwt-audience api.example.com
-  ftl deploy --dry-run`,
+  ftl deploy --dry-run
+  ftl deploy --timeout 10m
+  ftl deploy --no-wait
+  ftl deploy --region us-east-1 --region eu-west-1
+  ftl deploy --target spin --registry ghcr.io/acme --var region=eu
+  ftl deploy --target fermyon-cloud
+
+Apps with regions in their configuration, or deployed with --region, are
+deployed to each region in turn. A failure in one region does not stop the
+others; the command reports the status of every region and fails if any
+region did.
+
+Targets:
+  ftl            Deploy through the FTL platform (default)
+  spin           Run the app with 'spin up', after pushing it to --registry if given
+  fermyon-cloud  Deploy the app with 'spin deploy'
+
+The spin and fermyon-cloud targets skip the FTL platform entirely: no login
+is needed, and --var values are passed to spin as --variable arguments.
+Features of the platform, such as regions, are not available.
+
+Policies in .ftl/policy.yaml and in policy.yaml in the FTL config directory
+are checked before anything is built or pushed:
+
+  require_registry_components: true     # no locally built components
+  allowed_registries: [ghcr.io]         # wildcards such as *.example.com allowed
+  forbid_latest: true                   # registry versions must be pinned
+  require_signature_tag: true           # registry artifacts need a cosign signature tag (not verified)
+  require_provenance: true              # registry artifacts need a provenance attestation
+  trusted_builders:                     # CI workflows that may have built them
+    - https://github.com/acme/*/.github/workflows/release.yml@refs/heads/main
+  allowed_sources:                      # git repositories they may be built from
+    - https://github.com/acme/*
+
+require_signature_tag only checks that a cosign signature tag exists; the
+signature is not verified, so it offers no protection against anyone who
+can push to the repository.
+
+Locally built components are pushed with a provenance attestation recording
+the builder, the git commit, the build command and a digest of the build
+inputs. Check a published component's provenance with 'ftl component verify'.
+
+Monorepos:
+  ftl deploy --all                       Deploy every app found in subdirectories
+  ftl deploy --all --only api --only web Deploy some of them
+
+With --all, apps are found by their ftl.yaml, ftl.json or app.cue and
+deployed one after another, logging in once. Dependencies between them are
+declared in .ftl/workspace.yaml, so that apps are deployed after those they
+depend on; an app whose dependency failed is skipped:
+
+  apps:
+    api:
+      depends_on: [auth]`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-			return runDeploy(ctx, opts)
+			if opts.All {
+				return runDeployAll(cmd.Context(), opts)
+			}
+			if len(opts.Only) > 0 {
+				return &usageError{fmt.Errorf("--only requires --all")}
+			}
+			return runDeploy(cmd.Context(), opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.Environment, "environment", "e", "production", "Deployment environment")
 	cmd.Flags().StringVarP(&opts.ConfigFile, "file", "f", "", "FTL configuration file (auto-detects if not specified)")
+	_ = cmd.RegisterFlagCompletionFunc("file", completeConfigFiles)
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Deploy every FTL app in this directory and its subdirectories")
+	cmd.Flags().StringSliceVar(&opts.Only, "only", nil, "With --all, deploy only these apps, by name or directory (can be used multiple times)")
+	addDeployFlags(cmd, opts)
+	addDeployTargetFlags(cmd, opts)
+
+	return cmd
+}
+
+// addDeployFlags registers the flags shared by 'ftl deploy' and 'ftl bundle deploy'
+func addDeployFlags(cmd *cobra.Command, opts *DeployOptions) {
+	cmd.Flags().StringVarP(&opts.Environment, "environment", "e", "production", "Deployment environment")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Validate configuration without deploying")
 	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.AllowSensitiveChanges, "allow-sensitive-changes", false, "With --yes, also skip confirmation of changes to access and auth settings")
 	cmd.Flags().StringVar(&opts.AccessControl, "access-control", "", "Access control mode (public, private, org, custom)")
 	cmd.Flags().StringVar(&opts.JWTIssuer, "jwt-issuer", "", "JWT issuer URL for authentication")
 	cmd.Flags().StringVar(&opts.JWTAudience, "jwt-audience", "", "JWT audience for authentication")
 	cmd.Flags().StringSliceVar(&opts.AllowedRoles, "allowed-roles", nil, "Allowed roles for org mode")
 	cmd.Flags().StringToStringVar(&opts.Variables, "var", nil, "Set variable (can be used multiple times)")
 	cmd.Flags().StringVar(&opts.OrgID, "org", "", "Organization ID for deployment (uses interactive selection if not specified)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 5*time.Minute, "Maximum time to wait for the deployment to complete")
+	cmd.Flags().BoolVar(&opts.NoWait, "no-wait", false, "Return as soon as the deployment is accepted, printing its ID")
+	cmd.Flags().StringSliceVar(&opts.Regions, "region", nil, "Deploy to these regions instead of those in the configuration (can be used multiple times)")
 
-	return cmd
+	_ = cmd.RegisterFlagCompletionFunc("access-control", completeFixed("public", "private", "org", "custom"))
 }
 
 func runDeploy(ctx context.Context, opts *DeployOptions) error {
+	if err := validateTarget(opts); err != nil {
+		return err
+	}
+	if structuredFormat() != "" && !opts.Yes && !opts.DryRun {
+		return &usageError{fmt.Errorf("--yes is required when using --output %s", structuredFormat())}
+	}
+
 	// Auto-detect config file if not specified
 	if opts.ConfigFile == "" {
-		for _, file := range []string{"ftl.yaml", "ftl.yml", "ftl.json", "app.cue"} {
-			if _, err := os.Stat(file); err == nil {
-				opts.ConfigFile = file
-				break
-			}
-		}
-		if opts.ConfigFile == "" {
-			return fmt.Errorf("no FTL configuration file found (ftl.yaml, ftl.json, or app.cue)")
+		config, err := detectDeployConfig()
+		if err != nil {
+			return err
 		}
+		opts.ConfigFile = config
 	}
 
 	// First synthesize spin.toml from the FTL configuration
-	Info("Synthesizing Spin manifest from %s", opts.ConfigFile)
-	if err := runSynth(ctx, opts.ConfigFile); err != nil {
-		return fmt.Errorf("failed to synthesize spin.toml: %w", err)
+	if !opts.Prebuilt {
+		Info("Synthesizing Spin manifest from %s", opts.ConfigFile)
+		synthCtx, span := tracing.Start(ctx, "deploy.synth", attribute.String("ftl.config", opts.ConfigFile))
+		err := runSynth(synthCtx, opts.ConfigFile)
+		tracing.End(span, err)
+		if err != nil {
+			return fmt.Errorf("failed to synthesize spin.toml: %w", err)
+		}
+		Success("Generated spin.toml")
 	}
-	Success("Generated spin.toml")
 
 	// Load and parse configuration
 	manifest, err := loadDeployManifest(opts.ConfigFile)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if opts.Prebuilt {
+		// Prebuilt artifacts are referenced relative to the configuration
+		resolveLocalSources(manifest, filepath.Dir(opts.ConfigFile))
+	}
 
-	// Apply command-line overrides
-	if opts.AccessControl != "" {
-		manifest.Access = opts.AccessControl
+	applyDeployOverrides(manifest, opts)
+	if err := validation.Check(manifest); err != nil {
+		return &usageError{fmt.Errorf("invalid configuration: %w", err)}
 	}
-	if opts.JWTIssuer != "" {
-		if manifest.Auth == nil {
-			manifest.Auth = &validation.AuthConfig{}
-		}
-		manifest.Auth.JWTIssuer = opts.JWTIssuer
-		if opts.JWTAudience != "" {
-			manifest.Auth.JWTAudience = opts.JWTAudience
-		}
+	if err := validateTargetComponents(manifest, opts); err != nil {
+		return err
+	}
+
+	// Enforce deploy policies before anything is built or pushed
+	if err := enforceDeployPolicies(ctx, manifest, filepath.Dir(opts.ConfigFile)); err != nil {
+		return err
 	}
 
 	// Run spin build to build all local components
-	if !opts.DryRun {
+	if !opts.DryRun && !opts.Prebuilt {
 		Info("Building local components with 'spin build'")
+		buildCtx, span := tracing.Start(ctx, "deploy.build")
 		cmd := ExecCommand("spin", "build")
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = messageOutput()
 		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
+		if env := tracing.Environ(buildCtx); len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		err := cmd.Run()
+		tracing.End(span, err)
+		if err != nil {
 			return fmt.Errorf("failed to build components: %w", err)
 		}
 		Success("All local components built successfully")
-		fmt.Println()
+		_, _ = fmt.Fprintln(messageOutput())
 	}
 
 	// Dry-run mode: validate configuration without authentication
 	if opts.DryRun {
+		if structuredFormat() != "" {
+			result := newDeployResult(manifest, opts, "dry_run")
+			return writeResult(result)
+		}
+		if isSpinTarget(opts) {
+			displaySpinTargetDryRun(manifest, opts)
+			return nil
+		}
 		displayDryRunSummary(manifest, false)
 		return nil
 	}
 
+	// Other targets use spin directly, without the FTL platform
+	if isSpinTarget(opts) {
+		return runSpinTarget(ctx, manifest, opts)
+	}
+
 	// Initialize auth manager
-	store, err := auth.NewKeyringStore()
+	authManager, err := newAuthManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize credential store: %w", err)
+		return err
 	}
-	authManager := auth.NewManager(store, nil)
 
-	// Auto-detect and perform M2M authentication if credentials are available
-	if auth.IsM2MConfigured() {
-		Info("M2M credentials detected in environment, authenticating as machine...")
-		if err := authManager.LoginMachine(ctx); err != nil {
-			return fmt.Errorf("failed to authenticate with M2M credentials: %w", err)
-		}
-		Success("Authenticated as machine")
-	}
-
-	// Check authentication
-	if _, err := authManager.GetToken(ctx); err != nil {
-		// Check if we have a pre-generated M2M token
-		if token := auth.GetM2MTokenFromEnv(); token != "" {
-			Info("Using M2M token from environment...")
-			if err := authManager.LoginMachineWithToken(ctx, token); err != nil {
-				return fmt.Errorf("failed to authenticate with M2M token: %w", err)
-			}
-			Success("Authenticated with M2M token")
-		} else {
-			return fmt.Errorf("not logged in to FTL. Run 'ftl auth login' first")
+	if !opts.loggedIn {
+		if err := loginForDeploy(ctx, authManager); err != nil {
+			return err
 		}
 	}
 
 	// Create API client
-	apiClient, err := api.NewFTLClient(authManager, "")
+	apiClient, err := newAPIClient(authManager)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
 	}
 
 	// Check if app exists
 	appName := manifest.Name
 
 	// Add spinner for potentially slow API call (cold starts)
-	sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(messageOutput()))
 	sp.Suffix = " Checking for existing app..."
 	sp.Start()
 
@@ -194,11 +310,28 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 
 	var appID string
 	var existingAccess string
+	var deployed *DeployedConfig
 	appExists := len(apps.Apps) > 0
 	if appExists {
-		appID = apps.Apps[0].AppId.String()
-		if apps.Apps[0].AccessControl != nil {
-			existingAccess = string(*apps.Apps[0].AccessControl)
+		existing := apps.Apps[0]
+		appID = existing.AppId.String()
+		if existing.AccessControl != nil {
+			existingAccess = string(*existing.AccessControl)
+		}
+
+		// Compare components and auth with the deployed app, so production
+		// settings are not changed by accident
+		deployed, err = fetchDeployedConfig(ctx, apiClient, appID)
+		if err != nil {
+			Warn("Could not compare with the deployed app: %v", err)
+		} else {
+			if existing.CustomAuth != nil {
+				deployed.JWTIssuer = existing.CustomAuth.Issuer
+				deployed.JWTAudience = existing.CustomAuth.Audience
+			}
+			if existing.AllowedRoles != nil {
+				deployed.AllowedRoles = *existing.AllowedRoles
+			}
 		}
 	}
 
@@ -211,7 +344,7 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 		// Or get temporary creds to see available orgs
 		if !appExists {
 			// For new apps, we need to create it first to get deployment context
-			sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+			sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(messageOutput()))
 			sp.Suffix = " Preparing org-scoped deployment..."
 			sp.Start()
 
@@ -232,7 +365,7 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 		}
 
 		// Get deployment credentials to see available orgs
-		sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(messageOutput()))
 		sp.Suffix = " Checking organization access..."
 		sp.Start()
 
@@ -293,9 +426,12 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 
 	// NOW build deployment preview with complete information
 	preview := BuildDeploymentPreviewWithOrg(manifest, opts, appID, existingAccess, selectedOrgID, selectedOrgName)
+	if deployed != nil && preview.Changes != nil {
+		diffDeployedConfig(preview.Changes, manifest, opts, deployed)
+	}
 
 	// Show preview and get confirmation
-	confirmed, err := ConfirmDeployment(preview, opts.Yes)
+	confirmed, err := ConfirmDeployment(preview, opts.Yes, opts.AllowSensitiveChanges)
 	if err != nil {
 		return fmt.Errorf("confirmation failed: %w", err)
 	}
@@ -359,12 +495,14 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 	namespace := creds.Registry.PackageNamespace
 
 	Info("Processing components...")
-	processedManifest, err := processComponents(ctx, manifest, ecrAuth, namespace)
+	pushCtx, pushSpan := tracing.Start(ctx, "deploy.push", attribute.Int("ftl.components", len(manifest.Components)))
+	processedManifest, err := processComponents(pushCtx, manifest, ecrAuth, namespace)
+	tracing.End(pushSpan, err)
 	if err != nil {
 		return fmt.Errorf("failed to process components: %w", err)
 	}
 	Success("All components processed and pushed to FTL Engine Registry")
-	fmt.Println()
+	_, _ = fmt.Fprintln(messageOutput())
 
 	// Create deployment request with the processed manifest
 	Info("Deploying application...")
@@ -379,39 +517,131 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 	// Create streaming deployer
 	deployer := deploy.NewStreamingDeployer()
 
-	// Deploy with streaming progress
-	sp = spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-	sp.Suffix = " Starting deployment..."
-	sp.Start()
-
-	var deploymentURL string
+	var deploymentID string
 
 	// Prepare deployment options with org context
 	deployOpts := deploy.DeployOptions{
 		Environment: opts.Environment,
 		OrgID:       selectedOrgID,
+		Timeout:     opts.Timeout,
+		NoWait:      opts.NoWait,
 	}
 
-	err = deployer.Deploy(ctx, deploymentJSON, creds, deployOpts, func(event deploy.StreamEvent) {
-		switch event.Type {
-		case "progress":
-			sp.Suffix = fmt.Sprintf(" %s", event.Message)
-		case "complete":
-			deploymentURL = event.URL
-			sp.Stop()
-			Success("Deployment completed successfully!")
+	// runDeployment streams a single deployment request with progress output
+	runDeployment := func(ctx context.Context, dopts deploy.DeployOptions) (string, error) {
+		var url string
+
+		// Deploy with streaming progress
+		sp := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(messageOutput()))
+		sp.Suffix = " Starting deployment..."
+		sp.Start()
+
+		err := deployer.Deploy(ctx, deploymentJSON, creds, dopts, func(event deploy.StreamEvent) {
 			if event.DeploymentID != "" {
-				Info("Deployment ID: %s", event.DeploymentID)
+				deploymentID = event.DeploymentID
 			}
-		case "error":
-			sp.Stop()
-			Error("Deployment failed: %s", event.Message)
+			if event.Stage != "" {
+				// Print each completed stage above the spinner
+				sp.Stop()
+				Success("%s", deploy.StageLabel(event.Stage))
+				sp.Start()
+			}
+
+			switch event.Type {
+			case "progress", "stage":
+				sp.Suffix = fmt.Sprintf(" %s", event.Message)
+			case "complete":
+				url = event.URL
+				sp.Stop()
+				Success("Deployment completed successfully!")
+				if event.DeploymentID != "" {
+					Info("Deployment ID: %s", event.DeploymentID)
+				}
+			case "error":
+				sp.Stop()
+				Error("Deployment failed: %s", event.Message)
+			}
+		})
+
+		sp.Stop()
+		if err != nil {
+			if errors.Is(err, deploy.ErrDeployTimeout) && deploymentID != "" {
+				Warn("Deployment %s is still in progress. Run 'ftl status %s' to check on it", deploymentID, manifest.Name)
+			}
+			return "", fmt.Errorf("deployment failed: %w", err)
+		}
+		return url, nil
+	}
+
+	rolloutCtx, rolloutSpan := tracing.Start(ctx, "deploy.rollout",
+		attribute.String("ftl.environment", opts.Environment),
+	)
+	if len(manifest.Regions) > 0 {
+		rollout := deployRegions(manifest.Regions, func(region string) (string, string, error) {
+			deploymentID = ""
+			regionOpts := deployOpts
+			regionOpts.Region = region
+			url, err := runDeployment(rolloutCtx, regionOpts)
+			return url, deploymentID, err
+		})
+		err = rolloutError(rollout)
+		tracing.End(rolloutSpan, err)
+
+		if structuredFormat() != "" {
+			status := "deployed"
+			if state := rollout.State(); state != platform.RolloutSucceeded {
+				status = string(state)
+			}
+			result := newDeployResult(processedManifest, opts, status)
+			result.AppID = appID
+			result.Regions = rollout.Regions()
+			result.SetURL(rolloutURL(rollout))
+			if werr := writeResult(result); werr != nil {
+				return werr
+			}
+			if err != nil {
+				return &reportedError{err}
+			}
+			return nil
 		}
-	})
 
+		if perr := printRollout(rollout); perr != nil {
+			return perr
+		}
+		if err != nil {
+			return err
+		}
+		if url := rolloutURL(rollout); url != "" {
+			displayMCPUrls(url, processedManifest.Components)
+		}
+		return nil
+	}
+
+	deploymentURL, err := runDeployment(rolloutCtx, deployOpts)
+	tracing.End(rolloutSpan, err)
 	if err != nil {
-		sp.Stop()
-		return fmt.Errorf("deployment failed: %w", err)
+		return err
+	}
+
+	if structuredFormat() != "" {
+		status := "deployed"
+		if opts.NoWait && deploymentURL == "" {
+			status = "accepted"
+		}
+		result := newDeployResult(processedManifest, opts, status)
+		result.AppID = appID
+		result.DeploymentID = deploymentID
+		result.SetURL(deploymentURL)
+		return writeResult(result)
+	}
+
+	if opts.NoWait && deploymentURL == "" {
+		Success("Deployment started")
+		if deploymentID != "" {
+			Info("Deployment ID: %s", deploymentID)
+		}
+		Info("Run 'ftl status %s' to check progress", manifest.Name)
+		return nil
 	}
 
 	if deploymentURL != "" {
@@ -422,6 +652,74 @@ func runDeploy(ctx context.Context, opts *DeployOptions) error {
 	return nil
 }
 
+// loginForDeploy authenticates with machine credentials from the
+// environment when there are any, and otherwise checks that the user is
+// logged in
+func loginForDeploy(ctx context.Context, authManager *auth.Manager) error {
+	// Auto-detect and perform M2M authentication if credentials are available
+	if auth.IsM2MConfigured() {
+		Info("M2M credentials detected in environment, authenticating as machine...")
+		if err := authManager.LoginMachine(ctx); err != nil {
+			return fmt.Errorf("failed to authenticate with M2M credentials: %w", err)
+		}
+		Success("Authenticated as machine")
+	} else if auth.IsOIDCConfigured() {
+		Info("CI identity token detected, authenticating as machine...")
+		if err := authManager.LoginOIDC(ctx); err != nil {
+			return fmt.Errorf("failed to authenticate with CI identity token: %w", err)
+		}
+		Success("Authenticated as machine")
+	}
+
+	// Check authentication
+	if _, err := authManager.GetToken(ctx); err != nil {
+		// Check if we have a pre-generated M2M token
+		if token := auth.GetM2MTokenFromEnv(); token != "" {
+			Info("Using M2M token from environment...")
+			if err := authManager.LoginMachineWithToken(ctx, token); err != nil {
+				return fmt.Errorf("failed to authenticate with M2M token: %w", err)
+			}
+			Success("Authenticated with M2M token")
+		} else {
+			return fmt.Errorf("not logged in to FTL. Run 'ftl auth login' first")
+		}
+	}
+	return nil
+}
+
+// detectDeployConfig returns the FTL configuration file in the current
+// directory
+func detectDeployConfig() (string, error) {
+	for _, file := range []string{"ftl.yaml", "ftl.yml", "ftl.json", "app.cue"} {
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("no FTL configuration file found (ftl.yaml, ftl.json, or app.cue)")
+}
+
+// applyDeployOverrides applies the command-line overrides of the
+// configuration
+func applyDeployOverrides(manifest *validation.Application, opts *DeployOptions) {
+	if opts.AccessControl != "" {
+		manifest.Access = opts.AccessControl
+	}
+	if opts.JWTIssuer != "" || opts.JWTAudience != "" {
+		if manifest.Auth == nil {
+			manifest.Auth = &validation.AuthConfig{}
+		}
+		if opts.JWTIssuer != "" {
+			manifest.Auth.JWTIssuer = opts.JWTIssuer
+		}
+		if opts.JWTAudience != "" {
+			manifest.Auth.JWTAudience = opts.JWTAudience
+		}
+	}
+	if len(opts.Regions) > 0 {
+		manifest.Regions = opts.Regions
+	}
+}
+
 // loadDeployManifest loads the FTL manifest configuration for deployment
 func loadDeployManifest(configFile string) (*validation.Application, error) {
 	// Clean the path to prevent directory traversal
@@ -440,11 +738,26 @@ func loadDeployManifest(configFile string) (*validation.Application, error) {
 	return validation.ExtractApplication(validatedValue)
 }
 
+// resolveLocalSources makes relative local component and middleware paths
+// relative to dir
+func resolveLocalSources(manifest *validation.Application, dir string) {
+	for _, components := range [][]*validation.Component{manifest.Components, manifest.Middleware} {
+		for _, comp := range components {
+			if src, ok := comp.Source.(*validation.LocalSource); ok && !filepath.IsAbs(src.Path) {
+				src.Path = filepath.Join(dir, src.Path)
+			}
+		}
+	}
+}
+
 // runSynth runs the synth command to generate spin.toml
 func runSynth(ctx context.Context, configFile string) error {
 	cmd := ExecCommand("ftl", "synth", "-o", "spin.toml", configFile)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = messageOutput()
 	cmd.Stderr = os.Stderr
+	if env := tracing.Environ(ctx); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	return cmd.Run()
 }
 
@@ -470,6 +783,7 @@ func processComponents(ctx context.Context, manifest *validation.Application, ec
 	// Process each component
 	for _, comp := range manifest.Components {
 		var wasmPath string
+		var meta *oci.Metadata
 		var err error
 
 		// Check if it's a local or registry source
@@ -481,10 +795,15 @@ func processComponents(ctx context.Context, manifest *validation.Application, ec
 				return nil, fmt.Errorf("failed to find built WASM for %s: %w", comp.ID, err)
 			}
 			Info("Found local component %s at %s", comp.ID, wasmPath)
+			if meta, err = oci.LoadMetadata(componentDir(src.Path)); err != nil {
+				return nil, fmt.Errorf("failed to load metadata for %s: %w", comp.ID, err)
+			}
 		case *validation.RegistrySource:
 			// Registry component - pull it
 			Info("Pulling component %s from %s", comp.ID, src.Registry)
-			wasmPath, err = puller.Pull(ctx, src.Registry, src.Package, src.Version)
+			pullCtx, span := tracing.Start(ctx, "deploy.pull", attribute.String("ftl.component", comp.ID))
+			wasmPath, err = puller.Pull(pullCtx, src.Registry, src.Package, src.Version)
+			tracing.End(span, err)
 			if err != nil {
 				return nil, fmt.Errorf("failed to pull component %s: %w", comp.ID, err)
 			}
@@ -496,17 +815,25 @@ func processComponents(ctx context.Context, manifest *validation.Application, ec
 		// Push to ECR
 		// Package name should use / not : for the repository path
 		packageName := fmt.Sprintf("%s/%s", namespace, comp.ID)
-		version := manifest.Version
-		if version == "" {
-			version = "0.1.0"
-		}
+		version := pushVersion(manifest, meta)
 
 		Info("Pushing %s to FTL Engine Registry", comp.ID)
-		if err := pusher.Push(ctx, wasmPath, packageName, version); err != nil {
+		pushCtx, span := tracing.Start(ctx, "deploy.push.component", attribute.String("ftl.component", comp.ID))
+		err = pusher.PushWithMetadata(pushCtx, wasmPath, packageName, version, meta)
+		tracing.End(span, err)
+		if err != nil {
 			return nil, fmt.Errorf("failed to push component %s: %w", comp.ID, err)
 		}
 		Success("Pushed %s", comp.ID)
 
+		if src, ok := comp.Source.(*validation.LocalSource); ok {
+			if _, err := pusher.AttachProvenance(ctx, packageName, version, componentProvenance(comp, src)); err != nil {
+				Warn("Failed to attach provenance to %s: %v", comp.ID, err)
+			} else {
+				Debug("Attached provenance to %s", comp.ID)
+			}
+		}
+
 		// Create processed component with ECR reference
 		// Convert package name from namespace/component to namespace:component for Spin compatibility
 		spinPackageName := strings.Replace(packageName, "/", ":", 1)
@@ -519,6 +846,7 @@ func processComponents(ctx context.Context, manifest *validation.Application, ec
 			},
 			Build:     comp.Build,
 			Variables: comp.Variables,
+			Secrets:   comp.Secrets,
 		}
 		processedManifest.Components = append(processedManifest.Components, processedComp)
 	}
@@ -526,6 +854,33 @@ func processComponents(ctx context.Context, manifest *validation.Application, ec
 	return processedManifest, nil
 }
 
+// pushVersion returns the version a component is pushed to the FTL
+// registry with: the version it was released with by 'ftl component
+// release', or the app's
+func pushVersion(manifest *validation.Application, meta *oci.Metadata) string {
+	if meta != nil && meta.Version != "" {
+		return meta.Version
+	}
+	return appVersion(manifest)
+}
+
+// appVersion returns the version an app is deployed with
+func appVersion(manifest *validation.Application) string {
+	if manifest.Version != "" {
+		return manifest.Version
+	}
+	return "0.1.0"
+}
+
+// componentDir returns the directory of a local component's source, which
+// holds its component.yaml
+func componentDir(sourcePath string) string {
+	if strings.HasSuffix(sourcePath, ".wasm") {
+		return filepath.Dir(sourcePath)
+	}
+	return sourcePath
+}
+
 // findBuiltWASM locates the built WASM file for a local component
 func findBuiltWASM(sourcePath, componentID string) (string, error) {
 	// Check if sourcePath is already a .wasm file
@@ -554,6 +909,39 @@ func findBuiltWASM(sourcePath, componentID string) (string, error) {
 	return "", fmt.Errorf("could not find built WASM file for component %s", componentID)
 }
 
+// referencedSecrets returns the secrets the components of an app read,
+// sorted and without duplicates
+func referencedSecrets(manifest *validation.Application) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, comp := range manifest.Components {
+		for _, name := range comp.Secrets {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fetchDeployedConfig returns the components of an app's current
+// deployment, sorted by name. The platform does not report deployed
+// variables or versions, so only component names are known.
+func fetchDeployedConfig(ctx context.Context, apiClient api.FTLAPI, appID string) (*DeployedConfig, error) {
+	components, err := api.IterateComponents(ctx, apiClient, appID).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list components: %w", err)
+	}
+	deployed := &DeployedConfig{Components: make([]string, 0, len(components))}
+	for _, comp := range components {
+		deployed.Components = append(deployed.Components, comp.ComponentName)
+	}
+	sort.Strings(deployed.Components)
+	return deployed, nil
+}
+
 // createDeploymentRequest creates a flat FTL deployment request (no "application" wrapper)
 func createDeploymentRequest(manifest *validation.Application, opts *DeployOptions) map[string]interface{} {
 	// Build flat FTL deployment request
@@ -561,12 +949,7 @@ func createDeploymentRequest(manifest *validation.Application, opts *DeployOptio
 		"name": manifest.Name,
 	}
 
-	// Add version
-	if manifest.Version != "" {
-		req["version"] = manifest.Version
-	} else {
-		req["version"] = "0.1.0"
-	}
+	req["version"] = appVersion(manifest)
 
 	// Add description if present
 	if manifest.Description != "" {
@@ -624,6 +1007,11 @@ func createDeploymentRequest(manifest *validation.Application, opts *DeployOptio
 			deployComp["variables"] = comp.Variables
 		}
 
+		// Secrets are referenced by name; the platform provides the values
+		if len(comp.Secrets) > 0 {
+			deployComp["secrets"] = comp.Secrets
+		}
+
 		components = append(components, deployComp)
 	}
 	req["components"] = components
@@ -662,6 +1050,9 @@ func displayDryRunSummary(manifest *validation.Application, appExists bool) {
 		fmt.Printf("  Description: %s\n", manifest.Description)
 	}
 	fmt.Printf("  Access Control: %s\n", manifest.Access)
+	if len(manifest.Regions) > 0 {
+		fmt.Printf("  Regions: %s\n", strings.Join(manifest.Regions, ", "))
+	}
 
 	if manifest.Auth != nil {
 		if manifest.Auth.JWTIssuer != "" {
@@ -716,6 +1107,53 @@ func displayDryRunSummary(manifest *validation.Application, appExists bool) {
 	fmt.Println("To perform the actual deployment, run without --dry-run")
 }
 
+// deployResult is the machine-readable result of 'ftl deploy'
+type deployResult struct {
+	App          string                  `json:"app"`
+	AppID        string                  `json:"app_id,omitempty"`
+	DeploymentID string                  `json:"deployment_id,omitempty"`
+	Status       string                  `json:"status"`
+	Environment  string                  `json:"environment,omitempty"`
+	Access       string                  `json:"access,omitempty"`
+	URL          string                  `json:"url,omitempty"`
+	MCPURL       string                  `json:"mcp_url,omitempty"`
+	Components   []deployResultComponent `json:"components"`
+	// Regions reports each region of a multi-region deployment
+	Regions []platform.RegionStatus `json:"regions,omitempty"`
+}
+
+// deployResultComponent describes one component of a deployed application
+type deployResultComponent struct {
+	ID     string `json:"id"`
+	MCPURL string `json:"mcp_url,omitempty"`
+}
+
+func newDeployResult(manifest *validation.Application, opts *DeployOptions, status string) *deployResult {
+	result := &deployResult{
+		App:         manifest.Name,
+		Status:      status,
+		Environment: opts.Environment,
+		Access:      manifest.Access,
+		Components:  make([]deployResultComponent, 0, len(manifest.Components)),
+	}
+	for _, comp := range manifest.Components {
+		result.Components = append(result.Components, deployResultComponent{ID: comp.ID})
+	}
+	return result
+}
+
+// SetURL records the application URL and derives the MCP endpoints from it
+func (r *deployResult) SetURL(baseURL string) {
+	if baseURL == "" {
+		return
+	}
+	r.URL = baseURL
+	r.MCPURL = strings.TrimRight(baseURL, "/") + "/mcp"
+	for i := range r.Components {
+		r.Components[i].MCPURL = fmt.Sprintf("%s/x/%s", r.MCPURL, r.Components[i].ID)
+	}
+}
+
 // displayMCPUrls displays a table showing MCP URLs for the application and its components
 func displayMCPUrls(baseURL string, components []*validation.Component) {
 	// Ensure the base URL ends with /mcp
@@ -809,7 +1247,7 @@ func selectOrganization(orgIDs []string) (string, error) {
 						Message: fmt.Sprintf("Use current organization '%s'?", displayName),
 						Default: true,
 					}
-					if err := survey.AskOne(prompt, &useConfig); err == nil && useConfig {
+					if err := ask(prompt, &useConfig, "Pass the organization with --org or set FTL_ORG_ID"); err == nil && useConfig {
 						// Update last used time
 						if hasInfo {
 							orgInfo.LastUsed = time.Now().Format(time.RFC3339)
@@ -845,7 +1283,7 @@ func selectOrganization(orgIDs []string) (string, error) {
 		Help:    "Choose which organization to deploy this application to",
 	}
 
-	err = survey.AskOne(prompt, &selected)
+	err = ask(prompt, &selected, "Pass the organization with --org or set FTL_ORG_ID")
 	if err != nil {
 		return "", err
 	}
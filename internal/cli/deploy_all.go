@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceFile declares the dependencies between the apps of a monorepo
+const workspaceFile = ".ftl/workspace.yaml"
+
+// deployAllMaxDepth is how deep below the working directory apps are
+// searched for
+const deployAllMaxDepth = 3
+
+// Deploy states of an app deployed with --all
+const (
+	appDeployed = "deployed"
+	appFailed   = "failed"
+	appSkipped  = "skipped"
+	appDryRun   = "dry_run"
+)
+
+// workspaceConfig is the content of .ftl/workspace.yaml:
+//
+//	apps:
+//	  api:
+//	    depends_on: [auth]
+type workspaceConfig struct {
+	Apps map[string]struct {
+		DependsOn []string `yaml:"depends_on"`
+	} `yaml:"apps"`
+}
+
+// workspaceApp is an app found by 'ftl deploy --all'
+type workspaceApp struct {
+	Name      string
+	Dir       string
+	Config    string
+	DependsOn []string
+}
+
+// deployAllApp is the outcome of deploying one app with --all
+type deployAllApp struct {
+	Name   string      `json:"name"`
+	Dir    string      `json:"dir"`
+	Status string      `json:"status"`
+	Error  string      `json:"error,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+}
+
+// deployAllResult is the machine-readable form of 'ftl deploy --all'
+type deployAllResult struct {
+	Apps []deployAllApp `json:"apps"`
+}
+
+// runDeployAll deploys every app below the working directory, in the
+// order of the dependencies declared in .ftl/workspace.yaml. Apps whose
+// dependencies failed are skipped; the others are deployed regardless.
+func runDeployAll(ctx context.Context, opts *DeployOptions) error {
+	if opts.ConfigFile != "" {
+		return &usageError{fmt.Errorf("--all deploys every app it finds; it cannot be combined with --file")}
+	}
+	if structuredFormat() != "" && !opts.Yes && !opts.DryRun {
+		return &usageError{fmt.Errorf("--yes is required when using --output %s", structuredFormat())}
+	}
+
+	apps, err := findWorkspaceApps(".")
+	if err != nil {
+		return err
+	}
+	if apps, err = selectWorkspaceApps(apps, opts.Only); err != nil {
+		return err
+	}
+	if apps, err = orderWorkspaceApps(apps); err != nil {
+		return err
+	}
+
+	// Log in once for all apps
+	if !opts.DryRun && !isSpinTarget(opts) {
+		authManager, err := newAuthManager()
+		if err != nil {
+			return err
+		}
+		if err := loginForDeploy(ctx, authManager); err != nil {
+			return err
+		}
+	}
+
+	selected := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		selected[app.Name] = true
+	}
+	deployed := make(map[string]bool, len(apps))
+	result := deployAllResult{}
+	for i, app := range apps {
+		outcome := deployAllApp{Name: app.Name, Dir: app.Dir}
+		for _, dep := range app.DependsOn {
+			if selected[dep] && !deployed[dep] {
+				outcome.Status = appSkipped
+				outcome.Error = fmt.Sprintf("dependency '%s' was not deployed", dep)
+				Warn("[%d/%d] Skipping %s: %s", i+1, len(apps), app.Name, outcome.Error)
+				break
+			}
+		}
+
+		if outcome.Status == "" {
+			Info("[%d/%d] Deploying %s from %s", i+1, len(apps), app.Name, app.Dir)
+			appOpts := *opts
+			appOpts.All = false
+			appOpts.ConfigFile = app.Config
+			appOpts.loggedIn = true
+			doc, err := deployInDir(ctx, app.Dir, &appOpts)
+			outcome.Result = doc
+			switch {
+			case err != nil:
+				outcome.Status = appFailed
+				outcome.Error = err.Error()
+				Error("Deploying %s failed: %v", app.Name, err)
+			case opts.DryRun:
+				outcome.Status = appDryRun
+				deployed[app.Name] = true
+			default:
+				outcome.Status = appDeployed
+				deployed[app.Name] = true
+			}
+		}
+		result.Apps = append(result.Apps, outcome)
+	}
+
+	err = deployAllError(result)
+	if structuredFormat() != "" {
+		if writeErr := writeResult(result); writeErr != nil {
+			return writeErr
+		}
+		if err != nil {
+			return &reportedError{err}
+		}
+		return nil
+	}
+	if printErr := printDeployAll(result); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+// deployInDir deploys the app in dir from within that directory, as the
+// build and synthesis steps expect. With structured output, the app's
+// result document is returned instead of written.
+func deployInDir(ctx context.Context, dir string, opts *DeployOptions) (interface{}, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, fmt.Errorf("failed to enter %s: %w", dir, err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	if structuredFormat() == "" {
+		return nil, runDeploy(ctx, opts)
+	}
+	output := colorOutput
+	var buf bytes.Buffer
+	colorOutput = &buf
+	err = runDeploy(ctx, opts)
+	colorOutput = output
+
+	var doc interface{}
+	if buf.Len() > 0 {
+		// JSON documents are YAML too
+		_ = yaml.Unmarshal(buf.Bytes(), &doc)
+	}
+	return doc, err
+}
+
+// findWorkspaceApps returns the apps in root and its subdirectories, with
+// their dependencies from root's .ftl/workspace.yaml
+func findWorkspaceApps(root string) ([]*workspaceApp, error) {
+	var apps []*workspaceApp
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		name := entry.Name()
+		depth := strings.Count(filepath.ToSlash(path), "/")
+		if path != root && (strings.HasPrefix(name, ".") || projectSkipDirs[name] || depth >= deployAllMaxDepth) {
+			return filepath.SkipDir
+		}
+		for _, config := range []string{"ftl.yaml", "ftl.yml", "ftl.json", "app.cue"} {
+			if _, err := os.Stat(filepath.Join(path, config)); err == nil {
+				apps = append(apps, &workspaceApp{Name: projectName(path), Dir: path, Config: config})
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for apps: %w", err)
+	}
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("no FTL apps (ftl.yaml, ftl.json, or app.cue) found in %s or its subdirectories", root)
+	}
+
+	seen := make(map[string]string, len(apps))
+	for _, app := range apps {
+		if other, ok := seen[app.Name]; ok {
+			return nil, fmt.Errorf("apps in %s and %s are both named '%s'", other, app.Dir, app.Name)
+		}
+		seen[app.Name] = app.Dir
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, workspaceFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return apps, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", workspaceFile, err)
+	}
+	var workspace workspaceConfig
+	if err := yaml.Unmarshal(data, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", workspaceFile, err)
+	}
+	for name, declared := range workspace.Apps {
+		if _, ok := seen[name]; !ok {
+			return nil, fmt.Errorf("%s lists app '%s', which was not found", workspaceFile, name)
+		}
+		for _, dep := range declared.DependsOn {
+			if _, ok := seen[dep]; !ok || dep == name {
+				return nil, fmt.Errorf("%s: app '%s' depends on '%s', which must be another app of the workspace", workspaceFile, name, dep)
+			}
+		}
+	}
+	for _, app := range apps {
+		app.DependsOn = workspace.Apps[app.Name].DependsOn
+	}
+	return apps, nil
+}
+
+// selectWorkspaceApps keeps the apps named in only, by name or directory.
+// Dependencies that are not selected are assumed to be deployed already.
+func selectWorkspaceApps(apps []*workspaceApp, only []string) ([]*workspaceApp, error) {
+	if len(only) == 0 {
+		return apps, nil
+	}
+	var selected []*workspaceApp
+	for _, want := range only {
+		found := false
+		for _, app := range apps {
+			if app.Name == want || filepath.Clean(app.Dir) == filepath.Clean(want) {
+				if !slices.Contains(selected, app) {
+					selected = append(selected, app)
+				}
+				found = true
+			}
+		}
+		if !found {
+			return nil, &usageError{fmt.Errorf("--only %s matches no app", want)}
+		}
+	}
+	return selected, nil
+}
+
+// orderWorkspaceApps sorts apps so that each comes after the apps it
+// depends on, and otherwise by directory
+func orderWorkspaceApps(apps []*workspaceApp) ([]*workspaceApp, error) {
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Dir < apps[j].Dir })
+	selected := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		selected[app.Name] = true
+	}
+
+	var ordered []*workspaceApp
+	placed := make(map[string]bool, len(apps))
+	for len(ordered) < len(apps) {
+		progress := false
+		for _, app := range apps {
+			if placed[app.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range app.DependsOn {
+				if selected[dep] && !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, app)
+				placed[app.Name] = true
+				progress = true
+			}
+		}
+		if !progress {
+			var cycle []string
+			for _, app := range apps {
+				if !placed[app.Name] {
+					cycle = append(cycle, app.Name)
+				}
+			}
+			return nil, fmt.Errorf("%s: dependency cycle between %s", workspaceFile, strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// deployAllError reports the apps that were not deployed
+func deployAllError(result deployAllResult) error {
+	var failed []string
+	for _, app := range result.Apps {
+		if app.Status == appFailed || app.Status == appSkipped {
+			failed = append(failed, app.Name)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d apps were not deployed: %s", len(failed), len(result.Apps), strings.Join(failed, ", "))
+}
+
+// printDeployAll summarizes the deployment of several apps
+func printDeployAll(result deployAllResult) error {
+	_, _ = fmt.Fprintln(colorOutput)
+	tb := NewTableBuilder("APP", "DIRECTORY", "STATUS", "ERROR")
+	for _, app := range result.Apps {
+		tb.AddRow(app.Name, app.Dir, app.Status, orDash(app.Error))
+	}
+	return tb.Write(NewDataWriter(colorOutput, "table"))
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeWorkspaceApp writes a prebuilt app with one component to dir
+func writeWorkspaceApp(t *testing.T, dir, config string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tool.wasm"), []byte("\x00asm\x01\x00\x00\x00"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ftl.yaml"), []byte(config), 0600))
+}
+
+func TestFindWorkspaceApps_Order(t *testing.T) {
+	chdirTemp(t)
+	writeWorkspaceApp(t, filepath.Join("apps", "api"), "name: api\n")
+	writeWorkspaceApp(t, filepath.Join("apps", "auth"), "name: auth\n")
+	writeWorkspaceApp(t, filepath.Join("apps", "web"), "name: web\n")
+	writeWorkspaceApp(t, filepath.Join("apps", "web", "node_modules", "dep"), "name: dep\n")
+	require.NoError(t, os.MkdirAll(".ftl", 0750))
+	require.NoError(t, os.WriteFile(workspaceFile, []byte("apps:\n  api:\n    depends_on: [web]\n  web:\n    depends_on: [auth]\n"), 0600))
+
+	apps, err := findWorkspaceApps(".")
+	require.NoError(t, err)
+	apps, err = orderWorkspaceApps(apps)
+	require.NoError(t, err)
+	var names []string
+	for _, app := range apps {
+		names = append(names, app.Name)
+	}
+	assert.Equal(t, []string{"auth", "web", "api"}, names)
+
+	selected, err := selectWorkspaceApps(apps, []string{"api", filepath.Join("apps", "auth")})
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+	_, err = selectWorkspaceApps(apps, []string{"missing"})
+	assert.ErrorContains(t, err, "--only missing matches no app")
+
+	require.NoError(t, os.WriteFile(workspaceFile, []byte("apps:\n  api:\n    depends_on: [web]\n  web:\n    depends_on: [api]\n"), 0600))
+	apps, err = findWorkspaceApps(".")
+	require.NoError(t, err)
+	_, err = orderWorkspaceApps(apps)
+	assert.ErrorContains(t, err, "dependency cycle between api, web")
+
+	require.NoError(t, os.WriteFile(workspaceFile, []byte("apps:\n  api:\n    depends_on: [billing]\n"), 0600))
+	_, err = findWorkspaceApps(".")
+	assert.ErrorContains(t, err, "app 'api' depends on 'billing'")
+}
+
+func TestRunDeployAll_Platform(t *testing.T) {
+	platform := usePlatform(t)
+	chdirTemp(t)
+	out := setGlobalOutput(t, "json")
+	writeWorkspaceApp(t, filepath.Join("apps", "api"), "name: api\ncomponents:\n  - id: tool\n    source: ./tool.wasm\n")
+	writeWorkspaceApp(t, filepath.Join("apps", "web"), "name: web\ncomponents:\n  - id: tool\n    source: ./tool.wasm\n")
+	require.NoError(t, os.MkdirAll(".ftl", 0750))
+	require.NoError(t, os.WriteFile(workspaceFile, []byte("apps:\n  api:\n    depends_on: [web]\n"), 0600))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, runDeployAll(context.Background(), &DeployOptions{Prebuilt: true, Yes: true}))
+	after, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, after, "the working directory is restored")
+
+	apps := platform.Apps()
+	require.Len(t, apps, 2)
+	assert.Equal(t, "web", apps[0].AppName, "dependencies are deployed first")
+	assert.Equal(t, "api", apps[1].AppName)
+
+	var result deployAllResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	require.Len(t, result.Apps, 2)
+	assert.Equal(t, appDeployed, result.Apps[0].Status)
+	assert.Equal(t, appDeployed, result.Apps[1].Status)
+	doc, _ := result.Apps[1].Result.(map[string]interface{})
+	assert.Equal(t, "deployed", doc["status"])
+
+	// An app whose dependency fails is skipped
+	writeWorkspaceApp(t, filepath.Join("apps", "web"), "name: web\naccess: custom\ncomponents:\n  - id: tool\n    source: ./tool.wasm\n")
+	out.Reset()
+	err = runDeployAll(context.Background(), &DeployOptions{Prebuilt: true, Yes: true})
+	var reported *reportedError
+	require.ErrorAs(t, err, &reported)
+	assert.ErrorContains(t, err, "2 of 2 apps were not deployed: web, api")
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, appFailed, result.Apps[0].Status)
+	assert.Equal(t, appSkipped, result.Apps[1].Status)
+	assert.Len(t, platform.Deployments(), 2)
+}
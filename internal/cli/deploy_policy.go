@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/fastertools/ftl/internal/config"
+	"github.com/fastertools/ftl/internal/deploy"
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// checkArtifactSignatureTag looks up registry signature tags; overridden in
+// tests
+var checkArtifactSignatureTag deploy.SignatureTagChecker = func(ctx context.Context, src *validation.RegistrySource) (bool, error) {
+	return oci.HasSignatureTag(ctx, src.Registry, src.Package, src.Version)
+}
+
+// fetchArtifactProvenance looks up registry provenance; overridden in tests
+var fetchArtifactProvenance deploy.ProvenanceFetcher = func(ctx context.Context, src *validation.RegistrySource) (*oci.Provenance, error) {
+	return oci.FetchProvenance(ctx, src.Registry, src.Package, src.Version)
+}
+
+// deployPolicyPaths returns the policy files enforced for a project: the
+// project's own .ftl/policy.yaml and the policy.yaml in the FTL config
+// directory, where organizations can distribute a policy for all projects.
+func deployPolicyPaths(projectDir string) []string {
+	paths := []string{filepath.Join(projectDir, deploy.ProjectPolicyPath)}
+	if dir, err := config.Dir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "policy.yaml"))
+	}
+	return paths
+}
+
+// enforceDeployPolicies fails when the application breaks any policy that
+// applies to the project
+func enforceDeployPolicies(ctx context.Context, manifest *validation.Application, projectDir string) error {
+	policies, err := loadDeployPolicies(projectDir)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	if err := deploy.CheckPolicies(ctx, manifest, policies, checkArtifactSignatureTag, fetchArtifactProvenance); err != nil {
+		return err
+	}
+	Success("Deploy policy checks passed")
+	return nil
+}
+
+// loadDeployPolicies loads the policies that apply to the project
+func loadDeployPolicies(projectDir string) ([]*deploy.Policy, error) {
+	var policies []*deploy.Policy
+	for _, file := range deployPolicyPaths(projectDir) {
+		policy, err := deploy.LoadPolicy(file)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			Debug("Enforcing deploy policy %s", file)
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/deploy"
+	"github.com/fastertools/ftl/validation"
+)
+
+func TestEnforceDeployPolicies(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	projectDir := t.TempDir()
+
+	app := &validation.Application{
+		Name: "demo",
+		Components: []*validation.Component{
+			{ID: "echo", Source: &validation.LocalSource{Path: "./echo"}},
+			{ID: "fluid", Source: &validation.RegistrySource{Registry: "ghcr.io", Package: "acme:fluid", Version: "latest"}},
+		},
+	}
+
+	// Without policies everything is allowed
+	require.NoError(t, enforceDeployPolicies(context.Background(), app, projectDir))
+
+	// A project policy forbidding floating versions
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".ftl"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, deploy.ProjectPolicyPath), []byte("forbid_latest: true\n"), 0600))
+
+	// An organization policy requiring registry components
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "ftl"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(configHome, "ftl", "policy.yaml"), []byte("require_registry_components: true\n"), 0600))
+
+	err := enforceDeployPolicies(context.Background(), app, projectDir)
+	var policyErr *deploy.PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	require.Len(t, policyErr.Violations, 2)
+	assert.Equal(t, "fluid", policyErr.Violations[0].Component)
+	assert.Equal(t, deploy.RuleForbidLatest, policyErr.Violations[0].Rule)
+	assert.Equal(t, "echo", policyErr.Violations[1].Component)
+	assert.Equal(t, deploy.RuleRequireRegistry, policyErr.Violations[1].Rule)
+}
+
+func TestEnforceDeployPolicies_Signatures(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".ftl"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, deploy.ProjectPolicyPath), []byte("require_signature_tag: true\n"), 0600))
+
+	oldCheck := checkArtifactSignatureTag
+	defer func() { checkArtifactSignatureTag = oldCheck }()
+	var checked []string
+	checkArtifactSignatureTag = func(ctx context.Context, src *validation.RegistrySource) (bool, error) {
+		checked = append(checked, src.Package)
+		return true, nil
+	}
+
+	app := &validation.Application{Components: []*validation.Component{
+		{ID: "fluid", Source: &validation.RegistrySource{Registry: "ghcr.io", Package: "acme:fluid", Version: "1.0.0"}},
+	}}
+	require.NoError(t, enforceDeployPolicies(context.Background(), app, projectDir))
+	assert.Equal(t, []string{"acme:fluid"}, checked)
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
@@ -50,13 +51,31 @@ type DeploymentChanges struct {
 	EnvironmentChanged bool
 	OldEnvironment     string
 	NewEnvironment     string
+
+	// Compared with the deployed app
+	AuthChanged map[string]VariableChange
 }
 
 // VariableChange represents a variable modification
 type VariableChange struct {
-	Old   string
-	New   string
-	Added bool
+	Old     string
+	New     string
+	Added   bool
+	Removed bool
+}
+
+// DeployedConfig is the configuration of an app's current deployment
+type DeployedConfig struct {
+	JWTIssuer    string
+	JWTAudience  string
+	AllowedRoles []string
+	Components   []string
+}
+
+// Sensitive reports whether the changes touch the access mode or auth
+// settings, which need an explicit confirmation even with --yes
+func (c *DeploymentChanges) Sensitive() bool {
+	return c.AccessModeChanged || len(c.AuthChanged) > 0
 }
 
 // ShowDeploymentPreview displays a comprehensive deployment preview
@@ -216,24 +235,22 @@ func showDeploymentChanges(changes *DeploymentChanges) {
 			strings.Join(changes.ComponentsUpdated, ", "))
 	}
 
+	// Auth changes (critical!)
+	for _, key := range sortedKeys(changes.AuthChanged) {
+		hasChanges = true
+		change := changes.AuthChanged[key]
+		fmt.Printf("  %s %s: %s → %s\n",
+			color.New(color.FgRed).Sprint("⚠"),
+			key,
+			orNone(change.Old),
+			orNone(change.New))
+	}
+
 	// Variable changes
 	if len(changes.VariablesChanged) > 0 {
 		hasChanges = true
 		fmt.Printf("  %s Variables:\n", color.New(color.FgCyan).Sprint("○"))
-		for key, change := range changes.VariablesChanged {
-			if change.Added {
-				fmt.Printf("    %s %s = %s\n",
-					color.New(color.FgGreen).Sprint("+"),
-					key,
-					maskIfSensitive(key, change.New))
-			} else {
-				fmt.Printf("    %s %s: %s → %s\n",
-					color.New(color.FgBlue).Sprint("~"),
-					key,
-					maskIfSensitive(key, change.Old),
-					maskIfSensitive(key, change.New))
-			}
-		}
+		showVariableChanges(changes.VariablesChanged, "    ")
 	}
 
 	if !hasChanges {
@@ -242,6 +259,43 @@ func showDeploymentChanges(changes *DeploymentChanges) {
 	}
 }
 
+// showVariableChanges prints variable changes in key order, masking
+// sensitive values
+func showVariableChanges(changes map[string]VariableChange, indent string) {
+	for _, key := range sortedKeys(changes) {
+		change := changes[key]
+		switch {
+		case change.Added:
+			fmt.Printf("%s%s %s = %s\n",
+				indent,
+				color.New(color.FgGreen).Sprint("+"),
+				key,
+				maskIfSensitive(key, change.New))
+		case change.Removed:
+			fmt.Printf("%s%s %s (was %s)\n",
+				indent,
+				color.New(color.FgRed).Sprint("-"),
+				key,
+				maskIfSensitive(key, change.Old))
+		default:
+			fmt.Printf("%s%s %s: %s → %s\n",
+				indent,
+				color.New(color.FgBlue).Sprint("~"),
+				key,
+				maskIfSensitive(key, change.Old),
+				maskIfSensitive(key, change.New))
+		}
+	}
+}
+
+// orNone shows an empty setting as (none)
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
 // showAccessModeImplications explains what the access mode means
 func showAccessModeImplications(mode string) {
 	implications := ""
@@ -327,9 +381,12 @@ func maskIfSensitive(key, value string) string {
 	return value
 }
 
-// ConfirmDeployment prompts for deployment confirmation with preview
-func ConfirmDeployment(preview *DeploymentPreview, forceYes bool) (bool, error) {
-	if forceYes {
+// ConfirmDeployment prompts for deployment confirmation with preview.
+// forceYes skips the prompt, unless the update changes the access mode or
+// auth settings and allowSensitive is not set.
+func ConfirmDeployment(preview *DeploymentPreview, forceYes, allowSensitive bool) (bool, error) {
+	sensitive := preview.IsUpdate && preview.Changes != nil && preview.Changes.Sensitive()
+	if forceYes && (!sensitive || allowSensitive) {
 		return true, nil
 	}
 
@@ -359,6 +416,12 @@ func ConfirmDeployment(preview *DeploymentPreview, forceYes bool) (bool, error)
 		}
 	}
 
+	hint := "Pass --yes to deploy without confirmation"
+	if sensitive {
+		message = "⚠️  Access or auth settings change. " + strings.TrimPrefix(message, "⚠️  ")
+		hint = "Pass --yes --allow-sensitive-changes to deploy access and auth changes without confirmation"
+	}
+
 	// Interactive confirmation with no default - user must explicitly choose
 	// This follows the CDK pattern for safety
 	confirm := false
@@ -371,7 +434,7 @@ func ConfirmDeployment(preview *DeploymentPreview, forceYes bool) (bool, error)
 			Help:    "You must explicitly type 'y' for yes or 'n' for no",
 		}
 
-		err := survey.AskOne(prompt, &response, survey.WithValidator(func(val interface{}) error {
+		err := ask(prompt, &response, hint, survey.WithValidator(func(val interface{}) error {
 			str, ok := val.(string)
 			if !ok {
 				return fmt.Errorf("invalid response")
@@ -506,6 +569,76 @@ func calculateChanges(
 	return changes
 }
 
+// diffDeployedConfig adds the differences between the configuration to
+// deploy and the app's current deployment to changes: the components added
+// and removed, and auth settings. Every deployed component is pushed again,
+// so the remaining ones count as updated.
+func diffDeployedConfig(
+	changes *DeploymentChanges,
+	manifest *validation.Application,
+	opts *DeployOptions,
+	deployed *DeployedConfig,
+) {
+	changes.ComponentsUpdated = nil
+	for _, comp := range manifest.Components {
+		if containsString(deployed.Components, comp.ID) {
+			changes.ComponentsUpdated = append(changes.ComponentsUpdated, comp.ID)
+		} else {
+			changes.ComponentsAdded = append(changes.ComponentsAdded, comp.ID)
+		}
+	}
+	for _, name := range deployed.Components {
+		if !hasComponent(manifest, name) {
+			changes.ComponentsRemoved = append(changes.ComponentsRemoved, name)
+		}
+	}
+
+	// Auth settings are sent only for the access modes that use them
+	var issuer, audience string
+	if manifest.Auth != nil && (manifest.Access == "org" || manifest.Access == "custom") {
+		issuer = manifest.Auth.JWTIssuer
+		audience = manifest.Auth.JWTAudience
+	}
+	var roles []string
+	if manifest.Access == "org" {
+		roles = opts.AllowedRoles
+	}
+	changes.AuthChanged = make(map[string]VariableChange)
+	addAuthChange(changes.AuthChanged, "JWT issuer", deployed.JWTIssuer, issuer)
+	addAuthChange(changes.AuthChanged, "JWT audience", deployed.JWTAudience, audience)
+	addAuthChange(changes.AuthChanged, "Allowed roles", joinSorted(deployed.AllowedRoles), joinSorted(roles))
+}
+
+func addAuthChange(changes map[string]VariableChange, key, old, new string) {
+	if old != new {
+		changes[key] = VariableChange{Old: old, New: new}
+	}
+}
+
+func hasComponent(manifest *validation.Application, id string) bool {
+	for _, comp := range manifest.Components {
+		if comp.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinSorted(list []string) string {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
 // formatFileSize formats bytes into human-readable size
 func formatFileSize(bytes int64) string {
 	const unit = 1024
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/internal/api/apitest"
+	"github.com/fastertools/ftl/validation"
+)
+
+func testDeployedConfig() *DeployedConfig {
+	return &DeployedConfig{
+		Components: []string{"legacy", "weather"},
+	}
+}
+
+func TestFetchDeployedConfig(t *testing.T) {
+	f := apitest.NewFake()
+	appID := f.AddApp("test-app", api.AppAccessControlPublic).AppId.String()
+	_, err := f.CreateDeployCredentials(context.Background(), appID, []string{"weather", "legacy"})
+	require.NoError(t, err)
+
+	deployed, err := fetchDeployedConfig(context.Background(), f, appID)
+	require.NoError(t, err)
+	assert.Equal(t, testDeployedConfig(), deployed)
+}
+
+func TestDiffDeployedConfig(t *testing.T) {
+	manifest := &validation.Application{
+		Name:   "test-app",
+		Access: "public",
+		Components: []*validation.Component{
+			{ID: "weather"},
+			{ID: "news"},
+		},
+	}
+	opts := &DeployOptions{}
+
+	changes := calculateChanges(manifest, opts, "public")
+	diffDeployedConfig(changes, manifest, opts, testDeployedConfig())
+
+	assert.Equal(t, []string{"weather"}, changes.ComponentsUpdated)
+	assert.Equal(t, []string{"news"}, changes.ComponentsAdded)
+	assert.Equal(t, []string{"legacy"}, changes.ComponentsRemoved)
+	assert.Empty(t, changes.AuthChanged)
+	assert.False(t, changes.Sensitive())
+}
+
+func TestDiffDeployedConfig_Auth(t *testing.T) {
+	deployed := testDeployedConfig()
+	deployed.JWTIssuer = "https://old.example.com"
+	deployed.AllowedRoles = []string{"admin"}
+
+	manifest := &validation.Application{
+		Name:   "test-app",
+		Access: "org",
+		Auth:   &validation.AuthConfig{JWTIssuer: "https://new.example.com"},
+		Components: []*validation.Component{
+			{ID: "weather"},
+		},
+	}
+	opts := &DeployOptions{AllowedRoles: []string{"member", "admin"}}
+
+	changes := calculateChanges(manifest, opts, "org")
+	diffDeployedConfig(changes, manifest, opts, deployed)
+
+	assert.Equal(t, map[string]VariableChange{
+		"JWT issuer":    {Old: "https://old.example.com", New: "https://new.example.com"},
+		"Allowed roles": {Old: "admin", New: "admin, member"},
+	}, changes.AuthChanged)
+	assert.True(t, changes.Sensitive())
+}
+
+func TestDeploymentChanges_Sensitive(t *testing.T) {
+	changes := &DeploymentChanges{
+		VariablesChanged:  map[string]VariableChange{"db_password": {Old: "a", New: "b"}},
+		ComponentsUpdated: []string{"weather"},
+	}
+	assert.False(t, changes.Sensitive())
+
+	assert.True(t, (&DeploymentChanges{AccessModeChanged: true}).Sensitive())
+	assert.True(t, (&DeploymentChanges{AuthChanged: map[string]VariableChange{"JWT audience": {New: "ftl"}}}).Sensitive())
+}
+
+func TestConfirmDeployment_SensitiveChanges(t *testing.T) {
+	setNonInteractive(t)
+
+	preview := &DeploymentPreview{
+		IsUpdate:    true,
+		AppName:     "test-app",
+		AccessMode:  "public",
+		Environment: "production",
+		Changes: &DeploymentChanges{
+			AuthChanged: map[string]VariableChange{"JWT issuer": {Old: "https://old.example.com"}},
+		},
+	}
+
+	// --yes alone does not deploy auth changes
+	_, err := ConfirmDeployment(preview, true, false)
+	require.Error(t, err)
+	assert.Equal(t, ftlerr.InputRequired, ftlerr.CodeOf(err))
+	assert.Contains(t, ftlerr.HintOf(err), "--allow-sensitive-changes")
+
+	confirmed, err := ConfirmDeployment(preview, true, true)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+
+	// Other changes only need --yes
+	preview.Changes = &DeploymentChanges{ComponentsUpdated: []string{"weather"}}
+	confirmed, err = ConfirmDeployment(preview, true, false)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+}
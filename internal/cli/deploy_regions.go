@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/platform"
+)
+
+// regionDeployer deploys to one region, returning the application URL and
+// the deployment ID, which may be set even if the deployment failed
+type regionDeployer func(region string) (url, deploymentID string, err error)
+
+// deployRegions deploys to each region in turn, carrying on past failures
+// so that one unhealthy location does not hold back the others
+func deployRegions(regions []string, deployTo regionDeployer) *platform.Rollout {
+	rollout := platform.NewRollout(regions)
+	for _, region := range regions {
+		Info("Deploying to %s...", region)
+		rollout.Start(region)
+		url, deploymentID, err := deployTo(region)
+		if err != nil {
+			Error("Deployment to %s failed: %v", region, err)
+			rollout.Fail(region, deploymentID, err)
+			continue
+		}
+		Success("Deployed to %s", region)
+		rollout.Succeed(region, deploymentID, url)
+	}
+	return rollout
+}
+
+// rolloutURL returns the URL of the first region deployed to
+func rolloutURL(rollout *platform.Rollout) string {
+	for _, region := range rollout.Regions() {
+		if region.URL != "" {
+			return region.URL
+		}
+	}
+	return ""
+}
+
+// rolloutError reports the regions a deployment failed in, with a hint to
+// retry just those
+func rolloutError(rollout *platform.Rollout) error {
+	var failed *platform.RolloutError
+	if !errors.As(rollout.Err(), &failed) {
+		return nil
+	}
+	return ftlerr.Wrap(failed, ftlerr.DeployFailed, "multi-region rollout incomplete").
+		WithHint(fmt.Sprintf("Retry the failed regions with 'ftl deploy --region %s'", strings.Join(failed.FailedRegions(), ",")))
+}
+
+// printRollout summarizes a multi-region deployment
+func printRollout(rollout *platform.Rollout) error {
+	_, _ = fmt.Fprintln(colorOutput)
+	tb := NewTableBuilder("REGION", "STATUS", "DEPLOYMENT", "URL")
+	for _, region := range rollout.Regions() {
+		tb.AddRow(region.Region, string(region.State), orDash(region.DeploymentID), orDash(region.URL))
+	}
+	return tb.Write(NewDataWriter(colorOutput, "table"))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/platform"
+)
+
+func TestDeployRegions(t *testing.T) {
+	setGlobalOutput(t, "")
+
+	var deployed []string
+	rollout := deployRegions([]string{"us-east-1", "eu-west-1", "ap-south-1"}, func(region string) (string, string, error) {
+		deployed = append(deployed, region)
+		if region == "eu-west-1" {
+			return "", "dep-eu", errors.New("capacity exceeded")
+		}
+		return "https://" + region + ".example.com", "dep-" + region, nil
+	})
+
+	// A failed region does not stop the rest
+	assert.Equal(t, []string{"us-east-1", "eu-west-1", "ap-south-1"}, deployed)
+	assert.Equal(t, platform.RolloutPartial, rollout.State())
+	assert.Equal(t, "https://us-east-1.example.com", rolloutURL(rollout))
+
+	regions := rollout.Regions()
+	require.Len(t, regions, 3)
+	assert.Equal(t, platform.RegionFailed, regions[1].State)
+	assert.Equal(t, "dep-eu", regions[1].DeploymentID)
+
+	err := rolloutError(rollout)
+	require.Error(t, err)
+	assert.Equal(t, ftlerr.DeployFailed, ftlerr.CodeOf(err))
+	assert.Contains(t, err.Error(), "eu-west-1: capacity exceeded")
+	assert.Equal(t, "Retry the failed regions with 'ftl deploy --region eu-west-1'", ftlerr.HintOf(err))
+}
+
+func TestDeployRegions_AllSucceeded(t *testing.T) {
+	setGlobalOutput(t, "")
+
+	rollout := deployRegions([]string{"us-east-1"}, func(region string) (string, string, error) {
+		return "https://app.example.com", "dep-1", nil
+	})
+	assert.Equal(t, platform.RolloutSucceeded, rollout.State())
+	assert.NoError(t, rolloutError(rollout))
+	assert.NoError(t, printRollout(rollout))
+}
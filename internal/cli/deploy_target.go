@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/fastertools/ftl/internal/tracing"
+	"github.com/fastertools/ftl/validation"
+)
+
+// Deployment targets
+const (
+	// TargetFTL deploys through the FTL platform API
+	TargetFTL = "ftl"
+	// TargetSpin runs the app with 'spin up', optionally from a registry
+	TargetSpin = "spin"
+	// TargetFermyonCloud deploys the app with 'spin deploy'
+	TargetFermyonCloud = "fermyon-cloud"
+)
+
+// addDeployTargetFlags adds the flags selecting where 'ftl deploy' deploys to
+func addDeployTargetFlags(cmd *cobra.Command, opts *DeployOptions) {
+	cmd.Flags().StringVar(&opts.Target, "target", TargetFTL, "Where to deploy (ftl, spin, fermyon-cloud)")
+	cmd.Flags().StringVar(&opts.Registry, "registry", "", "Registry to push the app to before running it (--target spin only)")
+	_ = cmd.RegisterFlagCompletionFunc("target", completeFixed(TargetFTL, TargetSpin, TargetFermyonCloud))
+}
+
+// isSpinTarget reports whether the deployment bypasses the FTL platform
+func isSpinTarget(opts *DeployOptions) bool {
+	return opts.Target == TargetSpin || opts.Target == TargetFermyonCloud
+}
+
+// validateTarget checks the target flags before any work is done. Flags
+// for features of the FTL platform cannot be used with other targets.
+func validateTarget(opts *DeployOptions) error {
+	switch opts.Target {
+	case "", TargetFTL:
+		if opts.Registry != "" {
+			return &usageError{fmt.Errorf("--registry can only be used with --target %s", TargetSpin)}
+		}
+		return nil
+	case TargetSpin, TargetFermyonCloud:
+	default:
+		return &usageError{fmt.Errorf("invalid target '%s': must be one of ftl, spin, fermyon-cloud", opts.Target)}
+	}
+
+	if opts.Registry != "" && opts.Target != TargetSpin {
+		return &usageError{fmt.Errorf("--registry can only be used with --target %s", TargetSpin)}
+	}
+	platformOnly := map[string]bool{
+		"--region":        len(opts.Regions) > 0,
+		"--org":           opts.OrgID != "",
+		"--no-wait":       opts.NoWait,
+		"--allowed-roles": len(opts.AllowedRoles) > 0,
+	}
+	var flags []string
+	for flag, set := range platformOnly {
+		if set {
+			flags = append(flags, flag)
+		}
+	}
+	if len(flags) > 0 {
+		sort.Strings(flags)
+		return &usageError{fmt.Errorf("%s cannot be used with --target %s: only the FTL platform supports them", strings.Join(flags, ", "), opts.Target)}
+	}
+	return nil
+}
+
+// validateTargetComponents checks that the target can run every
+// component. The FTL platform deploys component binaries only, so it
+// cannot serve the files of static components.
+func validateTargetComponents(manifest *validation.Application, opts *DeployOptions) error {
+	if isSpinTarget(opts) {
+		return nil
+	}
+	var static []string
+	for _, comp := range manifest.Components {
+		if comp.IsStatic() {
+			static = append(static, comp.ID)
+		}
+	}
+	if len(static) > 0 {
+		return &usageError{fmt.Errorf("static components cannot be deployed to the FTL platform: %s (use --target %s or --target %s)",
+			strings.Join(static, ", "), TargetSpin, TargetFermyonCloud)}
+	}
+	return nil
+}
+
+// spinImageReference returns the reference the app is pushed to in a
+// registry, named after the app and tagged with its version
+func spinImageReference(registry string, manifest *validation.Application) string {
+	version := manifest.Version
+	if version == "" {
+		version = "0.1.0"
+	}
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(registry, "/"), manifest.Name, version)
+}
+
+// spinVariableArgs returns the --variable arguments for the app variables
+// and the --var overrides, sorted by name
+func spinVariableArgs(manifest *validation.Application, opts *DeployOptions) []string {
+	variables := make(map[string]string, len(manifest.Variables)+len(opts.Variables))
+	for k, v := range manifest.Variables {
+		variables[k] = v
+	}
+	for k, v := range opts.Variables {
+		variables[k] = v
+	}
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		args = append(args, "--variable", name+"="+variables[name])
+	}
+	return args
+}
+
+// spinTargetCommands returns the spin commands that deploy the app to a
+// target other than the FTL platform, in order
+func spinTargetCommands(manifest *validation.Application, opts *DeployOptions) [][]string {
+	variables := spinVariableArgs(manifest, opts)
+
+	if opts.Target == TargetFermyonCloud {
+		return [][]string{append([]string{"deploy"}, variables...)}
+	}
+
+	if opts.Registry == "" {
+		return [][]string{append([]string{"up"}, variables...)}
+	}
+	ref := spinImageReference(opts.Registry, manifest)
+	return [][]string{
+		{"registry", "push", ref},
+		append([]string{"up", "--from", ref}, variables...),
+	}
+}
+
+// unsetSpinSecrets returns the secrets the components read that are given
+// neither with --var nor through a SPIN_VARIABLE_* environment variable.
+// Without the platform, nothing else provides them.
+func unsetSpinSecrets(manifest *validation.Application, opts *DeployOptions) []string {
+	var unset []string
+	for _, name := range referencedSecrets(manifest) {
+		if _, ok := opts.Variables[name]; ok {
+			continue
+		}
+		if _, ok := manifest.Variables[name]; ok {
+			continue
+		}
+		if _, ok := os.LookupEnv("SPIN_VARIABLE_" + strings.ToUpper(name)); ok {
+			continue
+		}
+		unset = append(unset, name)
+	}
+	return unset
+}
+
+// runSpinTarget deploys an app that has been synthesized and built with
+// spin, without the FTL platform
+func runSpinTarget(ctx context.Context, manifest *validation.Application, opts *DeployOptions) error {
+	if manifest.Access == "org" {
+		Warn("Org access control relies on the FTL platform for membership; consider --access-control custom")
+	}
+	if len(manifest.Regions) > 0 {
+		Warn("Regions are ignored with --target %s", opts.Target)
+	}
+	if opts.Target == TargetSpin {
+		if unset := unsetSpinSecrets(manifest, opts); len(unset) > 0 {
+			Warn("Secrets not provided: %s", strings.Join(unset, ", "))
+			Info("Export them as SPIN_VARIABLE_<NAME>")
+		}
+	}
+
+	for _, args := range spinTargetCommands(manifest, opts) {
+		Info("Running 'spin %s'", strings.Join(redactSpinVariables(args), " "))
+		spinCtx, span := tracing.Start(ctx, "deploy.spin",
+			attribute.String("ftl.target", opts.Target),
+			attribute.String("spin.command", args[0]),
+		)
+		cmd := ExecCommand("spin", args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = messageOutput()
+		cmd.Stderr = os.Stderr
+		if env := tracing.Environ(spinCtx); len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		err := cmd.Run()
+		tracing.End(span, err)
+		if err != nil {
+			return fmt.Errorf("'spin %s' failed: %w", args[0], err)
+		}
+		if args[0] == "registry" {
+			Success("Pushed %s", args[len(args)-1])
+		}
+	}
+
+	if opts.Target == TargetFermyonCloud {
+		Success("Deployed %s to Fermyon Cloud", manifest.Name)
+	}
+	return nil
+}
+
+// redactSpinVariables hides the values of --variable arguments, which may
+// be secrets, from progress messages
+func redactSpinVariables(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i := 1; i < len(redacted); i++ {
+		if redacted[i-1] == "--variable" {
+			if name, _, ok := strings.Cut(redacted[i], "="); ok {
+				redacted[i] = name + "=***"
+			}
+		}
+	}
+	return redacted
+}
+
+// displaySpinTargetDryRun shows what a deployment to a target other than
+// the FTL platform would do
+func displaySpinTargetDryRun(manifest *validation.Application, opts *DeployOptions) {
+	fmt.Println()
+	fmt.Println("🔍 DRY RUN MODE - No changes will be made")
+	fmt.Println()
+
+	color.Cyan("Application Configuration:")
+	fmt.Printf("  Name: %s\n", manifest.Name)
+	fmt.Printf("  Version: %s\n", manifest.Version)
+	fmt.Printf("  Access Control: %s\n", manifest.Access)
+	fmt.Printf("  Target: %s\n", opts.Target)
+
+	fmt.Println()
+	color.Cyan("Actions that would be performed:")
+	fmt.Printf("  ✓ Synthesize spin.toml from configuration\n")
+	fmt.Printf("  ✓ Build local components with 'spin build'\n")
+	for _, args := range spinTargetCommands(manifest, opts) {
+		fmt.Printf("  ✓ Run 'spin %s'\n", strings.Join(redactSpinVariables(args), " "))
+	}
+
+	fmt.Println()
+	fmt.Println("To perform the actual deployment, run without --dry-run")
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/validation"
+)
+
+func TestValidateTarget(t *testing.T) {
+	valid := []*DeployOptions{
+		{},
+		{Target: TargetFTL, Regions: []string{"us-east-1"}},
+		{Target: TargetSpin, Registry: "ghcr.io/acme"},
+		{Target: TargetFermyonCloud},
+	}
+	for _, opts := range valid {
+		assert.NoError(t, validateTarget(opts), opts.Target)
+	}
+
+	invalid := []*DeployOptions{
+		{Target: "kubernetes"},
+		{Target: TargetFTL, Registry: "ghcr.io/acme"},
+		{Target: TargetFermyonCloud, Registry: "ghcr.io/acme"},
+		{Target: TargetSpin, AllowedRoles: []string{"admin"}},
+		{Target: TargetSpin, Regions: []string{"us-east-1"}},
+		{Target: TargetFermyonCloud, OrgID: "org_1"},
+		{Target: TargetSpin, NoWait: true},
+	}
+	for _, opts := range invalid {
+		var usage *usageError
+		assert.ErrorAs(t, validateTarget(opts), &usage, opts.Target)
+	}
+}
+
+func TestValidateTargetComponents(t *testing.T) {
+	manifest := &validation.Application{
+		Name: "my-app",
+		Components: []*validation.Component{
+			{ID: "weather", Source: &validation.LocalSource{Path: "weather.wasm"}},
+			{ID: "docs", Type: validation.ComponentTypeStatic, Dir: "./docs"},
+		},
+	}
+
+	err := validateTargetComponents(manifest, &DeployOptions{Target: TargetFTL})
+	var usage *usageError
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "static components cannot be deployed to the FTL platform: docs")
+
+	assert.NoError(t, validateTargetComponents(manifest, &DeployOptions{Target: TargetSpin}))
+	assert.NoError(t, validateTargetComponents(&validation.Application{Name: "my-app"}, &DeployOptions{}))
+}
+
+func TestSpinTargetCommands(t *testing.T) {
+	manifest := &validation.Application{
+		Name:      "my-app",
+		Version:   "1.2.0",
+		Variables: map[string]string{"region": "eu", "api_key": "placeholder"},
+	}
+	vars := map[string]string{"api_key": "s3cret"}
+
+	commands := spinTargetCommands(manifest, &DeployOptions{Target: TargetSpin, Variables: vars})
+	assert.Equal(t, [][]string{
+		{"up", "--variable", "api_key=s3cret", "--variable", "region=eu"},
+	}, commands)
+
+	commands = spinTargetCommands(manifest, &DeployOptions{Target: TargetSpin, Registry: "ghcr.io/acme/", Variables: vars})
+	assert.Equal(t, [][]string{
+		{"registry", "push", "ghcr.io/acme/my-app:1.2.0"},
+		{"up", "--from", "ghcr.io/acme/my-app:1.2.0", "--variable", "api_key=s3cret", "--variable", "region=eu"},
+	}, commands)
+
+	commands = spinTargetCommands(&validation.Application{Name: "my-app"}, &DeployOptions{Target: TargetFermyonCloud})
+	assert.Equal(t, [][]string{{"deploy"}}, commands)
+
+	assert.Equal(t, []string{"up", "--variable", "api_key=***"}, redactSpinVariables([]string{"up", "--variable", "api_key=s3cret"}))
+}
+
+func TestUnsetSpinSecrets(t *testing.T) {
+	manifest := &validation.Application{
+		Name: "my-app",
+		Components: []*validation.Component{
+			{ID: "a", Secrets: []string{"api_key", "db_password", "token"}},
+		},
+	}
+	t.Setenv("SPIN_VARIABLE_TOKEN", "t")
+
+	unset := unsetSpinSecrets(manifest, &DeployOptions{Variables: map[string]string{"api_key": "k"}})
+	assert.Equal(t, []string{"db_password"}, unset)
+}
+
+func TestRunSpinTarget(t *testing.T) {
+	oldExecCommand := ExecCommand
+	defer func() { ExecCommand = oldExecCommand }()
+
+	var ran []string
+	ExecCommand = func(name string, args ...string) *exec.Cmd {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		return MockExecCommandHelper(name, args...)
+	}
+
+	manifest := &validation.Application{Name: "my-app", Version: "0.2.0", Access: "public"}
+	opts := &DeployOptions{Target: TargetSpin, Registry: "ghcr.io/acme", Variables: map[string]string{"mode": "prod"}}
+	require.NoError(t, runSpinTarget(context.Background(), manifest, opts))
+
+	assert.Equal(t, []string{
+		"spin registry push ghcr.io/acme/my-app:0.2.0",
+		"spin up --from ghcr.io/acme/my-app:0.2.0 --variable mode=prod",
+	}, ran)
+}
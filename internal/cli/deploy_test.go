@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http/httptest"
@@ -409,6 +410,30 @@ func TestCreateDeploymentRequest(t *testing.T) {
 	assert.Equal(t, "deploy_value", variables["DEPLOY_VAR"])
 }
 
+func TestCreateDeploymentRequestSecrets(t *testing.T) {
+	manifest := &validation.Application{
+		Name: "test-app",
+		Components: []*validation.Component{
+			{
+				ID:      "billing",
+				Source:  &validation.RegistrySource{Registry: "test.registry.com", Package: "test:billing", Version: "1.0.0"},
+				Secrets: []string{"stripe_key", "db_password"},
+			},
+			{
+				ID:      "reports",
+				Source:  &validation.RegistrySource{Registry: "test.registry.com", Package: "test:reports", Version: "1.0.0"},
+				Secrets: []string{"db_password"},
+			},
+		},
+	}
+
+	req := createDeploymentRequest(manifest, &DeployOptions{})
+	components := req["components"].([]map[string]interface{})
+	assert.Equal(t, []string{"stripe_key", "db_password"}, components[0]["secrets"])
+
+	assert.Equal(t, []string{"db_password", "stripe_key"}, referencedSecrets(manifest))
+}
+
 func TestDisplayDryRunSummary(t *testing.T) {
 	manifest := &validation.Application{
 		Name:        "test-app",
@@ -681,3 +706,75 @@ func TestWASMComponentDiscovery(t *testing.T) {
 		})
 	}
 }
+
+func TestRunDeploy_JWTOverridesChecked(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "json")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: test-app\ncomponents: []\n"), 0600))
+
+	err := runDeploy(context.Background(), &DeployOptions{
+		ConfigFile: "ftl.yaml",
+		Prebuilt:   true,
+		DryRun:     true,
+		JWTIssuer:  "https://auth.example.com",
+	})
+	var usage *usageError
+	require.ErrorAs(t, err, &usage)
+	assert.ErrorContains(t, err, "auth: jwt_audience is required when jwt_issuer is set")
+
+	assert.NoError(t, runDeploy(context.Background(), &DeployOptions{
+		ConfigFile:  "ftl.yaml",
+		Prebuilt:    true,
+		DryRun:      true,
+		JWTIssuer:   "https://auth.example.com",
+		JWTAudience: "api",
+	}))
+}
+
+func TestRunDeploy_Platform(t *testing.T) {
+	platform := usePlatform(t)
+	chdirTemp(t)
+	out := setGlobalOutput(t, "json")
+	require.NoError(t, os.WriteFile("geo.wasm", []byte("\x00asm\x01\x00\x00\x00"), 0600))
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(`name: demo
+version: 1.2.0
+components:
+  - id: geo
+    source: ./geo.wasm
+    variables:
+      units: metric
+`), 0600))
+
+	require.NoError(t, runDeploy(context.Background(), &DeployOptions{
+		ConfigFile: "ftl.yaml",
+		Prebuilt:   true,
+		Yes:        true,
+	}))
+
+	apps := platform.Apps()
+	require.Len(t, apps, 1)
+	assert.Equal(t, "demo", apps[0].AppName)
+
+	deployments := platform.Deployments()
+	require.Len(t, deployments, 1)
+	assert.Equal(t, apps[0].AppId.String(), deployments[0].AppID)
+	assert.Equal(t, "1.2.0", deployments[0].Request["version"])
+	components, _ := deployments[0].Request["components"].([]interface{})
+	require.Len(t, components, 1)
+	assert.Equal(t, map[string]interface{}{"units": "metric"}, components[0].(map[string]interface{})["variables"])
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, "deployed", result["status"])
+	assert.Equal(t, apps[0].AppId.String(), result["app_id"])
+	assert.Equal(t, deployments[0].ID, result["deployment_id"])
+
+	// Deploying again updates the existing app
+	require.NoError(t, runDeploy(context.Background(), &DeployOptions{
+		ConfigFile: "ftl.yaml",
+		Prebuilt:   true,
+		Yes:        true,
+	}))
+	assert.Len(t, platform.Apps(), 1)
+	assert.Len(t, platform.Deployments(), 2)
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/validation"
+)
+
+// DiffOptions holds options for the diff command
+type DiffOptions struct {
+	DeployOptions
+
+	// ExitCode fails the command when the configuration differs from the
+	// deployed app
+	ExitCode bool
+}
+
+func newDiffCmd() *cobra.Command {
+	opts := &DiffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff [flags]",
+		Short: "Compare the local configuration with the deployed app",
+		Long: `Compare the local configuration with the app's current deployment on the
+FTL platform, showing what 'ftl deploy' would change: components added or
+removed, the access mode and auth settings. The platform does not report
+deployed variables or versions, so they are not compared.
+
+Flags overriding the configuration take the same values as for 'ftl deploy'.
+
+Example:
+  ftl diff
+  ftl diff -f staging.yaml --access-control private
+  ftl diff --exit-code            # fail when deploying would change the app
+  ftl diff --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ConfigFile, "file", "f", "", "FTL configuration file (auto-detects if not specified)")
+	cmd.Flags().StringVar(&opts.AccessControl, "access-control", "", "Access control mode (public, private, org, custom)")
+	cmd.Flags().StringVar(&opts.JWTIssuer, "jwt-issuer", "", "JWT issuer URL for authentication")
+	cmd.Flags().StringVar(&opts.JWTAudience, "jwt-audience", "", "JWT audience for authentication")
+	cmd.Flags().StringSliceVar(&opts.AllowedRoles, "allowed-roles", nil, "Allowed roles for org mode")
+	cmd.Flags().BoolVar(&opts.ExitCode, "exit-code", false, "Exit with status 1 when the configuration differs from the deployed app")
+	_ = cmd.RegisterFlagCompletionFunc("file", completeConfigFiles)
+	_ = cmd.RegisterFlagCompletionFunc("access-control", completeFixed("public", "private", "org", "custom"))
+
+	return cmd
+}
+
+// Allow overriding for tests
+var runDiff = runDiffImpl
+
+func runDiffImpl(ctx context.Context, opts *DiffOptions) error {
+	if opts.ConfigFile == "" {
+		file, err := detectDeployConfig()
+		if err != nil {
+			return err
+		}
+		opts.ConfigFile = file
+	}
+	manifest, err := loadDeployManifest(opts.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	applyDeployOverrides(manifest, &opts.DeployOptions)
+	if err := validation.Check(manifest); err != nil {
+		return &usageError{fmt.Errorf("invalid configuration: %w", err)}
+	}
+
+	authManager, err := newAuthManager()
+	if err != nil {
+		return err
+	}
+	if _, err := authManager.GetToken(ctx); err != nil {
+		return fmt.Errorf("not logged in to FTL. Run 'ftl auth login' first")
+	}
+	apiClient, err := newAPIClient(authManager)
+	if err != nil {
+		return err
+	}
+
+	apps, err := apiClient.ListApps(ctx, &api.ListAppsParams{Name: &manifest.Name})
+	if err != nil {
+		return fmt.Errorf("failed to check existing apps: %w", err)
+	}
+
+	// An app that was never deployed is compared with an empty deployment
+	var appID, existingAccess string
+	deployed := &DeployedConfig{}
+	if len(apps.Apps) > 0 {
+		existing := apps.Apps[0]
+		appID = existing.AppId.String()
+		if existing.AccessControl != nil {
+			existingAccess = string(*existing.AccessControl)
+		}
+		deployed, err = fetchDeployedConfig(ctx, apiClient, appID)
+		if err != nil {
+			return fmt.Errorf("failed to get the deployed app: %w", err)
+		}
+		if existing.CustomAuth != nil {
+			deployed.JWTIssuer = existing.CustomAuth.Issuer
+			deployed.JWTAudience = existing.CustomAuth.Audience
+		}
+		if existing.AllowedRoles != nil {
+			deployed.AllowedRoles = *existing.AllowedRoles
+		}
+	}
+
+	changes := calculateChanges(manifest, &opts.DeployOptions, existingAccess)
+	diffDeployedConfig(changes, manifest, &opts.DeployOptions, deployed)
+	// Every deployment pushes the components again, which changes nothing
+	changes.ComponentsUpdated = nil
+	result := newDiffResult(manifest.Name, appID, changes)
+
+	if structuredFormat() != "" {
+		if err := writeResult(result); err != nil {
+			return err
+		}
+	} else {
+		if appID == "" {
+			Info("%s is not deployed yet; 'ftl deploy' creates it", manifest.Name)
+		} else {
+			Info("Comparing %s with the deployed app %s (%s)", opts.ConfigFile, manifest.Name, appID)
+		}
+		showDeploymentChanges(changes)
+	}
+
+	if opts.ExitCode && result.Changed {
+		err := fmt.Errorf("%s differs from the deployed app", opts.ConfigFile)
+		if structuredFormat() != "" {
+			return &reportedError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// diffResult is the result document of 'ftl diff'
+type diffResult struct {
+	App               string               `json:"app"`
+	AppID             string               `json:"app_id,omitempty"`
+	Deployed          bool                 `json:"deployed"`
+	Changed           bool                 `json:"changed"`
+	Access            *diffValue           `json:"access,omitempty"`
+	ComponentsAdded   []string             `json:"components_added,omitempty"`
+	ComponentsRemoved []string             `json:"components_removed,omitempty"`
+	Auth              map[string]diffValue `json:"auth,omitempty"`
+}
+
+// diffValue is a changed setting
+type diffValue struct {
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// authResultKeys names the auth settings in diff results
+var authResultKeys = map[string]string{
+	"JWT issuer":    "jwt_issuer",
+	"JWT audience":  "jwt_audience",
+	"Allowed roles": "allowed_roles",
+}
+
+func newDiffResult(app, appID string, changes *DeploymentChanges) *diffResult {
+	result := &diffResult{
+		App:               app,
+		AppID:             appID,
+		Deployed:          appID != "",
+		ComponentsAdded:   changes.ComponentsAdded,
+		ComponentsRemoved: changes.ComponentsRemoved,
+		Auth:              make(map[string]diffValue),
+	}
+	if changes.AccessModeChanged {
+		result.Access = &diffValue{Change: "changed", Old: changes.OldAccessMode, New: changes.NewAccessMode}
+	}
+	for key, change := range changes.AuthChanged {
+		result.Auth[authResultKeys[key]] = diffValue{Change: "changed", Old: change.Old, New: change.New}
+	}
+
+	result.Changed = result.Access != nil ||
+		len(result.ComponentsAdded)+len(result.ComponentsRemoved)+len(result.Auth) > 0
+	return result
+}
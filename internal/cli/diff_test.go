@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/api"
+)
+
+func TestRunDiff_Platform(t *testing.T) {
+	platform := usePlatform(t)
+	chdirTemp(t)
+	out := setGlobalOutput(t, "json")
+
+	appID := platform.AddApp("demo", api.AppAccessControlPublic).AppId.String()
+	_, err := platform.CreateDeployCredentials(context.Background(), appID, []string{"geo", "legacy"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(`name: demo
+access: private
+components:
+  - id: geo
+    source:
+      registry: ghcr.io
+      package: acme:geo
+      version: 1.0.0
+  - id: news
+    source:
+      registry: ghcr.io
+      package: acme:news
+      version: 1.0.0
+`), 0600))
+
+	err = runDiff(context.Background(), &DiffOptions{
+		DeployOptions: DeployOptions{ConfigFile: "ftl.yaml"},
+		ExitCode:      true,
+	})
+	var reported *reportedError
+	require.ErrorAs(t, err, &reported)
+
+	var result diffResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, appID, result.AppID)
+	assert.True(t, result.Changed)
+	assert.Equal(t, &diffValue{Change: "changed", Old: "public", New: "private"}, result.Access)
+	assert.Equal(t, []string{"news"}, result.ComponentsAdded)
+	assert.Equal(t, []string{"legacy"}, result.ComponentsRemoved)
+}
+
+func TestNewDiffResult(t *testing.T) {
+	changes := &DeploymentChanges{
+		AccessModeChanged: true,
+		OldAccessMode:     "private",
+		NewAccessMode:     "public",
+		ComponentsAdded:   []string{"news"},
+		ComponentsUpdated: []string{"weather"},
+		AuthChanged:       map[string]VariableChange{"JWT issuer": {New: "https://auth.example.com"}},
+	}
+
+	result := newDiffResult("test-app", "123e4567-e89b-12d3-a456-426614174000", changes)
+	assert.True(t, result.Deployed)
+	assert.True(t, result.Changed)
+	assert.Equal(t, &diffValue{Change: "changed", Old: "private", New: "public"}, result.Access)
+	assert.Equal(t, []string{"news"}, result.ComponentsAdded)
+	assert.Equal(t, map[string]diffValue{
+		"jwt_issuer": {Change: "changed", New: "https://auth.example.com"},
+	}, result.Auth)
+
+	unchanged := newDiffResult("test-app", "", &DeploymentChanges{})
+	assert.False(t, unchanged.Deployed)
+	assert.False(t, unchanged.Changed)
+
+	// Pushing the components again is not a difference
+	pushed := newDiffResult("test-app", "123e4567-e89b-12d3-a456-426614174000", &DeploymentChanges{ComponentsUpdated: []string{"weather"}})
+	assert.False(t, pushed.Changed)
+}
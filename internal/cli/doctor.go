@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/auth"
+)
+
+// Check outcomes reported by ftl doctor
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// rustWASMTarget is the Rust target used by FTL component templates
+const rustWASMTarget = "wasm32-wasip2"
+
+var (
+	// probeCommand runs a tool and returns its output; overridden in tests
+	probeCommand = func(name string, args ...string) (string, error) {
+		out, err := ExecCommand(name, args...).Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	// probeCredentialStore checks the OS keyring; overridden in tests
+	probeCredentialStore = auth.CheckKeyring
+
+	// doctorRegistryURL is probed to check connectivity to the registry
+	// hosting the gateway and other published components
+	doctorRegistryURL = "https://ghcr.io/v2/"
+)
+
+// doctorCheck is the outcome of a single diagnostic
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// doctorReport is the machine-readable form of 'ftl doctor'
+type doctorReport struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []doctorCheck `json:"checks"`
+}
+
+// doctorToolchain is a language toolchain used to build components
+type doctorToolchain struct {
+	command string
+	args    []string
+	purpose string
+	fix     string
+}
+
+var doctorToolchains = []doctorToolchain{
+	{"cargo", []string{"--version"}, "Rust components", "Install Rust from https://rustup.rs"},
+	{"tinygo", []string{"version"}, "Go components", "Install TinyGo from https://tinygo.org/getting-started/install/"},
+	{"npm", []string{"--version"}, "TypeScript and JavaScript components", "Install Node.js from https://nodejs.org"},
+	{"componentize-py", []string{"--version"}, "Python components", "Run 'pip install componentize-py'"},
+}
+
+// DoctorOptions holds options for the doctor command
+type DoctorOptions struct {
+	Offline bool
+}
+
+func newDoctorCmd() *cobra.Command {
+	opts := &DoctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check your environment for problems",
+		Long: `Check that everything needed to build and deploy FTL applications is
+installed and reachable, and print how to fix anything that is not.
+
+Checks:
+  - Spin is installed
+  - the toolchains used by your components (cargo, tinygo, npm, componentize-py)
+  - the ` + rustWASMTarget + ` Rust target
+  - connectivity to the component registry
+  - the OS credential store used by 'ftl auth login'
+  - the FTL configuration in the current directory, if any
+
+Toolchains your project's components build with are required; others are
+reported as warnings. The command exits non-zero when a check fails.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "Skip checks that need network access")
+
+	return cmd
+}
+
+func runDoctor(ctx context.Context, opts *DoctorOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	configCheck, needed := checkDoctorConfig()
+
+	checks := []doctorCheck{checkSpin()}
+	checks = append(checks, checkToolchains(needed)...)
+	checks = append(checks, checkRustTarget(needed["cargo"]))
+	if opts.Offline {
+		checks = append(checks, doctorCheck{Name: "registry", Status: doctorSkip, Detail: "skipped with --offline"})
+	} else {
+		checks = append(checks, checkRegistry(ctx))
+	}
+	checks = append(checks, checkCredentialStore(), configCheck)
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == doctorFail {
+			failed++
+		}
+	}
+	report := doctorReport{Healthy: failed == 0, Checks: checks}
+
+	if structuredFormat() != "" {
+		if err := writeResult(report); err != nil {
+			return err
+		}
+	} else {
+		displayDoctorReport(report)
+	}
+
+	if failed > 0 {
+		err := fmt.Errorf("%d doctor check(s) failed", failed)
+		if structuredFormat() != "" {
+			return &reportedError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+func checkSpin() doctorCheck {
+	out, err := probeCommand("spin", "--version")
+	if err != nil {
+		return doctorCheck{
+			Name:   "spin",
+			Status: doctorFail,
+			Detail: "spin not found",
+			Fix:    "Install Spin from https://spinframework.dev/install and make sure it is on your PATH",
+		}
+	}
+	return doctorCheck{Name: "spin", Status: doctorOK, Detail: firstLine(out)}
+}
+
+// checkToolchains checks every known toolchain. Missing toolchains are
+// failures when the project needs them and warnings otherwise.
+func checkToolchains(needed map[string]bool) []doctorCheck {
+	checks := make([]doctorCheck, 0, len(doctorToolchains))
+	for _, tool := range doctorToolchains {
+		out, err := probeCommand(tool.command, tool.args...)
+		if err == nil {
+			checks = append(checks, doctorCheck{Name: tool.command, Status: doctorOK, Detail: firstLine(out)})
+			continue
+		}
+
+		check := doctorCheck{
+			Name:   tool.command,
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("%s not found (needed for %s)", tool.command, tool.purpose),
+			Fix:    tool.fix,
+		}
+		if needed[tool.command] {
+			check.Status = doctorFail
+			check.Detail = fmt.Sprintf("%s not found, but this project's components build with it", tool.command)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func checkRustTarget(required bool) doctorCheck {
+	check := doctorCheck{Name: rustWASMTarget, Status: doctorWarn}
+	if required {
+		check.Status = doctorFail
+	}
+
+	out, err := probeCommand("rustup", "target", "list", "--installed")
+	if err != nil {
+		if !required {
+			return doctorCheck{Name: rustWASMTarget, Status: doctorSkip, Detail: "rustup not found"}
+		}
+		check.Detail = "rustup not found, cannot check for the " + rustWASMTarget + " target"
+		check.Fix = "Install Rust with rustup from https://rustup.rs"
+		return check
+	}
+
+	for _, target := range strings.Fields(out) {
+		if target == rustWASMTarget {
+			return doctorCheck{Name: rustWASMTarget, Status: doctorOK, Detail: "installed"}
+		}
+	}
+	check.Detail = rustWASMTarget + " target not installed"
+	check.Fix = "Run 'rustup target add " + rustWASMTarget + "'"
+	return check
+}
+
+func checkRegistry(ctx context.Context) doctorCheck {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	check := doctorCheck{Name: "registry", Status: doctorOK, Detail: doctorRegistryURL + " reachable"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doctorRegistryURL, nil)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("cannot reach %s: %v", doctorRegistryURL, err)
+		check.Fix = "Check your network connection and proxy settings (HTTPS_PROXY). For air-gapped environments use 'ftl bundle'"
+		return check
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The registry answers anonymous requests with 401, which still proves
+	// it is reachable
+	if resp.StatusCode >= http.StatusInternalServerError {
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("%s returned status %d", doctorRegistryURL, resp.StatusCode)
+		check.Fix = "The registry may be having an outage; try again later"
+	}
+	return check
+}
+
+func checkCredentialStore() doctorCheck {
+	if err := probeCredentialStore(); err != nil {
+		fix := "Set FTL_CLIENT_ID and FTL_CLIENT_SECRET to use machine credentials instead"
+		switch runtime.GOOS {
+		case "linux":
+			fix = "Install and unlock a Secret Service provider such as gnome-keyring, or set FTL_CLIENT_ID and FTL_CLIENT_SECRET to use machine credentials"
+		case "darwin":
+			fix = "Unlock your login keychain, or set FTL_CLIENT_ID and FTL_CLIENT_SECRET to use machine credentials"
+		}
+		return doctorCheck{
+			Name:   "credential store",
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("OS keyring unavailable: %v", err),
+			Fix:    fix,
+		}
+	}
+	return doctorCheck{Name: "credential store", Status: doctorOK, Detail: "OS keyring available"}
+}
+
+// checkDoctorConfig validates the FTL configuration in the current directory
+// and returns the toolchains its components build with
+func checkDoctorConfig() (doctorCheck, map[string]bool) {
+	needed := make(map[string]bool)
+
+	configFile := ""
+	for _, file := range []string{"ftl.yaml", "ftl.yml", "ftl.json", "app.cue"} {
+		if _, err := os.Stat(file); err == nil {
+			configFile = file
+			break
+		}
+	}
+	if configFile == "" {
+		return doctorCheck{
+			Name:   "config",
+			Status: doctorSkip,
+			Detail: "no FTL configuration in the current directory",
+			Fix:    "Run 'ftl init' to create a project",
+		}, needed
+	}
+
+	check := doctorCheck{Name: "config", Status: doctorOK, Detail: configFile + " is valid"}
+	invalid := func(err error) doctorCheck {
+		return doctorCheck{
+			Name:   "config",
+			Status: doctorFail,
+			Detail: fmt.Sprintf("%s is invalid: %v", configFile, err),
+			Fix:    fmt.Sprintf("Run 'ftl synth %s' to see the full error", configFile),
+		}
+	}
+
+	if filepath.Ext(configFile) == ".cue" {
+		input, err := os.ReadFile(configFile)
+		if err != nil {
+			return invalid(err), needed
+		}
+		if _, err := synthesizeFromInput(input, []string{configFile}); err != nil {
+			return invalid(err), needed
+		}
+		return check, needed
+	}
+
+	manifest, err := loadDeployManifest(configFile)
+	if err != nil {
+		return invalid(err), needed
+	}
+	for _, comp := range manifest.Components {
+		if comp.Build == nil {
+			continue
+		}
+		if fields := strings.Fields(comp.Build.Command); len(fields) > 0 {
+			needed[fields[0]] = true
+		}
+	}
+	return check, needed
+}
+
+func displayDoctorReport(report doctorReport) {
+	for _, check := range report.Checks {
+		var mark string
+		switch check.Status {
+		case doctorOK:
+			mark = successColor.Sprint("✓")
+		case doctorWarn:
+			mark = warnColor.Sprint("⚠")
+		case doctorFail:
+			mark = errorColor.Sprint("✗")
+		default:
+			mark = "-"
+		}
+		_, _ = fmt.Fprintf(colorOutput, "%s %-17s %s\n", mark, check.Name, check.Detail)
+		if check.Fix != "" && check.Status != doctorOK {
+			_, _ = fmt.Fprintf(colorOutput, "  %s %s\n", infoColor.Sprint("→"), check.Fix)
+		}
+	}
+
+	_, _ = fmt.Fprintln(colorOutput)
+	if report.Healthy {
+		_, _ = fmt.Fprintln(colorOutput, successColor.Sprint("No problems found"))
+	}
+}
+
+// firstLine returns the first line of a tool's output
+func firstLine(out string) string {
+	line, _, _ := strings.Cut(out, "\n")
+	return line
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDoctorProbes replaces external tools with canned outputs. Tools
+// missing from the map are reported as not installed.
+func stubDoctorProbes(t *testing.T, tools map[string]string) {
+	t.Helper()
+	oldProbe, oldStore, oldURL := probeCommand, probeCredentialStore, doctorRegistryURL
+	t.Cleanup(func() {
+		probeCommand, probeCredentialStore, doctorRegistryURL = oldProbe, oldStore, oldURL
+	})
+
+	probeCommand = func(name string, args ...string) (string, error) {
+		out, ok := tools[name]
+		if !ok {
+			return "", errors.New("executable file not found in $PATH")
+		}
+		return out, nil
+	}
+	probeCredentialStore = func() error { return nil }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	doctorRegistryURL = server.URL
+}
+
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+	require.NoError(t, os.Chdir(t.TempDir()))
+}
+
+func findCheck(t *testing.T, checks []doctorCheck, name string) doctorCheck {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %s check", name)
+	return doctorCheck{}
+}
+
+func runDoctorJSON(t *testing.T, opts *DoctorOptions) (doctorReport, error) {
+	t.Helper()
+	setGlobalOutput(t, "json")
+
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	err := runDoctor(context.Background(), opts)
+
+	var report doctorReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	return report, err
+}
+
+func TestDoctor_AllGood(t *testing.T) {
+	chdirTemp(t)
+	stubDoctorProbes(t, map[string]string{
+		"spin":            "spin 3.1.0 (abc 2025-01-01)\nextra",
+		"cargo":           "cargo 1.85.0",
+		"tinygo":          "tinygo version 0.35.0",
+		"npm":             "10.2.0",
+		"componentize-py": "componentize-py 0.16.0",
+		"rustup":          "wasm32-wasip2\nx86_64-unknown-linux-gnu",
+	})
+
+	report, err := runDoctorJSON(t, &DoctorOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.Healthy)
+
+	assert.Equal(t, doctorCheck{Name: "spin", Status: doctorOK, Detail: "spin 3.1.0 (abc 2025-01-01)"}, findCheck(t, report.Checks, "spin"))
+	assert.Equal(t, doctorOK, findCheck(t, report.Checks, rustWASMTarget).Status)
+	assert.Equal(t, doctorOK, findCheck(t, report.Checks, "registry").Status)
+	assert.Equal(t, doctorOK, findCheck(t, report.Checks, "credential store").Status)
+	assert.Equal(t, doctorSkip, findCheck(t, report.Checks, "config").Status)
+}
+
+func TestDoctor_MissingToolsForProject(t *testing.T) {
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(`name: demo
+version: 0.1.0
+components:
+  - id: echo
+    source: ./echo
+    build:
+      command: cargo build --target wasm32-wasip2 --release
+`), 0600))
+	stubDoctorProbes(t, map[string]string{
+		"spin":   "spin 3.1.0",
+		"rustup": "x86_64-unknown-linux-gnu",
+	})
+
+	report, err := runDoctorJSON(t, &DoctorOptions{Offline: true})
+	require.Error(t, err)
+	var reported *reportedError
+	assert.ErrorAs(t, err, &reported, "the report already describes the failure")
+	assert.False(t, report.Healthy)
+
+	// cargo is required by the project, tinygo is not
+	assert.Equal(t, doctorFail, findCheck(t, report.Checks, "cargo").Status)
+	assert.Equal(t, doctorWarn, findCheck(t, report.Checks, "tinygo").Status)
+
+	target := findCheck(t, report.Checks, rustWASMTarget)
+	assert.Equal(t, doctorFail, target.Status)
+	assert.Equal(t, "Run 'rustup target add wasm32-wasip2'", target.Fix)
+
+	assert.Equal(t, doctorSkip, findCheck(t, report.Checks, "registry").Status)
+	assert.Equal(t, doctorOK, findCheck(t, report.Checks, "config").Status)
+}
+
+func TestDoctor_InvalidConfig(t *testing.T) {
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: [unclosed"), 0600))
+	stubDoctorProbes(t, map[string]string{"spin": "spin 3.1.0"})
+
+	report, err := runDoctorJSON(t, &DoctorOptions{Offline: true})
+	require.Error(t, err)
+
+	check := findCheck(t, report.Checks, "config")
+	assert.Equal(t, doctorFail, check.Status)
+	assert.Contains(t, check.Detail, "ftl.yaml is invalid")
+}
+
+func TestDoctor_UnreachableRegistryAndKeyring(t *testing.T) {
+	chdirTemp(t)
+	stubDoctorProbes(t, map[string]string{"spin": "spin 3.1.0"})
+	probeCredentialStore = func() error { return errors.New("no secret service") }
+	doctorRegistryURL = "http://127.0.0.1:1/v2/"
+
+	report, err := runDoctorJSON(t, &DoctorOptions{})
+	require.Error(t, err)
+
+	registry := findCheck(t, report.Checks, "registry")
+	assert.Equal(t, doctorFail, registry.Status)
+	assert.Contains(t, registry.Fix, "ftl bundle")
+
+	store := findCheck(t, report.Checks, "credential store")
+	assert.Equal(t, doctorWarn, store.Status)
+	assert.Contains(t, store.Fix, "FTL_CLIENT_ID")
+}
+
+func TestDoctor_HumanOutput(t *testing.T) {
+	chdirTemp(t)
+	stubDoctorProbes(t, map[string]string{})
+	color.NoColor = true
+	defer func() { color.NoColor = false }()
+
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	err := runDoctor(context.Background(), &DoctorOptions{Offline: true})
+	assert.EqualError(t, err, "1 doctor check(s) failed")
+
+	out := buf.String()
+	assert.Contains(t, out, "spin not found")
+	assert.Contains(t, out, "→ Install Spin from")
+	assert.False(t, strings.Contains(out, "No problems found"))
+}
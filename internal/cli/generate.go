@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/generate"
+	"github.com/fastertools/ftl/validation"
+)
+
+// GenerateVarsOptions holds options for the generate vars command
+type GenerateVarsOptions struct {
+	ConfigFile string
+	Prune      bool
+	DryRun     bool
+}
+
+// generateVarsResult is the machine-readable form of 'ftl generate vars'
+type generateVarsResult struct {
+	File       string                         `json:"file"`
+	Written    bool                           `json:"written"`
+	Components map[string][]generate.Variable `json:"components"`
+	Changes    *generate.Changes              `json:"changes"`
+}
+
+func newGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate configuration and clients for an app",
+	}
+
+	cmd.AddCommand(newGenerateVarsCmd())
+	cmd.AddCommand(newGenerateClientCmd())
+
+	return cmd
+}
+
+func newGenerateVarsCmd() *cobra.Command {
+	opts := &GenerateVarsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "vars",
+		Short: "Sync component variables with ftlvar struct tags",
+		Long: `Scan Go components for struct fields tagged with ftlvar and add the
+variables they declare to each component's variables in ftl.yaml or
+ftl.json, so the app config always provides what the code expects.
+
+  type Config struct {
+      ftl.Config
+      APIKey  string ` + "`ftlvar:\"api_key,required,secret\"`" + `
+      BaseURL string ` + "`ftlvar:\",default=https://api.example.com\"`" + `
+  }
+
+New variables are set to their default, or to an empty value marked as
+required. Existing values are never changed. Variables that are no longer
+declared are reported, and removed with --prune. Run 'ftl synth' afterwards
+to regenerate spin.toml.
+
+Example:
+  ftl generate vars
+  ftl generate vars --dry-run
+  ftl generate vars -f ftl.yaml --prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerateVars(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ConfigFile, "file", "f", "", "FTL configuration file (ftl.yaml or ftl.json)")
+	cmd.Flags().BoolVar(&opts.Prune, "prune", false, "Remove variables that are no longer declared")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the updated configuration instead of writing it")
+	_ = cmd.RegisterFlagCompletionFunc("file", completeConfigFiles)
+
+	return cmd
+}
+
+func runGenerateVars(opts *GenerateVarsOptions) error {
+	configFile := opts.ConfigFile
+	if configFile == "" {
+		for _, file := range []string{"ftl.yaml", "ftl.yml", "ftl.json"} {
+			if _, err := os.Stat(file); err == nil {
+				configFile = file
+				break
+			}
+		}
+		if configFile == "" {
+			return fmt.Errorf("no ftl.yaml or ftl.json found; variables can only be generated into YAML or JSON configuration")
+		}
+	}
+	configFile = filepath.Clean(configFile)
+
+	ext := strings.ToLower(filepath.Ext(configFile))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return &usageError{fmt.Errorf("cannot update %s: variables can only be generated into YAML or JSON configuration", configFile)}
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	manifest, err := loadDeployManifest(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	declared, err := scanComponentVariables(manifest, filepath.Dir(configFile))
+	if err != nil {
+		return err
+	}
+
+	var updated []byte
+	var changes *generate.Changes
+	if ext == ".json" {
+		updated, changes, err = generate.ApplyJSON(data, declared, opts.Prune)
+	} else {
+		updated, changes, err = generate.ApplyYAML(data, declared, opts.Prune)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", configFile, err)
+	}
+
+	written := false
+	if !opts.DryRun && !changes.Empty() {
+		if err := os.WriteFile(configFile, updated, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", configFile, err)
+		}
+		written = true
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(generateVarsResult{
+			File:       configFile,
+			Written:    written,
+			Components: declared,
+			Changes:    changes,
+		})
+	}
+
+	if opts.DryRun {
+		_, _ = colorOutput.Write(updated)
+	}
+	displayVarChanges(configFile, declared, changes, written)
+	return nil
+}
+
+// scanComponentVariables collects the ftlvar declarations of every local Go
+// component, keyed by component ID
+func scanComponentVariables(manifest *validation.Application, baseDir string) (map[string][]generate.Variable, error) {
+	declared := make(map[string][]generate.Variable)
+	for _, comp := range manifest.Components {
+		src, ok := comp.Source.(*validation.LocalSource)
+		if !ok {
+			continue
+		}
+		workdir := ""
+		if comp.Build != nil {
+			workdir = comp.Build.Workdir
+		}
+		dir := generate.SourceDir(src.Path, workdir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		if !generate.HasGoFiles(dir) {
+			continue
+		}
+
+		vars, err := generate.ScanDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", comp.ID, err)
+		}
+		if len(vars) > 0 {
+			Debug("Found %d variables in %s", len(vars), dir)
+			declared[comp.ID] = vars
+		}
+	}
+	return declared, nil
+}
+
+func displayVarChanges(configFile string, declared map[string][]generate.Variable, changes *generate.Changes, written bool) {
+	if len(declared) == 0 {
+		Info("No ftlvar tags found in Go components")
+		return
+	}
+
+	for _, name := range changes.Added {
+		Success("Added %s", name)
+	}
+	for _, name := range changes.Removed {
+		Success("Removed %s", name)
+	}
+	for _, name := range changes.Stale {
+		Warn("%s is no longer declared by its component; run with --prune to remove it", name)
+	}
+	for _, name := range changes.MissingRequired {
+		Warn("%s is required; set its value in %s before deploying", name, configFile)
+	}
+
+	switch {
+	case changes.Empty():
+		Info("Variables in %s are up to date", configFile)
+	case written:
+		Success("Updated %s. Run 'ftl synth' to regenerate spin.toml", configFile)
+	}
+}
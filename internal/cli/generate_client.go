@@ -0,0 +1,541 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// GenerateClientOptions holds options for the generate client command
+type GenerateClientOptions struct {
+	Lang       string
+	Package    string
+	URL        string
+	Components []string
+	OutFile    string
+	Auth       bool
+	Timeout    time.Duration
+}
+
+func newGenerateClientCmd() *cobra.Command {
+	opts := &GenerateClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "client",
+		Short: "Generate a typed client for the tools of an app",
+		Long: `Generate a typed client that calls the tools of an FTL app over MCP.
+
+Tool names and JSON schemas are read from a running app ('ftl up' locally, or
+a deployed MCP URL), like 'ftl tools export'. By default every component
+listed in ftl.yaml is included; use --component to limit the client to
+specific components.
+
+The Go client is a package with no dependencies outside the standard
+library. Each tool becomes a method taking an input struct, and returning an
+output struct when the tool declares an output schema:
+
+  client := weather.New("https://my-app.example.com/mcp")
+  client.Header.Set("Authorization", "Bearer "+token)
+  forecast, err := client.WeatherGetForecast(ctx, weather.WeatherGetForecastInput{City: "Paris"})
+
+Languages:
+  go          Go package (--package defaults to the directory of --out-file)
+  typescript  TypeScript types and a fetch-based client
+  python      Python TypedDicts and a urllib-based client
+
+Examples:
+  ftl generate client --lang go --out-file internal/weather/client.go
+  ftl generate client --lang go --package tools --url https://my-app.example.com/mcp --auth`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerateClient(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Lang, "lang", "l", "go", "Client language (go, typescript, python)")
+	cmd.Flags().StringVarP(&opts.Package, "package", "p", "", "Go package name (default: the directory name of --out-file, or ftlclient)")
+	cmd.Flags().StringVarP(&opts.URL, "url", "u", defaultCallURL, "MCP endpoint URL")
+	cmd.Flags().StringSliceVarP(&opts.Components, "component", "c", nil, "Only include tools from these components")
+	cmd.Flags().StringVar(&opts.OutFile, "out-file", "", "File to write the client to (default: stdout)")
+	cmd.Flags().BoolVar(&opts.Auth, "auth", false, "Send FTL credentials with the request")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Request timeout")
+
+	_ = cmd.RegisterFlagCompletionFunc("lang", completeFixed("go", "typescript", "python"))
+	_ = cmd.RegisterFlagCompletionFunc("component", completeComponentNames)
+
+	return cmd
+}
+
+func runGenerateClient(ctx context.Context, opts *GenerateClientOptions) error {
+	switch opts.Lang {
+	case "go", "typescript", "ts", "python", "py":
+	default:
+		return &usageError{fmt.Errorf("unsupported language '%s': must be one of go, typescript, python", opts.Lang)}
+	}
+
+	tools, _, _, err := fetchTools(ctx, opts.URL, opts.Auth, opts.Components, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	switch opts.Lang {
+	case "go":
+		out, err = generateGoClient(goClientPackage(opts.Package, opts.OutFile), tools)
+		if err != nil {
+			return err
+		}
+	case "typescript", "ts":
+		out = []byte(generateTypeScriptClient(tools))
+	case "python", "py":
+		out = []byte(generatePythonClient(tools))
+	}
+
+	if opts.OutFile == "" {
+		_, err = colorOutput.Write(out)
+		return err
+	}
+	if dir := filepath.Dir(opts.OutFile); dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(opts.OutFile, out, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.OutFile, err)
+	}
+	Success("Generated a client for %d tools in %s", len(tools), opts.OutFile)
+	return nil
+}
+
+// goClientPackage returns the package name of a generated Go client: the
+// one given, or the name of the directory it is written to
+func goClientPackage(name, output string) string {
+	if name == "" && output != "" {
+		if abs, err := filepath.Abs(output); err == nil {
+			name = filepath.Base(filepath.Dir(abs))
+		}
+	}
+	name = strings.ToLower(strings.Join(identifierWords(name), ""))
+	if name == "" || !token.IsIdentifier(name) || token.IsKeyword(name) || name == "main" {
+		return "ftlclient"
+	}
+	return name
+}
+
+// goTypeWriter accumulates the named types a JSON schema needs
+type goTypeWriter struct {
+	b     strings.Builder
+	names map[string]bool
+}
+
+// generateGoClient writes a Go package with a method per tool
+func generateGoClient(pkg string, tools []mcpTool) ([]byte, error) {
+	w := &goTypeWriter{names: map[string]bool{}}
+	w.b.WriteString("// Code generated by ftl generate client. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&w.b, "// Package %s calls the tools of an FTL app over MCP.\n", pkg)
+	fmt.Fprintf(&w.b, "package %s\n", pkg)
+	w.b.WriteString(goClientRuntime)
+
+	for _, tool := range tools {
+		typeName := goIdentifier(tool.Name)
+		input := w.namedType(typeName+"Input", tool.InputSchema)
+		output := ""
+		if tool.OutputSchema != nil {
+			output = w.namedType(typeName+"Output", tool.OutputSchema)
+		}
+
+		w.b.WriteString("\n")
+		doc := fmt.Sprintf("%s calls the %s tool.", typeName, tool.Name)
+		if desc := strings.TrimSpace(tool.Description); desc != "" {
+			doc += "\n\n" + desc
+		}
+		writeGoDoc(&w.b, "", doc)
+		if output == "" {
+			fmt.Fprintf(&w.b, "func (c *Client) %s(ctx context.Context, in %s) (*ToolResult, error) {\n", typeName, input)
+			fmt.Fprintf(&w.b, "\treturn c.CallTool(ctx, %q, in)\n}\n", tool.Name)
+			continue
+		}
+		fmt.Fprintf(&w.b, "func (c *Client) %s(ctx context.Context, in %s) (*%s, error) {\n", typeName, input, output)
+		fmt.Fprintf(&w.b, "\tresult, err := c.CallTool(ctx, %q, in)\n", tool.Name)
+		w.b.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		fmt.Fprintf(&w.b, "\tvar out %s\n", output)
+		fmt.Fprintf(&w.b, "\tif err := result.Decode(&out); err != nil {\n\t\treturn nil, fmt.Errorf(\"%s: %%w\", err)\n\t}\n", tool.Name)
+		w.b.WriteString("\treturn &out, nil\n}\n")
+	}
+
+	src, err := format.Source([]byte(w.b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated client: %w", err)
+	}
+	return src, nil
+}
+
+// namedType declares a type for a schema and returns its name. Objects
+// become structs; anything else is declared as an alias.
+func (w *goTypeWriter) namedType(name string, schema map[string]interface{}) string {
+	for w.names[name] {
+		name += "_"
+	}
+	w.names[name] = true
+
+	var decl strings.Builder
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		fmt.Fprintf(&decl, "type %s = %s\n", name, w.goType(name, schema))
+	} else {
+		required := requiredSet(schema)
+		fmt.Fprintf(&decl, "type %s struct {\n", name)
+		fields := map[string]bool{}
+		for _, prop := range sortedKeys(props) {
+			propSchema, _ := props[prop].(map[string]interface{})
+			field := goIdentifier(prop)
+			for fields[field] {
+				field += "_"
+			}
+			fields[field] = true
+
+			typ := w.goType(name+field, propSchema)
+			tag := prop
+			if !required[prop] {
+				tag += ",omitempty"
+				if isGoScalar(typ) || strings.HasPrefix(typ, name) {
+					typ = "*" + typ
+				}
+			}
+			if desc, ok := propSchema["description"].(string); ok && desc != "" {
+				writeGoDoc(&decl, "\t", desc)
+			}
+			fmt.Fprintf(&decl, "\t%s %s `json:%q`\n", field, typ, tag)
+		}
+		decl.WriteString("}\n")
+	}
+
+	w.b.WriteString("\n")
+	if desc, ok := schema["description"].(string); ok && desc != "" {
+		writeGoDoc(&w.b, "", desc)
+	}
+	w.b.WriteString(decl.String())
+	return name
+}
+
+// goType converts a JSON schema into a Go type, declaring named types for
+// nested objects
+func (w *goTypeWriter) goType(name string, schema map[string]interface{}) string {
+	if schema == nil {
+		return "any"
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		if _, isString := enum[0].(string); isString {
+			return "string"
+		}
+		return "any"
+	}
+
+	switch t := schema["type"].(type) {
+	case []interface{}:
+		// ["string", "null"] is an optional string; other unions are any
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		if len(types) == 1 {
+			sub := copySchema(schema)
+			sub["type"] = types[0]
+			return w.goType(name, sub)
+		}
+		return "any"
+	case string:
+		switch t {
+		case "string":
+			return "string"
+		case "integer":
+			return "int64"
+		case "number":
+			return "float64"
+		case "boolean":
+			return "bool"
+		case "array":
+			items, _ := schema["items"].(map[string]interface{})
+			if items == nil {
+				return "[]any"
+			}
+			return "[]" + w.goType(name+"Item", items)
+		case "object":
+			return w.objectType(name, schema)
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return w.objectType(name, schema)
+	}
+	return "any"
+}
+
+func (w *goTypeWriter) objectType(name string, schema map[string]interface{}) string {
+	if props, _ := schema["properties"].(map[string]interface{}); len(props) > 0 {
+		return w.namedType(name, schema)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		return "map[string]" + w.goType(name+"Value", additional)
+	}
+	return "map[string]any"
+}
+
+func isGoScalar(typ string) bool {
+	switch typ {
+	case "string", "int64", "float64", "bool":
+		return true
+	}
+	return false
+}
+
+// goIdentifier turns a tool or property name into an exported identifier
+func goIdentifier(name string) string {
+	id := toPascalIdentifier(name)
+	if id == "" || !token.IsIdentifier(id) {
+		return "X" + id
+	}
+	return id
+}
+
+// writeGoDoc writes text as a comment, one line per line of text
+func writeGoDoc(b *strings.Builder, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
+
+// goClientRuntime is the part of a generated Go client shared by all tools
+const goClientRuntime = `
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Client calls the tools of an FTL app over MCP streamable HTTP
+type Client struct {
+	url string
+
+	// HTTPClient sends the requests. Default: http.DefaultClient
+	HTTPClient *http.Client
+	// Header is added to every request, e.g. for Authorization
+	Header http.Header
+
+	mu          sync.Mutex
+	nextID      int
+	sessionID   string
+	initialized bool
+}
+
+// New returns a client for the MCP endpoint of an app
+func New(url string) *Client {
+	return &Client{url: url, Header: http.Header{}}
+}
+
+// Content is an item of a tool result's content
+type Content struct {
+	Type string ` + "`json:\"type\"`" + `
+	Text string ` + "`json:\"text,omitempty\"`" + `
+}
+
+// ToolResult is the result of a tool call
+type ToolResult struct {
+	Content           []Content       ` + "`json:\"content\"`" + `
+	StructuredContent json.RawMessage ` + "`json:\"structuredContent,omitempty\"`" + `
+	IsError           bool            ` + "`json:\"isError,omitempty\"`" + `
+}
+
+// Text returns the text of the result's content
+func (r *ToolResult) Text() string {
+	var texts []string
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			texts = append(texts, c.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Decode decodes the result's structured content, or its text when the
+// tool returned none, into out
+func (r *ToolResult) Decode(out any) error {
+	data := []byte(r.StructuredContent)
+	if len(data) == 0 {
+		data = []byte(r.Text())
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
+
+// ToolError is returned when a tool reports an error
+type ToolError struct {
+	Tool    string
+	Message string
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("tool %s failed: %s", e.Tool, e.Message)
+}
+
+// CallTool calls a tool by name. Errors reported by the tool are returned
+// as a *ToolError.
+func (c *Client) CallTool(ctx context.Context, name string, arguments any) (*ToolResult, error) {
+	if err := c.initialize(ctx); err != nil {
+		return nil, err
+	}
+	var result ToolResult
+	params := map[string]any{"name": name, "arguments": arguments}
+	if err := c.rpc(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return &result, &ToolError{Tool: name, Message: result.Text()}
+	}
+	return &result, nil
+}
+
+func (c *Client) initialize(ctx context.Context) error {
+	c.mu.Lock()
+	initialized := c.initialized
+	c.mu.Unlock()
+	if initialized {
+		return nil
+	}
+
+	params := map[string]any{
+		"protocolVersion": "` + mcpProtocolVersion + `",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "ftl-generated-client", "version": "0.1.0"},
+	}
+	if err := c.rpc(ctx, "initialize", params, nil); err != nil {
+		return err
+	}
+	if _, err := c.post(ctx, map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"}); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.initialized = true
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) rpc(ctx context.Context, method string, params, result any) error {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.mu.Unlock()
+
+	body, err := c.post(ctx, map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Result json.RawMessage ` + "`json:\"result\"`" + `
+		Error  *struct {
+			Code    int    ` + "`json:\"code\"`" + `
+			Message string ` + "`json:\"message\"`" + `
+		} ` + "`json:\"error\"`" + `
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s failed: %s (%d)", method, resp.Error.Message, resp.Error.Code)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// post sends a JSON-RPC message and returns the JSON body of the reply,
+// which may come as plain JSON or in an event stream
+func (c *Client) post(ctx context.Context, message any) ([]byte, error) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("MCP-Protocol-Version", "` + mcpProtocolVersion + `")
+	c.mu.Lock()
+	if c.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", c.sessionID)
+	}
+	c.mu.Unlock()
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		c.mu.Lock()
+		c.sessionID = sid
+		c.mu.Unlock()
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return lastEventData(body), nil
+	}
+	return body, nil
+}
+
+// lastEventData extracts the data of the last event in an event stream
+func lastEventData(body []byte) []byte {
+	var data, last []byte
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+		case line == "" && len(data) > 0:
+			last, data = data, nil
+		}
+	}
+	if len(data) > 0 {
+		last = data
+	}
+	return last
+}
+`
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGoClient(t *testing.T) {
+	out, err := generateGoClient("weather", testTools)
+	require.NoError(t, err)
+	src := string(out)
+
+	assert.Contains(t, src, "// Code generated by ftl generate client. DO NOT EDIT.")
+	assert.Contains(t, src, "package weather")
+	assert.Contains(t, src, "type WeatherGetForecastInput struct")
+	assert.Contains(t, src, "City  string  `json:\"city\"`")
+	assert.Contains(t, src, "Days  *int64  `json:\"days,omitempty\"`")
+	assert.Contains(t, src, "// WeatherGetForecast calls the weather__get_forecast tool.")
+	assert.Contains(t, src, "func (c *Client) WeatherGetForecast(ctx context.Context, in WeatherGetForecastInput) (*WeatherGetForecastOutput, error)")
+	assert.Contains(t, src, "[]float64")
+	assert.Contains(t, src, "func (c *Client) EchoEcho(ctx context.Context, in EchoEchoInput) (*ToolResult, error)")
+}
+
+func TestGenerateGoClientCompiles(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	out, err := generateGoClient("weather", testTools)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/weather\n\ngo 1.24\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "client.go"), out, 0600))
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestGoClientPackage(t *testing.T) {
+	assert.Equal(t, "tools", goClientPackage("tools", "x/client.go"))
+	assert.Equal(t, "weatherapi", goClientPackage("", "internal/weather-api/client.go"))
+	assert.Equal(t, "ftlclient", goClientPackage("", ""))
+	assert.Equal(t, "ftlclient", goClientPackage("type", ""))
+}
+
+func TestRunGenerateClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg["id"] == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		result := map[string]interface{}{}
+		if msg["method"] == "tools/list" {
+			result["tools"] = testTools
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": msg["id"], "result": result})
+	}))
+	defer server.Close()
+
+	chdirTemp(t)
+
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	err := runGenerateClient(context.Background(), &GenerateClientOptions{
+		Lang:       "go",
+		URL:        server.URL,
+		Components: []string{"echo"},
+		Timeout:    5 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "package ftlclient")
+	assert.Contains(t, buf.String(), "func (c *Client) EchoEcho(")
+	assert.NotContains(t, buf.String(), "WeatherGetForecast(")
+
+	outFile := filepath.Join("weather", "client.go")
+	err = runGenerateClient(context.Background(), &GenerateClientOptions{
+		Lang:    "go",
+		URL:     server.URL,
+		OutFile: outFile,
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "package weather")
+
+	var usage *usageError
+	err = runGenerateClient(context.Background(), &GenerateClientOptions{Lang: "java", URL: server.URL, Timeout: 5 * time.Second})
+	assert.ErrorAs(t, err, &usage)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const generateTestConfig = `name: demo
+version: "0.1.0"
+components:
+  - id: weather
+    source: ./weather
+    variables:
+      legacy_token: abc # kept until pruned
+`
+
+const generateTestSource = `package main
+
+import ftl "github.com/fastertools/ftl/sdk/go"
+
+type Config struct {
+	ftl.Config
+	APIKey  string ` + "`ftlvar:\"api_key,required,secret\"`" + `
+	BaseURL string ` + "`ftlvar:\",default=https://api.example.com\"`" + `
+}
+`
+
+func setupGenerateProject(t *testing.T) {
+	t.Helper()
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte(generateTestConfig), 0600))
+	require.NoError(t, os.MkdirAll("weather", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join("weather", "main.go"), []byte(generateTestSource), 0600))
+}
+
+func TestRunGenerateVars(t *testing.T) {
+	setupGenerateProject(t)
+	setGlobalOutput(t, "")
+
+	require.NoError(t, runGenerateVars(&GenerateVarsOptions{}))
+
+	data, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `api_key: "" # required`)
+	assert.Contains(t, string(data), "base_url: https://api.example.com")
+	assert.Contains(t, string(data), "legacy_token: abc # kept until pruned")
+
+	// Running again changes nothing
+	require.NoError(t, runGenerateVars(&GenerateVarsOptions{}))
+	again, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+func TestRunGenerateVars_DryRun(t *testing.T) {
+	setupGenerateProject(t)
+	buf := setGlobalOutput(t, "")
+
+	require.NoError(t, runGenerateVars(&GenerateVarsOptions{DryRun: true}))
+
+	assert.Contains(t, buf.String(), "base_url: https://api.example.com")
+	data, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, generateTestConfig, string(data))
+}
+
+func TestRunGenerateVars_PruneJSON(t *testing.T) {
+	setupGenerateProject(t)
+	buf := setGlobalOutput(t, "json")
+
+	require.NoError(t, runGenerateVars(&GenerateVarsOptions{Prune: true}))
+
+	var result generateVarsResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.True(t, result.Written)
+	assert.ElementsMatch(t, []string{"weather.api_key", "weather.base_url"}, result.Changes.Added)
+	assert.Equal(t, []string{"weather.legacy_token"}, result.Changes.Removed)
+	assert.Equal(t, []string{"weather.api_key"}, result.Changes.MissingRequired)
+
+	data, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "legacy_token")
+}
+
+func TestRunGenerateVars_RejectsTOML(t *testing.T) {
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("ftl.toml", []byte("[application]\nname = \"demo\"\n"), 0600))
+
+	err := runGenerateVars(&GenerateVarsOptions{ConfigFile: "ftl.toml"})
+	var uerr *usageError
+	assert.ErrorAs(t, err, &uerr)
+}
@@ -18,6 +18,7 @@ type InitOptions struct {
 	Language      string // Configuration language: yaml, go, cue, json
 	NoInteractive bool
 	Force         bool
+	Workspace     bool // Scaffold a monorepo workspace instead of a single project
 }
 
 // newInitCmd creates the init command
@@ -32,7 +33,12 @@ func newInitCmd() *cobra.Command {
 This command creates a new FTL project directory with:
 - ftl.yaml configuration file
 - Basic project structure
-- Example components (optional)`,
+- Example components (optional)
+
+With --workspace it creates a monorepo layout instead:
+- main.go, a Go CDK app that adds every component in components/
+- components/, apps/ and shared/ directories
+- A Makefile that builds every component and app`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -47,6 +53,7 @@ This command creates a new FTL project directory with:
 	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", "configuration language (yaml, go, cue, json)")
 	cmd.Flags().BoolVar(&opts.NoInteractive, "no-interactive", false, "disable interactive prompts")
 	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "overwrite existing files")
+	cmd.Flags().BoolVar(&opts.Workspace, "workspace", false, "scaffold a monorepo workspace (apps/, components/, shared/)")
 
 	return cmd
 }
@@ -62,6 +69,14 @@ func runInit(opts *InitOptions) error {
 		}
 	}
 
+	// Workspaces are always configured in Go
+	if opts.Workspace {
+		if opts.Language != "" && opts.Language != "go" {
+			return &usageError{fmt.Errorf("--workspace uses Go configuration; it cannot be combined with --language %s", opts.Language)}
+		}
+		opts.Language = "go"
+	}
+
 	// Prompt for config language if not specified
 	if opts.Language == "" {
 		if opts.NoInteractive {
@@ -86,8 +101,6 @@ func runInit(opts *InitOptions) error {
 		return fmt.Errorf("failed to create project directory: %w", err)
 	}
 
-	Info("Initializing FTL project '%s' with %s configuration", opts.Name, opts.Language)
-
 	// Get description or use default
 	description := opts.Description
 	if description == "" {
@@ -100,6 +113,12 @@ func runInit(opts *InitOptions) error {
 		return fmt.Errorf("failed to initialize scaffolder: %w", err)
 	}
 
+	if opts.Workspace {
+		return initWorkspace(scaffolder, projectDir, opts.Name, description)
+	}
+
+	Info("Initializing FTL project '%s' with %s configuration", opts.Name, opts.Language)
+
 	if err := scaffolder.GenerateProject(projectDir, opts.Name, description, opts.Language); err != nil {
 		return fmt.Errorf("failed to generate project: %w", err)
 	}
@@ -141,12 +160,34 @@ func runInit(opts *InitOptions) error {
 	return nil
 }
 
+func initWorkspace(scaffolder *scaffold.Scaffolder, projectDir, name, description string) error {
+	Info("Initializing FTL workspace '%s'", name)
+
+	if err := scaffolder.GenerateWorkspace(projectDir, name, description); err != nil {
+		return fmt.Errorf("failed to generate workspace: %w", err)
+	}
+
+	Success("Created main.go")
+	Success("Created go.mod")
+	Success("Created Makefile")
+	Success("Created components/, apps/ and shared/")
+
+	fmt.Println()
+	Info("Next steps:")
+	fmt.Println("  1. cd", name+"/components")
+	fmt.Println("  2. ftl add my-tool")
+	fmt.Println("  3. cd .. && make build")
+	fmt.Println("  4. make up")
+
+	return nil
+}
+
 func promptForName(opts *InitOptions) error {
 	prompt := &survey.Input{
 		Message: "Project name:",
 		Help:    "The name of your FTL project (lowercase, alphanumeric, hyphens)",
 	}
-	return survey.AskOne(prompt, &opts.Name, survey.WithValidator(survey.Required))
+	return ask(prompt, &opts.Name, "Pass the project name: ftl init <name>", survey.WithValidator(survey.Required))
 }
 
 func promptForLanguage(opts *InitOptions) error {
@@ -163,7 +204,7 @@ func promptForLanguage(opts *InitOptions) error {
 	}
 
 	var choice string
-	if err := survey.AskOne(prompt, &choice); err != nil {
+	if err := ask(prompt, &choice, "Pass the configuration language with --language"); err != nil {
 		return err
 	}
 
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"testing"
@@ -273,3 +275,45 @@ func TestGoProjectGeneration(t *testing.T) {
 	goModPath := filepath.Join(tmpDir, "go-app", "go.mod")
 	assert.FileExists(t, goModPath)
 }
+
+func TestWorkspaceGeneration(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runInit(&InitOptions{Name: "mono", NoInteractive: true, Workspace: true})
+	require.NoError(t, err)
+
+	root := filepath.Join(tmpDir, "mono")
+	for _, file := range []string{"main.go", "go.mod", "Makefile", "README.md", ".gitignore"} {
+		assert.FileExists(t, filepath.Join(root, file))
+	}
+	for _, dir := range []string{"apps", "components", "shared"} {
+		assert.DirExists(t, filepath.Join(root, dir))
+	}
+
+	// The top-level app must be valid Go
+	_, err = parser.ParseFile(token.NewFileSet(), filepath.Join(root, "main.go"), nil, 0)
+	require.NoError(t, err)
+
+	makefile, err := os.ReadFile(filepath.Join(root, "Makefile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(makefile), "\t\t(cd $$dir && ftl build) || exit 1; \\\n")
+
+	// Components are built with their language's build profile
+	mainGo, err := os.ReadFile(filepath.Join(root, "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(mainGo), "WithBuildProfile(lang.profile)")
+}
+
+func TestWorkspaceRejectsOtherLanguages(t *testing.T) {
+	chdirTemp(t)
+
+	err := runInit(&InitOptions{Name: "mono", Language: "yaml", NoInteractive: true, Workspace: true})
+	var usage *usageError
+	assert.ErrorAs(t, err, &usage)
+}
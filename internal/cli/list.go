@@ -5,11 +5,9 @@ import (
 	"fmt"
 	"strings"
 
-	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/spf13/cobra"
 
 	"github.com/fastertools/ftl/internal/api"
-	"github.com/fastertools/ftl/internal/auth"
 )
 
 func newListCmd() *cobra.Command {
@@ -22,11 +20,12 @@ func newListCmd() *cobra.Command {
 		Long:  `List all FTL applications deployed on the platform.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			return runList(ctx, format, detailed)
+			return runList(ctx, resolveOutputFormat(format), detailed)
 		},
 	}
 
-	cmd.Flags().StringVarP(&format, "output", "o", "table", "Output format (table, json)")
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
 	cmd.Flags().BoolVarP(&detailed, "detailed", "d", false, "Show additional details (app ID, deployment info)")
 
 	return cmd
@@ -37,11 +36,10 @@ var runList = runListImpl
 
 func runListImpl(ctx context.Context, format string, detailed bool) error {
 	// Initialize auth manager
-	store, err := auth.NewKeyringStore()
+	authManager, err := newAuthManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize credential store: %w", err)
+		return err
 	}
-	authManager := auth.NewManager(store, nil)
 
 	// Check authentication
 	if _, err := authManager.GetToken(ctx); err != nil {
@@ -49,21 +47,24 @@ func runListImpl(ctx context.Context, format string, detailed bool) error {
 	}
 
 	// Create API client
-	apiClient, err := api.NewFTLClient(authManager, "")
+	apiClient, err := newAPIClient(authManager)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
 	}
 
-	// List all apps - use empty params instead of nil
+	// List all apps across every page - use empty params instead of nil
 	// (some backends may filter differently with nil vs empty params)
 	Debug("Calling ListApps with empty params")
-	response, err := apiClient.ListApps(ctx, &api.ListAppsParams{})
+	apps, err := api.IterateApps(ctx, apiClient, &api.ListAppsParams{}).Collect()
 	if err != nil {
 		return fmt.Errorf("failed to list apps: %w", err)
 	}
+	if apps == nil {
+		apps = []api.ListedApp{}
+	}
 
-	Debug("ListApps returned %d apps", len(response.Apps))
-	if len(response.Apps) == 0 {
+	Debug("ListApps returned %d apps", len(apps))
+	if len(apps) == 0 && format == "table" {
 		_, _ = fmt.Fprintln(colorOutput, "No applications found.")
 		return nil
 	}
@@ -72,40 +73,16 @@ func runListImpl(ctx context.Context, format string, detailed bool) error {
 	dw := NewDataWriter(colorOutput, format)
 
 	switch format {
-	case "json":
-		return dw.WriteStruct(response.Apps)
+	case "json", "yaml":
+		return dw.WriteStruct(apps)
 	case "table":
-		return displayAppsTable(response.Apps, detailed, dw)
+		return displayAppsTable(apps, detailed, dw)
 	default:
-		return fmt.Errorf("invalid output format: %s (use 'table' or 'json')", format)
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
 	}
 }
 
-func displayAppsTable(apps []struct {
-	AccessControl *api.ListAppsResponseBodyAppsAccessControl `json:"accessControl,omitempty"`
-	AllowedRoles  *[]string                                  `json:"allowedRoles,omitempty"`
-	AppId         openapi_types.UUID                         `json:"appId"`
-	AppName       string                                     `json:"appName"`
-	CreatedAt     string                                     `json:"createdAt"`
-	CustomAuth    *struct {
-		Audience string `json:"audience"`
-		Issuer   string `json:"issuer"`
-	} `json:"customAuth,omitempty"`
-	LatestDeployment *struct {
-		CreatedAt          *float32                                           `json:"createdAt,omitempty"`
-		DeployedAt         *float32                                           `json:"deployedAt,omitempty"`
-		DeploymentDuration *float32                                           `json:"deploymentDuration,omitempty"`
-		DeploymentId       string                                             `json:"deploymentId"`
-		Environment        *string                                            `json:"environment,omitempty"`
-		Status             api.ListAppsResponseBodyAppsLatestDeploymentStatus `json:"status"`
-		StatusMessage      *string                                            `json:"statusMessage,omitempty"`
-	} `json:"latestDeployment"`
-	OrgId         *string                            `json:"orgId,omitempty"`
-	ProviderError *string                            `json:"providerError,omitempty"`
-	ProviderUrl   *string                            `json:"providerUrl,omitempty"`
-	Status        api.ListAppsResponseBodyAppsStatus `json:"status"`
-	UpdatedAt     string                             `json:"updatedAt"`
-}, detailed bool, dw *DataWriter) error {
+func displayAppsTable(apps []api.ListedApp, detailed bool, dw *DataWriter) error {
 	// Build headers
 	var headers []string
 	if detailed {
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/fatih/color"
@@ -249,3 +251,18 @@ func mustParseUUID(s string) openapi_types.UUID {
 	}
 	return openapi_types.UUID(googleUUID)
 }
+
+func TestRunList_Platform(t *testing.T) {
+	platform := usePlatform(t)
+	out := setGlobalOutput(t, "")
+	platform.AddApp("alpha", api.AppAccessControlPublic)
+	platform.AddApp("beta", api.AppAccessControlPrivate)
+
+	require.NoError(t, runListImpl(context.Background(), "json", false))
+
+	var apps []api.ListedApp
+	require.NoError(t, json.Unmarshal(out.Bytes(), &apps))
+	require.Len(t, apps, 2)
+	assert.Equal(t, "alpha", apps[0].AppName)
+	assert.Equal(t, "beta", apps[1].AppName)
+}
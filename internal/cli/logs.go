@@ -59,6 +59,7 @@ Examples:
 
   # Get logs from the last 30 minutes, showing only last 50 lines
   ftl logs my-app --since 30m --tail 50`,
+		ValidArgsFunction: completeAppNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
@@ -103,7 +104,7 @@ func runLogs(ctx context.Context, opts *LogsOptions) error {
 
 	// Create API client with auth
 	client, err := api.NewClientWithResponses(
-		api.DefaultAPIBaseURL,
+		api.BaseURL(),
 		api.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
 			req.Header.Set("Authorization", "Bearer "+token)
 			return nil
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// mockAuthManifestFile is the manifest ftl up --mock-auth runs. It sits next
+// to spin.toml so relative component sources still resolve, and is removed
+// when ftl up exits. It is the only manifest that gives the authorizer a
+// mock identity: the shipped authorizer does not declare the variable.
+const mockAuthManifestFile = "spin.mock-auth.toml"
+
+// defaultMockSubject is the subject of the mock identity unless --mock-sub
+// is given
+const defaultMockSubject = "dev-user"
+
+// mockIdentity builds the claims of the identity the authorizer accepts
+// every request as. Claims are given as name=value; values that parse as
+// JSON keep their type, anything else is a string.
+func mockIdentity(subject string, claims []string) (string, error) {
+	identity := map[string]interface{}{"sub": subject}
+	for _, claim := range claims {
+		name, value, ok := strings.Cut(claim, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return "", &usageError{fmt.Errorf("invalid --mock-claim %q, expected name=value", claim)}
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			identity[name] = decoded
+		} else {
+			identity[name] = value
+		}
+	}
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// withMockAuth sets the authorizer of a Spin manifest to accept every
+// request as the given identity. The identity is a value of the authorizer
+// component, never an app variable, so it cannot be overridden at runtime.
+func withMockAuth(spinManifest []byte, identity string) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(spinManifest, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spin.toml: %w", err)
+	}
+
+	components, _ := doc["component"].(map[string]interface{})
+	authorizer, ok := components["mcp-authorizer"].(map[string]interface{})
+	if !ok {
+		return nil, &usageError{fmt.Errorf("--mock-auth needs an app with private, org or custom access, which runs the mcp-authorizer")}
+	}
+	variables, _ := authorizer["variables"].(map[string]interface{})
+	if variables == nil {
+		variables = make(map[string]interface{})
+		authorizer["variables"] = variables
+	}
+	variables["mcp_mock_identity"] = identity
+	if appVariables, ok := doc["variables"].(map[string]interface{}); ok {
+		delete(appVariables, "mcp_mock_identity")
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode spin.toml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeMockAuthManifest writes the mock-auth variant of spin.toml and
+// returns its path
+func writeMockAuthManifest(identity string) (string, error) {
+	data, err := os.ReadFile("spin.toml")
+	if err != nil {
+		return "", fmt.Errorf("failed to read spin.toml: %w", err)
+	}
+	mocked, err := withMockAuth(data, identity)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(mockAuthManifestFile, mocked, 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", mockAuthManifestFile, err)
+	}
+	return mockAuthManifestFile, nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fastertools/ftl/synthesis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockIdentity(t *testing.T) {
+	identity, err := mockIdentity("alice", []string{"org_id=org_123", "tier=3", "admin=true", "scope=read write"})
+	require.NoError(t, err)
+
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(identity), &claims))
+	assert.Equal(t, map[string]interface{}{
+		"sub":    "alice",
+		"org_id": "org_123",
+		"tier":   float64(3),
+		"admin":  true,
+		"scope":  "read write",
+	}, claims)
+
+	_, err = mockIdentity("alice", []string{"no-value"})
+	var usage *usageError
+	assert.True(t, errors.As(err, &usage))
+}
+
+func TestWithMockAuth(t *testing.T) {
+	synth := synthesis.NewSynthesizer()
+	private, err := synth.SynthesizeYAML([]byte(`
+name: secure
+access: custom
+auth:
+  jwt_issuer: https://auth.example.com
+  jwt_audience: api
+  policy: "package mcp.authorization\ndefault allow := true"
+components:
+  - id: tool
+    source: ./tool.wasm
+`))
+	require.NoError(t, err)
+
+	mocked, err := withMockAuth([]byte(private), `{"sub":"alice"}`)
+	require.NoError(t, err)
+
+	var doc struct {
+		Variables map[string]interface{} `toml:"variables"`
+		Component map[string]struct {
+			Source    interface{}       `toml:"source"`
+			Variables map[string]string `toml:"variables"`
+		} `toml:"component"`
+		Trigger struct {
+			HTTP []map[string]interface{} `toml:"http"`
+		} `toml:"trigger"`
+	}
+	require.NoError(t, toml.Unmarshal(mocked, &doc))
+	authorizer := doc.Component["mcp-authorizer"]
+	assert.Equal(t, `{"sub":"alice"}`, authorizer.Variables["mcp_mock_identity"])
+	assert.NotContains(t, doc.Variables, "mcp_mock_identity", "the identity must not be settable as an app variable")
+	assert.Equal(t, "https://auth.example.com", authorizer.Variables["mcp_jwt_issuer"])
+	assert.Equal(t, "./tool.wasm", doc.Component["tool"].Source)
+	assert.Len(t, doc.Trigger.HTTP, 3)
+
+	public, err := synth.SynthesizeYAML([]byte("name: open\ncomponents:\n  - id: tool\n    source: ./tool.wasm\n"))
+	require.NoError(t, err)
+	_, err = withMockAuth([]byte(public), `{"sub":"alice"}`)
+	var usage *usageError
+	assert.True(t, errors.As(err, &usage), "public apps have no authorizer to mock")
+}
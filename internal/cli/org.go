@@ -11,8 +11,6 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 
-	"github.com/fastertools/ftl/internal/api"
-	"github.com/fastertools/ftl/internal/auth"
 	"github.com/fastertools/ftl/internal/config"
 )
 
@@ -39,6 +37,7 @@ organization context, and view the currently selected organization.`,
 // newOrgListCmd creates the 'org list' command
 func newOrgListCmd() *cobra.Command {
 	var refresh bool
+	var format string
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -47,11 +46,13 @@ func newOrgListCmd() *cobra.Command {
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			return runOrgList(ctx, refresh)
+			return runOrgList(ctx, refresh, resolveOutputFormat(format))
 		},
 	}
 
 	cmd.Flags().BoolVar(&refresh, "refresh", false, "Refresh organization list from server")
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
 
 	return cmd
 }
@@ -98,8 +99,16 @@ func newOrgCurrentCmd() *cobra.Command {
 	return cmd
 }
 
+// orgListEntry is the machine-readable form of an organization in 'ftl org list'
+type orgListEntry struct {
+	ID       string `json:"id" yaml:"id"`
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Current  bool   `json:"current" yaml:"current"`
+	LastUsed string `json:"lastUsed,omitempty" yaml:"lastUsed,omitempty"`
+}
+
 // runOrgList lists available organizations
-func runOrgList(ctx context.Context, refresh bool) error {
+func runOrgList(ctx context.Context, refresh bool, format string) error {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -112,11 +121,10 @@ func runOrgList(ctx context.Context, refresh bool) error {
 
 	if needsRefresh {
 		// Initialize auth
-		store, err := auth.NewKeyringStore()
+		authManager, err := newAuthManager()
 		if err != nil {
-			return fmt.Errorf("failed to initialize credential store: %w", err)
+			return err
 		}
-		authManager := auth.NewManager(store, nil)
 
 		// Check authentication
 		if _, err := authManager.GetToken(ctx); err != nil {
@@ -124,9 +132,9 @@ func runOrgList(ctx context.Context, refresh bool) error {
 		}
 
 		// Create FTL client
-		client, err := api.NewFTLClient(authManager, "")
+		client, err := newAPIClient(authManager)
 		if err != nil {
-			return fmt.Errorf("failed to create API client: %w", err)
+			return err
 		}
 
 		// Get user info and organizations
@@ -154,7 +162,7 @@ func runOrgList(ctx context.Context, refresh bool) error {
 		}
 		if err := cfg.SetCurrentUser(userCfg); err != nil {
 			// Non-fatal
-			fmt.Printf("Warning: failed to save user info: %v\n", err)
+			Warn("Failed to save user info: %v", err)
 		}
 
 		// Update config with org info
@@ -174,15 +182,28 @@ func runOrgList(ctx context.Context, refresh bool) error {
 		}
 	}
 
+	// Display organizations
+	currentOrg := cfg.GetCurrentOrg()
+
+	if format != "table" {
+		entries := make([]orgListEntry, 0, len(orgs))
+		for _, orgID := range orgs {
+			entry := orgListEntry{ID: orgID, Current: orgID == currentOrg}
+			if orgInfo, exists := cfg.GetOrganization(orgID); exists {
+				entry.Name = orgInfo.Name
+				entry.LastUsed = orgInfo.LastUsed
+			}
+			entries = append(entries, entry)
+		}
+		return NewDataWriter(colorOutput, format).WriteStruct(entries)
+	}
+
 	if len(orgs) == 0 {
 		Warn("No organizations available")
 		Info("Run 'ftl org list --refresh' to check for new organizations")
 		return nil
 	}
 
-	// Display organizations
-	currentOrg := cfg.GetCurrentOrg()
-
 	// Use tabwriter for aligned output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	_, _ = fmt.Fprintln(w, "CURRENT\tORG ID\tNAME\tLAST USED")
@@ -231,11 +252,10 @@ func runOrgSet(ctx context.Context, orgID string, forceInteractive bool) error {
 	}
 
 	// Initialize auth
-	store, err := auth.NewKeyringStore()
+	authManager, err := newAuthManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize credential store: %w", err)
+		return err
 	}
-	authManager := auth.NewManager(store, nil)
 
 	// Check authentication
 	if _, err := authManager.GetToken(ctx); err != nil {
@@ -243,9 +263,9 @@ func runOrgSet(ctx context.Context, orgID string, forceInteractive bool) error {
 	}
 
 	// Create FTL client
-	client, err := api.NewFTLClient(authManager, "")
+	client, err := newAPIClient(authManager)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
 	}
 
 	// Get user info and organizations
@@ -276,10 +296,6 @@ func runOrgSet(ctx context.Context, orgID string, forceInteractive bool) error {
 
 	// If interactive mode or no org specified, prompt for selection
 	if forceInteractive || orgID == "" {
-		if !isInteractive() {
-			return fmt.Errorf("organization ID required in non-interactive mode")
-		}
-
 		// Build options with names
 		options := make([]string, len(orgIDs))
 		for i, id := range orgIDs {
@@ -297,7 +313,7 @@ func runOrgSet(ctx context.Context, orgID string, forceInteractive bool) error {
 			Options: options,
 		}
 
-		if err := survey.AskOne(prompt, &selected); err != nil {
+		if err := ask(prompt, &selected, "Pass the organization ID: ftl org set <org-id>"); err != nil {
 			return err
 		}
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
 )
 
 // OutputFormat represents the output format type
@@ -13,6 +15,7 @@ type OutputFormat string
 const (
 	OutputFormatTable OutputFormat = "table"
 	OutputFormatJSON  OutputFormat = "json"
+	OutputFormatYAML  OutputFormat = "yaml"
 )
 
 // DataWriter handles formatted output of structured data
@@ -24,8 +27,11 @@ type DataWriter struct {
 // NewDataWriter creates a new DataWriter
 func NewDataWriter(output io.Writer, format string) *DataWriter {
 	of := OutputFormatTable
-	if format == "json" {
+	switch format {
+	case "json":
 		of = OutputFormatJSON
+	case "yaml", "yml":
+		of = OutputFormatYAML
 	}
 	return &DataWriter{
 		output: output,
@@ -38,6 +44,8 @@ func (dw *DataWriter) WriteKeyValue(title string, data map[string]interface{}) e
 	switch dw.format {
 	case OutputFormatJSON:
 		return dw.writeJSON(data)
+	case OutputFormatYAML:
+		return dw.writeYAML(data)
 	case OutputFormatTable:
 		return dw.writeKeyValueTable(title, data)
 	default:
@@ -48,9 +56,9 @@ func (dw *DataWriter) WriteKeyValue(title string, data map[string]interface{}) e
 // WriteTable writes tabular data with headers
 func (dw *DataWriter) WriteTable(headers []string, rows [][]string) error {
 	switch dw.format {
-	case OutputFormatJSON:
-		// Convert to array of objects for JSON
-		var jsonData []map[string]string
+	case OutputFormatJSON, OutputFormatYAML:
+		// Convert to array of objects
+		jsonData := []map[string]string{}
 		for _, row := range rows {
 			obj := make(map[string]string)
 			for i, header := range headers {
@@ -60,6 +68,9 @@ func (dw *DataWriter) WriteTable(headers []string, rows [][]string) error {
 			}
 			jsonData = append(jsonData, obj)
 		}
+		if dw.format == OutputFormatYAML {
+			return dw.writeYAML(jsonData)
+		}
 		return dw.writeJSON(jsonData)
 	case OutputFormatTable:
 		return dw.writeTabularData(headers, rows)
@@ -73,6 +84,8 @@ func (dw *DataWriter) WriteStruct(data interface{}) error {
 	switch dw.format {
 	case OutputFormatJSON:
 		return dw.writeJSON(data)
+	case OutputFormatYAML:
+		return dw.writeYAML(data)
 	case OutputFormatTable:
 		// For table format, we need to convert struct to key-value pairs
 		// This is a simplified version - could be enhanced with reflection
@@ -89,6 +102,26 @@ func (dw *DataWriter) writeJSON(data interface{}) error {
 	return encoder.Encode(data)
 }
 
+// writeYAML writes data as YAML. Data is first round-tripped through JSON so
+// YAML documents use the same field names as the JSON output.
+func (dw *DataWriter) writeYAML(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(dw.output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(generic); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
 // writeKeyValueTable writes key-value pairs as an aligned table
 func (dw *DataWriter) writeKeyValueTable(title string, data map[string]interface{}) error {
 	if title != "" {
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/config"
+)
+
+// pluginPrefix is the executable name prefix that marks an FTL plugin.
+// Running 'ftl corp-publish' executes the first ftl-corp-publish found.
+const pluginPrefix = "ftl-"
+
+// pluginInfo describes a discovered plugin executable
+type pluginInfo struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Shadowed is set when a built-in command or an earlier plugin has the same name
+	Shadowed bool `json:"shadowed,omitempty"`
+}
+
+// pluginExitError carries a plugin's exit code back to the ftl process
+type pluginExitError struct {
+	name string
+	code int
+}
+
+func (e *pluginExitError) Error() string {
+	return fmt.Sprintf("plugin '%s' exited with code %d", e.name, e.code)
+}
+
+// pluginDirs returns the directories searched for plugins, in priority
+// order: the FTL plugins directory, then every directory on PATH.
+func pluginDirs() []string {
+	var dirs []string
+	if configDir, err := config.Dir(); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, "plugins"))
+	}
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// pluginName returns the plugin name for an executable file name, or ""
+func pluginName(fileName string) string {
+	if runtime.GOOS == "windows" {
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	}
+	if !strings.HasPrefix(fileName, pluginPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(fileName, pluginPrefix)
+}
+
+// isExecutable reports whether the file can be run as a plugin
+func isExecutable(info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
+
+// discoverPlugins lists every plugin executable in the search directories.
+// Plugins that collide with built-in commands or earlier plugins are marked
+// as shadowed.
+func discoverPlugins(root *cobra.Command, dirs []string) []pluginInfo {
+	var plugins []pluginInfo
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := pluginName(entry.Name())
+			if name == "" {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || !isExecutable(info) {
+				continue
+			}
+
+			plugins = append(plugins, pluginInfo{
+				Name:     name,
+				Path:     filepath.Join(dir, entry.Name()),
+				Shadowed: seen[name] || isBuiltinCommand(root, name),
+			})
+			seen[name] = true
+		}
+	}
+	return plugins
+}
+
+// findPlugin returns the first plugin with the given name
+func findPlugin(name string, dirs []string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		for _, candidate := range pluginCandidates(filepath.Join(dir, pluginPrefix+name)) {
+			info, err := os.Stat(candidate)
+			if err == nil && isExecutable(info) {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
+func pluginCandidates(path string) []string {
+	if runtime.GOOS != "windows" {
+		return []string{path}
+	}
+	return []string{path + ".exe", path + ".bat", path + ".cmd"}
+}
+
+// isBuiltinCommand reports whether name is a command or alias of root
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return name == "help"
+}
+
+// runPluginIfRequested runs a plugin when the first argument names one
+// instead of a built-in command. It reports whether a plugin was run.
+func runPluginIfRequested(root *cobra.Command, args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(root, args[0]) {
+		return false, nil
+	}
+	// Cobra's hidden completion commands are never plugins
+	if strings.HasPrefix(args[0], "__") {
+		return false, nil
+	}
+
+	path, ok := findPlugin(args[0], pluginDirs())
+	if !ok {
+		return false, nil
+	}
+	return true, runPlugin(args[0], path, args[1:])
+}
+
+// runPlugin executes a plugin with the remaining arguments, passing along
+// the terminal and some context about the invoking CLI
+func runPlugin(name, path string, args []string) error {
+	cmd := ExecCommand(path, args...) // #nosec G204 -- plugins are executables the user installed
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "FTL_CLI_VERSION="+version, "FTL_PLUGIN_NAME="+name)
+	if self, err := os.Executable(); err == nil {
+		cmd.Env = append(cmd.Env, "FTL_CLI_PATH="+self)
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &pluginExitError{name: name, code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run plugin '%s': %w", name, err)
+	}
+	return nil
+}
+
+func newPluginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage CLI plugins",
+		Long: `Manage FTL CLI plugins.
+
+A plugin is any executable named ftl-<name> in the FTL plugins directory
+or on your PATH. Running 'ftl <name> [args...]' executes the plugin with
+the remaining arguments. Plugins receive FTL_CLI_VERSION, FTL_CLI_PATH and
+FTL_PLUGIN_NAME in their environment.
+
+Plugins cannot override built-in commands.`,
+	}
+
+	cmd.AddCommand(newPluginListCmd())
+	return cmd
+}
+
+func newPluginListCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List installed plugins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listPlugins(cmd.Root(), pluginDirs(), resolveOutputFormat(format))
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
+
+	return cmd
+}
+
+func listPlugins(root *cobra.Command, dirs []string, format string) error {
+	plugins := discoverPlugins(root, dirs)
+	sort.SliceStable(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+
+	dw := NewDataWriter(colorOutput, format)
+	switch format {
+	case "json", "yaml":
+		if plugins == nil {
+			plugins = []pluginInfo{}
+		}
+		return dw.WriteStruct(plugins)
+	case "table":
+	default:
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
+	}
+
+	if len(plugins) == 0 {
+		_, _ = fmt.Fprintln(colorOutput, "No plugins found.")
+		_, _ = fmt.Fprintf(colorOutput, "Install an executable named %s<name> on your PATH to add 'ftl <name>'.\n", pluginPrefix)
+		return nil
+	}
+
+	tb := NewTableBuilder("NAME", "PATH", "NOTE")
+	for _, p := range plugins {
+		note := ""
+		if p.Shadowed {
+			note = "shadowed"
+		}
+		tb.AddRow(p.Name, p.Path, note)
+	}
+	return tb.Write(dw)
+}
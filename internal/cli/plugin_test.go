@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPlugins installs test plugins into a fresh PATH and config directory
+func setupPlugins(t *testing.T) (pathDir, pluginDir string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts require a POSIX shell")
+	}
+
+	configDir := t.TempDir()
+	pathDir = t.TempDir()
+	pluginDir = filepath.Join(configDir, "ftl", "plugins")
+	require.NoError(t, os.MkdirAll(pluginDir, 0750))
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("PATH", pathDir)
+
+	script := func(dir, name, body string, mode os.FileMode) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), mode))
+	}
+	script(pluginDir, "ftl-corp-publish", `echo "$FTL_PLUGIN_NAME $*" > "$PLUGIN_OUT"`, 0700)
+	script(pathDir, "ftl-corp-publish", `echo "from PATH" > "$PLUGIN_OUT"`, 0700)
+	script(pathDir, "ftl-fail", "exit 3", 0700)
+	script(pathDir, "ftl-status", "exit 0", 0700)
+	script(pathDir, "ftl-not-executable", "exit 0", 0600)
+	script(pathDir, "other-tool", "exit 0", 0700)
+	return pathDir, pluginDir
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	pathDir, pluginDir := setupPlugins(t)
+
+	plugins := discoverPlugins(rootCmd, pluginDirs())
+	assert.ElementsMatch(t, []pluginInfo{
+		{Name: "corp-publish", Path: filepath.Join(pluginDir, "ftl-corp-publish")},
+		{Name: "corp-publish", Path: filepath.Join(pathDir, "ftl-corp-publish"), Shadowed: true},
+		{Name: "fail", Path: filepath.Join(pathDir, "ftl-fail")},
+		{Name: "status", Path: filepath.Join(pathDir, "ftl-status"), Shadowed: true},
+	}, plugins)
+
+	path, ok := findPlugin("corp-publish", pluginDirs())
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(pluginDir, "ftl-corp-publish"), path)
+
+	_, ok = findPlugin("not-executable", pluginDirs())
+	assert.False(t, ok)
+	_, ok = findPlugin("../ftl-fail", pluginDirs())
+	assert.False(t, ok)
+}
+
+func TestRunPluginIfRequested(t *testing.T) {
+	setupPlugins(t)
+	out := filepath.Join(t.TempDir(), "out.txt")
+	t.Setenv("PLUGIN_OUT", out)
+
+	handled, err := runPluginIfRequested(rootCmd, []string{"corp-publish", "--env", "prod"})
+	assert.True(t, handled)
+	require.NoError(t, err)
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "corp-publish --env prod\n", string(data))
+
+	handled, err = runPluginIfRequested(rootCmd, []string{"fail"})
+	assert.True(t, handled)
+	assert.Equal(t, 3, ExitCode(err))
+
+	// Built-in commands, flags and unknown names are left to cobra
+	for _, args := range [][]string{{"status", "my-app"}, {"--verbose"}, {"missing"}, {}} {
+		handled, _ = runPluginIfRequested(rootCmd, args)
+		assert.False(t, handled, "args %v", args)
+	}
+}
+
+func TestListPlugins(t *testing.T) {
+	setupPlugins(t)
+
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	require.NoError(t, listPlugins(rootCmd, pluginDirs(), "table"))
+	assert.Contains(t, buf.String(), "corp-publish")
+	assert.Contains(t, buf.String(), "shadowed")
+
+	buf.Reset()
+	require.NoError(t, listPlugins(rootCmd, pluginDirs(), "json"))
+	var plugins []pluginInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &plugins))
+	assert.Len(t, plugins, 4)
+	assert.Equal(t, "corp-publish", plugins[0].Name)
+
+	buf.Reset()
+	require.NoError(t, listPlugins(rootCmd, nil, "table"))
+	assert.Contains(t, buf.String(), "No plugins found.")
+}
+
+func TestCompletion_PluginNames(t *testing.T) {
+	_, pluginDir := setupPlugins(t)
+
+	got := runCompletion(t, "corp")
+	assert.Equal(t, []string{"corp-publish\tPlugin " + filepath.Join(pluginDir, "ftl-corp-publish"), ":4"}, got)
+}
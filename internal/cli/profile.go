@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/config"
+)
+
+// profileName is set by the global --profile flag
+var profileName string
+
+// profileEntry is the machine-readable form of a profile
+type profileEntry struct {
+	Name           string `json:"name" yaml:"name"`
+	Current        bool   `json:"current" yaml:"current"`
+	config.Profile `yaml:",inline"`
+}
+
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage FTL platform profiles",
+		Long: `Manage profiles for the FTL platforms you work with.
+
+A profile holds the API endpoint, the identity provider and the default
+registry of one platform, such as a self-hosted platform next to the FTL
+SaaS. Each profile keeps its own login, so switching profiles does not
+require logging in again.
+
+Profiles are stored in profiles.yaml in the FTL config directory
+(~/.config/ftl on Linux). The 'default' profile uses the FTL platform
+unless overridden. Use --profile or FTL_PROFILE to pick a profile for a
+single command.
+
+Example:
+  ftl profile set staging --api-url https://api.staging.example.com \
+      --authkit-domain auth.staging.example.com --registry registry.staging.example.com/tools
+  ftl profile use staging
+  ftl auth login
+  ftl --profile default list`,
+	}
+
+	cmd.AddCommand(
+		newProfileListCmd(),
+		newProfileUseCmd(),
+		newProfileCurrentCmd(),
+		newProfileSetCmd(),
+		newProfileDeleteCmd(),
+	)
+
+	return cmd
+}
+
+func newProfileListCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List profiles",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileList(resolveOutputFormat(format))
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
+
+	return cmd
+}
+
+func newProfileUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "use <name>",
+		Short:             "Select the current profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileUse(args[0])
+		},
+	}
+}
+
+func newProfileCurrentCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "current",
+		Aliases: []string{"show"},
+		Short:   "Show the profile in use",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileCurrent()
+		},
+	}
+}
+
+func newProfileSetCmd() *cobra.Command {
+	var profile config.Profile
+
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create or update a profile",
+		Long: `Create or update a profile. Only the given settings change; pass an
+empty value to go back to the default.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileSet(args[0], profile, func(name string) bool {
+				return cmd.Flags().Changed(name)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&profile.APIURL, "api-url", "", "Base URL of the platform API")
+	cmd.Flags().StringVar(&profile.AuthKitDomain, "authkit-domain", "", "Domain of the platform's identity provider")
+	cmd.Flags().StringVar(&profile.ClientID, "client-id", "", "OAuth client ID to log in with")
+	cmd.Flags().StringVar(&profile.Registry, "registry", "", "Registry used when a command is not given one")
+
+	return cmd
+}
+
+func newProfileDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete <name>",
+		Aliases:           []string{"rm"},
+		Short:             "Delete a profile",
+		Long:              `Delete a profile. Its login stays in the keyring until 'ftl auth logout' is run with the profile.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileDelete(args[0])
+		},
+	}
+}
+
+// applyProfileFlag selects the profile given with --profile for this
+// invocation
+func applyProfileFlag() error {
+	if profileName == "" {
+		return nil
+	}
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Get(profileName); !ok {
+		return &usageError{fmt.Errorf("profile '%s' not found (run 'ftl profile list')", profileName)}
+	}
+	config.SetProfileOverride(profileName)
+	return nil
+}
+
+func runProfileList(format string) error {
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	active := profiles.ActiveName()
+
+	entries := make([]profileEntry, 0, len(profiles.Profiles)+1)
+	for _, name := range profiles.Names() {
+		profile, _ := profiles.Get(name)
+		entries = append(entries, profileEntry{Name: name, Current: name == active, Profile: profile})
+	}
+
+	dw := NewDataWriter(colorOutput, format)
+	switch format {
+	case "json", "yaml":
+		return dw.WriteStruct(entries)
+	case "table":
+		return displayProfilesTable(entries, dw)
+	default:
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
+	}
+}
+
+func displayProfilesTable(entries []profileEntry, dw *DataWriter) error {
+	orDefault := func(value string) string {
+		if value == "" {
+			return "(default)"
+		}
+		return value
+	}
+	orNone := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	}
+
+	tb := NewTableBuilder("CURRENT", "NAME", "API URL", "AUTHKIT DOMAIN", "REGISTRY")
+	for _, entry := range entries {
+		current := " "
+		if entry.Current {
+			current = "*"
+		}
+		tb.AddRow(current, entry.Name, orDefault(entry.APIURL), orDefault(entry.AuthKitDomain), orNone(entry.Registry))
+	}
+	return tb.Write(dw)
+}
+
+func runProfileUse(name string) error {
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	if err := profiles.Use(name); err != nil {
+		return &usageError{err}
+	}
+	Success("Using profile '%s'", name)
+	return nil
+}
+
+func runProfileCurrent() error {
+	name, profile, err := config.ActiveProfile()
+	if err != nil {
+		return err
+	}
+
+	dw := NewDataWriter(colorOutput, "table")
+	kvb := NewKeyValueBuilder("Profile " + name)
+	kvb.Add("API URL", profileSetting(profile.APIURL, "(default)"))
+	kvb.Add("AuthKit domain", profileSetting(profile.AuthKitDomain, "(default)"))
+	kvb.Add("Client ID", profileSetting(profile.ClientID, "(default)"))
+	kvb.Add("Registry", profileSetting(profile.Registry, "-"))
+	return kvb.Write(dw)
+}
+
+func profileSetting(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func runProfileSet(name string, settings config.Profile, changed func(string) bool) error {
+	if err := config.ValidateProfileName(name); err != nil {
+		return &usageError{err}
+	}
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	profile, exists := profiles.Profiles[name]
+	if changed("api-url") {
+		profile.APIURL = settings.APIURL
+	}
+	if changed("authkit-domain") {
+		profile.AuthKitDomain = settings.AuthKitDomain
+	}
+	if changed("client-id") {
+		profile.ClientID = settings.ClientID
+	}
+	if changed("registry") {
+		profile.Registry = settings.Registry
+	}
+	profiles.Profiles[name] = profile
+
+	if err := profiles.Save(); err != nil {
+		return err
+	}
+	if exists {
+		Success("Updated profile '%s'", name)
+	} else {
+		Success("Created profile '%s'", name)
+		Info("Switch to it with 'ftl profile use %s', then log in with 'ftl auth login'", name)
+	}
+	return nil
+}
+
+func runProfileDelete(name string) error {
+	if name == config.DefaultProfile {
+		return &usageError{fmt.Errorf("the default profile cannot be deleted")}
+	}
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Profiles[name]; !ok {
+		return &usageError{fmt.Errorf("profile '%s' not found", name)}
+	}
+
+	delete(profiles.Profiles, name)
+	if profiles.Current == name {
+		profiles.Current = ""
+		Warn("Deleted the current profile; using '%s'", config.DefaultProfile)
+	}
+	if err := profiles.Save(); err != nil {
+		return err
+	}
+	Success("Deleted profile '%s'", name)
+	return nil
+}
+
+// defaultRegistry returns the registry of the active profile, used by
+// commands that were not given one
+func defaultRegistry() string {
+	_, profile, err := config.ActiveProfile()
+	if err != nil {
+		return ""
+	}
+	return profile.Registry
+}
+
+// completeProfileNames completes the names of known profiles
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return profiles.Names(), cobra.ShellCompDirectiveNoFileComp
+}
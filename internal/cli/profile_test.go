@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/config"
+)
+
+func TestProfileCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.ProfileEnv, "")
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	cmd := newProfileCmd()
+	cmd.SetArgs([]string{"set", "staging", "--api-url", "https://api.staging.example.com", "--registry", "registry.example.com/tools"})
+	require.NoError(t, cmd.Execute())
+
+	// Updating a profile keeps the settings that are not given
+	cmd = newProfileCmd()
+	cmd.SetArgs([]string{"set", "staging", "--authkit-domain", "auth.staging.example.com"})
+	require.NoError(t, cmd.Execute())
+
+	cmd = newProfileCmd()
+	cmd.SetArgs([]string{"use", "staging"})
+	require.NoError(t, cmd.Execute())
+
+	name, profile, err := config.ActiveProfile()
+	require.NoError(t, err)
+	assert.Equal(t, "staging", name)
+	assert.Equal(t, config.Profile{
+		APIURL:        "https://api.staging.example.com",
+		AuthKitDomain: "auth.staging.example.com",
+		Registry:      "registry.example.com/tools",
+	}, profile)
+	assert.Equal(t, "registry.example.com/tools", defaultRegistry())
+
+	buf.Reset()
+	require.NoError(t, runProfileList("json"))
+	var entries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "default", entries[0]["name"])
+	assert.Equal(t, "staging", entries[1]["name"])
+	assert.Equal(t, true, entries[1]["current"])
+	assert.Equal(t, "https://api.staging.example.com", entries[1]["api_url"])
+
+	var usage *usageError
+	assert.ErrorAs(t, runProfileUse("missing"), &usage)
+	assert.ErrorAs(t, runProfileDelete(config.DefaultProfile), &usage)
+
+	require.NoError(t, runProfileDelete("staging"))
+	name, _, err = config.ActiveProfile()
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultProfile, name)
+}
+
+func TestApplyProfileFlag(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer func() {
+		profileName = ""
+		config.SetProfileOverride("")
+	}()
+
+	profileName = "missing"
+	var usage *usageError
+	assert.ErrorAs(t, applyProfileFlag(), &usage)
+
+	profileName = config.DefaultProfile
+	assert.NoError(t, applyProfileFlag())
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/fastertools/ftl/ftlerr"
+)
+
+// nonInteractiveEnv disables prompts like --non-interactive, for CI
+const nonInteractiveEnv = "FTL_NONINTERACTIVE"
+
+// nonInteractive is set by the global --non-interactive flag
+var nonInteractive bool
+
+// For testing - allows answering prompts without a terminal
+var surveyAskOne = survey.AskOne
+
+// isInteractive reports whether commands may prompt: stdin must be a
+// terminal, and neither --non-interactive nor FTL_NONINTERACTIVE is set
+func isInteractive() bool {
+	if nonInteractive || envEnabled(os.Getenv(nonInteractiveEnv)) {
+		return false
+	}
+	fileInfo, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	// Check if stdin is a terminal (not a pipe or file)
+	return fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
+// envEnabled reports whether a boolean environment variable is set, to
+// anything but "0" or "false"
+func envEnabled(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
+
+// ask shows a prompt. Every prompt goes through ask, so none can hang a
+// CI job: when ftl cannot prompt, the prompt's default answer is used or,
+// without one, the command fails with hint, which names the flag or
+// argument that provides the answer. Confirmations only default to yes
+// when their default is yes.
+func ask(p survey.Prompt, response interface{}, hint string, opts ...survey.AskOpt) error {
+	if isInteractive() {
+		return surveyAskOne(p, response, opts...)
+	}
+	if answer, ok := defaultAnswer(p); ok {
+		Debug("Answering %q with its default %v", promptMessage(p), answer)
+		return setAnswer(response, answer)
+	}
+	return ftlerr.New(ftlerr.InputRequired,
+		fmt.Sprintf("cannot ask %q in non-interactive mode", promptMessage(p))).WithHint(hint)
+}
+
+// defaultAnswer returns the answer a prompt gives when accepted as is
+func defaultAnswer(p survey.Prompt) (interface{}, bool) {
+	switch p := p.(type) {
+	case *survey.Input:
+		return p.Default, p.Default != ""
+	case *survey.Confirm:
+		return true, p.Default
+	case *survey.Select:
+		switch d := p.Default.(type) {
+		case string:
+			return d, d != ""
+		case int:
+			if d >= 0 && d < len(p.Options) {
+				return p.Options[d], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// setAnswer stores a default answer in a prompt's response
+func setAnswer(response, answer interface{}) error {
+	switch r := response.(type) {
+	case *string:
+		if s, ok := answer.(string); ok {
+			*r = s
+			return nil
+		}
+	case *bool:
+		if b, ok := answer.(bool); ok {
+			*r = b
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot store %v in %T", answer, response)
+}
+
+// promptMessage returns a prompt's question, without its trailing colon
+func promptMessage(p survey.Prompt) string {
+	var message string
+	switch p := p.(type) {
+	case *survey.Input:
+		message = p.Message
+	case *survey.Confirm:
+		message = p.Message
+	case *survey.Select:
+		message = p.Message
+	case *survey.MultiSelect:
+		message = p.Message
+	case *survey.Password:
+		message = p.Message
+	}
+	return strings.TrimSuffix(strings.TrimSpace(message), ":")
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/ftlerr"
+)
+
+func setNonInteractive(t *testing.T) {
+	t.Helper()
+	old := nonInteractive
+	nonInteractive = true
+	t.Cleanup(func() { nonInteractive = old })
+}
+
+func TestIsInteractive_Disabled(t *testing.T) {
+	t.Setenv(nonInteractiveEnv, "1")
+	assert.False(t, isInteractive())
+
+	t.Setenv(nonInteractiveEnv, "")
+	setNonInteractive(t)
+	assert.False(t, isInteractive())
+}
+
+func TestEnvEnabled(t *testing.T) {
+	for _, value := range []string{"1", "true", "TRUE", "yes"} {
+		assert.True(t, envEnabled(value), value)
+	}
+	for _, value := range []string{"", "0", "false", "False", " "} {
+		assert.False(t, envEnabled(value), value)
+	}
+}
+
+func TestAsk_NonInteractiveDefaults(t *testing.T) {
+	setNonInteractive(t)
+	surveyAskOne = func(survey.Prompt, interface{}, ...survey.AskOpt) error {
+		t.Fatal("prompted in non-interactive mode")
+		return nil
+	}
+	t.Cleanup(func() { surveyAskOne = survey.AskOne })
+
+	var path string
+	require.NoError(t, ask(&survey.Input{Message: "Path:", Default: "./components/geo"}, &path, "Pass --source"))
+	assert.Equal(t, "./components/geo", path)
+
+	var choice string
+	require.NoError(t, ask(&survey.Select{Message: "Language:", Options: []string{"yaml", "go"}, Default: "go"}, &choice, ""))
+	assert.Equal(t, "go", choice)
+	require.NoError(t, ask(&survey.Select{Message: "Language:", Options: []string{"yaml", "go"}, Default: 0}, &choice, ""))
+	assert.Equal(t, "yaml", choice)
+
+	confirmed := false
+	require.NoError(t, ask(&survey.Confirm{Message: "Use current organization?", Default: true}, &confirmed, ""))
+	assert.True(t, confirmed)
+}
+
+func TestAsk_NonInteractiveRequiresInput(t *testing.T) {
+	setNonInteractive(t)
+
+	confirmed := false
+	err := ask(&survey.Confirm{Message: "Remove component 'geo'?"}, &confirmed, "Pass --yes to remove the component without confirmation")
+	require.Error(t, err)
+	assert.False(t, confirmed)
+	assert.Equal(t, ftlerr.InputRequired, ftlerr.CodeOf(err))
+	assert.Equal(t, "Pass --yes to remove the component without confirmation", ftlerr.HintOf(err))
+	assert.Contains(t, err.Error(), `"Remove component 'geo'?"`)
+
+	var name string
+	err = ask(&survey.Input{Message: "Component name:"}, &name, "Pass the component name: ftl add <name>")
+	assert.ErrorContains(t, err, `cannot ask "Component name" in non-interactive mode`)
+
+	var selected string
+	err = ask(&survey.Select{Message: "Select organization:", Options: []string{"a", "b"}}, &selected, "")
+	assert.Equal(t, ftlerr.InputRequired, ftlerr.CodeOf(err))
+}
+
+func TestRemoveComponent_NonInteractive(t *testing.T) {
+	setNonInteractive(t)
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: demo\ncomponents:\n  - id: geo\n    source: ./geo\n"), 0600))
+
+	err := removeComponent("geo", false)
+	assert.Equal(t, ftlerr.InputRequired, ftlerr.CodeOf(err))
+
+	err = removeComponent("", true)
+	assert.Equal(t, ftlerr.InputRequired, ftlerr.CodeOf(err))
+
+	require.NoError(t, removeComponent("geo", true))
+	data, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "geo")
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/fastertools/ftl/internal/buildcache"
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// componentProvenance describes how a local component was built: by which
+// builder, from which git commit, with which command and inputs
+func componentProvenance(comp *validation.Component, src *validation.LocalSource) *oci.Provenance {
+	provenance := &oci.Provenance{FinishedOn: time.Now()}
+	provenance.Builder, provenance.InvocationID = provenanceBuilder()
+
+	dir := componentDir(src.Path)
+	provenance.SourceRepo = normalizeGitRemote(gitOutput(dir, "remote", "get-url", "origin"))
+	provenance.SourceCommit = gitOutput(dir, "rev-parse", "HEAD")
+	if provenance.SourceCommit != "" {
+		provenance.SourceDirty = gitOutput(dir, "status", "--porcelain", "--", ".") != ""
+	}
+
+	if comp.Build != nil {
+		provenance.BuildCommand = comp.Build.Command
+		if len(comp.Build.Watch) > 0 {
+			workdir := comp.Build.Workdir
+			if workdir == "" {
+				workdir = "."
+			}
+			hash, err := buildcache.HashInputs(workdir, comp.Build.Watch, comp.Build.Command, comp.Build.Workdir)
+			if err != nil {
+				Debug("Failed to hash inputs of %s: %v", comp.ID, err)
+			}
+			provenance.InputsDigest = hash
+		}
+	}
+	return provenance
+}
+
+// provenanceBuilder identifies what is building: the CI workflow and run
+// when running in GitHub Actions or GitLab CI, otherwise the local user and
+// machine
+func provenanceBuilder() (builder, invocation string) {
+	if ref := os.Getenv("GITHUB_WORKFLOW_REF"); ref != "" {
+		server := os.Getenv("GITHUB_SERVER_URL")
+		builder = fmt.Sprintf("%s/%s", server, ref)
+		if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+			invocation = fmt.Sprintf("%s/%s/actions/runs/%s/attempts/%s", server, os.Getenv("GITHUB_REPOSITORY"), runID, os.Getenv("GITHUB_RUN_ATTEMPT"))
+		}
+		return builder, invocation
+	}
+	if project := os.Getenv("CI_PROJECT_URL"); project != "" {
+		return project + "/-/pipelines", os.Getenv("CI_JOB_URL")
+	}
+
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("local:%s@%s", name, host), ""
+}
+
+// gitOutput runs a git command in dir, returning its trimmed output or
+// nothing when it fails
+func gitOutput(dir string, args ...string) string {
+	out, err := ExecCommand("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// normalizeGitRemote turns a git remote into the https URL of the
+// repository, so policies can match sources however they were cloned, and
+// drops any credentials in it
+func normalizeGitRemote(remote string) string {
+	if remote == "" {
+		return ""
+	}
+	if !strings.Contains(remote, "://") {
+		// scp-like syntax: git@github.com:acme/tools.git
+		if host, path, ok := strings.Cut(remote, ":"); ok {
+			if _, h, found := strings.Cut(host, "@"); found {
+				host = h
+			}
+			remote = "https://" + host + "/" + path
+		}
+	}
+	u, err := url.Parse(remote)
+	if err != nil {
+		return remote
+	}
+	if u.Scheme == "ssh" || u.Scheme == "git" {
+		u.Scheme = "https"
+		u.Host = u.Hostname()
+	}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, ".git")
+	return u.String()
+}
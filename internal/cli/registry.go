@@ -3,7 +3,9 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/fastertools/ftl/oci"
 	"github.com/fastertools/ftl/spin"
 	"github.com/spf13/cobra"
 )
@@ -12,7 +14,7 @@ func newRegistryCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "registry",
 		Short: "Manage registry operations",
-		Long:  `Manage registry operations including push, pull, and list.`,
+		Long:  `Manage registry operations including push, pull, list, search and garbage collection.`,
 	}
 
 	// Add subcommands
@@ -20,6 +22,8 @@ func newRegistryCmd() *cobra.Command {
 		newRegistryPushCmd(),
 		newRegistryPullCmd(),
 		newRegistryListCmd(),
+		newRegistrySearchCmd(),
+		newRegistryGCCmd(),
 	)
 
 	return cmd
@@ -85,7 +89,10 @@ Example:
   ftl registry list --registry ghcr.io/myorg`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if registry == "" {
-				return fmt.Errorf("--registry flag is required")
+				registry = defaultRegistry()
+			}
+			if registry == "" {
+				return fmt.Errorf("--registry flag is required (or set a registry with 'ftl profile set')")
 			}
 
 			ctx := context.Background()
@@ -100,7 +107,109 @@ Example:
 		},
 	}
 
-	cmd.Flags().StringVarP(&registry, "registry", "r", "", "Registry URL to list")
+	cmd.Flags().StringVarP(&registry, "registry", "r", "", "Registry URL to list (default is the profile's registry)")
+
+	return cmd
+}
+
+func newRegistrySearchCmd() *cobra.Command {
+	var registry string
+	var limit int
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search a registry for components",
+		Long: `Search an OCI registry for WASM components to add to a project.
+
+Lists the components whose names contain the query, with their versions,
+descriptions and sizes. Without a query every component under the registry
+namespace is listed. Registries that do not allow listing their contents,
+such as GHCR, can only be searched for a component by its exact name.
+
+Add a component found this way with:
+  ftl component add <name> --registry <registry>/<namespace>:<package>@<version>
+
+Example:
+  ftl registry search --registry localhost:5000/acme
+  ftl registry search geo --registry ghcr.io/fastertools`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var query string
+			if len(args) > 0 {
+				query = args[0]
+			}
+			if registry == "" {
+				registry = defaultRegistry()
+			}
+			if registry == "" {
+				return &usageError{fmt.Errorf("--registry flag is required (or set a registry with 'ftl profile set')")}
+			}
+			return runRegistrySearch(cmd.Context(), registry, query, limit, resolveOutputFormat(format))
+		},
+	}
+
+	cmd.Flags().StringVarP(&registry, "registry", "r", "", "Registry and optional namespace to search (e.g. ghcr.io/fastertools, default is the profile's registry)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of components to list")
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
 
 	return cmd
 }
+
+func runRegistrySearch(ctx context.Context, registry, query string, limit int, format string) error {
+	components, err := oci.SearchComponents(ctx, registry, query, limit)
+	if err != nil {
+		return fmt.Errorf("failed to search %s: %w", registry, err)
+	}
+	if components == nil {
+		components = []oci.ComponentInfo{}
+	}
+
+	dw := NewDataWriter(colorOutput, format)
+	switch format {
+	case "json", "yaml":
+		return dw.WriteStruct(components)
+	case "table":
+	default:
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
+	}
+
+	if len(components) == 0 {
+		_, _ = fmt.Fprintf(colorOutput, "No components found in %s.\n", registry)
+		return nil
+	}
+
+	tb := NewTableBuilder("COMPONENT", "LATEST", "VERSIONS", "SIZE", "DESCRIPTION")
+	for _, c := range components {
+		description := c.Description
+		if description == "" {
+			description = "-"
+		} else if len(description) > 50 {
+			description = description[:47] + "..."
+		}
+		tb.AddRow(c.Registry+"/"+c.Package, c.Version, fmt.Sprintf("%d", len(c.Tags)), formatSize(c.Size), description)
+	}
+	if err := tb.Write(dw); err != nil {
+		return err
+	}
+
+	first := components[0]
+	id := first.Package[strings.LastIndexAny(first.Package, ":/")+1:]
+	_, _ = fmt.Fprintf(colorOutput, "\nAdd a component with: ftl component add %s --registry %s\n", id, first.Reference())
+	return nil
+}
+
+// formatSize renders a byte count for display
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
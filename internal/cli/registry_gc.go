@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/oci"
+)
+
+// RegistryGCOptions holds options for the registry gc command
+type RegistryGCOptions struct {
+	App        string
+	Keep       int
+	Components []string
+	DryRun     bool
+	Format     string
+}
+
+// registryGCResult is the machine-readable form of 'ftl registry gc'
+type registryGCResult struct {
+	App    string              `json:"app"`
+	DryRun bool                `json:"dry_run"`
+	Pruned []oci.PrunedVersion `json:"pruned"`
+}
+
+func newRegistryGCCmd() *cobra.Command {
+	opts := &RegistryGCOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete old versions of an app's components from the FTL registry",
+		Long: `Delete old versions of an app's components from the FTL Engine registry.
+
+Every deployment pushes the app's components, so their repositories keep
+growing. This keeps the newest --keep versions of each component, by
+semantic version, and deletes the rest. Tags that are not semantic
+versions are never deleted, and neither is a version whose image is also
+tagged with a kept version.
+
+Example:
+  ftl registry gc --app my-app --dry-run
+  ftl registry gc --app my-app --keep 3 --component weather`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRegistryGC(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.App, "app", "a", "", "Application name or ID")
+	cmd.Flags().IntVar(&opts.Keep, "keep", 5, "Number of newest versions to keep per component")
+	cmd.Flags().StringSliceVar(&opts.Components, "component", nil, "Only collect these components")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "List the versions that would be deleted without deleting them")
+	cmd.Flags().StringVarP(&opts.Format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.MarkFlagRequired("app")
+	_ = cmd.RegisterFlagCompletionFunc("app", completeAppNames)
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
+
+	return cmd
+}
+
+// Allow overriding for tests
+var runRegistryGC = runRegistryGCImpl
+
+func runRegistryGCImpl(ctx context.Context, opts *RegistryGCOptions) error {
+	if opts.Keep < 1 {
+		return &usageError{fmt.Errorf("--keep must be at least 1")}
+	}
+
+	apiClient, appID, err := appClient(ctx, opts.App)
+	if err != nil {
+		return err
+	}
+	components, err := api.IterateComponents(ctx, apiClient, appID).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list components: %w", err)
+	}
+	repositories, err := componentRepositories(components, opts.Components)
+	if err != nil {
+		return err
+	}
+	if len(repositories) == 0 {
+		Info("%s has no pushed components", opts.App)
+		return nil
+	}
+
+	names := make([]string, 0, len(repositories))
+	for name := range repositories {
+		names = append(names, name)
+	}
+	creds, err := apiClient.CreateDeployCredentials(ctx, appID, names)
+	if err != nil {
+		return fmt.Errorf("failed to get registry credentials: %w", err)
+	}
+	ecrAuth, err := oci.ParseECRToken(creds.Registry.RegistryUri, creds.Registry.AuthorizationToken)
+	if err != nil {
+		return fmt.Errorf("failed to parse ECR credentials: %w", err)
+	}
+
+	return pruneComponentRepositories(ctx, repositories, ecrAuth, opts)
+}
+
+// componentRepositories returns the repositories of an app's pushed
+// components by component name, restricted to only if it is not empty
+func componentRepositories(components []api.ListedComponent, only []string) (map[string]string, error) {
+	repositories := make(map[string]string, len(components))
+	for _, comp := range components {
+		if comp.RepositoryUri != nil && *comp.RepositoryUri != "" {
+			repositories[comp.ComponentName] = *comp.RepositoryUri
+		}
+	}
+	if len(only) == 0 {
+		return repositories, nil
+	}
+
+	selected := make(map[string]string, len(only))
+	for _, name := range only {
+		repo, ok := repositories[name]
+		if !ok {
+			return nil, fmt.Errorf("component '%s' has not been pushed for this app", name)
+		}
+		selected[name] = repo
+	}
+	return selected, nil
+}
+
+// pruneComponentRepositories applies the retention policy to each
+// component repository and reports the versions pruned
+func pruneComponentRepositories(ctx context.Context, repositories map[string]string, auth *oci.ECRAuth, opts *RegistryGCOptions) error {
+	names := make([]string, 0, len(repositories))
+	for name := range repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	policy := oci.RetentionPolicy{Keep: opts.Keep}
+	result := registryGCResult{App: opts.App, DryRun: opts.DryRun, Pruned: []oci.PrunedVersion{}}
+	componentOf := make(map[string]string)
+	for _, name := range names {
+		pruned, err := oci.PruneRepository(ctx, repositories[name], auth, policy, opts.DryRun)
+		result.Pruned = append(result.Pruned, pruned...)
+		for _, p := range pruned {
+			componentOf[p.Repository] = name
+		}
+		if err != nil {
+			return fmt.Errorf("failed to collect %s: %w", name, err)
+		}
+	}
+
+	format := resolveOutputFormat(opts.Format)
+	dw := NewDataWriter(colorOutput, format)
+	switch format {
+	case "json", "yaml":
+		return dw.WriteStruct(result)
+	case "table":
+	default:
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
+	}
+
+	if len(result.Pruned) == 0 {
+		Success("No versions beyond the newest %d of each component", opts.Keep)
+		return nil
+	}
+	tb := NewTableBuilder("COMPONENT", "VERSION", "DIGEST")
+	for _, p := range result.Pruned {
+		tb.AddRow(componentOf[p.Repository], p.Version, p.Digest)
+	}
+	if err := tb.Write(dw); err != nil {
+		return err
+	}
+	if opts.DryRun {
+		Info("%d versions would be deleted; run without --dry-run to delete them", len(result.Pruned))
+	} else {
+		Success("Deleted %d versions", len(result.Pruned))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/api"
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestComponentRepositories(t *testing.T) {
+	uri := "123.dkr.ecr.us-west-2.amazonaws.com/app-1/weather"
+	empty := ""
+	components := []api.ListedComponent{
+		{ComponentName: "weather", RepositoryUri: &uri},
+		{ComponentName: "draft", RepositoryUri: &empty},
+		{ComponentName: "new"},
+	}
+
+	repos, err := componentRepositories(components, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"weather": uri}, repos)
+
+	repos, err = componentRepositories(components, []string{"weather"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"weather": uri}, repos)
+
+	_, err = componentRepositories(components, []string{"draft"})
+	assert.ErrorContains(t, err, "has not been pushed")
+}
+
+func TestPruneComponentRepositories(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	chdirTemp(t)
+	ctx := context.Background()
+	auth := &oci.ECRAuth{Registry: regURL, Username: "test", Password: "test"}
+	pusher := oci.NewWASMPusher(auth)
+	for _, version := range []string{"0.1.0", "0.2.0", "0.3.0"} {
+		require.NoError(t, os.WriteFile("weather.wasm", []byte("weather "+version), 0600))
+		require.NoError(t, pusher.Push(ctx, "weather.wasm", "app-1/weather", version))
+	}
+	repos := map[string]string{"weather": regURL + "/app-1/weather"}
+
+	buf := setGlobalOutput(t, "")
+	require.NoError(t, pruneComponentRepositories(ctx, repos, auth, &RegistryGCOptions{App: "my-app", Keep: 1, DryRun: true}))
+	assert.Contains(t, buf.String(), "weather")
+	assert.Contains(t, buf.String(), "0.2.0")
+	assert.Contains(t, buf.String(), "0.1.0")
+
+	buf = setGlobalOutput(t, "json")
+	require.NoError(t, pruneComponentRepositories(ctx, repos, auth, &RegistryGCOptions{App: "my-app", Keep: 2, Format: "json"}))
+	var result registryGCResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.False(t, result.DryRun)
+	require.Len(t, result.Pruned, 1)
+	assert.Equal(t, "0.1.0", result.Pruned[0].Version)
+
+	buf = setGlobalOutput(t, "")
+	require.NoError(t, pruneComponentRepositories(ctx, repos, auth, &RegistryGCOptions{App: "my-app", Keep: 2}))
+	assert.NotContains(t, buf.String(), "weather", "nothing is left to delete")
+}
+
+func TestRunRegistryGC_InvalidKeep(t *testing.T) {
+	var usage *usageError
+	assert.ErrorAs(t, runRegistryGCImpl(context.Background(), &RegistryGCOptions{App: "my-app"}), &usage)
+}
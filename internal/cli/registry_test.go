@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestRunRegistrySearch(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	chdirTemp(t)
+	require.NoError(t, os.WriteFile("geo.wasm", []byte("component"), 0600))
+	ctx := context.Background()
+	pusher := oci.NewWASMPusher(&oci.ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.Push(ctx, "geo.wasm", "acme/geo", "1.0.0"))
+
+	buf := setGlobalOutput(t, "")
+	require.NoError(t, runRegistrySearch(ctx, regURL+"/acme", "", 50, "table"))
+	assert.Contains(t, buf.String(), regURL+"/acme:geo")
+	assert.Contains(t, buf.String(), "ftl component add geo --registry "+regURL+"/acme:geo@1.0.0")
+
+	buf.Reset()
+	require.NoError(t, runRegistrySearch(ctx, regURL+"/acme", "weather", 50, "table"))
+	assert.Contains(t, buf.String(), "No components found")
+
+	buf = setGlobalOutput(t, "json")
+	require.NoError(t, runRegistrySearch(ctx, regURL+"/acme", "geo", 50, "json"))
+	var components []oci.ComponentInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &components))
+	require.Len(t, components, 1)
+	assert.Equal(t, "acme:geo", components[0].Package)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KiB", formatSize(1536))
+	assert.Equal(t, "2.0 MiB", formatSize(2<<20))
+}
@@ -1,13 +1,19 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/fastertools/ftl/internal/config"
+	"github.com/fastertools/ftl/internal/tracing"
 )
 
 var (
@@ -38,17 +44,66 @@ var rootCmd = &cobra.Command{
 	Long: `FTL is a comprehensive toolkit for building, composing, and deploying 
 AI tools on WebAssembly. It provides everything you need to create secure,
 high-performance MCP servers that can run anywhere.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		if noColor {
 			color.NoColor = true
 		}
+		if err := validateGlobalOutput(); err != nil {
+			return err
+		}
+		if err := applyProfileFlag(); err != nil {
+			return err
+		}
+		if structuredFormat() != "" {
+			// Structured output must not contain color codes
+			color.NoColor = true
+		}
+		return nil
 	},
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, buildDate),
 }
 
 // Execute runs the root command
 func Execute() error {
-	return rootCmd.Execute()
+	// Commands not built into ftl may be provided by plugins
+	if handled, err := runPluginIfRequested(rootCmd, os.Args[1:]); handled {
+		var exitErr *pluginExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			Error("%v", err)
+		}
+		return err
+	}
+
+	shutdown, traceErr := tracing.Setup(context.Background(), version)
+	if traceErr != nil {
+		Debug("Tracing disabled: %v", traceErr)
+		shutdown = func(context.Context) error { return nil }
+	}
+	ctx, span := tracing.Start(tracing.FromEnviron(context.Background()), rootCmd.Name())
+
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	if cmd != nil {
+		span.SetName(cmd.CommandPath())
+	}
+	tracing.End(span, err)
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if flushErr := shutdown(flushCtx); flushErr != nil {
+		Debug("Failed to export traces: %v", flushErr)
+	}
+	cancel()
+
+	var reported *reportedError
+	if err != nil && structuredFormat() != "" && !errors.As(err, &reported) {
+		writeErrorResult(err)
+	} else if err != nil && structuredFormat() == "" {
+		printHint(err)
+	}
+
+	if settings, cfgErr := config.ReadTelemetry(); cfgErr == nil {
+		recordTelemetry(settings, cmd, start, err)
+	}
+	return err
 }
 
 // SetVersion sets the version information
@@ -66,10 +121,18 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./ftl.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().String("output", "", "machine-readable output format for command results (json, yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "platform profile to use (default is the current profile)")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt: use default answers or fail when input is required (also FTL_NONINTERACTIVE)")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err}
+	})
 
 	// Add commands
 	rootCmd.AddCommand(
@@ -86,9 +149,30 @@ func init() {
 		newSynthCmd(),
 		newListCmd(),
 		newStatusCmd(),
+		newDiffCmd(),
+		newAppCmd(),
 		newDeleteCmd(),
 		newLogsCmd(),
+		newCallCmd(),
+		newToolsCmd(),
+		newCompletionCmd(),
+		newPluginCmd(),
+		newTelemetryCmd(),
+		newBundleCmd(),
+		newDoctorCmd(),
+		newGenerateCmd(),
+		newCICmd(),
+		newConfigCmd(),
+		newProfileCmd(),
+		newUpgradeCmd(),
 	)
+
+	// Completion is provided by newCompletionCmd
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	_ = rootCmd.RegisterFlagCompletionFunc("config", completeConfigFiles)
+	_ = rootCmd.RegisterFlagCompletionFunc("output", completeFixed("json", "yaml"))
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	rootCmd.ValidArgsFunction = completePluginNames
 }
 
 // initConfig reads in config file and ENV variables if set
@@ -116,7 +200,7 @@ func initConfig() {
 
 // Success prints a success message
 func Success(format string, args ...interface{}) {
-	fmt.Println(successColor.Sprintf("✓ "+format, args...))
+	_, _ = fmt.Fprintln(messageOutput(), successColor.Sprintf("✓ "+format, args...))
 }
 
 // Error prints an error message
@@ -126,7 +210,7 @@ func Error(format string, args ...interface{}) {
 
 // Info prints an info message
 func Info(format string, args ...interface{}) {
-	fmt.Println(infoColor.Sprintf("ℹ "+format, args...))
+	_, _ = fmt.Fprintln(messageOutput(), infoColor.Sprintf("ℹ "+format, args...))
 }
 
 // Warn prints a warning message
@@ -149,7 +233,7 @@ func Fatal(format string, args ...interface{}) {
 
 // PrintStep prints a step in a process
 func PrintStep(step int, total int, message string) {
-	fmt.Printf("[%d/%d] %s\n", step, total, message)
+	_, _ = fmt.Fprintf(messageOutput(), "[%d/%d] %s\n", step, total, message)
 }
 
 // IsVerbose returns true if verbose mode is enabled
@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"testing"
 
@@ -377,8 +378,29 @@ func TestPreRunVerbose(t *testing.T) {
 }
 
 func TestRootCommandCompletion(t *testing.T) {
-	// Skip completion tests as they require full command setup
-	t.Skip("Completion tests require full cobra setup")
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			rootCmd.SetOut(&buf)
+			rootCmd.SetArgs([]string{"completion", shell})
+			defer func() {
+				rootCmd.SetOut(nil)
+				rootCmd.SetArgs(nil)
+			}()
+
+			require.NoError(t, rootCmd.Execute())
+			assert.Contains(t, buf.String(), "ftl")
+			assert.Contains(t, buf.String(), "__complete")
+		})
+	}
+
+	rootCmd.SetArgs([]string{"completion", "tcsh"})
+	rootCmd.SetErr(io.Discard)
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetErr(nil)
+	}()
+	assert.Error(t, rootCmd.Execute())
 }
 
 func TestCommandHelp(t *testing.T) {
@@ -9,24 +9,25 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/fastertools/ftl/internal/api"
-	"github.com/fastertools/ftl/internal/auth"
 )
 
 func newStatusCmd() *cobra.Command {
 	var format string
 
 	cmd := &cobra.Command{
-		Use:   "status <app-id|app-name>",
-		Short: "Get status of an FTL application",
-		Long:  `Get detailed status information for a specific FTL application.`,
-		Args:  cobra.ExactArgs(1),
+		Use:               "status <app-id|app-name>",
+		Short:             "Get status of an FTL application",
+		Long:              `Get detailed status information for a specific FTL application.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeAppNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			return runStatus(ctx, args[0], format)
+			return runStatus(ctx, args[0], resolveOutputFormat(format))
 		},
 	}
 
-	cmd.Flags().StringVarP(&format, "output", "o", "table", "Output format (table, json)")
+	cmd.Flags().StringVarP(&format, "output", "o", "", "Output format (table, json, yaml)")
+	_ = cmd.RegisterFlagCompletionFunc("output", completeFixed("table", "json", "yaml"))
 
 	return cmd
 }
@@ -36,11 +37,10 @@ var runStatus = runStatusImpl
 
 func runStatusImpl(ctx context.Context, appIdentifier string, format string) error {
 	// Initialize auth manager
-	store, err := auth.NewKeyringStore()
+	authManager, err := newAuthManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize credential store: %w", err)
+		return err
 	}
-	authManager := auth.NewManager(store, nil)
 
 	// Check authentication
 	if _, err := authManager.GetToken(ctx); err != nil {
@@ -48,9 +48,9 @@ func runStatusImpl(ctx context.Context, appIdentifier string, format string) err
 	}
 
 	// Create API client
-	apiClient, err := api.NewFTLClient(authManager, "")
+	apiClient, err := newAPIClient(authManager)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
 	}
 
 	// Determine if identifier is UUID or name
@@ -88,12 +88,12 @@ func runStatusImpl(ctx context.Context, appIdentifier string, format string) err
 	dw := NewDataWriter(colorOutput, format)
 
 	switch format {
-	case "json":
+	case "json", "yaml":
 		return dw.WriteStruct(app)
 	case "table":
 		return displayAppStatusTable(app)
 	default:
-		return fmt.Errorf("invalid output format: %s (use 'table' or 'json')", format)
+		return fmt.Errorf("invalid output format: %s (use 'table', 'json' or 'yaml')", format)
 	}
 }
 
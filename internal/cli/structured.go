@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/fastertools/ftl/ftlerr"
+)
+
+// Exit codes returned by the ftl binary
+const (
+	// ExitOK means the command succeeded
+	ExitOK = 0
+	// ExitFailure means the command ran and failed
+	ExitFailure = 1
+	// ExitUsage means the command line was invalid
+	ExitUsage = 2
+)
+
+// usageError marks errors caused by invalid flags or arguments
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// reportedError is returned by commands that already wrote a result
+// document describing the failure, so no error document is added
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+
+// ExitCode maps an error returned by Execute to the process exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return ExitUsage
+	}
+	var plugin *pluginExitError
+	if errors.As(err, &plugin) {
+		return plugin.code
+	}
+	return ExitFailure
+}
+
+// structuredFormat returns the machine-readable format selected with the
+// global --output flag (or FTL_OUTPUT), or "" for human-readable output.
+func structuredFormat() string {
+	switch strings.ToLower(viper.GetString("output")) {
+	case "json":
+		return "json"
+	case "yaml", "yml":
+		return "yaml"
+	}
+	return ""
+}
+
+// validateGlobalOutput checks the value of the global --output flag
+func validateGlobalOutput() error {
+	switch strings.ToLower(viper.GetString("output")) {
+	case "", "table", "text", "json", "yaml", "yml":
+		return nil
+	}
+	return &usageError{fmt.Errorf("invalid output format '%s': must be one of table, json, yaml", viper.GetString("output"))}
+}
+
+// resolveOutputFormat returns the format chosen with a command's own
+// --output flag, falling back to the global format and then to table.
+func resolveOutputFormat(local string) string {
+	if local != "" {
+		return local
+	}
+	if format := structuredFormat(); format != "" {
+		return format
+	}
+	return "table"
+}
+
+// messageOutput is where progress messages go. With structured output
+// stdout is reserved for the result document, so messages go to stderr.
+func messageOutput() io.Writer {
+	if structuredFormat() != "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// writeResult writes a command's result document in the structured format
+func writeResult(doc interface{}) error {
+	return NewDataWriter(colorOutput, structuredFormat()).WriteStruct(doc)
+}
+
+// errorDocument is written to stdout when a command fails with structured output
+type errorDocument struct {
+	Error    string      `json:"error"`
+	Code     ftlerr.Code `json:"code,omitempty"`
+	Hint     string      `json:"hint,omitempty"`
+	ExitCode int         `json:"exit_code"`
+}
+
+// writeErrorResult reports a failed command as a structured document
+func writeErrorResult(err error) {
+	_ = writeResult(errorDocument{
+		Error:    err.Error(),
+		Code:     ftlerr.CodeOf(err),
+		Hint:     ftlerr.HintOf(err),
+		ExitCode: ExitCode(err),
+	})
+}
+
+// printHint tells the user how to fix a failed command, if the error
+// carries a remediation hint
+func printHint(err error) {
+	if hint := ftlerr.HintOf(err); hint != "" {
+		fmt.Fprintln(os.Stderr, infoColor.Sprintf("→ %s", hint))
+	}
+}
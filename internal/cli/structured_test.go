@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fastertools/ftl/ftlerr"
+	"github.com/fastertools/ftl/validation"
+)
+
+func setGlobalOutput(t *testing.T, format string) *bytes.Buffer {
+	t.Helper()
+	oldOutput := colorOutput
+	var buf bytes.Buffer
+	colorOutput = &buf
+	viper.Set("output", format)
+	t.Cleanup(func() {
+		colorOutput = oldOutput
+		viper.Set("output", "")
+	})
+	return &buf
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitOK, ExitCode(nil))
+	assert.Equal(t, ExitFailure, ExitCode(errors.New("boom")))
+	assert.Equal(t, ExitUsage, ExitCode(&usageError{errors.New("bad flag")}))
+	assert.Equal(t, ExitUsage, ExitCode(fmt.Errorf("wrapped: %w", &usageError{errors.New("bad flag")})))
+}
+
+func TestResolveOutputFormat(t *testing.T) {
+	setGlobalOutput(t, "")
+	assert.Equal(t, "table", resolveOutputFormat(""))
+	assert.Equal(t, "json", resolveOutputFormat("json"))
+
+	viper.Set("output", "YAML")
+	assert.Equal(t, "yaml", resolveOutputFormat(""))
+	assert.Equal(t, "table", resolveOutputFormat("table"))
+	assert.NoError(t, validateGlobalOutput())
+
+	viper.Set("output", "xml")
+	assert.Equal(t, "table", resolveOutputFormat(""))
+	assert.Equal(t, ExitUsage, ExitCode(validateGlobalOutput()))
+}
+
+func TestDataWriterYAML(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDataWriter(&buf, "yaml")
+
+	require.NoError(t, dw.WriteStruct(struct {
+		AppName string `json:"appName"`
+		Count   int    `json:"count"`
+	}{AppName: "demo", Count: 2}))
+	assert.Equal(t, "appName: demo\ncount: 2\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, NewTableBuilder("NAME").Write(dw))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteErrorResult(t *testing.T) {
+	buf := setGlobalOutput(t, "json")
+
+	writeErrorResult(&usageError{errors.New("unknown flag: --bogus")})
+
+	var doc errorDocument
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "unknown flag: --bogus", doc.Error)
+	assert.Equal(t, ExitUsage, doc.ExitCode)
+	assert.Empty(t, doc.Code)
+
+	buf.Reset()
+	writeErrorResult(fmt.Errorf("failed to list apps: %w",
+		ftlerr.New(ftlerr.NotLoggedIn, "not logged in").WithHint(ftlerr.LoginHint)))
+
+	doc = errorDocument{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "failed to list apps: not logged in", doc.Error)
+	assert.Equal(t, ftlerr.NotLoggedIn, doc.Code)
+	assert.Equal(t, ftlerr.LoginHint, doc.Hint)
+	assert.Equal(t, ExitFailure, doc.ExitCode)
+}
+
+func TestListComponents_Structured(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(tmpDir+"/ftl.yaml", []byte(`name: test-app
+version: 0.1.0
+components:
+  - id: api
+    source: ./api
+    build:
+      command: make
+    variables:
+      token: x
+      region: y
+  - id: search
+    source:
+      registry: ghcr.io
+      package: acme/search
+      version: 1.0.0
+`), 0600))
+
+	oldWd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	buf := setGlobalOutput(t, "")
+
+	require.NoError(t, listComponents("json"))
+	var components []componentSummary
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &components))
+	require.Len(t, components, 2)
+	assert.Equal(t, componentSummary{ID: "api", Source: "./api", Build: "make", Variables: []string{"region", "token"}}, components[0])
+	assert.Equal(t, "ghcr.io/acme/search:1.0.0", components[1].Source)
+	assert.Empty(t, components[1].Variables)
+
+	buf.Reset()
+	require.NoError(t, listComponents("yaml"))
+	var fromYAML []map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &fromYAML))
+	assert.Equal(t, "api", fromYAML[0]["id"])
+
+	assert.Error(t, listComponents("xml"))
+}
+
+func TestDeployResult(t *testing.T) {
+	manifest := &validation.Application{
+		Name:   "demo",
+		Access: "public",
+		Components: []*validation.Component{
+			{ID: "weather"},
+			{ID: "search"},
+		},
+	}
+
+	result := newDeployResult(manifest, &DeployOptions{}, "deployed")
+	result.SetURL("https://demo.example.com/")
+
+	assert.Equal(t, "https://demo.example.com/mcp", result.MCPURL)
+	assert.Equal(t, "https://demo.example.com/mcp/x/weather", result.Components[0].MCPURL)
+	assert.Equal(t, "https://demo.example.com/mcp/x/search", result.Components[1].MCPURL)
+
+	accepted := newDeployResult(manifest, &DeployOptions{}, "accepted")
+	accepted.SetURL("")
+	data, err := json.Marshal(accepted)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"app":"demo","status":"accepted","access":"public","components":[{"id":"weather"},{"id":"search"}]}`, string(data))
+}
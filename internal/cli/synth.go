@@ -8,13 +8,18 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/oci"
 	"github.com/fastertools/ftl/synthesis"
+	"github.com/fastertools/ftl/validation"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 )
 
 // synthCmd represents the synth command
 func newSynthCmd() *cobra.Command {
 	var outputFile string
+	var check bool
 
 	cmd := &cobra.Command{
 		Use:   "synth [file]",
@@ -34,8 +39,12 @@ Examples:
   ftl synth platform.yaml -o spin.toml
 
   # Synthesize from stdin (YAML/JSON only)
-  cat platform.yaml | ftl synth -`,
-		Args: cobra.MaximumNArgs(1),
+  cat platform.yaml | ftl synth -
+
+  # Fail with a diff if the committed spin.toml is out of date
+  ftl synth --check`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeConfigFileArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var input []byte
 			var filename string
@@ -78,9 +87,19 @@ Examples:
 			if err != nil {
 				return fmt.Errorf("synthesis failed: %w", err)
 			}
+			if err := checkToolNames(filename); err != nil {
+				return fmt.Errorf("synthesis failed: %w", err)
+			}
 
-			// Output result
-			if outputFile != "" {
+			if check {
+				if outputFile == "" {
+					outputFile = "spin.toml"
+				}
+				return checkManifest(outputFile, manifest)
+			}
+
+			// Output result; "-" is stdout, like the input
+			if outputFile != "" && outputFile != "-" {
 				err = os.WriteFile(outputFile, []byte(manifest), 0600)
 				if err != nil {
 					return fmt.Errorf("failed to write output file: %w", err)
@@ -94,11 +113,65 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file, - for stdout (default: stdout)")
+	cmd.Flags().BoolVar(&check, "check", false, "Compare the output file (default spin.toml) with the synthesized manifest and fail if it is out of date")
 
 	return cmd
 }
 
+// synthCheckResult is the machine-readable form of 'ftl synth --check'
+type synthCheckResult struct {
+	File     string `json:"file"`
+	UpToDate bool   `json:"up_to_date"`
+	Diff     string `json:"diff,omitempty"`
+}
+
+// checkManifest compares the manifest at path with a freshly synthesized
+// one, printing a unified diff and failing when they differ. It lets hooks
+// and CI keep a committed spin.toml in sync with its configuration.
+func checkManifest(path, manifest string) error {
+	committed, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s does not exist; run 'ftl synth -o %s' to generate it", path, path)
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	result := synthCheckResult{File: path, UpToDate: string(committed) == manifest}
+	if !result.UpToDate {
+		result.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(committed)),
+			B:        difflib.SplitLines(manifest),
+			FromFile: path,
+			ToFile:   path + " (synthesized)",
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to diff %s: %w", path, err)
+		}
+	}
+
+	if structuredFormat() != "" {
+		if err := writeResult(result); err != nil {
+			return err
+		}
+	} else if result.UpToDate {
+		Success("%s is up to date", path)
+	} else {
+		_, _ = fmt.Fprint(colorOutput, result.Diff)
+	}
+
+	if !result.UpToDate {
+		err := fmt.Errorf("%s is out of date; run 'ftl synth -o %s' to regenerate it", path, path)
+		if structuredFormat() != "" {
+			return &reportedError{err}
+		}
+		return err
+	}
+	return nil
+}
+
 // synthesizeFromInput detects the format and synthesizes accordingly
 func synthesizeFromInput(input []byte, args []string) (string, error) {
 	// Detect format based on file extension or content
@@ -227,6 +300,37 @@ func synthesizeFromCUE(input []byte) (string, error) {
 	return synth.SynthesizeCUE(string(input))
 }
 
+// checkToolNames fails when local components of the YAML or JSON
+// configuration at path list the same tool in their component.yaml. Other
+// formats and registry components are not checked.
+func checkToolNames(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil
+	}
+	m, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	tools := make(map[string][]string)
+	for _, comp := range m.Components {
+		source, ok := comp.Source.(string)
+		if !ok {
+			continue
+		}
+		meta, err := oci.LoadMetadata(componentDir(filepath.Join(filepath.Dir(path), source)))
+		if err != nil {
+			return err
+		}
+		if meta != nil {
+			tools[comp.ID] = meta.Tools
+		}
+	}
+	return validation.CheckToolNames(tools)
+}
+
 // findConfigFile looks for FTL configuration files in priority order
 func findConfigFile() (string, error) {
 	// Define the search order for config files
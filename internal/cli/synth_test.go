@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -262,6 +263,82 @@ components: []`
 	t.Skip("Requires command execution framework")
 }
 
+func TestSynthCmd_Check(t *testing.T) {
+	chdirTemp(t)
+	buf := setGlobalOutput(t, "")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: check-app\ncomponents:\n  - id: tool\n    source: tool.wasm\n"), 0600))
+
+	// A missing manifest is reported rather than created
+	cmd := newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "--check"})
+	assert.ErrorContains(t, cmd.Execute(), "does not exist")
+
+	cmd = newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "-o", "spin.toml"})
+	require.NoError(t, cmd.Execute())
+
+	cmd = newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "--check"})
+	require.NoError(t, cmd.Execute())
+
+	// Drift fails with a diff of the committed manifest
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: check-app\ncomponents:\n  - id: tool\n    source: tool-v2.wasm\n"), 0600))
+	buf.Reset()
+	cmd = newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "--check"})
+	assert.ErrorContains(t, cmd.Execute(), "spin.toml is out of date")
+	assert.Contains(t, buf.String(), "--- spin.toml\n")
+	assert.Contains(t, buf.String(), "-source = 'tool.wasm'")
+	assert.Contains(t, buf.String(), "+source = 'tool-v2.wasm'")
+}
+
+func TestSynthCmd_OutputToStdout(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: stdout-app\ncomponents:\n  - id: tool\n    source: tool.wasm\n"), 0600))
+
+	cmd := newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "-o", "-"})
+	require.NoError(t, cmd.Execute())
+
+	_, err := os.Stat("-")
+	assert.True(t, os.IsNotExist(err), "-o - must not create a file named -")
+}
+
+func TestSynthCmd_DuplicateToolNames(t *testing.T) {
+	chdirTemp(t)
+	setGlobalOutput(t, "")
+	require.NoError(t, os.WriteFile("ftl.yaml", []byte("name: tools-app\ncomponents:\n  - id: weather\n    source: weather/app.wasm\n  - id: geo\n    source: geo/app.wasm\n"), 0600))
+	for _, dir := range []string{"weather", "geo"} {
+		require.NoError(t, os.MkdirAll(dir, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "component.yaml"), []byte("tools: [lookup, "+dir+"]\n"), 0600))
+	}
+
+	cmd := newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "-o", "spin.toml"})
+	assert.ErrorContains(t, cmd.Execute(), `tool "lookup" is provided by more than one component: geo, weather`)
+
+	require.NoError(t, os.WriteFile(filepath.Join("geo", "component.yaml"), []byte("tools: [geocode]\n"), 0600))
+	cmd = newSynthCmd()
+	cmd.SetArgs([]string{"ftl.yaml", "-o", "spin.toml"})
+	require.NoError(t, cmd.Execute())
+}
+
+func TestCheckManifest_JSON(t *testing.T) {
+	chdirTemp(t)
+	buf := setGlobalOutput(t, "json")
+	require.NoError(t, os.WriteFile("spin.toml", []byte("a\nb\n"), 0600))
+
+	err := checkManifest("spin.toml", "a\nc\n")
+	var reported *reportedError
+	assert.ErrorAs(t, err, &reported)
+
+	var result synthCheckResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.False(t, result.UpToDate)
+	assert.Contains(t, result.Diff, "+c")
+}
+
 func TestSynthCmd_StdinInput(t *testing.T) {
 	// This test would verify that the --stdin flag works correctly
 	// It would pipe YAML content through stdin and verify output
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/fastertools/ftl/internal/config"
+	"github.com/fastertools/ftl/internal/telemetry"
+)
+
+// telemetryNotice describes exactly what is collected when telemetry is on
+const telemetryNotice = `When enabled, ftl sends one event per command with:
+  - the command name (e.g. "ftl deploy"), never its arguments or flag values
+  - how long it took and whether it succeeded
+  - a coarse error category (usage, auth, network, timeout, canceled, other)
+  - the ftl version, OS, architecture and whether it ran in CI
+  - a random anonymous ID generated when telemetry was enabled
+
+Set FTL_TELEMETRY=off or DO_NOT_TRACK=1 to disable it for a single environment.`
+
+func newTelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage metrics",
+		Long: `Manage anonymous usage metrics.
+
+Telemetry is off by default and is only sent after you turn it on.
+
+` + telemetryNotice,
+	}
+
+	cmd.AddCommand(
+		newTelemetryOnCmd(),
+		newTelemetryOffCmd(),
+		newTelemetryStatusCmd(),
+	)
+
+	return cmd
+}
+
+func newTelemetryOnCmd() *cobra.Command {
+	var endpoint string
+
+	cmd := &cobra.Command{
+		Use:   "on",
+		Short: "Enable anonymous usage metrics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			return enableTelemetry(cfg, endpoint)
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", os.Getenv("FTL_TELEMETRY_ENDPOINT"), "URL that receives usage events")
+
+	return cmd
+}
+
+func newTelemetryOffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "off",
+		Short: "Disable anonymous usage metrics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			return disableTelemetry(cfg)
+		},
+	}
+}
+
+func newTelemetryStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether usage metrics are enabled",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			return showTelemetryStatus(cfg)
+		},
+	}
+}
+
+func enableTelemetry(cfg *config.Config, endpoint string) error {
+	settings := cfg.GetTelemetry()
+	if endpoint == "" {
+		endpoint = settings.Endpoint
+	}
+	if endpoint == "" {
+		return &usageError{fmt.Errorf("no telemetry endpoint configured: pass --endpoint or set FTL_TELEMETRY_ENDPOINT")}
+	}
+	if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+		return &usageError{fmt.Errorf("invalid telemetry endpoint '%s': must be an http or https URL", endpoint)}
+	}
+
+	settings.Enabled = true
+	settings.Endpoint = endpoint
+	if settings.AnonymousID == "" {
+		settings.AnonymousID = uuid.NewString()
+	}
+	if err := cfg.SetTelemetry(settings); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	Success("Telemetry enabled, sending to %s", endpoint)
+	_, _ = fmt.Fprintln(messageOutput(), telemetryNotice)
+	return nil
+}
+
+func disableTelemetry(cfg *config.Config) error {
+	settings := cfg.GetTelemetry()
+	settings.Enabled = false
+	if err := cfg.SetTelemetry(settings); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	Success("Telemetry disabled")
+	return nil
+}
+
+// telemetryStatus is the machine-readable form of 'ftl telemetry status'
+type telemetryStatus struct {
+	Enabled       bool   `json:"enabled"`
+	Endpoint      string `json:"endpoint,omitempty"`
+	DisabledByEnv bool   `json:"disabled_by_env"`
+}
+
+func showTelemetryStatus(cfg *config.Config) error {
+	settings := cfg.GetTelemetry()
+	status := telemetryStatus{
+		Enabled:       settings.Enabled && !telemetry.DisabledByEnv(),
+		Endpoint:      settings.Endpoint,
+		DisabledByEnv: settings.Enabled && telemetry.DisabledByEnv(),
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(status)
+	}
+
+	switch {
+	case status.Enabled:
+		_, _ = fmt.Fprintf(colorOutput, "Telemetry is enabled, sending to %s\n", status.Endpoint)
+	case status.DisabledByEnv:
+		_, _ = fmt.Fprintln(colorOutput, "Telemetry is enabled in your config but disabled by FTL_TELEMETRY or DO_NOT_TRACK")
+	default:
+		_, _ = fmt.Fprintln(colorOutput, "Telemetry is disabled. Run 'ftl telemetry on --endpoint <url>' to enable it.")
+	}
+	return nil
+}
+
+// recordTelemetry reports a finished command when the user has opted in.
+// Failures are ignored so telemetry can never affect a command's outcome.
+func recordTelemetry(settings config.TelemetryConfig, cmd *cobra.Command, start time.Time, err error) {
+	if !settings.Enabled || settings.Endpoint == "" || telemetry.DisabledByEnv() || cmd == nil {
+		return
+	}
+	// Never report managing telemetry itself or shell completion requests
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "telemetry" || strings.HasPrefix(c.Name(), "__") {
+			return
+		}
+	}
+
+	category := telemetry.CategorizeError(err)
+	var usage *usageError
+	if errors.As(err, &usage) {
+		category = telemetry.CategoryUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	event := telemetry.NewEvent(settings.AnonymousID, cmd.CommandPath(), version, time.Since(start), category)
+	if sendErr := telemetry.NewClient(settings.Endpoint).Send(ctx, event); sendErr != nil {
+		Debug("Telemetry not sent: %v", sendErr)
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/config"
+	"github.com/fastertools/ftl/internal/telemetry"
+)
+
+func TestEnableDisableTelemetry(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg := &config.Config{}
+
+	err := enableTelemetry(cfg, "")
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	err = enableTelemetry(cfg, "ftp://example.com")
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	assert.False(t, cfg.GetTelemetry().Enabled)
+
+	require.NoError(t, enableTelemetry(cfg, "https://telemetry.example.com/events"))
+	settings := cfg.GetTelemetry()
+	assert.True(t, settings.Enabled)
+	assert.Equal(t, "https://telemetry.example.com/events", settings.Endpoint)
+	assert.NotEmpty(t, settings.AnonymousID)
+
+	require.NoError(t, disableTelemetry(cfg))
+	assert.False(t, cfg.GetTelemetry().Enabled)
+
+	// Re-enabling keeps the endpoint and anonymous ID
+	require.NoError(t, enableTelemetry(cfg, ""))
+	assert.Equal(t, settings.Endpoint, cfg.GetTelemetry().Endpoint)
+	assert.Equal(t, settings.AnonymousID, cfg.GetTelemetry().AnonymousID)
+}
+
+func TestShowTelemetryStatus(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("FTL_TELEMETRY", "")
+	cfg := &config.Config{}
+
+	buf := setGlobalOutput(t, "")
+	require.NoError(t, showTelemetryStatus(cfg))
+	assert.Contains(t, buf.String(), "Telemetry is disabled")
+
+	cfg.Telemetry = config.TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.example.com"}
+	buf.Reset()
+	require.NoError(t, showTelemetryStatus(cfg))
+	assert.Contains(t, buf.String(), "Telemetry is enabled, sending to https://telemetry.example.com")
+
+	t.Setenv("DO_NOT_TRACK", "1")
+	buf = setGlobalOutput(t, "json")
+	require.NoError(t, showTelemetryStatus(cfg))
+	var status telemetryStatus
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &status))
+	assert.Equal(t, telemetryStatus{Enabled: false, Endpoint: "https://telemetry.example.com", DisabledByEnv: true}, status)
+}
+
+func TestRecordTelemetry(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("FTL_TELEMETRY", "")
+
+	var events []telemetry.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event telemetry.Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	root := &cobra.Command{Use: "ftl"}
+	deployCmd := &cobra.Command{Use: "deploy"}
+	telemetryCmd := &cobra.Command{Use: "telemetry"}
+	statusCmd := &cobra.Command{Use: "status"}
+	telemetryCmd.AddCommand(statusCmd)
+	root.AddCommand(deployCmd, telemetryCmd)
+
+	settings := config.TelemetryConfig{Enabled: true, Endpoint: server.URL, AnonymousID: "anon"}
+	start := time.Now().Add(-time.Second)
+
+	recordTelemetry(settings, deployCmd, start, nil)
+	recordTelemetry(settings, deployCmd, start, &usageError{errors.New("bad flag")})
+	recordTelemetry(settings, statusCmd, start, nil)
+	recordTelemetry(config.TelemetryConfig{Endpoint: server.URL}, deployCmd, start, nil)
+
+	t.Setenv("FTL_TELEMETRY", "off")
+	recordTelemetry(settings, deployCmd, start, nil)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "ftl deploy", events[0].Command)
+	assert.Equal(t, "anon", events[0].AnonymousID)
+	assert.True(t, events[0].Success)
+	assert.GreaterOrEqual(t, events[0].DurationMS, int64(1000))
+	assert.False(t, events[1].Success)
+	assert.Equal(t, telemetry.CategoryUsage, events[1].ErrorCategory)
+}
@@ -0,0 +1,808 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fastertools/ftl/internal/auth"
+	"github.com/fastertools/ftl/internal/manifest"
+)
+
+// ToolsExportOptions holds options for the tools export command
+type ToolsExportOptions struct {
+	Format     string
+	URL        string
+	Components []string
+	OutFile    string
+	Auth       bool
+	Timeout    time.Duration
+}
+
+func newToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Inspect and export application tools",
+		Long:  `Inspect and export the MCP tools served by an FTL application.`,
+	}
+
+	cmd.AddCommand(newToolsExportCmd())
+
+	return cmd
+}
+
+func newToolsExportCmd() *cobra.Command {
+	opts := &ToolsExportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Generate an API description or client bindings for tools",
+		Long: `Generate an OpenAPI document or typed client stubs for the tools of an app.
+
+Tool names and JSON schemas are read from a running app ('ftl up' locally, or
+a deployed MCP URL). By default every component listed in ftl.yaml is
+exported; use --component to limit the export to specific components.
+
+Formats:
+  openapi     OpenAPI 3.1 document (YAML or JSON, based on --out-file extension)
+  typescript  TypeScript types and a fetch-based client
+  python      Python TypedDicts and a urllib-based client
+
+Examples:
+  # Export an OpenAPI document from the local app
+  ftl tools export --format openapi --out-file tools.yaml
+
+  # Generate a TypeScript client for one component
+  ftl tools export --format typescript --component weather --out-file weather.ts`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runToolsExport(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Format, "format", "f", "openapi", "output format (openapi, typescript, python)")
+	cmd.Flags().StringVarP(&opts.URL, "url", "u", defaultCallURL, "MCP endpoint URL")
+	cmd.Flags().StringSliceVarP(&opts.Components, "component", "c", nil, "only export tools from these components")
+	cmd.Flags().StringVar(&opts.OutFile, "out-file", "", "file to write the export to (default: stdout)")
+	cmd.Flags().BoolVar(&opts.Auth, "auth", false, "send FTL credentials with the request")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "request timeout")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFixed("openapi", "typescript", "python"))
+	_ = cmd.RegisterFlagCompletionFunc("component", completeComponentNames)
+
+	return cmd
+}
+
+func runToolsExport(ctx context.Context, opts *ToolsExportOptions) error {
+	tools, appName, appVersion, err := fetchTools(ctx, opts.URL, opts.Auth, opts.Components, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	switch opts.Format {
+	case "openapi":
+		out, err = generateOpenAPI(appName, appVersion, tools, strings.HasSuffix(opts.OutFile, ".json"))
+	case "typescript", "ts":
+		out = []byte(generateTypeScriptClient(tools))
+	case "python", "py":
+		out = []byte(generatePythonClient(tools))
+	default:
+		return fmt.Errorf("unsupported format '%s': must be one of openapi, typescript, python", opts.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.OutFile == "" {
+		_, err = colorOutput.Write(out)
+		return err
+	}
+	if err := os.WriteFile(opts.OutFile, out, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.OutFile, err)
+	}
+	Success("Exported %d tools to %s", len(tools), opts.OutFile)
+	return nil
+}
+
+// fetchTools lists the tools of the app at url, limited to components, or
+// to the components of ftl.yaml when none are given. It also returns the
+// app's name and version from ftl.yaml, with defaults when there is none.
+func fetchTools(ctx context.Context, url string, useAuth bool, components []string, timeout time.Duration) ([]mcpTool, string, string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	appName := "ftl-app"
+	appVersion := "0.1.0"
+	if m, err := manifest.LoadAuto(); err == nil {
+		appName = m.Name
+		if m.Version != "" {
+			appVersion = m.Version
+		}
+		if len(components) == 0 {
+			for _, comp := range m.Components {
+				components = append(components, comp.ID)
+			}
+		}
+	}
+
+	client := newMCPCaller(url)
+	if useAuth {
+		store, err := auth.NewKeyringStore()
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to initialize credential store: %w", err)
+		}
+		token, err := auth.NewManager(store, nil).GetOrRefreshToken(ctx)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("not logged in to FTL. Run 'ftl auth login' first")
+		}
+		client.token = token
+	}
+
+	if err := client.initialize(ctx); err != nil {
+		return nil, "", "", fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+
+	tools, err := client.listTools(ctx)
+	if err != nil {
+		return nil, "", "", err
+	}
+	tools = filterToolsByComponent(tools, components)
+	if len(tools) == 0 {
+		return nil, "", "", fmt.Errorf("no tools found at %s", url)
+	}
+	return tools, appName, appVersion, nil
+}
+
+// filterToolsByComponent keeps tools whose gateway prefix (component__tool)
+// matches one of the given components. An empty list keeps everything.
+func filterToolsByComponent(tools []mcpTool, components []string) []mcpTool {
+	if len(components) == 0 {
+		return tools
+	}
+
+	allowed := make(map[string]bool, len(components))
+	for _, c := range components {
+		allowed[strings.ReplaceAll(c, "-", "_")] = true
+	}
+
+	var filtered []mcpTool
+	for _, tool := range tools {
+		prefix, _, ok := strings.Cut(tool.Name, "__")
+		if !ok || allowed[strings.ReplaceAll(prefix, "-", "_")] {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// generateOpenAPI describes each tool as a POST operation whose request body
+// is the tool's input schema. Operations are invoked through MCP tools/call.
+func generateOpenAPI(appName, appVersion string, tools []mcpTool, asJSON bool) ([]byte, error) {
+	paths := make(map[string]interface{}, len(tools))
+	for _, tool := range tools {
+		inputSchema := tool.InputSchema
+		if inputSchema == nil {
+			inputSchema = map[string]interface{}{"type": "object"}
+		}
+
+		successSchema := map[string]interface{}{"$ref": "#/components/schemas/ToolResult"}
+		if tool.OutputSchema != nil {
+			successSchema = tool.OutputSchema
+		}
+
+		operation := map[string]interface{}{
+			"operationId": tool.Name,
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": inputSchema},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Tool result",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": successSchema},
+					},
+				},
+			},
+		}
+		if tool.Title != "" {
+			operation["summary"] = tool.Title
+		}
+		if tool.Description != "" {
+			operation["description"] = tool.Description
+		}
+		if prefix, _, ok := strings.Cut(tool.Name, "__"); ok {
+			operation["tags"] = []string{prefix}
+		}
+
+		paths["/tools/"+tool.Name] = map[string]interface{}{"post": operation}
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       appName,
+			"version":     appVersion,
+			"description": "MCP tools exported by FTL. Each operation corresponds to an MCP tools/call request with the operationId as the tool name.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"ToolResult": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"content":           map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+						"structuredContent": map[string]interface{}{"type": "object"},
+						"isError":           map[string]interface{}{"type": "boolean"},
+					},
+				},
+			},
+		},
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal OpenAPI document: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI document: %w", err)
+	}
+	return data, nil
+}
+
+func generateTypeScriptClient(tools []mcpTool) string {
+	var b strings.Builder
+	b.WriteString("// Code generated by ftl tools export. DO NOT EDIT.\n\n")
+	b.WriteString(`export interface ToolResult<T = unknown> {
+  content: Array<{ type: string; text?: string; [key: string]: unknown }>;
+  structuredContent?: T;
+  isError?: boolean;
+}
+
+`)
+
+	for _, tool := range tools {
+		typeName := toPascalIdentifier(tool.Name)
+		writeDocComment(&b, "", tool.Description, "/** ", " */")
+		fmt.Fprintf(&b, "export type %sInput = %s;\n\n", typeName, tsType(tool.InputSchema, ""))
+		if tool.OutputSchema != nil {
+			fmt.Fprintf(&b, "export type %sOutput = %s;\n\n", typeName, tsType(tool.OutputSchema, ""))
+		}
+	}
+
+	b.WriteString(`export class ToolsClient {
+  private nextId = 1;
+  private initialized = false;
+  private sessionId?: string;
+
+  constructor(
+    private readonly url: string,
+    private readonly headers: Record<string, string> = {},
+  ) {}
+
+  private async rpc(method: string, params?: unknown, notify = false): Promise<any> {
+    const message: Record<string, unknown> = { jsonrpc: "2.0", method, params };
+    if (!notify) message.id = this.nextId++;
+    const res = await fetch(this.url, {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        Accept: "application/json, text/event-stream",
+        ...(this.sessionId ? { "Mcp-Session-Id": this.sessionId } : {}),
+        ...this.headers,
+      },
+      body: JSON.stringify(message),
+    });
+    const sessionId = res.headers.get("Mcp-Session-Id");
+    if (sessionId) this.sessionId = sessionId;
+    if (notify) return undefined;
+    if (!res.ok) throw new Error(` + "`${method} failed: ${res.status} ${await res.text()}`" + `);
+    const body = await this.readResponse(res, message.id);
+    if (body.error) throw new Error(` + "`${method} failed: ${body.error.message}`" + `);
+    return body.result;
+  }
+
+  // Streamed responses carry JSON-RPC messages as server-sent events; the
+  // response is the message with the request's ID
+  private async readResponse(res: Response, id: unknown): Promise<any> {
+    if (!(res.headers.get("Content-Type") ?? "").includes("text/event-stream")) {
+      return res.json();
+    }
+    const reader = res.body!.getReader();
+    const decoder = new TextDecoder();
+    let buffer = "";
+    for (;;) {
+      const { done, value } = await reader.read();
+      buffer += decoder.decode(value, { stream: !done });
+      const events = buffer.split(/\r?\n\r?\n/);
+      buffer = done ? "" : events.pop()!;
+      for (const event of events) {
+        const data = event
+          .split(/\r?\n/)
+          .filter((line) => line.startsWith("data:"))
+          .map((line) => line.slice(line.startsWith("data: ") ? 6 : 5))
+          .join("\n");
+        if (!data) continue;
+        const message = JSON.parse(data);
+        if (message.id === id) {
+          await reader.cancel();
+          return message;
+        }
+      }
+      if (done) throw new Error("event stream ended without a response");
+    }
+  }
+
+  async callTool<T = unknown>(name: string, args: unknown): Promise<ToolResult<T>> {
+    if (!this.initialized) {
+      await this.rpc("initialize", {
+        protocolVersion: "` + mcpProtocolVersion + `",
+        capabilities: {},
+        clientInfo: { name: "ftl-generated-client", version: "0.1.0" },
+      });
+      await this.rpc("notifications/initialized", undefined, true);
+      this.initialized = true;
+    }
+    return this.rpc("tools/call", { name, arguments: args });
+  }
+`)
+
+	for _, tool := range tools {
+		typeName := toPascalIdentifier(tool.Name)
+		output := "unknown"
+		if tool.OutputSchema != nil {
+			output = typeName + "Output"
+		}
+		b.WriteString("\n")
+		writeDocComment(&b, "  ", tool.Description, "/** ", " */")
+		fmt.Fprintf(&b, "  %s(args: %sInput): Promise<ToolResult<%s>> {\n", toCamelIdentifier(tool.Name), typeName, output)
+		fmt.Fprintf(&b, "    return this.callTool(%q, args);\n", tool.Name)
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// tsType converts a JSON schema into a TypeScript type expression
+func tsType(schema map[string]interface{}, indent string) string {
+	if schema == nil {
+		return "Record<string, unknown>"
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			data, _ := json.Marshal(v)
+			values = append(values, string(data))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	switch t := schema["type"].(type) {
+	case []interface{}:
+		var parts []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				sub := copySchema(schema)
+				sub["type"] = s
+				parts = append(parts, tsType(sub, indent))
+			}
+		}
+		return strings.Join(parts, " | ")
+	case string:
+		switch t {
+		case "string":
+			return "string"
+		case "integer", "number":
+			return "number"
+		case "boolean":
+			return "boolean"
+		case "null":
+			return "null"
+		case "array":
+			items, _ := schema["items"].(map[string]interface{})
+			if items == nil {
+				return "unknown[]"
+			}
+			return "Array<" + tsType(items, indent) + ">"
+		case "object":
+			return tsObjectType(schema, indent)
+		}
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return tsObjectType(schema, indent)
+	}
+	return "unknown"
+}
+
+func tsObjectType(schema map[string]interface{}, indent string) string {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return "Record<string, unknown>"
+	}
+
+	required := requiredSet(schema)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(props) {
+		prop, _ := props[name].(map[string]interface{})
+		if desc, ok := prop["description"].(string); ok && desc != "" {
+			writeDocComment(&b, indent+"  ", desc, "/** ", " */")
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		key := name
+		if !isIdentifier(name) {
+			key = fmt.Sprintf("%q", name)
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, key, optional, tsType(prop, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func generatePythonClient(tools []mcpTool) string {
+	var b strings.Builder
+	b.WriteString(`# Code generated by ftl tools export. DO NOT EDIT.
+
+import json
+import urllib.request
+from typing import Any, Dict, List, Literal, Optional, TypedDict, Union
+
+`)
+
+	for _, tool := range tools {
+		typeName := toPascalIdentifier(tool.Name)
+		writePythonTypedDict(&b, typeName+"Input", tool.InputSchema)
+		if tool.OutputSchema != nil {
+			writePythonTypedDict(&b, typeName+"Output", tool.OutputSchema)
+		}
+	}
+
+	b.WriteString(`class ToolsClient:
+    def __init__(self, url: str, headers: Optional[Dict[str, str]] = None) -> None:
+        self.url = url
+        self.headers = headers or {}
+        self._next_id = 1
+        self._initialized = False
+        self._session_id: Optional[str] = None
+
+    def _rpc(self, method: str, params: Any = None, notify: bool = False) -> Any:
+        message: Dict[str, Any] = {"jsonrpc": "2.0", "method": method}
+        if params is not None:
+            message["params"] = params
+        if not notify:
+            message["id"] = self._next_id
+            self._next_id += 1
+        headers = {
+            "Content-Type": "application/json",
+            "Accept": "application/json, text/event-stream",
+        }
+        if self._session_id:
+            headers["Mcp-Session-Id"] = self._session_id
+        request = urllib.request.Request(
+            self.url,
+            data=json.dumps(message).encode(),
+            headers={**headers, **self.headers},
+            method="POST",
+        )
+        with urllib.request.urlopen(request) as response:
+            session_id = response.headers.get("Mcp-Session-Id")
+            if session_id:
+                self._session_id = session_id
+            if notify:
+                return None
+            body = self._read_response(response, message["id"])
+        if "error" in body:
+            raise RuntimeError(f"{method} failed: {body['error']['message']}")
+        return body["result"]
+
+    @staticmethod
+    def _read_response(response: Any, request_id: Any) -> Any:
+        # Streamed responses carry JSON-RPC messages as server-sent events;
+        # the response is the message with the request's ID
+        if "text/event-stream" not in response.headers.get("Content-Type", ""):
+            return json.loads(response.read())
+        data: List[str] = []
+        for raw in response:
+            line = raw.decode().rstrip("\r\n")
+            if line.startswith("data:"):
+                data.append(line[6:] if line.startswith("data: ") else line[5:])
+            elif not line and data:
+                message = json.loads("\n".join(data))
+                data = []
+                if message.get("id") == request_id:
+                    return message
+        raise RuntimeError("event stream ended without a response")
+
+    def call_tool(self, name: str, arguments: Any) -> Dict[str, Any]:
+        if not self._initialized:
+            self._rpc(
+                "initialize",
+                {
+                    "protocolVersion": "` + mcpProtocolVersion + `",
+                    "capabilities": {},
+                    "clientInfo": {"name": "ftl-generated-client", "version": "0.1.0"},
+                },
+            )
+            self._rpc("notifications/initialized", notify=True)
+            self._initialized = True
+        return self._rpc("tools/call", {"name": name, "arguments": arguments})
+`)
+
+	for _, tool := range tools {
+		fmt.Fprintf(&b, "\n    def %s(self, args: %sInput) -> Dict[str, Any]:\n", toSnakeIdentifier(tool.Name), toPascalIdentifier(tool.Name))
+		if tool.Description != "" {
+			fmt.Fprintf(&b, "        %q\n", strings.TrimSpace(tool.Description))
+		}
+		fmt.Fprintf(&b, "        return self.call_tool(%q, args)\n", tool.Name)
+	}
+
+	return b.String()
+}
+
+func writePythonTypedDict(b *strings.Builder, name string, schema map[string]interface{}) {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		fmt.Fprintf(b, "%s = Dict[str, Any]\n\n\n", name)
+		return
+	}
+
+	// Optional fields need total=False; keep required and optional in separate classes
+	required := requiredSet(schema)
+	var req, opt []string
+	for _, prop := range sortedKeys(props) {
+		if required[prop] {
+			req = append(req, prop)
+		} else {
+			opt = append(opt, prop)
+		}
+	}
+
+	for _, prop := range append(append([]string{}, req...), opt...) {
+		if !isPythonIdentifier(prop) {
+			writePythonFunctionalTypedDict(b, name, schema, req, opt)
+			return
+		}
+	}
+
+	base := "TypedDict"
+	if len(req) > 0 && len(opt) > 0 {
+		fmt.Fprintf(b, "class _%sRequired(TypedDict):\n", name)
+		for _, prop := range req {
+			p, _ := props[prop].(map[string]interface{})
+			fmt.Fprintf(b, "    %s: %s\n", prop, pyType(p))
+		}
+		b.WriteString("\n\n")
+		base = "_" + name + "Required"
+		req = nil
+	}
+
+	if len(req) > 0 {
+		fmt.Fprintf(b, "class %s(%s):\n", name, base)
+	} else {
+		fmt.Fprintf(b, "class %s(%s, total=False):\n", name, base)
+	}
+	if desc, ok := schema["description"].(string); ok && desc != "" {
+		fmt.Fprintf(b, "    %q\n", desc)
+	}
+	for _, prop := range append(req, opt...) {
+		p, _ := props[prop].(map[string]interface{})
+		fmt.Fprintf(b, "    %s: %s\n", prop, pyType(p))
+	}
+	b.WriteString("\n\n")
+}
+
+// writePythonFunctionalTypedDict writes a TypedDict with the functional
+// syntax, for properties that are not Python identifiers, such as
+// "max-results" or "from"
+func writePythonFunctionalTypedDict(b *strings.Builder, name string, schema map[string]interface{}, req, opt []string) {
+	props, _ := schema["properties"].(map[string]interface{})
+	typedDict := func(name string, fields []string, total bool) {
+		fmt.Fprintf(b, "%s = TypedDict(\n    %q,\n    {\n", name, name)
+		for _, prop := range fields {
+			p, _ := props[prop].(map[string]interface{})
+			fmt.Fprintf(b, "        %q: %s,\n", prop, pyType(p))
+		}
+		if total {
+			b.WriteString("    },\n)\n\n\n")
+		} else {
+			b.WriteString("    },\n    total=False,\n)\n\n\n")
+		}
+	}
+
+	desc, _ := schema["description"].(string)
+	if len(req) == 0 || len(opt) == 0 {
+		if desc != "" {
+			writePythonComment(b, desc)
+		}
+		typedDict(name, append(req, opt...), len(opt) == 0)
+		return
+	}
+
+	typedDict("_"+name+"Required", req, true)
+	typedDict("_"+name+"Optional", opt, false)
+	fmt.Fprintf(b, "class %s(_%sRequired, _%sOptional):\n", name, name, name)
+	if desc != "" {
+		fmt.Fprintf(b, "    %q\n", desc)
+	} else {
+		b.WriteString("    pass\n")
+	}
+	b.WriteString("\n\n")
+}
+
+// writePythonComment writes text as Python comment lines
+func writePythonComment(b *strings.Builder, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+}
+
+// pythonKeywords cannot be used as Python identifiers
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "else": true, "except": true, "finally": true,
+	"for": true, "from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true,
+	"raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// isPythonIdentifier reports whether s can name a TypedDict field in a
+// class body
+func isPythonIdentifier(s string) bool {
+	if s == "" || pythonKeywords[s] {
+		return false
+	}
+	for i, r := range s {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// pyType converts a JSON schema into a Python type annotation
+func pyType(schema map[string]interface{}) string {
+	if schema == nil {
+		return "Any"
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			data, _ := json.Marshal(v)
+			values = append(values, string(data))
+		}
+		return "Literal[" + strings.Join(values, ", ") + "]"
+	}
+
+	switch t := schema["type"].(type) {
+	case []interface{}:
+		var parts []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				sub := copySchema(schema)
+				sub["type"] = s
+				parts = append(parts, pyType(sub))
+			}
+		}
+		return "Union[" + strings.Join(parts, ", ") + "]"
+	case string:
+		switch t {
+		case "string":
+			return "str"
+		case "integer":
+			return "int"
+		case "number":
+			return "float"
+		case "boolean":
+			return "bool"
+		case "null":
+			return "None"
+		case "array":
+			items, _ := schema["items"].(map[string]interface{})
+			return "List[" + pyType(items) + "]"
+		case "object":
+			return "Dict[str, Any]"
+		}
+	}
+	return "Any"
+}
+
+func writeDocComment(b *strings.Builder, indent, text, open, close string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	text = strings.ReplaceAll(text, "*/", "* /")
+	b.WriteString(indent + open + strings.ReplaceAll(text, "\n", "\n"+indent+" * ") + close + "\n")
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, r := range list {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	return required
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copySchema(schema map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		c[k] = v
+	}
+	return c
+}
+
+// identifierWords splits a tool name such as "weather__get-forecast" into words
+func identifierWords(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func toPascalIdentifier(name string) string {
+	var b strings.Builder
+	for _, w := range identifierWords(name) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+func toCamelIdentifier(name string) string {
+	p := toPascalIdentifier(name)
+	if p == "" {
+		return p
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
+
+func toSnakeIdentifier(name string) string {
+	return strings.ToLower(strings.Join(identifierWords(name), "_"))
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var testTools = []mcpTool{
+	{
+		Name:        "weather__get_forecast",
+		Description: "Get the forecast for a city",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city":  map[string]interface{}{"type": "string", "description": "City name"},
+				"days":  map[string]interface{}{"type": "integer"},
+				"units": map[string]interface{}{"enum": []interface{}{"metric", "imperial"}},
+			},
+			"required": []interface{}{"city"},
+		},
+		OutputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"temps": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "number"}},
+			},
+		},
+	},
+	{
+		Name: "echo__echo",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+			"required":   []interface{}{"message"},
+		},
+	},
+}
+
+func TestFilterToolsByComponent(t *testing.T) {
+	assert.Len(t, filterToolsByComponent(testTools, nil), 2)
+
+	filtered := filterToolsByComponent(testTools, []string{"weather"})
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "weather__get_forecast", filtered[0].Name)
+
+	// Component IDs use hyphens while the gateway may use underscores
+	tools := []mcpTool{{Name: "my_tool__run"}}
+	assert.Len(t, filterToolsByComponent(tools, []string{"my-tool"}), 1)
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	out, err := generateOpenAPI("my-app", "1.0.0", testTools, false)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out, &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	paths := doc["paths"].(map[string]interface{})
+	require.Contains(t, paths, "/tools/weather__get_forecast")
+	op := paths["/tools/weather__get_forecast"].(map[string]interface{})["post"].(map[string]interface{})
+	assert.Equal(t, "weather__get_forecast", op["operationId"])
+	assert.Equal(t, []interface{}{"weather"}, op["tags"])
+
+	jsonOut, err := generateOpenAPI("my-app", "1.0.0", testTools, true)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(jsonOut))
+}
+
+func TestGenerateTypeScriptClient(t *testing.T) {
+	out := generateTypeScriptClient(testTools)
+
+	assert.Contains(t, out, "export type WeatherGetForecastInput = {")
+	assert.Contains(t, out, "  city: string;")
+	assert.Contains(t, out, "  days?: number;")
+	assert.Contains(t, out, `  units?: "metric" | "imperial";`)
+	assert.Contains(t, out, "  temps?: Array<number>;")
+	assert.Contains(t, out, "weatherGetForecast(args: WeatherGetForecastInput): Promise<ToolResult<WeatherGetForecastOutput>>")
+	assert.Contains(t, out, "echoEcho(args: EchoEchoInput): Promise<ToolResult<unknown>>")
+	assert.Contains(t, out, `res.headers.get("Mcp-Session-Id")`)
+	assert.Contains(t, out, `.includes("text/event-stream")`)
+}
+
+func TestGeneratePythonClient(t *testing.T) {
+	out := generatePythonClient(testTools)
+
+	assert.Contains(t, out, "class _WeatherGetForecastInputRequired(TypedDict):\n    city: str\n")
+	assert.Contains(t, out, "class WeatherGetForecastInput(_WeatherGetForecastInputRequired, total=False):")
+	assert.Contains(t, out, `    units: Literal["metric", "imperial"]`)
+	assert.Contains(t, out, "class EchoEchoInput(TypedDict):\n    message: str\n")
+	assert.Contains(t, out, "def weather_get_forecast(self, args: WeatherGetForecastInput)")
+	assert.Contains(t, out, `response.headers.get("Mcp-Session-Id")`)
+	assert.Contains(t, out, `"text/event-stream" not in response.headers.get("Content-Type", "")`)
+}
+
+func TestRunToolsExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg["id"] == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		result := map[string]interface{}{}
+		if msg["method"] == "tools/list" {
+			result["tools"] = testTools
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": msg["id"], "result": result})
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	err := runToolsExport(context.Background(), &ToolsExportOptions{
+		Format:     "typescript",
+		URL:        server.URL,
+		Components: []string{"echo"},
+		Timeout:    5 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "echoEcho(")
+	assert.NotContains(t, buf.String(), "weatherGetForecast(")
+
+	outFile := filepath.Join(tmpDir, "tools.json")
+	err = runToolsExport(context.Background(), &ToolsExportOptions{
+		Format:  "openapi",
+		URL:     server.URL,
+		OutFile: outFile,
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+
+	err = runToolsExport(context.Background(), &ToolsExportOptions{
+		Format:  "java",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+	})
+	assert.ErrorContains(t, err, "unsupported format")
+}
+
+func TestGeneratePythonClient_FieldNames(t *testing.T) {
+	tools := []mcpTool{{
+		Name: "mail__send",
+		InputSchema: map[string]interface{}{
+			"type":        "object",
+			"description": "Message to send",
+			"properties": map[string]interface{}{
+				"from":        map[string]interface{}{"type": "string"},
+				"class":       map[string]interface{}{"type": "string"},
+				"max-results": map[string]interface{}{"type": "integer"},
+			},
+			"required": []interface{}{"from"},
+		},
+		OutputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"message-id": map[string]interface{}{"type": "string"}},
+		},
+	}}
+	out := generatePythonClient(tools)
+
+	assert.Contains(t, out, "_MailSendInputRequired = TypedDict(\n    \"_MailSendInputRequired\",\n    {\n        \"from\": str,\n    },\n)\n")
+	assert.Contains(t, out, "        \"class\": str,\n        \"max-results\": int,\n    },\n    total=False,\n)\n")
+	assert.Contains(t, out, "class MailSendInput(_MailSendInputRequired, _MailSendInputOptional):\n    \"Message to send\"\n")
+	assert.Contains(t, out, "MailSendOutput = TypedDict(\n    \"MailSendOutput\",\n    {\n        \"message-id\": str,\n    },\n    total=False,\n)\n")
+	assert.NotContains(t, out, "    from: str")
+}
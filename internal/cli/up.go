@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"time"
 
+	"github.com/fastertools/ftl/internal/buildcache"
+	"github.com/fastertools/ftl/internal/manifest"
 	"github.com/fastertools/ftl/spin"
 	"github.com/fastertools/ftl/synthesis"
 	"github.com/fatih/color"
@@ -14,6 +18,10 @@ import (
 func newUpCmd() *cobra.Command {
 	var build bool
 	var watch bool
+	var debounce time.Duration
+	var mockAuth bool
+	var mockSubject string
+	var mockClaims []string
 	var skipSynth bool
 	var configFile string
 
@@ -38,14 +46,56 @@ func newUpCmd() *cobra.Command {
 	var sqlite []string
 	var stateDir string
 	var listen string
+	var fresh bool
+
+	// Multi-app flags
+	var apps []string
+	var portRange string
 
 	cmd := &cobra.Command{
 		Use:   "up",
 		Short: "Run the FTL application locally",
-		Long:  `Run the FTL application locally with hot reload support.`,
+		Long: `Run the FTL application locally with hot reload support.
+
+Unless --listen is given, the application is served on the first free port in
+--port-range, so several apps can run side by side without port conflicts.
+
+Use --app to run multiple FTL projects at once. Each project runs on its own
+free port behind a local reverse proxy that routes by app name:
+
+  ftl up --app ./weather --app ./search
+  # http://127.0.0.1:3000/weather/mcp
+  # http://127.0.0.1:3000/search/mcp
+
+With --app only --build, --listen and --port-range apply; other flags are
+rejected.
+
+With --watch, ftl.yaml and ftl.json projects rebuild only the component whose
+build.watch patterns matched a change, once files have stopped changing for
+--debounce. The app restarts after a successful build; when a build fails the
+compiler output is shown and the previous version keeps serving. Changes to
+the FTL config itself need a restart.
+
+Apps with private, org or custom access need a token for every request. With
+--mock-auth the authorizer instead accepts every request as a fake identity,
+so authenticated code paths can be tried locally; policies still apply:
+
+  ftl up --mock-auth --mock-sub alice --mock-claim org_id=org_123 --mock-claim 'scope=read write'
+
+Components keep key-value and SQLite state between runs in --state-dir
+(.spin by default). Spin provides the stores labelled "default" itself;
+for other key_value_stores and sqlite_databases labels, ftl up generates a
+runtime config backing them with local files, unless --runtime-config-file
+is given. --fresh removes the stored state before starting.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
+			if len(apps) > 0 {
+				if err := checkMultiAppFlags(cmd.LocalNonPersistentFlags()); err != nil {
+					return err
+				}
+			}
+
 			// Color helpers
 			blue := color.New(color.FgBlue).SprintFunc()
 			green := color.New(color.FgGreen).SprintFunc()
@@ -56,6 +106,30 @@ func newUpCmd() *cobra.Command {
 				return err
 			}
 
+			portStart, portEnd, err := parsePortRange(portRange)
+			if err != nil {
+				return err
+			}
+
+			// Select a free port unless an address was given explicitly
+			if listen == "" {
+				port, err := findFreePort(defaultUpHost, portStart, portEnd, nil)
+				if err != nil {
+					return err
+				}
+				if port != portStart {
+					fmt.Printf("%s Port %d is in use, using %d\n", yellow("ℹ"), portStart, port)
+				}
+				listen = fmt.Sprintf("%s:%d", defaultUpHost, port)
+			}
+
+			// Run several projects behind a reverse proxy
+			if len(apps) > 0 {
+				ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+				defer stop()
+				return runMultiApp(ctx, apps, listen, portStart, portEnd, build)
+			}
+
 			// Auto-detect config file if not specified
 			if configFile == "" {
 				// Try to detect the config format
@@ -105,7 +179,44 @@ func newUpCmd() *cobra.Command {
 				fmt.Printf("%s Build completed\n", green("✓"))
 			}
 
-			fmt.Printf("%s Starting FTL application...\n", blue("→"))
+			// Run a copy of the manifest whose authorizer accepts a mock identity
+			if mockAuth {
+				if from != "" {
+					return &usageError{fmt.Errorf("--mock-auth cannot be combined with --from")}
+				}
+				identity, err := mockIdentity(mockSubject, mockClaims)
+				if err != nil {
+					return err
+				}
+				from, err = writeMockAuthManifest(identity)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = os.Remove(from) }()
+				Warn("Mock authentication enabled: every request is accepted as %s", identity)
+			}
+
+			localStateDir := stateDir
+			if localStateDir == "" {
+				localStateDir = defaultStateDir
+			}
+			if fresh {
+				removed, err := wipeLocalState(localStateDir)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s Removed local state (%d files)\n", green("✓"), len(removed))
+			}
+
+			// Spin only provides the default stores locally
+			if runtimeConfigFile == "" {
+				runtimeConfigFile, err = upRuntimeConfig(from, localStateDir)
+				if err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("%s Starting FTL application on http://%s/mcp\n", blue("→"), listen)
 
 			// Build options array for spin up/watch command
 			var spinOptions []string
@@ -210,6 +321,21 @@ func newUpCmd() *cobra.Command {
 				spinOptions = append(spinOptions, "--listen", listen)
 			}
 
+			// Rebuild changed components ourselves when their watch
+			// patterns are known, otherwise leave it to spin watch
+			if watch && isManifestConfig(configFile) {
+				watcher, err := upComponentWatcher(configFile, debounce)
+				if err != nil {
+					return err
+				}
+				if len(watcher.components) > 0 {
+					ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+					defer stop()
+					fmt.Printf("%s Watching %d components for changes...\n", yellow("ℹ"), len(watcher.components))
+					return runWatch(ctx, watcher, spin.NewExecutor(), spinOptions)
+				}
+			}
+
 			// Run with watch if requested
 			if watch {
 				fmt.Printf("%s Starting with watch mode...\n", yellow("ℹ"))
@@ -230,8 +356,13 @@ func newUpCmd() *cobra.Command {
 	// FTL-specific flags
 	cmd.Flags().BoolVarP(&build, "build", "b", false, "Build before running")
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes and reload")
+	cmd.Flags().DurationVar(&debounce, "debounce", defaultWatchDebounce, "How long files must stop changing before a watched component is rebuilt")
 	cmd.Flags().BoolVar(&skipSynth, "skip-synth", false, "Skip synthesis of spin.toml from FTL config")
 	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file to synthesize (auto-detects if not specified)")
+	_ = cmd.RegisterFlagCompletionFunc("config", completeConfigFiles)
+	cmd.Flags().BoolVar(&mockAuth, "mock-auth", false, "Accept every request as a mock identity instead of requiring tokens")
+	cmd.Flags().StringVar(&mockSubject, "mock-sub", defaultMockSubject, "Subject of the mock identity")
+	cmd.Flags().StringArrayVar(&mockClaims, "mock-claim", nil, "Claim of the mock identity (name=value, JSON values keep their type). Can be used multiple times")
 
 	// Spin up pass-through flags
 	cmd.Flags().StringArrayVar(&componentIDs, "component-id", nil, "[Experimental] Component ID to run. This can be specified multiple times. The default is all components")
@@ -255,7 +386,49 @@ func newUpCmd() *cobra.Command {
 	cmd.Flags().StringVar(&runtimeConfigFile, "runtime-config-file", "", "Configuration file for config providers and wasmtime config")
 	cmd.Flags().StringArrayVar(&sqlite, "sqlite", nil, "Run a SQLite statement such as a migration against the default database. To run from a file, prefix the filename with @ e.g. spin up --sqlite @migration.sql")
 	cmd.Flags().StringVar(&stateDir, "state-dir", "", "Set the application state directory path. This is used in the default locations for logs, key value stores, etc.")
-	cmd.Flags().StringVar(&listen, "listen", "", "Set the listen address for HTTP applications (default: localhost:3000)")
+	cmd.Flags().BoolVar(&fresh, "fresh", false, "Remove the local key-value and SQLite state before starting")
+	cmd.Flags().StringVar(&listen, "listen", "", "Set the listen address for HTTP applications (default: first free port in --port-range on 127.0.0.1)")
+
+	// Multi-app flags
+	cmd.Flags().StringArrayVar(&apps, "app", nil, "Project directory to run behind the local proxy. Can be used multiple times")
+	cmd.Flags().StringVar(&portRange, "port-range", defaultPortRange, "Range of ports to select free ports from")
 
 	return cmd
 }
+
+// upRuntimeConfig generates the runtime config for the named stores of the
+// manifest ftl up runs, returning its path, or "" when none is needed
+func upRuntimeConfig(from, stateDir string) (string, error) {
+	manifestFile := "spin.toml"
+	if from != "" {
+		manifestFile = from
+	}
+	data, err := os.ReadFile(manifestFile) // #nosec G304 -- the manifest the user runs
+	if err != nil {
+		// Not a local manifest, such as a registry reference: left to spin
+		return "", nil
+	}
+	kv, sqlite, err := namedStores(data)
+	if err != nil {
+		return "", err
+	}
+	return writeLocalRuntimeConfig(stateDir, kv, sqlite)
+}
+
+// upComponentWatcher watches the components of an ftl.yaml or ftl.json
+// project, recording rebuilds in the build cache
+func upComponentWatcher(configFile string, debounce time.Duration) (*componentWatcher, error) {
+	m, err := manifest.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ResolveBuilds(); err != nil {
+		return nil, err
+	}
+	watcher := newComponentWatcher(m, debounce)
+	watcher.build = watchBuild
+	if cache, err := buildcache.Load(buildcache.DefaultPath); err == nil {
+		watcher.cache = cache
+	}
+	return watcher, nil
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/spf13/pflag"
+
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/spin"
+	"github.com/fastertools/ftl/synthesis"
+)
+
+const (
+	// defaultUpHost is the interface 'ftl up' binds to
+	defaultUpHost = "127.0.0.1"
+
+	// defaultPortRange is the range searched for free ports
+	defaultPortRange = "3000-3100"
+)
+
+// parsePortRange parses a range such as "3000-3100" or a single port "3000"
+func parsePortRange(s string) (int, int, error) {
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		endStr = startStr
+	}
+
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range '%s': %w", s, err)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range '%s': %w", s, err)
+	}
+
+	if start < 1 || end > 65535 || start > end {
+		return 0, 0, fmt.Errorf("invalid port range '%s': must be within 1-65535 and start <= end", s)
+	}
+	return start, end, nil
+}
+
+// findFreePort returns the first port in [start, end] that can be bound on host.
+// Ports listed in skip are never returned.
+func findFreePort(host string, start, end int, skip map[int]bool) (int, error) {
+	for port := start; port <= end; port++ {
+		if skip[port] {
+			continue
+		}
+		l, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			continue
+		}
+		_ = l.Close()
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port available in range %d-%d", start, end)
+}
+
+// upProject is a single FTL project run as part of a multi-app session
+type upProject struct {
+	Name    string `json:"name"`
+	Dir     string `json:"dir"`
+	Backend string `json:"backend"`
+	MCPURL  string `json:"mcp_url"`
+}
+
+// projectName returns the application name from the project's config,
+// falling back to the directory name.
+func projectName(dir string) string {
+	for _, name := range []string{"ftl.yaml", "ftl.yml", "ftl.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if m, err := manifest.Load(path); err == nil && m.Name != "" {
+			return m.Name
+		}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return filepath.Base(abs)
+}
+
+// detectProjectConfig returns the FTL config file in dir, if any
+func detectProjectConfig(dir string) string {
+	for _, name := range []string{"ftl.yaml", "ftl.yml", "ftl.json", "app.cue", "main.go"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// prepareProject synthesizes spin.toml for a project directory
+func prepareProject(dir string) error {
+	configFile := detectProjectConfig(dir)
+	if configFile == "" {
+		if _, err := os.Stat(filepath.Join(dir, "spin.toml")); err != nil {
+			return fmt.Errorf("no ftl.yaml, ftl.json, app.cue, main.go or spin.toml found in %s", dir)
+		}
+		return nil
+	}
+
+	manifest, err := synthesis.SynthesizeFromConfig(filepath.Join(dir, configFile))
+	if err != nil {
+		return fmt.Errorf("synthesis failed for %s: %w", dir, err)
+	}
+	return os.WriteFile(filepath.Join(dir, "spin.toml"), []byte(manifest), 0600)
+}
+
+// newMultiAppProxy routes /<app>/... to the backend of the named project,
+// stripping the app prefix. GET / lists the running projects.
+func newMultiAppProxy(projects []*upProject) (http.Handler, error) {
+	routes := make(map[string]*httputil.ReverseProxy, len(projects))
+	for _, p := range projects {
+		target, err := url.Parse(p.Backend)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backend for %s: %w", p.Name, err)
+		}
+		routes[p.Name] = httputil.NewSingleHostReverseProxy(target)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trimmed := strings.TrimPrefix(r.URL.Path, "/")
+		if trimmed == "" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"apps": projects})
+			return
+		}
+
+		name, rest, _ := strings.Cut(trimmed, "/")
+		proxy, ok := routes[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown app '%s'", name), http.StatusNotFound)
+			return
+		}
+
+		r = r.Clone(r.Context())
+		r.URL.Path = "/" + rest
+		r.URL.RawPath = ""
+		proxy.ServeHTTP(w, r)
+	}), nil
+}
+
+// multiAppFlags are the flags of 'ftl up' that apply to projects run with
+// --app; the others configure a single spin process
+var multiAppFlags = map[string]bool{
+	"app":        true,
+	"build":      true,
+	"listen":     true,
+	"port-range": true,
+}
+
+// checkMultiAppFlags rejects flags that --app would otherwise ignore
+func checkMultiAppFlags(flags *pflag.FlagSet) error {
+	var ignored []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Changed && !multiAppFlags[f.Name] {
+			ignored = append(ignored, "--"+f.Name)
+		}
+	})
+	if len(ignored) > 0 {
+		return &usageError{fmt.Errorf("%s cannot be combined with --app", strings.Join(ignored, ", "))}
+	}
+	return nil
+}
+
+// runMultiApp starts every project on its own free port and serves them all
+// behind a single reverse proxy on listen.
+func runMultiApp(ctx context.Context, dirs []string, listen string, portStart, portEnd int, build bool) error {
+	blue := color.New(color.FgBlue).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+
+	proxyHost, proxyPortStr, err := net.SplitHostPort(listen)
+	if err != nil {
+		return fmt.Errorf("invalid listen address '%s': %w", listen, err)
+	}
+	proxyPort, _ := strconv.Atoi(proxyPortStr)
+	used := map[int]bool{proxyPort: true}
+
+	var projects []*upProject
+	seen := make(map[string]string)
+	for _, dir := range dirs {
+		name := projectName(dir)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("apps in %s and %s are both named '%s'", other, dir, name)
+		}
+		seen[name] = dir
+
+		fmt.Printf("%s Preparing %s (%s)\n", blue("→"), name, dir)
+		if err := prepareProject(dir); err != nil {
+			return err
+		}
+
+		port, err := findFreePort(defaultUpHost, portStart, portEnd, used)
+		if err != nil {
+			return err
+		}
+		used[port] = true
+
+		projects = append(projects, &upProject{
+			Name:    name,
+			Dir:     dir,
+			Backend: fmt.Sprintf("http://%s:%d", defaultUpHost, port),
+			MCPURL:  fmt.Sprintf("http://%s/%s/mcp", listen, name),
+		})
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(projects)+1)
+	for _, p := range projects {
+		executor := spin.NewExecutor(spin.WithDir(p.Dir))
+		if build {
+			fmt.Printf("%s Building %s...\n", blue("→"), p.Name)
+			if err := executor.Run(ctx, "build"); err != nil {
+				return fmt.Errorf("failed to build %s: %w", p.Name, err)
+			}
+		}
+
+		wg.Add(1)
+		go func(p *upProject) {
+			defer wg.Done()
+			listenAddr := strings.TrimPrefix(p.Backend, "http://")
+			if err := executor.Run(ctx, "up", "--listen", listenAddr); err != nil && ctx.Err() == nil {
+				errCh <- fmt.Errorf("%s exited: %w", p.Name, err)
+			}
+		}(p)
+	}
+
+	handler, err := newMultiAppProxy(projects)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: net.JoinHostPort(proxyHost, proxyPortStr), Handler: handler} // #nosec G112 -- local development proxy
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- fmt.Errorf("proxy failed: %w", err)
+		}
+	}()
+
+	fmt.Println()
+	tb := NewTableBuilder("APP", "MCP URL", "DIRECTORY")
+	for _, p := range projects {
+		tb.AddRow(p.Name, p.MCPURL, p.Dir)
+	}
+	_ = tb.Write(NewDataWriter(colorOutput, "table"))
+	fmt.Printf("\n%s Serving %d apps on http://%s\n", green("✓"), len(projects), listen)
+
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+	}
+
+	_ = server.Shutdown(context.Background())
+	cancel()
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{input: "3000-3100", wantStart: 3000, wantEnd: 3100},
+		{input: "8080", wantStart: 8080, wantEnd: 8080},
+		{input: " 4000 - 4001 ", wantStart: 4000, wantEnd: 4001},
+		{input: "3100-3000", wantErr: true},
+		{input: "0-10", wantErr: true},
+		{input: "3000-70000", wantErr: true},
+		{input: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, end, err := parsePortRange(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestFindFreePort(t *testing.T) {
+	// Occupy a port and make sure it is skipped
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	_, err = findFreePort("127.0.0.1", busy, busy, nil)
+	assert.Error(t, err)
+
+	port, err := findFreePort("127.0.0.1", busy, busy+50, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, busy, port)
+
+	skipped, err := findFreePort("127.0.0.1", busy, busy+50, map[int]bool{port: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, port, skipped)
+}
+
+func TestProjectName(t *testing.T) {
+	dir := t.TempDir()
+	appDir := filepath.Join(dir, "weather-dir")
+	require.NoError(t, os.MkdirAll(appDir, 0750))
+
+	// Falls back to the directory name
+	assert.Equal(t, "weather-dir", projectName(appDir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "ftl.yaml"), []byte("name: weather\n"), 0600))
+	assert.Equal(t, "weather", projectName(appDir))
+}
+
+func TestMultiAppProxy(t *testing.T) {
+	newBackend := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, name+" "+r.URL.Path)
+		}))
+	}
+	weather := newBackend("weather")
+	defer weather.Close()
+	search := newBackend("search")
+	defer search.Close()
+
+	handler, err := newMultiAppProxy([]*upProject{
+		{Name: "weather", Backend: weather.URL},
+		{Name: "search", Backend: search.URL},
+	})
+	require.NoError(t, err)
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(proxy.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/weather/mcp")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "weather /mcp", body)
+
+	status, body = get("/search/mcp/x/tool")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "search /mcp/x/tool", body)
+
+	status, _ = get("/unknown/mcp")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = get("/")
+	assert.Equal(t, http.StatusOK, status)
+	var index struct {
+		Apps []upProject `json:"apps"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &index))
+	assert.Len(t, index.Apps, 2)
+	assert.Equal(t, "weather", index.Apps[0].Name)
+}
+
+func TestUpCommand_MultiAppRejectsSingleAppFlags(t *testing.T) {
+	cmd := newUpCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--app", "./weather", "--watch", "--env", "KEY=value", "--build"})
+
+	err := cmd.Execute()
+	var usage *usageError
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "--env, --watch cannot be combined with --app")
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// defaultStateDir is where spin keeps an app's local state, such as its
+// key-value stores and SQLite databases, unless --state-dir is given
+const defaultStateDir = ".spin"
+
+// localRuntimeConfigFile is the runtime config 'ftl up' generates in the
+// state directory for stores spin does not provide by itself
+const localRuntimeConfigFile = "ftl-runtime-config.toml"
+
+// namedStores returns the labels of the key-value stores and SQLite
+// databases the components of a Spin manifest use, other than "default",
+// which spin provides locally by itself
+func namedStores(manifest []byte) (kv, sqlite []string, err error) {
+	var m struct {
+		Component map[string]struct {
+			KeyValueStores  []string `toml:"key_value_stores"`
+			SQLiteDatabases []string `toml:"sqlite_databases"`
+		} `toml:"component"`
+	}
+	if _, err := toml.Decode(string(manifest), &m); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Spin manifest: %w", err)
+	}
+
+	kvSet := make(map[string]bool)
+	sqliteSet := make(map[string]bool)
+	for _, comp := range m.Component {
+		for _, label := range comp.KeyValueStores {
+			kvSet[label] = true
+		}
+		for _, label := range comp.SQLiteDatabases {
+			sqliteSet[label] = true
+		}
+	}
+	return namedLabels(kvSet), namedLabels(sqliteSet), nil
+}
+
+func namedLabels(set map[string]bool) []string {
+	var labels []string
+	for label := range set {
+		if label != "default" {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// writeLocalRuntimeConfig writes a runtime config to stateDir backing the
+// named stores with files next to spin's default ones, and returns its
+// path. Without named stores nothing is written and the path is empty.
+func writeLocalRuntimeConfig(stateDir string, kv, sqlite []string) (string, error) {
+	if len(kv)+len(sqlite) == 0 {
+		return "", nil
+	}
+	dir, err := filepath.Abs(stateDir)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("# Generated by 'ftl up' for the stores spin does not provide locally\n")
+	for _, label := range kv {
+		fmt.Fprintf(&b, "\n[key_value_store.%s]\ntype = \"spin\"\npath = %q\n", label, filepath.Join(dir, "key_value_"+label+".db"))
+	}
+	for _, label := range sqlite {
+		fmt.Fprintf(&b, "\n[sqlite_database.%s]\ntype = \"spin\"\npath = %q\n", label, filepath.Join(dir, "sqlite_"+label+".db"))
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", stateDir, err)
+	}
+	path := filepath.Join(dir, localRuntimeConfigFile)
+	if err := os.WriteFile(path, b.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// wipeLocalState removes the key-value stores and SQLite databases in
+// stateDir, with their journals, returning the removed files
+func wipeLocalState(stateDir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(stateDir, "*.db*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const storesManifest = `spin_manifest_version = 2
+
+[application]
+name = "analytics"
+
+[component.events]
+source = "./events.wasm"
+key_value_stores = ["default", "sessions"]
+sqlite_databases = ["analytics"]
+
+[component.reports]
+source = "./reports.wasm"
+key_value_stores = ["sessions", "cache"]
+sqlite_databases = ["default"]
+
+[component.mcp-gateway]
+source = "./gateway.wasm"
+key_value_stores = ["default"]
+`
+
+func TestNamedStores(t *testing.T) {
+	kv, sqlite, err := namedStores([]byte(storesManifest))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache", "sessions"}, kv)
+	assert.Equal(t, []string{"analytics"}, sqlite)
+
+	_, _, err = namedStores([]byte("not = [toml"))
+	assert.Error(t, err)
+}
+
+func TestWriteLocalRuntimeConfig(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), ".spin")
+
+	path, err := writeLocalRuntimeConfig(stateDir, []string{"sessions"}, []string{"analytics"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(stateDir, localRuntimeConfigFile), path)
+
+	var config struct {
+		KeyValueStore  map[string]map[string]string `toml:"key_value_store"`
+		SQLiteDatabase map[string]map[string]string `toml:"sqlite_database"`
+	}
+	_, err = toml.DecodeFile(path, &config)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"type": "spin", "path": filepath.Join(stateDir, "key_value_sessions.db")}, config.KeyValueStore["sessions"])
+	assert.Equal(t, map[string]string{"type": "spin", "path": filepath.Join(stateDir, "sqlite_analytics.db")}, config.SQLiteDatabase["analytics"])
+
+	// Default stores need no runtime config
+	path, err = writeLocalRuntimeConfig(t.TempDir(), nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestWipeLocalState(t *testing.T) {
+	stateDir := t.TempDir()
+	for _, name := range []string{"sqlite_key_value.db", "sqlite_db.db", "sqlite_db.db-wal", "key_value_sessions.db"} {
+		require.NoError(t, os.WriteFile(filepath.Join(stateDir, name), []byte("state"), 0600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(stateDir, "logs"), 0750))
+
+	removed, err := wipeLocalState(stateDir)
+	require.NoError(t, err)
+	assert.Len(t, removed, 4)
+
+	left, err := os.ReadDir(stateDir)
+	require.NoError(t, err)
+	require.Len(t, left, 1)
+	assert.Equal(t, "logs", left[0].Name())
+
+	// A missing state directory has nothing to wipe
+	removed, err = wipeLocalState(filepath.Join(stateDir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
@@ -0,0 +1,407 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+
+	"github.com/fastertools/ftl/internal/scaffold"
+)
+
+// upgradeReleasesURL lists the releases of the FTL repository, including
+// the CLI's, which are tagged cli-v<version>
+var upgradeReleasesURL = "https://api.github.com/repos/fastertools/ftl/releases?per_page=100"
+
+// cliReleaseTagPrefix prefixes the git tags of CLI releases
+const cliReleaseTagPrefix = "cli-v"
+
+// projectSkipDirs are not searched for projects or component manifests
+var projectSkipDirs = map[string]bool{
+	"node_modules": true,
+	"target":       true,
+	"dist":         true,
+	"vendor":       true,
+}
+
+// upgradeMaxDepth is how deep below the project root component manifests
+// are searched for
+const upgradeMaxDepth = 3
+
+// versionPattern finds a reference to a released package in a file. The
+// first submatch of Pattern is the version.
+type versionPattern struct {
+	Name    string
+	Files   []string // Base names of the files the package is referenced from
+	Pattern *regexp.Regexp
+	Latest  func(scaffold.Versions) string
+}
+
+const versionExpr = `([0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.+-]*)`
+
+var upgradePatterns = []versionPattern{
+	{
+		Name:    "mcp-gateway",
+		Files:   []string{"ftl.yaml", "ftl.yml", "ftl.json", "spin.toml"},
+		Pattern: regexp.MustCompile(`package"?\s*[:=]\s*"?fastertools:mcp-gateway"?[\s,]*"?version"?\s*[:=]\s*"?v?` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.Components.MCPGateway },
+	},
+	{
+		Name:    "mcp-authorizer",
+		Files:   []string{"ftl.yaml", "ftl.yml", "ftl.json", "spin.toml"},
+		Pattern: regexp.MustCompile(`package"?\s*[:=]\s*"?fastertools:mcp-authorizer"?[\s,]*"?version"?\s*[:=]\s*"?v?` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.Components.MCPAuthorizer },
+	},
+	{
+		Name:    "ftl-sdk (rust)",
+		Files:   []string{"Cargo.toml"},
+		Pattern: regexp.MustCompile(`(?m)^ftl-sdk\s*=\s*(?:\{[^}\n]*?version\s*=\s*)?"[\^~=]?` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.SDK.Rust },
+	},
+	{
+		Name:    "ftl-sdk (typescript)",
+		Files:   []string{"package.json"},
+		Pattern: regexp.MustCompile(`"ftl-sdk"\s*:\s*"[\^~]?` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.SDK.TypeScript },
+	},
+	{
+		Name:    "ftl-sdk (python)",
+		Files:   []string{"pyproject.toml"},
+		Pattern: regexp.MustCompile(`"ftl-sdk\s*[=~>]=\s*` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.SDK.Python },
+	},
+	{
+		Name:    "ftl-sdk (go)",
+		Files:   []string{"go.mod"},
+		Pattern: regexp.MustCompile(`github\.com/fastertools/ftl/sdk/go\s+v` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.SDK.Go },
+	},
+	{
+		Name:    "ftl (go cdk)",
+		Files:   []string{"go.mod"},
+		Pattern: regexp.MustCompile(`github\.com/fastertools/ftl\s+v` + versionExpr),
+		Latest:  func(v scaffold.Versions) string { return v.FTLCli },
+	},
+}
+
+// upgradeItem is a released package and the version in use
+type upgradeItem struct {
+	Name     string `json:"name"`
+	File     string `json:"file,omitempty"`
+	Current  string `json:"current"`
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated"`
+
+	start, end int // Offsets of Current in File
+}
+
+// upgradeReport is the machine-readable form of 'ftl upgrade'
+type upgradeReport struct {
+	CLI      upgradeItem   `json:"cli"`
+	Project  []upgradeItem `json:"project"`
+	Applied  []string      `json:"applied,omitempty"` // Files rewritten with --apply
+	CLIError string        `json:"cli_error,omitempty"`
+}
+
+// UpgradeOptions holds options for the upgrade command
+type UpgradeOptions struct {
+	Apply   bool
+	Offline bool
+}
+
+func newUpgradeCmd() *cobra.Command {
+	opts := &UpgradeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Check for newer versions of the CLI, SDKs and platform components",
+		Long: `Check for newer versions of the CLI, SDKs and platform components.
+
+The installed CLI is compared with the latest release on GitHub. The
+project's references to mcp-gateway and mcp-authorizer (in ftl.yaml,
+ftl.json, or a spin.toml not synthesized from them) and to the FTL SDKs (in the components' Cargo.toml,
+package.json, pyproject.toml and go.mod) are compared with the versions
+released with this CLI, so upgrade the CLI first to see the newest ones.
+
+With --apply, outdated references are rewritten to the new versions.
+Rebuild the components afterwards to pick up new SDKs.
+
+Examples:
+  # Report outdated versions
+  ftl upgrade
+
+  # Rewrite the project to the new versions
+  ftl upgrade --apply`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpgrade(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Apply, "apply", false, "Rewrite outdated references in the project to the new versions")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "Skip checking GitHub for a newer CLI")
+
+	return cmd
+}
+
+func runUpgrade(ctx context.Context, opts *UpgradeOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	released, err := scaffold.ReleaseVersions()
+	if err != nil {
+		return err
+	}
+
+	report := upgradeReport{CLI: upgradeItem{Name: "ftl", Current: version}}
+	if !opts.Offline {
+		latest, err := latestCLIRelease(ctx)
+		if err != nil {
+			report.CLIError = err.Error()
+		} else {
+			report.CLI.Latest = latest
+			report.CLI.Outdated = isNewerVersion(latest, version)
+		}
+	}
+
+	report.Project, err = findVersionReferences(".", released)
+	if err != nil {
+		return err
+	}
+
+	if opts.Apply {
+		report.Applied, err = applyUpgrades(report.Project)
+		if err != nil {
+			return err
+		}
+	}
+
+	if structuredFormat() != "" {
+		return writeResult(report)
+	}
+	displayUpgradeReport(report, opts.Apply)
+	return nil
+}
+
+// latestCLIRelease returns the version of the newest CLI release
+func latestCLIRelease(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upgradeReleasesURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cannot check for CLI releases: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot check for CLI releases: status %d", resp.StatusCode)
+	}
+
+	var releases []struct {
+		TagName    string `json:"tag_name"`
+		Draft      bool   `json:"draft"`
+		Prerelease bool   `json:"prerelease"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return "", fmt.Errorf("invalid release list: %w", err)
+	}
+
+	latest := ""
+	for _, release := range releases {
+		if release.Draft || release.Prerelease || !strings.HasPrefix(release.TagName, cliReleaseTagPrefix) {
+			continue
+		}
+		v := strings.TrimPrefix(release.TagName, cliReleaseTagPrefix)
+		if latest == "" || isNewerVersion(v, latest) {
+			latest = v
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no CLI release found")
+	}
+	return latest, nil
+}
+
+// isNewerVersion reports whether version a is newer than b. Versions that
+// are not semantic versions, such as development builds, are never older.
+func isNewerVersion(a, b string) bool {
+	va, vb := semver.Canonical("v"+strings.TrimPrefix(a, "v")), semver.Canonical("v"+strings.TrimPrefix(b, "v"))
+	if va == "" || vb == "" {
+		return false
+	}
+	return semver.Compare(va, vb) > 0
+}
+
+// findVersionReferences finds the references to released packages in the
+// project at root
+func findVersionReferences(root string, released scaffold.Versions) ([]upgradeItem, error) {
+	var items []upgradeItem
+	synthesized := hasFTLConfig(root)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			depth := strings.Count(filepath.ToSlash(path), "/")
+			if path != root && (strings.HasPrefix(name, ".") || projectSkipDirs[name] || depth >= upgradeMaxDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var data []byte
+		for _, pattern := range upgradePatterns {
+			if !matchesFile(pattern, path, root, synthesized) {
+				continue
+			}
+			if data == nil {
+				if data, err = os.ReadFile(filepath.Clean(path)); err != nil {
+					return err
+				}
+			}
+			for _, match := range pattern.Pattern.FindAllSubmatchIndex(data, -1) {
+				current := string(data[match[2]:match[3]])
+				latest := pattern.Latest(released)
+				items = append(items, upgradeItem{
+					Name:     pattern.Name,
+					File:     path,
+					Current:  current,
+					Latest:   latest,
+					Outdated: isNewerVersion(latest, current),
+					start:    match[2],
+					end:      match[3],
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the project: %w", err)
+	}
+	return items, nil
+}
+
+// matchesFile reports whether pattern applies to the file at path. The FTL
+// configuration and spin.toml are only read at the project root, and
+// spin.toml only when it is not synthesized from an FTL configuration.
+func matchesFile(pattern versionPattern, path, root string, synthesized bool) bool {
+	base := filepath.Base(path)
+	for _, name := range pattern.Files {
+		if base != name {
+			continue
+		}
+		if name == "spin.toml" && synthesized {
+			return false
+		}
+		if strings.HasPrefix(name, "ftl.") || name == "spin.toml" {
+			return filepath.Dir(path) == filepath.Clean(root)
+		}
+		return true
+	}
+	return false
+}
+
+// hasFTLConfig reports whether the project at root has an FTL
+// configuration, from which its spin.toml is synthesized
+func hasFTLConfig(root string) bool {
+	for _, name := range []string{"ftl.yaml", "ftl.yml", "ftl.json", "app.cue"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// applyUpgrades rewrites outdated references and returns the files changed
+func applyUpgrades(items []upgradeItem) ([]string, error) {
+	byFile := make(map[string][]upgradeItem)
+	for _, item := range items {
+		if item.Outdated {
+			byFile[item.File] = append(byFile[item.File], item)
+		}
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, err
+		}
+		// Replace from the end so earlier offsets stay valid
+		edits := byFile[file]
+		sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+		for _, item := range edits {
+			data = append(data[:item.start:item.start], append([]byte(item.Latest), data[item.end:]...)...)
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(file, data, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", file, err)
+		}
+	}
+	return files, nil
+}
+
+func displayUpgradeReport(report upgradeReport, apply bool) {
+	switch {
+	case report.CLIError != "":
+		Warn("%s", report.CLIError)
+	case report.CLI.Latest == "":
+	case report.CLI.Outdated:
+		_, _ = fmt.Fprintf(colorOutput, "%s ftl %s → %s\n", warnColor.Sprint("⚠"), report.CLI.Current, report.CLI.Latest)
+		_, _ = fmt.Fprintf(colorOutput, "  %s Install the new CLI, then run 'ftl upgrade' again for the versions released with it\n", infoColor.Sprint("→"))
+	default:
+		_, _ = fmt.Fprintf(colorOutput, "%s ftl %s is the latest release\n", successColor.Sprint("✓"), report.CLI.Current)
+	}
+
+	if len(report.Project) == 0 {
+		_, _ = fmt.Fprintln(colorOutput, "No SDK or platform component versions found in this project")
+		return
+	}
+
+	outdated := 0
+	for _, item := range report.Project {
+		if !item.Outdated {
+			_, _ = fmt.Fprintf(colorOutput, "%s %-22s %-10s %s\n", successColor.Sprint("✓"), item.Name, item.Current, item.File)
+			continue
+		}
+		outdated++
+		_, _ = fmt.Fprintf(colorOutput, "%s %-22s %-10s %s (%s available)\n", warnColor.Sprint("⚠"), item.Name, item.Current, item.File, item.Latest)
+	}
+
+	_, _ = fmt.Fprintln(colorOutput)
+	switch {
+	case outdated == 0:
+		_, _ = fmt.Fprintln(colorOutput, successColor.Sprint("The project is up to date"))
+	case apply:
+		Success("Updated %d reference(s) in %s", outdated, strings.Join(report.Applied, ", "))
+		Info("Rebuild with 'ftl build' to use the new versions")
+	default:
+		Info("Run 'ftl upgrade --apply' to update %d reference(s)", outdated)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/internal/scaffold"
+)
+
+func stubCLIReleases(t *testing.T, body string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	oldURL := upgradeReleasesURL
+	upgradeReleasesURL = server.URL
+	t.Cleanup(func() { upgradeReleasesURL = oldURL })
+}
+
+func TestLatestCLIRelease(t *testing.T) {
+	stubCLIReleases(t, `[
+		{"tag_name": "sdk-go-v0.20.0"},
+		{"tag_name": "cli-v0.12.0-rc.1", "prerelease": true},
+		{"tag_name": "cli-v0.11.2"},
+		{"tag_name": "cli-v0.9.0"}
+	]`)
+
+	latest, err := latestCLIRelease(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0.11.2", latest)
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	assert.True(t, isNewerVersion("0.11.0", "0.10.3"))
+	assert.True(t, isNewerVersion("v1.0.0", "0.15.0"))
+	assert.False(t, isNewerVersion("0.11.0", "0.11.0"))
+	assert.False(t, isNewerVersion("0.11.0", "dev"))
+}
+
+func TestFindVersionReferences(t *testing.T) {
+	chdirTemp(t)
+	files := map[string]string{
+		"ftl.yaml": "name: app\ncomponents:\n  - id: gw\n    source:\n      registry: ghcr.io\n      package: fastertools:mcp-gateway\n      version: 0.1.0\n",
+		// Synthesized from ftl.yaml, so not reported
+		"spin.toml":                        "[component.mcp-gateway]\nsource = { registry = \"ghcr.io\", package = \"fastertools:mcp-gateway\", version = \"0.0.1\" }\n",
+		"weather/Cargo.toml":               "[dependencies]\nftl-sdk = { version = \"0.1.0\", features = [\"macros\"] }\n",
+		"search/package.json":              "{\n  \"dependencies\": {\n    \"ftl-sdk\": \"^0.1.0\"\n  }\n}\n",
+		"search/node_modules/package.json": "{\"dependencies\": {\"ftl-sdk\": \"^0.0.1\"}}\n",
+		"geo/go.mod":                       "module geo\n\nrequire (\n\tgithub.com/fastertools/ftl/sdk/go v999.0.0\n)\n",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	released := scaffold.Versions{
+		SDK:        scaffold.SDKVersions{Rust: "0.13.0", TypeScript: "0.11.1", Go: "0.11.0"},
+		Components: scaffold.ComponentVersions{MCPGateway: "0.15.0"},
+	}
+	items, err := findVersionReferences(".", released)
+	require.NoError(t, err)
+
+	found := make(map[string]upgradeItem)
+	for _, item := range items {
+		found[item.File] = item
+	}
+	assert.Len(t, found, 4)
+	assert.Equal(t, "0.1.0", found["ftl.yaml"].Current)
+	assert.True(t, found["ftl.yaml"].Outdated)
+	assert.True(t, found[filepath.Join("weather", "Cargo.toml")].Outdated)
+	assert.True(t, found[filepath.Join("search", "package.json")].Outdated)
+	assert.False(t, found[filepath.Join("geo", "go.mod")].Outdated, "newer versions are left alone")
+
+	applied, err := applyUpgrades(items)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+
+	data, err := os.ReadFile(filepath.Join("weather", "Cargo.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `ftl-sdk = { version = "0.13.0", features = ["macros"] }`)
+	data, err = os.ReadFile(filepath.Join("search", "package.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"ftl-sdk": "^0.11.1"`)
+	data, err = os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "version: 0.15.0")
+}
+
+func TestRunUpgrade_Report(t *testing.T) {
+	chdirTemp(t)
+	stubCLIReleases(t, `[{"tag_name": "cli-v99.0.0"}]`)
+	oldVersion := version
+	version = "0.1.0"
+	t.Cleanup(func() { version = oldVersion })
+
+	require.NoError(t, os.MkdirAll("tool", 0750))
+	require.NoError(t, os.WriteFile(filepath.Join("tool", "pyproject.toml"), []byte("dependencies = [\n    \"ftl-sdk==0.0.1\",\n]\n"), 0600))
+
+	var buf bytes.Buffer
+	oldOutput := colorOutput
+	colorOutput = &buf
+	defer func() { colorOutput = oldOutput }()
+
+	require.NoError(t, runUpgrade(context.Background(), &UpgradeOptions{}))
+	assert.Contains(t, buf.String(), "ftl 0.1.0 → 99.0.0")
+	assert.Contains(t, buf.String(), "ftl-sdk (python)")
+
+	data, err := os.ReadFile(filepath.Join("tool", "pyproject.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ftl-sdk==0.0.1", "the report alone does not rewrite files")
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fastertools/ftl/internal/buildcache"
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/fastertools/ftl/spin"
+)
+
+// watchInterval is how often the inputs of watched components are hashed
+var watchInterval = 500 * time.Millisecond
+
+// defaultWatchDebounce is how long inputs must stay unchanged before the
+// component is rebuilt, so saving several files triggers one build
+const defaultWatchDebounce = 300 * time.Millisecond
+
+// watchedComponent tracks the inputs of a component with watch patterns
+type watchedComponent struct {
+	id       string
+	dir      string
+	patterns []string
+	command  string
+	workdir  string
+	source   string
+
+	// built is the hash of the inputs last built, or seen at startup
+	built string
+	// pending is the latest hash that differs from built
+	pending string
+	// changedAt is when pending was first seen
+	changedAt time.Time
+}
+
+// componentWatcher rebuilds the components whose watched inputs changed
+type componentWatcher struct {
+	components []*watchedComponent
+	debounce   time.Duration
+	cache      *buildcache.Cache
+
+	// build builds a single component
+	build func(ctx context.Context, id string) error
+	now   func() time.Time
+}
+
+// newComponentWatcher watches the local components of m that have build
+// watch patterns. Components whose inputs cannot be read are skipped.
+func newComponentWatcher(m *manifest.Manifest, debounce time.Duration) *componentWatcher {
+	w := &componentWatcher{debounce: debounce, now: time.Now}
+	for _, comp := range m.Components {
+		source, ok := comp.Source.(string)
+		if !ok || comp.Build == nil || comp.Build.Command == "" || len(comp.Build.Watch) == 0 {
+			continue
+		}
+		wc := &watchedComponent{
+			id:       comp.ID,
+			dir:      componentBuildDir(&comp),
+			patterns: comp.Build.Watch,
+			command:  comp.Build.Command,
+			workdir:  comp.Build.Workdir,
+			source:   source,
+		}
+		hash, err := wc.hash()
+		if err != nil {
+			Warn("Not watching %s: %v", comp.ID, err)
+			continue
+		}
+		wc.built = hash
+		w.components = append(w.components, wc)
+	}
+	return w
+}
+
+func (wc *watchedComponent) hash() (string, error) {
+	hash, err := buildcache.HashInputs(wc.dir, wc.patterns, wc.command, wc.workdir)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash inputs of %s: %w", wc.id, err)
+	}
+	return hash, nil
+}
+
+// poll hashes every watched component and returns those whose inputs
+// changed and have since settled for the debounce period
+func (w *componentWatcher) poll() []*watchedComponent {
+	now := w.now()
+	var ready []*watchedComponent
+	for _, wc := range w.components {
+		hash, err := wc.hash()
+		if err != nil {
+			Debug("%v", err)
+			continue
+		}
+		switch {
+		case hash == wc.built:
+			wc.pending = ""
+		case hash != wc.pending:
+			wc.pending = hash
+			wc.changedAt = now
+		case now.Sub(wc.changedAt) >= w.debounce:
+			ready = append(ready, wc)
+		}
+	}
+	return ready
+}
+
+// rebuild builds a changed component. When the build fails the previous
+// artifact is put back, so the running app keeps serving it.
+func (w *componentWatcher) rebuild(ctx context.Context, wc *watchedComponent) error {
+	hash := wc.pending
+	wc.built, wc.pending = hash, ""
+
+	previous, readErr := os.ReadFile(filepath.Clean(wc.source))
+	if err := w.build(ctx, wc.id); err != nil {
+		if readErr == nil {
+			if restoreErr := writeArtifact(wc.source, previous); restoreErr != nil {
+				Warn("Failed to restore the previous build of %s: %v", wc.id, restoreErr)
+			}
+		}
+		return err
+	}
+
+	if w.cache != nil {
+		w.cache.Update(wc.id, hash)
+		if err := w.cache.Save(); err != nil {
+			Warn("Failed to save build cache: %v", err)
+		}
+	}
+	return nil
+}
+
+// runWatch serves the app with spin up and rebuilds components as their
+// watched inputs change. Only the changed component is rebuilt, and the app
+// is restarted once it builds; a failed build is reported and the app keeps
+// running the previous version.
+func runWatch(ctx context.Context, w *componentWatcher, executor spin.Executor, upOptions []string) error {
+	server := startWatchServer(ctx, executor, upOptions)
+	defer func() { server.stop() }()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-server.exited:
+			if server.err != nil {
+				return fmt.Errorf("failed to start: %w", server.err)
+			}
+			return nil
+		case <-ticker.C:
+		}
+
+		rebuilt := false
+		for _, wc := range w.poll() {
+			Info("%s changed, rebuilding", wc.id)
+			started := time.Now()
+			if err := w.rebuild(ctx, wc); err != nil {
+				Error("Build of %s failed, still serving the previous version: %v", wc.id, err)
+				continue
+			}
+			Success("Rebuilt %s in %s", wc.id, time.Since(started).Round(time.Millisecond))
+			rebuilt = true
+		}
+		if rebuilt {
+			server.stop()
+			Info("Restarting application")
+			server = startWatchServer(ctx, executor, upOptions)
+		}
+	}
+}
+
+// watchServer is a running spin up process
+type watchServer struct {
+	cancel context.CancelFunc
+	exited chan struct{}
+	// err is why the process exited, unless it was stopped
+	err error
+}
+
+func startWatchServer(ctx context.Context, executor spin.Executor, upOptions []string) *watchServer {
+	serverCtx, cancel := context.WithCancel(ctx)
+	s := &watchServer{cancel: cancel, exited: make(chan struct{})}
+	go func() {
+		defer close(s.exited)
+		err := executor.Run(serverCtx, append([]string{"up"}, upOptions...)...)
+		if serverCtx.Err() == nil {
+			s.err = err
+		}
+	}()
+	return s
+}
+
+// stop stops the process and waits for it to exit
+func (s *watchServer) stop() {
+	s.cancel()
+	<-s.exited
+}
+
+// watchBuild builds a single component with spin build, streaming the
+// compiler's output
+func watchBuild(ctx context.Context, id string) error {
+	return spin.NewExecutor().Run(ctx, "build", "--component-id", id)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fastertools/ftl/internal/buildcache"
+	"github.com/fastertools/ftl/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func watchManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		Name: "app",
+		Components: []manifest.Component{
+			{ID: "api", Source: "api/api.wasm", Build: &manifest.BuildConfig{Command: "make", Workdir: "api", Watch: []string{"**/*.go"}}},
+			{ID: "web", Source: "web/web.wasm", Build: &manifest.BuildConfig{Command: "make", Workdir: "web", Watch: []string{"src/*.js"}}},
+			{ID: "unwatched", Source: "other.wasm", Build: &manifest.BuildConfig{Command: "make"}},
+			{ID: "remote", Source: map[string]interface{}{"registry": "ghcr.io"}},
+		},
+	}
+}
+
+func writeWatchFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestComponentWatcher_DebouncesPerComponent(t *testing.T) {
+	chdirTemp(t)
+	writeWatchFile(t, "api/main.go", "package main")
+	writeWatchFile(t, "web/src/app.js", "console.log(1)")
+
+	w := newComponentWatcher(watchManifest(), time.Second)
+	require.Len(t, w.components, 2, "only local components with watch patterns are watched")
+
+	now := time.Unix(0, 0)
+	w.now = func() time.Time { return now }
+	assert.Empty(t, w.poll())
+
+	writeWatchFile(t, "api/main.go", "package main // edited")
+	assert.Empty(t, w.poll(), "a change waits for the debounce period")
+
+	now = now.Add(500 * time.Millisecond)
+	writeWatchFile(t, "api/main.go", "package main // edited again")
+	assert.Empty(t, w.poll(), "further changes restart the debounce period")
+
+	now = now.Add(time.Second)
+	ready := w.poll()
+	require.Len(t, ready, 1)
+	assert.Equal(t, "api", ready[0].id)
+
+	writeWatchFile(t, "api/notes.txt", "not watched")
+	assert.Len(t, w.poll(), 1, "files outside the watch patterns do not matter")
+}
+
+func TestComponentWatcher_RebuildKeepsArtifactOnFailure(t *testing.T) {
+	chdirTemp(t)
+	writeWatchFile(t, "api/main.go", "package main")
+	writeWatchFile(t, "api/api.wasm", "previous build")
+
+	w := newComponentWatcher(watchManifest(), 0)
+	require.Len(t, w.components, 1, "components whose inputs cannot be read are skipped")
+	cache, err := buildcache.Load(buildcache.DefaultPath)
+	require.NoError(t, err)
+	w.cache = cache
+
+	w.build = func(ctx context.Context, id string) error {
+		writeWatchFile(t, "api/api.wasm", "")
+		return errors.New("syntax error")
+	}
+	writeWatchFile(t, "api/main.go", "package main {")
+	w.poll()
+	ready := w.poll()
+	require.Len(t, ready, 1)
+	require.Error(t, w.rebuild(context.Background(), ready[0]))
+
+	data, err := os.ReadFile("api/api.wasm")
+	require.NoError(t, err)
+	assert.Equal(t, "previous build", string(data))
+	assert.Empty(t, w.poll(), "a failed build is not retried until the inputs change again")
+
+	var built []string
+	w.build = func(ctx context.Context, id string) error {
+		built = append(built, id)
+		writeWatchFile(t, "api/api.wasm", "new build")
+		return nil
+	}
+	writeWatchFile(t, "api/main.go", "package main // fixed")
+	w.poll()
+	ready = w.poll()
+	require.Len(t, ready, 1)
+	require.NoError(t, w.rebuild(context.Background(), ready[0]))
+	assert.Equal(t, []string{"api"}, built)
+
+	hash, err := ready[0].hash()
+	require.NoError(t, err)
+	assert.True(t, cache.Fresh("api", hash), "successful rebuilds are recorded in the build cache")
+}
@@ -29,6 +29,9 @@ type Config struct {
 	// CurrentUser stores info about the logged-in user
 	CurrentUser *UserInfo `json:"current_user,omitempty"`
 
+	// Telemetry stores the anonymous usage metrics settings
+	Telemetry TelemetryConfig `json:"telemetry,omitempty"`
+
 	// Version of the config schema
 	Version string `json:"version"`
 }
@@ -41,6 +44,14 @@ type UserInfo struct {
 	UpdatedAt string `json:"updated_at,omitempty"`
 }
 
+// TelemetryConfig stores the anonymous usage metrics settings. Telemetry
+// is off unless the user explicitly enables it.
+type TelemetryConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	AnonymousID string `json:"anonymous_id,omitempty"`
+}
+
 // OrgInfo stores information about an organization
 type OrgInfo struct {
 	ID          string `json:"id"`
@@ -71,8 +82,8 @@ var (
 	mu       sync.RWMutex
 )
 
-// configPath returns the path to the config file
-func configPath() (string, error) {
+// Dir returns the directory holding FTL's user configuration
+func Dir() (string, error) {
 	var configDir string
 
 	// Check XDG_CONFIG_HOME first for testing and Linux compatibility
@@ -87,7 +98,15 @@ func configPath() (string, error) {
 		}
 	}
 
-	ftlDir := filepath.Join(configDir, "ftl")
+	return filepath.Join(configDir, "ftl"), nil
+}
+
+// configPath returns the path to the config file
+func configPath() (string, error) {
+	ftlDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
 	return filepath.Join(ftlDir, "config.json"), nil
 }
 
@@ -296,3 +315,43 @@ func (c *Config) ClearCurrentUser() error {
 
 	return c.Save()
 }
+
+// GetTelemetry returns the telemetry settings
+func (c *Config) GetTelemetry() TelemetryConfig {
+	mu.RLock()
+	defer mu.RUnlock()
+	return c.Telemetry
+}
+
+// ReadTelemetry returns the saved telemetry settings. Unlike Load it never
+// creates the config directory, so running a command leaves no files behind
+// for users who have not opted in.
+func ReadTelemetry() (TelemetryConfig, error) {
+	path, err := configPath()
+	if err != nil {
+		return TelemetryConfig{}, err
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is controlled via configPath()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return TelemetryConfig{}, nil
+		}
+		return TelemetryConfig{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return TelemetryConfig{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cfg.Telemetry, nil
+}
+
+// SetTelemetry updates the telemetry settings
+func (c *Config) SetTelemetry(telemetry TelemetryConfig) error {
+	mu.Lock()
+	c.Telemetry = telemetry
+	mu.Unlock()
+
+	return c.Save()
+}
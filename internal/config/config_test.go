@@ -195,3 +195,37 @@ func TestConcurrency(t *testing.T) {
 		t.Errorf("Expected at least 5 organizations after concurrent adds, got %d", len(orgs))
 	}
 }
+
+func TestReadTelemetry(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	settings, err := ReadTelemetry()
+	if err != nil {
+		t.Fatalf("Failed to read telemetry settings: %v", err)
+	}
+	if settings.Enabled {
+		t.Error("Expected telemetry to be disabled without a config file")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "ftl")); !os.IsNotExist(err) {
+		t.Errorf("Expected config directory not to be created, stat error: %v", err)
+	}
+
+	instance = nil
+	once = sync.Once{}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.SetTelemetry(TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.example.com"}); err != nil {
+		t.Fatalf("Failed to save telemetry settings: %v", err)
+	}
+
+	settings, err = ReadTelemetry()
+	if err != nil {
+		t.Fatalf("Failed to read telemetry settings: %v", err)
+	}
+	if !settings.Enabled || settings.Endpoint != "https://telemetry.example.com" {
+		t.Errorf("Expected saved telemetry settings, got %+v", settings)
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the profile in use when none is selected. It talks to
+// the FTL platform unless profiles.yaml overrides its settings.
+const DefaultProfile = "default"
+
+// ProfileEnv selects the profile for a single invocation
+const ProfileEnv = "FTL_PROFILE"
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Profile holds the settings of one FTL platform. Empty fields fall back to
+// the built-in defaults.
+type Profile struct {
+	// APIURL is the base URL of the platform API
+	APIURL string `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+
+	// AuthKitDomain is the domain of the platform's identity provider
+	AuthKitDomain string `yaml:"authkit_domain,omitempty" json:"authkit_domain,omitempty"`
+
+	// ClientID is the OAuth client ID the CLI logs in with
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+
+	// Registry is the registry used when a command is not given one
+	Registry string `yaml:"registry,omitempty" json:"registry,omitempty"`
+}
+
+// Profiles is the content of profiles.yaml
+type Profiles struct {
+	// Current is the profile selected with 'ftl profile use'
+	Current string `yaml:"current,omitempty"`
+
+	// Profiles maps profile names to their settings
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+var (
+	profileOverride string
+	profileMu       sync.RWMutex
+)
+
+// SetProfileOverride selects a profile for this process, taking precedence
+// over FTL_PROFILE and the current profile. It backs the --profile flag.
+func SetProfileOverride(name string) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profileOverride = name
+}
+
+// ValidateProfileName checks that a profile name can be stored
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// profilesPath returns the path to profiles.yaml
+func profilesPath() (string, error) {
+	ftlDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ftlDir, "profiles.yaml"), nil
+}
+
+// LoadProfiles reads profiles.yaml. A missing file holds no profiles.
+func LoadProfiles() (*Profiles, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := &Profiles{Profiles: make(map[string]Profile)}
+	data, err := os.ReadFile(path) // #nosec G304 - path is controlled via profilesPath()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	if err := yaml.Unmarshal(data, profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if profiles.Profiles == nil {
+		profiles.Profiles = make(map[string]Profile)
+	}
+	return profiles, nil
+}
+
+// Save writes profiles.yaml
+func (p *Profiles) Save() error {
+	path, err := profilesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+
+	// Write atomically by writing to temp file then renaming
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write profiles: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	return nil
+}
+
+// Names returns the known profile names, sorted, including the default
+// profile
+func (p *Profiles) Names() []string {
+	names := []string{DefaultProfile}
+	for name := range p.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Get returns the settings of a profile. The default profile always
+// exists.
+func (p *Profiles) Get(name string) (Profile, bool) {
+	profile, ok := p.Profiles[name]
+	if name == DefaultProfile {
+		return profile, true
+	}
+	return profile, ok
+}
+
+// Use selects the current profile
+func (p *Profiles) Use(name string) error {
+	if _, ok := p.Get(name); !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	if name == DefaultProfile {
+		p.Current = ""
+	} else {
+		p.Current = name
+	}
+	return p.Save()
+}
+
+// ActiveName returns the name of the profile in use: the --profile flag,
+// then FTL_PROFILE, then the current profile
+func (p *Profiles) ActiveName() string {
+	profileMu.RLock()
+	override := profileOverride
+	profileMu.RUnlock()
+
+	switch {
+	case override != "":
+		return override
+	case os.Getenv(ProfileEnv) != "":
+		return os.Getenv(ProfileEnv)
+	case p.Current != "":
+		return p.Current
+	default:
+		return DefaultProfile
+	}
+}
+
+// ActiveProfile returns the name and settings of the profile in use
+func ActiveProfile() (string, Profile, error) {
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return DefaultProfile, Profile{}, err
+	}
+	name := profiles.ActiveName()
+	profile, ok := profiles.Get(name)
+	if !ok {
+		return name, Profile{}, fmt.Errorf("profile '%s' not found (run 'ftl profile list')", name)
+	}
+	return name, profile, nil
+}
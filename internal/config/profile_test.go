@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfilesDefaultWithoutFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ProfileEnv, "")
+
+	name, profile, err := ActiveProfile()
+	if err != nil {
+		t.Fatalf("Failed to load active profile: %v", err)
+	}
+	if name != DefaultProfile {
+		t.Errorf("Expected the default profile, got %s", name)
+	}
+	if profile != (Profile{}) {
+		t.Errorf("Expected built-in settings, got %+v", profile)
+	}
+}
+
+func TestProfilesUseAndPersist(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv(ProfileEnv, "")
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		t.Fatalf("Failed to load profiles: %v", err)
+	}
+	profiles.Profiles["staging"] = Profile{APIURL: "https://api.staging.example.com", Registry: "registry.example.com/tools"}
+	if err := profiles.Use("staging"); err != nil {
+		t.Fatalf("Failed to use profile: %v", err)
+	}
+	if err := profiles.Use("missing"); err == nil {
+		t.Error("Expected an unknown profile to be rejected")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "ftl", "profiles.yaml"))
+	if err != nil {
+		t.Fatalf("Profiles file was not written: %v", err)
+	}
+	if !strings.Contains(string(data), "current: staging") || !strings.Contains(string(data), "api_url: https://api.staging.example.com") {
+		t.Errorf("Unexpected profiles file:\n%s", data)
+	}
+
+	name, profile, err := ActiveProfile()
+	if err != nil {
+		t.Fatalf("Failed to load active profile: %v", err)
+	}
+	if name != "staging" || profile.Registry != "registry.example.com/tools" {
+		t.Errorf("Expected staging, got %s %+v", name, profile)
+	}
+
+	// The environment and the flag override the current profile
+	t.Setenv(ProfileEnv, DefaultProfile)
+	if name, _, _ := ActiveProfile(); name != DefaultProfile {
+		t.Errorf("Expected FTL_PROFILE to select the default profile, got %s", name)
+	}
+	SetProfileOverride("staging")
+	defer SetProfileOverride("")
+	if name, _, _ := ActiveProfile(); name != "staging" {
+		t.Errorf("Expected the override to select staging, got %s", name)
+	}
+
+	// Using the default profile clears the selection
+	if err := profiles.Use(DefaultProfile); err != nil {
+		t.Fatalf("Failed to use default profile: %v", err)
+	}
+	reloaded, _ := LoadProfiles()
+	if reloaded.Current != "" {
+		t.Errorf("Expected no current profile, got %s", reloaded.Current)
+	}
+	if got := strings.Join(reloaded.Names(), ","); got != "default,staging" {
+		t.Errorf("Unexpected profile names %s", got)
+	}
+}
+
+func TestActiveProfileUnknown(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ProfileEnv, "nope")
+
+	if _, _, err := ActiveProfile(); err == nil {
+		t.Error("Expected an unknown profile to be an error")
+	}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"staging", "self-hosted", "eu_1"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("Expected %s to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Staging", "-x", "a b"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
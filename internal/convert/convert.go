@@ -0,0 +1,318 @@
+// Package convert translates FTL application configuration between the
+// YAML, JSON, CUE and Go (CDK) formats without losing information
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fastertools/ftl/cdk"
+	"github.com/fastertools/ftl/internal/scaffold"
+	"github.com/fastertools/ftl/validation"
+)
+
+// Format is a configuration file format
+type Format string
+
+const (
+	YAML Format = "yaml"
+	JSON Format = "json"
+	CUE  Format = "cue"
+	Go   Format = "go"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{YAML, JSON, CUE, Go}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "yaml", "yml":
+		return YAML, nil
+	case "json":
+		return JSON, nil
+	case "cue":
+		return CUE, nil
+	case "go":
+		return Go, nil
+	}
+	return "", fmt.Errorf("unsupported configuration format %q (supported: yaml, json, cue, go)", name)
+}
+
+// FormatOf returns the format of a configuration file from its extension
+func FormatOf(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("cannot determine the format of %s", path)
+	}
+	return ParseFormat(ext)
+}
+
+// DefaultFile is the conventional configuration file name for the format
+func (f Format) DefaultFile() string {
+	switch f {
+	case JSON:
+		return "ftl.json"
+	case CUE:
+		return "app.cue"
+	case Go:
+		return "main.go"
+	}
+	return "ftl.yaml"
+}
+
+// Load reads and validates a configuration file of any supported format.
+// Go configuration is run with the CDK's JSON export enabled.
+func Load(path string) (*validation.Application, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if format == Go {
+		data, err = exportGo(path)
+		format = JSON
+	} else {
+		data, err = os.ReadFile(filepath.Clean(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	validator := validation.New()
+	var value cue.Value
+	switch format {
+	case YAML:
+		value, err = validator.ValidateYAML(data)
+	case JSON:
+		value, err = validator.ValidateJSON(data)
+	case CUE:
+		value, err = validator.ValidateCUE(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return validation.ExtractApplication(value)
+}
+
+// exportGo runs a Go CDK program and returns the application it defines as
+// JSON configuration
+func exportGo(path string) ([]byte, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	cmd := exec.Command("go", "run", absPath) // #nosec G204 -- runs the project's own configuration, as 'ftl synth' does
+	cmd.Dir = filepath.Dir(absPath)
+	cmd.Env = append(os.Environ(), cdk.ExportEnv+"=json")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("failed to run Go file: %w\nstderr: %s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("failed to run Go file: %w", err)
+	}
+
+	if strings.Contains(string(output), "spin_manifest_version") {
+		return nil, fmt.Errorf("%s printed a Spin manifest instead of its configuration; upgrade github.com/fastertools/ftl in its go.mod", path)
+	}
+	return output, nil
+}
+
+// config mirrors the FTL configuration schema in its conventional field
+// order. Fields left at their schema defaults are omitted.
+type config struct {
+	Name        string      `json:"name" yaml:"name"`
+	Version     string      `json:"version,omitempty" yaml:"version,omitempty"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Access      string      `json:"access,omitempty" yaml:"access,omitempty"`
+	Auth        *authConfig `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Regions     []string    `json:"regions,omitempty" yaml:"regions,omitempty"`
+	Components  []component `json:"components,omitempty" yaml:"components,omitempty"`
+	Middleware  []component `json:"middleware,omitempty" yaml:"middleware,omitempty"`
+}
+
+type authConfig struct {
+	JWTIssuer   string      `json:"jwt_issuer" yaml:"jwt_issuer"`
+	JWTAudience string      `json:"jwt_audience" yaml:"jwt_audience"`
+	JWTJwksURI  string      `json:"jwt_jwks_uri,omitempty" yaml:"jwt_jwks_uri,omitempty"`
+	Policy      string      `json:"policy" yaml:"policy"`
+	PolicyData  interface{} `json:"policy_data,omitempty" yaml:"policy_data,omitempty"`
+}
+
+type component struct {
+	ID          string                    `json:"id" yaml:"id"`
+	Type        string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	Dir         string                    `json:"dir,omitempty" yaml:"dir,omitempty"`
+	Source      interface{}               `json:"source,omitempty" yaml:"source,omitempty"`
+	Build       *buildConfig              `json:"build,omitempty" yaml:"build,omitempty"`
+	Variables   map[string]string         `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Transforms  map[string]*toolTransform `json:"transforms,omitempty" yaml:"transforms,omitempty"`
+	Gateway     *gatewayRoute             `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+	CallTools   bool                      `json:"call_tools,omitempty" yaml:"call_tools,omitempty"`
+	Sampling    bool                      `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	Elicitation bool                      `json:"elicitation,omitempty" yaml:"elicitation,omitempty"`
+	Idempotency bool                      `json:"idempotency,omitempty" yaml:"idempotency,omitempty"`
+	Secrets     []string                  `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	KeyValueStores  []string `json:"key_value_stores,omitempty" yaml:"key_value_stores,omitempty"`
+	SQLiteDatabases []string `json:"sqlite_databases,omitempty" yaml:"sqlite_databases,omitempty"`
+
+	ServiceDependencies []string `json:"service_dependencies,omitempty" yaml:"service_dependencies,omitempty"`
+}
+
+type toolTransform struct {
+	Rename   map[string]string      `json:"rename,omitempty" yaml:"rename,omitempty"`
+	Defaults map[string]interface{} `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Set      map[string]interface{} `json:"set,omitempty" yaml:"set,omitempty"`
+}
+
+type gatewayRoute struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"`
+	Retries        int `json:"retries,omitempty" yaml:"retries,omitempty"`
+	BackoffMS      int `json:"backoff_ms,omitempty" yaml:"backoff_ms,omitempty"`
+}
+
+type buildConfig struct {
+	Profile string   `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Flags   []string `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Command string   `json:"command,omitempty" yaml:"command,omitempty"`
+	Workdir string   `json:"workdir,omitempty" yaml:"workdir,omitempty"`
+	Watch   []string `json:"watch,omitempty" yaml:"watch,omitempty"`
+}
+
+type registrySource struct {
+	Registry string `json:"registry" yaml:"registry"`
+	Package  string `json:"package" yaml:"package"`
+	Version  string `json:"version" yaml:"version"`
+}
+
+func newConfig(app *validation.Application) config {
+	c := config{
+		Name:        app.Name,
+		Version:     app.Version,
+		Description: app.Description,
+		Access:      app.Access,
+		Regions:     app.Regions,
+	}
+	if app.Auth != nil {
+		c.Auth = &authConfig{
+			JWTIssuer:   app.Auth.JWTIssuer,
+			JWTAudience: app.Auth.JWTAudience,
+			JWTJwksURI:  app.Auth.JWTJwksURI,
+			Policy:      app.Auth.Policy,
+			PolicyData:  app.Auth.PolicyData,
+		}
+	}
+	for _, comp := range app.Components {
+		c.Components = append(c.Components, newComponent(comp))
+	}
+	for _, mw := range app.Middleware {
+		c.Middleware = append(c.Middleware, newComponent(mw))
+	}
+	return c
+}
+
+func newComponent(comp *validation.Component) component {
+	comp = comp.WithoutDerived()
+	cc := component{ID: comp.ID, Variables: comp.Variables, CallTools: comp.CallTools, Sampling: comp.Sampling, Elicitation: comp.Elicitation, Idempotency: comp.Idempotency, Secrets: comp.Secrets,
+		KeyValueStores: comp.KeyValueStores, SQLiteDatabases: comp.SQLiteDatabases, ServiceDependencies: comp.ServiceDependencies}
+	if comp.IsStatic() {
+		cc.Type = comp.Type
+		cc.Dir = comp.Dir
+	}
+	// Static components keep the default source unless one was given
+	switch src := comp.Source.(type) {
+	case *validation.LocalSource:
+		cc.Source = src.Path
+	case *validation.RegistrySource:
+		if comp.IsStatic() && src.Package == scaffold.StaticPackage {
+			break
+		}
+		cc.Source = registrySource{Registry: src.Registry, Package: src.Package, Version: src.Version}
+	}
+	if hasBuild(comp.Build) {
+		cc.Build = &buildConfig{Profile: comp.Build.Profile, Flags: comp.Build.Flags, Command: comp.Build.Command, Workdir: comp.Build.Workdir, Watch: comp.Build.Watch}
+	}
+	for tool, t := range comp.Transforms {
+		if cc.Transforms == nil {
+			cc.Transforms = make(map[string]*toolTransform)
+		}
+		cc.Transforms[tool] = &toolTransform{Rename: t.Rename, Defaults: t.Defaults, Set: t.Set}
+	}
+	if g := comp.Gateway; g != nil {
+		cc.Gateway = &gatewayRoute{TimeoutSeconds: g.TimeoutSeconds, Retries: g.Retries, BackoffMS: g.BackoffMS}
+	}
+	return cc
+}
+
+// hasBuild reports whether a component's build differs from the schema
+// default of an empty command
+func hasBuild(b *validation.BuildConfig) bool {
+	return b != nil && (b.Profile != "" || b.Command != "" || b.Workdir != "" || len(b.Watch) > 0)
+}
+
+// Encode renders the application in the given format
+func Encode(app *validation.Application, format Format) ([]byte, error) {
+	switch format {
+	case YAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(newConfig(app)); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	case JSON:
+		data, err := json.MarshalIndent(newConfig(app), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case CUE:
+		return encodeCUE(newConfig(app))
+	case Go:
+		return encodeGo(app)
+	}
+	return nil, fmt.Errorf("unsupported configuration format %q", format)
+}
+
+// encodeCUE renders the configuration as top-level CUE fields, the form
+// 'ftl synth' reads
+func encodeCUE(c config) ([]byte, error) {
+	value := cuecontext.New().Encode(c)
+	if value.Err() != nil {
+		return nil, fmt.Errorf("failed to encode CUE: %w", value.Err())
+	}
+	lit, ok := value.Syntax(cue.Concrete(true)).(*ast.StructLit)
+	if !ok {
+		return nil, fmt.Errorf("failed to encode CUE: configuration is not a struct")
+	}
+	return format.Node(&ast.File{Decls: lit.Elts})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
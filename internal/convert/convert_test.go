@@ -0,0 +1,282 @@
+package convert
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastertools/ftl/validation"
+)
+
+const testConfig = `name: demo
+version: 1.2.0
+description: Demo "tools"
+access: custom
+auth:
+  jwt_issuer: https://auth.example.com
+  jwt_audience: demo-api
+  jwt_jwks_uri: https://auth.example.com/.well-known/jwks.json
+  policy: |
+    package mcp.authorization
+    default allow := false
+    allow if input.token.sub in data.admins
+  policy_data:
+    admins:
+      - alice
+      - bob
+    limits:
+      burst: 10
+      strict: true
+regions:
+  - us-east-1
+  - eu-west-1
+components:
+  - id: weather
+    source: weather/target/wasm32-wasip1/release/weather.wasm
+    build:
+      command: cargo build --release --target wasm32-wasip1
+      workdir: weather
+      watch:
+        - src/**/*.rs
+        - Cargo.toml
+    variables:
+      API_URL: https://api.example.com
+      LOG_LEVEL: debug
+  - id: geo
+    build:
+      profile: go
+      flags: ["-opt=2"]
+      workdir: geo
+  - id: remote
+    source:
+      registry: ghcr.io
+      package: example:remote
+      version: 1.0.0
+    call_tools: true
+    sampling: true
+    elicitation: true
+    idempotency: true
+    key_value_stores: [sessions]
+    sqlite_databases: [default, analytics]
+    service_dependencies: [weather]
+    secrets: [api_key, db_password]
+    gateway:
+      timeout_seconds: 30
+      retries: 2
+      backoff_ms: 250
+    transforms:
+      "*":
+        set:
+          tenant: ${claims.org_id}
+      search:
+        rename:
+          query: q
+        defaults:
+          limit: 10
+          exact: false
+middleware:
+  - id: rate-limiter
+    source:
+      registry: ghcr.io
+      package: example:rate-limiter
+      version: 2.0.0
+    variables:
+      LIMIT: "100"
+  - id: audit-log
+    source: audit/audit.wasm
+    build:
+      command: make
+`
+
+func loadTestConfig(t *testing.T) *validation.Application {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ftl.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	app, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return app
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"yaml": YAML, "YML": YAML, "json": JSON, "cue": CUE, "go": Go} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("toml"); err == nil {
+		t.Error("ParseFormat(toml) should fail")
+	}
+	if _, err := FormatOf("Makefile"); err == nil {
+		t.Error("FormatOf(Makefile) should fail")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := loadTestConfig(t)
+
+	for _, format := range []Format{YAML, JSON, CUE} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Encode(want, format)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if strings.Contains(string(data), "tinygo") {
+				t.Errorf("what the build profile derives should be left out:\n%s", data)
+			}
+			path := filepath.Join(t.TempDir(), format.DefaultFile())
+			if err := os.WriteFile(path, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v\n%s", err, data)
+			}
+			assertSameApp(t, want, got)
+		})
+	}
+}
+
+func TestEncodeGo(t *testing.T) {
+	app := loadTestConfig(t)
+
+	data, err := Encode(app, Go)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	source := string(data)
+	for _, want := range []string{
+		`SetCustomAuth("https://auth.example.com", "demo-api")`,
+		`SetJWKSURI("https://auth.example.com/.well-known/jwks.json")`,
+		"SetPolicy(`package mcp.authorization\n",
+		`"admins": []interface{}{"alice", "bob"}`,
+		`WithWorkdir("weather")`,
+		`WithWatch("src/**/*.rs", "Cargo.toml")`,
+		`WithEnv("API_URL", "https://api.example.com")`,
+		`FromRegistry("ghcr.io", "example:remote", "1.0.0")`,
+		`WithRenamedArgument("search", "query", "q")`,
+		`WithDefaultArgument("search", "limit", 10)`,
+		`WithDefaultArgument("search", "exact", false)`,
+		`WithFixedArgument("*", "tenant", "${claims.org_id}")`,
+		`WithToolCalls()`,
+		`WithSampling()`,
+		`WithElicitation()`,
+		`WithIdempotency()`,
+		`WithKeyValueStores("sessions")`,
+		`WithSQLiteDatabases("default", "analytics")`,
+		`WithServiceDependency("weather")`,
+		`WithSecrets("api_key", "db_password")`,
+		`AddMiddleware("rate-limiter")`,
+		`FromRegistry("ghcr.io", "example:rate-limiter", "2.0.0")`,
+		`WithEnv("LIMIT", "100")`,
+		`AddMiddleware("audit-log")`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Go source missing %s:\n%s", want, source)
+		}
+	}
+	if strings.Contains(source, "SetAccess") {
+		t.Errorf("custom access is implied by SetCustomAuth:\n%s", source)
+	}
+}
+
+func TestRoundTripStatic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ftl.yaml")
+	config := "name: docs-app\ncomponents:\n  - id: docs\n    type: static\n    dir: ./docs\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	want, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, format := range []Format{YAML, JSON, CUE} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Encode(want, format)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if strings.Contains(string(data), "mcp-static") {
+				t.Errorf("default static source should be left out:\n%s", data)
+			}
+			path := filepath.Join(t.TempDir(), format.DefaultFile())
+			if err := os.WriteFile(path, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v\n%s", err, data)
+			}
+			comp := got.Components[0]
+			if !comp.IsStatic() || comp.Dir != "./docs" {
+				t.Errorf("static component = type %q dir %q, want static ./docs\n%s", comp.Type, comp.Dir, data)
+			}
+		})
+	}
+
+	if _, err := Encode(want, Go); err == nil {
+		t.Error("Encode(Go) should reject static components")
+	}
+}
+
+// TestRoundTripGo runs the generated program against this checkout of the
+// CDK and reads the configuration back
+func TestRoundTripGo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a Go program")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Skip("go.sum not available")
+	}
+
+	want := loadTestConfig(t)
+	data, err := Encode(want, Go)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	goMod := "module demo\n\ngo 1.24\n\nrequire github.com/fastertools/ftl v0.0.0\n\nreplace github.com/fastertools/ftl => " + root + "\n"
+	for name, content := range map[string][]byte{"main.go": data, "go.mod": []byte(goMod), "go.sum": sum} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	got, err := Load(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	assertSameApp(t, want, got)
+}
+
+func assertSameApp(t *testing.T, want, got *validation.Application) {
+	t.Helper()
+	wantYAML, err := Encode(want, YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotYAML, err := Encode(got, YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wantYAML, gotYAML) {
+		t.Errorf("configuration changed in conversion\nwant:\n%s\ngot:\n%s", wantYAML, gotYAML)
+	}
+}
@@ -0,0 +1,207 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	"github.com/fastertools/ftl/validation"
+)
+
+// encodeGo renders the application as a Go program that builds it with the
+// CDK and prints its Spin manifest, like the 'ftl init --format go' template
+func encodeGo(app *validation.Application) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(`package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/fastertools/ftl/cdk"
+)
+
+func main() {
+	ftl := cdk.New()
+`)
+
+	fmt.Fprintf(&b, "app := ftl.NewApp(%s)", strconv.Quote(app.Name))
+	if app.Version != "" {
+		fmt.Fprintf(&b, ".\nSetVersion(%s)", strconv.Quote(app.Version))
+	}
+	if app.Description != "" {
+		fmt.Fprintf(&b, ".\nSetDescription(%s)", strconv.Quote(app.Description))
+	}
+
+	// The builder starts out public and SetCustomAuth switches it to custom
+	access := "public"
+	if auth := app.Auth; auth != nil {
+		fmt.Fprintf(&b, ".\nSetCustomAuth(%s, %s)", strconv.Quote(auth.JWTIssuer), strconv.Quote(auth.JWTAudience))
+		access = "custom"
+		if auth.JWTJwksURI != "" {
+			fmt.Fprintf(&b, ".\nSetJWKSURI(%s)", strconv.Quote(auth.JWTJwksURI))
+		}
+		if auth.Policy != "" {
+			fmt.Fprintf(&b, ".\nSetPolicy(%s)", goString(auth.Policy))
+		}
+		if auth.PolicyData != nil {
+			fmt.Fprintf(&b, ".\nSetPolicyData(%s)", goValue(auth.PolicyData))
+		}
+	}
+	if app.Access != "" && app.Access != access {
+		fmt.Fprintf(&b, ".\nSetAccess(%s)", strconv.Quote(app.Access))
+	}
+	if len(app.Regions) > 0 {
+		fmt.Fprintf(&b, ".\nSetRegions(%s)", quoteAll(app.Regions))
+	}
+	b.WriteString("\n\n")
+
+	for _, comp := range app.Components {
+		if comp.IsStatic() {
+			return nil, fmt.Errorf("component %q is static, which the CDK does not support", comp.ID)
+		}
+		fmt.Fprintf(&b, "app.AddComponent(%s)", strconv.Quote(comp.ID))
+		writeGoSource(&b, comp)
+		for _, tool := range sortedKeys(comp.Transforms) {
+			t := comp.Transforms[tool]
+			for _, from := range sortedKeys(t.Rename) {
+				fmt.Fprintf(&b, ".\nWithRenamedArgument(%s, %s, %s)", strconv.Quote(tool), strconv.Quote(from), strconv.Quote(t.Rename[from]))
+			}
+			for _, name := range sortedKeys(t.Defaults) {
+				fmt.Fprintf(&b, ".\nWithDefaultArgument(%s, %s, %s)", strconv.Quote(tool), strconv.Quote(name), goValue(t.Defaults[name]))
+			}
+			for _, name := range sortedKeys(t.Set) {
+				fmt.Fprintf(&b, ".\nWithFixedArgument(%s, %s, %s)", strconv.Quote(tool), strconv.Quote(name), goValue(t.Set[name]))
+			}
+		}
+		if g := comp.Gateway; g != nil {
+			if g.TimeoutSeconds > 0 {
+				fmt.Fprintf(&b, ".\nWithCallTimeout(%d)", g.TimeoutSeconds)
+			}
+			if g.Retries > 0 || g.BackoffMS > 0 {
+				fmt.Fprintf(&b, ".\nWithCallRetries(%d, %d)", g.Retries, g.BackoffMS)
+			}
+		}
+		if comp.CallTools {
+			b.WriteString(".\nWithToolCalls()")
+		}
+		if comp.Sampling {
+			b.WriteString(".\nWithSampling()")
+		}
+		if comp.Elicitation {
+			b.WriteString(".\nWithElicitation()")
+		}
+		if comp.Idempotency {
+			b.WriteString(".\nWithIdempotency()")
+		}
+		if len(comp.KeyValueStores) > 0 {
+			fmt.Fprintf(&b, ".\nWithKeyValueStores(%s)", quoteAll(comp.KeyValueStores))
+		}
+		if len(comp.SQLiteDatabases) > 0 {
+			fmt.Fprintf(&b, ".\nWithSQLiteDatabases(%s)", quoteAll(comp.SQLiteDatabases))
+		}
+		for _, dep := range comp.ServiceDependencies {
+			fmt.Fprintf(&b, ".\nWithServiceDependency(%s)", strconv.Quote(dep))
+		}
+		if len(comp.Secrets) > 0 {
+			fmt.Fprintf(&b, ".\nWithSecrets(%s)", quoteAll(comp.Secrets))
+		}
+		b.WriteString(".\nBuild()\n\n")
+	}
+
+	for _, mw := range app.Middleware {
+		fmt.Fprintf(&b, "app.AddMiddleware(%s)", strconv.Quote(mw.ID))
+		writeGoSource(&b, mw)
+		b.WriteString(".\nBuild()\n\n")
+	}
+
+	b.WriteString(`manifest, err := app.Build().Synthesize()
+	if err != nil {
+		log.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	fmt.Print(manifest)
+}
+`)
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format Go source: %w", err)
+	}
+	return source, nil
+}
+
+// writeGoSource writes the builder calls for the source, build and
+// variables shared by components and middleware
+func writeGoSource(b *bytes.Buffer, comp *validation.Component) {
+	comp = comp.WithoutDerived()
+	switch src := comp.Source.(type) {
+	case *validation.LocalSource:
+		fmt.Fprintf(b, ".\nFromLocal(%s)", strconv.Quote(src.Path))
+	case *validation.RegistrySource:
+		fmt.Fprintf(b, ".\nFromRegistry(%s, %s, %s)", strconv.Quote(src.Registry), strconv.Quote(src.Package), strconv.Quote(src.Version))
+	}
+	if build := comp.Build; hasBuild(build) {
+		if build.Profile != "" {
+			fmt.Fprintf(b, ".\nWithBuildProfile(%s)", quoteAll(append([]string{build.Profile}, build.Flags...)))
+		}
+		if build.Command != "" {
+			fmt.Fprintf(b, ".\nWithBuild(%s)", strconv.Quote(build.Command))
+		}
+		if build.Workdir != "" {
+			fmt.Fprintf(b, ".\nWithWorkdir(%s)", strconv.Quote(build.Workdir))
+		}
+		if len(build.Watch) > 0 {
+			fmt.Fprintf(b, ".\nWithWatch(%s)", quoteAll(build.Watch))
+		}
+	}
+	for _, key := range sortedKeys(comp.Variables) {
+		fmt.Fprintf(b, ".\nWithEnv(%s, %s)", strconv.Quote(key), strconv.Quote(comp.Variables[key]))
+	}
+}
+
+// goString quotes s, keeping multi-line text such as Rego policies readable
+// in a raw string literal where possible
+func goString(s string) string {
+	if strings.Contains(s, "\n") && !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// goValue renders decoded JSON-like data as a Go literal
+func goValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return goString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case map[string]interface{}:
+		fields := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			fields = append(fields, fmt.Sprintf("%s: %s,\n", strconv.Quote(key), goValue(v[key])))
+		}
+		return "map[string]interface{}{\n" + strings.Join(fields, "") + "}"
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = goValue(item)
+		}
+		return "[]interface{}{" + strings.Join(items, ", ") + "}"
+	}
+	return fmt.Sprintf("%#v", v)
+}
@@ -0,0 +1,250 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// ProjectPolicyPath is where a project keeps its deploy policy
+const ProjectPolicyPath = ".ftl/policy.yaml"
+
+// Policy rules checked by the CLI before anything is built or pushed. They
+// mirror the platform's RequireRegistryComponents and AllowedRegistries
+// settings so violations are caught locally.
+type Policy struct {
+	// RequireRegistryComponents rejects components built from local sources
+	RequireRegistryComponents bool `yaml:"require_registry_components"`
+
+	// AllowedRegistries lists the registries components may come from.
+	// Entries may use path.Match wildcards, e.g. "*.dkr.ecr.*.amazonaws.com".
+	// Empty allows every registry.
+	AllowedRegistries []string `yaml:"allowed_registries"`
+
+	// ForbidLatest rejects registry components without a pinned version
+	ForbidLatest bool `yaml:"forbid_latest"`
+
+	// RequireSignatureTag rejects registry components without a cosign
+	// signature tag in their registry. The signature is not verified, so
+	// anyone who can push to the repository can satisfy the rule: it
+	// catches unsigned releases by mistake, and gives no security guarantee.
+	RequireSignatureTag bool `yaml:"require_signature_tag"`
+
+	// RequireProvenance rejects registry components without a provenance
+	// attestation of how they were built
+	RequireProvenance bool `yaml:"require_provenance"`
+
+	// TrustedBuilders lists the builders registry components must have
+	// been built by, according to their provenance. Entries may use
+	// path.Match wildcards. Empty trusts every builder.
+	TrustedBuilders []string `yaml:"trusted_builders"`
+
+	// AllowedSources lists the git repositories registry components must
+	// have been built from, according to their provenance. Entries may use
+	// path.Match wildcards, e.g. "https://github.com/acme/*". Empty allows
+	// every source.
+	AllowedSources []string `yaml:"allowed_sources"`
+
+	// Source is the file the policy was loaded from
+	Source string `yaml:"-"`
+}
+
+// Violation is a single policy rule broken by a component
+type Violation struct {
+	Component string `json:"component"`
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+	Source    string `json:"policy"`
+}
+
+// Policy rule names reported in violations
+const (
+	RuleRequireRegistry     = "require_registry_components"
+	RuleAllowedRegistry     = "allowed_registries"
+	RuleForbidLatest        = "forbid_latest"
+	RuleRequireSignatureTag = "require_signature_tag"
+	RuleRequireProv         = "require_provenance"
+	RuleTrustedBuilder      = "trusted_builders"
+	RuleAllowedSource       = "allowed_sources"
+)
+
+// SignatureTagChecker reports whether a registry component has a signature
+// tag
+type SignatureTagChecker func(ctx context.Context, src *validation.RegistrySource) (bool, error)
+
+// ProvenanceFetcher returns the provenance attached to a registry
+// component, or nil when it has none
+type ProvenanceFetcher func(ctx context.Context, src *validation.RegistrySource) (*oci.Provenance, error)
+
+// PolicyError is returned when an application breaks one or more policies
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "deployment blocked by policy (%d violation(s)):", len(e.Violations))
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  - %s: %s [%s in %s]", v.Component, v.Message, v.Rule, v.Source)
+	}
+	return b.String()
+}
+
+// LoadPolicy reads a policy file. A missing file yields a nil policy.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file) // #nosec G304 -- policy paths are fixed or chosen by the user
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", file, err)
+	}
+
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid policy %s: %w", file, err)
+	}
+	p.Source = file
+	return &p, nil
+}
+
+// CheckPolicies checks the application against every policy and returns a
+// *PolicyError listing all violations, or nil. checkSignature is only called
+// when a policy requires signed artifacts, and fetchProvenance when a
+// policy checks provenance.
+func CheckPolicies(ctx context.Context, app *validation.Application, policies []*Policy, checkSignature SignatureTagChecker, fetchProvenance ProvenanceFetcher) error {
+	var violations []Violation
+	for _, p := range policies {
+		if p == nil {
+			continue
+		}
+		found, err := p.Check(ctx, app, checkSignature, fetchProvenance)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, found...)
+	}
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Check returns the components of the application that break the policy
+func (p *Policy) Check(ctx context.Context, app *validation.Application, checkSignature SignatureTagChecker, fetchProvenance ProvenanceFetcher) ([]Violation, error) {
+	var violations []Violation
+	violate := func(component, rule, format string, args ...interface{}) {
+		violations = append(violations, Violation{
+			Component: component,
+			Rule:      rule,
+			Message:   fmt.Sprintf(format, args...),
+			Source:    p.Source,
+		})
+	}
+
+	for _, comp := range app.Components {
+		switch src := comp.Source.(type) {
+		case *validation.LocalSource:
+			if p.RequireRegistryComponents {
+				violate(comp.ID, RuleRequireRegistry, "local source %s is not allowed; publish the component to a registry", src.Path)
+			}
+
+		case *validation.RegistrySource:
+			if !p.registryAllowed(src.Registry) {
+				violate(comp.ID, RuleAllowedRegistry, "registry %s is not in the allowed registries %v", src.Registry, p.AllowedRegistries)
+			}
+			if p.ForbidLatest && (src.Version == "" || strings.EqualFold(src.Version, "latest")) {
+				violate(comp.ID, RuleForbidLatest, "version of %s must be pinned, not %q", src.Package, src.Version)
+			}
+			if p.RequireSignatureTag {
+				if checkSignature == nil {
+					return nil, fmt.Errorf("policy %s requires signature tags but they cannot be looked up", p.Source)
+				}
+				signed, err := checkSignature(ctx, src)
+				if err != nil {
+					return nil, fmt.Errorf("failed to look up signature tag of %s: %w", comp.ID, err)
+				}
+				if !signed {
+					violate(comp.ID, RuleRequireSignatureTag, "%s/%s:%s has no signature tag", src.Registry, src.Package, src.Version)
+				}
+			}
+			if p.ChecksProvenance() {
+				if fetchProvenance == nil {
+					return nil, fmt.Errorf("policy %s checks provenance but provenance cannot be fetched", p.Source)
+				}
+				provenance, err := fetchProvenance(ctx, src)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch provenance of %s: %w", comp.ID, err)
+				}
+				violations = append(violations, p.CheckProvenance(comp.ID, provenance)...)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// ChecksProvenance reports whether the policy has provenance rules
+func (p *Policy) ChecksProvenance() bool {
+	return p.RequireProvenance || len(p.TrustedBuilders) > 0 || len(p.AllowedSources) > 0
+}
+
+// CheckProvenance returns the provenance rules a component's provenance,
+// nil when it has none, breaks
+func (p *Policy) CheckProvenance(component string, provenance *oci.Provenance) []Violation {
+	var violations []Violation
+	violate := func(rule, format string, args ...interface{}) {
+		violations = append(violations, Violation{
+			Component: component,
+			Rule:      rule,
+			Message:   fmt.Sprintf(format, args...),
+			Source:    p.Source,
+		})
+	}
+
+	if provenance == nil {
+		if p.ChecksProvenance() {
+			violate(RuleRequireProv, "no provenance attestation is attached")
+		}
+		return violations
+	}
+	if !matchAny(p.TrustedBuilders, provenance.Builder) {
+		violate(RuleTrustedBuilder, "built by %q, which is not in the trusted builders %v", provenance.Builder, p.TrustedBuilders)
+	}
+	if !matchAny(p.AllowedSources, provenance.SourceRepo) {
+		violate(RuleAllowedSource, "built from %q, which is not in the allowed sources %v", provenance.SourceRepo, p.AllowedSources)
+	}
+	return violations
+}
+
+func (p *Policy) registryAllowed(registry string) bool {
+	return matchAny(p.AllowedRegistries, registry)
+}
+
+// matchAny reports whether value matches one of the patterns, which may
+// use path.Match wildcards. No patterns match every value.
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern == value {
+			return true
+		}
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+func policyTestApp() *validation.Application {
+	return &validation.Application{
+		Name: "demo",
+		Components: []*validation.Component{
+			{ID: "local", Source: &validation.LocalSource{Path: "./local"}},
+			{ID: "pinned", Source: &validation.RegistrySource{Registry: "ghcr.io", Package: "acme:pinned", Version: "1.2.3"}},
+			{ID: "floating", Source: &validation.RegistrySource{Registry: "docker.io", Package: "acme:floating", Version: "latest"}},
+		},
+	}
+}
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func TestLoadPolicy(t *testing.T) {
+	file := writePolicy(t, `require_registry_components: true
+allowed_registries:
+  - ghcr.io
+  - "*.dkr.ecr.*.amazonaws.com"
+forbid_latest: true
+require_signature_tag: true
+require_provenance: true
+trusted_builders:
+  - "https://github.com/acme/*"
+`)
+	p, err := LoadPolicy(file)
+	require.NoError(t, err)
+	assert.True(t, p.RequireRegistryComponents)
+	assert.True(t, p.ForbidLatest)
+	assert.True(t, p.RequireSignatureTag)
+	assert.True(t, p.RequireProvenance)
+	assert.Equal(t, []string{"https://github.com/acme/*"}, p.TrustedBuilders)
+	assert.Equal(t, []string{"ghcr.io", "*.dkr.ecr.*.amazonaws.com"}, p.AllowedRegistries)
+	assert.Equal(t, file, p.Source)
+}
+
+func TestLoadPolicy_Missing(t *testing.T) {
+	p, err := LoadPolicy(filepath.Join(t.TempDir(), "policy.yaml"))
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestLoadPolicy_UnknownField(t *testing.T) {
+	_, err := LoadPolicy(writePolicy(t, "forbid_latests: true\n"))
+	assert.ErrorContains(t, err, "invalid policy")
+}
+
+func TestLoadPolicy_Empty(t *testing.T) {
+	p, err := LoadPolicy(writePolicy(t, ""))
+	require.NoError(t, err)
+	assert.NotNil(t, p)
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p := &Policy{
+		RequireRegistryComponents: true,
+		AllowedRegistries:         []string{"ghcr.io"},
+		ForbidLatest:              true,
+		Source:                    "policy.yaml",
+	}
+
+	violations, err := p.Check(context.Background(), policyTestApp(), nil, nil)
+	require.NoError(t, err)
+
+	rules := map[string]string{}
+	for _, v := range violations {
+		rules[v.Component+"/"+v.Rule] = v.Message
+		assert.Equal(t, "policy.yaml", v.Source)
+	}
+	assert.Len(t, violations, 3)
+	assert.Contains(t, rules, "local/"+RuleRequireRegistry)
+	assert.Contains(t, rules, "floating/"+RuleAllowedRegistry)
+	assert.Contains(t, rules, "floating/"+RuleForbidLatest)
+}
+
+func TestPolicyCheck_RegistryWildcard(t *testing.T) {
+	p := &Policy{AllowedRegistries: []string{"*.dkr.ecr.*.amazonaws.com"}}
+	app := &validation.Application{Components: []*validation.Component{
+		{ID: "ecr", Source: &validation.RegistrySource{Registry: "123.dkr.ecr.us-west-2.amazonaws.com", Package: "a:b", Version: "1.0.0"}},
+	}}
+
+	violations, err := p.Check(context.Background(), app, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestPolicyCheck_RequireSignatureTag(t *testing.T) {
+	p := &Policy{RequireSignatureTag: true, Source: "policy.yaml"}
+	checker := func(ctx context.Context, src *validation.RegistrySource) (bool, error) {
+		return src.Package == "acme:pinned", nil
+	}
+
+	violations, err := p.Check(context.Background(), policyTestApp(), checker, nil)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "floating", violations[0].Component)
+	assert.Equal(t, RuleRequireSignatureTag, violations[0].Rule)
+
+	_, err = p.Check(context.Background(), policyTestApp(), func(ctx context.Context, src *validation.RegistrySource) (bool, error) {
+		return false, errors.New("registry unreachable")
+	}, nil)
+	assert.ErrorContains(t, err, "failed to look up signature tag")
+}
+
+func TestPolicyCheck_Provenance(t *testing.T) {
+	p := &Policy{
+		TrustedBuilders: []string{"https://github.com/acme/*/.github/workflows/release.yml@refs/heads/main"},
+		AllowedSources:  []string{"https://github.com/acme/*"},
+		Source:          "policy.yaml",
+	}
+	fetch := func(ctx context.Context, src *validation.RegistrySource) (*oci.Provenance, error) {
+		if src.Package != "acme:pinned" {
+			return nil, nil
+		}
+		return &oci.Provenance{
+			Builder:    "https://github.com/acme/geo/.github/workflows/release.yml@refs/heads/main",
+			SourceRepo: "https://github.com/someone/geo",
+		}, nil
+	}
+
+	violations, err := p.Check(context.Background(), policyTestApp(), nil, fetch)
+	require.NoError(t, err)
+	rules := map[string]string{}
+	for _, v := range violations {
+		rules[v.Component+"/"+v.Rule] = v.Message
+	}
+	assert.Len(t, violations, 2)
+	assert.Contains(t, rules, "pinned/"+RuleAllowedSource)
+	assert.Contains(t, rules, "floating/"+RuleRequireProv)
+
+	_, err = p.Check(context.Background(), policyTestApp(), nil, nil)
+	assert.ErrorContains(t, err, "provenance cannot be fetched")
+
+	assert.Empty(t, (&Policy{}).CheckProvenance("pinned", nil), "policies without provenance rules accept any component")
+	assert.Len(t, (&Policy{RequireProvenance: true}).CheckProvenance("pinned", nil), 1)
+	assert.Empty(t, (&Policy{RequireProvenance: true}).CheckProvenance("pinned", &oci.Provenance{Builder: "local:dev@laptop"}))
+}
+
+func TestCheckPolicies(t *testing.T) {
+	policies := []*Policy{
+		{ForbidLatest: true, Source: "project"},
+		nil,
+		{AllowedRegistries: []string{"ghcr.io"}, Source: "org"},
+	}
+
+	err := CheckPolicies(context.Background(), policyTestApp(), policies, nil, nil)
+	var policyErr *PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Len(t, policyErr.Violations, 2)
+	assert.Contains(t, err.Error(), "deployment blocked by policy (2 violation(s))")
+	assert.Contains(t, err.Error(), "[forbid_latest in project]")
+	assert.Contains(t, err.Error(), "[allowed_registries in org]")
+
+	assert.NoError(t, CheckPolicies(context.Background(), policyTestApp(), nil, nil, nil))
+}
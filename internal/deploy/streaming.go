@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,12 +18,42 @@ import (
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/fastertools/ftl/ftlerr"
 	"github.com/fastertools/ftl/internal/api"
 )
 
+// Deployment stages reported in StreamEvent.Stage
+const (
+	StageImagePulled         = "image_pulled"
+	StageManifestSynthesized = "manifest_synthesized"
+	StageRouted              = "routed"
+	StageHealthy             = "healthy"
+)
+
+// stageLabels are the human-readable names of known deployment stages
+var stageLabels = map[string]string{
+	StageImagePulled:         "Component images pulled",
+	StageManifestSynthesized: "Manifest synthesized",
+	StageRouted:              "Routes configured",
+	StageHealthy:             "Application healthy",
+}
+
+// StageLabel returns a human-readable name for a deployment stage
+func StageLabel(stage string) string {
+	if label, ok := stageLabels[stage]; ok {
+		return label
+	}
+	return strings.ReplaceAll(stage, "_", " ")
+}
+
+// ErrDeployTimeout is returned when a deployment does not finish within DeployOptions.Timeout
+var ErrDeployTimeout error = ftlerr.New(ftlerr.DeployTimeout, "deployment timed out").
+	WithHint("Run 'ftl status' to check on the deployment, or retry with a longer --timeout")
+
 // StreamEvent represents a deployment progress event from the streaming response
 type StreamEvent struct {
-	Type         string            `json:"type"` // "progress", "complete", "error"
+	Type         string            `json:"type"` // "progress", "stage", "complete", "error"
+	Stage        string            `json:"stage,omitempty"`
 	Message      string            `json:"message"`
 	DeploymentID string            `json:"deploymentId,omitempty"`
 	URL          string            `json:"url,omitempty"`
@@ -74,6 +105,17 @@ func extractRegion(functionURL, registryURI string) string {
 type DeployOptions struct {
 	Environment string
 	OrgID       string // Selected org ID for org-scoped deployments
+
+	// Timeout bounds the whole deployment. Zero uses the deployer's default.
+	Timeout time.Duration
+
+	// NoWait returns as soon as the platform reports a deployment ID,
+	// without waiting for the deployment to complete.
+	NoWait bool
+
+	// Region selects the platform region to deploy to. Empty uses the
+	// platform's default region.
+	Region string
 }
 
 // Deploy performs a deployment using the streaming Lambda Function URL
@@ -109,11 +151,14 @@ func (d *StreamingDeployer) Deploy(
 	}
 
 	// Add environment parameter if not production
+	q := reqURL.Query()
 	if opts.Environment != "" && opts.Environment != "production" {
-		q := reqURL.Query()
 		q.Set("environment", opts.Environment)
-		reqURL.RawQuery = q.Encode()
 	}
+	if opts.Region != "" {
+		q.Set("region", opts.Region)
+	}
+	reqURL.RawQuery = q.Encode()
 
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "POST", reqURL.String(), bytes.NewReader(ftlConfig))
@@ -151,9 +196,16 @@ func (d *StreamingDeployer) Deploy(
 		return fmt.Errorf("sign request: %w", err)
 	}
 
-	// Make the request
-	resp, err := d.httpClient.Do(req)
+	// Make the request, bounded by the configured timeout
+	client := d.httpClient
+	if opts.Timeout > 0 {
+		client = &http.Client{Transport: d.httpClient.Transport, Timeout: opts.Timeout}
+	}
+	resp, err := client.Do(req)
 	if err != nil {
+		if isTimeout(err) {
+			return fmt.Errorf("%w after %s", ErrDeployTimeout, client.Timeout)
+		}
 		return fmt.Errorf("send deployment request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
@@ -161,7 +213,7 @@ func (d *StreamingDeployer) Deploy(
 	// Check for non-200 status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("deployment failed with status %d: %s", resp.StatusCode, string(body))
+		return ftlerr.FromStatus(resp.StatusCode, body, fmt.Sprintf("deployment failed with status %d", resp.StatusCode))
 	}
 
 	// Process the NDJSON stream
@@ -189,9 +241,12 @@ func (d *StreamingDeployer) Deploy(
 		case "complete":
 			return nil // Deployment successful
 		case "error":
-			return fmt.Errorf("deployment failed: %s", event.Message)
-		case "progress":
-			// Continue processing
+			return ftlerr.New(ftlerr.DeployFailed, "deployment failed: "+event.Message).WithHint("Run 'ftl logs' to see what went wrong")
+		case "progress", "stage":
+			// Detach once the platform has accepted the deployment
+			if opts.NoWait && event.DeploymentID != "" {
+				return nil
+			}
 		default:
 			// Unknown event type, log and continue
 			fmt.Printf("Unknown event type: %s\n", event.Type)
@@ -199,9 +254,21 @@ func (d *StreamingDeployer) Deploy(
 	}
 
 	if err := scanner.Err(); err != nil {
+		if isTimeout(err) {
+			return fmt.Errorf("%w after %s", ErrDeployTimeout, client.Timeout)
+		}
 		return fmt.Errorf("error reading stream: %w", err)
 	}
 
 	// If we got here without a complete event, something went wrong
 	return fmt.Errorf("deployment stream ended without completion")
 }
+
+// isTimeout reports whether err was caused by a client or context deadline
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr interface{ Timeout() bool }
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -292,3 +292,92 @@ func TestStreamingDeployIncompleteStream(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "deployment stream ended without completion")
 }
+
+func TestStreamingDeployStages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		for _, event := range []StreamEvent{
+			{Type: "stage", Stage: StageImagePulled, Message: "Pulled images", DeploymentID: "deploy-123"},
+			{Type: "stage", Stage: StageManifestSynthesized, Message: "Synthesized"},
+			{Type: "stage", Stage: StageRouted, Message: "Routed"},
+			{Type: "stage", Stage: StageHealthy, Message: "Healthy"},
+			{Type: "complete", Message: "Done", URL: "https://app.example.com"},
+		} {
+			_ = encoder.Encode(event)
+		}
+	}))
+	defer server.Close()
+
+	creds := createTestCredentials(server.URL, "", "user", "user_123", nil)
+
+	var stages []string
+	err := NewStreamingDeployer().Deploy(context.Background(), []byte(`{}`), creds, DeployOptions{}, func(event StreamEvent) {
+		if event.Stage != "" {
+			stages = append(stages, event.Stage)
+		}
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{StageImagePulled, StageManifestSynthesized, StageRouted, StageHealthy}, stages)
+	assert.Equal(t, "Application healthy", StageLabel(StageHealthy))
+	assert.Equal(t, "custom stage", StageLabel("custom_stage"))
+}
+
+func TestStreamingDeployNoWait(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_ = json.NewEncoder(w).Encode(StreamEvent{Type: "progress", Message: "Accepted", DeploymentID: "deploy-456"})
+		w.(http.Flusher).Flush()
+		// Hold the stream open; the deployer must not wait for completion
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	creds := createTestCredentials(server.URL, "", "user", "user_123", nil)
+
+	var deploymentID string
+	err := NewStreamingDeployer().Deploy(context.Background(), []byte(`{}`), creds, DeployOptions{NoWait: true}, func(event StreamEvent) {
+		deploymentID = event.DeploymentID
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy-456", deploymentID)
+}
+
+func TestStreamingDeployTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_ = json.NewEncoder(w).Encode(StreamEvent{Type: "progress", Message: "Working"})
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	creds := createTestCredentials(server.URL, "", "user", "user_123", nil)
+
+	err := NewStreamingDeployer().Deploy(context.Background(), []byte(`{}`), creds, DeployOptions{Timeout: 200 * time.Millisecond}, nil)
+	assert.ErrorIs(t, err, ErrDeployTimeout)
+}
+
+func TestStreamingDeployRegionParam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "eu-west-1", r.URL.Query().Get("region"))
+		_ = json.NewEncoder(w).Encode(StreamEvent{Type: "complete", Message: "Success"})
+	}))
+	defer server.Close()
+
+	creds := createTestCredentials(server.URL, "", "user", "user_123", nil)
+	err := NewStreamingDeployer().Deploy(context.Background(), []byte(`{}`), creds, DeployOptions{Region: "eu-west-1"}, nil)
+	assert.NoError(t, err)
+}
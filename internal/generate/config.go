@@ -0,0 +1,217 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Changes summarizes how component variables were brought in sync with the
+// variables their code declares. Entries are "component.variable".
+type Changes struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Stale variables are set in the config but no longer declared; they are
+	// only removed with pruning
+	Stale []string `json:"stale,omitempty"`
+	// MissingRequired variables were added without a value and must be set
+	// before deploying
+	MissingRequired []string `json:"missing_required,omitempty"`
+}
+
+// Empty reports whether nothing was changed
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// reconcile compares the variables a component sets with the ones its code
+// declares and returns the values to add and the names to remove
+func reconcile(component string, existing map[string]bool, declared []Variable, prune bool, changes *Changes) (add []Variable, remove []string) {
+	names := make(map[string]bool, len(declared))
+	for _, v := range declared {
+		names[v.Name] = true
+		if existing[v.Name] {
+			continue
+		}
+		add = append(add, v)
+		changes.Added = append(changes.Added, component+"."+v.Name)
+		if v.Required {
+			changes.MissingRequired = append(changes.MissingRequired, component+"."+v.Name)
+		}
+	}
+
+	var stale []string
+	for name := range existing {
+		if !names[name] {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		if prune {
+			remove = append(remove, name)
+			changes.Removed = append(changes.Removed, component+"."+name)
+		} else {
+			changes.Stale = append(changes.Stale, component+"."+name)
+		}
+	}
+	return add, remove
+}
+
+// ApplyYAML updates the variables of the components in declared within an
+// ftl.yaml document. Comments and unrelated content are preserved.
+func ApplyYAML(data []byte, declared map[string][]Variable, prune bool) ([]byte, *Changes, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("expected a YAML mapping at the top level")
+	}
+
+	changes := &Changes{}
+	components := mappingValue(doc.Content[0], "components")
+	if components == nil || components.Kind != yaml.SequenceNode {
+		return data, changes, nil
+	}
+
+	for _, comp := range components.Content {
+		if comp.Kind != yaml.MappingNode {
+			continue
+		}
+		idNode := mappingValue(comp, "id")
+		if idNode == nil {
+			continue
+		}
+		vars, ok := declared[idNode.Value]
+		if !ok {
+			continue
+		}
+
+		varsNode := mappingValue(comp, "variables")
+		existing := make(map[string]bool)
+		if varsNode != nil && varsNode.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(varsNode.Content); i += 2 {
+				existing[varsNode.Content[i].Value] = true
+			}
+		}
+
+		add, remove := reconcile(idNode.Value, existing, vars, prune, changes)
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		if varsNode == nil || varsNode.Kind != yaml.MappingNode {
+			varsNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			comp.Content = append(comp.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "variables"},
+				varsNode,
+			)
+		}
+
+		for _, name := range remove {
+			for i := 0; i+1 < len(varsNode.Content); i += 2 {
+				if varsNode.Content[i].Value == name {
+					varsNode.Content = append(varsNode.Content[:i], varsNode.Content[i+2:]...)
+					break
+				}
+			}
+		}
+		for _, v := range add {
+			value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.Default}
+			if v.Default == "" {
+				value.Style = yaml.DoubleQuotedStyle
+			}
+			if v.Required {
+				value.LineComment = "required"
+			}
+			varsNode.Content = append(varsNode.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.Name},
+				value,
+			)
+		}
+	}
+
+	if changes.Empty() {
+		return data, changes, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return buf.Bytes(), changes, nil
+}
+
+// ApplyJSON updates the variables of the components in declared within an
+// ftl.json document
+func ApplyJSON(data []byte, declared map[string][]Variable, prune bool) ([]byte, *Changes, error) {
+	var doc map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	changes := &Changes{}
+	components, _ := doc["components"].([]interface{})
+	for _, raw := range components {
+		comp, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := comp["id"].(string)
+		vars, ok := declared[id]
+		if !ok {
+			continue
+		}
+
+		varsMap, _ := comp["variables"].(map[string]interface{})
+		existing := make(map[string]bool, len(varsMap))
+		for name := range varsMap {
+			existing[name] = true
+		}
+
+		add, remove := reconcile(id, existing, vars, prune, changes)
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		if varsMap == nil {
+			varsMap = make(map[string]interface{})
+			comp["variables"] = varsMap
+		}
+		for _, name := range remove {
+			delete(varsMap, name)
+		}
+		for _, v := range add {
+			varsMap[v.Name] = v.Default
+		}
+	}
+
+	if changes.Empty() {
+		return data, changes, nil
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return append(out, '\n'), changes, nil
+}
+
+// mappingValue returns the value for key in a YAML mapping node
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
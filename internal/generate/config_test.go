@@ -0,0 +1,91 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDeclared = map[string][]Variable{
+	"weather": {
+		{Name: "api_key", Required: true},
+		{Name: "base_url", Default: "https://api.example.com", HasDefault: true},
+		{Name: "units", Default: "metric", HasDefault: true},
+	},
+}
+
+const testYAML = `# Weather app
+name: weather-app
+components:
+  - id: weather
+    source: ./weather # built with tinygo
+    variables:
+      units: imperial
+      old_flag: "true"
+  - id: other
+    source: ./other
+`
+
+func TestApplyYAML(t *testing.T) {
+	out, changes, err := ApplyYAML([]byte(testYAML), testDeclared, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"weather.api_key", "weather.base_url"}, changes.Added)
+	assert.Equal(t, []string{"weather.old_flag"}, changes.Stale)
+	assert.Empty(t, changes.Removed)
+	assert.Equal(t, []string{"weather.api_key"}, changes.MissingRequired)
+
+	assert.Equal(t, `# Weather app
+name: weather-app
+components:
+  - id: weather
+    source: ./weather # built with tinygo
+    variables:
+      units: imperial
+      old_flag: "true"
+      api_key: "" # required
+      base_url: https://api.example.com
+  - id: other
+    source: ./other
+`, string(out))
+}
+
+func TestApplyYAML_PruneAndCreate(t *testing.T) {
+	declared := map[string][]Variable{
+		"weather": {{Name: "units", Default: "metric", HasDefault: true}},
+		"other":   {{Name: "token", Required: true}},
+	}
+	out, changes, err := ApplyYAML([]byte(testYAML), declared, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"weather.old_flag"}, changes.Removed)
+	assert.Equal(t, []string{"other.token"}, changes.Added)
+	assert.Contains(t, string(out), "    variables:\n      units: imperial\n  - id: other")
+	assert.Contains(t, string(out), "    source: ./other\n    variables:\n      token: \"\" # required\n")
+}
+
+func TestApplyYAML_UpToDate(t *testing.T) {
+	declared := map[string][]Variable{"weather": {{Name: "units"}, {Name: "old_flag"}}}
+	out, changes, err := ApplyYAML([]byte(testYAML), declared, true)
+	require.NoError(t, err)
+	assert.True(t, changes.Empty())
+	assert.Equal(t, testYAML, string(out))
+}
+
+func TestApplyJSON(t *testing.T) {
+	input := `{"name": "weather-app", "version": "0.1.0", "components": [{"id": "weather", "source": "./weather", "variables": {"units": "imperial"}}]}`
+	out, changes, err := ApplyJSON([]byte(input), testDeclared, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"weather.api_key", "weather.base_url"}, changes.Added)
+	assert.JSONEq(t, `{
+		"name": "weather-app",
+		"version": "0.1.0",
+		"components": [{
+			"id": "weather",
+			"source": "./weather",
+			"variables": {"units": "imperial", "api_key": "", "base_url": "https://api.example.com"}
+		}]
+	}`, string(out))
+}
@@ -0,0 +1,241 @@
+// Package generate derives FTL configuration from component source code
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// VarTag is the struct tag naming the Spin variable a field is loaded from:
+//
+//	type Config struct {
+//		ftl.Config
+//		APIKey     string `ftlvar:"api_key,required,secret"`
+//		BaseURL    string `ftlvar:",default=https://api.example.com"`
+//		MaxRetries int    `ftlvar:",default=3"`
+//	}
+//
+// An empty name is derived from the field name; "-" skips the field.
+const VarTag = "ftlvar"
+
+// variableName matches the variable names Spin accepts
+var variableName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Variable is a component variable declared with an ftlvar tag
+type Variable struct {
+	Name       string `json:"name"`
+	Field      string `json:"field"`
+	Struct     string `json:"struct"`
+	Default    string `json:"default,omitempty"`
+	HasDefault bool   `json:"-"`
+	Required   bool   `json:"required,omitempty"`
+	Secret     bool   `json:"secret,omitempty"`
+	Position   string `json:"position"`
+}
+
+// ScanDir returns the variables declared by ftlvar tags in the Go files of
+// dir, sorted by name. Test files and subdirectories are not scanned.
+func ScanDir(dir string) ([]Variable, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	seen := make(map[string]Variable)
+	var vars []Variable
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		var scanErr error
+		ast.Inspect(file, func(n ast.Node) bool {
+			if scanErr != nil {
+				return false
+			}
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			found, err := structVariables(fset, spec.Name.Name, st)
+			if err != nil {
+				scanErr = err
+				return false
+			}
+			for _, v := range found {
+				if prev, dup := seen[v.Name]; dup {
+					scanErr = fmt.Errorf("%s: variable %s is already declared at %s", v.Position, v.Name, prev.Position)
+					return false
+				}
+				seen[v.Name] = v
+				vars = append(vars, v)
+			}
+			return true
+		})
+		if scanErr != nil {
+			return nil, scanErr
+		}
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars, nil
+}
+
+func structVariables(fset *token.FileSet, structName string, st *ast.StructType) ([]Variable, error) {
+	var vars []Variable
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		rawTag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tag, ok := reflect.StructTag(rawTag).Lookup(VarTag)
+		if !ok {
+			continue
+		}
+
+		position := fset.Position(field.Pos()).String()
+		if !supportedVarType(field.Type) {
+			return nil, fmt.Errorf("%s: field %s.%s has a type that cannot hold a variable", position, structName, field.Names[0].Name)
+		}
+
+		for _, name := range field.Names {
+			v, err := parseVarTag(tag, name.Name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", position, err)
+			}
+			if v == nil {
+				continue
+			}
+			v.Struct = structName
+			v.Position = position
+			vars = append(vars, *v)
+		}
+	}
+	return vars, nil
+}
+
+// varTypes are the field types the SDK can parse a variable into
+var varTypes = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+func supportedVarType(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return varTypes[t.Name]
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && pkg.Name == "time" && t.Sel.Name == "Duration"
+	case *ast.ArrayType:
+		elem, ok := t.Elt.(*ast.Ident)
+		return t.Len == nil && ok && elem.Name == "string"
+	}
+	return false
+}
+
+// parseVarTag parses an ftlvar tag. It returns nil for skipped fields.
+func parseVarTag(tag, fieldName string) (*Variable, error) {
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return nil, nil
+	}
+	if name == "" {
+		name = SnakeCase(fieldName)
+	}
+	if !variableName.MatchString(name) {
+		return nil, fmt.Errorf("invalid variable name %q for field %s: use lowercase letters, digits and underscores", name, fieldName)
+	}
+
+	v := &Variable{Name: name, Field: fieldName}
+	for options != "" {
+		var option string
+		// A default value runs to the end of the tag so it may contain commas
+		if strings.HasPrefix(options, "default=") {
+			option, options = options, ""
+		} else {
+			option, options, _ = strings.Cut(options, ",")
+		}
+
+		switch {
+		case option == "required":
+			v.Required = true
+		case option == "secret":
+			v.Secret = true
+		case strings.HasPrefix(option, "required_with="), strings.HasPrefix(option, "oneof="):
+			// Cross-field constraints are checked when the component loads
+			// its config; the variables themselves stay optional
+		case strings.HasPrefix(option, "default="):
+			v.Default = strings.TrimPrefix(option, "default=")
+			v.HasDefault = true
+		default:
+			return nil, fmt.Errorf("unknown %s option %q on field %s", VarTag, option, fieldName)
+		}
+	}
+	if v.Required && v.HasDefault {
+		return nil, fmt.Errorf("field %s cannot be both required and have a default", fieldName)
+	}
+	return v, nil
+}
+
+// SnakeCase converts a Go field name to a variable name, keeping acronyms
+// together: APIKey becomes api_key and MaxRetries becomes max_retries.
+func SnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SourceDir returns the directory holding a local component's sources. The
+// source may point at the component directory or at its built .wasm file.
+func SourceDir(source, workdir string) string {
+	if workdir != "" {
+		return workdir
+	}
+	if strings.HasSuffix(source, ".wasm") {
+		return filepath.Dir(source)
+	}
+	return source
+}
+
+// HasGoFiles reports whether dir directly contains Go source files
+func HasGoFiles(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	return len(matches) > 0
+}
@@ -0,0 +1,109 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
+
+func TestScanDir(t *testing.T) {
+	dir := t.TempDir()
+	writeGoFile(t, dir, "config.go", `package main
+
+import (
+	"time"
+
+	ftl "github.com/fastertools/ftl/sdk/go"
+)
+
+type Config struct {
+	ftl.Config
+	APIKey     string `+"`ftlvar:\"api_key,required,secret\"`"+`
+	BaseURL    string `+"`ftlvar:\",default=https://api.example.com/v1?a=1,b=2\"`"+`
+	MaxRetries int `+"`json:\"max\" ftlvar:\"\"`"+`
+	Timeout    time.Duration `+"`ftlvar:\"timeout,default=10s\"`"+`
+	Regions    []string `+"`ftlvar:\"regions,required_with=api_key\"`"+`
+	Ignored    string `+"`ftlvar:\"-\"`"+`
+	Untagged   string
+}
+`)
+	writeGoFile(t, dir, "config_test.go", `package main
+
+type testConfig struct {
+	Secret string `+"`ftlvar:\"test_only\"`"+`
+}
+`)
+
+	vars, err := ScanDir(dir)
+	require.NoError(t, err)
+	require.Len(t, vars, 5)
+
+	assert.Equal(t, "api_key", vars[0].Name)
+	assert.True(t, vars[0].Required)
+	assert.True(t, vars[0].Secret)
+	assert.Equal(t, "Config", vars[0].Struct)
+	assert.Equal(t, "APIKey", vars[0].Field)
+	assert.Contains(t, vars[0].Position, "config.go:11")
+
+	assert.Equal(t, "base_url", vars[1].Name)
+	assert.True(t, vars[1].HasDefault)
+	assert.Equal(t, "https://api.example.com/v1?a=1,b=2", vars[1].Default)
+
+	assert.Equal(t, "max_retries", vars[2].Name)
+	assert.False(t, vars[2].HasDefault)
+
+	assert.Equal(t, "regions", vars[3].Name)
+	assert.False(t, vars[3].Required, "conditionally required variables stay optional")
+	assert.Equal(t, "timeout", vars[4].Name)
+	assert.Equal(t, "10s", vars[4].Default)
+}
+
+func TestScanDir_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"unsupported type", "type C struct { N map[string]int `ftlvar:\"n\"` }", "cannot hold a variable"},
+		{"invalid name", "type C struct { N string `ftlvar:\"Bad-Name\"` }", "invalid variable name"},
+		{"unknown option", "type C struct { N string `ftlvar:\"n,optional\"` }", "unknown ftlvar option"},
+		{"required with default", "type C struct { N string `ftlvar:\"n,required,default=x\"` }", "cannot be both required"},
+		{"duplicate", "type A struct { N string `ftlvar:\"n\"` }\ntype B struct { M string `ftlvar:\"n\"` }", "already declared"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeGoFile(t, dir, "main.go", "package main\n\n"+tt.source+"\n")
+			_, err := ScanDir(dir)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"APIKey":     "api_key",
+		"BaseURL":    "base_url",
+		"MaxRetries": "max_retries",
+		"Token":      "token",
+		"HTTPServer": "http_server",
+		"v2Endpoint": "v2_endpoint",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, SnakeCase(in), in)
+	}
+}
+
+func TestSourceDir(t *testing.T) {
+	assert.Equal(t, "components/echo", SourceDir("components/echo", ""))
+	assert.Equal(t, "components/echo", SourceDir("components/echo/echo.wasm", ""))
+	assert.Equal(t, "src", SourceDir("components/echo", "src"))
+}
@@ -26,7 +26,9 @@ type Manifest struct {
 // Component represents a component in the manifest
 type Component struct {
 	ID        string            `yaml:"id" json:"id"`
-	Source    interface{}       `yaml:"source" json:"source"` // Can be string or SourceRegistry
+	Type      string            `yaml:"type,omitempty" json:"type,omitempty"`     // "tool" (default) or "static"
+	Dir       string            `yaml:"dir,omitempty" json:"dir,omitempty"`       // Directory a static component serves
+	Source    interface{}       `yaml:"source,omitempty" json:"source,omitempty"` // Can be string or SourceRegistry
 	Build     *BuildConfig      `yaml:"build,omitempty" json:"build,omitempty"`
 	Variables map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
 }
@@ -157,7 +159,7 @@ func (c Component) MarshalJSON() ([]byte, error) {
 	// Create a new struct with the Source field handled properly
 	aux := struct {
 		componentAlias
-		Source interface{} `json:"source"`
+		Source interface{} `json:"source,omitempty"`
 	}{
 		componentAlias: componentAlias(c),
 	}
@@ -182,9 +184,12 @@ type SourceRegistry struct {
 	Version  string `yaml:"version" json:"version"`
 }
 
-// BuildConfig represents build configuration
+// BuildConfig represents build configuration. A Profile derives Command,
+// Watch and the component's source unless they are set; see ResolveBuilds.
 type BuildConfig struct {
-	Command string   `yaml:"command" json:"command"`
+	Profile string   `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Flags   []string `yaml:"flags,omitempty" json:"flags,omitempty"`
+	Command string   `yaml:"command,omitempty" json:"command,omitempty"`
 	Workdir string   `yaml:"workdir,omitempty" json:"workdir,omitempty"`
 	Watch   []string `yaml:"watch,omitempty" json:"watch,omitempty"`
 }
@@ -332,6 +337,33 @@ func (m *Manifest) RemoveComponent(id string) error {
 	return nil
 }
 
+// ResolveBuilds fills in the build command, watch patterns and source that
+// the build profile of each component derives, where the manifest does not
+// set them. Builds and watchers resolve the manifest first; it is not saved
+// resolved, so it keeps following the profiles.
+func (m *Manifest) ResolveBuilds() error {
+	for i := range m.Components {
+		comp := &m.Components[i]
+		if comp.Build == nil || comp.Build.Profile == "" {
+			continue
+		}
+		profile, err := validation.BuildProfileFor(comp.Build.Profile, comp.ID, comp.Build.Flags)
+		if err != nil {
+			return fmt.Errorf("component '%s': %w", comp.ID, err)
+		}
+		if comp.Build.Command == "" {
+			comp.Build.Command = profile.Command
+		}
+		if comp.Build.Watch == nil {
+			comp.Build.Watch = profile.Watch
+		}
+		if comp.Source == nil && comp.Type != validation.ComponentTypeStatic {
+			comp.Source = profile.Source(comp.Build.Workdir)
+		}
+	}
+	return nil
+}
+
 // ParseRegistrySource parses a registry string into a SourceRegistry
 // Format: registry/namespace:package@version
 func ParseRegistrySource(registry string) (*SourceRegistry, error) {
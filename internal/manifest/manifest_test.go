@@ -147,3 +147,41 @@ func TestRegistrySourceYAML(t *testing.T) {
 		t.Errorf("Expected local-comp, got %s", loaded.Components[1].ID)
 	}
 }
+
+func TestResolveBuilds(t *testing.T) {
+	m := &Manifest{
+		Name: "app",
+		Components: []Component{
+			{ID: "my-tool", Build: &BuildConfig{Profile: "rust", Workdir: "my-tool"}},
+			{ID: "geo", Source: "geo/geo.wasm", Build: &BuildConfig{Profile: "go", Command: "make", Workdir: "geo"}},
+			{ID: "plain", Source: "plain.wasm", Build: &BuildConfig{Command: "make build"}},
+		},
+	}
+	if err := m.ResolveBuilds(); err != nil {
+		t.Fatalf("ResolveBuilds failed: %v", err)
+	}
+
+	rust := m.Components[0]
+	if rust.Source != "my-tool/target/wasm32-wasip1/release/my_tool.wasm" {
+		t.Errorf("Expected the profile's source, got %v", rust.Source)
+	}
+	if rust.Build.Command != "cargo component build --release --target wasm32-wasip1" {
+		t.Errorf("Expected the profile's command, got %s", rust.Build.Command)
+	}
+	if len(rust.Build.Watch) != 2 {
+		t.Errorf("Expected the profile's watch patterns, got %v", rust.Build.Watch)
+	}
+
+	geo := m.Components[1]
+	if geo.Source != "geo/geo.wasm" || geo.Build.Command != "make" {
+		t.Errorf("Expected the configured source and command to be kept, got %v %s", geo.Source, geo.Build.Command)
+	}
+	if m.Components[2].Build.Watch != nil {
+		t.Errorf("Expected builds without a profile to be left alone, got %v", m.Components[2].Build.Watch)
+	}
+
+	m.Components[2].Build.Profile = "cobol"
+	if err := m.ResolveBuilds(); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+}
@@ -0,0 +1,163 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// errNoCDKApp reports a main.go without a CDK app to add components to
+var errNoCDKApp = errors.New("no CDK app found")
+
+// addCDKComponent adds a component built with the language's build profile
+// to the CDK app of a Go configuration. The app is the variable assigned
+// from NewApp; the AddComponent chain goes before the statement that builds
+// it, along with the comments above that statement.
+func addCDKComponent(src []byte, name, language string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse main.go: %w", err)
+	}
+
+	if cdkComponentExists(file, name) {
+		return nil, fmt.Errorf("component '%s' already exists", name)
+	}
+
+	var app string
+	var target ast.Stmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok || target != nil {
+			return target == nil
+		}
+		for _, stmt := range block.List {
+			if app == "" {
+				app = newAppVariable(stmt)
+				continue
+			}
+			if buildsApp(stmt, app) {
+				target = stmt
+				return false
+			}
+		}
+		if app != "" && target == nil {
+			// The app is built somewhere else; only the block that
+			// creates it is edited
+			app = ""
+		}
+		return true
+	})
+	if target == nil {
+		return nil, errNoCDKApp
+	}
+
+	// Insert at the start of the line of the first comment directly above
+	// the statement, or of the statement itself
+	line := fset.Position(target.Pos()).Line
+	for _, group := range file.Comments {
+		if fset.Position(group.End()).Line == line-1 {
+			line = fset.Position(group.Pos()).Line
+		}
+	}
+	offset := fset.Position(target.Pos()).Offset
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	for l := fset.Position(target.Pos()).Line; l > line; l-- {
+		start = bytes.LastIndexByte(src[:start-1], '\n') + 1
+	}
+	indent := src[start : start+len(src[start:])-len(bytes.TrimLeft(src[start:], " \t"))]
+
+	var chain strings.Builder
+	fmt.Fprintf(&chain, "%s%s.AddComponent(%s).\n", indent, app, strconv.Quote(name))
+	fmt.Fprintf(&chain, "%s\tWithBuildProfile(%s).\n", indent, strconv.Quote(language))
+	fmt.Fprintf(&chain, "%s\tWithWorkdir(%s).\n", indent, strconv.Quote(name))
+	fmt.Fprintf(&chain, "%s\tBuild()\n\n", indent)
+
+	var out bytes.Buffer
+	out.Write(src[:start])
+	out.WriteString(chain.String())
+	out.Write(src[start:])
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format main.go: %w", err)
+	}
+	return formatted, nil
+}
+
+// newAppVariable returns the variable stmt assigns a new CDK app to, if any
+func newAppVariable(stmt ast.Stmt) string {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return ""
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || !callsMethod(assign.Rhs[0], "NewApp") {
+		return ""
+	}
+	return ident.Name
+}
+
+// buildsApp reports whether stmt calls Build on the app variable
+func buildsApp(stmt ast.Stmt, app string) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if ok && sel.Sel.Name == "Build" {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == app {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// callsMethod reports whether a method chain calls the named method
+func callsMethod(expr ast.Expr, method string) bool {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if sel.Sel.Name == method {
+			return true
+		}
+		expr = sel.X
+	}
+}
+
+// cdkComponentExists reports whether the file adds a component named name
+func cdkComponentExists(file *ast.File, name string) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return !found
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "AddComponent" {
+			return !found
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if value, err := strconv.Unquote(lit.Value); err == nil && value == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
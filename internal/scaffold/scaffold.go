@@ -5,9 +5,11 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"cuelang.org/go/cue"
@@ -44,26 +46,21 @@ type ComponentVersions struct {
 	MCPGateway    string
 }
 
-// Scaffolder handles component generation using CUE templates
-type Scaffolder struct {
-	ctx       *cue.Context
-	templates cue.Value
-	versions  Versions
-}
-
-// NewScaffolder creates a new scaffolder with embedded templates
-func NewScaffolder() (*Scaffolder, error) {
-	ctx := cuecontext.New()
-
-	// Parse the release-please manifest
+// ReleaseVersions returns the versions of the CLI, SDKs and platform
+// components released with this build, from the embedded release-please
+// manifest
+func ReleaseVersions() (Versions, error) {
 	var manifest map[string]string
 	if err := json.Unmarshal([]byte(manifestJSON), &manifest); err != nil {
-		return nil, fmt.Errorf("failed to parse release manifest: %w", err)
+		return Versions{}, fmt.Errorf("failed to parse release manifest: %w", err)
 	}
 
-	// Extract versions from the manifest
-	versions := Versions{
-		FTLCli: manifest["."],
+	cli := manifest["."]
+	if cli == "" {
+		cli = manifest["cmd/ftl"]
+	}
+	return Versions{
+		FTLCli: cli,
 		SDK: SDKVersions{
 			Go:         manifest["sdk/go"],
 			Rust:       manifest["sdk/rust"],
@@ -74,6 +71,23 @@ func NewScaffolder() (*Scaffolder, error) {
 			MCPAuthorizer: manifest["components/mcp-authorizer"],
 			MCPGateway:    manifest["components/mcp-gateway"],
 		},
+	}, nil
+}
+
+// Scaffolder handles component generation using CUE templates
+type Scaffolder struct {
+	ctx       *cue.Context
+	templates cue.Value
+	versions  Versions
+}
+
+// NewScaffolder creates a new scaffolder with embedded templates
+func NewScaffolder() (*Scaffolder, error) {
+	ctx := cuecontext.New()
+
+	versions, err := ReleaseVersions()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create templates with versions
@@ -101,29 +115,81 @@ _versions: {
 	}, nil
 }
 
+// ComponentPlan is what GenerateComponent writes for a new component
+type ComponentPlan struct {
+	// Files holds the content of the component's files, by path
+	Files map[string][]byte
+	// ConfigFile is the configuration the component is added to, or empty
+	// when the app discovers its components itself
+	ConfigFile string
+	// Config is the updated content of ConfigFile
+	Config []byte
+}
+
 // GenerateComponent creates a new component from templates
 func (s *Scaffolder) GenerateComponent(name, language string) error {
+	plan, err := s.PlanComponent(name, language)
+	if err != nil {
+		return err
+	}
+	return plan.Write()
+}
+
+// PlanComponent renders a new component and the configuration it is added
+// to without writing them
+func (s *Scaffolder) PlanComponent(name, language string) (*ComponentPlan, error) {
 	// Validate inputs
 	if err := s.validateInputs(name, language); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Create component instance from template
 	component, err := s.createComponentInstance(name, language)
 	if err != nil {
-		return fmt.Errorf("failed to create component instance: %w", err)
+		return nil, fmt.Errorf("failed to create component instance: %w", err)
 	}
 
 	// Generate files
-	if err := s.generateFiles(name, component); err != nil {
-		return fmt.Errorf("failed to generate files: %w", err)
+	files, err := s.generateFiles(name, component)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate files: %w", err)
 	}
 
 	// Update ftl.yaml
-	if err := s.updateFTLConfig(name, component); err != nil {
-		return fmt.Errorf("failed to update ftl.yaml: %w", err)
+	configFile, config, err := s.updateFTLConfig(name, component)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ftl.yaml: %w", err)
 	}
 
+	return &ComponentPlan{Files: files, ConfigFile: configFile, Config: config}, nil
+}
+
+// Paths returns the paths of the component's files, sorted
+func (p *ComponentPlan) Paths() []string {
+	paths := make([]string, 0, len(p.Files))
+	for path := range p.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write creates the component's files and updates the configuration
+func (p *ComponentPlan) Write() error {
+	for _, path := range p.Paths() {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, p.Files[path], 0600); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", path, err)
+		}
+	}
+	if p.ConfigFile == "" {
+		return nil
+	}
+	if err := os.WriteFile(p.ConfigFile, p.Config, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p.ConfigFile, err)
+	}
 	return nil
 }
 
@@ -142,8 +208,18 @@ func (s *Scaffolder) GenerateProject(projectDir, name, description, format strin
 		return fmt.Errorf("invalid format: %s", format)
 	}
 
-	// Get project template
-	templatePath := fmt.Sprintf("#ProjectTemplates.%s", format)
+	return s.writeProject(fmt.Sprintf("#ProjectTemplates.%s", format), projectDir, name, description)
+}
+
+// GenerateWorkspace creates a monorepo workspace: a top-level Go CDK app that
+// composes the components in components/, plus apps/ and shared/ directories
+func (s *Scaffolder) GenerateWorkspace(projectDir, name, description string) error {
+	return s.writeProject("#WorkspaceProject", projectDir, name, description)
+}
+
+// writeProject fills the project template at templatePath and writes its
+// files, which may be in subdirectories, to projectDir
+func (s *Scaffolder) writeProject(templatePath, projectDir, name, description string) error {
 	templateValue := s.templates.LookupPath(cue.ParsePath(templatePath))
 	if templateValue.Err() != nil {
 		return fmt.Errorf("failed to get project template: %w", templateValue.Err())
@@ -175,7 +251,10 @@ func (s *Scaffolder) GenerateProject(projectDir, name, description, format strin
 			return fmt.Errorf("failed to extract content for %s: %w", filename, err)
 		}
 
-		filePath := filepath.Join(projectDir, filename)
+		filePath := filepath.Join(projectDir, filepath.FromSlash(filename))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", filename, err)
+		}
 		if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
 			return fmt.Errorf("failed to write %s: %w", filename, err)
 		}
@@ -199,7 +278,7 @@ func (s *Scaffolder) validateInputs(name, language string) error {
 	}
 
 	// Validate language
-	validLanguages := []string{"rust", "typescript", "python", "go"}
+	validLanguages := append(s.ListLanguages(), StaticTemplate)
 	found := false
 	for _, lang := range validLanguages {
 		if lang == language {
@@ -248,13 +327,8 @@ component: #Templates[%q] & {
 	return component, nil
 }
 
-// generateFiles creates all the component files
-func (s *Scaffolder) generateFiles(name string, component cue.Value) error {
-	// Create component directory
-	if err := os.MkdirAll(name, 0750); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
+// generateFiles renders all the component files, by path
+func (s *Scaffolder) generateFiles(name string, component cue.Value) (map[string][]byte, error) {
 	// Get language for special handling
 	language, _ := component.LookupPath(cue.ParsePath("language")).String()
 
@@ -264,15 +338,17 @@ func (s *Scaffolder) generateFiles(name string, component cue.Value) error {
 	// Extract files map
 	files := component.LookupPath(cue.ParsePath("files"))
 	if !files.Exists() {
-		return fmt.Errorf("no files defined in template")
+		return nil, fmt.Errorf("no files defined in template")
 	}
 
-	// Iterate over files and create them
+	// Iterate over files and render them
 	iter, err := files.Fields()
 	if err != nil {
-		return fmt.Errorf("failed to iterate files: %w", err)
+		return nil, fmt.Errorf("failed to iterate files: %w", err)
 	}
 
+	rendered := make(map[string][]byte)
+
 	for iter.Next() {
 		path := iter.Selector().Unquoted()
 		content := iter.Value()
@@ -280,7 +356,7 @@ func (s *Scaffolder) generateFiles(name string, component cue.Value) error {
 		// Get content as string
 		contentStr, err := content.String()
 		if err != nil {
-			return fmt.Errorf("failed to get content for %s: %w", path, err)
+			return nil, fmt.Errorf("failed to get content for %s: %w", path, err)
 		}
 
 		// Apply language-specific substitutions
@@ -289,59 +365,60 @@ func (s *Scaffolder) generateFiles(name string, component cue.Value) error {
 			if path == "Cargo.toml" {
 				contentStr = strings.ReplaceAll(contentStr, `name = "`+name+`"`, `name = "`+nameUnderscore+`"`)
 			}
-			// In Makefile, the built WASM file uses underscores
-			if path == "Makefile" {
-				contentStr = strings.ReplaceAll(contentStr, "/"+name+".wasm", "/"+nameUnderscore+".wasm")
-				contentStr = strings.ReplaceAll(contentStr, " "+name+".wasm", " "+nameUnderscore+".wasm")
-			}
 		}
 
-		// Create full path
-		fullPath := filepath.Join(name, path)
-
-		// Create directory if needed
-		dir := filepath.Dir(fullPath)
-		if dir != "." && dir != name {
-			if err := os.MkdirAll(dir, 0750); err != nil {
-				return fmt.Errorf("failed to create directory %s: %w", dir, err)
-			}
-		}
-
-		// Write file
-		if err := os.WriteFile(fullPath, []byte(contentStr), 0600); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", fullPath, err)
-		}
+		rendered[filepath.Join(name, path)] = []byte(contentStr)
 	}
 
-	return nil
+	return rendered, nil
 }
 
-// updateFTLConfig adds the new component to ftl.yaml or ftl.json
-func (s *Scaffolder) updateFTLConfig(name string, component cue.Value) error {
+// updateFTLConfig adds the new component to ftl.yaml, ftl.json or the Go
+// CDK app in main.go, returning the configuration file and its updated
+// content
+func (s *Scaffolder) updateFTLConfig(name string, component cue.Value) (string, []byte, error) {
+	// A workspace's top-level app discovers its components itself
+	if inWorkspaceComponentsDir() {
+		return "", nil, nil
+	}
+
 	// Detect configuration format
 	format, configPath, err := s.detectConfigFormat()
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
+	language, _ := component.LookupPath(cue.ParsePath("language")).String()
+
 	// Handle unsupported formats with helpful messages
+	if language == StaticTemplate && (format == "go" || format == "cue") {
+		return "", nil, fmt.Errorf("%s configurations require manual component registration - "+
+			"add component with id=%s type=static dir=./%s to your application", format, name, name)
+	}
 	if format == "go" {
-		// Extract build configuration for the helpful message
-		language, _ := component.LookupPath(cue.ParsePath("language")).String()
-		wasmPath := s.getWasmPath(name, language)
-
-		return fmt.Errorf("go-based configurations require manual component registration - "+
-			"add this to your main.go: "+
-			"app.AddComponent(\"%s\")."+
-			"FromLocal(\"./%s\")."+
-			"WithBuild(\"cd %s && make build\")."+
-			"Build()", name, wasmPath, name)
+		data, err := os.ReadFile(filepath.Clean(configPath))
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+		}
+		output, err := addCDKComponent(data, name, language)
+		if errors.Is(err, errNoCDKApp) {
+			return "", nil, fmt.Errorf("go-based configurations require manual component registration - "+
+				"add this to your main.go: "+
+				"app.AddComponent(\"%s\")."+
+				"WithBuildProfile(\"%s\")."+
+				"WithWorkdir(\"%s\")."+
+				"Build()", name, language, name)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return configPath, output, nil
 	}
 
 	if format == "cue" {
-		return fmt.Errorf("cue configurations require manual component registration - "+
-			"add component with id=%s source=./%s/%s.wasm build.command='make build' build.workdir=%s to your app.cue components array",
-			name, name, name, name)
+		return "", nil, fmt.Errorf("cue configurations require manual component registration - "+
+			"add component with id=%s build.profile=%s build.workdir=%s to your app.cue components array",
+			name, language, name)
 	}
 
 	// Read existing config
@@ -349,7 +426,7 @@ func (s *Scaffolder) updateFTLConfig(name string, component cue.Value) error {
 	configPath = filepath.Clean(configPath)
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to read %s: %w", configPath, err)
+		return "", nil, fmt.Errorf("failed to read %s: %w", configPath, err)
 	}
 
 	// Parse and validate the config
@@ -362,57 +439,32 @@ func (s *Scaffolder) updateFTLConfig(name string, component cue.Value) error {
 	case "yaml":
 		validatedValue, err = v.ValidateYAML(data)
 		if err != nil {
-			return fmt.Errorf("failed to validate %s: %w", configPath, err)
+			return "", nil, fmt.Errorf("failed to validate %s: %w", configPath, err)
 		}
 		manifest, err = validation.ExtractApplication(validatedValue)
 		if err != nil {
-			return fmt.Errorf("failed to extract application: %w", err)
+			return "", nil, fmt.Errorf("failed to extract application: %w", err)
 		}
 	case "json":
 		validatedValue, err = v.ValidateJSON(data)
 		if err != nil {
-			return fmt.Errorf("failed to validate %s: %w", configPath, err)
+			return "", nil, fmt.Errorf("failed to validate %s: %w", configPath, err)
 		}
 		manifest, err = validation.ExtractApplication(validatedValue)
 		if err != nil {
-			return fmt.Errorf("failed to extract application: %w", err)
+			return "", nil, fmt.Errorf("failed to extract application: %w", err)
 		}
 	default:
-		return fmt.Errorf("unsupported configuration format: %s", format)
+		return "", nil, fmt.Errorf("unsupported configuration format: %s", format)
 	}
 
-	// Extract build configuration from component
-	build := component.LookupPath(cue.ParsePath("build"))
-
-	command, _ := build.LookupPath(cue.ParsePath("command")).String()
-
-	// Extract watch patterns
-	watchIter, _ := build.LookupPath(cue.ParsePath("watch")).List()
-	var watchPatterns []string
-	for watchIter.Next() {
-		pattern, _ := watchIter.Value().String()
-		watchPatterns = append(watchPatterns, pattern)
-	}
-
-	// Extract language to determine WASM output path
-	language, _ := component.LookupPath(cue.ParsePath("language")).String()
-	wasmPath := s.getWasmPath(name, language)
-
 	// Create new component config
-	newComponent := &validation.Component{
-		ID:     name,
-		Source: &validation.LocalSource{Path: wasmPath},
-		Build: &validation.BuildConfig{
-			Command: command,
-			Workdir: name,
-			Watch:   watchPatterns,
-		},
-	}
+	newComponent := s.newComponentConfig(name, language, component)
 
 	// Check for duplicate
 	for _, comp := range manifest.Components {
 		if comp.ID == name {
-			return fmt.Errorf("component '%s' already exists", name)
+			return "", nil, fmt.Errorf("component '%s' already exists", name)
 		}
 	}
 
@@ -430,24 +482,43 @@ func (s *Scaffolder) updateFTLConfig(name string, component cue.Value) error {
 		encoder := yaml.NewEncoder(&buf)
 		encoder.SetIndent(2)
 		if err := encoder.Encode(flatConfig); err != nil {
-			return fmt.Errorf("failed to encode config: %w", err)
+			return "", nil, fmt.Errorf("failed to encode config: %w", err)
 		}
 		output = buf.Bytes()
 	case "json":
 		var err error
 		output, err = json.MarshalIndent(flatConfig, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to encode config: %w", err)
+			return "", nil, fmt.Errorf("failed to encode config: %w", err)
 		}
 		// Add trailing newline for consistency
 		output = append(output, '\n')
 	}
 
-	if err := os.WriteFile(configPath, output, 0600); err != nil {
-		return fmt.Errorf("failed to write %s: %w", configPath, err)
+	return configPath, output, nil
+}
+
+// newComponentConfig returns the ftl.yaml entry of a generated component
+func (s *Scaffolder) newComponentConfig(name, language string, component cue.Value) *validation.Component {
+	// Static components are served by FTL's static component
+	if language == StaticTemplate {
+		return &validation.Component{
+			ID:   name,
+			Type: validation.ComponentTypeStatic,
+			Dir:  "./" + name,
+		}
 	}
 
-	return nil
+	// The build profile derives the command, watch patterns and source
+	profile, _ := component.LookupPath(cue.ParsePath("build.profile")).String()
+
+	return &validation.Component{
+		ID: name,
+		Build: &validation.BuildConfig{
+			Profile: profile,
+			Workdir: name,
+		},
+	}
 }
 
 // detectConfigFormat detects which configuration format is being used
@@ -471,7 +542,8 @@ func (s *Scaffolder) detectConfigFormat() (string, string, error) {
 	if _, err := os.Stat("main.go"); err == nil {
 		// Double-check it's actually an FTL Go config by looking for the CDK import
 		data, err := os.ReadFile("main.go")
-		if err == nil && strings.Contains(string(data), "synthesis.NewCDK") {
+		if err == nil && (strings.Contains(string(data), "synthesis.NewCDK") ||
+			strings.Contains(string(data), `"github.com/fastertools/ftl/cdk"`)) {
 			return "go", "main.go", nil
 		}
 	}
@@ -480,22 +552,15 @@ func (s *Scaffolder) detectConfigFormat() (string, string, error) {
 		"Run 'ftl init' to create a new project")
 }
 
-// getWasmPath returns the WASM output path for a component
-func (s *Scaffolder) getWasmPath(name, language string) string {
-	switch language {
-	case "rust":
-		// Rust uses underscores in the binary name
-		binaryName := strings.ReplaceAll(name, "-", "_")
-		return filepath.Join(name, binaryName+".wasm")
-	case "typescript":
-		return filepath.Join(name, "dist", name+".wasm")
-	case "python":
-		return filepath.Join(name, "app.wasm")
-	case "go":
-		return filepath.Join(name, "main.wasm")
-	default:
-		return filepath.Join(name, name+".wasm")
+// inWorkspaceComponentsDir reports whether the working directory is the
+// components/ directory of a workspace created by GenerateWorkspace
+func inWorkspaceComponentsDir() bool {
+	wd, err := os.Getwd()
+	if err != nil || filepath.Base(wd) != "components" {
+		return false
 	}
+	data, err := os.ReadFile(filepath.Join("..", "main.go"))
+	return err == nil && strings.Contains(string(data), "addComponents(app, componentsDir)")
 }
 
 // convertToFlatStructure converts validation types to flat YAML/JSON structure
@@ -532,14 +597,26 @@ func convertToFlatStructure(app *validation.Application) map[string]interface{}
 	if len(app.Components) > 0 {
 		components := make([]map[string]interface{}, 0, len(app.Components))
 		for _, comp := range app.Components {
+			// Components built with a profile keep the command, watch
+			// patterns and source it derives unless others were given
+			comp = comp.WithoutDerived()
+
 			c := make(map[string]interface{})
 			c["id"] = comp.ID
+			if comp.IsStatic() {
+				c["type"] = comp.Type
+				c["dir"] = comp.Dir
+			}
 
-			// Convert source
+			// Convert source. Static components keep the default source
+			// unless one was given.
 			switch src := comp.Source.(type) {
 			case *validation.LocalSource:
 				c["source"] = src.Path
 			case *validation.RegistrySource:
+				if comp.IsStatic() && src.Package == StaticPackage {
+					break
+				}
 				source := make(map[string]interface{})
 				source["registry"] = src.Registry
 				source["package"] = src.Package
@@ -550,6 +627,12 @@ func convertToFlatStructure(app *validation.Application) map[string]interface{}
 			// Convert build config
 			if comp.Build != nil {
 				build := make(map[string]interface{})
+				if comp.Build.Profile != "" {
+					build["profile"] = comp.Build.Profile
+				}
+				if len(comp.Build.Flags) > 0 {
+					build["flags"] = comp.Build.Flags
+				}
 				if comp.Build.Command != "" {
 					build["command"] = comp.Build.Command
 				}
@@ -582,6 +665,13 @@ func convertToFlatStructure(app *validation.Application) map[string]interface{}
 	return result
 }
 
+// StaticTemplate is the template of static resources components, which
+// GenerateComponent accepts in place of a language
+const StaticTemplate = "static"
+
+// StaticPackage is the registry package static components default to
+const StaticPackage = "fastertools:mcp-static"
+
 // ListLanguages returns the available languages
 func (s *Scaffolder) ListLanguages() []string {
 	return []string{"rust", "typescript", "python", "go"}
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+
+	"github.com/fastertools/ftl/validation"
 )
 
 func TestValidateComponentName(t *testing.T) {
@@ -66,31 +68,6 @@ func TestListLanguages(t *testing.T) {
 	assert.Contains(t, languages, "go")
 }
 
-func TestGetWasmPath(t *testing.T) {
-	scaffolder, _ := NewScaffolder()
-
-	tests := []struct {
-		name     string
-		compName string
-		language string
-		expected string
-	}{
-		{"rust normal", "my-component", "rust", "my-component/my_component.wasm"},
-		{"rust with hyphens", "test-comp-name", "rust", "test-comp-name/test_comp_name.wasm"},
-		{"typescript", "my-component", "typescript", "my-component/dist/my-component.wasm"},
-		{"python", "my-component", "python", "my-component/app.wasm"},
-		{"go", "my-component", "go", "my-component/main.wasm"},
-		{"unknown", "my-component", "unknown", "my-component/my-component.wasm"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := scaffolder.getWasmPath(tt.compName, tt.language)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestDetectConfigFormat(t *testing.T) {
 	scaffolder, _ := NewScaffolder()
 
@@ -177,6 +154,7 @@ func TestValidateInputs(t *testing.T) {
 		{"valid", "my-component", "rust", false},
 		{"invalid name", "123-comp", "rust", true},
 		{"invalid language", "my-component", "java", true},
+		{"static template", "my-docs", "static", false},
 		{"empty name", "", "rust", true},
 		{"empty language", "my-component", "", true},
 	}
@@ -220,7 +198,7 @@ func TestGenerateComponent_BasicFlow(t *testing.T) {
 	assert.DirExists(t, "test-tool")
 	assert.FileExists(t, "test-tool/Cargo.toml")
 	assert.FileExists(t, "test-tool/src/lib.rs")
-	assert.FileExists(t, "test-tool/Makefile")
+	assert.NoFileExists(t, "test-tool/Makefile")
 
 	// Check Cargo.toml has correct package name (underscores)
 	cargoContent, _ := os.ReadFile("test-tool/Cargo.toml")
@@ -232,18 +210,11 @@ func TestGenerateComponent_BasicFlow(t *testing.T) {
 	_ = yaml.Unmarshal(updatedData, &updatedManifest)
 	components := updatedManifest["components"].([]interface{})
 	assert.Len(t, components, 1)
-	// Handle both map[string]interface{} and map[interface{}]interface{}
-	var compID, compSource string
-	switch comp := components[0].(type) {
-	case map[string]interface{}:
-		compID = comp["id"].(string)
-		compSource = comp["source"].(string)
-	case map[interface{}]interface{}:
-		compID = comp["id"].(string)
-		compSource = comp["source"].(string)
-	}
-	assert.Equal(t, "test-tool", compID)
-	assert.Equal(t, "test-tool/test_tool.wasm", compSource)
+	// The build profile derives the command, watch patterns and source
+	assert.Equal(t, map[string]interface{}{
+		"id":    "test-tool",
+		"build": map[string]interface{}{"profile": "rust", "workdir": "test-tool"},
+	}, components[0])
 }
 
 func TestGenerateComponent_TypeScript(t *testing.T) {
@@ -272,7 +243,6 @@ func TestGenerateComponent_TypeScript(t *testing.T) {
 	assert.FileExists(t, "ts-tool/package.json")
 	assert.FileExists(t, "ts-tool/tsconfig.json")
 	assert.FileExists(t, "ts-tool/src/index.ts")
-	assert.FileExists(t, "ts-tool/Makefile")
 
 	// Check package.json
 	pkgContent, _ := os.ReadFile("ts-tool/package.json")
@@ -304,7 +274,41 @@ func TestGenerateComponent_Python(t *testing.T) {
 	// Check Python files
 	assert.FileExists(t, "py-tool/src/main.py")
 	assert.FileExists(t, "py-tool/pyproject.toml")
-	assert.FileExists(t, "py-tool/Makefile")
+}
+
+func TestGenerateComponent_Static(t *testing.T) {
+	scaffolder, _ := NewScaffolder()
+
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	_ = os.Chdir(tmpDir)
+
+	manifestData := map[string]interface{}{
+		"name":    "test-app",
+		"version": "0.1.0",
+		"components": []interface{}{
+			map[string]interface{}{"id": "handbook", "type": "static", "dir": "./handbook"},
+		},
+	}
+	data, _ := yaml.Marshal(manifestData)
+	_ = os.WriteFile("ftl.yaml", data, 0600)
+
+	err := scaffolder.GenerateComponent("docs", StaticTemplate)
+	require.NoError(t, err)
+
+	assert.FileExists(t, "docs/index.md")
+	assert.FileExists(t, "docs/data/example.json")
+	assert.NoFileExists(t, "docs/Makefile")
+
+	updatedData, err := os.ReadFile("ftl.yaml")
+	require.NoError(t, err)
+	var updated map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(updatedData, &updated))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "handbook", "type": "static", "dir": "./handbook"},
+		map[string]interface{}{"id": "docs", "type": "static", "dir": "./docs"},
+	}, updated["components"])
 }
 
 func TestGenerateComponent_Go(t *testing.T) {
@@ -332,11 +336,17 @@ func TestGenerateComponent_Go(t *testing.T) {
 	// Check Go files
 	assert.FileExists(t, "go-tool/main.go")
 	assert.FileExists(t, "go-tool/go.mod")
-	assert.FileExists(t, "go-tool/Makefile")
 
 	// Check go.mod
 	goModContent, _ := os.ReadFile("go-tool/go.mod")
 	assert.Contains(t, string(goModContent), "module github.com/example/go-tool")
+
+	// Check the tests and the schema snapshot they compare against
+	testContent, _ := os.ReadFile("go-tool/main_test.go")
+	assert.Contains(t, string(testContent), `CheckGoldenSchemas("testdata/schemas.golden.json")`)
+	assert.Contains(t, string(testContent), `"example_tool": {"message": "Hello, World!"}`)
+	golden, _ := os.ReadFile("go-tool/testdata/schemas.golden.json")
+	assert.Contains(t, string(golden), `"name": "example_tool"`)
 }
 
 func TestGenerateComponent_DuplicateName(t *testing.T) {
@@ -489,25 +499,55 @@ func TestGenerateFiles_RustNameConversion(t *testing.T) {
 	cargoStr := string(cargoContent)
 	assert.Contains(t, cargoStr, `name = "my_cool_tool"`)
 
-	// Check Makefile references underscore version
-	makefileContent, _ := os.ReadFile("my-cool-tool/Makefile")
-	makefileStr := string(makefileContent)
-	assert.Contains(t, makefileStr, "my_cool_tool.wasm")
+	// Check the source derived from the build profile uses the crate name
+	updatedData, _ := os.ReadFile("ftl.yaml")
+	v, err := validation.New().ValidateYAML(updatedData)
+	require.NoError(t, err)
+	app, err := validation.ExtractApplication(v)
+	require.NoError(t, err)
+	assert.Equal(t, &validation.LocalSource{Path: "my-cool-tool/target/wasm32-wasip1/release/my_cool_tool.wasm"}, app.Components[0].Source)
+}
+
+func TestUpdateFTLConfig_KeepsBuildProfiles(t *testing.T) {
+	scaffolder, _ := NewScaffolder()
+
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	_ = os.Chdir(tmpDir)
+
+	// An existing component overriding the watch patterns of its profile
+	_ = os.WriteFile("ftl.yaml", []byte(`name: test-app
+version: 0.1.0
+components:
+  - id: geo
+    build:
+      profile: go
+      flags: ["-opt=2"]
+      workdir: geo
+      watch: ["**/*.go"]
+`), 0600)
+
+	require.NoError(t, scaffolder.GenerateComponent("py-tool", "python"))
 
-	// Check ftl.yaml has correct WASM path
 	updatedData, _ := os.ReadFile("ftl.yaml")
-	var updatedManifest map[interface{}]interface{}
-	_ = yaml.Unmarshal(updatedData, &updatedManifest)
-	components := updatedManifest["components"].([]interface{})
-	// Handle both map[string]interface{} and map[interface{}]interface{}
-	var compSource string
-	switch comp := components[0].(type) {
-	case map[string]interface{}:
-		compSource = comp["source"].(string)
-	case map[interface{}]interface{}:
-		compSource = comp["source"].(string)
-	}
-	assert.Equal(t, "my-cool-tool/my_cool_tool.wasm", compSource)
+	var updated map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(updatedData, &updated))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"id": "geo",
+			"build": map[string]interface{}{
+				"profile": "go",
+				"flags":   []interface{}{"-opt=2"},
+				"workdir": "geo",
+				"watch":   []interface{}{"**/*.go"},
+			},
+		},
+		map[string]interface{}{
+			"id":    "py-tool",
+			"build": map[string]interface{}{"profile": "python", "workdir": "py-tool"},
+		},
+	}, updated["components"])
 }
 
 func TestCreateComponentInstance(t *testing.T) {
@@ -544,7 +584,7 @@ func TestGenerateComponent_CreatesCorrectStructure(t *testing.T) {
 	}{
 		{
 			"rust",
-			[]string{"Cargo.toml", "src/lib.rs", "Makefile"},
+			[]string{"Cargo.toml", "src/lib.rs"},
 		},
 		{
 			"typescript",
@@ -552,11 +592,11 @@ func TestGenerateComponent_CreatesCorrectStructure(t *testing.T) {
 		},
 		{
 			"python",
-			[]string{"src/main.py", "pyproject.toml", "Makefile"},
+			[]string{"src/main.py", "pyproject.toml"},
 		},
 		{
 			"go",
-			[]string{"main.go", "go.mod", "Makefile"},
+			[]string{"main.go", "main_test.go", "testdata/schemas.golden.json", "go.mod"},
 		},
 	}
 
@@ -590,3 +630,93 @@ func TestGenerateComponent_CreatesCorrectStructure(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateWorkspace_AddComponent(t *testing.T) {
+	scaffolder, err := NewScaffolder()
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	require.NoError(t, scaffolder.GenerateWorkspace(tmpDir, "mono", "A workspace"))
+	assert.FileExists(t, filepath.Join(tmpDir, "main.go"))
+	assert.FileExists(t, filepath.Join(tmpDir, "components", ".gitkeep"))
+
+	readme, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(readme), "# mono\n\nA workspace")
+
+	// Components added inside the workspace are discovered by its app, so
+	// there is no configuration to update
+	require.NoError(t, os.Chdir(filepath.Join(tmpDir, "components")))
+	require.NoError(t, scaffolder.GenerateComponent("weather", "go"))
+	assert.FileExists(t, filepath.Join("weather", "main.go"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "ftl.yaml"))
+}
+
+const cdkMainGo = `package main
+
+import (
+	"fmt"
+
+	"github.com/fastertools/ftl/cdk"
+)
+
+func main() {
+	ftl := cdk.New()
+	app := ftl.NewApp("test-app").
+		SetVersion("0.1.0")
+
+	// Build and synthesize to spin.toml
+	builtCDK := app.Build()
+	manifest, _ := builtCDK.Synthesize()
+	fmt.Print(manifest)
+}
+`
+
+func TestAddCDKComponent(t *testing.T) {
+	out, err := addCDKComponent([]byte(cdkMainGo), "my-tool", "rust")
+	require.NoError(t, err)
+
+	expected := `		SetVersion("0.1.0")
+
+	app.AddComponent("my-tool").
+		WithBuildProfile("rust").
+		WithWorkdir("my-tool").
+		Build()
+
+	// Build and synthesize to spin.toml
+	builtCDK := app.Build()
+`
+	assert.Contains(t, string(out), expected)
+
+	_, err = addCDKComponent(out, "my-tool", "go")
+	assert.ErrorContains(t, err, "component 'my-tool' already exists")
+
+	_, err = addCDKComponent([]byte("package main\n\nfunc main() {}\n"), "my-tool", "rust")
+	assert.ErrorIs(t, err, errNoCDKApp)
+}
+
+func TestGenerateComponent_CDKMainGo(t *testing.T) {
+	scaffolder, _ := NewScaffolder()
+
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	_ = os.Chdir(tmpDir)
+
+	require.NoError(t, os.WriteFile("main.go", []byte(cdkMainGo), 0600))
+
+	plan, err := scaffolder.PlanComponent("my-tool", "go")
+	require.NoError(t, err)
+	assert.Equal(t, "main.go", plan.ConfigFile)
+	assert.Contains(t, plan.Paths(), filepath.Join("my-tool", "main.go"))
+	assert.NoDirExists(t, "my-tool", "planning should not write files")
+
+	require.NoError(t, plan.Write())
+	assert.FileExists(t, filepath.Join("my-tool", "main.go"))
+	data, err := os.ReadFile("main.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `WithBuildProfile("go")`)
+}
@@ -11,7 +11,8 @@ package scaffold
 	name:     #ComponentName
 	language: #Language
 	
-	// Build configuration (used in ftl.yaml)
+	// Build configuration (used in ftl.yaml). The language's build profile
+	// derives the build command, watch patterns and output path.
 	build: #BuildConfig
 	
 	// Files to generate
@@ -20,8 +21,7 @@ package scaffold
 
 // Build configuration structure
 #BuildConfig: {
-	command: string
-	watch: [...string]
+	profile: #Language
 }
 
 // Rust component template
@@ -29,10 +29,7 @@ package scaffold
 	language: "rust"
 	name: string
 	
-	build: {
-		command: "make build"
-		watch: ["src/**/*.rs", "Cargo.toml"]
-	}
+	build: profile: "rust"
 	
 	files: {
 		"README.md": """
@@ -44,17 +41,17 @@ package scaffold
 			
 			### Build the component
 			```bash
-			make build
+			# From the project root
+			ftl build
 			```
 			
-			### Run tests
-			```bash
-			make test
-			```
+			FTL builds the component with its `rust` build profile, which runs
+			`cargo component build --release --target wasm32-wasip1`. Install
+			cargo-component with `cargo install cargo-component`.
 			
-			### Clean build artifacts
+			### Run tests
 			```bash
-			make clean
+			cargo test
 			```
 			
 			## Integration with FTL
@@ -71,9 +68,14 @@ package scaffold
 			## Configuration
 			
 			The component configuration in `ftl.yaml`:
-			- `source`: Path to the compiled WASM file
-			- `build.command`: Build command (make build)
-			- `build.watch`: Files to watch for auto-rebuild
+			- `build.profile`: Build profile (rust), which derives the build
+			  command, the files to watch for auto-rebuild and the path of the
+			  compiled WASM file
+			- `build.workdir`: Directory the build runs in
+			- `build.flags`: Extra flags for cargo-component
+			
+			Set `source`, `build.command` or `build.watch` to override what
+			the profile derives.
 			
 			## Adding Tools
 			
@@ -121,21 +123,6 @@ package scaffold
 			pedantic = { level = "warn", priority = -1 }
 			"""
 		
-		"Makefile": """
-			.PHONY: build clean test
-
-			build:
-			\tcargo build --release --target wasm32-wasip1
-			\tcp target/wasm32-wasip1/release/\(name).wasm \(name).wasm
-
-			clean:
-			\tcargo clean
-			\trm -f \(name).wasm
-
-			test:
-			\tcargo test
-			"""
-		
 		"src/lib.rs": """
 			use ftl_sdk::{tools, text, ToolResponse};
 			use serde::Deserialize;
@@ -174,10 +161,7 @@ package scaffold
 	language: "typescript"
 	name: string
 	
-	build: {
-		command: "make build"
-		watch: ["src/**/*.ts", "src/**/*.js", "package.json", "tsconfig.json"]
-	}
+	build: profile: "typescript"
 	
 	files: {
 		"README.md": """
@@ -194,11 +178,15 @@ package scaffold
 			
 			### Build the component
 			```bash
-			make build
-			# or
-			npm run build
+			# From the project root
+			ftl build
 			```
 			
+			FTL builds the component with its `typescript` build profile, which
+			installs dependencies and runs `npm run build`: the sources are
+			bundled with esbuild and componentized with j2w (jco). Extra flags
+			for j2w go in `build.flags` in `ftl.yaml`.
+			
 			### Type checking
 			```bash
 			npm run typecheck
@@ -277,31 +265,6 @@ package scaffold
 			"""
 		
 		
-		"Makefile": """
-			.PHONY: build clean test install format lint
-
-			install:
-			\tnpm install
-
-			build: install
-			\tnpm run build
-
-			clean:
-			\trm -rf dist node_modules build
-
-			test: install
-			\tnpm test
-
-			format: install
-			\tnpm run format || echo "No format script defined"
-
-			lint: install
-			\tnpm run lint || echo "No lint script defined"
-
-			dev: install format lint test
-			\t@echo "Development checks passed!"
-			"""
-		
 		"src/index.ts": """
 			import { createTools, ToolResponse } from 'ftl-sdk'
 			import * as z from 'zod'
@@ -353,10 +316,7 @@ package scaffold
 	language: "python"
 	name: string
 	
-	build: {
-		command: "make build"
-		watch: ["src/**/*.py", "pyproject.toml"]
-	}
+	build: profile: "python"
 	
 	files: {
 		"README.md": """
@@ -368,26 +328,31 @@ package scaffold
 			
 			### Setup development environment
 			```bash
-			make install-dev
-			# or
+			python3 -m venv .venv && . .venv/bin/activate
 			pip install -e ".[dev]"
 			```
 			
 			### Build the component
 			```bash
-			make build
+			# From the project root
+			ftl build
 			```
 			
+			FTL builds the component with its `python` build profile, which
+			installs the component and componentize-py in `.venv` and runs
+			`componentize-py -w spin-http componentize src.main -p . -o app.wasm`.
+			Extra flags for componentize-py go in `build.flags` in `ftl.yaml`.
+			
 			### Run tests
 			```bash
-			make test
+			pytest
 			```
 			
 			### Code quality
 			```bash
-			make format    # Format with black
-			make lint      # Lint with ruff
-			make type-check # Type check with mypy
+			black src tests      # Format
+			ruff check src tests # Lint
+			mypy src             # Type check
 			```
 			
 			## Integration with FTL
@@ -509,75 +474,6 @@ package scaffold
 			]
 			"""
 		
-		"Makefile": """
-			.PHONY: help install install-dev format lint type-check test test-cov clean build
-
-			help:
-			\t@echo "Available commands:"
-			\t@echo "  install      Install project dependencies"
-			\t@echo "  install-dev  Install project with development dependencies"
-			\t@echo "  format       Format code with black"
-			\t@echo "  lint         Run linting with ruff"
-			\t@echo "  type-check   Run type checking with mypy"
-			\t@echo "  test         Run tests"
-			\t@echo "  test-cov     Run tests with coverage"
-			\t@echo "  clean        Clean build artifacts"
-			\t@echo "  build        Build WebAssembly module"
-
-			install:
-			\tpip install -e .
-
-			install-dev:
-			\tpip install -e ".[dev]"
-			\t@echo "Installing componentize-py for WebAssembly builds..."
-			\tpip install componentize-py
-
-			format:
-			\tblack src tests
-
-			lint:
-			\truff check src tests --fix
-
-			type-check:
-			\tmypy src
-
-			test:
-			\tpytest
-
-			test-cov:
-			\tpytest --cov=src --cov-report=term-missing --cov-report=html
-
-			clean:
-			\trm -rf build dist *.egg-info
-			\trm -rf .coverage htmlcov .pytest_cache .mypy_cache .ruff_cache
-			\trm -f app.wasm
-			\tfind . -type d -name __pycache__ -exec rm -rf {} +
-			\tfind . -type f -name "*.pyc" -delete
-
-			build: clean
-			\t@echo "Building WebAssembly module..."
-			\t@if [ ! -d "venv" ]; then \\
-			\t\techo "Creating Python virtual environment..."; \\
-			\t\tpython3 -m venv venv; \\
-			\tfi
-			\t@echo "Installing dependencies..."
-			\t@. venv/bin/activate && pip install --upgrade pip --quiet
-			\t@. venv/bin/activate && pip install componentize-py --quiet
-			\t@. venv/bin/activate && pip install -e . --quiet
-			\t@. venv/bin/activate && pip install ftl-sdk --quiet
-			\t@echo "Building WASM component..."
-			\t@. venv/bin/activate && componentize-py -w spin-http componentize src.main -p . -o app.wasm
-			\t@echo "✓ Build successful!"
-
-			# Development workflow
-			dev: install-dev
-			\t@echo "Development environment ready!"
-			\t@echo "Run 'make test' to run tests"
-			\t@echo "Run 'make format' to format code"
-			\t@echo "Run 'make lint' to run linting"
-			\t@echo "Run 'make build' to build WebAssembly module"
-			"""
-		
 		"src/__init__.py": ""
 		
 		"src/main.py": """
@@ -640,6 +536,7 @@ package scaffold
 			build/
 			.env
 			venv/
+			.venv/
 			.coverage
 			htmlcov/
 			"""
@@ -651,10 +548,7 @@ package scaffold
 	language: "go"
 	name: string
 	
-	build: {
-		command: "make build"
-		watch: ["*.go", "go.mod"]
-	}
+	build: profile: "go"
 	
 	files: {
 		"README.md": """
@@ -664,26 +558,27 @@ package scaffold
 			
 			## Development
 			
-			### Setup development environment
-			```bash
-			make dev-setup
-			```
-			
 			### Build the component
 			```bash
-			make build
+			# From the project root
+			ftl build
 			```
 			
+			FTL builds the component with its `go` build profile, which runs
+			`tinygo build -target=wasip1 -gc=leaking -scheduler=none -no-debug -o main.wasm .`.
+			Extra flags for TinyGo, such as `-opt=2`, go in `build.flags` in
+			`ftl.yaml`.
+			
 			### Run tests
 			```bash
-			make test
+			# Tests build the SDK without Spin
+			go test -tags test ./...
 			```
 			
 			### Code quality
 			```bash
-			make fmt     # Format code
-			make lint    # Run linter
-			make quality # Run all quality checks
+			go fmt ./...
+			golangci-lint run
 			```
 			
 			## Integration with FTL
@@ -704,16 +599,21 @@ package scaffold
 			
 			## Adding Tools
 			
-			Edit `main.go` and add new tools in the `ftl.Handle` call:
+			Edit `main.go` and add new tools to the `tools` map:
 			```go
-			ftl.Handle(ftl.Tools{
-			    "yourTool": {
-			        Description: "Tool description",
-			        InputSchema: YourInput{},
-			        Handler:     YourToolFunction,
-			    },
-			})
+			"yourTool": {
+			    Description: "Tool description",
+			    InputSchema: map[string]interface{}{"type": "object"},
+			    Handler:     YourToolFunction,
+			},
 			```
+			
+			Then add an example input for the tool to `examples` in `main_test.go`.
+			`go test -tags test ./...` checks each example against its tool's schema and calls the
+			tool with it, and compares the tool schemas with the snapshot in
+			`testdata/schemas.golden.json`, so changes that would break clients are
+			caught before publishing. After an intended schema change, update the
+			snapshot with `FTL_UPDATE_GOLDEN=1 go test -tags test -run TestToolSchemas ./...`.
 			"""
 		
 		"go.mod": """
@@ -726,125 +626,119 @@ package scaffold
 			)
 			"""
 		
-		"Makefile": """
-			.PHONY: help dev-setup fmt lint test test-cov clean build quality
-
-			help:
-			\t@echo "Available commands:"
-			\t@echo "  dev-setup    Install development dependencies"
-			\t@echo "  fmt          Format code with gofmt"
-			\t@echo "  lint         Run linting with golangci-lint"
-			\t@echo "  test         Run tests"
-			\t@echo "  test-cov     Run tests with coverage"
-			\t@echo "  clean        Clean build artifacts"
-			\t@echo "  build        Build WebAssembly module"
-			\t@echo "  quality      Run all quality checks"
-
-			dev-setup:
-			\t@echo "Installing development dependencies..."
-			\t@which golangci-lint > /dev/null || go install github.com/golangci/golangci-lint/cmd/golangci-lint@latest
-			\t@which tinygo > /dev/null || echo "TinyGo not found. Please install from https://tinygo.org"
-			\tgo mod download
-			\t@echo "Development environment ready!"
-
-			fmt:
-			\tgo fmt ./...
-
-			lint:
-			\t@which golangci-lint > /dev/null || (echo "golangci-lint not found. Run 'make dev-setup' first." && exit 1)
-			\tgolangci-lint run
-
-			test:
-			\tgo test -v ./...
-
-			test-cov:
-			\tgo test -v -coverprofile=coverage.out ./...
-			\tgo tool cover -html=coverage.out -o coverage.html
-			\t@echo "Coverage report generated: coverage.html"
-
-			clean:
-			\trm -f *.wasm app.wasm
-			\trm -f coverage.out coverage.html
-			\tgo clean
-
-			build: clean
-			\t@echo "Building WebAssembly module..."
-			\t@which tinygo > /dev/null || (echo "TinyGo not found. Please install from https://tinygo.org" && exit 1)
-			\ttinygo build -target=wasip1 -gc=leaking -scheduler=none -no-debug -o main.wasm main.go
-			\t@echo "Built: main.wasm"
-
-			# Run all quality checks
-			quality: fmt lint test
-			\t@echo "All quality checks passed!"
-
-			# Verify TinyGo compatibility
-			verify-tinygo:
-			\t@echo "Checking TinyGo compatibility..."
-			\ttinygo build -target=wasip1 -gc=leaking -scheduler=none -no-debug -o /tmp/test.wasm main.go && rm /tmp/test.wasm
-			\t@echo "TinyGo build successful!"
-
-			# Quick development cycle
-			dev: fmt lint test build
-			\t@echo "Development cycle complete!"
-			"""
-		
 		"main.go": """
 			package main
 
 			import (
-			\t"github.com/fastertools/ftl/sdk/go/ftl"
+				ftl "github.com/fastertools/ftl/sdk/go"
 			)
 
-			// ExampleToolInput defines the input for the example tool
-			type ExampleToolInput struct {
-			\tMessage string `json:"message" description:"The input message to process"`
+			// tools are the component's tools, keyed by name
+			var tools = map[string]ftl.ToolDefinition{
+				"exampleTool": {
+					Description: "An example tool that processes messages",
+					InputSchema: map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"message": map[string]interface{}{
+								"type":        "string",
+								"description": "The input message to process",
+							},
+						},
+						"required": []string{"message"},
+					},
+					Handler: ExampleTool,
+				},
+
+				// Add more tools here as needed, with an example input in
+				// main_test.go:
+				// "anotherTool": {
+				//     Description: "Another tool description",
+				//     InputSchema: map[string]interface{}{"type": "object"},
+				//     Handler:     AnotherTool,
+				// },
 			}
 
 			// ExampleTool processes messages
-			func ExampleTool(input ExampleToolInput) ftl.ToolResponse {
-			\t// TODO: Implement your tool logic here
-			\treturn ftl.Text("Processed: " + input.Message)
+			func ExampleTool(input map[string]interface{}) ftl.ToolResponse {
+				message, _ := input["message"].(string)
+				// TODO: Implement your tool logic here
+				return ftl.Text("Processed: " + message)
 			}
 
-			func main() {
-			\t// Register tools
-			\tftl.Handle(ftl.Tools{
-			\t\t"exampleTool": {
-			\t\t\tDescription: "An example tool that processes messages",
-			\t\t\tInputSchema: ExampleToolInput{},
-			\t\t\tHandler:     ExampleTool,
-			\t\t},
-			\t\t
-			\t\t// Add more tools here as needed:
-			\t\t// "anotherTool": {
-			\t\t//     Description: "Another tool description",
-			\t\t//     InputSchema: AnotherInput{},
-			\t\t//     Handler:     AnotherTool,
-			\t\t// },
-			\t})
+			func init() {
+				ftl.CreateTools(tools)
 			}
+
+			func main() {}
 			"""
 		
 		"main_test.go": """
 			package main
 
 			import (
-			\t"testing"
+				"testing"
+
+				"github.com/fastertools/ftl/sdk/go/ftltest"
 			)
 
+			// examples holds an example input for each tool, by the name the tool
+			// is listed under
+			var examples = map[string]map[string]interface{}{
+				"example_tool": {"message": "Hello, World!"},
+			}
+
+			// TestToolSchemas catches schema changes that would break clients.
+			// After an intended change, update the snapshot with
+			// FTL_UPDATE_GOLDEN=1 go test -tags test -run TestToolSchemas ./...
+			func TestToolSchemas(t *testing.T) {
+				ftltest.New(t, tools).CheckGoldenSchemas("testdata/schemas.golden.json")
+			}
+
+			// TestToolExamples checks each tool's example input against its schema
+			// and calls the tool with it
+			func TestToolExamples(t *testing.T) {
+				for _, tool := range ftltest.New(t, tools).Tools() {
+					t.Run(tool.Name, func(t *testing.T) {
+						input, ok := examples[tool.Name]
+						if !ok {
+							t.Fatalf("no example input for '%s'", tool.Name)
+						}
+						ftltest.New(t, tools).CheckExample(tool.Name, input)
+					})
+				}
+			}
+
 			func TestExampleTool(t *testing.T) {
-			\tinput := ExampleToolInput{
-			\t\tMessage: "Hello, World!",
-			\t}
-			\t
-			\tresponse := ExampleTool(input)
-			\t
-			\tif response.Text != "Processed: Hello, World!" {
-			\t\tt.Errorf("Expected 'Processed: Hello, World!', got '%s'", response.Text)
-			\t}
+				response := ExampleTool(map[string]interface{}{"message": "Hello, World!"})
+				if got := response.Content[0].Text; got != "Processed: Hello, World!" {
+					t.Errorf("Expected 'Processed: Hello, World!', got '%s'", got)
+				}
 			}
 			"""
 		
+		"testdata/schemas.golden.json": """
+			[
+			  {
+			    "name": "example_tool",
+			    "description": "An example tool that processes messages",
+			    "inputSchema": {
+			      "properties": {
+			        "message": {
+			          "description": "The input message to process",
+			          "type": "string"
+			        }
+			      },
+			      "required": [
+			        "message"
+			      ],
+			      "type": "object"
+			    }
+			  }
+			]
+
+			"""
+		
 		".gitignore": """
 			*.wasm
 			*.exe
@@ -855,12 +749,45 @@ package scaffold
 	}
 }
 
+// Static resources template: files the gateway serves as MCP resources,
+// with no code or build
+#StaticComponent: {
+	name:     #ComponentName
+	language: "static"
+
+	files: [string]: string
+	files: {
+		"index.md": """
+			# \(name)
+			
+			Static resources served by the FTL gateway.
+			
+			Every file in this directory is an MCP resource with the URI
+			`ftl://\(name)/<path>`, for example `ftl://\(name)/index.md`.
+			Clients list them with `resources/list` and read them with
+			`resources/read`.
+			
+			Add markdown docs, JSON datasets or any other files here and run
+			`ftl up` to serve them.
+			"""
+
+		"data/example.json": """
+			{
+			  "name": "\(name)",
+			  "description": "Example dataset served as an MCP resource"
+			}
+			
+			"""
+	}
+}
+
 // Template selector based on language
 #Templates: {
 	rust:       #RustComponent
 	typescript: #TypeScriptComponent
 	python:     #PythonComponent
 	go:         #GoComponent
+	static:     #StaticComponent
 }
 
 // ===========================================================================
@@ -884,8 +811,10 @@ package scaffold
 #CommonGitignore: """
 	.spin/
 	spin.toml
+	spin.mock-auth.toml
 	*.wasm
-	.ftl/
+	.ftl/*
+	!.ftl/policy.yaml
 	.env
 	.env.local
 	target/
@@ -1052,4 +981,198 @@ package scaffold
 	json: #JSONProject
 	cue:  #CUEProject
 	go:   #GoProject
-}
\ No newline at end of file
+}
+// ===========================================================================
+// Workspace Templates
+// ===========================================================================
+
+// Monorepo workspace: a top-level Go CDK app composing every component in
+// components/, further apps in apps/, and code shared between components in
+// shared/
+#WorkspaceProject: {
+	name:        string
+	description: string
+
+	files: {
+		"main.go": """
+			package main
+			
+			import (
+				"fmt"
+				"log"
+				"os"
+				"path/filepath"
+			
+				"github.com/fastertools/ftl/cdk"
+			)
+			
+			// componentsDir holds one directory per component. Every directory
+			// with a language's marker file is added to the app under its
+			// directory name.
+			const componentsDir = "components"
+			
+			// languages maps a component's marker file to the build profile that
+			// builds it. The profile derives the build command, the files that
+			// trigger a rebuild and the WebAssembly module it produces.
+			var languages = []struct {
+				marker  string
+				profile string
+			}{
+				{"Cargo.toml", "rust"},
+				{"package.json", "typescript"},
+				{"pyproject.toml", "python"},
+				{"go.mod", "go"},
+			}
+			
+			func main() {
+				// Create your FTL application using the CDK
+				ftl := cdk.New()
+				app := ftl.NewApp("\(name)").
+					SetDescription("\(description)").
+					SetVersion("0.1.0")
+			
+				if err := addComponents(app, componentsDir); err != nil {
+					log.Fatalf("Failed to add components: %v", err)
+				}
+			
+				// Add components from other sources here
+				// Example:
+				// app.AddComponent("search").
+				//     FromRegistry("ghcr.io", "acme:search", "1.0.0").
+				//     Build()
+			
+				// Build and synthesize to spin.toml
+				builtCDK := app.Build()
+				manifest, err := builtCDK.Synthesize()
+				if err != nil {
+					log.Fatalf("Failed to synthesize: %v", err)
+				}
+			
+				// Output the manifest
+				fmt.Print(manifest)
+			}
+			
+			// addComponents adds every component found in dir to the app
+			func addComponents(app *cdk.AppBuilder, dir string) error {
+				entries, err := os.ReadDir(dir)
+				if err != nil {
+					return err
+				}
+				for _, entry := range entries {
+					componentDir := filepath.Join(dir, entry.Name())
+					if !entry.IsDir() {
+						continue
+					}
+					for _, lang := range languages {
+						if _, err := os.Stat(filepath.Join(componentDir, lang.marker)); err != nil {
+							continue
+						}
+						app.AddComponent(entry.Name()).
+							WithBuildProfile(lang.profile).
+							WithWorkdir(filepath.ToSlash(componentDir)).
+							Build()
+						break
+					}
+				}
+				return nil
+			}
+			"""
+
+		"go.mod": """
+			module \(name)
+			
+			go 1.24
+			
+			require github.com/fastertools/ftl v\(_versions.ftl_cli)
+			
+			// For local development, uncomment and adjust the path:
+			// replace github.com/fastertools/ftl => ../path/to/ftl
+			"""
+
+		"Makefile": """
+			.PHONY: all build build-components build-apps synth up deploy test clean help
+			
+			# Component and app directories are discovered, so new ones need no changes here
+			COMPONENTS := $(patsubst %/,%,$(wildcard components/*/))
+			APPS := $(patsubst %/,%,$(sort $(dir $(wildcard apps/*/ftl.yaml apps/*/ftl.json apps/*/main.go))))
+			
+			all: build
+			
+			help:
+			\t@echo "Available targets:"
+			\t@echo "  build            - Build every component and app"
+			\t@echo "  build-components - Build the components in components/"
+			\t@echo "  build-apps       - Build the apps in apps/"
+			\t@echo "  synth            - Generate spin.toml for the top-level app"
+			\t@echo "  up               - Run the top-level app locally"
+			\t@echo "  deploy           - Deploy the top-level app"
+			\t@echo "  test             - Test every component"
+			\t@echo "  clean            - Remove build artifacts"
+			
+			build: build-components build-apps synth
+			
+			# The top-level app builds every component with its language's build profile
+			build-components:
+			\tftl build -c main.go
+			
+			build-apps: build-components
+			\t@for dir in $(APPS); do \\
+			\t\techo "→ Building $$dir"; \\
+			\t\t(cd $$dir && ftl build) || exit 1; \\
+			\tdone
+			
+			synth:
+			\tftl synth main.go -o spin.toml
+			
+			up: build
+			\tftl up
+			
+			deploy:
+			\tftl deploy
+			
+			test:
+			\t@for dir in $(COMPONENTS); do \\
+			\t\techo "→ Testing $$dir"; \\
+			\t\tif [ -f $$dir/Cargo.toml ]; then (cd $$dir && cargo test) || exit 1; \\
+			\t\telif [ -f $$dir/package.json ]; then (cd $$dir && npm test) || exit 1; \\
+			\t\telif [ -f $$dir/pyproject.toml ]; then (cd $$dir && pytest) || exit 1; \\
+			\t\telif [ -f $$dir/go.mod ]; then (cd $$dir && go test -tags test ./...) || exit 1; \\
+			\t\tfi; \\
+			\tdone
+			
+			clean:
+			\trm -f spin.toml
+			"""
+
+		"README.md": """
+			# \(name)
+			
+			\(description)
+			
+			## Layout
+			
+			- `main.go` - the top-level app, written with the FTL Go CDK. It adds
+			  every component in `components/` automatically.
+			- `components/` - one directory per MCP tool component. Create new
+			  components with `cd components && ftl add <name>`.
+			- `apps/` - further FTL apps that compose a subset of the components,
+			  each in its own directory with an `ftl.yaml` or Go CDK `main.go`.
+			- `shared/` - code shared between components, such as libraries
+			  referenced as path dependencies.
+			
+			## Building
+			
+			```bash
+			make build   # build every component and app
+			make up      # run the top-level app locally
+			make deploy  # deploy the top-level app
+			```
+			"""
+
+		"components/.gitkeep": ""
+		"apps/.gitkeep":       ""
+		"shared/.gitkeep":     ""
+
+		".gitignore": #CommonGitignore
+	}
+}
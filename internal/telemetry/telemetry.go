@@ -0,0 +1,156 @@
+// Package telemetry sends anonymous, opt-in usage metrics for the FTL CLI
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fastertools/ftl/ftlerr"
+)
+
+// sendTimeout bounds how long the CLI waits for the telemetry endpoint
+const sendTimeout = 2 * time.Second
+
+// Error categories reported instead of error messages, which may contain
+// names, paths or other identifying details
+const (
+	CategoryNone     = ""
+	CategoryUsage    = "usage"
+	CategoryAuth     = "auth"
+	CategoryNetwork  = "network"
+	CategoryTimeout  = "timeout"
+	CategoryCanceled = "canceled"
+	CategoryOther    = "other"
+)
+
+// Event is a single command invocation. It deliberately carries no
+// arguments, flag values, file names or error messages.
+type Event struct {
+	AnonymousID   string    `json:"anonymous_id"`
+	Command       string    `json:"command"`
+	DurationMS    int64     `json:"duration_ms"`
+	Success       bool      `json:"success"`
+	ErrorCategory string    `json:"error_category,omitempty"`
+	Version       string    `json:"version"`
+	OS            string    `json:"os"`
+	Arch          string    `json:"arch"`
+	CI            bool      `json:"ci"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// NewEvent describes a finished command run
+func NewEvent(anonymousID, command, version string, duration time.Duration, category string) Event {
+	return Event{
+		AnonymousID:   anonymousID,
+		Command:       command,
+		DurationMS:    duration.Milliseconds(),
+		Success:       category == CategoryNone,
+		ErrorCategory: category,
+		Version:       version,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		CI:            os.Getenv("CI") != "",
+		Timestamp:     time.Now().UTC(),
+	}
+}
+
+// DisabledByEnv reports whether the environment forbids telemetry, which
+// overrides the user's configuration
+func DisabledByEnv() bool {
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return true
+	}
+	switch strings.ToLower(os.Getenv("FTL_TELEMETRY")) {
+	case "0", "off", "false", "no":
+		return true
+	}
+	return false
+}
+
+// CategorizeError maps an error to a coarse category
+func CategorizeError(err error) string {
+	if err == nil {
+		return CategoryNone
+	}
+	if errors.Is(err, context.Canceled) {
+		return CategoryCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return CategoryTimeout
+		}
+		return CategoryNetwork
+	}
+
+	if ftlerr.IsAuth(err) {
+		return CategoryAuth
+	}
+	if ftlerr.CodeOf(err) == ftlerr.DeployTimeout {
+		return CategoryTimeout
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not logged in"), strings.Contains(msg, "unauthorized"), strings.Contains(msg, "authenticat"):
+		return CategoryAuth
+	case strings.Contains(msg, "unknown command"), strings.Contains(msg, "unknown flag"), strings.Contains(msg, "accepts"):
+		return CategoryUsage
+	}
+	return CategoryOther
+}
+
+// Client posts events to a telemetry endpoint
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the given endpoint
+func NewClient(endpoint string) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Send posts the event. Callers should ignore errors: telemetry must never
+// affect the outcome of a command.
+func (c *Client) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send telemetry: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("send telemetry: status %d", resp.StatusCode)
+	}
+	return nil
+}
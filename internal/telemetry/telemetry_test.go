@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/ftlerr"
+)
+
+func TestDisabledByEnv(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("FTL_TELEMETRY", "")
+	assert.False(t, DisabledByEnv())
+
+	t.Setenv("FTL_TELEMETRY", "off")
+	assert.True(t, DisabledByEnv())
+
+	t.Setenv("FTL_TELEMETRY", "")
+	t.Setenv("DO_NOT_TRACK", "1")
+	assert.True(t, DisabledByEnv())
+
+	t.Setenv("DO_NOT_TRACK", "0")
+	assert.False(t, DisabledByEnv())
+}
+
+func TestCategorizeError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, CategoryNone},
+		{context.Canceled, CategoryCanceled},
+		{fmt.Errorf("deploy: %w", context.DeadlineExceeded), CategoryTimeout},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, CategoryNetwork},
+		{errors.New("not logged in to FTL. Run 'ftl auth login' first"), CategoryAuth},
+		{fmt.Errorf("list apps: %w", ftlerr.FromStatus(403, nil, "API error")), CategoryAuth},
+		{fmt.Errorf("deployment failed: %w", ftlerr.New(ftlerr.DeployTimeout, "deployment timed out")), CategoryTimeout},
+		{errors.New(`unknown command "frobnicate" for "ftl"`), CategoryUsage},
+		{errors.New("failed to load manifest"), CategoryOther},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CategorizeError(tt.err), "%v", tt.err)
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	var got Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := NewEvent("anon-1", "ftl deploy", "1.2.3", 1500*time.Millisecond, CategoryNetwork)
+	require.NoError(t, NewClient(server.URL).Send(context.Background(), event))
+
+	assert.Equal(t, "anon-1", got.AnonymousID)
+	assert.Equal(t, "ftl deploy", got.Command)
+	assert.Equal(t, int64(1500), got.DurationMS)
+	assert.False(t, got.Success)
+	assert.Equal(t, CategoryNetwork, got.ErrorCategory)
+	assert.Equal(t, "1.2.3", got.Version)
+}
+
+func TestClientSend_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL).Send(context.Background(), NewEvent("a", "ftl list", "dev", 0, CategoryNone))
+	assert.Error(t, err)
+}
@@ -0,0 +1,294 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exportTimeout bounds a single OTLP request
+const exportTimeout = 10 * time.Second
+
+// errExporterShutdown is returned when spans are exported after Shutdown
+var errExporterShutdown = errors.New("exporter is shut down")
+
+// Exporter sends spans to an OTLP/HTTP endpoint using the JSON encoding
+type Exporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+
+	mu       sync.Mutex
+	shutdown bool
+}
+
+// NewExporter creates an exporter posting to the given /v1/traces URL
+func NewExporter(endpoint string, headers map[string]string) *Exporter {
+	return &Exporter{
+		endpoint: endpoint,
+		headers:  headers,
+		client:   &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// ExportSpans implements sdktrace.SpanExporter
+func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	shutdown := e.shutdown
+	e.mu.Unlock()
+	if shutdown {
+		return errExporterShutdown
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(encodeSpans(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("OTLP endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Shutdown implements sdktrace.SpanExporter
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+// The types below mirror the OTLP JSON encoding of
+// ExportTraceServiceRequest. IDs are hex strings and 64-bit integers are
+// decimal strings, as the protobuf JSON mapping requires.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	TraceState        string         `json:"traceState,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpEvent struct {
+	Name         string         `json:"name"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue holds exactly one of its fields
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// OTLP status codes, which are numbered differently from codes.Code
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+// encodeSpans groups spans by resource and instrumentation scope
+func encodeSpans(spans []sdktrace.ReadOnlySpan) otlpRequest {
+	var req otlpRequest
+	resources := make(map[attribute.Distinct]int)
+	scopes := make(map[attribute.Distinct]map[string]int)
+
+	for _, span := range spans {
+		var key attribute.Distinct
+		var resAttrs []attribute.KeyValue
+		if res := span.Resource(); res != nil {
+			key = res.Equivalent()
+			resAttrs = res.Attributes()
+		}
+		ri, ok := resources[key]
+		if !ok {
+			ri = len(req.ResourceSpans)
+			resources[key] = ri
+			scopes[key] = make(map[string]int)
+			req.ResourceSpans = append(req.ResourceSpans, otlpResourceSpans{
+				Resource: otlpResource{Attributes: encodeAttributes(resAttrs)},
+			})
+		}
+
+		scope := span.InstrumentationScope()
+		scopeKey := scope.Name + "@" + scope.Version
+		si, ok := scopes[key][scopeKey]
+		if !ok {
+			si = len(req.ResourceSpans[ri].ScopeSpans)
+			scopes[key][scopeKey] = si
+			req.ResourceSpans[ri].ScopeSpans = append(req.ResourceSpans[ri].ScopeSpans, otlpScopeSpans{
+				Scope: otlpScope{Name: scope.Name, Version: scope.Version},
+			})
+		}
+
+		ss := &req.ResourceSpans[ri].ScopeSpans[si]
+		ss.Spans = append(ss.Spans, encodeSpan(span))
+	}
+	return req
+}
+
+func encodeSpan(span sdktrace.ReadOnlySpan) otlpSpan {
+	sc := span.SpanContext()
+	out := otlpSpan{
+		TraceID:           sc.TraceID().String(),
+		SpanID:            sc.SpanID().String(),
+		TraceState:        sc.TraceState().String(),
+		Name:              span.Name(),
+		Kind:              int(span.SpanKind()),
+		StartTimeUnixNano: unixNano(span.StartTime()),
+		EndTimeUnixNano:   unixNano(span.EndTime()),
+		Attributes:        encodeAttributes(span.Attributes()),
+	}
+	if parent := span.Parent(); parent.SpanID().IsValid() {
+		out.ParentSpanID = parent.SpanID().String()
+	}
+	for _, event := range span.Events() {
+		out.Events = append(out.Events, otlpEvent{
+			Name:         event.Name,
+			TimeUnixNano: unixNano(event.Time),
+			Attributes:   encodeAttributes(event.Attributes),
+		})
+	}
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		out.Status = &otlpStatus{Code: otlpStatusOK}
+	case codes.Error:
+		out.Status = &otlpStatus{Code: otlpStatusError, Message: status.Description}
+	}
+	return out
+}
+
+func encodeAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		out = append(out, otlpKeyValue{Key: string(attr.Key), Value: encodeValue(attr.Value)})
+	}
+	return out
+}
+
+func encodeValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		var values []otlpAnyValue
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, encodeValue(attribute.BoolValue(b)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		var values []otlpAnyValue
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, encodeValue(attribute.Int64Value(i)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		var values []otlpAnyValue
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, encodeValue(attribute.Float64Value(f)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		var values []otlpAnyValue
+		for _, s := range v.AsStringSlice() {
+			values = append(values, encodeValue(attribute.StringValue(s)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	default:
+		s := v.Emit()
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+func unixNano(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,140 @@
+// Package tracing emits OpenTelemetry spans for FTL CLI commands.
+//
+// Tracing is off unless an OTLP endpoint is configured through the standard
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT
+// environment variables. Spans are exported as OTLP/HTTP JSON, which every
+// OpenTelemetry collector accepts.
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies CLI spans unless OTEL_SERVICE_NAME overrides it
+const ServiceName = "ftl-cli"
+
+// instrumentationName is the tracer name used for all CLI spans
+const instrumentationName = "github.com/fastertools/ftl"
+
+// propagator carries W3C trace context to and from child processes
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Endpoint returns the OTLP/HTTP traces URL from the environment, or an
+// empty string when tracing is not configured
+func Endpoint() string {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return ""
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// Setup installs a global tracer provider exporting to the configured OTLP
+// endpoint. The returned function flushes pending spans and must be called
+// before the process exits. When tracing is not configured Setup does
+// nothing and spans are discarded.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	endpoint := Endpoint()
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	name := os.Getenv("OTEL_SERVICE_NAME")
+	if name == "" {
+		name = ServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(name),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := NewExporter(endpoint, ParseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")))
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return provider.Shutdown, nil
+}
+
+// Start begins a span as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// FromEnviron returns ctx with the remote parent span passed to this process
+// in the TRACEPARENT environment variable, so CI pipelines can nest CLI spans
+// under their own
+func FromEnviron(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	for _, key := range []string{"traceparent", "tracestate", "baggage"} {
+		if value := os.Getenv(strings.ToUpper(key)); value != "" {
+			carrier[key] = value
+		}
+	}
+	return propagator.Extract(ctx, carrier)
+}
+
+// Environ returns the environment entries that continue the trace in ctx in
+// a child process, such as 'spin build'. It is empty when ctx has no
+// recording span.
+func Environ(ctx context.Context) []string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	var env []string
+	for _, key := range carrier.Keys() {
+		env = append(env, strings.ToUpper(key)+"="+carrier.Get(key))
+	}
+	return env
+}
+
+// ParseHeaders parses the OTEL_EXPORTER_OTLP_HEADERS format, a comma
+// separated list of URL-encoded key=value pairs
+func ParseHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(strings.TrimSpace(val)); err == nil {
+			val = decoded
+		}
+		headers[key] = val
+	}
+	return headers
+}
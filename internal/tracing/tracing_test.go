@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEndpoint(t *testing.T) {
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	assert.Empty(t, Endpoint())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318/")
+	assert.Equal(t, "http://collector:4318/v1/traces", Endpoint())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://traces:4318/custom")
+	assert.Equal(t, "http://traces:4318/custom", Endpoint())
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	assert.Empty(t, Endpoint())
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers := ParseHeaders("x-api-key=secret, Authorization=Bearer%20abc,invalid,=empty")
+	assert.Equal(t, map[string]string{
+		"x-api-key":     "secret",
+		"Authorization": "Bearer abc",
+	}, headers)
+	assert.Empty(t, ParseHeaders(""))
+}
+
+func TestEnvironRoundTrip(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	assert.Nil(t, Environ(context.Background()))
+
+	env := Environ(ctx)
+	require.NotEmpty(t, env)
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		t.Setenv(key, value)
+	}
+
+	remote := trace.SpanContextFromContext(FromEnviron(context.Background()))
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+}
+
+func TestExporter(t *testing.T) {
+	var got map[string]any
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		apiKey = r.Header.Get("x-api-key")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.URL, map[string]string{"x-api-key": "secret"})
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("ftl-test")
+
+	ctx, parent := tracer.Start(context.Background(), "deploy")
+	_, child := tracer.Start(ctx, "deploy.push", trace.WithAttributes(
+		attribute.String("component", "weather"),
+		attribute.Int("attempt", 2),
+		attribute.StringSlice("tags", []string{"a", "b"}),
+	))
+	End(child, errors.New("push failed"))
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	assert.Equal(t, "secret", apiKey)
+	encoded, err := json.Marshal(got)
+	require.NoError(t, err)
+	var req otlpRequest
+	require.NoError(t, json.Unmarshal(encoded, &req))
+
+	require.Len(t, req.ResourceSpans, 1)
+	require.Len(t, req.ResourceSpans[0].ScopeSpans, 1)
+	assert.Equal(t, "ftl-test", req.ResourceSpans[0].ScopeSpans[0].Scope.Name)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "deploy.push", span.Name)
+	assert.Equal(t, parent.SpanContext().TraceID().String(), span.TraceID)
+	assert.Equal(t, parent.SpanContext().SpanID().String(), span.ParentSpanID)
+	assert.Len(t, span.SpanID, 16)
+	assert.NotEqual(t, "0", span.StartTimeUnixNano)
+	require.NotNil(t, span.Status)
+	assert.Equal(t, otlpStatusError, span.Status.Code)
+	assert.Equal(t, "push failed", span.Status.Message)
+	require.Len(t, span.Events, 1)
+	assert.Equal(t, "exception", span.Events[0].Name)
+
+	attrs := make(map[string]otlpAnyValue)
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	require.NotNil(t, attrs["component"].StringValue)
+	assert.Equal(t, "weather", *attrs["component"].StringValue)
+	require.NotNil(t, attrs["attempt"].IntValue)
+	assert.Equal(t, "2", *attrs["attempt"].IntValue)
+	require.NotNil(t, attrs["tags"].ArrayValue)
+	assert.Len(t, attrs["tags"].ArrayValue.Values, 2)
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+	assert.ErrorIs(t, exporter.ExportSpans(context.Background(), nil), errExporterShutdown)
+}
+
+func TestExporterRejectedRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.URL, nil)
+	provider := sdktrace.NewTracerProvider()
+	_, span := provider.Tracer("test").Start(context.Background(), "build")
+	span.End()
+
+	err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{span.(sdktrace.ReadOnlySpan)})
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,258 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"golang.org/x/mod/semver"
+)
+
+// ComponentInfo describes a version of a WASM component in a registry
+type ComponentInfo struct {
+	// Registry is the registry host, e.g. "ghcr.io"
+	Registry string `json:"registry"`
+	// Package is the Spin-style package name, e.g. "fastertools:geo"
+	Package string `json:"package"`
+	// Version is the version described: the one requested, or the newest
+	// by semantic version where tags allow
+	Version string   `json:"version"`
+	Tags    []string `json:"tags"`
+	Digest  string   `json:"digest"`
+	// Size is the size in bytes of the version's WASM layers
+	Size int64 `json:"size"`
+	// Targets lists the builds of a multi-target component
+	Targets []string `json:"targets,omitempty"`
+	Metadata
+}
+
+// Reference returns the component version in the form accepted by
+// 'ftl component add --registry'
+func (c ComponentInfo) Reference() string {
+	return fmt.Sprintf("%s/%s@%s", c.Registry, c.Package, c.Version)
+}
+
+// SearchComponents lists the WASM components in a registry, optionally
+// under a namespace such as "ghcr.io/fastertools", whose repository names
+// contain query. Repositories that are not WASM components are skipped.
+//
+// Registries that do not allow listing their catalog, such as GHCR, are
+// searched for a repository named by the query within the namespace.
+func SearchComponents(ctx context.Context, location, query string, limit int) ([]ComponentInfo, error) {
+	host, namespace, _ := strings.Cut(strings.TrimSuffix(location, "/"), "/")
+	reg, err := name.NewRegistry(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry %s: %w", host, err)
+	}
+	opts := registryOptions(ctx)
+
+	repos, err := remote.Catalog(ctx, reg, opts...)
+	if err != nil {
+		if query == "" {
+			return nil, fmt.Errorf("%s does not allow listing its components; search for a package by name: %w", host, err)
+		}
+		repos = []string{strings.TrimPrefix(namespace+"/"+query, "/")}
+	}
+	// Registries should list repositories in lexical order, but not all do
+	sort.Strings(repos)
+
+	var components []ComponentInfo
+	for _, repo := range repos {
+		if namespace != "" && !strings.HasPrefix(repo, namespace+"/") {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(strings.TrimPrefix(repo, namespace+"/")), strings.ToLower(query)) {
+			continue
+		}
+		info, ok, err := inspectComponent(reg.Repo(repo), "", opts)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		components = append(components, *info)
+		if limit > 0 && len(components) == limit {
+			break
+		}
+	}
+	return components, nil
+}
+
+// InspectComponent describes a version of a component, or its newest
+// version if version is empty, including the metadata it was pushed with
+func InspectComponent(ctx context.Context, registry, packageName, version string) (*ComponentInfo, error) {
+	ref := fmt.Sprintf("%s/%s", registry, strings.Replace(packageName, ":", "/", 1))
+	repo, err := name.NewRepository(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	info, ok, err := inspectComponent(repo, version, registryOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s is not a WASM component", ref)
+	}
+	return info, nil
+}
+
+// registryOptions reaches registries with the user's registry credentials
+func registryOptions(ctx context.Context) []remote.Option {
+	return []remote.Option{
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithContext(ctx),
+	}
+}
+
+// inspectComponent describes a version of a repository, or its newest if
+// version is empty, or reports false if it is not a WASM component
+func inspectComponent(repo name.Repository, version string, opts []remote.Option) (*ComponentInfo, bool, error) {
+	tags, err := remote.List(repo, opts...)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to list versions of %s: %w", repo, err)
+	}
+	tags = versionTags(tags)
+	if len(tags) == 0 {
+		return nil, false, nil
+	}
+	if version == "" {
+		version = latestTag(tags)
+	}
+
+	info := &ComponentInfo{
+		Registry: repo.RegistryStr(),
+		Package:  strings.Replace(repo.RepositoryStr(), "/", ":", 1),
+		Version:  version,
+		Tags:     tags,
+	}
+
+	readErr := func(err error) error {
+		return fmt.Errorf("failed to read %s:%s: %w", repo, version, err)
+	}
+	desc, err := remote.Get(repo.Tag(version), opts...)
+	if err != nil {
+		return nil, false, readErr(err)
+	}
+	info.Digest = desc.Digest.String()
+
+	var annotations map[string]string
+	var manifest *v1.Manifest
+	if desc.MediaType.IsIndex() {
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return nil, false, readErr(err)
+		}
+		indexManifest, err := idx.IndexManifest()
+		if err != nil {
+			return nil, false, readErr(err)
+		}
+		for _, m := range indexManifest.Manifests {
+			if m.Platform != nil && m.Platform.Architecture == WASMArchitecture {
+				info.Targets = append(info.Targets, Target{OS: m.Platform.OS, Variant: m.Platform.Variant}.String())
+			}
+		}
+		if len(info.Targets) == 0 {
+			return nil, false, nil
+		}
+		annotations = indexManifest.Annotations
+		selected, err := selectManifest(indexManifest.Manifests, DefaultTarget)
+		if err != nil {
+			selected = indexManifest.Manifests[0]
+		}
+		img, err := idx.Image(selected.Digest)
+		if err != nil {
+			return nil, false, readErr(err)
+		}
+		if manifest, err = img.Manifest(); err != nil {
+			return nil, false, readErr(err)
+		}
+	} else {
+		img, err := desc.Image()
+		if err != nil {
+			return nil, false, readErr(err)
+		}
+		if manifest, err = img.Manifest(); err != nil {
+			return nil, false, readErr(err)
+		}
+	}
+
+	isWASM := manifest.Config.MediaType == WASMConfigMediaType
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == WASMLayerMediaType {
+			isWASM = true
+			info.Size += layer.Size
+		}
+	}
+	if !isWASM {
+		return nil, false, nil
+	}
+
+	// An index's own annotations take precedence over its builds'
+	merged := map[string]string{}
+	for k, v := range manifest.Annotations {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	info.Metadata = MetadataFromAnnotations(merged)
+	return info, true, nil
+}
+
+// versionTags drops the tags registries use for signatures and referrers
+// and sorts the rest, newest first
+func versionTags(tags []string) []string {
+	versions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "sha256-") {
+			continue
+		}
+		versions = append(versions, tag)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, vj := semverOf(versions[i]), semverOf(versions[j])
+		if vi != "" && vj != "" {
+			return semver.Compare(vi, vj) > 0
+		}
+		if (vi != "") != (vj != "") {
+			return vi != ""
+		}
+		return versions[i] > versions[j]
+	})
+	return versions
+}
+
+// latestTag picks the newest semantic version among tags sorted by
+// versionTags, falling back to "latest"
+func latestTag(tags []string) string {
+	if semverOf(tags[0]) == "" {
+		for _, tag := range tags {
+			if tag == "latest" {
+				return tag
+			}
+		}
+	}
+	return tags[0]
+}
+
+// semverOf returns tag as a semantic version with a v prefix, or "" if it
+// is not one
+func semverOf(tag string) string {
+	v := tag
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return ""
+	}
+	return v
+}
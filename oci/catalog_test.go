@@ -0,0 +1,73 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchComponents(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	dir := t.TempDir()
+	wasm := filepath.Join(dir, "component.wasm")
+	require.NoError(t, os.WriteFile(wasm, []byte("component"), 0600))
+
+	ctx := context.Background()
+	pusher := NewWASMPusher(&ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	for _, version := range []string{"0.9.0", "1.10.0", "1.2.0"} {
+		require.NoError(t, pusher.Push(ctx, wasm, "acme/geo", version))
+	}
+	require.NoError(t, pusher.PushIndex(ctx, map[Target]string{{OS: "wasip1"}: wasm, {OS: "wasip2"}: wasm}, "acme/geocode", "2.0.0", &Metadata{Description: "Geocoding tools"}))
+	require.NoError(t, pusher.Push(ctx, wasm, "other/geo", "1.0.0"))
+
+	// Container images are not components
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(regURL + "/acme/geo-server:1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	components, err := SearchComponents(ctx, regURL+"/acme", "GEO", 0)
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+
+	geo := components[0]
+	assert.Equal(t, regURL, geo.Registry)
+	assert.Equal(t, "acme:geo", geo.Package)
+	assert.Equal(t, "1.10.0", geo.Version)
+	assert.Equal(t, []string{"1.10.0", "1.2.0", "0.9.0"}, geo.Tags)
+	assert.Equal(t, int64(len("component")), geo.Size)
+	assert.Equal(t, regURL+"/acme:geo@1.10.0", geo.Reference())
+
+	assert.Equal(t, "acme:geocode", components[1].Package)
+	assert.Equal(t, []string{"wasip1", "wasip2"}, components[1].Targets)
+	assert.Equal(t, "Geocoding tools", components[1].Description)
+
+	components, err = SearchComponents(ctx, regURL, "", 1)
+	require.NoError(t, err)
+	assert.Len(t, components, 1)
+
+	components, err = SearchComponents(ctx, regURL+"/acme", "weather", 0)
+	require.NoError(t, err)
+	assert.Empty(t, components)
+}
+
+func TestVersionTags(t *testing.T) {
+	tags := versionTags([]string{"latest", "0.1.0", "sha256-abc.sig", "v1.0.0", "1.0.0-rc.1", "dev"})
+	assert.Equal(t, []string{"v1.0.0", "1.0.0-rc.1", "0.1.0", "latest", "dev"}, tags)
+	assert.Equal(t, "v1.0.0", latestTag(tags))
+	assert.Equal(t, "latest", latestTag(versionTags([]string{"dev", "latest"})))
+}
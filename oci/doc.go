@@ -5,6 +5,9 @@
 // This package implements:
 //   - WASM OCI image creation with proper layerDigests field for Spin compatibility
 //   - Registry push/pull operations for WASM components
+//   - Component metadata from component.yaml stored as manifest annotations
+//   - Multi-target components (e.g. wasip1 and wasip2 builds) published as an
+//     OCI image index and pulled by target
 //   - ECR (Elastic Container Registry) authentication support
 //   - Caching for pulled WASM artifacts
 //
@@ -23,6 +26,10 @@
 //	pusher := oci.NewWASMPusher(auth)
 //	err := pusher.Push(ctx, "component.wasm", "namespace/component", "1.0.0")
 //
+//	// Push it with the metadata in component.yaml next to its source
+//	meta, err := oci.LoadMetadata("path/to/component")
+//	err = pusher.PushWithMetadata(ctx, "component.wasm", "namespace/component", "1.0.0", meta)
+//
 //	// Pull a WASM component from a registry
 //	puller := oci.NewWASMPuller()
 //	source := &types.RegistrySource{
@@ -31,4 +38,11 @@
 //	    Version:  "1.0.0",
 //	}
 //	wasmPath, err := puller.Pull(ctx, source)
+//
+//	// Push wasip1 and wasip2 builds as an image index and pull one of them
+//	err = pusher.PushIndex(ctx, map[oci.Target]string{
+//	    {OS: "wasip1"}: "component-p1.wasm",
+//	    {OS: "wasip2"}: "component-p2.wasm",
+//	}, "namespace/component", "1.0.0", nil)
+//	wasmPath, err = puller.PullTarget(ctx, "ghcr.io", "org/component", "1.0.0", oci.Target{OS: "wasip1"})
 package oci
@@ -2,8 +2,9 @@ package oci
 
 import (
 	"encoding/base64"
-	"fmt"
 	"strings"
+
+	"github.com/fastertools/ftl/ftlerr"
 )
 
 // ECRAuth holds parsed ECR authentication details
@@ -18,12 +19,12 @@ type ECRAuth struct {
 func ParseECRToken(registryURI, authToken string) (*ECRAuth, error) {
 	decoded, err := base64.StdEncoding.DecodeString(authToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode ECR token: %w", err)
+		return nil, ftlerr.Wrap(err, ftlerr.RegistryAuthFailed, "failed to decode ECR token")
 	}
 
 	parts := strings.SplitN(string(decoded), ":", 2)
 	if len(parts) != 2 || parts[0] != "AWS" {
-		return nil, fmt.Errorf("invalid ECR token format")
+		return nil, ftlerr.New(ftlerr.RegistryAuthFailed, "invalid ECR token format")
 	}
 
 	return &ECRAuth{
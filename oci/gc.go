@@ -0,0 +1,150 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// RetentionPolicy selects the versions of a component kept when its
+// repository is pruned. Only semantic versions are pruned: other tags,
+// such as "latest", are always kept.
+type RetentionPolicy struct {
+	// Keep is how many of the newest versions are kept
+	Keep int
+	// Protect lists versions kept whatever their age, such as those
+	// deployed
+	Protect []string
+}
+
+// Expired returns the versions among tags that the policy does not keep,
+// newest first
+func (p RetentionPolicy) Expired(tags []string) []string {
+	protected := make(map[string]bool, len(p.Protect))
+	for _, version := range p.Protect {
+		protected[version] = true
+	}
+
+	var expired []string
+	kept := 0
+	for _, tag := range versionTags(tags) {
+		if semverOf(tag) == "" || protected[tag] {
+			continue
+		}
+		if kept < p.Keep {
+			kept++
+			continue
+		}
+		expired = append(expired, tag)
+	}
+	return expired
+}
+
+// PrunedVersion is a version deleted from a repository, or that would be
+// deleted in a dry run
+type PrunedVersion struct {
+	Repository string `json:"repository"`
+	Version    string `json:"version"`
+	Digest     string `json:"digest"`
+}
+
+// PruneRepository deletes the versions of a component repository, such as
+// "ghcr.io/acme/geo", that the policy does not keep. A version whose image
+// is also tagged with a kept version is not deleted. Signatures and other
+// referrers stored under the referrers tag scheme are deleted with their
+// version. With dryRun, the versions are only returned.
+//
+// auth may be nil to use the user's registry credentials.
+func PruneRepository(ctx context.Context, repository string, auth *ECRAuth, policy RetentionPolicy, dryRun bool) ([]PrunedVersion, error) {
+	if policy.Keep < 1 {
+		return nil, fmt.Errorf("retention policy must keep at least one version")
+	}
+	repo, err := name.NewRepository(repository)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository %s: %w", repository, err)
+	}
+	opts := registryOptions(ctx)
+	if auth != nil {
+		opts = []remote.Option{
+			remote.WithAuth(authn.FromConfig(authn.AuthConfig{Username: auth.Username, Password: auth.Password})),
+			remote.WithContext(ctx),
+		}
+	}
+
+	tags, err := remote.List(repo, opts...)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list versions of %s: %w", repo, err)
+	}
+	expired := policy.Expired(tags)
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	// Resolve every version, as deleting an image removes all its tags
+	isExpired := make(map[string]bool, len(expired))
+	for _, tag := range expired {
+		isExpired[tag] = true
+	}
+	digests := make(map[string]string, len(tags))
+	keptDigests := make(map[string]bool)
+	for _, tag := range versionTags(tags) {
+		desc, err := remote.Head(repo.Tag(tag), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s:%s: %w", repo, tag, err)
+		}
+		digests[tag] = desc.Digest.String()
+		if !isExpired[tag] {
+			keptDigests[desc.Digest.String()] = true
+		}
+	}
+
+	var pruned []PrunedVersion
+	var deletions []string
+	deleted := make(map[string]bool)
+	for _, tag := range expired {
+		digest := digests[tag]
+		if keptDigests[digest] {
+			continue
+		}
+		pruned = append(pruned, PrunedVersion{Repository: repo.String(), Version: tag, Digest: digest})
+		if dryRun {
+			continue
+		}
+		// Not every registry deletes tags; deleting the image below removes
+		// them on those that do not
+		_ = remote.Delete(repo.Tag(tag), opts...)
+		if !deleted[digest] {
+			deleted[digest] = true
+			deletions = append(deletions, digest)
+		}
+	}
+
+	for _, digest := range deletions {
+		if err := deleteManifest(repo, digest, opts); err != nil {
+			return pruned, fmt.Errorf("failed to delete %s@%s: %w", repo, digest, err)
+		}
+		referrersTag := strings.Replace(digest, ":", "-", 1)
+		if desc, err := remote.Head(repo.Tag(referrersTag), opts...); err == nil {
+			_ = remote.Delete(repo.Tag(referrersTag), opts...)
+			if err := deleteManifest(repo, desc.Digest.String(), opts); err != nil {
+				return pruned, fmt.Errorf("failed to delete referrers of %s@%s: %w", repo, digest, err)
+			}
+		}
+	}
+	return pruned, nil
+}
+
+// deleteManifest deletes a manifest by digest
+func deleteManifest(repo name.Repository, digest string, opts []remote.Option) error {
+	if err := remote.Delete(repo.Digest(digest), opts...); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
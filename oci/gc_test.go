@@ -0,0 +1,82 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicy_Expired(t *testing.T) {
+	tags := []string{"1.0.0", "latest", "1.10.0", "1.2.0", "0.9.0", "sha256-abc", "2.0.0"}
+
+	policy := RetentionPolicy{Keep: 2}
+	assert.Equal(t, []string{"1.2.0", "1.0.0", "0.9.0"}, policy.Expired(tags))
+
+	policy = RetentionPolicy{Keep: 2, Protect: []string{"0.9.0", "1.10.0"}}
+	assert.Equal(t, []string{"1.0.0"}, policy.Expired(tags))
+
+	assert.Empty(t, RetentionPolicy{Keep: 10}.Expired(tags))
+}
+
+func TestPruneRepository(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	auth := &ECRAuth{Registry: regURL, Username: "test", Password: "test"}
+	pusher := NewWASMPusher(auth)
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		wasm := filepath.Join(dir, version+".wasm")
+		require.NoError(t, os.WriteFile(wasm, []byte("component "+version), 0600))
+		require.NoError(t, pusher.Push(ctx, wasm, "acme/geo", version))
+	}
+	repository := regURL + "/acme/geo"
+	repo, err := name.NewRepository(repository)
+	require.NoError(t, err)
+
+	// 1.0.1 is the same image as the kept 1.3.0
+	desc, err := remote.Get(repo.Tag("1.3.0"))
+	require.NoError(t, err)
+	require.NoError(t, remote.Tag(repo.Tag("1.0.1"), desc))
+
+	policy := RetentionPolicy{Keep: 1, Protect: []string{"1.1.0"}}
+
+	pruned, err := PruneRepository(ctx, repository, auth, policy, true)
+	require.NoError(t, err)
+	versions := func(pruned []PrunedVersion) []string {
+		var v []string
+		for _, p := range pruned {
+			v = append(v, p.Version)
+		}
+		return v
+	}
+	assert.Equal(t, []string{"1.2.0", "1.0.0"}, versions(pruned))
+
+	tags, err := remote.List(repo)
+	require.NoError(t, err)
+	assert.Len(t, tags, 5, "a dry run deletes nothing")
+
+	pruned, err = PruneRepository(ctx, repository, auth, policy, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.0", "1.0.0"}, versions(pruned))
+	assert.Equal(t, repo.String(), pruned[0].Repository)
+	assert.True(t, strings.HasPrefix(pruned[0].Digest, "sha256:"))
+
+	tags, err = remote.List(repo)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1.0.1", "1.1.0", "1.3.0"}, tags)
+
+	_, err = PruneRepository(ctx, repository, auth, RetentionPolicy{}, false)
+	assert.ErrorContains(t, err, "at least one version")
+}
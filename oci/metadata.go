@@ -0,0 +1,149 @@
+package oci
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetadataFile is the file next to a component's source that describes it
+const MetadataFile = "component.yaml"
+
+// Annotations under which component metadata is stored in its manifest.
+// Standard OCI annotations are used where one exists.
+const (
+	AnnotationDescription   = "org.opencontainers.image.description"
+	AnnotationDocumentation = "org.opencontainers.image.documentation"
+	AnnotationSource        = "org.opencontainers.image.source"
+	AnnotationLicenses      = "org.opencontainers.image.licenses"
+	AnnotationAuthors       = "org.opencontainers.image.authors"
+	AnnotationVersion       = "org.opencontainers.image.version"
+	// AnnotationTools lists the MCP tools the component provides, separated by commas
+	AnnotationTools = "dev.fastertools.ftl.tools"
+)
+
+// Metadata describes a component so that it is self-describing once
+// published. It is read from component.yaml and pushed as manifest
+// annotations.
+type Metadata struct {
+	// Version is the version the component is pushed as, maintained by
+	// 'ftl component release'
+	Version       string   `yaml:"version,omitempty" json:"version,omitempty"`
+	Description   string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Documentation string   `yaml:"documentation,omitempty" json:"documentation,omitempty"`
+	Source        string   `yaml:"source,omitempty" json:"source,omitempty"`
+	License       string   `yaml:"license,omitempty" json:"license,omitempty"`
+	Authors       string   `yaml:"authors,omitempty" json:"authors,omitempty"`
+	Tools         []string `yaml:"tools,omitempty" json:"tools,omitempty"`
+}
+
+// LoadMetadata reads the component.yaml in dir. A missing file is not an
+// error and returns nil.
+func LoadMetadata(dir string) (*Metadata, error) {
+	path := filepath.Join(dir, MetadataFile)
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var meta Metadata
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, tool := range meta.Tools {
+		if tool == "" || strings.Contains(tool, ",") {
+			return nil, fmt.Errorf("invalid tool name %q in %s", tool, path)
+		}
+	}
+	return &meta, nil
+}
+
+// Annotations returns the metadata as manifest annotations
+func (m *Metadata) Annotations() map[string]string {
+	annotations := map[string]string{}
+	if m == nil {
+		return annotations
+	}
+	set := func(key, value string) {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	set(AnnotationDescription, m.Description)
+	set(AnnotationDocumentation, m.Documentation)
+	set(AnnotationSource, m.Source)
+	set(AnnotationLicenses, m.License)
+	set(AnnotationAuthors, m.Authors)
+	set(AnnotationVersion, m.Version)
+	set(AnnotationTools, strings.Join(m.Tools, ","))
+	return annotations
+}
+
+// MetadataFromAnnotations reads metadata from manifest annotations
+func MetadataFromAnnotations(annotations map[string]string) Metadata {
+	meta := Metadata{
+		Description:   annotations[AnnotationDescription],
+		Documentation: annotations[AnnotationDocumentation],
+		Source:        annotations[AnnotationSource],
+		License:       annotations[AnnotationLicenses],
+		Authors:       annotations[AnnotationAuthors],
+		Version:       annotations[AnnotationVersion],
+	}
+	if tools := annotations[AnnotationTools]; tools != "" {
+		meta.Tools = strings.Split(tools, ",")
+	}
+	return meta
+}
+
+// SaveMetadataVersion sets the version in the component.yaml in dir,
+// creating the file if needed. Other fields and comments are kept.
+func SaveMetadataVersion(dir, version string) error {
+	path := filepath.Join(dir, MetadataFile)
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse %s: not a mapping", path)
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: version}
+	set := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "version" {
+			root.Content[i+1] = value
+			set = true
+		}
+	}
+	if !set {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+		root.Content = append([]*yaml.Node{key, value}, root.Content...)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
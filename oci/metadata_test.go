@@ -0,0 +1,74 @@
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	meta, err := LoadMetadata(dir)
+	require.NoError(t, err)
+	assert.Nil(t, meta, "a missing component.yaml is not an error")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataFile), []byte(`description: Geocoding tools
+documentation: https://example.com/geo
+license: MIT
+tools: [geocode, reverse_geocode]
+`), 0600))
+	meta, err = LoadMetadata(dir)
+	require.NoError(t, err)
+
+	annotations := meta.Annotations()
+	assert.Equal(t, map[string]string{
+		AnnotationDescription:   "Geocoding tools",
+		AnnotationDocumentation: "https://example.com/geo",
+		AnnotationLicenses:      "MIT",
+		AnnotationTools:         "geocode,reverse_geocode",
+	}, annotations)
+	assert.Equal(t, *meta, MetadataFromAnnotations(annotations))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataFile), []byte("tools: [\"a,b\"]\n"), 0600))
+	_, err = LoadMetadata(dir)
+	assert.ErrorContains(t, err, "invalid tool name")
+}
+
+func TestMetadata_NilAnnotations(t *testing.T) {
+	var meta *Metadata
+	assert.Empty(t, meta.Annotations())
+}
+
+func TestSaveMetadataVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, SaveMetadataVersion(dir, "0.1.0"))
+	meta, err := LoadMetadata(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "0.1.0", meta.Version)
+
+	path := filepath.Join(dir, MetadataFile)
+	require.NoError(t, os.WriteFile(path, []byte(`# Geocoding component
+description: Geocoding tools
+version: 0.1.0
+tools:
+  - geocode
+`), 0600))
+	require.NoError(t, SaveMetadataVersion(dir, "0.2.0"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Geocoding component
+description: Geocoding tools
+version: 0.2.0
+tools:
+  - geocode
+`, string(data))
+	meta, err = LoadMetadata(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "0.2.0", meta.Annotations()[AnnotationVersion])
+}
@@ -0,0 +1,264 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// ProvenanceBuildType is the SLSA build type of components built by the
+// FTL CLI
+const ProvenanceBuildType = "https://github.com/fastertools/ftl/component-build/v1"
+
+// In-toto and SLSA identifiers of provenance statements
+const (
+	inTotoStatementType    = "https://in-toto.io/Statement/v1"
+	slsaProvenanceType     = "https://slsa.dev/provenance/v1"
+	provenanceTitle        = "provenance.intoto.json"
+	provenanceDigestSHA256 = "sha256"
+)
+
+// Provenance describes how a component was built, published as a SLSA
+// provenance statement attached to the component
+type Provenance struct {
+	// Builder identifies what built the component, e.g. the URI of a CI
+	// workflow
+	Builder string `json:"builder"`
+	// InvocationID identifies the build run, e.g. the URL of a CI job
+	InvocationID string `json:"invocation_id,omitempty"`
+	// SourceRepo is the git repository the component was built from
+	SourceRepo string `json:"source_repo,omitempty"`
+	// SourceCommit is the commit the component was built from
+	SourceCommit string `json:"source_commit,omitempty"`
+	// SourceDirty is set when the working tree had uncommitted changes
+	SourceDirty bool `json:"source_dirty,omitempty"`
+	// BuildCommand is the command that built the component
+	BuildCommand string `json:"build_command,omitempty"`
+	// InputsDigest is the SHA-256 of the build inputs, the files matching
+	// the component's watch patterns
+	InputsDigest string `json:"inputs_digest,omitempty"`
+	// FinishedOn is when the build finished
+	FinishedOn time.Time `json:"finished_on"`
+	// Digest is the digest of the component the provenance is about
+	Digest string `json:"digest,omitempty"`
+}
+
+// provenanceStatement is an in-toto statement with a SLSA v1 predicate
+type provenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []resourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     struct {
+		BuildDefinition struct {
+			BuildType            string                 `json:"buildType"`
+			ExternalParameters   map[string]interface{} `json:"externalParameters"`
+			InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+			ResolvedDependencies []resourceDescriptor   `json:"resolvedDependencies,omitempty"`
+		} `json:"buildDefinition"`
+		RunDetails struct {
+			Builder struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+			Metadata struct {
+				InvocationID string    `json:"invocationId,omitempty"`
+				FinishedOn   time.Time `json:"finishedOn"`
+			} `json:"metadata"`
+		} `json:"runDetails"`
+	} `json:"predicate"`
+}
+
+type resourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Statement returns the provenance as an in-toto statement about the
+// artifact with the given name and digest
+func (p *Provenance) Statement(artifact, digest string) ([]byte, error) {
+	algorithm, hex, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+
+	var s provenanceStatement
+	s.Type = inTotoStatementType
+	s.Subject = []resourceDescriptor{{Name: artifact, Digest: map[string]string{algorithm: hex}}}
+	s.PredicateType = slsaProvenanceType
+
+	def := &s.Predicate.BuildDefinition
+	def.BuildType = ProvenanceBuildType
+	def.ExternalParameters = map[string]interface{}{}
+	if p.BuildCommand != "" {
+		def.ExternalParameters["command"] = p.BuildCommand
+	}
+	if p.SourceDirty {
+		def.InternalParameters = map[string]interface{}{"dirty": true}
+	}
+	if p.SourceRepo != "" || p.SourceCommit != "" {
+		source := resourceDescriptor{Name: "source"}
+		if p.SourceRepo != "" {
+			source.URI = "git+" + p.SourceRepo
+		}
+		if p.SourceCommit != "" {
+			source.Digest = map[string]string{"gitCommit": p.SourceCommit}
+		}
+		def.ResolvedDependencies = append(def.ResolvedDependencies, source)
+	}
+	if p.InputsDigest != "" {
+		def.ResolvedDependencies = append(def.ResolvedDependencies, resourceDescriptor{
+			Name:   "inputs",
+			Digest: map[string]string{provenanceDigestSHA256: p.InputsDigest},
+		})
+	}
+
+	run := &s.Predicate.RunDetails
+	run.Builder.ID = p.Builder
+	run.Metadata.InvocationID = p.InvocationID
+	run.Metadata.FinishedOn = p.FinishedOn.UTC()
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// ParseProvenance reads a provenance statement, returning the provenance
+// and the digests of the artifacts it is about
+func ParseProvenance(data []byte) (*Provenance, []string, error) {
+	var s provenanceStatement
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, nil, fmt.Errorf("invalid provenance statement: %w", err)
+	}
+	if s.Type != inTotoStatementType || s.PredicateType != slsaProvenanceType {
+		return nil, nil, fmt.Errorf("unsupported provenance statement %s with predicate %s", s.Type, s.PredicateType)
+	}
+
+	def := s.Predicate.BuildDefinition
+	p := &Provenance{
+		Builder:      s.Predicate.RunDetails.Builder.ID,
+		InvocationID: s.Predicate.RunDetails.Metadata.InvocationID,
+		FinishedOn:   s.Predicate.RunDetails.Metadata.FinishedOn,
+	}
+	p.BuildCommand, _ = def.ExternalParameters["command"].(string)
+	p.SourceDirty, _ = def.InternalParameters["dirty"].(bool)
+	for _, dep := range def.ResolvedDependencies {
+		switch dep.Name {
+		case "source":
+			p.SourceRepo = strings.TrimPrefix(dep.URI, "git+")
+			p.SourceCommit = dep.Digest["gitCommit"]
+		case "inputs":
+			p.InputsDigest = dep.Digest[provenanceDigestSHA256]
+		}
+	}
+
+	var digests []string
+	for _, subject := range s.Subject {
+		for algorithm, hex := range subject.Digest {
+			digests = append(digests, algorithm+":"+hex)
+		}
+	}
+	return p, digests, nil
+}
+
+// AttachProvenance attaches the provenance of a component pushed with the
+// pusher, as a statement about the pushed image
+func (p *WASMPusher) AttachProvenance(ctx context.Context, packageName, version string, provenance *Provenance) (*Attachment, error) {
+	tag, err := p.reference(packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	return attachProvenance(tag, []remote.Option{p.authOption(), remote.WithContext(ctx)}, provenance)
+}
+
+func attachProvenance(tag name.Reference, opts []remote.Option, provenance *Provenance) (*Attachment, error) {
+	desc, err := remote.Head(tag, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", tag, err)
+	}
+	statement, err := provenance.Statement(tag.Context().Name(), desc.Digest.String())
+	if err != nil {
+		return nil, err
+	}
+	return attach(tag, opts, ArtifactTypeProvenance, provenanceTitle, statement)
+}
+
+// FetchProvenance returns the provenance attached to a component that is
+// about the component's current image, the most recent when there are
+// several, or nil when it has none
+func FetchProvenance(ctx context.Context, registry, packageName, version string) (*Provenance, error) {
+	tag, opts, err := componentReference(ctx, registry, packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	return fetchProvenance(tag, opts)
+}
+
+func fetchProvenance(tag name.Reference, opts []remote.Option) (*Provenance, error) {
+	subject, err := remote.Head(tag, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", tag, err)
+	}
+	filter := remote.WithFilter("artifactType", ArtifactTypeProvenance)
+	idx, err := remote.Referrers(tag.Context().Digest(subject.Digest.String()), append(opts, filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments of %s: %w", tag, err)
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments of %s: %w", tag, err)
+	}
+
+	var latest *Provenance
+	for _, desc := range manifest.Manifests {
+		data, err := attachmentContent(tag.Context().Digest(desc.Digest.String()), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read provenance %s: %w", desc.Digest, err)
+		}
+		provenance, digests, err := ParseProvenance(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read provenance %s: %w", desc.Digest, err)
+		}
+		if !contains(digests, subject.Digest.String()) {
+			continue
+		}
+		if latest == nil || provenance.FinishedOn.After(latest.FinishedOn) {
+			provenance.Digest = subject.Digest.String()
+			latest = provenance
+		}
+	}
+	return latest, nil
+}
+
+// attachmentContent returns the content of a single-file attachment
+func attachmentContent(ref name.Digest, opts []remote.Option) ([]byte, error) {
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) != 1 {
+		return nil, fmt.Errorf("expected one layer, found %d", len(layers))
+	}
+	rc, err := layers[0].Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadAll(rc)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProvenance() *Provenance {
+	return &Provenance{
+		Builder:      "https://github.com/acme/tools/.github/workflows/release.yml@refs/heads/main",
+		InvocationID: "https://github.com/acme/tools/actions/runs/42/attempts/1",
+		SourceRepo:   "https://github.com/acme/tools",
+		SourceCommit: "8d1f3c2a9b7e6d5c4b3a29180f7e6d5c4b3a2918",
+		BuildCommand: "make build",
+		InputsDigest: "5f2b8c",
+		FinishedOn:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestProvenance_Statement(t *testing.T) {
+	want := testProvenance()
+	statement, err := want.Statement("ghcr.io/acme/geo", "sha256:abc123")
+	require.NoError(t, err)
+	for _, s := range []string{
+		`"_type": "https://in-toto.io/Statement/v1"`,
+		`"predicateType": "https://slsa.dev/provenance/v1"`,
+		`"buildType": "` + ProvenanceBuildType + `"`,
+		`"uri": "git+https://github.com/acme/tools"`,
+		`"gitCommit": "8d1f3c2a9b7e6d5c4b3a29180f7e6d5c4b3a2918"`,
+	} {
+		assert.Contains(t, string(statement), s)
+	}
+
+	got, digests, err := ParseProvenance(statement)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, []string{"sha256:abc123"}, digests)
+
+	_, err = want.Statement("ghcr.io/acme/geo", "abc123")
+	assert.ErrorContains(t, err, "invalid digest")
+	_, _, err = ParseProvenance([]byte(`{"_type": "https://in-toto.io/Statement/v0.1"}`))
+	assert.ErrorContains(t, err, "unsupported provenance statement")
+}
+
+func TestProvenance_AttachAndFetch(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	wasm := filepath.Join(t.TempDir(), "component.wasm")
+	require.NoError(t, os.WriteFile(wasm, []byte("component"), 0600))
+
+	ctx := context.Background()
+	pusher := NewWASMPusher(&ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.Push(ctx, wasm, "test/component", "1.0.0"))
+
+	none, err := FetchProvenance(ctx, regURL, "test:component", "1.0.0")
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	older := testProvenance()
+	older.FinishedOn = older.FinishedOn.Add(-time.Hour)
+	older.BuildCommand = "make old"
+	_, err = pusher.AttachProvenance(ctx, "test/component", "1.0.0", older)
+	require.NoError(t, err)
+	attached, err := pusher.AttachProvenance(ctx, "test/component", "1.0.0", testProvenance())
+	require.NoError(t, err)
+	assert.Equal(t, ArtifactTypeProvenance, attached.ArtifactType)
+
+	got, err := FetchProvenance(ctx, regURL, "test:component", "1.0.0")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "make build", got.BuildCommand, "the most recent provenance is used")
+	assert.True(t, strings.HasPrefix(got.Digest, "sha256:"))
+
+	// Provenance of an earlier image does not carry over to a new push
+	require.NoError(t, os.WriteFile(wasm, []byte("changed"), 0600))
+	require.NoError(t, pusher.Push(ctx, wasm, "test/component", "1.0.0"))
+	got, err = FetchProvenance(ctx, regURL, "test:component", "1.0.0")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,190 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// Artifact types of common metadata attached to components
+const (
+	// ArtifactTypeSPDX is an SPDX software bill of materials
+	ArtifactTypeSPDX = "application/spdx+json"
+	// ArtifactTypeCycloneDX is a CycloneDX software bill of materials
+	ArtifactTypeCycloneDX = "application/vnd.cyclonedx+json"
+	// ArtifactTypeSignature is a detached signature of the component
+	ArtifactTypeSignature = "application/vnd.dev.sigstore.bundle.v0.3+json"
+	// ArtifactTypeSchema is a JSON Schema document, e.g. for the component's tools
+	ArtifactTypeSchema = "application/schema+json"
+	// ArtifactTypeReadme is the component's documentation
+	ArtifactTypeReadme = "text/markdown"
+	// ArtifactTypeProvenance is an in-toto statement of how the component
+	// was built
+	ArtifactTypeProvenance = "application/vnd.in-toto+json"
+)
+
+// annotationTitle names an attached file, as ORAS does
+const annotationTitle = "org.opencontainers.image.title"
+
+// Attachment is a metadata artifact that refers to a component image
+// through the OCI referrers API
+type Attachment struct {
+	ArtifactType string            `json:"artifactType"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	Title        string            `json:"title,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// Attach uploads the file at path as an artifact of artifactType that
+// refers to a component image, so it can be found with ListAttachments.
+// Registries without the referrers API are supported through the OCI
+// referrers tag scheme.
+func Attach(ctx context.Context, registry, packageName, version, artifactType, path string) (*Attachment, error) {
+	if artifactType == "" {
+		return nil, fmt.Errorf("artifact type is required")
+	}
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	tag, opts, err := componentReference(ctx, registry, packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	return attach(tag, opts, artifactType, filepath.Base(path), content)
+}
+
+// attach pushes content as an artifact referring to the image at tag
+func attach(tag name.Reference, opts []remote.Option, artifactType, title string, content []byte) (*Attachment, error) {
+	subject, err := remote.Head(tag, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", tag, err)
+	}
+
+	annotations := map[string]string{
+		annotationTitle:                    title,
+		"org.opencontainers.image.created": time.Now().UTC().Format(time.RFC3339),
+	}
+	img, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1), mutate.Addendum{
+		Layer:       static.NewLayer(content, types.MediaType(artifactType)),
+		Annotations: map[string]string{annotationTitle: title},
+		MediaType:   types.MediaType(artifactType),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	// The config media type is the artifact type for registries that
+	// predate the manifest's artifactType field
+	img = mutate.ConfigMediaType(img, types.MediaType(artifactType))
+	img = mutate.Annotations(img, annotations).(v1.Image)
+	img = mutate.Subject(img, v1.Descriptor{
+		MediaType: subject.MediaType,
+		Digest:    subject.Digest,
+		Size:      subject.Size,
+	}).(v1.Image)
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	size, err := img.Size()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	if err := remote.Write(tag.Context().Digest(digest.String()), img, opts...); err != nil {
+		return nil, fmt.Errorf("failed to push attachment: %w", err)
+	}
+
+	return &Attachment{
+		ArtifactType: artifactType,
+		Digest:       digest.String(),
+		Size:         size,
+		Title:        title,
+		Annotations:  annotations,
+	}, nil
+}
+
+// ListAttachments returns the artifacts that refer to a component image,
+// limited to artifactType unless it is empty
+func ListAttachments(ctx context.Context, registry, packageName, version, artifactType string) ([]Attachment, error) {
+	tag, opts, err := componentReference(ctx, registry, packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := remote.Head(tag, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", tag, err)
+	}
+
+	if artifactType != "" {
+		opts = append(opts, remote.WithFilter("artifactType", artifactType))
+	}
+	idx, err := remote.Referrers(tag.Context().Digest(subject.Digest.String()), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments of %s: %w", tag, err)
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments of %s: %w", tag, err)
+	}
+
+	attachments := make([]Attachment, 0, len(manifest.Manifests))
+	for _, desc := range manifest.Manifests {
+		annotations := desc.Annotations
+		if annotations == nil {
+			// The referrers tag scheme does not record annotations, so
+			// read them from the attachment itself
+			if annotations, err = referrerAnnotations(tag.Context().Digest(desc.Digest.String()), opts); err != nil {
+				return nil, fmt.Errorf("failed to read attachment %s: %w", desc.Digest, err)
+			}
+		}
+		attachments = append(attachments, Attachment{
+			ArtifactType: desc.ArtifactType,
+			Digest:       desc.Digest.String(),
+			Size:         desc.Size,
+			Title:        annotations[annotationTitle],
+			Annotations:  annotations,
+		})
+	}
+	return attachments, nil
+}
+
+// referrerAnnotations returns the annotations of a referrer's manifest
+func referrerAnnotations(ref name.Digest, opts []remote.Option) (map[string]string, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := v1.ParseManifest(bytes.NewReader(desc.Manifest))
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Annotations, nil
+}
+
+// componentReference returns the reference of a component version and the
+// options for reaching its registry
+func componentReference(ctx context.Context, registry, packageName, version string) (name.Reference, []remote.Option, error) {
+	ociPackageName := strings.Replace(packageName, ":", "/", 1)
+	ref := fmt.Sprintf("%s/%s:%s", registry, ociPackageName, version)
+
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	return tag, registryOptions(ctx), nil
+}
@@ -0,0 +1,77 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttach(t *testing.T) {
+	for _, referrers := range []bool{true, false} {
+		t.Run(map[bool]string{true: "referrers API", false: "tag scheme"}[referrers], func(t *testing.T) {
+			s := httptest.NewServer(registry.New(registry.WithReferrersSupport(referrers)))
+			defer s.Close()
+			regURL := strings.TrimPrefix(s.URL, "http://")
+
+			dir := t.TempDir()
+			wasm := filepath.Join(dir, "component.wasm")
+			sbom := filepath.Join(dir, "sbom.spdx.json")
+			readme := filepath.Join(dir, "README.md")
+			require.NoError(t, os.WriteFile(wasm, []byte("component"), 0600))
+			require.NoError(t, os.WriteFile(sbom, []byte(`{"spdxVersion":"SPDX-2.3"}`), 0600))
+			require.NoError(t, os.WriteFile(readme, []byte("# Component"), 0600))
+
+			ctx := context.Background()
+			pusher := NewWASMPusher(&ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+			require.NoError(t, pusher.Push(ctx, wasm, "test/component", "1.0.0"))
+
+			attached, err := Attach(ctx, regURL, "test:component", "1.0.0", ArtifactTypeSPDX, sbom)
+			require.NoError(t, err)
+			assert.Equal(t, "sbom.spdx.json", attached.Title)
+			_, err = Attach(ctx, regURL, "test:component", "1.0.0", ArtifactTypeReadme, readme)
+			require.NoError(t, err)
+
+			all, err := ListAttachments(ctx, regURL, "test:component", "1.0.0", "")
+			require.NoError(t, err)
+			require.Len(t, all, 2)
+
+			sboms, err := ListAttachments(ctx, regURL, "test:component", "1.0.0", ArtifactTypeSPDX)
+			require.NoError(t, err)
+			require.Len(t, sboms, 1)
+			assert.Equal(t, ArtifactTypeSPDX, sboms[0].ArtifactType)
+			assert.Equal(t, attached.Digest, sboms[0].Digest)
+			assert.Equal(t, "sbom.spdx.json", sboms[0].Title)
+
+			// The component itself is unchanged
+			path, err := NewWASMPullerWithCache(t.TempDir()).Pull(ctx, regURL, "test:component", "1.0.0")
+			require.NoError(t, err)
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("component"), content)
+		})
+	}
+}
+
+func TestAttach_Errors(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	path := filepath.Join(t.TempDir(), "README.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Component"), 0600))
+
+	ctx := context.Background()
+	_, err := Attach(ctx, regURL, "test/missing", "1.0.0", "", path)
+	assert.ErrorContains(t, err, "artifact type is required")
+	_, err = Attach(ctx, regURL, "test/missing", "1.0.0", ArtifactTypeReadme, path)
+	assert.ErrorContains(t, err, "failed to resolve")
+	_, err = ListAttachments(ctx, regURL, "test/missing", "1.0.0", "")
+	assert.ErrorContains(t, err, "failed to resolve")
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -16,8 +17,13 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/fastertools/ftl/ftlerr"
 )
 
 // WASMPuller handles pulling WASM components from OCI registries
@@ -51,6 +57,13 @@ func NewWASMPullerWithCache(cacheDir string) *WASMPuller {
 // Pull downloads a WASM component from a registry
 // Parameters are now explicit instead of using a types package
 func (p *WASMPuller) Pull(ctx context.Context, registry, packageName, version string) (string, error) {
+	return p.PullTarget(ctx, registry, packageName, version, DefaultTarget)
+}
+
+// PullTarget downloads the build of a WASM component for target. Components
+// published as a single manifest have one build, which is used whatever the
+// target.
+func (p *WASMPuller) PullTarget(ctx context.Context, registry, packageName, version string, target Target) (string, error) {
 	// Convert Spin-style package name (namespace:package) to OCI format (namespace/package)
 	// This handles cases like "bowlofarugula:fluid" -> "bowlofarugula/fluid"
 	ociPackageName := strings.Replace(packageName, ":", "/", 1)
@@ -65,10 +78,14 @@ func (p *WASMPuller) Pull(ctx context.Context, registry, packageName, version st
 		return "", fmt.Errorf("invalid reference %s: %w", ref, err)
 	}
 
-	// Pull the image
-	img, err := remote.Image(tag, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	// Pull the image, or the target's image from an index
+	desc, err := remote.Get(tag, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx))
 	if err != nil {
-		return "", fmt.Errorf("failed to pull %s: %w", ref, err)
+		return "", ftlerr.Wrap(err, ftlerr.RegistryPullFailed, "failed to pull "+ref).WithHint("Check the component's registry, package and version, and that you can read from the registry")
+	}
+	img, err := targetImage(desc, target)
+	if err != nil {
+		return "", ftlerr.Wrap(err, ftlerr.RegistryPullFailed, "failed to pull "+ref)
 	}
 
 	// Get the manifest to find the WASM layer
@@ -142,6 +159,26 @@ func (p *WASMPuller) Pull(ctx context.Context, registry, packageName, version st
 	return cachePath, nil
 }
 
+// targetImage resolves a pulled descriptor to the image built for target
+func targetImage(desc *remote.Descriptor, target Target) (v1.Image, error) {
+	if !desc.MediaType.IsIndex() {
+		return desc.Image()
+	}
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image index: %w", err)
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image index: %w", err)
+	}
+	selected, err := selectManifest(manifest.Manifests, target)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Image(selected.Digest)
+}
+
 // WASMPusher handles pushing WASM components to OCI registries
 type WASMPusher struct {
 	auth *ECRAuth
@@ -155,6 +192,12 @@ func NewWASMPusher(auth *ECRAuth) *WASMPusher {
 // Push uploads a WASM component to a registry as an OCI artifact
 // Following the CNCF TAG Runtime WASM OCI Artifact specification
 func (p *WASMPusher) Push(ctx context.Context, wasmPath, packageName, version string) error {
+	return p.PushWithMetadata(ctx, wasmPath, packageName, version, nil)
+}
+
+// PushWithMetadata uploads a WASM component with metadata describing it,
+// stored as manifest annotations
+func (p *WASMPusher) PushWithMetadata(ctx context.Context, wasmPath, packageName, version string, meta *Metadata) error {
 	// Clean the WASM file path
 	wasmPath = filepath.Clean(wasmPath)
 
@@ -165,37 +208,101 @@ func (p *WASMPusher) Push(ctx context.Context, wasmPath, packageName, version st
 	}
 
 	// Create and push the WASM OCI image
-	img, err := p.createWASMImage(wasmContent, version)
+	img, err := p.createTargetImage(wasmContent, version, DefaultTarget, meta)
 	if err != nil {
 		return fmt.Errorf("failed to create WASM image: %w", err)
 	}
 
-	// Construct the registry reference
-	ref := fmt.Sprintf("%s/%s:%s", p.auth.Registry, packageName, version)
-
-	// Parse the reference
-	tag, err := name.ParseReference(ref)
+	tag, err := p.reference(packageName, version)
 	if err != nil {
-		return fmt.Errorf("invalid reference %s: %w", ref, err)
+		return err
 	}
 
-	// Create authenticator
-	authConfig := authn.AuthConfig{
-		Username: p.auth.Username,
-		Password: p.auth.Password,
+	// Push the image
+	if err := remote.Write(tag, img, p.authOption(), remote.WithContext(ctx)); err != nil {
+		return pushError(err)
 	}
-	authenticator := authn.FromConfig(authConfig)
 
-	// Push the image
-	if err := remote.Write(tag, img, remote.WithAuth(authenticator)); err != nil {
-		return fmt.Errorf("failed to push to registry: %w", err)
+	return nil
+}
+
+// PushIndex uploads the builds of a multi-target WASM component, keyed by
+// target, as an OCI image index annotated with the component's metadata,
+// which may be nil. Pullers select a build with PullTarget.
+func (p *WASMPusher) PushIndex(ctx context.Context, builds map[Target]string, packageName, version string, meta *Metadata) error {
+	if len(builds) == 0 {
+		return fmt.Errorf("no builds to push")
+	}
+
+	// Order the index by target so pushes are reproducible
+	targets := make([]Target, 0, len(builds))
+	for target := range builds {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].String() < targets[j].String() })
+
+	var adds []mutate.IndexAddendum
+	for _, target := range targets {
+		wasmContent, err := os.ReadFile(filepath.Clean(builds[target]))
+		if err != nil {
+			return fmt.Errorf("failed to read WASM file for %s: %w", target, err)
+		}
+		img, err := p.createTargetImage(wasmContent, version, target, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create WASM image for %s: %w", target, err)
+		}
+		adds = append(adds, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: target.platform()},
+		})
 	}
 
+	idx := mutate.IndexMediaType(mutate.AppendManifests(empty.Index, adds...), types.OCIImageIndex)
+	annotations := meta.Annotations()
+	annotations["org.opencontainers.image.version"] = version
+	annotations["org.opencontainers.image.created"] = time.Now().UTC().Format(time.RFC3339)
+	idx = mutate.Annotations(idx, annotations).(v1.ImageIndex)
+
+	tag, err := p.reference(packageName, version)
+	if err != nil {
+		return err
+	}
+	if err := remote.WriteIndex(tag, idx, p.authOption(), remote.WithContext(ctx)); err != nil {
+		return pushError(err)
+	}
 	return nil
 }
 
+// reference returns the registry reference of a package version
+func (p *WASMPusher) reference(packageName, version string) (name.Reference, error) {
+	ref := fmt.Sprintf("%s/%s:%s", p.auth.Registry, packageName, version)
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	return tag, nil
+}
+
+// authOption authenticates pushes with the pusher's registry credentials
+func (p *WASMPusher) authOption() remote.Option {
+	return remote.WithAuth(authn.FromConfig(authn.AuthConfig{
+		Username: p.auth.Username,
+		Password: p.auth.Password,
+	}))
+}
+
+func pushError(err error) error {
+	return ftlerr.Wrap(err, ftlerr.RegistryPushFailed, "failed to push to registry").WithHint("Check that you are logged in to the registry with push access")
+}
+
 // createWASMImage creates a WASM OCI image from content
 func (p *WASMPusher) createWASMImage(wasmContent []byte, version string) (v1.Image, error) {
+	return p.createTargetImage(wasmContent, version, DefaultTarget, nil)
+}
+
+// createTargetImage creates a WASM OCI image for one target's build,
+// annotated with the component's metadata
+func (p *WASMPusher) createTargetImage(wasmContent []byte, version string, target Target, meta *Metadata) (v1.Image, error) {
 	// Calculate SHA256 for the WASM content
 	wasmHash := sha256.Sum256(wasmContent)
 	wasmHashStr := hex.EncodeToString(wasmHash[:])
@@ -207,7 +314,7 @@ func (p *WASMPusher) createWASMImage(wasmContent []byte, version string) (v1.Ima
 	configData := WASMConfig{
 		Created:      time.Now().UTC().Format(time.RFC3339),
 		Architecture: WASMArchitecture,
-		OS:           WASMOS,
+		OS:           target.OS,
 		LayerDigests: []string{fmt.Sprintf("sha256:%s", wasmHashStr)},
 	}
 	configData.RootFS.Type = "layers"
@@ -219,16 +326,16 @@ func (p *WASMPusher) createWASMImage(wasmContent []byte, version string) (v1.Ima
 	}
 
 	// Create annotations for the manifest
-	annotations := map[string]string{
-		"org.opencontainers.image.version": version,
-		"org.opencontainers.image.created": time.Now().UTC().Format(time.RFC3339),
-	}
+	annotations := meta.Annotations()
+	annotations["org.opencontainers.image.version"] = version
+	annotations["org.opencontainers.image.created"] = time.Now().UTC().Format(time.RFC3339)
 
 	// Create a custom WASM OCI image
 	return &wasmOCIImage{
 		wasmLayer:   wasmLayer,
 		config:      configJSON,
 		hashStr:     wasmHashStr,
+		os:          target.OS,
 		annotations: annotations,
 	}, nil
 }
@@ -0,0 +1,60 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// SignatureTag returns the tag under which cosign stores the signature of
+// the artifact with the given digest (e.g. "sha256-abc....sig")
+func SignatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+// HasSignatureTag reports whether a cosign signature tag is stored next to
+// the artifact in its registry. It only checks that the tag exists; the
+// signature itself is not verified, so the tag proves nothing about who
+// signed the artifact.
+func HasSignatureTag(ctx context.Context, registry, packageName, version string) (bool, error) {
+	ociPackageName := strings.Replace(packageName, ":", "/", 1)
+	ref := fmt.Sprintf("%s/%s:%s", registry, ociPackageName, version)
+
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return false, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+
+	opts := []remote.Option{
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithContext(ctx),
+	}
+
+	desc, err := remote.Head(tag, opts...)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	sigTag := tag.Context().Tag(SignatureTag(desc.Digest.String()))
+	if _, err := remote.Head(sigTag, opts...); err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to look up signature for %s: %w", ref, err)
+	}
+	return true, nil
+}
+
+// isNotFound reports whether a registry request failed because the
+// artifact or repository does not exist
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
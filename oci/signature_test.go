@@ -0,0 +1,57 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureTag(t *testing.T) {
+	assert.Equal(t, "sha256-abc123.sig", SignatureTag("sha256:abc123"))
+}
+
+func TestHasSignatureTag(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	wasmPath := filepath.Join(t.TempDir(), "component.wasm")
+	require.NoError(t, os.WriteFile(wasmPath, []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}, 0600))
+
+	ctx := context.Background()
+	pusher := NewWASMPusher(&ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.Push(ctx, wasmPath, "test/component", "1.0.0"))
+
+	signed, err := HasSignatureTag(ctx, regURL, "test:component", "1.0.0")
+	require.NoError(t, err)
+	assert.False(t, signed)
+
+	// Store a signature under the cosign tag for the artifact's digest
+	ref, err := name.ParseReference(regURL + "/test/component:1.0.0")
+	require.NoError(t, err)
+	desc, err := remote.Head(ref)
+	require.NoError(t, err)
+	require.NoError(t, pusher.Push(ctx, wasmPath, "test/component", SignatureTag(desc.Digest.String())))
+
+	signed, err = HasSignatureTag(ctx, regURL, "test:component", "1.0.0")
+	require.NoError(t, err)
+	assert.True(t, signed)
+}
+
+func TestHasSignature_MissingArtifact(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	_, err := HasSignatureTag(context.Background(), regURL, "test:missing", "1.0.0")
+	assert.ErrorContains(t, err, "failed to resolve")
+}
@@ -0,0 +1,87 @@
+package oci
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// Target identifies one build of a component that publishes several, such
+// as wasip1 and wasip2 builds or debug and release builds. Multi-target
+// components are pushed as an OCI image index whose manifests carry the
+// target as their platform.
+type Target struct {
+	// OS is the WASI version the build targets, e.g. "wasip2"
+	OS string
+	// Variant optionally distinguishes builds for the same OS, e.g. "debug"
+	Variant string
+}
+
+// DefaultTarget is the target pulled when none is requested
+var DefaultTarget = Target{OS: WASMOS}
+
+// ParseTarget parses a target written as "os" or "os/variant"
+func ParseTarget(s string) (Target, error) {
+	osName, variant, _ := strings.Cut(s, "/")
+	if osName == "" || strings.Contains(variant, "/") {
+		return Target{}, fmt.Errorf("invalid target %q: expected os or os/variant, e.g. wasip2 or wasip2/debug", s)
+	}
+	return Target{OS: osName, Variant: variant}, nil
+}
+
+// String returns the target in the form accepted by ParseTarget
+func (t Target) String() string {
+	if t.Variant == "" {
+		return t.OS
+	}
+	return t.OS + "/" + t.Variant
+}
+
+// platform returns the index platform selector for the target
+func (t Target) platform() *v1.Platform {
+	return &v1.Platform{Architecture: WASMArchitecture, OS: t.OS, Variant: t.Variant}
+}
+
+// selectManifest picks the manifest in an image index built for target. A
+// target without a variant prefers a build without one, then a release
+// build, then any build for the OS.
+func selectManifest(manifests []v1.Descriptor, target Target) (v1.Descriptor, error) {
+	var candidates []v1.Descriptor
+	for _, m := range manifests {
+		p := m.Platform
+		if p == nil || p.OS != target.OS || (p.Architecture != "" && p.Architecture != WASMArchitecture) {
+			continue
+		}
+		if p.Variant == target.Variant {
+			return m, nil
+		}
+		candidates = append(candidates, m)
+	}
+
+	if target.Variant == "" && len(candidates) > 0 {
+		for _, m := range candidates {
+			if m.Platform.Variant == "release" {
+				return m, nil
+			}
+		}
+		return candidates[0], nil
+	}
+	return v1.Descriptor{}, fmt.Errorf("no build for target %s (available: %s)", target, strings.Join(indexTargets(manifests), ", "))
+}
+
+// indexTargets lists the targets of the manifests in an image index
+func indexTargets(manifests []v1.Descriptor) []string {
+	var targets []string
+	for _, m := range manifests {
+		if m.Platform != nil {
+			targets = append(targets, Target{OS: m.Platform.OS, Variant: m.Platform.Variant}.String())
+		}
+	}
+	if len(targets) == 0 {
+		return []string{"none"}
+	}
+	sort.Strings(targets)
+	return targets
+}
@@ -0,0 +1,121 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("wasip2/debug")
+	require.NoError(t, err)
+	assert.Equal(t, Target{OS: "wasip2", Variant: "debug"}, target)
+	assert.Equal(t, "wasip2/debug", target.String())
+
+	target, err = ParseTarget("wasip1")
+	require.NoError(t, err)
+	assert.Equal(t, Target{OS: "wasip1"}, target)
+
+	for _, s := range []string{"", "/debug", "wasip2/debug/x"} {
+		_, err := ParseTarget(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSelectManifest(t *testing.T) {
+	desc := func(os, variant string) v1.Descriptor {
+		return v1.Descriptor{Platform: &v1.Platform{Architecture: WASMArchitecture, OS: os, Variant: variant}}
+	}
+	manifests := []v1.Descriptor{
+		desc("wasip1", ""),
+		desc("wasip2", "debug"),
+		desc("wasip2", "release"),
+	}
+
+	tests := []struct {
+		target Target
+		want   v1.Descriptor
+	}{
+		{Target{OS: "wasip1"}, manifests[0]},
+		{Target{OS: "wasip2", Variant: "debug"}, manifests[1]},
+		{Target{OS: "wasip2"}, manifests[2]},
+	}
+	for _, tt := range tests {
+		got, err := selectManifest(manifests, tt.target)
+		require.NoError(t, err, tt.target)
+		assert.Equal(t, tt.want, got, tt.target)
+	}
+
+	_, err := selectManifest(manifests, Target{OS: "wasip1", Variant: "debug"})
+	assert.ErrorContains(t, err, "no build for target wasip1/debug (available: wasip1, wasip2/debug, wasip2/release)")
+}
+
+func TestWASMPusher_PushIndex(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	dir := t.TempDir()
+	builds := map[Target]string{}
+	contents := map[Target][]byte{
+		{OS: "wasip1"}:                   []byte("wasip1 build"),
+		{OS: "wasip2", Variant: "debug"}: []byte("wasip2 debug build"),
+		{OS: "wasip2"}:                   []byte("wasip2 build"),
+	}
+	for target, content := range contents {
+		path := filepath.Join(dir, strings.ReplaceAll(target.String(), "/", "-")+".wasm")
+		require.NoError(t, os.WriteFile(path, content, 0600))
+		builds[target] = path
+	}
+
+	ctx := context.Background()
+	pusher := NewWASMPusher(&ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.PushIndex(ctx, builds, "test/multi", "1.0.0", nil))
+
+	puller := NewWASMPullerWithCache(t.TempDir())
+	for target, content := range contents {
+		path, err := puller.PullTarget(ctx, regURL, "test/multi", "1.0.0", target)
+		require.NoError(t, err, target)
+		pulled, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, content, pulled, target)
+	}
+
+	// Pull selects the default target
+	path, err := puller.Pull(ctx, regURL, "test/multi", "1.0.0")
+	require.NoError(t, err)
+	pulled, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wasip2 build"), pulled)
+
+	_, err = puller.PullTarget(ctx, regURL, "test/multi", "1.0.0", Target{OS: "wasip3"})
+	assert.ErrorContains(t, err, "no build for target wasip3")
+}
+
+func TestWASMPuller_PullTarget_SingleManifest(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	path := filepath.Join(t.TempDir(), "component.wasm")
+	require.NoError(t, os.WriteFile(path, []byte("only build"), 0600))
+
+	ctx := context.Background()
+	pusher := NewWASMPusher(&ECRAuth{Registry: regURL, Username: "test", Password: "test"})
+	require.NoError(t, pusher.Push(ctx, path, "test/single", "1.0.0"))
+
+	// A single-manifest artifact is used whatever the requested target
+	pulledPath, err := NewWASMPullerWithCache(t.TempDir()).PullTarget(ctx, regURL, "test/single", "1.0.0", Target{OS: "wasip1", Variant: "debug"})
+	require.NoError(t, err)
+	pulled, err := os.ReadFile(pulledPath)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("only build"), pulled)
+}
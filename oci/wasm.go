@@ -29,6 +29,7 @@ type wasmOCIImage struct {
 	wasmLayer   v1.Layer
 	config      []byte
 	hashStr     string
+	os          string
 	annotations map[string]string
 }
 
@@ -64,9 +65,13 @@ func (w *wasmOCIImage) ConfigName() (v1.Hash, error) {
 func (w *wasmOCIImage) ConfigFile() (*v1.ConfigFile, error) {
 	// We can't return the custom fields here, but we override RawConfigFile
 	// to provide the actual config with layerDigests
+	osName := w.os
+	if osName == "" {
+		osName = WASMOS
+	}
 	return &v1.ConfigFile{
 		Architecture: WASMArchitecture,
-		OS:           WASMOS,
+		OS:           osName,
 		Config:       v1.Config{},
 		RootFS: v1.RootFS{
 			Type:    "layers",
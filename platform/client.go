@@ -3,7 +3,11 @@
 package platform
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"cuelang.org/go/cue"
 	"github.com/fastertools/ftl/policy"
@@ -22,6 +26,9 @@ type Processor struct {
 	validator       *validation.Validator
 	synthesizer     *synthesis.Synthesizer
 	policyGenerator *policy.Generator
+
+	webhooksOnce sync.Once
+	webhooks     *webhookQueue
 }
 
 // Config defines platform-specific settings.
@@ -39,6 +46,26 @@ type Config struct {
 	// Security settings
 	RequireRegistryComponents bool     // If true, reject local file sources
 	AllowedRegistries         []string // Whitelist of allowed registries (empty = allow all)
+	AllowedRegions            []string // Regions apps may deploy to (empty = allow all)
+
+	// Resource settings used by EstimateDeployment
+	MemoryPerComponent int64   // Bytes of memory reserved per component. Default: 128 MiB
+	CostPerGBMonth     float64 // Price of a GB of reserved memory per month (0 = no cost estimate)
+	Quota              Quota   // Limits deployments are checked against
+
+	// Registry retention settings used by GarbageCollect
+	RetainVersions int // Newest versions kept per component. Default: 10
+
+	// ComponentSize returns the artifact size of a registry component.
+	// Default: the size of its WASM layers in the registry.
+	ComponentSize func(ctx context.Context, source *validation.RegistrySource) (int64, error)
+
+	// Webhook settings used by NotifyDeployment
+	Webhooks           []Webhook            // Endpoints called on deployment state changes
+	WebhookMaxAttempts int                  // Attempts per call before giving up. Default: 5
+	WebhookBackoff     time.Duration        // Delay before the first retry, doubled after each. Default: 1s
+	WebhookClient      *http.Client         // Default: a client with a 10s timeout
+	WebhookFailed      func(WebhookFailure) // Called when a call is given up on
 }
 
 // DefaultConfig returns production-ready default configuration.
@@ -55,6 +82,14 @@ func DefaultConfig() Config {
 			"ghcr.io",          // For gateway and authorizer
 			DefaultECRRegistry, // For user components
 		},
+		MemoryPerComponent: 128 << 20,
+		RetainVersions:     10,
+		WebhookMaxAttempts: 5,
+		WebhookBackoff:     time.Second,
+		Quota: Quota{
+			MaxComponents: 50,
+			WarnThreshold: 0.8,
+		},
 	}
 }
 
@@ -116,7 +151,8 @@ type ProcessMetadata struct {
 	AccessMode         string
 	InjectedGateway    bool
 	InjectedAuthorizer bool
-	SubjectsInjected   int // Number of allowed subjects that were injected
+	SubjectsInjected   int    // Number of allowed subjects that were injected
+	Region             string // Target region, set by ProcessRegions
 }
 
 // Process handles an FTL deployment request.
@@ -137,37 +173,17 @@ type ProcessMetadata struct {
 //   - org: Platform provides org members (filtered by allowed_roles if specified in config)
 //   - custom: No allowed subjects needed (app handles its own auth)
 func (p *Processor) Process(req ProcessRequest) (*ProcessResult, error) {
-	// 1. Validate and parse the configuration to typed structure
-	var cueValue interface{}
-	var err error
-
-	switch req.Format {
-	case "yaml":
-		cueValue, err = p.validator.ValidateYAML(req.ConfigData)
-		if err != nil {
-			return nil, fmt.Errorf("validation failed: %w", err)
-		}
-	case "json":
-		cueValue, err = p.validator.ValidateJSON(req.ConfigData)
-		if err != nil {
-			return nil, fmt.Errorf("validation failed: %w", err)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported format: %s", req.Format)
-	}
-
-	// Extract typed Application from validated CUE value
-	validatedApp, err := validation.ExtractApplication(cueValue.(cue.Value))
+	// 1-2. Validate and parse the configuration, checking components in strict mode
+	validatedApp, err := p.load(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract application: %w", err)
+		return nil, err
 	}
+	return p.process(validatedApp, req)
+}
 
-	// 2. Validate components if strict mode
-	if p.config.RequireRegistryComponents {
-		if err := p.validateComponents(validatedApp); err != nil {
-			return nil, err
-		}
-	}
+// process synthesizes a loaded application for deployment.
+func (p *Processor) process(validatedApp *validation.Application, req ProcessRequest) (*ProcessResult, error) {
+	var err error
 
 	// 3. Handle access mode
 	accessMode := validatedApp.Access
@@ -273,6 +289,41 @@ func (p *Processor) Process(req ProcessRequest) (*ProcessResult, error) {
 	return result, nil
 }
 
+// load validates and parses a request's configuration to a typed
+// Application and, in strict mode, checks its components.
+func (p *Processor) load(req ProcessRequest) (*validation.Application, error) {
+	var cueValue interface{}
+	var err error
+
+	switch req.Format {
+	case "yaml":
+		cueValue, err = p.validator.ValidateYAML(req.ConfigData)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	case "json":
+		cueValue, err = p.validator.ValidateJSON(req.ConfigData)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", req.Format)
+	}
+
+	// Extract typed Application from validated CUE value
+	validatedApp, err := validation.ExtractApplication(cueValue.(cue.Value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract application: %w", err)
+	}
+
+	if p.config.RequireRegistryComponents {
+		if err := p.validateComponents(validatedApp); err != nil {
+			return nil, err
+		}
+	}
+	return validatedApp, nil
+}
+
 // validateComponents ensures all components meet platform requirements.
 func (p *Processor) validateComponents(app *validation.Application) error {
 	for _, component := range app.Components {
@@ -23,6 +23,56 @@
 //
 //	deployToFermyon(result.SpinTOML)
 //
+// # Estimates
+//
+// Check a request against the configured quota before provisioning it:
+//
+//	config.Quota = platform.Quota{MaxComponents: 20, MaxMemoryBytes: 2 << 30, WarnThreshold: 0.8}
+//	processor := platform.NewProcessor(config)
+//
+//	estimate, err := processor.EstimateDeployment(ctx, request)
+//	if err != nil {
+//	    return handleError(err)
+//	}
+//	if estimate.Verdict == platform.VerdictReject {
+//	    return rejectDeployment(estimate.Reasons)
+//	}
+//
+// # Kubernetes
+//
+// Emit manifests for a cluster running SpinKube or a Spin containerd shim:
+//
+//	manifests, err := platform.KubernetesManifests(result, platform.KubernetesOptions{
+//	    Image: "registry.example.com/my-app:1.0.0",
+//	})
+//
+// # Registry Garbage Collection
+//
+// Delete old versions of an app's components, keeping the newest few:
+//
+//	result, err := processor.GarbageCollect(ctx, platform.GarbageCollectRequest{
+//	    Repositories: repositories,
+//	    Keep:         5,
+//	    Auth:         ecrAuth,
+//	})
+//
+// # Deployment Webhooks
+//
+// Call integrators' endpoints, signed and retried, as deployments change
+// state:
+//
+//	config.Webhooks = []platform.Webhook{{URL: "https://hooks.example.com/ftl", Secret: secret}}
+//	processor := platform.NewProcessor(config)
+//	defer processor.Close(ctx)
+//
+//	err := processor.NotifyDeployment(platform.WebhookDeploymentDeployed, platform.Deployment{
+//	    ID:      deploymentID,
+//	    AppName: result.Metadata.AppName,
+//	    URL:     appURL,
+//	})
+//
+// Receivers check calls with VerifyWebhook.
+//
 // # Platform Components
 //
 // The platform automatically injects security components:
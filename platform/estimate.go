@@ -0,0 +1,160 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fastertools/ftl/oci"
+	"github.com/fastertools/ftl/validation"
+)
+
+// Quota limits the resources a deployment may use. Zero limits are
+// unlimited.
+type Quota struct {
+	MaxComponents    int     // Components, including injected platform components
+	MaxArtifactBytes int64   // Total size of component artifacts
+	MaxMemoryBytes   int64   // Total memory reservation
+	MaxMonthlyCost   float64 // Estimated monthly cost
+
+	// WarnThreshold is the fraction of a limit above which a deployment
+	// is allowed with a warning, e.g. 0.8. Zero disables warnings.
+	WarnThreshold float64
+}
+
+// Verdict is the outcome of checking an estimate against the quota
+type Verdict string
+
+const (
+	// VerdictAllow means the deployment is within its quota
+	VerdictAllow Verdict = "allow"
+	// VerdictWarn means the deployment is close to a limit
+	VerdictWarn Verdict = "warn"
+	// VerdictReject means the deployment exceeds a limit
+	VerdictReject Verdict = "reject"
+)
+
+// EstimateResult describes the resources a deployment would use.
+type EstimateResult struct {
+	AppName string
+
+	// ComponentCount includes the injected gateway and authorizer
+	ComponentCount int
+
+	// ArtifactBytes is the total size of the component artifacts
+	ArtifactBytes int64
+
+	// MemoryBytes is the memory reserved for the deployment
+	MemoryBytes int64
+
+	// MonthlyCost is the estimated cost of the memory reservation, or 0
+	// if Config.CostPerGBMonth is not set
+	MonthlyCost float64
+
+	// Verdict and the reasons for it, one per limit approached or exceeded
+	Verdict Verdict
+	Reasons []string
+}
+
+// EstimateDeployment estimates the resources a deployment request would
+// use and checks them against the configured quota, so platforms can
+// reject or warn about a deployment before provisioning it.
+//
+// The request is validated as it would be by Process, and an invalid
+// request is an error rather than a rejection. The sizes of registry
+// components are read with Config.ComponentSize.
+func (p *Processor) EstimateDeployment(ctx context.Context, req ProcessRequest) (*EstimateResult, error) {
+	app, err := p.load(req)
+	if err != nil {
+		return nil, err
+	}
+
+	components := append([]*validation.Component{}, app.Components...)
+	components = append(components, &validation.Component{
+		ID: "mcp-gateway",
+		Source: &validation.RegistrySource{
+			Registry: p.config.GatewayRegistry,
+			Package:  p.config.GatewayPackage,
+			Version:  p.config.GatewayVersion,
+		},
+	})
+	if app.Access != "" && app.Access != "public" {
+		components = append(components, &validation.Component{
+			ID: "mcp-authorizer",
+			Source: &validation.RegistrySource{
+				Registry: p.config.AuthorizerRegistry,
+				Package:  p.config.AuthorizerPackage,
+				Version:  p.config.AuthorizerVersion,
+			},
+		})
+	}
+
+	result := &EstimateResult{
+		AppName:        app.Name,
+		ComponentCount: len(components),
+		MemoryBytes:    int64(len(components)) * p.config.MemoryPerComponent,
+	}
+	for _, component := range components {
+		size, err := p.componentSize(ctx, component.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get size of component %s: %w", component.ID, err)
+		}
+		result.ArtifactBytes += size
+	}
+	result.MonthlyCost = float64(result.MemoryBytes) / (1 << 30) * p.config.CostPerGBMonth
+
+	result.Verdict, result.Reasons = p.config.Quota.check(result)
+	return result, nil
+}
+
+// componentSize returns the size of a component's artifact
+func (p *Processor) componentSize(ctx context.Context, source validation.ComponentSource) (int64, error) {
+	switch src := source.(type) {
+	case *validation.LocalSource:
+		info, err := os.Stat(src.Path)
+		if err != nil {
+			return 0, err
+		}
+		return info.Size(), nil
+	case *validation.RegistrySource:
+		if p.config.ComponentSize != nil {
+			return p.config.ComponentSize(ctx, src)
+		}
+		info, err := oci.InspectComponent(ctx, src.Registry, src.Package, src.Version)
+		if err != nil {
+			return 0, err
+		}
+		return info.Size, nil
+	}
+	return 0, fmt.Errorf("unsupported component source %T", source)
+}
+
+// check compares an estimate to the quota
+func (q Quota) check(e *EstimateResult) (Verdict, []string) {
+	verdict := VerdictAllow
+	var reasons []string
+	limit := func(what string, used, allowed float64, format func(float64) string) {
+		if allowed <= 0 {
+			return
+		}
+		switch {
+		case used > allowed:
+			verdict = VerdictReject
+			reasons = append(reasons, fmt.Sprintf("%s %s exceeds the limit of %s", what, format(used), format(allowed)))
+		case q.WarnThreshold > 0 && used > allowed*q.WarnThreshold:
+			if verdict == VerdictAllow {
+				verdict = VerdictWarn
+			}
+			reasons = append(reasons, fmt.Sprintf("%s %s is close to the limit of %s", what, format(used), format(allowed)))
+		}
+	}
+
+	count := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	bytes := func(v float64) string { return fmt.Sprintf("%.1f MiB", v/(1<<20)) }
+	cost := func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	limit("component count", float64(e.ComponentCount), float64(q.MaxComponents), count)
+	limit("artifact size", float64(e.ArtifactBytes), float64(q.MaxArtifactBytes), bytes)
+	limit("memory reservation", float64(e.MemoryBytes), float64(q.MaxMemoryBytes), bytes)
+	limit("monthly cost", e.MonthlyCost, q.MaxMonthlyCost, cost)
+	return verdict, reasons
+}
@@ -0,0 +1,149 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastertools/ftl/validation"
+)
+
+const estimateTestConfig = `
+name: estimate-app
+access: private
+components:
+  - id: api
+    source:
+      registry: ghcr.io
+      package: test:api
+      version: 1.0.0
+  - id: worker
+    source:
+      registry: ghcr.io
+      package: test:worker
+      version: 1.0.0
+`
+
+func estimateTestProcessor(quota Quota) *Processor {
+	config := DefaultConfig()
+	config.CostPerGBMonth = 4
+	config.Quota = quota
+	config.ComponentSize = func(_ context.Context, source *validation.RegistrySource) (int64, error) {
+		if strings.HasPrefix(source.Package, "test:") {
+			return 3 << 20, nil
+		}
+		return 1 << 20, nil
+	}
+	return NewProcessor(config)
+}
+
+func TestProcessor_EstimateDeployment(t *testing.T) {
+	processor := estimateTestProcessor(Quota{})
+	result, err := processor.EstimateDeployment(context.Background(), ProcessRequest{
+		Format:     "yaml",
+		ConfigData: []byte(estimateTestConfig),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two user components plus the gateway and authorizer
+	if result.ComponentCount != 4 {
+		t.Errorf("expected 4 components, got %d", result.ComponentCount)
+	}
+	if result.ArtifactBytes != 8<<20 {
+		t.Errorf("expected 8 MiB of artifacts, got %d", result.ArtifactBytes)
+	}
+	if result.MemoryBytes != 4*128<<20 {
+		t.Errorf("expected 512 MiB of memory, got %d", result.MemoryBytes)
+	}
+	if result.MonthlyCost != 2 {
+		t.Errorf("expected a monthly cost of 2, got %v", result.MonthlyCost)
+	}
+	if result.Verdict != VerdictAllow || len(result.Reasons) != 0 {
+		t.Errorf("expected allow without reasons, got %s %v", result.Verdict, result.Reasons)
+	}
+}
+
+func TestProcessor_EstimateDeployment_Quota(t *testing.T) {
+	tests := []struct {
+		name        string
+		quota       Quota
+		wantVerdict Verdict
+		wantReasons []string
+	}{
+		{
+			name:        "within quota",
+			quota:       Quota{MaxComponents: 10, MaxArtifactBytes: 100 << 20, WarnThreshold: 0.8},
+			wantVerdict: VerdictAllow,
+		},
+		{
+			name:        "close to the memory limit",
+			quota:       Quota{MaxMemoryBytes: 600 << 20, WarnThreshold: 0.8},
+			wantVerdict: VerdictWarn,
+			wantReasons: []string{"memory reservation 512.0 MiB is close to the limit of 600.0 MiB"},
+		},
+		{
+			name:        "too many components and too expensive",
+			quota:       Quota{MaxComponents: 3, MaxMonthlyCost: 1, MaxMemoryBytes: 600 << 20, WarnThreshold: 0.8},
+			wantVerdict: VerdictReject,
+			wantReasons: []string{
+				"component count 4 exceeds the limit of 3",
+				"memory reservation 512.0 MiB is close to the limit of 600.0 MiB",
+				"monthly cost 2.00 exceeds the limit of 1.00",
+			},
+		},
+		{
+			name:        "warnings disabled",
+			quota:       Quota{MaxMemoryBytes: 600 << 20},
+			wantVerdict: VerdictAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := estimateTestProcessor(tt.quota).EstimateDeployment(context.Background(), ProcessRequest{
+				Format:     "yaml",
+				ConfigData: []byte(estimateTestConfig),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Verdict != tt.wantVerdict {
+				t.Errorf("expected verdict %s, got %s", tt.wantVerdict, result.Verdict)
+			}
+			if strings.Join(result.Reasons, "\n") != strings.Join(tt.wantReasons, "\n") {
+				t.Errorf("expected reasons %q, got %q", tt.wantReasons, result.Reasons)
+			}
+		})
+	}
+}
+
+func TestProcessor_EstimateDeployment_Errors(t *testing.T) {
+	processor := estimateTestProcessor(Quota{})
+	_, err := processor.EstimateDeployment(context.Background(), ProcessRequest{
+		Format: "yaml",
+		ConfigData: []byte(`
+name: local-app
+components:
+  - id: api
+    source: ./api.wasm
+`),
+	})
+	if err == nil || !strings.Contains(err.Error(), "local component sources not allowed") {
+		t.Errorf("expected local sources to be rejected, got %v", err)
+	}
+
+	config := DefaultConfig()
+	config.ComponentSize = func(context.Context, *validation.RegistrySource) (int64, error) {
+		return 0, errors.New("registry unavailable")
+	}
+	_, err = NewProcessor(config).EstimateDeployment(context.Background(), ProcessRequest{
+		Format:     "yaml",
+		ConfigData: []byte(estimateTestConfig),
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to get size of component api") {
+		t.Errorf("expected a size error, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fastertools/ftl/oci"
+)
+
+// GarbageCollectRequest selects the component repositories of an app whose
+// old versions are deleted
+type GarbageCollectRequest struct {
+	// Repositories are the app's component repositories, e.g.
+	// "<account>.dkr.ecr.us-west-2.amazonaws.com/<namespace>/<component>"
+	Repositories []string
+
+	// Keep is how many of the newest versions are kept per component
+	// (0 = Config.RetainVersions)
+	Keep int
+
+	// Protect lists versions kept whatever their age, such as those of
+	// the app's current deployment
+	Protect []string
+
+	// Auth authenticates to the registry (nil = default keychain)
+	Auth *oci.ECRAuth
+
+	// DryRun reports the versions that would be deleted without deleting
+	DryRun bool
+}
+
+// GarbageCollectResult lists the versions deleted, or that would be
+// deleted in a dry run
+type GarbageCollectResult struct {
+	Pruned []oci.PrunedVersion `json:"pruned"`
+}
+
+// GarbageCollect deletes the versions of an app's components beyond the
+// retention policy. Every deployment pushes its components, so without it
+// their repositories grow forever. Repositories must be in an allowed
+// registry.
+func (p *Processor) GarbageCollect(ctx context.Context, req GarbageCollectRequest) (*GarbageCollectResult, error) {
+	policy := oci.RetentionPolicy{Keep: req.Keep, Protect: req.Protect}
+	if policy.Keep == 0 {
+		policy.Keep = p.config.RetainVersions
+	}
+
+	for _, repository := range req.Repositories {
+		registry, _, _ := strings.Cut(repository, "/")
+		if !p.isAllowedRegistry(registry) {
+			return nil, fmt.Errorf("registry not allowed: %s", registry)
+		}
+	}
+
+	result := &GarbageCollectResult{Pruned: []oci.PrunedVersion{}}
+	for _, repository := range req.Repositories {
+		pruned, err := oci.PruneRepository(ctx, repository, req.Auth, policy, req.DryRun)
+		result.Pruned = append(result.Pruned, pruned...)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
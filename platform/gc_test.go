@@ -0,0 +1,61 @@
+package platform
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fastertools/ftl/oci"
+)
+
+func TestProcessor_GarbageCollect(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	regURL := strings.TrimPrefix(s.URL, "http://")
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	auth := &oci.ECRAuth{Registry: regURL, Username: "test", Password: "test"}
+	pusher := oci.NewWASMPusher(auth)
+	for _, version := range []string{"0.1.0", "0.2.0", "0.3.0"} {
+		wasm := filepath.Join(dir, version+".wasm")
+		require.NoError(t, os.WriteFile(wasm, []byte("component "+version), 0600))
+		require.NoError(t, pusher.Push(ctx, wasm, "app-123/weather", version))
+	}
+
+	config := DefaultConfig()
+	config.AllowedRegistries = []string{regURL}
+	config.RetainVersions = 2
+	processor := NewProcessor(config)
+
+	result, err := processor.GarbageCollect(ctx, GarbageCollectRequest{
+		Repositories: []string{regURL + "/app-123/weather", regURL + "/app-123/never-pushed"},
+		Auth:         auth,
+		DryRun:       true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Pruned, 1)
+	assert.Equal(t, "0.1.0", result.Pruned[0].Version)
+
+	result, err = processor.GarbageCollect(ctx, GarbageCollectRequest{
+		Repositories: []string{regURL + "/app-123/weather"},
+		Keep:         1,
+		Protect:      []string{"0.1.0"},
+		Auth:         auth,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Pruned, 1)
+	assert.Equal(t, "0.2.0", result.Pruned[0].Version)
+
+	_, err = processor.GarbageCollect(ctx, GarbageCollectRequest{
+		Repositories: []string{"docker.io/library/weather"},
+	})
+	assert.ErrorContains(t, err, "registry not allowed")
+}
@@ -0,0 +1,383 @@
+package platform
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// KubernetesKind selects the workload KubernetesManifests emits.
+type KubernetesKind string
+
+const (
+	// KubernetesSpinApp emits a SpinKube SpinApp resource, run by the Spin
+	// operator
+	KubernetesSpinApp KubernetesKind = "SpinApp"
+	// KubernetesDeployment emits a Deployment and a Service running on a
+	// Spin containerd shim runtime class
+	KubernetesDeployment KubernetesKind = "Deployment"
+)
+
+// Labels and annotations set on the emitted resources
+const (
+	kubernetesNameLabel          = "app.kubernetes.io/name"
+	kubernetesVersionLabel       = "app.kubernetes.io/version"
+	kubernetesManagedByLabel     = "app.kubernetes.io/managed-by"
+	kubernetesComponentsAnnot    = "ftl.fastertools.dev/components"
+	kubernetesAccessModeAnnot    = "ftl.fastertools.dev/access-mode"
+	kubernetesManifestKey        = "spin.toml"
+	kubernetesDefaultExecutor    = "containerd-shim-spin"
+	kubernetesDefaultRuntime     = "wasmtime-spin-v2"
+	kubernetesDefaultPort        = 80
+	kubernetesSpinVariablePrefix = "SPIN_VARIABLE_"
+)
+
+// KubernetesOptions configures the manifests emitted for a deployment.
+type KubernetesOptions struct {
+	// Kind of workload to emit. Default: KubernetesSpinApp
+	Kind KubernetesKind
+
+	// Image is the OCI reference of the Spin application pushed from the
+	// result's SpinTOML, e.g. with 'spin registry push'. Required.
+	Image string
+
+	// Namespace of the emitted resources (empty = the namespace they are
+	// applied to)
+	Namespace string
+
+	// Replicas of the workload. Default: 1
+	Replicas int32
+
+	// Executor of a SpinApp. Default: containerd-shim-spin
+	Executor string
+
+	// RuntimeClassName of a Deployment. Default: wasmtime-spin-v2
+	RuntimeClassName string
+
+	// Port the Service of a Deployment listens on. Default: 80
+	Port int32
+
+	// Variables are passed to the application as Spin variables
+	Variables map[string]string
+
+	// SecretName is the Kubernetes Secret the application's secret
+	// variables are read from, keyed by variable name. Secret variables
+	// are left for the platform to provide when empty.
+	SecretName string
+}
+
+// KubernetesManifests turns a processed deployment into Kubernetes
+// manifests, as a multi-document YAML stream, so platforms running their
+// own clusters can deploy FTL applications.
+//
+// The stream holds a ConfigMap with the Spin manifest, including the
+// injected gateway and authorizer components, followed by a SpinApp or a
+// Deployment and Service running opts.Image. The image must be built from
+// that Spin manifest.
+func KubernetesManifests(result *ProcessResult, opts KubernetesOptions) ([]byte, error) {
+	if result == nil {
+		return nil, fmt.Errorf("no deployment result")
+	}
+	if opts.Image == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+	if opts.Kind == "" {
+		opts.Kind = KubernetesSpinApp
+	}
+	if opts.Kind != KubernetesSpinApp && opts.Kind != KubernetesDeployment {
+		return nil, fmt.Errorf("unsupported kubernetes kind: %s", opts.Kind)
+	}
+	if opts.Replicas == 0 {
+		opts.Replicas = 1
+	}
+
+	manifest, err := parseSpinManifest(result.SpinTOML)
+	if err != nil {
+		return nil, err
+	}
+
+	name := result.Metadata.AppName
+	meta := kubernetesMetadata{
+		Name:      name,
+		Namespace: opts.Namespace,
+		Labels: map[string]string{
+			kubernetesNameLabel:      name,
+			kubernetesVersionLabel:   result.Metadata.AppVersion,
+			kubernetesManagedByLabel: "ftl",
+		},
+		Annotations: map[string]string{
+			kubernetesComponentsAnnot: strings.Join(manifest.components(), ","),
+			kubernetesAccessModeAnnot: result.Metadata.AccessMode,
+		},
+	}
+	variables := kubernetesVariables(manifest, opts)
+
+	configMap := kubernetesConfigMap{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata:   meta.named(name + "-spin-manifest"),
+		Data:       map[string]string{kubernetesManifestKey: result.SpinTOML},
+	}
+	objects := []interface{}{configMap}
+
+	switch opts.Kind {
+	case KubernetesSpinApp:
+		executor := opts.Executor
+		if executor == "" {
+			executor = kubernetesDefaultExecutor
+		}
+		objects = append(objects, kubernetesSpinApp{
+			APIVersion: "core.spinkube.dev/v1alpha1",
+			Kind:       "SpinApp",
+			Metadata:   meta,
+			Spec: kubernetesSpinAppSpec{
+				Image:     opts.Image,
+				Executor:  executor,
+				Replicas:  opts.Replicas,
+				Variables: variables,
+			},
+		})
+
+	case KubernetesDeployment:
+		runtimeClass := opts.RuntimeClassName
+		if runtimeClass == "" {
+			runtimeClass = kubernetesDefaultRuntime
+		}
+		port := opts.Port
+		if port == 0 {
+			port = kubernetesDefaultPort
+		}
+		selector := map[string]string{kubernetesNameLabel: name}
+
+		env := make([]kubernetesVariable, 0, len(variables))
+		for _, v := range variables {
+			v.Name = kubernetesSpinVariablePrefix + strings.ToUpper(v.Name)
+			env = append(env, v)
+		}
+
+		objects = append(objects,
+			kubernetesDeployment{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Metadata:   meta,
+				Spec: kubernetesDeploymentSpec{
+					Replicas: opts.Replicas,
+					Selector: kubernetesSelector{MatchLabels: selector},
+					Template: kubernetesPodTemplate{
+						Metadata: kubernetesMetadata{Labels: meta.Labels, Annotations: meta.Annotations},
+						Spec: kubernetesPodSpec{
+							RuntimeClassName: runtimeClass,
+							Containers: []kubernetesContainer{{
+								Name:    name,
+								Image:   opts.Image,
+								Command: []string{"/"},
+								Ports:   []kubernetesContainerPort{{ContainerPort: kubernetesDefaultPort}},
+								Env:     env,
+							}},
+						},
+					},
+				},
+			},
+			kubernetesService{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Metadata:   meta,
+				Spec: kubernetesServiceSpec{
+					Selector: selector,
+					Ports: []kubernetesServicePort{{
+						Name:       "http",
+						Port:       port,
+						TargetPort: kubernetesDefaultPort,
+					}},
+				},
+			},
+		)
+	}
+
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal kubernetes manifest: %w", err)
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// spinManifest is the part of a Spin manifest the emitter reads
+type spinManifest struct {
+	Variables map[string]struct {
+		Secret bool `toml:"secret"`
+	} `toml:"variables"`
+	Component map[string]toml.Primitive `toml:"component"`
+}
+
+func parseSpinManifest(spinTOML string) (*spinManifest, error) {
+	var manifest spinManifest
+	if _, err := toml.Decode(spinTOML, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse spin manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// components returns the IDs of the manifest's components, sorted
+func (m *spinManifest) components() []string {
+	ids := make([]string, 0, len(m.Component))
+	for id := range m.Component {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// kubernetesVariables returns the Spin variables of the workload, sorted
+// by name: the given values, then the secret variables read from
+// opts.SecretName
+func kubernetesVariables(manifest *spinManifest, opts KubernetesOptions) []kubernetesVariable {
+	names := make([]string, 0, len(opts.Variables))
+	for name := range opts.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	variables := make([]kubernetesVariable, 0, len(names))
+	for _, name := range names {
+		variables = append(variables, kubernetesVariable{Name: name, Value: opts.Variables[name]})
+	}
+
+	if opts.SecretName == "" {
+		return variables
+	}
+	secrets := make([]string, 0, len(manifest.Variables))
+	for name, variable := range manifest.Variables {
+		if _, set := opts.Variables[name]; variable.Secret && !set {
+			secrets = append(secrets, name)
+		}
+	}
+	sort.Strings(secrets)
+	for _, name := range secrets {
+		variables = append(variables, kubernetesVariable{
+			Name: name,
+			ValueFrom: &kubernetesValueFrom{
+				SecretKeyRef: &kubernetesKeyRef{Name: opts.SecretName, Key: name},
+			},
+		})
+	}
+	return variables
+}
+
+type kubernetesMetadata struct {
+	Name        string            `yaml:"name,omitempty"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// named returns a copy of the metadata with another name
+func (m kubernetesMetadata) named(name string) kubernetesMetadata {
+	m.Name = name
+	return m
+}
+
+type kubernetesConfigMap struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   kubernetesMetadata `yaml:"metadata"`
+	Data       map[string]string  `yaml:"data"`
+}
+
+type kubernetesSpinApp struct {
+	APIVersion string                `yaml:"apiVersion"`
+	Kind       string                `yaml:"kind"`
+	Metadata   kubernetesMetadata    `yaml:"metadata"`
+	Spec       kubernetesSpinAppSpec `yaml:"spec"`
+}
+
+type kubernetesSpinAppSpec struct {
+	Image     string               `yaml:"image"`
+	Executor  string               `yaml:"executor"`
+	Replicas  int32                `yaml:"replicas"`
+	Variables []kubernetesVariable `yaml:"variables,omitempty"`
+}
+
+// kubernetesVariable is both a SpinApp variable and a container
+// environment variable, which share their shape
+type kubernetesVariable struct {
+	Name      string               `yaml:"name"`
+	Value     string               `yaml:"value,omitempty"`
+	ValueFrom *kubernetesValueFrom `yaml:"valueFrom,omitempty"`
+}
+
+type kubernetesValueFrom struct {
+	SecretKeyRef *kubernetesKeyRef `yaml:"secretKeyRef,omitempty"`
+}
+
+type kubernetesKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type kubernetesDeployment struct {
+	APIVersion string                   `yaml:"apiVersion"`
+	Kind       string                   `yaml:"kind"`
+	Metadata   kubernetesMetadata       `yaml:"metadata"`
+	Spec       kubernetesDeploymentSpec `yaml:"spec"`
+}
+
+type kubernetesDeploymentSpec struct {
+	Replicas int32                 `yaml:"replicas"`
+	Selector kubernetesSelector    `yaml:"selector"`
+	Template kubernetesPodTemplate `yaml:"template"`
+}
+
+type kubernetesSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type kubernetesPodTemplate struct {
+	Metadata kubernetesMetadata `yaml:"metadata"`
+	Spec     kubernetesPodSpec  `yaml:"spec"`
+}
+
+type kubernetesPodSpec struct {
+	RuntimeClassName string                `yaml:"runtimeClassName"`
+	Containers       []kubernetesContainer `yaml:"containers"`
+}
+
+type kubernetesContainer struct {
+	Name    string                    `yaml:"name"`
+	Image   string                    `yaml:"image"`
+	Command []string                  `yaml:"command"`
+	Ports   []kubernetesContainerPort `yaml:"ports"`
+	Env     []kubernetesVariable      `yaml:"env,omitempty"`
+}
+
+type kubernetesContainerPort struct {
+	ContainerPort int32 `yaml:"containerPort"`
+}
+
+type kubernetesService struct {
+	APIVersion string                `yaml:"apiVersion"`
+	Kind       string                `yaml:"kind"`
+	Metadata   kubernetesMetadata    `yaml:"metadata"`
+	Spec       kubernetesServiceSpec `yaml:"spec"`
+}
+
+type kubernetesServiceSpec struct {
+	Selector map[string]string       `yaml:"selector"`
+	Ports    []kubernetesServicePort `yaml:"ports"`
+}
+
+type kubernetesServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int32  `yaml:"port"`
+	TargetPort int32  `yaml:"targetPort"`
+}
@@ -0,0 +1,139 @@
+package platform
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func processKubernetesTestApp(t *testing.T) *ProcessResult {
+	t.Helper()
+	result, err := NewProcessor(DefaultConfig()).Process(ProcessRequest{
+		Format: "yaml",
+		ConfigData: []byte(`
+name: weather-app
+version: 1.2.0
+access: private
+components:
+  - id: weather
+    secrets: [weather_api_key]
+    source:
+      registry: ghcr.io
+      package: test:weather
+      version: 1.0.0
+`),
+		AllowedSubjects: []string{"user_owner_123"},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+// decodeKubernetesManifests decodes a multi-document YAML stream
+func decodeKubernetesManifests(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	var objects []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var obj map[string]interface{}
+		err := decoder.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+func TestKubernetesManifestsSpinApp(t *testing.T) {
+	result := processKubernetesTestApp(t)
+
+	data, err := KubernetesManifests(result, KubernetesOptions{
+		Image:      "registry.example.com/weather-app:1.2.0",
+		Namespace:  "tools",
+		SecretName: "weather-app-secrets",
+		Variables:  map[string]string{"units": "metric"},
+	})
+	require.NoError(t, err)
+
+	objects := decodeKubernetesManifests(t, data)
+	require.Len(t, objects, 2)
+
+	configMap := objects[0]
+	assert.Equal(t, "ConfigMap", configMap["kind"])
+	assert.Equal(t, result.SpinTOML, configMap["data"].(map[string]interface{})["spin.toml"])
+
+	spinApp := objects[1]
+	assert.Equal(t, "core.spinkube.dev/v1alpha1", spinApp["apiVersion"])
+	assert.Equal(t, "SpinApp", spinApp["kind"])
+
+	meta := spinApp["metadata"].(map[string]interface{})
+	assert.Equal(t, "weather-app", meta["name"])
+	assert.Equal(t, "tools", meta["namespace"])
+	annotations := meta["annotations"].(map[string]interface{})
+	assert.Equal(t, "private", annotations["ftl.fastertools.dev/access-mode"])
+	assert.Contains(t, annotations["ftl.fastertools.dev/components"], "mcp-gateway")
+	assert.Contains(t, annotations["ftl.fastertools.dev/components"], "mcp-authorizer")
+	assert.Contains(t, annotations["ftl.fastertools.dev/components"], "weather")
+
+	spec := spinApp["spec"].(map[string]interface{})
+	assert.Equal(t, "registry.example.com/weather-app:1.2.0", spec["image"])
+	assert.Equal(t, "containerd-shim-spin", spec["executor"])
+	assert.Equal(t, 1, spec["replicas"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "units", "value": "metric"},
+		map[string]interface{}{"name": "weather_api_key", "valueFrom": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{"name": "weather-app-secrets", "key": "weather_api_key"},
+		}},
+	}, spec["variables"])
+}
+
+func TestKubernetesManifestsDeployment(t *testing.T) {
+	result := processKubernetesTestApp(t)
+
+	data, err := KubernetesManifests(result, KubernetesOptions{
+		Kind:       KubernetesDeployment,
+		Image:      "registry.example.com/weather-app:1.2.0",
+		Replicas:   3,
+		Port:       8080,
+		SecretName: "weather-app-secrets",
+	})
+	require.NoError(t, err)
+
+	objects := decodeKubernetesManifests(t, data)
+	require.Len(t, objects, 3)
+	assert.Equal(t, "ConfigMap", objects[0]["kind"])
+	assert.Equal(t, "Deployment", objects[1]["kind"])
+	assert.Equal(t, "Service", objects[2]["kind"])
+
+	spec := objects[1]["spec"].(map[string]interface{})
+	assert.Equal(t, 3, spec["replicas"])
+	pod := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, "wasmtime-spin-v2", pod["runtimeClassName"])
+	container := pod["containers"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "registry.example.com/weather-app:1.2.0", container["image"])
+	env := container["env"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "SPIN_VARIABLE_WEATHER_API_KEY", env["name"])
+
+	ports := objects[2]["spec"].(map[string]interface{})["ports"].([]interface{})
+	assert.Equal(t, 8080, ports[0].(map[string]interface{})["port"])
+	assert.Equal(t, 80, ports[0].(map[string]interface{})["targetPort"])
+}
+
+func TestKubernetesManifestsErrors(t *testing.T) {
+	result := processKubernetesTestApp(t)
+
+	_, err := KubernetesManifests(result, KubernetesOptions{})
+	assert.ErrorContains(t, err, "image is required")
+
+	_, err = KubernetesManifests(result, KubernetesOptions{Image: "x", Kind: "StatefulSet"})
+	assert.ErrorContains(t, err, "unsupported kubernetes kind")
+
+	_, err = KubernetesManifests(nil, KubernetesOptions{Image: "x"})
+	assert.Error(t, err)
+}
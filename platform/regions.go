@@ -0,0 +1,217 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RegionResult is the deployment-ready result for one region of a
+// multi-region deployment.
+type RegionResult struct {
+	Region string
+	*ProcessResult
+}
+
+// ProcessRegions processes a deployment request once for each region
+// listed in the application's regions, so platforms running Spin in
+// several locations can fan the deployment out. An application without
+// regions produces a single result with an empty Region, for the
+// platform's default region.
+//
+// Regions must be in Config.AllowedRegions unless it is empty.
+func (p *Processor) ProcessRegions(req ProcessRequest) ([]RegionResult, error) {
+	app, err := p.load(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range app.Regions {
+		if !p.isAllowedRegion(region) {
+			return nil, fmt.Errorf("region not allowed: %s", region)
+		}
+	}
+
+	// The Spin manifest does not depend on the region
+	result, err := p.process(app, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(app.Regions) == 0 {
+		return []RegionResult{{ProcessResult: result}}, nil
+	}
+
+	results := make([]RegionResult, 0, len(app.Regions))
+	for _, region := range app.Regions {
+		regional := *result
+		regional.Metadata.Region = region
+		results = append(results, RegionResult{Region: region, ProcessResult: &regional})
+	}
+	return results, nil
+}
+
+// isAllowedRegion checks if a region is in the whitelist.
+func (p *Processor) isAllowedRegion(region string) bool {
+	if len(p.config.AllowedRegions) == 0 {
+		return true
+	}
+	for _, allowed := range p.config.AllowedRegions {
+		if region == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionState is the progress of a deployment in one region
+type RegionState string
+
+const (
+	RegionPending   RegionState = "pending"
+	RegionDeploying RegionState = "deploying"
+	RegionSucceeded RegionState = "succeeded"
+	RegionFailed    RegionState = "failed"
+)
+
+// RolloutState aggregates the states of all regions of a deployment
+type RolloutState string
+
+const (
+	RolloutInProgress RolloutState = "in_progress"
+	RolloutSucceeded  RolloutState = "succeeded"
+	// RolloutPartial means some regions succeeded and others failed
+	RolloutPartial RolloutState = "partial"
+	RolloutFailed  RolloutState = "failed"
+)
+
+// RegionStatus is the status of a deployment in one region
+type RegionStatus struct {
+	Region       string      `json:"region"`
+	State        RegionState `json:"state"`
+	DeploymentID string      `json:"deployment_id,omitempty"`
+	URL          string      `json:"url,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// Rollout tracks a deployment across regions. It is safe for concurrent
+// use, so regions may be deployed in parallel.
+type Rollout struct {
+	mu      sync.Mutex
+	regions []RegionStatus
+}
+
+// NewRollout starts tracking a deployment to regions, all pending.
+func NewRollout(regions []string) *Rollout {
+	r := &Rollout{regions: make([]RegionStatus, 0, len(regions))}
+	for _, region := range regions {
+		r.regions = append(r.regions, RegionStatus{Region: region, State: RegionPending})
+	}
+	return r
+}
+
+// Start marks a region as deploying.
+func (r *Rollout) Start(region string) {
+	r.update(region, func(s *RegionStatus) { s.State = RegionDeploying })
+}
+
+// Succeed marks a region as deployed.
+func (r *Rollout) Succeed(region, deploymentID, url string) {
+	r.update(region, func(s *RegionStatus) {
+		s.State = RegionSucceeded
+		s.DeploymentID = deploymentID
+		s.URL = url
+		s.Error = ""
+	})
+}
+
+// Fail marks a region as failed. The deployment ID may be empty if the
+// platform never accepted the deployment.
+func (r *Rollout) Fail(region, deploymentID string, err error) {
+	r.update(region, func(s *RegionStatus) {
+		s.State = RegionFailed
+		s.DeploymentID = deploymentID
+		s.Error = err.Error()
+	})
+}
+
+func (r *Rollout) update(region string, fn func(*RegionStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.regions {
+		if r.regions[i].Region == region {
+			fn(&r.regions[i])
+			return
+		}
+	}
+	status := RegionStatus{Region: region, State: RegionPending}
+	fn(&status)
+	r.regions = append(r.regions, status)
+}
+
+// Regions returns the status of each region in the order they were added.
+func (r *Rollout) Regions() []RegionStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RegionStatus(nil), r.regions...)
+}
+
+// State aggregates the region states. The rollout is in progress until
+// every region has succeeded or failed.
+func (r *Rollout) State() RolloutState {
+	regions := r.Regions()
+	var succeeded, failed int
+	for _, s := range regions {
+		switch s.State {
+		case RegionSucceeded:
+			succeeded++
+		case RegionFailed:
+			failed++
+		}
+	}
+	switch {
+	case succeeded+failed < len(regions):
+		return RolloutInProgress
+	case failed == 0:
+		return RolloutSucceeded
+	case succeeded == 0:
+		return RolloutFailed
+	}
+	return RolloutPartial
+}
+
+// Err returns a *RolloutError if any region failed, or nil.
+func (r *Rollout) Err() error {
+	var failed []RegionStatus
+	regions := r.Regions()
+	for _, s := range regions {
+		if s.State == RegionFailed {
+			failed = append(failed, s)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &RolloutError{Failed: failed, Total: len(regions)}
+}
+
+// RolloutError reports the regions a deployment failed in.
+type RolloutError struct {
+	Failed []RegionStatus
+	Total  int
+}
+
+func (e *RolloutError) Error() string {
+	details := make([]string, 0, len(e.Failed))
+	for _, s := range e.Failed {
+		details = append(details, fmt.Sprintf("%s: %s", s.Region, s.Error))
+	}
+	return fmt.Sprintf("deployment failed in %d of %d regions (%s)", len(e.Failed), e.Total, strings.Join(details, "; "))
+}
+
+// FailedRegions lists the regions the deployment failed in.
+func (e *RolloutError) FailedRegions() []string {
+	regions := make([]string, 0, len(e.Failed))
+	for _, s := range e.Failed {
+		regions = append(regions, s.Region)
+	}
+	return regions
+}
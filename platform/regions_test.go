@@ -0,0 +1,109 @@
+package platform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const regionsTestConfig = `
+name: regional-app
+regions: [us-east-1, eu-west-1]
+components:
+  - id: api
+    source:
+      registry: ghcr.io
+      package: test:api
+      version: 1.0.0
+`
+
+func TestProcessor_ProcessRegions(t *testing.T) {
+	processor := NewProcessor(DefaultConfig())
+	results, err := processor.ProcessRegions(ProcessRequest{Format: "yaml", ConfigData: []byte(regionsTestConfig)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 regional results, got %d", len(results))
+	}
+	for i, want := range []string{"us-east-1", "eu-west-1"} {
+		if results[i].Region != want || results[i].Metadata.Region != want {
+			t.Errorf("result %d: expected region %s, got %s (metadata %s)", i, want, results[i].Region, results[i].Metadata.Region)
+		}
+		if !strings.Contains(results[i].SpinTOML, "mcp-gateway") {
+			t.Errorf("result %d: SpinTOML should contain mcp-gateway", i)
+		}
+	}
+
+	// Without regions the platform's default region is used
+	single := strings.Replace(regionsTestConfig, "regions: [us-east-1, eu-west-1]\n", "", 1)
+	results, err = processor.ProcessRegions(ProcessRequest{Format: "yaml", ConfigData: []byte(single)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Region != "" {
+		t.Errorf("expected a single result for the default region, got %+v", results)
+	}
+}
+
+func TestProcessor_ProcessRegions_NotAllowed(t *testing.T) {
+	config := DefaultConfig()
+	config.AllowedRegions = []string{"us-east-1"}
+	_, err := NewProcessor(config).ProcessRegions(ProcessRequest{Format: "yaml", ConfigData: []byte(regionsTestConfig)})
+	if err == nil || !strings.Contains(err.Error(), "region not allowed: eu-west-1") {
+		t.Errorf("expected eu-west-1 to be rejected, got %v", err)
+	}
+}
+
+func TestRollout(t *testing.T) {
+	rollout := NewRollout([]string{"us-east-1", "eu-west-1", "ap-south-1"})
+	if rollout.State() != RolloutInProgress {
+		t.Errorf("expected in_progress, got %s", rollout.State())
+	}
+
+	rollout.Start("us-east-1")
+	rollout.Succeed("us-east-1", "dep-1", "https://us.example.com")
+	rollout.Succeed("eu-west-1", "dep-2", "https://eu.example.com")
+	if rollout.State() != RolloutInProgress {
+		t.Errorf("expected in_progress with a region pending, got %s", rollout.State())
+	}
+	if rollout.Err() != nil {
+		t.Errorf("expected no error before any failure, got %v", rollout.Err())
+	}
+
+	rollout.Fail("ap-south-1", "", errors.New("capacity exceeded"))
+	if rollout.State() != RolloutPartial {
+		t.Errorf("expected partial, got %s", rollout.State())
+	}
+
+	var rolloutErr *RolloutError
+	if !errors.As(rollout.Err(), &rolloutErr) {
+		t.Fatalf("expected a RolloutError, got %v", rollout.Err())
+	}
+	if got := strings.Join(rolloutErr.FailedRegions(), ","); got != "ap-south-1" {
+		t.Errorf("expected ap-south-1 to have failed, got %s", got)
+	}
+	if want := "deployment failed in 1 of 3 regions (ap-south-1: capacity exceeded)"; rolloutErr.Error() != want {
+		t.Errorf("expected %q, got %q", want, rolloutErr.Error())
+	}
+
+	regions := rollout.Regions()
+	if regions[0].DeploymentID != "dep-1" || regions[1].URL != "https://eu.example.com" || regions[2].State != RegionFailed {
+		t.Errorf("unexpected region statuses: %+v", regions)
+	}
+}
+
+func TestRollout_States(t *testing.T) {
+	succeeded := NewRollout([]string{"us-east-1"})
+	succeeded.Succeed("us-east-1", "", "")
+	if succeeded.State() != RolloutSucceeded {
+		t.Errorf("expected succeeded, got %s", succeeded.State())
+	}
+
+	failed := NewRollout([]string{"us-east-1", "eu-west-1"})
+	failed.Fail("us-east-1", "", errors.New("boom"))
+	failed.Fail("eu-west-1", "", errors.New("boom"))
+	if failed.State() != RolloutFailed {
+		t.Errorf("expected failed, got %s", failed.State())
+	}
+}
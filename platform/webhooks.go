@@ -0,0 +1,341 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookEvent is a deployment state change webhooks are called for
+type WebhookEvent string
+
+const (
+	WebhookDeploymentCreated   WebhookEvent = "deployment.created"
+	WebhookDeploymentDeploying WebhookEvent = "deployment.deploying"
+	WebhookDeploymentDeployed  WebhookEvent = "deployment.deployed"
+	WebhookDeploymentFailed    WebhookEvent = "deployment.failed"
+)
+
+// Headers of webhook calls
+const (
+	WebhookEventHeader     = "X-FTL-Event"
+	WebhookDeliveryHeader  = "X-FTL-Delivery"
+	WebhookSignatureHeader = "X-FTL-Signature"
+)
+
+// Webhook is an endpoint called when deployments change state
+type Webhook struct {
+	// URL receives a POST with the DeploymentEvent as JSON
+	URL string
+
+	// Secret signs each call: the X-FTL-Signature header holds
+	// "t=<unix time>,v1=<hex HMAC-SHA256 of '<unix time>.<body>'>",
+	// checked with VerifyWebhook
+	Secret string
+
+	// Events the webhook is called for (empty = all)
+	Events []WebhookEvent
+}
+
+// Deployment identifies the deployment a webhook is called about
+type Deployment struct {
+	ID         string `json:"id"`
+	AppName    string `json:"app_name"`
+	AppVersion string `json:"app_version,omitempty"`
+	Region     string `json:"region,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DeploymentEvent is the body of a webhook call
+type DeploymentEvent struct {
+	// ID is the same for every attempt to deliver the event, so receivers
+	// can ignore duplicates
+	ID         string       `json:"id"`
+	Type       WebhookEvent `json:"type"`
+	Time       time.Time    `json:"time"`
+	Deployment Deployment   `json:"deployment"`
+}
+
+// WebhookFailure reports an event a webhook could not be called with
+type WebhookFailure struct {
+	URL      string
+	Event    DeploymentEvent
+	Attempts int
+	Err      error
+}
+
+// ErrWebhooksClosed is returned when notifying after Close
+var ErrWebhooksClosed = errors.New("webhooks are closed")
+
+// NotifyDeployment queues calls to the webhooks subscribed to the event.
+// Calls are made in the background and retried with exponential backoff
+// until they succeed or Config.WebhookMaxAttempts is reached, when
+// Config.WebhookFailed is called. Close waits for queued calls.
+func (p *Processor) NotifyDeployment(event WebhookEvent, deployment Deployment) error {
+	if len(p.config.Webhooks) == 0 {
+		return nil
+	}
+	return p.webhookQueue().notify(event, deployment)
+}
+
+// Close waits for queued webhook calls until ctx is done, then abandons
+// those left. Notifying after Close fails with ErrWebhooksClosed.
+func (p *Processor) Close(ctx context.Context) error {
+	if len(p.config.Webhooks) == 0 {
+		return nil
+	}
+	return p.webhookQueue().close(ctx)
+}
+
+func (p *Processor) webhookQueue() *webhookQueue {
+	p.webhooksOnce.Do(func() {
+		p.webhooks = newWebhookQueue(p.config)
+	})
+	return p.webhooks
+}
+
+// webhookDelivery is an event being delivered to one webhook
+type webhookDelivery struct {
+	webhook  Webhook
+	event    DeploymentEvent
+	body     []byte
+	attempts int
+}
+
+// webhookQueue delivers webhook calls from a single worker, scheduling
+// failed calls to be retried
+type webhookQueue struct {
+	webhooks    []Webhook
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	failed      func(WebhookFailure)
+
+	queue   chan *webhookDelivery
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newWebhookQueue(config Config) *webhookQueue {
+	q := &webhookQueue{
+		webhooks:    config.Webhooks,
+		client:      config.WebhookClient,
+		maxAttempts: config.WebhookMaxAttempts,
+		backoff:     config.WebhookBackoff,
+		failed:      config.WebhookFailed,
+		queue:       make(chan *webhookDelivery, 64),
+	}
+	if q.client == nil {
+		q.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if q.maxAttempts <= 0 {
+		q.maxAttempts = 5
+	}
+	if q.backoff <= 0 {
+		q.backoff = time.Second
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	go q.run()
+	return q
+}
+
+func (q *webhookQueue) notify(event WebhookEvent, deployment Deployment) error {
+	id, err := newEventID()
+	if err != nil {
+		return err
+	}
+	e := DeploymentEvent{ID: id, Type: event, Time: time.Now().UTC(), Deployment: deployment}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrWebhooksClosed
+	}
+	for _, webhook := range q.webhooks {
+		if !webhook.subscribed(event) {
+			continue
+		}
+		q.pending.Add(1)
+		q.enqueue(&webhookDelivery{webhook: webhook, event: e, body: body})
+	}
+	return nil
+}
+
+// enqueue hands a delivery to the worker without blocking the caller
+func (q *webhookQueue) enqueue(d *webhookDelivery) {
+	select {
+	case q.queue <- d:
+	default:
+		go func() {
+			select {
+			case q.queue <- d:
+			case <-q.ctx.Done():
+				q.fail(d, q.ctx.Err())
+			}
+		}()
+	}
+}
+
+func (q *webhookQueue) run() {
+	for {
+		select {
+		case d := <-q.queue:
+			q.deliver(d)
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *webhookQueue) deliver(d *webhookDelivery) {
+	d.attempts++
+	retry, err := q.call(d)
+	if err == nil {
+		q.pending.Done()
+		return
+	}
+	if !retry || d.attempts >= q.maxAttempts || q.ctx.Err() != nil {
+		q.fail(d, err)
+		return
+	}
+
+	delay := q.backoff << (d.attempts - 1)
+	time.AfterFunc(delay, func() { q.enqueue(d) })
+}
+
+// call makes one attempt to deliver an event, reporting whether a failed
+// attempt may be retried
+func (q *webhookQueue) call(d *webhookDelivery) (bool, error) {
+	req, err := http.NewRequestWithContext(q.ctx, http.MethodPost, d.webhook.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(d.event.Type))
+	req.Header.Set(WebhookDeliveryHeader, d.event.ID)
+	if d.webhook.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, signWebhook(d.webhook.Secret, time.Now(), d.body))
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+func (q *webhookQueue) fail(d *webhookDelivery, err error) {
+	if q.failed != nil {
+		q.failed(WebhookFailure{URL: d.webhook.URL, Event: d.event, Attempts: d.attempts, Err: err})
+	}
+	q.pending.Done()
+}
+
+func (q *webhookQueue) close(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		close(done)
+	}()
+	defer q.cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("webhook calls abandoned: %w", ctx.Err())
+	}
+}
+
+func (w Webhook) subscribed(event WebhookEvent) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func signWebhook(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + webhookMAC(secret, timestamp, body)
+}
+
+func webhookMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks the X-FTL-Signature of a webhook call received by
+// an integrator, rejecting calls signed more than tolerance ago to limit
+// replays (0 = any age).
+func VerifyWebhook(secret, signature string, body []byte, tolerance time.Duration) error {
+	var timestamp, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			mac = value
+		}
+	}
+	if timestamp == "" || mac == "" {
+		return errors.New("malformed webhook signature")
+	}
+	if !hmac.Equal([]byte(mac), []byte(webhookMAC(secret, timestamp, body))) {
+		return errors.New("webhook signature does not match")
+	}
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return errors.New("malformed webhook signature")
+		}
+		if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("webhook signature timestamp is %s off, beyond the %s tolerance", age.Round(time.Second), tolerance)
+		}
+	}
+	return nil
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,191 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// webhookReceiver records the events it receives, failing the first calls
+type webhookReceiver struct {
+	t        *testing.T
+	secret   string
+	failures int
+
+	mu     sync.Mutex
+	calls  int
+	events []DeploymentEvent
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if err := VerifyWebhook(r.secret, req.Header.Get(WebhookSignatureHeader), body, time.Minute); err != nil {
+		r.t.Errorf("invalid signature: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.calls <= r.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var event DeploymentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.t.Errorf("invalid body: %v", err)
+	}
+	if req.Header.Get(WebhookEventHeader) != string(event.Type) || req.Header.Get(WebhookDeliveryHeader) != event.ID {
+		r.t.Errorf("headers do not match event %+v: %v", event, req.Header)
+	}
+	r.events = append(r.events, event)
+}
+
+func (r *webhookReceiver) received() []DeploymentEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DeploymentEvent(nil), r.events...)
+}
+
+func webhookConfig(webhooks ...Webhook) Config {
+	config := DefaultConfig()
+	config.Webhooks = webhooks
+	config.WebhookBackoff = time.Millisecond
+	config.WebhookMaxAttempts = 3
+	return config
+}
+
+func TestProcessor_NotifyDeployment(t *testing.T) {
+	all := &webhookReceiver{t: t, secret: "s3cret", failures: 2}
+	allServer := httptest.NewServer(all)
+	defer allServer.Close()
+	failed := &webhookReceiver{t: t, secret: "other"}
+	failedServer := httptest.NewServer(failed)
+	defer failedServer.Close()
+
+	processor := NewProcessor(webhookConfig(
+		Webhook{URL: allServer.URL, Secret: "s3cret"},
+		Webhook{URL: failedServer.URL, Secret: "other", Events: []WebhookEvent{WebhookDeploymentFailed}},
+	))
+
+	deployment := Deployment{ID: "dep-1", AppName: "demo", AppVersion: "1.0.0", Region: "us-east-1"}
+	for _, event := range []WebhookEvent{WebhookDeploymentCreated, WebhookDeploymentDeploying} {
+		if err := processor.NotifyDeployment(event, deployment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deployment.Error = "out of memory"
+	if err := processor.NotifyDeployment(WebhookDeploymentFailed, deployment); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := processor.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first two calls fail and are retried
+	events := all.received()
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events after retries, got %+v", events)
+	}
+	types := map[WebhookEvent]bool{}
+	for _, event := range events {
+		types[event.Type] = true
+		if event.Deployment.ID != "dep-1" || event.Deployment.AppName != "demo" {
+			t.Errorf("unexpected deployment %+v", event.Deployment)
+		}
+	}
+	if !types[WebhookDeploymentCreated] || !types[WebhookDeploymentDeploying] || !types[WebhookDeploymentFailed] {
+		t.Errorf("expected every event, got %+v", events)
+	}
+
+	// Webhooks only receive the events they subscribe to
+	events = failed.received()
+	if len(events) != 1 || events[0].Type != WebhookDeploymentFailed || events[0].Deployment.Error != "out of memory" {
+		t.Errorf("expected only the failed event, got %+v", events)
+	}
+
+	if err := processor.NotifyDeployment(WebhookDeploymentDeployed, deployment); err != ErrWebhooksClosed {
+		t.Errorf("expected ErrWebhooksClosed after Close, got %v", err)
+	}
+}
+
+func TestProcessor_NotifyDeployment_GivesUp(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer rejecting.Close()
+
+	var mu sync.Mutex
+	var failures []WebhookFailure
+	config := webhookConfig(Webhook{URL: server.URL}, Webhook{URL: rejecting.URL})
+	config.WebhookFailed = func(f WebhookFailure) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, f)
+	}
+	processor := NewProcessor(config)
+	if err := processor.NotifyDeployment(WebhookDeploymentDeployed, Deployment{ID: "dep-1", AppName: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := processor.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+	if len(failures) != 2 {
+		t.Fatalf("expected both webhooks to fail, got %+v", failures)
+	}
+	for _, f := range failures {
+		want := 3
+		if f.URL == rejecting.URL {
+			// Client errors other than rate limits are not retried
+			want = 1
+		}
+		if f.Attempts != want || f.Event.Type != WebhookDeploymentDeployed || f.Err == nil {
+			t.Errorf("unexpected failure %+v", f)
+		}
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	signature := signWebhook("s3cret", time.Now(), body)
+
+	if err := VerifyWebhook("s3cret", signature, body, time.Minute); err != nil {
+		t.Errorf("expected a valid signature, got %v", err)
+	}
+	if err := VerifyWebhook("wrong", signature, body, time.Minute); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a mismatch with the wrong secret, got %v", err)
+	}
+	if err := VerifyWebhook("s3cret", signature, []byte(`{"id":"evt_2"}`), time.Minute); err == nil {
+		t.Error("expected a mismatch with a changed body")
+	}
+	if err := VerifyWebhook("s3cret", "v1=abc", body, 0); err == nil || !strings.Contains(err.Error(), "malformed") {
+		t.Errorf("expected a malformed signature, got %v", err)
+	}
+
+	old := signWebhook("s3cret", time.Now().Add(-time.Hour), body)
+	if err := VerifyWebhook("s3cret", old, body, 5*time.Minute); err == nil || !strings.Contains(err.Error(), "tolerance") {
+		t.Errorf("expected an old signature to be rejected, got %v", err)
+	}
+	if err := VerifyWebhook("s3cret", old, body, 0); err != nil {
+		t.Errorf("expected any age to be accepted without a tolerance, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package ftl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// CallDepthHeader carries how deeply nested a tool call is, so chains of
+// tools calling tools can be cut off before they loop forever. The gateway
+// forwards it to components.
+const CallDepthHeader = "X-FTL-Call-Depth"
+
+// MaxCallDepth is the deepest a chain of tool calls may nest. A tool called
+// by a client is at depth 0.
+const MaxCallDepth = 8
+
+// GatewayURL is the MCP endpoint CallTool sends calls to tools of other
+// components to. The component needs call_tools enabled in ftl.yaml to be
+// allowed to reach it.
+var GatewayURL = "http://mcp-gateway.spin.internal/mcp"
+
+// ErrCallDepthExceeded is returned by CallTool when a call would nest more
+// than MaxCallDepth deep
+var ErrCallDepthExceeded = errors.New("tool call depth limit exceeded")
+
+// sendRequest sends outbound HTTP requests. It is replaced when built for
+// Spin, which provides its own transport.
+var sendRequest = http.DefaultClient.Do
+
+// callState is what CallTool needs to know about the call it is made from
+type callState struct {
+	tools map[string]ToolDefinition
+	depth int
+}
+
+type callStateKey struct{}
+
+// withCallState records the component's tools and the depth of the current
+// call in ctx
+func withCallState(ctx context.Context, tools map[string]ToolDefinition, depth int) context.Context {
+	return context.WithValue(ctx, callStateKey{}, &callState{tools: tools, depth: depth})
+}
+
+func callStateFrom(ctx context.Context) *callState {
+	if state, ok := ctx.Value(callStateKey{}).(*callState); ok {
+		return state
+	}
+	return &callState{}
+}
+
+// callDepthFromHeader reads the depth of an incoming call
+func callDepthFromHeader(header http.Header) int {
+	depth, err := strconv.Atoi(header.Get(CallDepthHeader))
+	if err != nil || depth < 0 {
+		return 0
+	}
+	return depth
+}
+
+// CallTool calls another tool from inside a tool handler, so a component
+// can offer higher-level tools that orchestrate existing ones:
+//
+//	"summarize_url": {
+//		ContextHandler: func(ctx *ftl.ToolContext, input map[string]interface{}) ftl.ToolResponse {
+//			page, err := ftl.CallTool(ctx, "fetch__get_page", map[string]interface{}{"url": input["url"]})
+//			if err != nil {
+//				return ftl.Errorf("Failed to fetch page: %v", err)
+//			}
+//			if page.IsError {
+//				return page
+//			}
+//			return ftl.CallToolResponse(ctx, "summarize", map[string]interface{}{"text": page.Content[0].Text})
+//		},
+//	},
+//
+// Tools of the calling component are called directly by the name they are
+// listed under. Other names are sent through the gateway, which expects
+// them in its "component__tool" form. Pass the handler's ToolContext so the
+// call joins its trace, keeps its Meta and is counted against MaxCallDepth.
+// Tools of the same component see the caller's Identity; tools called
+// through the gateway do not.
+//
+// A tool that fails returns a response with IsError set; the error is only
+// set when the tool could not be called at all.
+func CallTool(ctx context.Context, name string, input map[string]interface{}) (ToolResponse, error) {
+	state := callStateFrom(ctx)
+	depth := state.depth + 1
+	if depth > MaxCallDepth {
+		return ToolResponse{}, fmt.Errorf("%w: calling %s at depth %d", ErrCallDepthExceeded, name, depth)
+	}
+	if input == nil {
+		input = make(map[string]interface{})
+	}
+
+	var trace TraceContext
+	var identity *Identity
+	var meta map[string]interface{}
+	if tc, ok := ctx.(*ToolContext); ok {
+		trace = tc.Trace
+		identity = tc.identity
+		meta = tc.Meta
+	}
+
+	if tool := findTool(ctx, state.tools, name); tool != nil {
+		return tool.call(&ToolContext{
+			Context:  withCallState(ctx, state.tools, depth),
+			ToolName: name,
+			Trace:    trace,
+			Meta:     meta,
+			identity: identity,
+		}, input), nil
+	}
+	return callGateway(ctx, name, input, depth, trace, meta)
+}
+
+// CallToolResponse calls another tool like CallTool and returns its
+// response, turning a failed call into an error response
+func CallToolResponse(ctx context.Context, name string, input map[string]interface{}) ToolResponse {
+	response, err := CallTool(ctx, name, input)
+	if err != nil {
+		return Errorf("Failed to call tool '%s': %v", name, err)
+	}
+	return response
+}
+
+// findTool finds an enabled tool by the name it is listed under
+func findTool(ctx context.Context, tools map[string]ToolDefinition, name string) *ToolDefinition {
+	for key, tool := range tools {
+		effectiveName := tool.Name
+		if effectiveName == "" {
+			effectiveName = camelToSnake(key)
+		}
+		if effectiveName == name && tool.enabled(ctx) {
+			return &tool
+		}
+	}
+	return nil
+}
+
+// callGateway calls a tool through the gateway's MCP endpoint
+func callGateway(ctx context.Context, name string, input map[string]interface{}, depth int, trace TraceContext, meta map[string]interface{}) (ToolResponse, error) {
+	if err := requireFeature(ctx, FeatureCallTools); err != nil {
+		return ToolResponse{}, fmt.Errorf("calling %s: %w", name, err)
+	}
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": input,
+	}
+	if len(meta) > 0 {
+		params["_meta"] = meta
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  params,
+	})
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("failed to encode call: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, GatewayURL, bytes.NewReader(body))
+	if err != nil {
+		return ToolResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CallDepthHeader, strconv.Itoa(depth))
+	if call := samplingCallFrom(ctx); call != "" {
+		req.Header.Set(SamplingCallHeader, call)
+	}
+	(&ToolContext{Trace: trace}).InjectTrace(req.Header)
+
+	resp, err := sendRequest(req)
+	if err != nil {
+		return ToolResponse{}, fmt.Errorf("failed to reach gateway: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ToolResponse{}, fmt.Errorf("gateway returned status %d", resp.StatusCode)
+	}
+
+	var rpc struct {
+		Result *ToolResponse `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpc); err != nil {
+		return ToolResponse{}, fmt.Errorf("invalid gateway response: %w", err)
+	}
+	if rpc.Error != nil {
+		return ToolResponse{}, fmt.Errorf("%s (code %d)", rpc.Error.Message, rpc.Error.Code)
+	}
+	if rpc.Result == nil {
+		return ToolResponse{}, errors.New("invalid gateway response: missing result")
+	}
+	return *rpc.Result, nil
+}
@@ -0,0 +1,132 @@
+package ftl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCallTool_InComponent(t *testing.T) {
+	tools := map[string]ToolDefinition{
+		"double": {
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				n, _ := input["n"].(float64)
+				return Textf("%d:%v", callStateFrom(ctx).depth, n*2)
+			},
+		},
+		"quadruple": {
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				doubled, err := CallTool(ctx, "double", input)
+				if err != nil {
+					return Errorf("%v", err)
+				}
+				return doubled
+			},
+		},
+	}
+
+	ctx := &ToolContext{
+		Context: withCallState(context.Background(), tools, 0),
+		Trace:   TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"},
+	}
+	got, err := CallTool(ctx, "quadruple", map[string]interface{}{"n": 2.0})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if got.Content[0].Text != "2:4" {
+		t.Errorf("CallTool() = %q, want the nested call at depth 2", got.Content[0].Text)
+	}
+}
+
+func TestCallTool_DepthLimit(t *testing.T) {
+	calls := 0
+	tools := map[string]ToolDefinition{
+		"recurse": {
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				calls++
+				return CallToolResponse(ctx, "recurse", input)
+			},
+		},
+	}
+
+	got, err := CallTool(withCallState(context.Background(), tools, 0), "recurse", nil)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if calls != MaxCallDepth || !got.IsError {
+		t.Errorf("expected %d calls ending in an error, got %d calls and %+v", MaxCallDepth, calls, got)
+	}
+
+	_, err = CallTool(withCallState(context.Background(), tools, MaxCallDepth), "recurse", nil)
+	if !errors.Is(err, ErrCallDepthExceeded) {
+		t.Errorf("CallTool() error = %v, want ErrCallDepthExceeded", err)
+	}
+}
+
+func TestCallTool_Gateway(t *testing.T) {
+	var gotDepth, gotTraceparent string
+	var gotParams struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		Meta      map[string]interface{} `json:"_meta"`
+	}
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotDepth = r.Header.Get(CallDepthHeader)
+		gotTraceparent = r.Header.Get(TraceparentHeader)
+		var req struct {
+			Params json.RawMessage `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.Unmarshal(req.Params, &gotParams)
+
+		if gotParams.Name == "missing__tool" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"Unknown tool"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"sunny"}],"_meta":{"station":"OSL"}}}`))
+	}))
+	defer gateway.Close()
+
+	gatewayURL := GatewayURL
+	GatewayURL = gateway.URL
+	defer func() { GatewayURL = gatewayURL }()
+
+	ctx := &ToolContext{
+		Context: withCallState(context.Background(), nil, 2),
+		Trace:   TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+		Meta:    map[string]interface{}{"progressToken": "p1"},
+	}
+	got, err := CallTool(ctx, "weather__forecast", map[string]interface{}{"city": "Oslo"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if got.Content[0].Text != "sunny" || got.Meta["station"] != "OSL" {
+		t.Errorf("CallTool() = %+v", got)
+	}
+	if gotParams.Name != "weather__forecast" || gotParams.Arguments["city"] != "Oslo" || gotParams.Meta["progressToken"] != "p1" {
+		t.Errorf("gateway received %+v", gotParams)
+	}
+	if gotDepth != "3" {
+		t.Errorf("gateway received depth %q, want 3", gotDepth)
+	}
+	if gotTraceparent != testTraceparent {
+		t.Errorf("gateway received traceparent %q, want %q", gotTraceparent, testTraceparent)
+	}
+
+	if _, err := CallTool(ctx, "missing__tool", nil); err == nil || err.Error() != "Unknown tool (code -32602)" {
+		t.Errorf("CallTool() error = %v, want the gateway's error", err)
+	}
+}
+
+func TestCallDepthFromHeader(t *testing.T) {
+	for value, want := range map[string]int{"": 0, "3": 3, "-1": 0, "x": 0} {
+		header := http.Header{}
+		header.Set(CallDepthHeader, value)
+		if got := callDepthFromHeader(header); got != want {
+			t.Errorf("callDepthFromHeader(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
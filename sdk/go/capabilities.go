@@ -0,0 +1,113 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GatewayFeaturesHeader lists the features of the gateway a tool call came
+// through, comma separated. Gateways that predate it do not send it.
+const GatewayFeaturesHeader = "X-FTL-Gateway-Features"
+
+// Features a gateway can list in GatewayFeaturesHeader
+const (
+	FeatureCallTools   = "call_tools"
+	FeatureSampling    = "sampling"
+	FeatureElicitation = "elicitation"
+	FeatureStreaming   = "streaming"
+	FeatureBatching    = "batching"
+)
+
+// ErrFeatureUnsupported is returned when the gateway the tool was called
+// through does not support what was asked of it
+var ErrFeatureUnsupported = errors.New("not supported by the gateway")
+
+// GatewayCapabilities is what the gateway a tool was called through, and
+// the client that called it, can do for the tool
+type GatewayCapabilities struct {
+	// Known is false when the gateway did not list its features, because it
+	// predates GatewayFeaturesHeader or the tool was not called through a
+	// gateway. The SDK then tries every feature and reports failures when
+	// they happen.
+	Known bool
+
+	// CallTools is whether CallTool can reach tools of other components
+	CallTools bool
+
+	// Streaming is whether the client accepted a streamed response to the
+	// call
+	Streaming bool
+
+	// Sampling and Elicitation are whether RequestSampling and Elicit can
+	// ask the client, which requires a streamed call
+	Sampling    bool
+	Elicitation bool
+
+	// Batching is whether the gateway accepts JSON-RPC batches
+	Batching bool
+}
+
+type gatewayFeaturesKey struct{}
+
+// withGatewayFeatures records the features listed by the gateway, when it
+// listed any
+func withGatewayFeatures(ctx context.Context, header http.Header) context.Context {
+	values := header.Values(GatewayFeaturesHeader)
+	if len(values) == 0 {
+		return ctx
+	}
+	features := make(map[string]bool)
+	for _, value := range values {
+		for _, feature := range strings.Split(value, ",") {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				features[feature] = true
+			}
+		}
+	}
+	return context.WithValue(ctx, gatewayFeaturesKey{}, features)
+}
+
+// Capabilities reports what the gateway a tool was called through supports,
+// so tools can adapt instead of failing when a feature is missing:
+//
+//	if !ftl.Capabilities(ctx).Sampling {
+//		return ftl.Text(firstParagraph(text))
+//	}
+//	summary, err := ftl.RequestSampling(ctx, "Summarize:\n"+text, nil)
+//
+// Pass the handler's ToolContext or a context derived from it. With an
+// older gateway, Known is false and features are assumed available whenever
+// the call allows them.
+func Capabilities(ctx context.Context) GatewayCapabilities {
+	streamed := samplingCallFrom(ctx) != ""
+	features, ok := ctx.Value(gatewayFeaturesKey{}).(map[string]bool)
+	if !ok {
+		return GatewayCapabilities{
+			CallTools:   true,
+			Streaming:   streamed,
+			Sampling:    streamed,
+			Elicitation: streamed,
+		}
+	}
+	return GatewayCapabilities{
+		Known:       true,
+		CallTools:   features[FeatureCallTools],
+		Streaming:   streamed && features[FeatureStreaming],
+		Sampling:    streamed && features[FeatureSampling],
+		Elicitation: streamed && features[FeatureElicitation],
+		Batching:    features[FeatureBatching],
+	}
+}
+
+// requireFeature fails with ErrFeatureUnsupported when the gateway listed
+// its features without feature
+func requireFeature(ctx context.Context, feature string) error {
+	features, ok := ctx.Value(gatewayFeaturesKey{}).(map[string]bool)
+	if ok && !features[feature] {
+		return fmt.Errorf("%w: %s", ErrFeatureUnsupported, feature)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	features := func(value string) http.Header {
+		header := http.Header{}
+		header.Set(GatewayFeaturesHeader, value)
+		return header
+	}
+	tests := []struct {
+		name     string
+		header   http.Header
+		streamed bool
+		want     GatewayCapabilities
+	}{
+		{
+			name:     "older gateway",
+			header:   http.Header{},
+			streamed: true,
+			want:     GatewayCapabilities{CallTools: true, Streaming: true, Sampling: true, Elicitation: true},
+		},
+		{
+			name:   "older gateway, call not streamed",
+			header: http.Header{},
+			want:   GatewayCapabilities{CallTools: true},
+		},
+		{
+			name:     "all features",
+			header:   features("call_tools, sampling, elicitation, streaming, batching"),
+			streamed: true,
+			want:     GatewayCapabilities{Known: true, CallTools: true, Streaming: true, Sampling: true, Elicitation: true, Batching: true},
+		},
+		{
+			name:   "call not streamed",
+			header: features("call_tools,sampling,elicitation,streaming"),
+			want:   GatewayCapabilities{Known: true, CallTools: true},
+		},
+		{
+			name:     "no features",
+			header:   features(""),
+			streamed: true,
+			want:     GatewayCapabilities{Known: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := withGatewayFeatures(context.Background(), tt.header)
+			if tt.streamed {
+				ctx = withSamplingCall(ctx, "0a1b2c")
+			}
+			if got := Capabilities(ctx); got != tt.want {
+				t.Errorf("Capabilities() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapabilities_FromRequest(t *testing.T) {
+	var got GatewayCapabilities
+	handler := Handler(map[string]ToolDefinition{
+		"probe": {
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				got = Capabilities(ctx)
+				return Text("ok")
+			},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/probe", strings.NewReader("{}"))
+	req.Header.Set(GatewayFeaturesHeader, "call_tools,sampling,streaming")
+	req.Header.Set(SamplingCallHeader, "0a1b2c")
+	handler(httptest.NewRecorder(), req)
+
+	want := GatewayCapabilities{Known: true, CallTools: true, Streaming: true, Sampling: true}
+	if got != want {
+		t.Errorf("Capabilities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnsupportedFeatures_FailWithoutReachingGateway(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("gateway received %s", r.URL.Path)
+	}))
+	defer gateway.Close()
+
+	gatewayURL, samplingURL := GatewayURL, SamplingURL
+	GatewayURL, SamplingURL = gateway.URL, gateway.URL
+	defer func() { GatewayURL, SamplingURL = gatewayURL, samplingURL }()
+
+	header := http.Header{}
+	header.Set(GatewayFeaturesHeader, "streaming")
+	ctx := withGatewayFeatures(withSamplingCall(context.Background(), "0a1b2c"), header)
+
+	if _, err := CallTool(ctx, "writer__summarize", nil); !errors.Is(err, ErrFeatureUnsupported) {
+		t.Errorf("CallTool() error = %v, want ErrFeatureUnsupported", err)
+	}
+	_, err := RequestSampling(ctx, "Summarize", nil)
+	if !errors.Is(err, ErrSamplingUnavailable) || !errors.Is(err, ErrFeatureUnsupported) {
+		t.Errorf("RequestSampling() error = %v, want ErrSamplingUnavailable and ErrFeatureUnsupported", err)
+	}
+	_, err = Elicit[map[string]interface{}](ctx, "Proceed?", map[string]interface{}{"type": "object"})
+	if !errors.Is(err, ErrElicitationUnavailable) || !errors.Is(err, ErrFeatureUnsupported) {
+		t.Errorf("Elicit() error = %v, want ErrElicitationUnavailable and ErrFeatureUnsupported", err)
+	}
+}
@@ -0,0 +1,67 @@
+package ftl
+
+import (
+	"context"
+	"strconv"
+)
+
+// Condition decides per request whether tools are exposed, so a component
+// can offer some tools only in certain deployments:
+//
+//	readTools := map[string]ftl.ToolDefinition{"get": {Handler: get}}
+//	writeTools := ftl.ToolsIf(ftl.Not(ftl.VariableFlag("read_only", true)),
+//		map[string]ftl.ToolDefinition{"put": {Handler: put}})
+//
+//	ftl.CreateTools(ftl.MergeTools(readTools, writeTools))
+//
+// Tools whose condition is false are left out of the tool list and calls
+// to them fail as if they did not exist.
+type Condition func(ctx context.Context) bool
+
+// ToolsIf gates every tool in tools behind cond, in addition to any
+// condition a tool already has
+func ToolsIf(cond Condition, tools map[string]ToolDefinition) map[string]ToolDefinition {
+	gated := make(map[string]ToolDefinition, len(tools))
+	for key, tool := range tools {
+		tool.Enabled = All(tool.Enabled, cond)
+		gated[key] = tool
+	}
+	return gated
+}
+
+// VariableFlag is true while the Spin variable (or the matching environment
+// variable) holds a true value such as "true" or "1". An unset, empty or
+// unparsable variable yields defaultValue.
+func VariableFlag(variable string, defaultValue bool) Condition {
+	return func(ctx context.Context) bool {
+		value, err := WithEnvFallback(spinVariables)(variable)
+		if err != nil || value == "" {
+			return defaultValue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			secureLogf("Ignoring invalid value for %s: %q", variable, value)
+			return defaultValue
+		}
+		return enabled
+	}
+}
+
+// Not negates a condition
+func Not(cond Condition) Condition {
+	return func(ctx context.Context) bool {
+		return !cond(ctx)
+	}
+}
+
+// All is true when every non-nil condition is true
+func All(conditions ...Condition) Condition {
+	return func(ctx context.Context) bool {
+		for _, cond := range conditions {
+			if cond != nil && !cond(ctx) {
+				return false
+			}
+		}
+		return true
+	}
+}
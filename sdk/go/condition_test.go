@@ -0,0 +1,64 @@
+package ftl
+
+import (
+	"context"
+	"testing"
+)
+
+// stubVariables replaces the component's Spin variables for the test
+func stubVariables(t *testing.T) map[string]string {
+	t.Helper()
+	values := map[string]string{}
+	old := spinVariables
+	spinVariables = mapGetter(values)
+	t.Cleanup(func() { spinVariables = old })
+	return values
+}
+
+func TestToolsIf(t *testing.T) {
+	values := stubVariables(t)
+	ctx := context.Background()
+
+	own := false
+	tools := ToolsIf(Not(VariableFlag("read_only", true)), map[string]ToolDefinition{
+		"put":    {Handler: reverseHandler},
+		"delete": {Handler: reverseHandler, Enabled: func(context.Context) bool { return own }},
+	})
+	put, del := tools["put"], tools["delete"]
+
+	if put.enabled(ctx) || del.enabled(ctx) {
+		t.Error("write tools should be hidden while read_only defaults to true")
+	}
+
+	values["read_only"] = "false"
+	if !put.enabled(ctx) {
+		t.Error("put should be enabled when read_only is false")
+	}
+	if del.enabled(ctx) {
+		t.Error("delete should keep its own condition")
+	}
+	own = true
+	if !del.enabled(ctx) {
+		t.Error("delete should be enabled when both conditions hold")
+	}
+}
+
+func TestVariableFlag(t *testing.T) {
+	values := stubVariables(t)
+	ctx := context.Background()
+
+	t.Setenv("FROM_ENV", "true")
+	if !VariableFlag("from_env", false)(ctx) {
+		t.Error("VariableFlag should fall back to the environment")
+	}
+
+	values["bad"] = "maybe"
+	if !VariableFlag("bad", true)(ctx) {
+		t.Error("VariableFlag should use the default for invalid values")
+	}
+
+	values["off"] = "0"
+	if VariableFlag("off", true)(ctx) {
+		t.Error("VariableFlag should be false for 0")
+	}
+}
@@ -0,0 +1,304 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Config marks a struct whose fields are loaded from Spin variables with
+// LoadConfig. Each field names its variable with an ftlvar tag:
+//
+//	type Config struct {
+//		ftl.Config
+//		APIKey     string        `ftlvar:"api_key,required,secret"`
+//		BaseURL    string        `ftlvar:",default=https://api.example.com"`
+//		MaxRetries int           `ftlvar:",default=3"`
+//		Timeout    time.Duration `ftlvar:",default=10s"`
+//		Debug      bool          `ftlvar:"debug"`
+//	}
+//
+// An empty name is derived from the field name (BaseURL becomes base_url)
+// and "-" skips the field. Cross-field constraints are declared with
+// required_with=NAME, which requires the variable when variable NAME is
+// set, and oneof=GROUP, which requires exactly one variable of GROUP:
+//
+//	JWTIssuer   string `ftlvar:"jwt_issuer"`
+//	JWTAudience string `ftlvar:"jwt_audience,required_with=jwt_issuer"`
+//	ModelURL    string `ftlvar:"model_url,oneof=model"`
+//	ModelPath   string `ftlvar:"model_path,oneof=model"`
+//
+// Fields may be strings, bools, integers, floats, time.Duration or
+// []string (comma separated). Run 'ftl generate vars' to add the declared
+// variables to the component in ftl.yaml.
+type Config struct{}
+
+// ConfigValidator is implemented by config structs that check their values
+// after loading, e.g. that a port is in range
+type ConfigValidator interface {
+	Validate() error
+}
+
+// VariableGetter looks up a variable by name
+type VariableGetter func(name string) (string, error)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// spinVariables reads the component's Spin variables. It is replaced when
+// built for Spin, so tests and other hosts only see the environment.
+var spinVariables VariableGetter = func(name string) (string, error) {
+	return "", fmt.Errorf("variable %s: Spin variables are not available", name)
+}
+
+// LoadConfig loads a T from the component's Spin variables, falling back
+// to environment variables for values Spin does not provide. See Config.
+//
+//	cfg, err := ftl.LoadConfig[Config](ctx)
+func LoadConfig[T any](ctx context.Context) (T, error) {
+	return LoadConfigFrom[T](ctx, WithEnvFallback(spinVariables))
+}
+
+// LoadConfigFrom loads a T from the variables returned by get. Empty values
+// fall back to the tag's default. All missing required variables and
+// invalid values are reported together. If T implements ConfigValidator,
+// Validate is called on the loaded value.
+func LoadConfigFrom[T any](ctx context.Context, get VariableGetter) (T, error) {
+	var cfg T
+	if err := ctx.Err(); err != nil {
+		return cfg, err
+	}
+
+	v := reflect.ValueOf(&cfg).Elem()
+	if v.Kind() != reflect.Struct {
+		return cfg, fmt.Errorf("config type %T must be a struct", cfg)
+	}
+	t := v.Type()
+
+	var errs []error
+	var specs []varSpec
+	set := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("ftlvar")
+		if !ok {
+			continue
+		}
+		spec, err := parseVarTag(tag, field.Name)
+		if err != nil {
+			return cfg, err
+		}
+		name := spec.name
+		if name == "" {
+			continue
+		}
+		if !field.IsExported() {
+			return cfg, fmt.Errorf("field %s must be exported to hold variable %s", field.Name, name)
+		}
+		specs = append(specs, spec)
+
+		value, err := get(name)
+		if err != nil && spec.required {
+			errs = append(errs, fmt.Errorf("variable %s: %w", name, err))
+			continue
+		}
+		if value == "" {
+			value = spec.def
+		}
+		if value == "" {
+			if spec.required {
+				errs = append(errs, fmt.Errorf("variable %s is required", name))
+			}
+			continue
+		}
+		set[name] = true
+		if err := setField(v.Field(i), value); err != nil {
+			errs = append(errs, fmt.Errorf("variable %s: %w", name, err))
+		}
+	}
+	errs = append(errs, checkVarRules(specs, set)...)
+	if len(errs) > 0 {
+		return cfg, errors.Join(errs...)
+	}
+
+	if validator, ok := any(&cfg).(ConfigValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return cfg, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// WithEnvFallback returns a getter that falls back to the environment when
+// get has no value. The variable api_key is read from API_KEY.
+func WithEnvFallback(get VariableGetter) VariableGetter {
+	return func(name string) (string, error) {
+		value, err := get(name)
+		if err == nil && value != "" {
+			return value, nil
+		}
+		if env, ok := os.LookupEnv(strings.ToUpper(name)); ok {
+			return env, nil
+		}
+		return value, err
+	}
+}
+
+// setField parses value into a field of one of the supported kinds
+func setField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", value)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// varSpec is a field's parsed ftlvar tag
+type varSpec struct {
+	// name is empty for skipped fields
+	name         string
+	required     bool
+	def          string
+	requiredWith string
+	oneOf        string
+}
+
+// parseVarTag parses a field's ftlvar tag
+func parseVarTag(tag, fieldName string) (varSpec, error) {
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return varSpec{}, nil
+	}
+	if name == "" {
+		name = fieldToVariable(fieldName)
+	}
+
+	spec := varSpec{name: name}
+	for options != "" {
+		var option string
+		// A default value runs to the end of the tag so it may contain commas
+		if strings.HasPrefix(options, "default=") {
+			option, options = options, ""
+		} else {
+			option, options, _ = strings.Cut(options, ",")
+		}
+
+		switch {
+		case option == "required":
+			spec.required = true
+		case option == "secret":
+		case strings.HasPrefix(option, "default="):
+			spec.def = strings.TrimPrefix(option, "default=")
+		case strings.HasPrefix(option, "required_with="):
+			spec.requiredWith = strings.TrimPrefix(option, "required_with=")
+		case strings.HasPrefix(option, "oneof="):
+			spec.oneOf = strings.TrimPrefix(option, "oneof=")
+		default:
+			return varSpec{}, fmt.Errorf("unknown ftlvar option %q on field %s", option, fieldName)
+		}
+	}
+	return spec, nil
+}
+
+// checkVarRules reports violations of the required_with and oneof
+// constraints given the variables that have values
+func checkVarRules(specs []varSpec, set map[string]bool) []error {
+	var errs []error
+	var groups []string
+	members := make(map[string][]string)
+	for _, spec := range specs {
+		if spec.requiredWith != "" && set[spec.requiredWith] && !set[spec.name] {
+			errs = append(errs, fmt.Errorf("variable %s is required when %s is set", spec.name, spec.requiredWith))
+		}
+		if spec.oneOf != "" {
+			if _, ok := members[spec.oneOf]; !ok {
+				groups = append(groups, spec.oneOf)
+			}
+			members[spec.oneOf] = append(members[spec.oneOf], spec.name)
+		}
+	}
+
+	for _, group := range groups {
+		count := 0
+		for _, name := range members[group] {
+			if set[name] {
+				count++
+			}
+		}
+		if count != 1 {
+			errs = append(errs, fmt.Errorf("exactly one of variables %s must be set", strings.Join(members[group], ", ")))
+		}
+	}
+	return errs
+}
+
+// fieldToVariable converts a field name to a variable name, keeping
+// acronyms together: APIKey becomes api_key
+func fieldToVariable(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
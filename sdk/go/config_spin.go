@@ -0,0 +1,9 @@
+//go:build !test
+
+package ftl
+
+import "github.com/spinframework/spin-go-sdk/variables"
+
+func init() {
+	spinVariables = variables.Get
+}
@@ -0,0 +1,172 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Config
+	APIKey     string        `ftlvar:"api_key,required,secret"`
+	BaseURL    string        `ftlvar:",default=https://api.example.com"`
+	MaxRetries int           `ftlvar:",default=3"`
+	Timeout    time.Duration `ftlvar:",default=10s"`
+	Debug      bool          `ftlvar:"debug"`
+	Ratio      float64       `ftlvar:"ratio"`
+	Regions    []string      `ftlvar:"regions"`
+	Skipped    string        `ftlvar:"-"`
+	Plain      string
+}
+
+type validatedConfig struct {
+	Port int `ftlvar:"port,default=8080"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Port < 1 || c.Port > 65535 {
+		return errors.New("port out of range")
+	}
+	return nil
+}
+
+func mapGetter(values map[string]string) VariableGetter {
+	return func(name string) (string, error) {
+		v, ok := values[name]
+		if !ok {
+			return "", errors.New("no such variable")
+		}
+		return v, nil
+	}
+}
+
+func TestLoadConfigFrom(t *testing.T) {
+	cfg, err := LoadConfigFrom[testConfig](context.Background(), mapGetter(map[string]string{
+		"api_key":     "secret",
+		"max_retries": "5",
+		"debug":       "true",
+		"ratio":       "0.5",
+		"regions":     "us-east-1, eu-west-1",
+	}))
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() error = %v", err)
+	}
+	if cfg.APIKey != "secret" {
+		t.Errorf("APIKey = %q, want %q", cfg.APIKey, "secret")
+	}
+	if cfg.BaseURL != "https://api.example.com" {
+		t.Errorf("BaseURL = %q, want the default", cfg.BaseURL)
+	}
+	if cfg.MaxRetries != 5 {
+		t.Errorf("MaxRetries = %d, want 5", cfg.MaxRetries)
+	}
+	if cfg.Timeout != 10*time.Second {
+		t.Errorf("Timeout = %v, want 10s", cfg.Timeout)
+	}
+	if !cfg.Debug {
+		t.Error("Debug = false, want true")
+	}
+	if cfg.Ratio != 0.5 {
+		t.Errorf("Ratio = %v, want 0.5", cfg.Ratio)
+	}
+	if len(cfg.Regions) != 2 || cfg.Regions[1] != "eu-west-1" {
+		t.Errorf("Regions = %v, want [us-east-1 eu-west-1]", cfg.Regions)
+	}
+}
+
+func TestLoadConfigFrom_Errors(t *testing.T) {
+	_, err := LoadConfigFrom[testConfig](context.Background(), mapGetter(map[string]string{
+		"api_key":     "",
+		"max_retries": "many",
+		"timeout":     "soon",
+	}))
+	if err == nil {
+		t.Fatal("LoadConfigFrom() should fail")
+	}
+	for _, want := range []string{"api_key is required", `invalid integer "many"`, `invalid duration "soon"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestLoadConfigFrom_Validate(t *testing.T) {
+	cfg, err := LoadConfigFrom[validatedConfig](context.Background(), mapGetter(nil))
+	if err != nil || cfg.Port != 8080 {
+		t.Errorf("LoadConfigFrom() = %v, %v; want default port", cfg, err)
+	}
+
+	_, err = LoadConfigFrom[validatedConfig](context.Background(), mapGetter(map[string]string{"port": "70000"}))
+	if err == nil || !strings.Contains(err.Error(), "port out of range") {
+		t.Errorf("LoadConfigFrom() error = %v, want validation error", err)
+	}
+}
+
+type crossFieldConfig struct {
+	JWTIssuer   string `ftlvar:"jwt_issuer"`
+	JWTAudience string `ftlvar:"jwt_audience,required_with=jwt_issuer"`
+	ModelURL    string `ftlvar:"model_url,oneof=model"`
+	ModelPath   string `ftlvar:"model_path,oneof=model"`
+}
+
+func TestLoadConfigFrom_CrossField(t *testing.T) {
+	cfg, err := LoadConfigFrom[crossFieldConfig](context.Background(), mapGetter(map[string]string{
+		"model_path": "/models/a.bin",
+	}))
+	if err != nil || cfg.ModelPath != "/models/a.bin" {
+		t.Errorf("LoadConfigFrom() = %v, %v; want model_path only", cfg, err)
+	}
+
+	_, err = LoadConfigFrom[crossFieldConfig](context.Background(), mapGetter(map[string]string{
+		"jwt_issuer": "https://auth.example.com",
+		"model_url":  "https://example.com/a.bin",
+		"model_path": "/models/a.bin",
+	}))
+	if err == nil {
+		t.Fatal("LoadConfigFrom() should fail")
+	}
+	for _, want := range []string{
+		"variable jwt_audience is required when jwt_issuer is set",
+		"exactly one of variables model_url, model_path must be set",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	if _, err := LoadConfigFrom[crossFieldConfig](context.Background(), mapGetter(nil)); err == nil {
+		t.Error("LoadConfigFrom() should require one of model_url and model_path")
+	}
+}
+
+func TestLoadConfigFrom_NotAStruct(t *testing.T) {
+	if _, err := LoadConfigFrom[string](context.Background(), mapGetter(nil)); err == nil {
+		t.Error("LoadConfigFrom() should reject non-struct types")
+	}
+}
+
+func TestWithEnvFallback(t *testing.T) {
+	t.Setenv("API_KEY", "from-env")
+	get := WithEnvFallback(mapGetter(map[string]string{"debug": "true"}))
+
+	if v, err := get("api_key"); err != nil || v != "from-env" {
+		t.Errorf("get(api_key) = %q, %v; want value from environment", v, err)
+	}
+	if v, err := get("debug"); err != nil || v != "true" {
+		t.Errorf("get(debug) = %q, %v; want Spin value", v, err)
+	}
+	if _, err := get("missing"); err == nil {
+		t.Error("get(missing) should fail")
+	}
+}
+
+func TestFieldToVariable(t *testing.T) {
+	tests := map[string]string{"APIKey": "api_key", "BaseURL": "base_url", "MaxRetries": "max_retries"}
+	for in, want := range tests {
+		if got := fieldToVariable(in); got != want {
+			t.Errorf("fieldToVariable(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
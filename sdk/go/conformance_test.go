@@ -0,0 +1,127 @@
+package ftl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// conformanceDir holds the schema fixtures shared by the SDKs
+const conformanceDir = "../conformance/schema"
+
+// loadFixtures decodes every JSON file of a conformance directory into a
+// slice of T, keyed by file name
+func loadFixtures[T any](t *testing.T, dir string) map[string][]T {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(conformanceDir, dir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no fixtures in %s: %v", dir, err)
+	}
+	fixtures := make(map[string][]T, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cases []T
+		if err := json.Unmarshal(data, &cases); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		fixtures[filepath.Base(file)] = cases
+	}
+	return fixtures
+}
+
+func TestConformance_Validation(t *testing.T) {
+	type validationCase struct {
+		Description string                 `json:"description"`
+		Schema      map[string]interface{} `json:"schema"`
+		Tests       []struct {
+			Description string      `json:"description"`
+			Data        interface{} `json:"data"`
+			Valid       bool        `json:"valid"`
+		} `json:"tests"`
+	}
+
+	for file, cases := range loadFixtures[validationCase](t, "validation") {
+		for _, c := range cases {
+			for _, tt := range c.Tests {
+				t.Run(file+"/"+c.Description+"/"+tt.Description, func(t *testing.T) {
+					err := Validate(c.Schema, tt.Data)
+					if tt.Valid && err != nil {
+						t.Errorf("expected valid, got %v", err)
+					}
+					if !tt.Valid && err == nil {
+						t.Errorf("expected invalid")
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestConformance_InputSchema(t *testing.T) {
+	type metadataCase struct {
+		Description string `json:"description"`
+		Tool        struct {
+			Name        string                 `json:"name"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+			Idempotency *struct {
+				KeyField string `json:"keyField"`
+			} `json:"idempotency"`
+		} `json:"tool"`
+		InputSchema map[string]interface{} `json:"inputSchema"`
+	}
+
+	for file, cases := range loadFixtures[metadataCase](t, "metadata") {
+		for _, c := range cases {
+			t.Run(file+"/"+c.Description, func(t *testing.T) {
+				tool := ToolDefinition{Name: c.Tool.Name, InputSchema: c.Tool.InputSchema}
+				if c.Tool.Idempotency != nil {
+					tool.Idempotency = &Idempotency{KeyField: c.Tool.Idempotency.KeyField}
+				}
+				metadata := tool.metadata("")
+				if metadata.Name != c.Tool.Name {
+					t.Errorf("expected name %q, got %q", c.Tool.Name, metadata.Name)
+				}
+				if !reflect.DeepEqual(metadata.InputSchema, c.InputSchema) {
+					got, _ := json.Marshal(metadata.InputSchema)
+					want, _ := json.Marshal(c.InputSchema)
+					t.Errorf("expected input schema %s, got %s", want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestToolDefinition_ValidateInput(t *testing.T) {
+	called := false
+	tool := ToolDefinition{
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+			"required":   []interface{}{"city"},
+		},
+		Handler: func(input map[string]interface{}) ToolResponse {
+			called = true
+			return Text("ok")
+		},
+		ValidateInput: true,
+	}
+	ctx := &ToolContext{ToolName: "get_forecast"}
+
+	response := tool.call(ctx, map[string]interface{}{"city": 1})
+	if !response.IsError || called {
+		t.Fatalf("expected invalid arguments to be rejected, got %+v", response)
+	}
+	if want := `Invalid arguments for tool 'get_forecast': /city: expected string, got integer`; response.Content[0].Text != want {
+		t.Errorf("expected %q, got %q", want, response.Content[0].Text)
+	}
+
+	response = tool.call(ctx, map[string]interface{}{"city": "Paris"})
+	if response.IsError || !called {
+		t.Errorf("expected valid arguments to reach the handler, got %+v", response)
+	}
+}
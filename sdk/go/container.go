@@ -0,0 +1,176 @@
+package ftl
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Container holds the services a component's handlers depend on, such as
+// HTTP clients, key-value stores or config. Each service is built by its
+// constructor the first time it is resolved and then reused, so it is
+// constructed once per component instance.
+//
+// Handlers receive their services through Inject instead of package-level
+// variables, so tests can call them with fakes:
+//
+//	type deps struct {
+//		Client *http.Client
+//		Config Config
+//	}
+//
+//	func init() {
+//		ftl.Provide(func(c *ftl.Container) (*http.Client, error) {
+//			return &http.Client{Timeout: 10 * time.Second}, nil
+//		})
+//		ftl.Provide(func(c *ftl.Container) (Config, error) {
+//			return ftl.LoadConfig[Config](context.Background())
+//		})
+//		ftl.Provide(func(c *ftl.Container) (deps, error) {
+//			client, err := ftl.ResolveFrom[*http.Client](c)
+//			if err != nil {
+//				return deps{}, err
+//			}
+//			cfg, err := ftl.ResolveFrom[Config](c)
+//			return deps{Client: client, Config: cfg}, err
+//		})
+//
+//		ftl.CreateTools(map[string]ftl.ToolDefinition{
+//			"fetch": {Handler: ftl.Inject(fetch)},
+//		})
+//	}
+//
+//	func fetch(d deps, input map[string]interface{}) ftl.ToolResponse { ... }
+type Container struct {
+	mu       sync.Mutex
+	services map[interface{}]*service
+
+	// root and chain are set on the views of the container passed to
+	// constructors: chain holds the services being built by that resolution,
+	// so cycles are told apart from concurrent resolves of the same service
+	root  *Container
+	chain []*service
+}
+
+type service struct {
+	build func(c *Container) (interface{}, error)
+
+	// mu is held while the service is built, so concurrent resolves wait
+	// for the constructor instead of running it again
+	mu    sync.Mutex
+	value interface{}
+	built bool
+}
+
+// serviceKey identifies a service by its type without reflection on the
+// hot path
+type serviceKey[T any] struct{}
+
+// NewContainer creates an empty container
+func NewContainer() *Container {
+	return &Container{services: make(map[interface{}]*service)}
+}
+
+var defaultContainer = NewContainer()
+
+// Provide registers the constructor of a T in the component's default
+// container. Providing the same type again replaces the constructor and
+// discards any instance already built.
+func Provide[T any](constructor func(c *Container) (T, error)) {
+	ProvideTo(defaultContainer, constructor)
+}
+
+// Resolve returns the T from the component's default container, building
+// it on first use
+func Resolve[T any]() (T, error) {
+	return ResolveFrom[T](defaultContainer)
+}
+
+// Inject wraps a handler that depends on a D resolved from the component's
+// default container
+func Inject[D any](handler func(deps D, input map[string]interface{}) ToolResponse) ToolHandler {
+	return InjectFrom(defaultContainer, handler)
+}
+
+// ProvideTo registers the constructor of a T in c
+func ProvideTo[T any](c *Container, constructor func(c *Container) (T, error)) {
+	c = c.registry()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services[serviceKey[T]{}] = &service{
+		build: func(c *Container) (interface{}, error) {
+			return constructor(c)
+		},
+	}
+}
+
+// ProvideValue registers an already built T in c
+func ProvideValue[T any](c *Container, value T) {
+	c = c.registry()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services[serviceKey[T]{}] = &service{value: value, built: true}
+}
+
+// ResolveFrom returns the T from c, building it and its dependencies on
+// first use. Concurrent calls wait for a single construction. A failed
+// construction is retried on the next call.
+func ResolveFrom[T any](c *Container) (T, error) {
+	var zero T
+
+	root := c.registry()
+	root.mu.Lock()
+	svc, ok := root.services[serviceKey[T]{}]
+	root.mu.Unlock()
+	if !ok {
+		return zero, fmt.Errorf("no provider for %s", typeName[T]())
+	}
+	for _, building := range c.chain {
+		if building == svc {
+			return zero, fmt.Errorf("dependency cycle while building %s", typeName[T]())
+		}
+	}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.built {
+		return svc.value.(T), nil
+	}
+
+	// Constructors resolve their own dependencies through a view recording
+	// this resolution's chain
+	chain := append(c.chain[:len(c.chain):len(c.chain)], svc)
+	value, err := svc.build(&Container{root: root, chain: chain})
+	if err != nil {
+		return zero, fmt.Errorf("failed to build %s: %w", typeName[T](), err)
+	}
+	svc.value = value
+	svc.built = true
+	return value.(T), nil
+}
+
+// InjectFrom wraps a handler that depends on a D resolved from c. If D
+// cannot be built, the tool returns an error response.
+func InjectFrom[D any](c *Container, handler func(deps D, input map[string]interface{}) ToolResponse) ToolHandler {
+	return func(input map[string]interface{}) ToolResponse {
+		deps, err := ResolveFrom[D](c)
+		if err != nil {
+			secureLogf("Failed to resolve handler dependencies: %v", err)
+			return Error(err.Error())
+		}
+		return handler(deps, input)
+	}
+}
+
+// registry returns the container holding the services, for views passed to
+// constructors
+func (c *Container) registry() *Container {
+	if c.root != nil {
+		return c.root
+	}
+	return c
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
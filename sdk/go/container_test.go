@@ -0,0 +1,158 @@
+package ftl
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testStore struct {
+	data map[string]string
+}
+
+type testDeps struct {
+	Store *testStore
+	Name  string
+}
+
+func TestResolveFrom(t *testing.T) {
+	c := NewContainer()
+	builds := 0
+	ProvideTo(c, func(c *Container) (*testStore, error) {
+		builds++
+		return &testStore{data: map[string]string{"k": "v"}}, nil
+	})
+	ProvideValue(c, "widgets")
+	ProvideTo(c, func(c *Container) (testDeps, error) {
+		store, err := ResolveFrom[*testStore](c)
+		if err != nil {
+			return testDeps{}, err
+		}
+		name, err := ResolveFrom[string](c)
+		return testDeps{Store: store, Name: name}, err
+	})
+
+	deps, err := ResolveFrom[testDeps](c)
+	if err != nil {
+		t.Fatalf("ResolveFrom() error = %v", err)
+	}
+	if deps.Name != "widgets" || deps.Store.data["k"] != "v" {
+		t.Errorf("ResolveFrom() = %+v, want resolved dependencies", deps)
+	}
+
+	store, err := ResolveFrom[*testStore](c)
+	if err != nil {
+		t.Fatalf("ResolveFrom() error = %v", err)
+	}
+	if store != deps.Store {
+		t.Error("ResolveFrom() should return the same instance")
+	}
+	if builds != 1 {
+		t.Errorf("constructor called %d times, want 1", builds)
+	}
+}
+
+func TestResolveFrom_Errors(t *testing.T) {
+	c := NewContainer()
+
+	if _, err := ResolveFrom[*testStore](c); err == nil || !strings.Contains(err.Error(), "no provider for *ftl.testStore") {
+		t.Errorf("ResolveFrom() error = %v, want missing provider", err)
+	}
+
+	ProvideTo(c, func(c *Container) (int, error) { return ResolveFrom[int](c) })
+	if _, err := ResolveFrom[int](c); err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("ResolveFrom() error = %v, want cycle error", err)
+	}
+
+	attempts := 0
+	ProvideTo(c, func(c *Container) (string, error) {
+		attempts++
+		if attempts == 1 {
+			return "", errors.New("store unavailable")
+		}
+		return "ok", nil
+	})
+	if _, err := ResolveFrom[string](c); err == nil || !strings.Contains(err.Error(), "store unavailable") {
+		t.Errorf("ResolveFrom() error = %v, want constructor error", err)
+	}
+	if v, err := ResolveFrom[string](c); err != nil || v != "ok" {
+		t.Errorf("ResolveFrom() = %q, %v; want retry to succeed", v, err)
+	}
+}
+
+func TestResolveFrom_Concurrent(t *testing.T) {
+	c := NewContainer()
+	var builds atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ProvideTo(c, func(c *Container) (*testStore, error) {
+		if builds.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return &testStore{}, nil
+	})
+	ProvideTo(c, func(c *Container) (testDeps, error) {
+		store, err := ResolveFrom[*testStore](c)
+		return testDeps{Store: store}, err
+	})
+
+	const callers = 8
+	stores := make([]*testStore, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	resolve := func(i int) {
+		defer wg.Done()
+		// Half the callers reach the store through another service
+		if i%2 == 0 {
+			stores[i], errs[i] = ResolveFrom[*testStore](c)
+			return
+		}
+		var deps testDeps
+		deps, errs[i] = ResolveFrom[testDeps](c)
+		stores[i] = deps.Store
+	}
+
+	// The other callers resolve while the first is still constructing
+	wg.Add(callers)
+	go resolve(0)
+	<-started
+	for i := 1; i < callers; i++ {
+		go resolve(i)
+	}
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("ResolveFrom() error = %v", errs[i])
+		}
+		if stores[i] != stores[0] {
+			t.Error("ResolveFrom() should return the same instance to every caller")
+		}
+	}
+	if n := builds.Load(); n != 1 {
+		t.Errorf("constructor called %d times, want 1", n)
+	}
+}
+
+func TestInjectFrom(t *testing.T) {
+	c := NewContainer()
+	handler := func(d testDeps, input map[string]interface{}) ToolResponse {
+		return Textf("%s: %v", d.Name, input["id"])
+	}
+
+	resp := InjectFrom(c, handler)(map[string]interface{}{"id": 1})
+	if !resp.IsError {
+		t.Error("handler without a provider should return an error response")
+	}
+
+	ProvideValue(c, testDeps{Name: "fake"})
+	resp = InjectFrom(c, handler)(map[string]interface{}{"id": 1})
+	if resp.IsError || resp.Content[0].Text != "fake: 1" {
+		t.Errorf("handler response = %+v, want injected deps", resp)
+	}
+}
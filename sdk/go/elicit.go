@@ -0,0 +1,78 @@
+package ftl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrElicitationUnavailable is returned by Elicit when the client cannot be
+// asked: it did not stream the call, does not support elicitation or did
+// not answer in time
+var ErrElicitationUnavailable = errors.New("elicitation unavailable")
+
+// ErrElicitationDeclined is returned by Elicit when the user declined or
+// cancelled the request
+var ErrElicitationDeclined = errors.New("elicitation declined")
+
+// Elicit asks the user for input with MCP elicitation while the tool runs,
+// so it can ask a follow-up question instead of failing on missing input:
+//
+//	"book_flight": {
+//		ContextHandler: func(ctx *ftl.ToolContext, input map[string]interface{}) ftl.ToolResponse {
+//			type dates struct {
+//				Departure string `json:"departure"`
+//			}
+//			answer, err := ftl.Elicit[dates](ctx, "When do you want to leave?", map[string]interface{}{
+//				"type":       "object",
+//				"properties": map[string]interface{}{"departure": map[string]interface{}{"type": "string", "format": "date"}},
+//				"required":   []interface{}{"departure"},
+//			})
+//			if err != nil {
+//				return ftl.Errorf("No departure date: %v", err)
+//			}
+//			return ftl.Textf("Booked for %s", answer.Departure)
+//		},
+//	},
+//
+// schema is the JSON Schema of the input: an object of string, number,
+// integer or boolean properties. The user's answer is checked against it
+// with Validate and decoded into T, which may be a struct or a
+// map[string]interface{}.
+//
+// The gateway relays the request to the client like RequestSampling.
+// Errors wrap ErrElicitationDeclined when the user declines or cancels, and
+// ErrElicitationUnavailable when the client cannot be asked.
+func Elicit[T any](ctx context.Context, message string, schema map[string]interface{}) (T, error) {
+	var answer T
+	params := map[string]interface{}{
+		"message":         message,
+		"requestedSchema": schema,
+	}
+	var result struct {
+		Action  string                 `json:"action"`
+		Content map[string]interface{} `json:"content"`
+	}
+	if err := requestClient(ctx, "elicitation/create", params, FeatureElicitation, ErrElicitationUnavailable, &result); err != nil {
+		return answer, err
+	}
+	if result.Action != "accept" {
+		return answer, fmt.Errorf("%w: the user chose %q", ErrElicitationDeclined, result.Action)
+	}
+	if result.Content == nil {
+		result.Content = make(map[string]interface{})
+	}
+	if err := Validate(schema, result.Content); err != nil {
+		return answer, fmt.Errorf("invalid input from the client: %w", err)
+	}
+
+	data, err := json.Marshal(result.Content)
+	if err != nil {
+		return answer, err
+	}
+	if err := json.Unmarshal(data, &answer); err != nil {
+		return answer, fmt.Errorf("failed to decode input: %w", err)
+	}
+	return answer, nil
+}
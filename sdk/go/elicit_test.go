@@ -0,0 +1,96 @@
+package ftl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var departureSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"departure": map[string]interface{}{"type": "string"},
+		"seats":     map[string]interface{}{"type": "integer", "minimum": 1},
+	},
+	"required": []interface{}{"departure"},
+}
+
+// elicitationGateway answers elicitation requests with result
+func elicitationGateway(t *testing.T, result string) {
+	t.Helper()
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				Message         string                 `json:"message"`
+				RequestedSchema map[string]interface{} `json:"requestedSchema"`
+			} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "elicitation/create" || req.Params.Message == "" || req.Params.RequestedSchema["type"] != "object" {
+			t.Errorf("gateway received %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	samplingURL := SamplingURL
+	SamplingURL = gateway.URL
+	t.Cleanup(func() {
+		SamplingURL = samplingURL
+		gateway.Close()
+	})
+}
+
+func TestElicit(t *testing.T) {
+	elicitationGateway(t, `{"action":"accept","content":{"departure":"2025-10-01","seats":2}}`)
+
+	type booking struct {
+		Departure string `json:"departure"`
+		Seats     int    `json:"seats"`
+	}
+	ctx := withSamplingCall(context.Background(), "0a1b2c")
+	got, err := Elicit[booking](ctx, "When do you want to leave?", departureSchema)
+	if err != nil {
+		t.Fatalf("Elicit() error = %v", err)
+	}
+	if got != (booking{Departure: "2025-10-01", Seats: 2}) {
+		t.Errorf("Elicit() = %+v", got)
+	}
+
+	answer, err := Elicit[map[string]interface{}](ctx, "When do you want to leave?", departureSchema)
+	if err != nil || answer["departure"] != "2025-10-01" {
+		t.Errorf("Elicit() = %v, %v", answer, err)
+	}
+}
+
+func TestElicit_Declined(t *testing.T) {
+	for _, action := range []string{"decline", "cancel"} {
+		t.Run(action, func(t *testing.T) {
+			elicitationGateway(t, `{"action":"`+action+`"}`)
+
+			_, err := Elicit[map[string]interface{}](withSamplingCall(context.Background(), "0a1b2c"), "Proceed?", departureSchema)
+			if !errors.Is(err, ErrElicitationDeclined) {
+				t.Errorf("Elicit() error = %v, want ErrElicitationDeclined", err)
+			}
+		})
+	}
+}
+
+func TestElicit_InvalidInput(t *testing.T) {
+	elicitationGateway(t, `{"action":"accept","content":{"seats":0}}`)
+
+	_, err := Elicit[map[string]interface{}](withSamplingCall(context.Background(), "0a1b2c"), "When?", departureSchema)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 2 {
+		t.Errorf("Elicit() error = %v, want both schema violations", err)
+	}
+}
+
+func TestElicit_Unavailable(t *testing.T) {
+	_, err := Elicit[map[string]interface{}](context.Background(), "When?", departureSchema)
+	if !errors.Is(err, ErrElicitationUnavailable) {
+		t.Errorf("Elicit() without a streamed call error = %v, want ErrElicitationUnavailable", err)
+	}
+}
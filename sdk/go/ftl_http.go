@@ -1,5 +1,3 @@
-//go:build !test
-
 package ftl
 
 import (
@@ -7,10 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-
-	spinhttp "github.com/spinframework/spin-go-sdk/http"
 )
 
+// handleHTTP registers the component's HTTP handler. It is replaced when
+// built for Spin; elsewhere CreateTools registers nothing.
+var handleHTTP = func(handler http.HandlerFunc) {}
+
 // safeWriteError writes an error response with proper headers and status
 func safeWriteError(w http.ResponseWriter, message string, statusCode int) {
 	// Ensure headers are set before writing status
@@ -59,6 +59,13 @@ func safeWriteError(w http.ResponseWriter, message string, statusCode int) {
 //
 //	func main() {}
 func CreateTools(tools map[string]ToolDefinition) {
+	handleHTTP(Handler(tools))
+}
+
+// Handler returns the HTTP handler CreateTools registers with Spin. It
+// runs outside Spin too, so tests can serve a component's tools with
+// net/http/httptest, as package ftltest does.
+func Handler(tools map[string]ToolDefinition) http.HandlerFunc {
 	// Validate tools input to prevent runtime issues
 	if tools == nil {
 		tools = make(map[string]ToolDefinition)
@@ -73,8 +80,15 @@ func CreateTools(tools map[string]ToolDefinition) {
 		}
 		toolsCopy[k] = v
 	}
+	toolsCopy = withJobTools(toolsCopy)
 
-	spinhttp.Handle(func(w http.ResponseWriter, r *http.Request) {
+	// Encode the tool listing once rather than on every request
+	toolList, err := newToolList(toolsCopy)
+	if err != nil {
+		secureLogf("Failed to precompute tools metadata: %v", err)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
 		// Defensive programming: validate request before processing
 		if r == nil {
 			safeWriteError(w, "Invalid request", http.StatusBadRequest)
@@ -91,32 +105,29 @@ func CreateTools(tools map[string]ToolDefinition) {
 			secureLogf("Available tools: %d registered", len(toolsCopy))
 		}
 
+		// Handle GET /.well-known/ftl/health - report the component is up
+		if method == "GET" && path == HealthPath {
+			w.Header().Set("Content-Type", "application/json")
+			if _, err := w.Write([]byte(`{"status":"ok"}`)); err != nil {
+				secureLogf("Failed to write health response: %v", err)
+			}
+			return
+		}
+
 		// Handle GET / - return tool metadata
 		if method == "GET" && (path == "/" || path == "") {
 			secureLogf("Handling GET request for tools metadata, found %d tools", len(toolsCopy))
+			if toolList != nil {
+				toolList.serve(w, r)
+				return
+			}
 			metadata := make([]ToolMetadata, 0, len(toolsCopy))
 			for key, tool := range toolsCopy {
-				// Use explicit name if provided, otherwise convert from key
-				toolName := tool.Name
-				if toolName == "" {
-					toolName = camelToSnake(key)
-				}
-
-				// Set default input schema if not provided
-				inputSchema := tool.InputSchema
-				if inputSchema == nil {
-					inputSchema = map[string]interface{}{"type": "object"}
+				if !tool.enabled(r.Context()) {
+					continue
 				}
 
-				metadata = append(metadata, ToolMetadata{
-					Name:         toolName,
-					Title:        tool.Title,
-					Description:  tool.Description,
-					InputSchema:  inputSchema,
-					OutputSchema: tool.OutputSchema,
-					Annotations:  tool.Annotations,
-					Meta:         tool.Meta,
-				})
+				metadata = append(metadata, tool.metadata(key))
 			}
 
 			w.Header().Set("Content-Type", "application/json")
@@ -132,17 +143,7 @@ func CreateTools(tools map[string]ToolDefinition) {
 			toolName := strings.TrimPrefix(path, "/")
 
 			// Find the tool by name
-			var toolEntry *ToolDefinition
-			for key, tool := range toolsCopy {
-				effectiveName := tool.Name
-				if effectiveName == "" {
-					effectiveName = camelToSnake(key)
-				}
-				if effectiveName == toolName {
-					toolEntry = &tool
-					break
-				}
-			}
+			toolEntry := findTool(r.Context(), toolsCopy, toolName)
 
 			if toolEntry == nil {
 				w.Header().Set("Content-Type", "application/json")
@@ -161,7 +162,17 @@ func CreateTools(tools map[string]ToolDefinition) {
 			}
 
 			// Execute handler
-			result := toolEntry.Handler(input)
+			ctx := withCallState(r.Context(), toolsCopy, callDepthFromHeader(r.Header))
+			ctx = withSamplingCall(ctx, r.Header.Get(SamplingCallHeader))
+			ctx = withGatewayFeatures(ctx, r.Header)
+			result := toolEntry.call(&ToolContext{
+				Context:   ctx,
+				ToolName:  toolName,
+				Trace:     TraceFromHeaders(r.Header),
+				RequestID: r.Header.Get(RequestIDHeader),
+				Meta:      MetaFromHeader(r.Header),
+				identity:  IdentityFromHeaders(r.Header),
+			}, input)
 
 			w.Header().Set("Content-Type", "application/json")
 			if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -183,5 +194,5 @@ func CreateTools(tools map[string]ToolDefinition) {
 		}); err != nil {
 			safeWriteError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}
 }
@@ -5,6 +5,7 @@
 package ftl
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -60,6 +61,18 @@ const (
 	ContentTypeResource = "resource"
 )
 
+// HealthPath is the endpoint the gateway probes to check that a component
+// is up. CreateTools answers it automatically.
+const HealthPath = "/.well-known/ftl/health"
+
+// TagsMetaKey is the _meta key holding a tool's tags, which the gateway
+// uses to filter tool listings
+const TagsMetaKey = "tags"
+
+// MaxConcurrencyMetaKey is the _meta key holding how many calls of a tool
+// the gateway runs at once
+const MaxConcurrencyMetaKey = "maxConcurrency"
+
 // ToolMetadata represents tool metadata returned by GET requests
 type ToolMetadata struct {
 	// The name of the tool (must be unique within the gateway)
@@ -112,6 +125,9 @@ type ToolResponse struct {
 
 	// Indicates if this response represents an error
 	IsError bool `json:"isError,omitempty"`
+
+	// Optional metadata returned to the client with the result
+	Meta map[string]interface{} `json:"_meta,omitempty"`
 }
 
 // ToolContent represents content that can be returned by tools
@@ -185,8 +201,159 @@ type ToolDefinition struct {
 	// Optional metadata for tool-specific extensions
 	Meta map[string]interface{}
 
+	// Optional tags that let clients filter tools when the gateway lists
+	// them, published in the tool's _meta
+	Tags []string
+
+	// Optional limit on how many calls of the tool the gateway runs at
+	// once, for tools wrapping rate-limited APIs or heavy computations.
+	// Excess calls fail with a retriable error.
+	MaxConcurrency int
+
 	// Handler function for tool execution
 	Handler ToolHandler
+
+	// Optional versions of the tool's input, oldest first, each with its
+	// own handler; used instead of InputSchema and the handlers when set
+	Versions []ToolVersion
+
+	// Optional handler that also receives the request context, including
+	// the trace propagated by the gateway; used instead of Handler when set
+	ContextHandler ContextToolHandler
+
+	// Optional condition checked on each request; when it is false the tool
+	// is left out of the tool list and cannot be called
+	Enabled Condition
+
+	// Optional poll function for a tool whose handler starts jobs with Async;
+	// adds the <name>_status and <name>_result tools that report on them
+	Poll PollFunc
+
+	// Optional duplicate-call suppression for tools with side effects,
+	// usually WithIdempotency()
+	Idempotency *Idempotency
+
+	// Check arguments against InputSchema before calling the handler, for
+	// components used without the gateway's argument validation
+	ValidateInput bool
+
+	// Optional limit on the bytes a call can store with TempFile and
+	// TempDir; defaults to DefaultTempQuota
+	TempQuota int64
+
+	// Optional function removing sensitive data from the tool's responses
+	// before they leave the component or are kept for idempotent calls.
+	// Fields of structured content tagged ftl:"redact" are removed first.
+	Redactor Redactor
+}
+
+// enabled reports whether the tool should be exposed for this request
+func (t *ToolDefinition) enabled(ctx context.Context) bool {
+	return t.Enabled == nil || t.Enabled(ctx)
+}
+
+// metadata describes the tool registered under key for tool listings
+func (t *ToolDefinition) metadata(key string) ToolMetadata {
+	// Use explicit name if provided, otherwise convert from key
+	name := t.Name
+	if name == "" {
+		name = camelToSnake(key)
+	}
+	return ToolMetadata{
+		Name:         name,
+		Title:        t.Title,
+		Description:  t.Description,
+		InputSchema:  t.inputSchema(),
+		OutputSchema: t.OutputSchema,
+		Annotations:  t.Annotations,
+		Meta:         t.metadataMeta(),
+	}
+}
+
+// inputSchema returns the input schema the tool is listed with
+func (t *ToolDefinition) inputSchema() map[string]interface{} {
+	schema := t.declaredSchema()
+	if t.Idempotency != nil {
+		schema = t.Idempotency.inputSchema(schema)
+	}
+	return schema
+}
+
+// declaredSchema returns the tool's own input schema
+func (t *ToolDefinition) declaredSchema() map[string]interface{} {
+	schema := t.InputSchema
+	if len(t.Versions) > 0 {
+		schema = t.versionsSchema()
+	}
+	if schema == nil {
+		schema = map[string]interface{}{"type": "object"}
+	}
+	return schema
+}
+
+// call runs the tool's handler for a request
+func (t *ToolDefinition) call(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+	if t.ValidateInput {
+		if err := Validate(t.inputSchema(), input); err != nil {
+			return Errorf("Invalid arguments for tool '%s': %v", ctx.ToolName, err)
+		}
+	}
+
+	// Give the call its own scratch space, removed when the handler returns
+	scoped := *ctx
+	var storage *tempStorage
+	scoped.Context, storage = withTempStorage(ctx.Context, t.TempQuota)
+
+	var response ToolResponse
+	if t.Idempotency != nil {
+		response = t.Idempotency.call(&scoped, t.declaredSchema(), input, func(input map[string]interface{}) ToolResponse {
+			return t.handle(&scoped, input)
+		})
+	} else {
+		response = t.handle(&scoped, input)
+	}
+	if err := storage.release(); err != nil {
+		return Errorf("Tool '%s' failed: %v", ctx.ToolName, err)
+	}
+	return response
+}
+
+// handle runs the tool's handler
+func (t *ToolDefinition) handle(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+	var response ToolResponse
+	switch {
+	case len(t.Versions) > 0:
+		response = t.callVersion(ctx, input)
+	case t.ContextHandler != nil:
+		response = t.ContextHandler(ctx, input)
+	case t.Handler != nil:
+		response = t.Handler(input)
+	default:
+		return Error(fmt.Sprintf("Tool '%s' has no handler", ctx.ToolName))
+	}
+	if t.Poll != nil {
+		response = describeJob(ctx.ToolName, response)
+	}
+	return t.redact(response)
+}
+
+// metadataMeta returns the tool's _meta, including its tags and
+// concurrency limit
+func (t *ToolDefinition) metadataMeta() map[string]interface{} {
+	if len(t.Tags) == 0 && t.MaxConcurrency <= 0 {
+		return t.Meta
+	}
+	meta := make(map[string]interface{}, len(t.Meta)+2)
+	for k, v := range t.Meta {
+		meta[k] = v
+	}
+	if len(t.Tags) > 0 {
+		meta[TagsMetaKey] = t.Tags
+	}
+	if t.MaxConcurrency > 0 {
+		meta[MaxConcurrencyMetaKey] = t.MaxConcurrency
+	}
+	return meta
 }
 
 // Text creates a simple text response
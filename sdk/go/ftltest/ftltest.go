@@ -0,0 +1,172 @@
+// Package ftltest tests a component's tools in-process, the way the
+// gateway reaches them: tools are listed and called over the component's
+// HTTP handler, with inputs and responses encoded as JSON.
+//
+// Tests using it build the SDK without Spin, with -tags test:
+//
+//	func TestTools(t *testing.T) {
+//	    c := ftltest.New(t, tools)
+//	    c.CheckGoldenSchemas("testdata/schemas.golden.json")
+//	    c.CheckExample("echo", map[string]interface{}{"message": "hello"})
+//	}
+package ftltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	ftl "github.com/fastertools/ftl/sdk/go"
+)
+
+// UpdateEnv names the environment variable that makes CheckGoldenSchemas
+// rewrite golden files instead of comparing against them, e.g.
+// FTL_UPDATE_GOLDEN=1 go test -tags test ./...
+const UpdateEnv = "FTL_UPDATE_GOLDEN"
+
+// Component serves a component's tools for a test
+type Component struct {
+	tb      testing.TB
+	handler http.Handler
+}
+
+// New serves the tools a component registers with ftl.CreateTools
+func New(tb testing.TB, tools map[string]ftl.ToolDefinition) *Component {
+	return &Component{tb: tb, handler: ftl.Handler(tools)}
+}
+
+// Tools lists the component's tools as the gateway sees them, sorted by
+// name
+func (c *Component) Tools() []ftl.ToolMetadata {
+	c.tb.Helper()
+	rec := c.do(http.MethodGet, "/", nil)
+	var tools []ftl.ToolMetadata
+	if err := json.Unmarshal(rec.Body.Bytes(), &tools); err != nil {
+		c.tb.Fatalf("failed to decode tool list: %v\n%s", err, rec.Body)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// Tool returns the listed tool with the given name, failing the test when
+// there is none
+func (c *Component) Tool(name string) ftl.ToolMetadata {
+	c.tb.Helper()
+	for _, tool := range c.Tools() {
+		if tool.Name == name {
+			return tool
+		}
+	}
+	c.tb.Fatalf("tool '%s' is not listed", name)
+	return ftl.ToolMetadata{}
+}
+
+// Call calls a tool by the name it is listed under
+func (c *Component) Call(name string, input map[string]interface{}) ftl.ToolResponse {
+	c.tb.Helper()
+	body, err := json.Marshal(input)
+	if err != nil {
+		c.tb.Fatalf("failed to encode input for '%s': %v", name, err)
+	}
+	rec := c.do(http.MethodPost, "/"+name, body)
+	var response ftl.ToolResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		c.tb.Fatalf("failed to decode response of '%s': %v\n%s", name, err, rec.Body)
+	}
+	return response
+}
+
+// CheckExample checks that input is valid against the tool's listed input
+// schema and that calling the tool with it succeeds, and returns the
+// response
+func (c *Component) CheckExample(name string, input map[string]interface{}) ftl.ToolResponse {
+	c.tb.Helper()
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+	tool := c.Tool(name)
+	if err := ftl.Validate(tool.InputSchema, roundTrip(c.tb, input)); err != nil {
+		c.tb.Errorf("example input for '%s' does not match its schema: %v", name, err)
+	}
+	response := c.Call(name, input)
+	if response.IsError {
+		c.tb.Errorf("'%s' failed for its example input: %s", name, responseText(response))
+	}
+	if tool.OutputSchema != nil && response.StructuredContent != nil {
+		if err := ftl.Validate(tool.OutputSchema, response.StructuredContent); err != nil {
+			c.tb.Errorf("structured output of '%s' does not match its schema: %v", name, err)
+		}
+	}
+	return response
+}
+
+// CheckGoldenSchemas compares the listed tools, with their input and
+// output schemas, against the golden file at path, so changes that would
+// break clients show up as test failures. A missing golden file is
+// written, as is any file when UpdateEnv is set.
+func (c *Component) CheckGoldenSchemas(path string) {
+	c.tb.Helper()
+	got, err := json.MarshalIndent(c.Tools(), "", "  ")
+	if err != nil {
+		c.tb.Fatalf("failed to encode tool list: %v", err)
+	}
+	got = append(got, '\n')
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) || os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			c.tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0600); err != nil {
+			c.tb.Fatal(err)
+		}
+		c.tb.Logf("wrote %s", path)
+		return
+	}
+	if err != nil {
+		c.tb.Fatal(err)
+	}
+	if !bytes.Equal(want, got) {
+		c.tb.Errorf("tool schemas differ from %s; if the change is intended, rerun with %s=1\nwant:\n%s\ngot:\n%s", path, UpdateEnv, want, got)
+	}
+}
+
+func (c *Component) do(method, path string, body []byte) *httptest.ResponseRecorder {
+	c.tb.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		c.tb.Fatalf("%s %s: status %d\n%s", method, path, rec.Code, rec.Body)
+	}
+	return rec
+}
+
+// roundTrip returns input as the component receives it, decoded from JSON
+func roundTrip(tb testing.TB, input map[string]interface{}) interface{} {
+	tb.Helper()
+	data, err := json.Marshal(input)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		tb.Fatal(err)
+	}
+	return decoded
+}
+
+func responseText(response ftl.ToolResponse) string {
+	for _, content := range response.Content {
+		if content.Text != "" {
+			return content.Text
+		}
+	}
+	return "no text content"
+}
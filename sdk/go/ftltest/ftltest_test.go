@@ -0,0 +1,102 @@
+package ftltest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ftl "github.com/fastertools/ftl/sdk/go"
+)
+
+var testTools = map[string]ftl.ToolDefinition{
+	"echoMessage": {
+		Description: "Echo the input message",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"message": map[string]interface{}{"type": "string"},
+				"times":   map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+			"required": []string{"message"},
+		},
+		Handler: func(input map[string]interface{}) ftl.ToolResponse {
+			message, _ := input["message"].(string)
+			if message == "" {
+				return ftl.Error("message is empty")
+			}
+			return ftl.Text("Echo: " + message)
+		},
+	},
+	"ping": {
+		Handler: func(input map[string]interface{}) ftl.ToolResponse {
+			return ftl.Text("pong")
+		},
+	},
+}
+
+// recorder records the errors a check reports instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestComponent_Tools(t *testing.T) {
+	c := New(t, testTools)
+
+	tools := c.Tools()
+	if len(tools) != 2 || tools[0].Name != "echo_message" || tools[1].Name != "ping" {
+		t.Fatalf("Tools() = %+v, want echo_message and ping", tools)
+	}
+	if got := c.Call("echo_message", map[string]interface{}{"message": "hi"}); got.Content[0].Text != "Echo: hi" {
+		t.Errorf("Call() = %+v", got)
+	}
+}
+
+func TestComponent_CheckExample(t *testing.T) {
+	c := New(t, testTools)
+	c.CheckExample("echo_message", map[string]interface{}{"message": "hi", "times": 2})
+	c.CheckExample("ping", nil)
+
+	r := &recorder{TB: t}
+	c = New(r, testTools)
+	c.CheckExample("echo_message", map[string]interface{}{"message": "", "times": 0})
+	if len(r.errors) != 2 {
+		t.Fatalf("expected a schema and a call error, got %q", r.errors)
+	}
+	if !strings.Contains(r.errors[0], "does not match its schema") || !strings.Contains(r.errors[1], "message is empty") {
+		t.Errorf("unexpected errors %q", r.errors)
+	}
+}
+
+func TestComponent_CheckGoldenSchemas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "schemas.golden.json")
+
+	// The first run writes the golden file
+	New(t, testTools).CheckGoldenSchemas(path)
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(golden), `"name": "echo_message"`) {
+		t.Errorf("golden file missing echo_message:\n%s", golden)
+	}
+	New(t, testTools).CheckGoldenSchemas(path)
+
+	changed := map[string]ftl.ToolDefinition{"ping": testTools["ping"]}
+	r := &recorder{TB: t}
+	New(r, changed).CheckGoldenSchemas(path)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], UpdateEnv) {
+		t.Errorf("expected a schema change error, got %q", r.errors)
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	New(t, changed).CheckGoldenSchemas(path)
+	t.Setenv(UpdateEnv, "")
+	New(t, changed).CheckGoldenSchemas(path)
+}
@@ -0,0 +1,150 @@
+package ftl
+
+import "strings"
+
+// Middleware wraps a tool handler, e.g. to validate input or log calls
+type Middleware func(next ToolHandler) ToolHandler
+
+// ToolGroup organizes related tools under a shared namespace. Tools in the
+// group named "text" are exposed as "text.reverse", "text.upper" and so on,
+// share the group's middleware and description prefix, and can be switched
+// off together with a variable:
+//
+//	text := ftl.NewToolGroup("text").
+//		Describe("Text utilities:").
+//		Tag("text").
+//		Use(logCalls).
+//		EnabledBy("text_tools_enabled").
+//		Handle("reverse", ftl.ToolDefinition{Handler: reverse}).
+//		Handle("upper", ftl.ToolDefinition{Handler: upper})
+//
+//	ftl.CreateTools(ftl.MergeTools(text.Tools(), math.Tools()))
+type ToolGroup struct {
+	name              string
+	descriptionPrefix string
+	middleware        []Middleware
+	conditions        []Condition
+	tags              []string
+	names             []string
+	tools             map[string]ToolDefinition
+}
+
+// NewToolGroup creates an empty tool group with the given namespace
+func NewToolGroup(name string) *ToolGroup {
+	return &ToolGroup{
+		name:  name,
+		tools: make(map[string]ToolDefinition),
+	}
+}
+
+// Describe sets a prefix added to the description of every tool in the group
+func (g *ToolGroup) Describe(prefix string) *ToolGroup {
+	g.descriptionPrefix = prefix
+	return g
+}
+
+// Use adds middleware applied to every tool in the group. The first
+// middleware added runs first.
+func (g *ToolGroup) Use(middleware ...Middleware) *ToolGroup {
+	g.middleware = append(g.middleware, middleware...)
+	return g
+}
+
+// Tag adds tags to every tool in the group, after the tool's own tags
+func (g *ToolGroup) Tag(tags ...string) *ToolGroup {
+	g.tags = append(g.tags, tags...)
+	return g
+}
+
+// EnabledBy makes the group depend on a Spin variable (or the matching
+// environment variable). The group's tools are hidden and cannot be called
+// while the variable is set to a false value such as "false" or "0"; an
+// unset or empty variable leaves them enabled.
+func (g *ToolGroup) EnabledBy(variable string) *ToolGroup {
+	return g.When(VariableFlag(variable, true))
+}
+
+// When exposes the group's tools only while every condition holds
+func (g *ToolGroup) When(conditions ...Condition) *ToolGroup {
+	g.conditions = append(g.conditions, conditions...)
+	return g
+}
+
+// Handle adds a tool to the group. The tool's name defaults to the
+// snake_case form of name and is prefixed with the group's namespace.
+func (g *ToolGroup) Handle(name string, tool ToolDefinition) *ToolGroup {
+	if name == "" {
+		return g
+	}
+	if _, exists := g.tools[name]; !exists {
+		g.names = append(g.names, name)
+	}
+	g.tools[name] = tool
+	return g
+}
+
+// Tools returns the group's tools, keyed by their namespaced names, ready
+// to pass to CreateTools
+func (g *ToolGroup) Tools() map[string]ToolDefinition {
+	tools := make(map[string]ToolDefinition, len(g.tools))
+	for _, key := range g.names {
+		tool := g.tools[key]
+
+		name := tool.Name
+		if name == "" {
+			name = camelToSnake(key)
+		}
+		tool.Name = g.name + "." + name
+
+		if g.descriptionPrefix != "" {
+			tool.Description = strings.TrimSpace(g.descriptionPrefix + " " + tool.Description)
+		}
+
+		if len(g.tags) > 0 {
+			tool.Tags = append(append([]string(nil), tool.Tags...), g.tags...)
+		}
+
+		for i := len(g.middleware) - 1; i >= 0; i-- {
+			if tool.Handler != nil {
+				tool.Handler = g.middleware[i](tool.Handler)
+			}
+			if tool.ContextHandler != nil {
+				tool.ContextHandler = wrapContextHandler(g.middleware[i], tool.ContextHandler)
+			}
+		}
+
+		if len(g.conditions) > 0 {
+			conditions := append([]Condition{tool.Enabled}, g.conditions...)
+			tool.Enabled = All(conditions...)
+		}
+
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+// wrapContextHandler applies middleware to a context-aware handler
+func wrapContextHandler(middleware Middleware, next ContextToolHandler) ContextToolHandler {
+	return func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+		return middleware(func(input map[string]interface{}) ToolResponse {
+			return next(ctx, input)
+		})(input)
+	}
+}
+
+// MergeTools combines tool sets, such as the tools of several groups, into
+// one map for CreateTools. If a key appears more than once the first
+// definition is kept.
+func MergeTools(toolSets ...map[string]ToolDefinition) map[string]ToolDefinition {
+	merged := make(map[string]ToolDefinition)
+	for _, tools := range toolSets {
+		for key, tool := range tools {
+			if _, exists := merged[key]; exists {
+				secureLogf("Skipping duplicate tool %s", key)
+				continue
+			}
+			merged[key] = tool
+		}
+	}
+	return merged
+}
@@ -0,0 +1,121 @@
+package ftl
+
+import (
+	"context"
+	"testing"
+)
+
+func reverseHandler(input map[string]interface{}) ToolResponse {
+	s, _ := input["text"].(string)
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return Text(string(runes))
+}
+
+func tagMiddleware(tag string) Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(input map[string]interface{}) ToolResponse {
+			resp := next(input)
+			resp.Content[0].Text = tag + resp.Content[0].Text
+			return resp
+		}
+	}
+}
+
+func TestToolGroup_Tools(t *testing.T) {
+	tools := NewToolGroup("text").
+		Describe("Text utilities:").
+		Use(tagMiddleware("a:"), tagMiddleware("b:")).
+		Handle("reverseText", ToolDefinition{Description: "Reverse text", Handler: reverseHandler}).
+		Handle("upper", ToolDefinition{Name: "to_upper", Handler: reverseHandler}).
+		Tools()
+
+	if len(tools) != 2 {
+		t.Fatalf("Tools() returned %d tools, want 2", len(tools))
+	}
+
+	reverse, ok := tools["text.reverse_text"]
+	if !ok {
+		t.Fatalf("Tools() = %v, want text.reverse_text", tools)
+	}
+	if reverse.Name != "text.reverse_text" {
+		t.Errorf("Name = %q, want text.reverse_text", reverse.Name)
+	}
+	if reverse.Description != "Text utilities: Reverse text" {
+		t.Errorf("Description = %q, want prefixed description", reverse.Description)
+	}
+	if got := reverse.Handler(map[string]interface{}{"text": "abc"}).Content[0].Text; got != "a:b:cba" {
+		t.Errorf("Handler() = %q, want middleware applied in order", got)
+	}
+
+	if _, ok := tools["text.to_upper"]; !ok {
+		t.Errorf("Tools() = %v, want explicit names to be namespaced", tools)
+	}
+}
+
+func TestToolGroup_EnabledBy(t *testing.T) {
+	values := stubVariables(t)
+	tool := NewToolGroup("text").
+		EnabledBy("text_enabled").
+		Handle("reverse", ToolDefinition{Handler: reverseHandler}).
+		Tools()["text.reverse"]
+	ctx := context.Background()
+
+	if !tool.enabled(ctx) {
+		t.Error("tool should be enabled while the variable is unset")
+	}
+	values["text_enabled"] = "false"
+	if tool.enabled(ctx) {
+		t.Error("tool should be disabled when the variable is false")
+	}
+	values["text_enabled"] = "1"
+	if !tool.enabled(ctx) {
+		t.Error("tool should be enabled when the variable is true")
+	}
+}
+
+func TestMergeTools(t *testing.T) {
+	first := ToolDefinition{Description: "first"}
+	merged := MergeTools(
+		map[string]ToolDefinition{"a.one": first},
+		map[string]ToolDefinition{"a.one": {Description: "second"}, "b.two": {}},
+	)
+	if len(merged) != 2 || merged["a.one"].Description != "first" {
+		t.Errorf("MergeTools() = %v, want first definition kept", merged)
+	}
+}
+
+func TestToolGroup_Tag(t *testing.T) {
+	tools := NewToolGroup("text").
+		Tag("text").
+		Handle("search", ToolDefinition{Tags: []string{"read"}, Meta: map[string]interface{}{"owner": "docs"}}).
+		Tools()
+
+	tool := tools["text.search"]
+	meta := tool.metadataMeta()
+	tags, _ := meta[TagsMetaKey].([]string)
+	if len(tags) != 2 || tags[0] != "read" || tags[1] != "text" {
+		t.Errorf("tags = %v, want [read text]", meta[TagsMetaKey])
+	}
+	if meta["owner"] != "docs" {
+		t.Errorf("meta = %v, want existing metadata kept", meta)
+	}
+}
+
+func TestToolDefinition_MaxConcurrency(t *testing.T) {
+	tool := ToolDefinition{MaxConcurrency: 2, Meta: map[string]interface{}{"owner": "render"}}
+	meta := tool.metadataMeta()
+	if meta[MaxConcurrencyMetaKey] != 2 || meta["owner"] != "render" {
+		t.Errorf("meta = %v, want maxConcurrency 2 and existing metadata kept", meta)
+	}
+	if _, ok := meta[TagsMetaKey]; ok {
+		t.Errorf("meta = %v, want no tags", meta)
+	}
+
+	unlimited := ToolDefinition{Meta: map[string]interface{}{"owner": "render"}}
+	if _, ok := unlimited.metadataMeta()[MaxConcurrencyMetaKey]; ok {
+		t.Error("tool without a limit published maxConcurrency")
+	}
+}
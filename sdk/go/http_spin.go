@@ -0,0 +1,16 @@
+//go:build !test
+
+package ftl
+
+import (
+	"net/http"
+
+	spinhttp "github.com/spinframework/spin-go-sdk/http"
+)
+
+func init() {
+	sendRequest = spinhttp.Send
+	handleHTTP = func(handler http.HandlerFunc) {
+		spinhttp.Handle(handler)
+	}
+}
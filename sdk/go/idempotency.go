@@ -0,0 +1,214 @@
+package ftl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// RequestIDHeader carries the ID of the client's request, forwarded by the
+// gateway from the client's Idempotency-Key or X-Request-Id header
+const RequestIDHeader = "X-FTL-Request-Id"
+
+// IdempotencyKeyField is the input field idempotent tools read their key
+// from by default
+const IdempotencyKeyField = "idempotency_key"
+
+// DefaultIdempotencyTTL is how long responses of idempotent tools are kept
+// by default
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyKeyPrefix namespaces cached responses in the key-value store
+const idempotencyKeyPrefix = "ftl:idempotency:"
+
+// Idempotency makes retried calls of a tool return the response of the
+// first call instead of running the handler again. Zero fields take their
+// defaults.
+type Idempotency struct {
+	// Input field holding the caller's key; defaults to IdempotencyKeyField
+	KeyField string
+
+	// How long responses are kept; defaults to DefaultIdempotencyTTL
+	TTL time.Duration
+
+	// Spin key-value store responses are kept in; defaults to "default"
+	Store string
+}
+
+// WithIdempotency suppresses duplicate calls of a tool with side effects,
+// which agents may retry after a timeout or a dropped connection:
+//
+//	"send_email": {
+//		Handler:     sendEmail,
+//		Idempotency: ftl.WithIdempotency(),
+//	}
+//
+// A call is identified by the "idempotency_key" input field or, when the
+// caller did not send one, by the request ID forwarded by the gateway.
+// Calls with neither always run. The key field is removed from the input
+// before the handler runs unless the tool's input schema declares it. Keys
+// are scoped to the caller's identity and the call's input, so a reused key
+// never returns the response of another user or of different arguments.
+//
+// Successful responses are kept in the key-value store, so the component
+// needs idempotency enabled in ftl.yaml. Error responses are not kept, so
+// failed calls can be retried, and expired responses are deleted when they
+// are next looked up.
+//
+// Duplicate calls that arrive while the first one is still running are not
+// suppressed, and the tool runs normally if the store is unavailable.
+func WithIdempotency() *Idempotency {
+	return &Idempotency{}
+}
+
+// kvStore is the part of a Spin key-value store idempotent tools use
+type kvStore interface {
+	Exists(key string) (bool, error)
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+	Delete(key string) error
+	Close()
+}
+
+// openKVStore opens a key-value store. It is replaced when built for Spin.
+var openKVStore func(name string) (kvStore, error)
+
+// idempotentResponse is a response kept in the key-value store
+type idempotentResponse struct {
+	Expires  int64        `json:"expires"`
+	Response ToolResponse `json:"response"`
+}
+
+// call runs handle unless a response for the call's key is still kept.
+// schema is the tool's own input schema, before the key field is added.
+func (i *Idempotency) call(ctx *ToolContext, schema, input map[string]interface{}, handle func(map[string]interface{}) ToolResponse) ToolResponse {
+	field := i.keyField()
+	key, _ := input[field].(string)
+	if _, ok := input[field]; ok && !declaresProperty(schema, field) {
+		trimmed := make(map[string]interface{}, len(input))
+		for k, v := range input {
+			if k != field {
+				trimmed[k] = v
+			}
+		}
+		input = trimmed
+	}
+	if key == "" {
+		key = ctx.RequestID
+	}
+	if key == "" || openKVStore == nil {
+		return handle(input)
+	}
+
+	store, err := openKVStore(i.storeName())
+	if err != nil {
+		secureLogf("Idempotency store unavailable: %v", err)
+		return handle(input)
+	}
+	defer store.Close()
+
+	storeKey := idempotencyStoreKey(ctx, key, input)
+	now := time.Now()
+	if exists, err := store.Exists(storeKey); err == nil && exists {
+		if data, err := store.Get(storeKey); err == nil {
+			var kept idempotentResponse
+			if json.Unmarshal(data, &kept) == nil && now.Unix() < kept.Expires {
+				return kept.Response
+			}
+		}
+		if err := store.Delete(storeKey); err != nil {
+			secureLogf("Failed to delete expired response of %s: %v", ctx.ToolName, err)
+		}
+	}
+
+	response := handle(input)
+	if response.IsError {
+		return response
+	}
+	data, err := json.Marshal(idempotentResponse{Expires: now.Add(i.ttl()).Unix(), Response: response})
+	if err == nil {
+		err = store.Set(storeKey, data)
+	}
+	if err != nil {
+		secureLogf("Failed to keep response of %s: %v", ctx.ToolName, err)
+	}
+	return response
+}
+
+// idempotencyStoreKey is where the response of a call is kept: a hash of
+// the caller's subject, the key and the input, so neither the key nor the
+// request ID it may come from is used as is
+func idempotencyStoreKey(ctx *ToolContext, key string, input map[string]interface{}) string {
+	subject := ""
+	if id := ctx.Identity(); id != nil {
+		subject = id.Subject
+	}
+	// Maps are encoded with sorted keys, so equal inputs hash alike
+	encoded, _ := json.Marshal(input)
+
+	hash := sha256.New()
+	for _, part := range [][]byte{[]byte(subject), []byte(key), encoded} {
+		hash.Write(part)
+		hash.Write([]byte{0})
+	}
+	return idempotencyKeyPrefix + ctx.ToolName + ":" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// inputSchema adds the key field to a tool's input schema, so callers know
+// they can send it
+func (i *Idempotency) inputSchema(schema map[string]interface{}) map[string]interface{} {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	field := i.keyField()
+	if declaresProperty(schema, field) {
+		return schema
+	}
+	withKey := make(map[string]interface{}, len(properties)+1)
+	for k, v := range properties {
+		withKey[k] = v
+	}
+	withKey[field] = map[string]interface{}{
+		"type":        "string",
+		"description": "Unique key for this call; retrying with the same key returns the first result instead of repeating the call",
+	}
+	adapted := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		adapted[k] = v
+	}
+	adapted["properties"] = withKey
+	return adapted
+}
+
+// declaresProperty reports whether an input schema lists a property
+func declaresProperty(schema map[string]interface{}, name string) bool {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, exists := properties[name]
+	return exists
+}
+
+func (i *Idempotency) keyField() string {
+	if i.KeyField == "" {
+		return IdempotencyKeyField
+	}
+	return i.KeyField
+}
+
+func (i *Idempotency) ttl() time.Duration {
+	if i.TTL <= 0 {
+		return DefaultIdempotencyTTL
+	}
+	return i.TTL
+}
+
+func (i *Idempotency) storeName() string {
+	if i.Store == "" {
+		return "default"
+	}
+	return i.Store
+}
@@ -0,0 +1,205 @@
+package ftl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memoryStore map[string][]byte
+
+func (m memoryStore) Exists(key string) (bool, error) { _, ok := m[key]; return ok, nil }
+func (m memoryStore) Get(key string) ([]byte, error)  { return m[key], nil }
+func (m memoryStore) Set(key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+func (m memoryStore) Delete(key string) error {
+	delete(m, key)
+	return nil
+}
+func (m memoryStore) Close() {}
+
+func useMemoryStore(t *testing.T) memoryStore {
+	t.Helper()
+	store := memoryStore{}
+	open := openKVStore
+	openKVStore = func(name string) (kvStore, error) { return store, nil }
+	t.Cleanup(func() { openKVStore = open })
+	return store
+}
+
+func TestIdempotency_SuppressesDuplicateCalls(t *testing.T) {
+	store := useMemoryStore(t)
+	sent := 0
+	tool := ToolDefinition{
+		Handler: func(input map[string]interface{}) ToolResponse {
+			if _, ok := input[IdempotencyKeyField]; ok {
+				t.Errorf("handler received the idempotency key")
+			}
+			if input["to"] == "" {
+				return Error("to is required")
+			}
+			sent++
+			return Textf("sent #%d to %v", sent, input["to"])
+		},
+		Idempotency: WithIdempotency(),
+	}
+	call := func(requestID string, input map[string]interface{}) string {
+		return tool.call(&ToolContext{Context: context.Background(), ToolName: "send_email", RequestID: requestID}, input).Content[0].Text
+	}
+
+	first := call("", map[string]interface{}{"to": "ada", IdempotencyKeyField: "k1"})
+	if retry := call("", map[string]interface{}{"to": "ada", IdempotencyKeyField: "k1"}); retry != first || sent != 1 {
+		t.Errorf("retry = %q after %d sends, want %q from a single send", retry, sent, first)
+	}
+	if other := call("", map[string]interface{}{"to": "ada", IdempotencyKeyField: "k2"}); other != "sent #2 to ada" {
+		t.Errorf("call with a new key = %q", other)
+	}
+
+	call("req-1", map[string]interface{}{"to": "bob"})
+	if retry := call("req-1", map[string]interface{}{"to": "bob"}); retry != "sent #3 to bob" || sent != 3 {
+		t.Errorf("retry of request = %q after %d sends", retry, sent)
+	}
+
+	call("", map[string]interface{}{"to": "eve"})
+	call("", map[string]interface{}{"to": "eve"})
+	if sent != 5 {
+		t.Errorf("calls without a key should always run, got %d sends", sent)
+	}
+
+	call("", map[string]interface{}{"to": "", IdempotencyKeyField: "k3"})
+	if len(store) != 3 {
+		t.Errorf("error responses should not be kept")
+	}
+}
+
+func TestIdempotency_Expiry(t *testing.T) {
+	store := useMemoryStore(t)
+	calls := 0
+	tool := ToolDefinition{
+		Handler:     func(input map[string]interface{}) ToolResponse { calls++; return Text("done") },
+		Idempotency: &Idempotency{KeyField: "request", TTL: time.Hour},
+	}
+	ctx := &ToolContext{Context: context.Background(), ToolName: "charge"}
+	tool.call(ctx, map[string]interface{}{"request": "r1"})
+	storeKey := idempotencyStoreKey(ctx, "r1", map[string]interface{}{})
+
+	var kept idempotentResponse
+	if err := json.Unmarshal(store[storeKey], &kept); err != nil {
+		t.Fatalf("response not kept: %v", err)
+	}
+	if ttl := time.Until(time.Unix(kept.Expires, 0)); ttl < 59*time.Minute || ttl > time.Hour {
+		t.Errorf("response kept for %v, want an hour", ttl)
+	}
+
+	kept.Expires = time.Now().Add(-time.Second).Unix()
+	store[storeKey], _ = json.Marshal(kept)
+	tool.call(ctx, map[string]interface{}{"request": "r1"})
+	if calls != 2 {
+		t.Errorf("expired response should run the tool again, got %d calls", calls)
+	}
+}
+
+func TestIdempotency_DeletesExpiredResponse(t *testing.T) {
+	store := useMemoryStore(t)
+	tool := ToolDefinition{
+		Handler:     func(input map[string]interface{}) ToolResponse { return Error("failed") },
+		Idempotency: WithIdempotency(),
+	}
+	ctx := &ToolContext{Context: context.Background(), ToolName: "charge", RequestID: "req-1"}
+	storeKey := idempotencyStoreKey(ctx, "req-1", nil)
+	store[storeKey], _ = json.Marshal(idempotentResponse{Expires: time.Now().Add(-time.Second).Unix(), Response: Text("done")})
+
+	if got := tool.call(ctx, nil); !got.IsError {
+		t.Errorf("expired response returned: %+v", got)
+	}
+	if _, ok := store[storeKey]; ok {
+		t.Errorf("expired response should be deleted")
+	}
+}
+
+func TestIdempotency_DeclaredKeyField(t *testing.T) {
+	useMemoryStore(t)
+	tool := ToolDefinition{
+		Handler: func(input map[string]interface{}) ToolResponse {
+			return Textf("order %v", input["order_id"])
+		},
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"order_id": map[string]interface{}{"type": "string"}},
+		},
+		Idempotency: &Idempotency{KeyField: "order_id"},
+	}
+	got := tool.call(&ToolContext{Context: context.Background(), ToolName: "ship"}, map[string]interface{}{"order_id": "o-1"})
+	if text := got.Content[0].Text; text != "order o-1" {
+		t.Errorf("declared key field should reach the handler, got %q", text)
+	}
+}
+
+func TestIdempotency_ScopedToCallerAndInput(t *testing.T) {
+	store := useMemoryStore(t)
+	tool := ToolDefinition{
+		ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+			return Textf("%v for %s", input["amount"], ctx.Identity().Subject)
+		},
+		Idempotency: WithIdempotency(),
+	}
+	call := func(subject, requestID string, input map[string]interface{}) string {
+		ctx := &ToolContext{Context: context.Background(), ToolName: "charge", RequestID: requestID, identity: &Identity{Subject: subject}}
+		return tool.call(ctx, input).Content[0].Text
+	}
+
+	call("ada", "", map[string]interface{}{"amount": 5, IdempotencyKeyField: "k1"})
+	if got := call("bob", "", map[string]interface{}{"amount": 5, IdempotencyKeyField: "k1"}); got != "5 for bob" {
+		t.Errorf("another user reusing a key got %q", got)
+	}
+
+	// Calls of a batch share the client's request ID
+	call("ada", "batch-1", map[string]interface{}{"amount": 5})
+	if got := call("ada", "batch-1", map[string]interface{}{"amount": 7}); got != "7 for ada" {
+		t.Errorf("call with the same request ID and other input got %q", got)
+	}
+	if got := call("ada", "batch-1", map[string]interface{}{"amount": 5}); got != "5 for ada" {
+		t.Errorf("retry got %q", got)
+	}
+
+	for key := range store {
+		if strings.Contains(key, "batch-1") || strings.Contains(key, "k1") {
+			t.Errorf("store key %q contains the raw key", key)
+		}
+	}
+	if len(store) != 4 {
+		t.Errorf("kept %d responses, want 4", len(store))
+	}
+}
+
+func TestIdempotency_StoreUnavailable(t *testing.T) {
+	open := openKVStore
+	openKVStore = func(name string) (kvStore, error) { return nil, errors.New("no such store") }
+	defer func() { openKVStore = open }()
+
+	tool := ToolDefinition{Handler: func(input map[string]interface{}) ToolResponse { return Text("ran") }, Idempotency: WithIdempotency()}
+	got := tool.call(&ToolContext{Context: context.Background(), RequestID: "req-1"}, nil)
+	if got.Content[0].Text != "ran" {
+		t.Errorf("tool should run without a store, got %+v", got)
+	}
+}
+
+func TestIdempotency_InputSchema(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"to": map[string]interface{}{"type": "string"}},
+	}
+	adapted := WithIdempotency().inputSchema(schema)
+	properties := adapted["properties"].(map[string]interface{})
+	if _, ok := properties[IdempotencyKeyField]; !ok || len(properties) != 2 {
+		t.Errorf("adapted properties = %v, want the key field added", properties)
+	}
+	if len(schema["properties"].(map[string]interface{})) != 1 {
+		t.Errorf("the tool's own schema should not change")
+	}
+}
@@ -0,0 +1,97 @@
+package ftl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Identity headers forwarded by the FTL gateway from the authorizer
+const (
+	AuthUserIDHeader   = "X-Auth-User-Id"
+	AuthClientIDHeader = "X-Auth-Client-Id"
+	AuthIssuerHeader   = "X-Auth-Issuer"
+	AuthScopesHeader   = "X-Auth-Scopes"
+	AuthClaimsHeader   = "X-Auth-Claims"
+)
+
+// Identity is the authenticated caller of a tool, as verified by the
+// app's authorizer
+type Identity struct {
+	// Subject of the caller's token, usually the user ID
+	Subject string
+
+	// ClientID of the application calling on the user's behalf
+	ClientID string
+
+	// Issuer of the caller's token
+	Issuer string
+
+	// OrgID is the caller's organization, from the org_id claim
+	OrgID string
+
+	// Scopes granted to the caller's token
+	Scopes []string
+
+	// Claims holds every claim of the caller's token
+	Claims map[string]interface{}
+}
+
+// HasScope reports whether the caller was granted a scope
+func (id *Identity) HasScope(scope string) bool {
+	for _, s := range id.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Claim returns a claim of the caller's token as a string, or "" if it is
+// missing or not a string
+func (id *Identity) Claim(name string) string {
+	value, _ := id.Claims[name].(string)
+	return value
+}
+
+// Identity returns the authenticated caller, or nil when the app has no
+// authorizer or the tool was called by another component's tool:
+//
+//	"my_orders": {
+//		ContextHandler: func(ctx *ftl.ToolContext, input map[string]interface{}) ftl.ToolResponse {
+//			caller := ctx.Identity()
+//			if caller == nil {
+//				return ftl.Error("Sign in to list your orders")
+//			}
+//			return listOrders(ctx, caller.OrgID, caller.Subject)
+//		},
+//	}
+func (c *ToolContext) Identity() *Identity {
+	return c.identity
+}
+
+// IdentityFromHeaders reads the caller's identity forwarded by the gateway,
+// returning nil when there is none
+func IdentityFromHeaders(header http.Header) *Identity {
+	subject := header.Get(AuthUserIDHeader)
+	if subject == "" {
+		return nil
+	}
+	id := &Identity{
+		Subject:  subject,
+		ClientID: header.Get(AuthClientIDHeader),
+		Issuer:   header.Get(AuthIssuerHeader),
+		Scopes:   strings.Fields(header.Get(AuthScopesHeader)),
+		Claims:   make(map[string]interface{}),
+	}
+	if encoded := header.Get(AuthClaimsHeader); encoded != "" {
+		if data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "=")); err == nil {
+			if err := json.Unmarshal(data, &id.Claims); err != nil {
+				secureLogf("Ignoring invalid %s header: %v", AuthClaimsHeader, err)
+			}
+		}
+	}
+	id.OrgID = id.Claim("org_id")
+	return id
+}
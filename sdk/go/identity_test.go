@@ -0,0 +1,61 @@
+package ftl
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+func TestIdentityFromHeaders(t *testing.T) {
+	if IdentityFromHeaders(http.Header{}) != nil {
+		t.Errorf("expected no identity without the user ID header")
+	}
+
+	header := http.Header{}
+	header.Set(AuthUserIDHeader, "user_1")
+	header.Set(AuthClientIDHeader, "client_9")
+	header.Set(AuthIssuerHeader, "https://auth.example.com")
+	header.Set(AuthScopesHeader, "read write")
+	header.Set(AuthClaimsHeader, base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user_1","org_id":"org_42","email":"ada@example.com","tier":3}`)))
+
+	id := IdentityFromHeaders(header)
+	if id == nil {
+		t.Fatal("expected an identity")
+	}
+	if id.Subject != "user_1" || id.ClientID != "client_9" || id.Issuer != "https://auth.example.com" || id.OrgID != "org_42" {
+		t.Errorf("identity = %+v", id)
+	}
+	if !id.HasScope("write") || id.HasScope("admin") {
+		t.Errorf("scopes = %v", id.Scopes)
+	}
+	if id.Claim("email") != "ada@example.com" || id.Claim("tier") != "" || id.Claims["tier"] != 3.0 {
+		t.Errorf("claims = %v", id.Claims)
+	}
+
+	header.Set(AuthClaimsHeader, "not base64 json")
+	if id := IdentityFromHeaders(header); id == nil || len(id.Claims) != 0 || id.OrgID != "" {
+		t.Errorf("invalid claims should be ignored, got %+v", id)
+	}
+}
+
+func TestCallTool_KeepsIdentity(t *testing.T) {
+	tools := map[string]ToolDefinition{
+		"whoami": {
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				if ctx.Identity() == nil {
+					return Text("anonymous")
+				}
+				return Text(ctx.Identity().Subject)
+			},
+		},
+	}
+	ctx := &ToolContext{
+		Context:  withCallState(context.Background(), tools, 0),
+		identity: &Identity{Subject: "user_1"},
+	}
+	got, err := CallTool(ctx, "whoami", nil)
+	if err != nil || got.Content[0].Text != "user_1" {
+		t.Errorf("CallTool() = %+v, %v; want the caller's identity", got, err)
+	}
+}
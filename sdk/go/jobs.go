@@ -0,0 +1,176 @@
+package ftl
+
+import (
+	"context"
+	"fmt"
+)
+
+// Companion tool suffixes added for tools that run jobs
+const (
+	JobStatusSuffix = "_status"
+	JobResultSuffix = "_result"
+)
+
+// JobState is the progress of a long-running job
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+)
+
+// JobStatus describes a long-running job, as reported by a PollFunc
+type JobStatus struct {
+	// State of the job
+	State JobState `json:"state"`
+
+	// Optional progress from 0 to 1
+	Progress float64 `json:"progress,omitempty"`
+
+	// Optional message, such as the current step or why the job failed
+	Message string `json:"message,omitempty"`
+
+	// Result of a succeeded job, returned by the <tool>_result tool
+	Result *ToolResponse `json:"-"`
+}
+
+// PollFunc reports the status of a job started by a tool's handler
+type PollFunc func(ctx context.Context, jobID string) (JobStatus, error)
+
+// AsyncJob is the structured content of a response returned by Async
+type AsyncJob struct {
+	JobID string   `json:"job_id"`
+	State JobState `json:"state"`
+}
+
+// Async responds to a tool call with a job that is still running, for work
+// that would not finish within the execution time limit. The tool must set
+// Poll; clients follow the job with the tool's companion tools:
+//
+//	"render": {
+//		Handler: func(input map[string]interface{}) ftl.ToolResponse {
+//			id := renderQueue.Submit(input)
+//			return ftl.Async(id)
+//		},
+//		Poll: func(ctx context.Context, jobID string) (ftl.JobStatus, error) {
+//			return renderQueue.Status(ctx, jobID)
+//		},
+//	}
+//
+// exposes "render", "render_status" and "render_result". The status and
+// result tools take the job ID as {"job_id": "..."}.
+func Async(jobID string) ToolResponse {
+	return WithStructured(fmt.Sprintf("Started job %s", jobID), AsyncJob{JobID: jobID, State: JobRunning})
+}
+
+// describeJob names the companion tools in a response returned by Async
+func describeJob(toolName string, response ToolResponse) ToolResponse {
+	job, ok := response.StructuredContent.(AsyncJob)
+	if !ok || len(response.Content) != 1 {
+		return response
+	}
+	response.Content[0].Text = fmt.Sprintf("Started job %s. Check its progress with %s%s and fetch the result with %s%s, passing {\"job_id\": %q}.",
+		job.JobID, toolName, JobStatusSuffix, toolName, JobResultSuffix, job.JobID)
+	return response
+}
+
+// jobInputSchema is the input of the companion tools
+var jobInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"job_id": map[string]interface{}{
+			"type":        "string",
+			"description": "The job ID returned when the job was started",
+		},
+	},
+	"required": []string{"job_id"},
+}
+
+// withJobTools adds the status and result companion tools of every tool
+// that polls jobs
+func withJobTools(tools map[string]ToolDefinition) map[string]ToolDefinition {
+	all := make(map[string]ToolDefinition, len(tools))
+	for key, tool := range tools {
+		all[key] = tool
+	}
+	for key, tool := range tools {
+		if tool.Poll == nil {
+			continue
+		}
+		name := tool.Name
+		if name == "" {
+			name = camelToSnake(key)
+		}
+		poll := tool.Poll
+		readOnly := &ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+
+		all[name+JobStatusSuffix] = ToolDefinition{
+			Name:        name + JobStatusSuffix,
+			Description: fmt.Sprintf("Check the progress of a %s job", name),
+			InputSchema: jobInputSchema,
+			Annotations: readOnly,
+			Tags:        tool.Tags,
+			Enabled:     tool.Enabled,
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				jobID, status, errResponse := pollJob(ctx, poll, input)
+				if errResponse != nil {
+					return *errResponse
+				}
+				text := fmt.Sprintf("Job %s is %s", jobID, status.State)
+				if status.Progress > 0 {
+					text += fmt.Sprintf(" (%.0f%%)", status.Progress*100)
+				}
+				if status.Message != "" {
+					text += ": " + status.Message
+				}
+				return WithStructured(text, status)
+			},
+		}
+
+		all[name+JobResultSuffix] = ToolDefinition{
+			Name:        name + JobResultSuffix,
+			Description: fmt.Sprintf("Fetch the result of a finished %s job", name),
+			InputSchema: jobInputSchema,
+			Annotations: readOnly,
+			Tags:        tool.Tags,
+			Enabled:     tool.Enabled,
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				jobID, status, errResponse := pollJob(ctx, poll, input)
+				if errResponse != nil {
+					return *errResponse
+				}
+				switch status.State {
+				case JobSucceeded:
+					if status.Result == nil {
+						return Textf("Job %s succeeded", jobID)
+					}
+					return *status.Result
+				case JobFailed:
+					if status.Message == "" {
+						return Errorf("Job %s failed", jobID)
+					}
+					return Errorf("Job %s failed: %s", jobID, status.Message)
+				}
+				return Errorf("Job %s is still %s; check %s%s and try again later", jobID, status.State, name, JobStatusSuffix)
+			},
+		}
+	}
+	return all
+}
+
+// pollJob reads the job ID from a companion tool's input and polls the job,
+// returning an error response if either fails
+func pollJob(ctx context.Context, poll PollFunc, input map[string]interface{}) (string, JobStatus, *ToolResponse) {
+	jobID, _ := input["job_id"].(string)
+	if jobID == "" {
+		response := Error("job_id is required")
+		return "", JobStatus{}, &response
+	}
+	status, err := poll(ctx, jobID)
+	if err != nil {
+		response := Errorf("Failed to check job %s: %v", jobID, err)
+		return jobID, JobStatus{}, &response
+	}
+	return jobID, status, nil
+}
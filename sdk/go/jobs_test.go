@@ -0,0 +1,80 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAsync_CompanionTools(t *testing.T) {
+	jobs := map[string]JobStatus{
+		"job-1": {State: JobRunning, Progress: 0.5, Message: "rendering frames"},
+		"job-2": {State: JobSucceeded, Result: &ToolResponse{Content: []ToolContent{{Type: "text", Text: "video.mp4"}}}},
+		"job-3": {State: JobFailed, Message: "out of memory"},
+	}
+	tools := withJobTools(map[string]ToolDefinition{
+		"renderVideo": {
+			Handler: func(input map[string]interface{}) ToolResponse {
+				return Async("job-1")
+			},
+			Poll: func(ctx context.Context, jobID string) (JobStatus, error) {
+				status, ok := jobs[jobID]
+				if !ok {
+					return JobStatus{}, errors.New("no such job")
+				}
+				return status, nil
+			},
+		},
+		"echo": {Handler: func(input map[string]interface{}) ToolResponse { return Text("hi") }},
+	})
+	if len(tools) != 4 {
+		t.Fatalf("expected 4 tools including companions, got %d", len(tools))
+	}
+
+	call := func(name string, input map[string]interface{}) ToolResponse {
+		t.Helper()
+		tool := findTool(context.Background(), tools, name)
+		if tool == nil {
+			t.Fatalf("tool %q not found", name)
+		}
+		return tool.call(&ToolContext{Context: context.Background(), ToolName: name}, input)
+	}
+
+	started := call("render_video", nil)
+	if job, ok := started.StructuredContent.(AsyncJob); !ok || job.JobID != "job-1" || job.State != JobRunning {
+		t.Errorf("structured content = %+v, want the running job", started.StructuredContent)
+	}
+	if text := started.Content[0].Text; !strings.Contains(text, "render_video_status") || !strings.Contains(text, "render_video_result") {
+		t.Errorf("response %q should name the companion tools", text)
+	}
+
+	tests := []struct {
+		tool    string
+		jobID   string
+		want    string
+		isError bool
+	}{
+		{"render_video_status", "job-1", "Job job-1 is running (50%): rendering frames", false},
+		{"render_video_status", "job-3", "Job job-3 is failed: out of memory", false},
+		{"render_video_status", "job-9", "Failed to check job job-9: no such job", true},
+		{"render_video_status", "", "job_id is required", true},
+		{"render_video_result", "job-1", "Job job-1 is still running; check render_video_status and try again later", true},
+		{"render_video_result", "job-2", "video.mp4", false},
+		{"render_video_result", "job-3", "Job job-3 failed: out of memory", true},
+	}
+	for _, tt := range tests {
+		got := call(tt.tool, map[string]interface{}{"job_id": tt.jobID})
+		if got.Content[0].Text != tt.want || got.IsError != tt.isError {
+			t.Errorf("%s(%q) = %q (error %v), want %q (error %v)", tt.tool, tt.jobID, got.Content[0].Text, got.IsError, tt.want, tt.isError)
+		}
+	}
+}
+
+func TestAsync_WithoutPoll(t *testing.T) {
+	tool := ToolDefinition{Handler: func(input map[string]interface{}) ToolResponse { return Async("job-1") }}
+	got := tool.call(&ToolContext{Context: context.Background(), ToolName: "render"}, nil)
+	if got.Content[0].Text != "Started job job-1" {
+		t.Errorf("response = %q, want the plain job text", got.Content[0].Text)
+	}
+}
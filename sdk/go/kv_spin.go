@@ -0,0 +1,11 @@
+//go:build !test
+
+package ftl
+
+import "github.com/spinframework/spin-go-sdk/kv"
+
+func init() {
+	openKVStore = func(name string) (kvStore, error) {
+		return kv.OpenStore(name)
+	}
+}
@@ -0,0 +1,62 @@
+package ftl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+)
+
+// MetaHeader carries the _meta of a tool call from the gateway, as base64url
+// JSON
+const MetaHeader = "X-FTL-Meta"
+
+// _meta keys the gateway adds to every tool call
+const (
+	// MetaTraceIDKey is the ID of the trace the call is part of
+	MetaTraceIDKey = "ftl/traceId"
+
+	// MetaRequestIDKey is the client's request ID, when it sent one
+	MetaRequestIDKey = "ftl/requestId"
+
+	// MetaUserAgentKey is the User-Agent of the client, when it sent one
+	MetaUserAgentKey = "ftl/userAgent"
+)
+
+// MetaFromHeader reads the _meta of a tool call forwarded by the gateway. It
+// returns nil when the header is missing or invalid.
+func MetaFromHeader(header http.Header) map[string]interface{} {
+	encoded := header.Get(MetaHeader)
+	if encoded == "" {
+		return nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil
+	}
+	return meta
+}
+
+// MetaString returns a string value of the call's _meta, or "" when it is
+// missing or not a string
+func (c *ToolContext) MetaString(key string) string {
+	value, _ := c.Meta[key].(string)
+	return value
+}
+
+// WithMeta returns the response with a key set in its _meta, which the
+// gateway returns to the client with the result:
+//
+//	return ftl.Text("Order placed").WithMeta("orderId", order.ID)
+func (r ToolResponse) WithMeta(key string, value interface{}) ToolResponse {
+	meta := make(map[string]interface{}, len(r.Meta)+1)
+	for k, v := range r.Meta {
+		meta[k] = v
+	}
+	meta[key] = value
+	r.Meta = meta
+	return r
+}
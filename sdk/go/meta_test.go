@@ -0,0 +1,54 @@
+package ftl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestMetaFromHeader(t *testing.T) {
+	if MetaFromHeader(http.Header{}) != nil {
+		t.Errorf("expected no meta without the header")
+	}
+
+	header := http.Header{}
+	header.Set(MetaHeader, base64.RawURLEncoding.EncodeToString([]byte(`{"progressToken":"p1","ftl/traceId":"4bf92f3577b34da6a3ce929d0e0e4736"}`)))
+	ctx := &ToolContext{Meta: MetaFromHeader(header)}
+	if ctx.MetaString(MetaTraceIDKey) != "4bf92f3577b34da6a3ce929d0e0e4736" || ctx.MetaString("progressToken") != "p1" {
+		t.Errorf("meta = %v", ctx.Meta)
+	}
+	if ctx.MetaString(MetaUserAgentKey) != "" {
+		t.Errorf("missing keys should be empty")
+	}
+
+	header.Set(MetaHeader, "not base64 json")
+	if meta := MetaFromHeader(header); meta != nil {
+		t.Errorf("invalid meta should be ignored, got %v", meta)
+	}
+}
+
+func TestToolResponse_WithMeta(t *testing.T) {
+	base := Text("Order placed").WithMeta("orderId", "o_1")
+	response := base.WithMeta("shard", 2)
+	if len(base.Meta) != 1 {
+		t.Errorf("WithMeta should not modify the original response, got %v", base.Meta)
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := decoded["_meta"].(map[string]interface{})
+	if meta["orderId"] != "o_1" || meta["shard"] != 2.0 {
+		t.Errorf("_meta = %v", decoded["_meta"])
+	}
+
+	if data, _ := json.Marshal(Text("ok")); string(data) != `{"content":[{"type":"text","text":"ok"}]}` {
+		t.Errorf("responses without meta should omit _meta, got %s", data)
+	}
+}
@@ -0,0 +1,155 @@
+package ftl
+
+import (
+	"reflect"
+	"strings"
+)
+
+// RedactedText replaces the strings Redact removes
+const RedactedText = "[REDACTED]"
+
+// Redactor rewrites a tool's responses to remove sensitive data, such as
+// secrets echoed back by an API or personal data in free text
+type Redactor func(response ToolResponse) ToolResponse
+
+// Redact returns a copy of v with the struct fields tagged ftl:"redact"
+// removed, for structured content and for values about to be logged:
+//
+//	type Customer struct {
+//		Name  string `json:"name"`
+//		Email string `json:"email" ftl:"redact"`
+//		Card  *Card  `json:"card,omitempty" ftl:"redact"`
+//	}
+//
+// Redacted strings are replaced with RedactedText and other fields with
+// their zero value. Tagged fields are found in nested structs, pointers,
+// slices, arrays, maps and interfaces; v itself is not modified.
+func Redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	value := reflect.ValueOf(v)
+	if !mayRedact(value.Type(), map[reflect.Type]bool{}) {
+		return v
+	}
+	return redactValue(value).Interface()
+}
+
+// redact removes sensitive data from a response of the tool, first the
+// tagged fields of its structured content and then whatever the tool's
+// Redactor removes
+func (t *ToolDefinition) redact(response ToolResponse) ToolResponse {
+	if response.StructuredContent != nil {
+		response.StructuredContent = Redact(response.StructuredContent)
+	}
+	if t.Redactor != nil {
+		response = t.Redactor(response)
+	}
+	return response
+}
+
+// isRedacted reports whether a struct field is tagged ftl:"redact"
+func isRedacted(field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup("ftl")
+	if !ok {
+		return false
+	}
+	for _, option := range strings.Split(tag, ",") {
+		if strings.TrimSpace(option) == "redact" {
+			return true
+		}
+	}
+	return false
+}
+
+// mayRedact reports whether values of type t can hold tagged fields,
+// which is always possible through interfaces
+func mayRedact(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return mayRedact(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.IsExported() && (isRedacted(field) || mayRedact(field.Type, seen)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue returns a copy of v with tagged fields redacted
+func redactValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(redactValue(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(redactValue(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isRedacted(field) {
+				out.Field(i).Set(redactedValue(field.Type))
+			} else {
+				out.Field(i).Set(redactValue(v.Field(i)))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), redactValue(iter.Value()))
+		}
+		return out
+	}
+	return v
+}
+
+// redactedValue is what a tagged field of type t is replaced with
+func redactedValue(t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(RedactedText).Convert(t)
+	}
+	return reflect.Zero(t)
+}
@@ -0,0 +1,83 @@
+package ftl
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type redactCard struct {
+	Number string `json:"number" ftl:"redact"`
+	Expiry string `json:"expiry"`
+}
+
+type redactCustomer struct {
+	Name    string        `json:"name"`
+	Email   string        `json:"email" ftl:"redact"`
+	Balance int           `json:"balance" ftl:"redact"`
+	Card    *redactCard   `json:"card,omitempty"`
+	History []redactCard  `json:"history"`
+	Extra   []interface{} `json:"extra"`
+}
+
+func TestRedact(t *testing.T) {
+	customer := redactCustomer{
+		Name:    "Ada",
+		Email:   "ada@example.com",
+		Balance: 42,
+		Card:    &redactCard{Number: "4111", Expiry: "12/30"},
+		History: []redactCard{{Number: "5500", Expiry: "01/29"}},
+		Extra:   []interface{}{redactCard{Number: "3400"}, "plain"},
+	}
+
+	got, ok := Redact(customer).(redactCustomer)
+	if !ok {
+		t.Fatalf("Redact() changed the type to %T", Redact(customer))
+	}
+	want := redactCustomer{
+		Name:    "Ada",
+		Email:   RedactedText,
+		Card:    &redactCard{Number: RedactedText, Expiry: "12/30"},
+		History: []redactCard{{Number: RedactedText, Expiry: "01/29"}},
+		Extra:   []interface{}{redactCard{Number: RedactedText}, "plain"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %+v, want %+v", got, want)
+	}
+	if customer.Email != "ada@example.com" || customer.Card.Number != "4111" || customer.History[0].Number != "5500" {
+		t.Error("Redact() modified its argument")
+	}
+
+	structured := map[string]interface{}{"customer": &customer, "count": 1}
+	redacted, _ := Redact(structured).(map[string]interface{})
+	if c, _ := redacted["customer"].(*redactCustomer); c == nil || c.Email != RedactedText {
+		t.Errorf("Redact() did not redact a struct in a map: %+v", redacted)
+	}
+
+	if Redact("text") != "text" || Redact(nil) != nil {
+		t.Error("Redact() changed a value without tagged fields")
+	}
+}
+
+func TestToolDefinition_Redactor(t *testing.T) {
+	tool := ToolDefinition{
+		Handler: func(input map[string]interface{}) ToolResponse {
+			return WithStructured("Customer Ada, token sk-123", redactCustomer{Name: "Ada", Email: "ada@example.com"})
+		},
+		Redactor: func(response ToolResponse) ToolResponse {
+			for i, content := range response.Content {
+				response.Content[i].Text = strings.ReplaceAll(content.Text, "sk-123", RedactedText)
+			}
+			return response
+		},
+	}
+
+	response := tool.call(&ToolContext{Context: context.Background(), ToolName: "customer"}, nil)
+	if response.Content[0].Text != "Customer Ada, token "+RedactedText {
+		t.Errorf("text = %q", response.Content[0].Text)
+	}
+	if customer, _ := response.StructuredContent.(redactCustomer); customer.Email != RedactedText {
+		t.Errorf("structured content = %+v", response.StructuredContent)
+	}
+}
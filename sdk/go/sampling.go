@@ -0,0 +1,238 @@
+package ftl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// SamplingCallHeader carries the ID of the streamed client call a tool runs
+// in. The gateway sets it when the client can be asked for completions or
+// input.
+const SamplingCallHeader = "X-FTL-Sampling-Call"
+
+// SamplingURL is the gateway endpoint RequestSampling and Elicit send
+// requests to. The component needs sampling or elicitation enabled in
+// ftl.yaml to be allowed to reach it.
+var SamplingURL = "http://mcp-gateway.spin.internal/sampling"
+
+// DefaultSamplingMaxTokens is the completion length RequestSampling asks
+// for when SamplingOptions sets none
+const DefaultSamplingMaxTokens = 1024
+
+// samplingUnavailableCode is the gateway's JSON-RPC error code for
+// requests that cannot reach the client
+const samplingUnavailableCode = -32001
+
+// ErrSamplingUnavailable is returned by RequestSampling when the client
+// cannot be asked: it did not stream the call, does not support sampling or
+// did not answer in time
+var ErrSamplingUnavailable = errors.New("sampling unavailable")
+
+// SamplingMessage is a message of the conversation sent to the client's
+// model
+type SamplingMessage struct {
+	// Role is "user" or "assistant"
+	Role string
+	Text string
+}
+
+// SamplingOptions tunes a sampling request. The client decides which model
+// answers and may ignore any of them.
+type SamplingOptions struct {
+	SystemPrompt string
+
+	// MaxTokens limits the completion, DefaultSamplingMaxTokens when 0
+	MaxTokens int
+
+	// Temperature is left to the client when nil
+	Temperature *float64
+
+	StopSequences []string
+
+	// ModelHints name preferred models, such as "claude-3-5-sonnet", in
+	// order of preference
+	ModelHints []string
+
+	// Messages are sent before the prompt, to continue a conversation
+	Messages []SamplingMessage
+}
+
+// SamplingResult is the client's completion
+type SamplingResult struct {
+	Role       string
+	Text       string
+	Model      string
+	StopReason string
+}
+
+type samplingCallKey struct{}
+
+// withSamplingCall records the streamed call sampling requests are relayed
+// in
+func withSamplingCall(ctx context.Context, call string) context.Context {
+	if call == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, samplingCallKey{}, call)
+}
+
+func samplingCallFrom(ctx context.Context) string {
+	call, _ := ctx.Value(samplingCallKey{}).(string)
+	return call
+}
+
+// RequestSampling asks the client's model for a completion of prompt with
+// MCP sampling, so tools can summarize or classify without API keys of
+// their own:
+//
+//	"summarize": {
+//		ContextHandler: func(ctx *ftl.ToolContext, input map[string]interface{}) ftl.ToolResponse {
+//			summary, err := ftl.RequestSampling(ctx, "Summarize:\n"+input["text"].(string), &ftl.SamplingOptions{MaxTokens: 200})
+//			if err != nil {
+//				return ftl.Errorf("Failed to summarize: %v", err)
+//			}
+//			return ftl.Text(summary.Text)
+//		},
+//	},
+//
+// The gateway relays the request to the client, which must have called the
+// tool accepting a streamed response. Pass the handler's ToolContext so the
+// request joins its trace. Tools called by other tools can request sampling
+// too. Errors wrap ErrSamplingUnavailable when the client cannot be asked.
+func RequestSampling(ctx context.Context, prompt string, opts *SamplingOptions) (*SamplingResult, error) {
+	if opts == nil {
+		opts = &SamplingOptions{}
+	}
+
+	var result struct {
+		Role    string `json:"role"`
+		Content struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Model      string `json:"model"`
+		StopReason string `json:"stopReason"`
+	}
+	if err := requestClient(ctx, "sampling/createMessage", samplingParams(prompt, opts), FeatureSampling, ErrSamplingUnavailable, &result); err != nil {
+		return nil, err
+	}
+	if result.Content.Type != "text" {
+		return nil, fmt.Errorf("client returned %q content, want text", result.Content.Type)
+	}
+	return &SamplingResult{
+		Role:       result.Role,
+		Text:       result.Content.Text,
+		Model:      result.Model,
+		StopReason: result.StopReason,
+	}, nil
+}
+
+// requestClient relays a request to the client through the gateway and
+// decodes the result of its response into result. Errors wrap unavailable
+// when the client cannot be asked, including when the gateway does not
+// support feature.
+func requestClient(ctx context.Context, method string, params interface{}, feature string, unavailable error, result interface{}) error {
+	call := samplingCallFrom(ctx)
+	if call == "" {
+		return fmt.Errorf("%w: the tool was not called by a streaming client", unavailable)
+	}
+	if err := requireFeature(ctx, feature); err != nil {
+		return fmt.Errorf("%w: %w", unavailable, err)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, SamplingURL+"/"+call, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if tc, ok := ctx.(*ToolContext); ok {
+		tc.InjectTrace(req.Header)
+	}
+
+	resp, err := sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach gateway: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gateway returned status %d", resp.StatusCode)
+	}
+
+	var rpc struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpc); err != nil {
+		return fmt.Errorf("invalid gateway response: %w", err)
+	}
+	if rpc.Error != nil {
+		if rpc.Error.Code == samplingUnavailableCode {
+			return fmt.Errorf("%w: %s", unavailable, rpc.Error.Message)
+		}
+		return fmt.Errorf("%s (code %d)", rpc.Error.Message, rpc.Error.Code)
+	}
+	if len(rpc.Result) == 0 {
+		return errors.New("invalid gateway response: missing result")
+	}
+	if err := json.Unmarshal(rpc.Result, result); err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return nil
+}
+
+// samplingParams builds the params of a sampling/createMessage request
+func samplingParams(prompt string, opts *SamplingOptions) map[string]interface{} {
+	message := func(role, text string) map[string]interface{} {
+		return map[string]interface{}{
+			"role":    role,
+			"content": map[string]interface{}{"type": "text", "text": text},
+		}
+	}
+	messages := make([]interface{}, 0, len(opts.Messages)+1)
+	for _, m := range opts.Messages {
+		messages = append(messages, message(m.Role, m.Text))
+	}
+	messages = append(messages, message("user", prompt))
+
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultSamplingMaxTokens
+	}
+	params := map[string]interface{}{
+		"messages":  messages,
+		"maxTokens": maxTokens,
+	}
+	if opts.SystemPrompt != "" {
+		params["systemPrompt"] = opts.SystemPrompt
+	}
+	if opts.Temperature != nil {
+		params["temperature"] = *opts.Temperature
+	}
+	if len(opts.StopSequences) > 0 {
+		params["stopSequences"] = opts.StopSequences
+	}
+	if len(opts.ModelHints) > 0 {
+		hints := make([]interface{}, 0, len(opts.ModelHints))
+		for _, name := range opts.ModelHints {
+			hints = append(hints, map[string]interface{}{"name": name})
+		}
+		params["modelPreferences"] = map[string]interface{}{"hints": hints}
+	}
+	return params
+}
@@ -0,0 +1,126 @@
+package ftl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestSampling(t *testing.T) {
+	var gotPath, gotTraceparent string
+	var gotParams map[string]interface{}
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotTraceparent = r.Header.Get(TraceparentHeader)
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.Unmarshal(req.Params, &gotParams)
+		if req.Method != "sampling/createMessage" {
+			t.Errorf("gateway received method %q", req.Method)
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"role":"assistant","content":{"type":"text","text":"Sunny all week"},"model":"test-model","stopReason":"endTurn"}}`))
+	}))
+	defer gateway.Close()
+
+	samplingURL := SamplingURL
+	SamplingURL = gateway.URL + "/sampling"
+	defer func() { SamplingURL = samplingURL }()
+
+	temperature := 0.2
+	ctx := &ToolContext{
+		Context: withSamplingCall(context.Background(), "0a1b2c"),
+		Trace:   TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+	}
+	got, err := RequestSampling(ctx, "Summarize the forecast", &SamplingOptions{
+		SystemPrompt: "Be brief",
+		MaxTokens:    50,
+		Temperature:  &temperature,
+		ModelHints:   []string{"claude-3-5-sonnet"},
+		Messages:     []SamplingMessage{{Role: "user", Text: "Forecast: sun"}},
+	})
+	if err != nil {
+		t.Fatalf("RequestSampling() error = %v", err)
+	}
+	want := SamplingResult{Role: "assistant", Text: "Sunny all week", Model: "test-model", StopReason: "endTurn"}
+	if *got != want {
+		t.Errorf("RequestSampling() = %+v, want %+v", *got, want)
+	}
+	if gotPath != "/sampling/0a1b2c" {
+		t.Errorf("gateway received path %q", gotPath)
+	}
+	if gotTraceparent != testTraceparent {
+		t.Errorf("gateway received traceparent %q, want %q", gotTraceparent, testTraceparent)
+	}
+
+	params, _ := json.Marshal(gotParams)
+	for _, want := range []string{
+		`"maxTokens":50`,
+		`"systemPrompt":"Be brief"`,
+		`"temperature":0.2`,
+		`"modelPreferences":{"hints":[{"name":"claude-3-5-sonnet"}]}`,
+		`"messages":[{"content":{"text":"Forecast: sun","type":"text"},"role":"user"},{"content":{"text":"Summarize the forecast","type":"text"},"role":"user"}]`,
+	} {
+		if !strings.Contains(string(params), want) {
+			t.Errorf("gateway received params %s, missing %s", params, want)
+		}
+	}
+}
+
+func TestRequestSampling_Defaults(t *testing.T) {
+	params := samplingParams("Classify", &SamplingOptions{})
+	if params["maxTokens"] != DefaultSamplingMaxTokens {
+		t.Errorf("maxTokens = %v, want %d", params["maxTokens"], DefaultSamplingMaxTokens)
+	}
+	for _, key := range []string{"systemPrompt", "temperature", "stopSequences", "modelPreferences"} {
+		if _, ok := params[key]; ok {
+			t.Errorf("unset option %s was sent", key)
+		}
+	}
+}
+
+func TestRequestSampling_Unavailable(t *testing.T) {
+	if _, err := RequestSampling(context.Background(), "Summarize", nil); !errors.Is(err, ErrSamplingUnavailable) {
+		t.Errorf("RequestSampling() without a streamed call error = %v, want ErrSamplingUnavailable", err)
+	}
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32001,"message":"The client did not answer the sampling request within 60 seconds"}}`))
+	}))
+	defer gateway.Close()
+
+	samplingURL := SamplingURL
+	SamplingURL = gateway.URL
+	defer func() { SamplingURL = samplingURL }()
+
+	_, err := RequestSampling(withSamplingCall(context.Background(), "0a1b2c"), "Summarize", nil)
+	if !errors.Is(err, ErrSamplingUnavailable) || !strings.Contains(err.Error(), "60 seconds") {
+		t.Errorf("RequestSampling() error = %v, want ErrSamplingUnavailable with the gateway's message", err)
+	}
+}
+
+func TestCallTool_ForwardsSamplingCall(t *testing.T) {
+	var gotCall string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCall = r.Header.Get(SamplingCallHeader)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}]}}`))
+	}))
+	defer gateway.Close()
+
+	gatewayURL := GatewayURL
+	GatewayURL = gateway.URL
+	defer func() { GatewayURL = gatewayURL }()
+
+	if _, err := CallTool(withSamplingCall(context.Background(), "0a1b2c"), "writer__summarize", nil); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if gotCall != "0a1b2c" {
+		t.Errorf("gateway received sampling call %q, want 0a1b2c", gotCall)
+	}
+}
@@ -0,0 +1,307 @@
+package ftl
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError lists the ways a value does not match a JSON Schema
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, "; ")
+}
+
+// Validate checks a value decoded from JSON against a JSON Schema, with the
+// same results as the gateway's argument validation for the keywords tools
+// use: type, enum, const, properties, required, additionalProperties, items,
+// minItems, maxItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, allOf, anyOf, oneOf and not. Other
+// keywords, such as format, are not checked.
+//
+// The fixtures in sdk/conformance keep these results aligned with the
+// other SDKs.
+func Validate(schema map[string]interface{}, value interface{}) error {
+	var errs []string
+	validateValue(schema, value, "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateValue appends the errors of value at path to errs. schema may
+// be an object or a boolean schema.
+func validateValue(schema interface{}, value interface{}, path string, errs *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, orRoot(path)+": "+fmt.Sprintf(format, args...))
+	}
+
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		if allowed, isBool := schema.(bool); isBool && !allowed {
+			fail("no value is allowed")
+		}
+		return
+	}
+
+	if t, ok := s["type"]; ok && !matchesType(t, value) {
+		fail("expected %s, got %s", typeList(t), jsonType(value))
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if jsonEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value is not one of the allowed values")
+		}
+	}
+	if c, ok := s["const"]; ok && !jsonEqual(c, value) {
+		fail("value does not equal the constant")
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(s, v, path, errs)
+	case []interface{}:
+		if n, ok := number(s["minItems"]); ok && float64(len(v)) < n {
+			fail("expected at least %v items, got %d", n, len(v))
+		}
+		if n, ok := number(s["maxItems"]); ok && float64(len(v)) > n {
+			fail("expected at most %v items, got %d", n, len(v))
+		}
+		if items, ok := s["items"]; ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := number(s["minLength"]); ok && length < n {
+			fail("expected at least %v characters", n)
+		}
+		if n, ok := number(s["maxLength"]); ok && length > n {
+			fail("expected at most %v characters", n)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("invalid pattern %q", pattern)
+			} else if !re.MatchString(v) {
+				fail("value does not match %q", pattern)
+			}
+		}
+	default:
+		if n, ok := number(value); ok {
+			if min, ok := number(s["minimum"]); ok && n < min {
+				fail("expected at least %v", min)
+			}
+			if max, ok := number(s["maximum"]); ok && n > max {
+				fail("expected at most %v", max)
+			}
+			if min, ok := number(s["exclusiveMinimum"]); ok && n <= min {
+				fail("expected more than %v", min)
+			}
+			if max, ok := number(s["exclusiveMaximum"]); ok && n >= max {
+				fail("expected less than %v", max)
+			}
+		}
+	}
+
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			validateValue(sub, value, path, errs)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok && countMatches(anyOf, value) == 0 {
+		fail("value matches none of anyOf")
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		if n := countMatches(oneOf, value); n != 1 {
+			fail("value matches %d of oneOf, expected exactly 1", n)
+		}
+	}
+	if not, ok := s["not"]; ok && countMatches([]interface{}{not}, value) == 1 {
+		fail("value matches a schema it must not match")
+	}
+}
+
+// validateObject checks the object keywords of a schema
+func validateObject(s map[string]interface{}, v map[string]interface{}, path string, errs *[]string) {
+	properties, _ := s["properties"].(map[string]interface{})
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := v[key]; !present {
+					*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", orRoot(path), key))
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	additional, hasAdditional := s["additionalProperties"]
+	for _, key := range keys {
+		propPath := path + "/" + key
+		if propSchema, ok := properties[key]; ok {
+			validateValue(propSchema, v[key], propPath, errs)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && !allowed {
+			*errs = append(*errs, fmt.Sprintf("%s: unexpected property %q", orRoot(path), key))
+			continue
+		}
+		validateValue(additional, v[key], propPath, errs)
+	}
+}
+
+// countMatches returns how many of schemas value matches
+func countMatches(schemas []interface{}, value interface{}) int {
+	n := 0
+	for _, sub := range schemas {
+		var subErrs []string
+		validateValue(sub, value, "", &subErrs)
+		if len(subErrs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// matchesType reports whether value has the type, or one of the types, t
+func matchesType(t interface{}, value interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return isType(t, value)
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && isType(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isType(name string, value interface{}) bool {
+	actual := jsonType(value)
+	switch name {
+	case "number":
+		return actual == "integer" || actual == "number"
+	default:
+		return actual == name
+	}
+}
+
+// jsonType returns the JSON Schema type of a value decoded from JSON.
+// Numbers without a fractional part, such as 1.0, are integers.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if n, ok := number(value); ok {
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func typeList(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(list))
+		for _, item := range list {
+			names = append(names, fmt.Sprint(item))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+// number converts a JSON number to a float64
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// jsonEqual compares values decoded from JSON, treating numbers by value
+func jsonEqual(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
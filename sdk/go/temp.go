@@ -0,0 +1,221 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TempDirEnv names the variable holding the directory calls get their
+// scratch space in. It defaults to os.TempDir(), and must be a writable
+// directory preopened for the component.
+const TempDirEnv = "FTL_TEMP_DIR"
+
+// DefaultTempQuota is how many bytes a call can store in its scratch space
+// by default
+const DefaultTempQuota = 64 << 20
+
+// ErrTempQuotaExceeded is returned when a call's scratch space is full
+var ErrTempQuotaExceeded = errors.New("temporary storage quota exceeded")
+
+// ErrNoTempStorage is returned by TempFile and TempDir outside a tool call
+var ErrNoTempStorage = errors.New("temporary storage is only available during a tool call")
+
+// tempStorageKey is the context key of a call's scratch space
+type tempStorageKey struct{}
+
+// tempStorage is the scratch space of a call, created on first use in a
+// directory of its own and removed when the handler returns
+type tempStorage struct {
+	mu      sync.Mutex
+	quota   int64
+	dir     string
+	written int64
+	files   []*ScratchFile
+}
+
+// withTempStorage gives the call in ctx its own scratch space, holding
+// quota bytes. Releasing the storage removes it.
+func withTempStorage(ctx context.Context, quota int64) (scoped context.Context, storage *tempStorage) {
+	if quota <= 0 {
+		quota = DefaultTempQuota
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	storage = &tempStorage{quota: quota}
+	return context.WithValue(ctx, tempStorageKey{}, storage), storage
+}
+
+// tempStorageFrom returns the scratch space of the call in ctx, if any
+func tempStorageFrom(ctx context.Context) *tempStorage {
+	storage, _ := ctx.Value(tempStorageKey{}).(*tempStorage)
+	return storage
+}
+
+// ScratchFile is a file in a call's scratch space. Writes fail with
+// ErrTempQuotaExceeded once the call has used up its quota.
+type ScratchFile struct {
+	*os.File
+	storage *tempStorage
+}
+
+// Write writes to the file unless that exceeds the call's quota
+func (f *ScratchFile) Write(p []byte) (int, error) {
+	if err := f.storage.reserve(int64(len(p))); err != nil {
+		return 0, err
+	}
+	return f.File.Write(p)
+}
+
+// WriteString writes s to the file unless that exceeds the call's quota
+func (f *ScratchFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// TempFile creates a file in the scratch space of the tool call in ctx, for
+// intermediate results such as resized images or unpacked archives:
+//
+//	"thumbnail": {
+//		ContextHandler: func(ctx *ftl.ToolContext, input map[string]interface{}) ftl.ToolResponse {
+//			file, err := ftl.TempFile(ctx)
+//			if err != nil {
+//				return ftl.Errorf("No scratch space: %v", err)
+//			}
+//			if _, err := io.Copy(file, download); err != nil {
+//				return ftl.Errorf("Download failed: %v", err)
+//			}
+//			...
+//		},
+//	},
+//
+// The file is closed and removed when the handler returns, so handlers
+// need not clean up after themselves. Writes through the file count
+// against the tool's TempQuota.
+func TempFile(ctx context.Context) (*ScratchFile, error) {
+	storage := tempStorageFrom(ctx)
+	if storage == nil {
+		return nil, ErrNoTempStorage
+	}
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	dir, err := storage.allocate()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(dir, "file-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	scratch := &ScratchFile{File: file, storage: storage}
+	storage.files = append(storage.files, scratch)
+	return scratch, nil
+}
+
+// TempDir creates a directory in the scratch space of the tool call in
+// ctx, for tools that write several files, such as archive extraction.
+// Like TempFile, it is removed when the handler returns.
+//
+// Files written to the directory are counted against the tool's TempQuota
+// when more scratch space is requested and when the handler returns; a
+// call that exceeded the quota fails even if its handler succeeded.
+func TempDir(ctx context.Context) (string, error) {
+	storage := tempStorageFrom(ctx)
+	if storage == nil {
+		return "", ErrNoTempStorage
+	}
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	dir, err := storage.allocate()
+	if err != nil {
+		return "", err
+	}
+	path, err := os.MkdirTemp(dir, "dir-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	return path, nil
+}
+
+// allocate creates the call's directory on first use and checks that the
+// call is within its quota. It is called with mu held.
+func (s *tempStorage) allocate() (string, error) {
+	if s.dir == "" {
+		root := os.Getenv(TempDirEnv)
+		if root == "" {
+			root = os.TempDir()
+		}
+		dir, err := os.MkdirTemp(root, "ftl-call-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create scratch space in %s: %w", root, err)
+		}
+		s.dir = dir
+		return dir, nil
+	}
+	if err := s.measure(); err != nil {
+		return "", err
+	}
+	if s.written > s.quota {
+		return "", ErrTempQuotaExceeded
+	}
+	return s.dir, nil
+}
+
+// reserve counts n more bytes against the quota
+func (s *tempStorage) reserve(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written+n > s.quota {
+		return ErrTempQuotaExceeded
+	}
+	s.written += n
+	return nil
+}
+
+// measure updates the bytes used from the files on disk, which includes
+// files written to directories from TempDir. It is called with mu held.
+func (s *tempStorage) measure() error {
+	var used int64
+	err := filepath.WalkDir(s.dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		used += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to measure scratch space: %w", err)
+	}
+	s.written = used
+	return nil
+}
+
+// release removes the call's scratch space and reports whether the call
+// stayed within its quota
+func (s *tempStorage) release() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	for _, file := range s.files {
+		_ = file.File.Close()
+	}
+	err := s.measure()
+	if err == nil && s.written > s.quota {
+		err = ErrTempQuotaExceeded
+	}
+	if removeErr := os.RemoveAll(s.dir); removeErr != nil {
+		secureLogf("Failed to remove scratch space %s: %v", s.dir, removeErr)
+	}
+	s.dir, s.files, s.written = "", nil, 0
+	return err
+}
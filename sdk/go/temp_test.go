@@ -0,0 +1,103 @@
+package ftl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTempFile_RemovedAfterCall(t *testing.T) {
+	t.Setenv(TempDirEnv, t.TempDir())
+	var path, dir string
+	tool := ToolDefinition{
+		ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+			file, err := TempFile(ctx)
+			if err != nil {
+				return Errorf("TempFile: %v", err)
+			}
+			if _, err := file.WriteString("intermediate"); err != nil {
+				return Errorf("write: %v", err)
+			}
+			path = file.Name()
+			if dir, err = TempDir(ctx); err != nil {
+				return Errorf("TempDir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "page.txt"), []byte("page"), 0600); err != nil {
+				return Errorf("write: %v", err)
+			}
+			return Text("done")
+		},
+	}
+
+	response := tool.call(&ToolContext{Context: context.Background(), ToolName: "unpack"}, nil)
+	if response.IsError {
+		t.Fatalf("call failed: %s", response.Content[0].Text)
+	}
+	if !strings.HasPrefix(path, os.Getenv(TempDirEnv)) {
+		t.Errorf("temp file %s is not in %s", path, os.Getenv(TempDirEnv))
+	}
+	for _, p := range []string{path, dir} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after the call", p)
+		}
+	}
+}
+
+func TestTempFile_Quota(t *testing.T) {
+	t.Setenv(TempDirEnv, t.TempDir())
+	tool := ToolDefinition{
+		ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+			file, err := TempFile(ctx)
+			if err != nil {
+				return Errorf("TempFile: %v", err)
+			}
+			if _, err := file.Write(make([]byte, 8)); err != nil {
+				return Errorf("first write: %v", err)
+			}
+			if _, err := file.Write(make([]byte, 8)); !errors.Is(err, ErrTempQuotaExceeded) {
+				return Errorf("second write error = %v", err)
+			}
+			return Text("done")
+		},
+		TempQuota: 10,
+	}
+	if response := tool.call(&ToolContext{Context: context.Background()}, nil); response.IsError {
+		t.Errorf("call failed: %s", response.Content[0].Text)
+	}
+}
+
+func TestTempDir_QuotaCheckedAfterCall(t *testing.T) {
+	t.Setenv(TempDirEnv, t.TempDir())
+	tool := ToolDefinition{
+		ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+			dir, err := TempDir(ctx)
+			if err != nil {
+				return Errorf("TempDir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "big"), make([]byte, 32), 0600); err != nil {
+				return Errorf("write: %v", err)
+			}
+			if _, err := TempFile(ctx); !errors.Is(err, ErrTempQuotaExceeded) {
+				return Errorf("TempFile error = %v", err)
+			}
+			return Text("done")
+		},
+		TempQuota: 16,
+	}
+	response := tool.call(&ToolContext{Context: context.Background(), ToolName: "unpack"}, nil)
+	if !response.IsError || !strings.Contains(response.Content[0].Text, ErrTempQuotaExceeded.Error()) {
+		t.Errorf("response = %+v, want a quota error", response)
+	}
+}
+
+func TestTempFile_OutsideCall(t *testing.T) {
+	if _, err := TempFile(context.Background()); !errors.Is(err, ErrNoTempStorage) {
+		t.Errorf("TempFile error = %v, want ErrNoTempStorage", err)
+	}
+	if _, err := TempDir(context.Background()); !errors.Is(err, ErrNoTempStorage) {
+		t.Errorf("TempDir error = %v, want ErrNoTempStorage", err)
+	}
+}
@@ -0,0 +1,155 @@
+package ftl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ToolList is a component's tools/list payload, computed once when the
+// handler is created instead of on every listing. Components with many
+// tools skip regenerating their schemas for each request, and callers
+// that send the listing's ETag in If-None-Match get 304 Not Modified.
+type ToolList struct {
+	// Tools are the listed tools, sorted by name
+	Tools []ToolMetadata
+
+	// JSON is the listing as served by GET /
+	JSON []byte
+
+	// ETag identifies the listing; it changes when any tool's metadata does
+	ETag string
+
+	// Hashes holds the SHA-256 of each tool's metadata, by tool name
+	Hashes map[string]string
+
+	entries []toolListEntry
+	static  bool
+}
+
+// toolListEntry is a listed tool and its encoded metadata
+type toolListEntry struct {
+	tool *ToolDefinition
+	json []byte
+}
+
+// ExportToolList computes the tools/list payload of tools, as CreateTools
+// serves it. Call it after all tools are registered to publish the hashes
+// of their schemas or check them in tests; CreateTools computes its own.
+// Tools with an Enabled condition are listed as if it held.
+func ExportToolList(tools map[string]ToolDefinition) (*ToolList, error) {
+	all := make(map[string]ToolDefinition, len(tools))
+	for key, tool := range tools {
+		if key != "" {
+			all[key] = tool
+		}
+	}
+	return newToolList(withJobTools(all))
+}
+
+// newToolList encodes the metadata of each tool once
+func newToolList(tools map[string]ToolDefinition) (*ToolList, error) {
+	list := &ToolList{
+		Tools:  make([]ToolMetadata, 0, len(tools)),
+		Hashes: make(map[string]string, len(tools)),
+		static: true,
+	}
+	for key := range tools {
+		tool := tools[key]
+		list.Tools = append(list.Tools, tool.metadata(key))
+		list.entries = append(list.entries, toolListEntry{tool: &tool})
+		if tool.Enabled != nil {
+			list.static = false
+		}
+	}
+	sort.Sort(byToolName{list})
+
+	for i, meta := range list.Tools {
+		data, err := json.Marshal(meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode metadata of tool '%s': %w", meta.Name, err)
+		}
+		list.entries[i].json = data
+		list.Hashes[meta.Name] = sha256Hex(data)
+	}
+	list.JSON = list.encode(nil)
+	list.ETag = etag(list.JSON)
+	return list, nil
+}
+
+// encode joins the encoded metadata of the tools enabled for a request, or
+// of every tool when enabled is nil
+func (l *ToolList) encode(enabled func(*ToolDefinition) bool) []byte {
+	var b strings.Builder
+	b.WriteByte('[')
+	first := true
+	for _, entry := range l.entries {
+		if enabled != nil && !enabled(entry.tool) {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		b.Write(entry.json)
+	}
+	b.WriteString("]\n")
+	return []byte(b.String())
+}
+
+// serve writes the listing for a request, or 304 Not Modified when the
+// request's If-None-Match names it
+func (l *ToolList) serve(w http.ResponseWriter, r *http.Request) {
+	body, tag := l.JSON, l.ETag
+	if !l.static {
+		body = l.encode(func(tool *ToolDefinition) bool { return tool.enabled(r.Context()) })
+		tag = etag(body)
+	}
+
+	w.Header().Set("ETag", tag)
+	if etagMatches(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		secureLogf("Failed to write tools metadata: %v", err)
+	}
+}
+
+// byToolName sorts a ToolList's tools and entries together by name
+type byToolName struct{ *ToolList }
+
+func (s byToolName) Len() int { return len(s.Tools) }
+
+func (s byToolName) Less(i, j int) bool { return s.Tools[i].Name < s.Tools[j].Name }
+
+func (s byToolName) Swap(i, j int) {
+	s.Tools[i], s.Tools[j] = s.Tools[j], s.Tools[i]
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// etag returns the strong entity tag of a listing
+func etag(body []byte) string {
+	return `"` + sha256Hex(body)[:32] + `"`
+}
+
+// etagMatches reports whether an If-None-Match header names tag
+func etagMatches(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package ftl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func listTools(t *testing.T, handler http.HandlerFunc, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestExportToolList(t *testing.T) {
+	tools := map[string]ToolDefinition{
+		"zeta":      {Description: "Last", Handler: reverseHandler},
+		"alphaTool": {Description: "First", Handler: reverseHandler},
+	}
+	list, err := ExportToolList(tools)
+	if err != nil {
+		t.Fatalf("ExportToolList: %v", err)
+	}
+	if len(list.Tools) != 2 || list.Tools[0].Name != "alpha_tool" || list.Tools[1].Name != "zeta" {
+		t.Fatalf("tools should be sorted by name, got %+v", list.Tools)
+	}
+	if len(list.Hashes) != 2 || len(list.Hashes["zeta"]) != 64 {
+		t.Errorf("expected a SHA-256 per tool, got %v", list.Hashes)
+	}
+
+	var listed []ToolMetadata
+	if err := json.Unmarshal(list.JSON, &listed); err != nil || len(listed) != 2 {
+		t.Fatalf("JSON should decode to the tools, got %s (%v)", list.JSON, err)
+	}
+
+	again, _ := ExportToolList(tools)
+	if again.ETag != list.ETag {
+		t.Error("the ETag should be stable across exports")
+	}
+	tools["zeta"] = ToolDefinition{Description: "Changed", Handler: reverseHandler}
+	changed, _ := ExportToolList(tools)
+	if changed.ETag == list.ETag || changed.Hashes["zeta"] == list.Hashes["zeta"] {
+		t.Error("changing a tool should change the ETag and its hash")
+	}
+	if changed.Hashes["alpha_tool"] != list.Hashes["alpha_tool"] {
+		t.Error("unchanged tools should keep their hash")
+	}
+}
+
+func TestHandler_ToolListETag(t *testing.T) {
+	tools := map[string]ToolDefinition{"echo": {Handler: reverseHandler}}
+	handler := Handler(tools)
+	list, _ := ExportToolList(tools)
+
+	rec := listTools(t, handler, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != list.ETag {
+		t.Fatalf("expected 200 with ETag %s, got %d %q", list.ETag, rec.Code, rec.Header().Get("ETag"))
+	}
+	if rec.Body.String() != string(list.JSON) {
+		t.Errorf("expected the exported listing, got %s", rec.Body)
+	}
+
+	rec = listTools(t, handler, `"other", `+list.ETag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("expected 304 for a matching If-None-Match, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := listTools(t, handler, `"other"`); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a stale If-None-Match, got %d", rec.Code)
+	}
+}
+
+func TestHandler_ToolListConditional(t *testing.T) {
+	on := false
+	handler := Handler(map[string]ToolDefinition{
+		"always": {Handler: reverseHandler},
+		"gated":  {Handler: reverseHandler, Enabled: func(context.Context) bool { return on }},
+	})
+
+	hidden := listTools(t, handler, "")
+	var listed []ToolMetadata
+	if err := json.Unmarshal(hidden.Body.Bytes(), &listed); err != nil || len(listed) != 1 {
+		t.Fatalf("expected only the enabled tool, got %s", hidden.Body)
+	}
+
+	on = true
+	shown := listTools(t, handler, hidden.Header().Get("ETag"))
+	if shown.Code != http.StatusOK {
+		t.Fatalf("a listing whose tools changed should not match the old ETag, got %d", shown.Code)
+	}
+	if err := json.Unmarshal(shown.Body.Bytes(), &listed); err != nil || len(listed) != 2 {
+		t.Errorf("expected both tools, got %s", shown.Body)
+	}
+}
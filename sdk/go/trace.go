@@ -0,0 +1,130 @@
+package ftl
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// W3C trace context headers forwarded by the FTL gateway
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// ContextToolHandler is a tool handler that receives the request context
+type ContextToolHandler func(ctx *ToolContext, input map[string]interface{}) ToolResponse
+
+// ToolContext carries request-scoped information to a ContextToolHandler.
+// It is a context.Context, so it can be passed to outbound calls directly.
+type ToolContext struct {
+	context.Context
+
+	// ToolName is the name the tool was called by
+	ToolName string
+
+	// Trace is the span of the gateway's call to this tool. It is empty
+	// when the request carried no valid traceparent header.
+	Trace TraceContext
+
+	// RequestID identifies the client's request when the client sent one
+	// through the gateway, and is empty otherwise
+	RequestID string
+
+	// Meta is the _meta of the client's request, plus the ftl/ keys the
+	// gateway adds. It is nil when the call did not come through the gateway.
+	Meta map[string]interface{}
+
+	// identity is the authenticated caller, see Identity
+	identity *Identity
+}
+
+// InjectTrace sets trace context headers on an outbound request so the
+// service it calls continues the same trace
+func (c *ToolContext) InjectTrace(header http.Header) {
+	if !c.Trace.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, c.Trace.Traceparent())
+	if c.Trace.TraceState != "" {
+		header.Set(TracestateHeader, c.Trace.TraceState)
+	}
+}
+
+// TraceContext identifies a span in a distributed trace, as propagated by
+// the W3C traceparent and tracestate headers
+type TraceContext struct {
+	// TraceID is the 32 hex digit trace ID
+	TraceID string
+
+	// SpanID is the 16 hex digit ID of the calling span
+	SpanID string
+
+	// Sampled reports whether the caller is recording the trace
+	Sampled bool
+
+	// TraceState holds vendor-specific trace data
+	TraceState string
+}
+
+// IsValid reports whether the trace and span IDs are set
+func (t TraceContext) IsValid() bool {
+	return isTraceID(t.TraceID, 32) && isTraceID(t.SpanID, 16)
+}
+
+// Traceparent formats the trace context as a traceparent header value, or
+// returns "" when it is not valid
+func (t TraceContext) Traceparent() string {
+	if !t.IsValid() {
+		return ""
+	}
+	flags := "00"
+	if t.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", t.TraceID, t.SpanID, flags)
+}
+
+// ParseTraceparent parses a version 00 traceparent header value
+func ParseTraceparent(value string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[3]) != 2 {
+		return TraceContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return TraceContext{}, false
+	}
+	trace := TraceContext{
+		TraceID: strings.ToLower(parts[1]),
+		SpanID:  strings.ToLower(parts[2]),
+		Sampled: flags[0]&1 == 1,
+	}
+	if !trace.IsValid() {
+		return TraceContext{}, false
+	}
+	return trace, true
+}
+
+// TraceFromHeaders reads the trace context of an incoming request
+func TraceFromHeaders(header http.Header) TraceContext {
+	trace, ok := ParseTraceparent(header.Get(TraceparentHeader))
+	if !ok {
+		return TraceContext{}
+	}
+	trace.TraceState = strings.TrimSpace(header.Get(TracestateHeader))
+	return trace
+}
+
+// isTraceID reports whether id is n hex digits and not all zeros
+func isTraceID(id string, n int) bool {
+	if len(id) != n {
+		return false
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return false
+	}
+	return strings.Trim(id, "0") != ""
+}
@@ -0,0 +1,99 @@
+package ftl
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	trace, ok := ParseTraceparent(testTraceparent)
+	if !ok {
+		t.Fatalf("ParseTraceparent(%q) failed", testTraceparent)
+	}
+	want := TraceContext{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Sampled: true,
+	}
+	if trace != want {
+		t.Errorf("ParseTraceparent() = %+v, want %+v", trace, want)
+	}
+	if got := trace.Traceparent(); got != testTraceparent {
+		t.Errorf("Traceparent() = %q, want %q", got, testTraceparent)
+	}
+
+	invalid := []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473g-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	}
+	for _, value := range invalid {
+		if _, ok := ParseTraceparent(value); ok {
+			t.Errorf("ParseTraceparent(%q) succeeded, want failure", value)
+		}
+	}
+}
+
+func TestTraceFromHeaders(t *testing.T) {
+	header := http.Header{}
+	if trace := TraceFromHeaders(header); trace.IsValid() || trace.Traceparent() != "" {
+		t.Errorf("TraceFromHeaders() = %+v, want empty trace without traceparent", trace)
+	}
+
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	header.Set(TracestateHeader, "vendor=1")
+	trace := TraceFromHeaders(header)
+	if !trace.IsValid() || trace.Sampled || trace.TraceState != "vendor=1" {
+		t.Errorf("TraceFromHeaders() = %+v, want unsampled trace with state", trace)
+	}
+}
+
+func TestToolContext_InjectTrace(t *testing.T) {
+	trace, _ := ParseTraceparent(testTraceparent)
+	trace.TraceState = "vendor=1"
+	ctx := &ToolContext{Context: context.Background(), Trace: trace}
+
+	header := http.Header{}
+	ctx.InjectTrace(header)
+	if got := header.Get(TraceparentHeader); got != testTraceparent {
+		t.Errorf("traceparent = %q, want %q", got, testTraceparent)
+	}
+	if got := header.Get(TracestateHeader); got != "vendor=1" {
+		t.Errorf("tracestate = %q, want vendor=1", got)
+	}
+
+	header = http.Header{}
+	(&ToolContext{Context: context.Background()}).InjectTrace(header)
+	if len(header) != 0 {
+		t.Errorf("InjectTrace() set %v without a trace", header)
+	}
+}
+
+func TestToolDefinition_ContextHandler(t *testing.T) {
+	trace, _ := ParseTraceparent(testTraceparent)
+	tool := NewToolGroup("trace").
+		Use(tagMiddleware("mw:")).
+		Handle("show", ToolDefinition{
+			ContextHandler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+				return Text(ctx.ToolName + " " + ctx.Trace.TraceID)
+			},
+		}).
+		Tools()["trace.show"]
+
+	got := tool.call(&ToolContext{Context: context.Background(), ToolName: "trace.show", Trace: trace}, nil)
+	if want := "mw:trace.show 4bf92f3577b34da6a3ce929d0e0e4736"; got.Content[0].Text != want {
+		t.Errorf("call() = %q, want %q", got.Content[0].Text, want)
+	}
+
+	missing := ToolDefinition{}
+	if resp := missing.call(&ToolContext{Context: context.Background(), ToolName: "missing"}, nil); !resp.IsError {
+		t.Errorf("call() without a handler = %+v, want error", resp)
+	}
+}
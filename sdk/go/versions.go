@@ -0,0 +1,173 @@
+package ftl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// InputVersionField is the input field callers name the version of a
+// versioned tool's input in
+const InputVersionField = "input_version"
+
+// MetaDeprecationKey is the response _meta key holding the deprecation
+// warning of a call made with a deprecated input version
+const MetaDeprecationKey = "ftl/deprecation"
+
+// ToolVersion is one version of a tool's input and the handler for it,
+// created with TypedToolVersion. A published tool can change its input
+// without breaking existing clients by listing its versions, oldest first:
+//
+//	"search": {
+//		Description: "Search the catalog",
+//		Versions: []ftl.ToolVersion{
+//			ftl.TypedToolVersion("1", searchV1Schema, searchV1).Deprecate("send query instead of q"),
+//			ftl.TypedToolVersion("2", searchV2Schema, searchV2),
+//		},
+//	},
+//
+// A call is handled by the version named in its "input_version" field or,
+// without one, by the newest version whose schema the input matches. Calls
+// handled by a deprecated version return its warning in the response's
+// _meta. The tool is listed with an input schema accepting any version.
+type ToolVersion struct {
+	// Version names the version, e.g. "1"
+	Version string
+
+	// InputSchema is the JSON Schema of this version's input
+	InputSchema map[string]interface{}
+
+	// Deprecated is the warning returned with calls made with this
+	// version; empty while the version is supported
+	Deprecated string
+
+	handler ContextToolHandler
+}
+
+// TypedToolVersion creates a version of a tool's input, decoded into T
+// before the handler runs. T may be a struct or a map[string]interface{}.
+func TypedToolVersion[T any](version string, schema map[string]interface{}, handler func(ctx *ToolContext, input T) ToolResponse) ToolVersion {
+	return ToolVersion{
+		Version:     version,
+		InputSchema: schema,
+		handler: func(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+			var typed T
+			data, err := json.Marshal(input)
+			if err == nil {
+				err = json.Unmarshal(data, &typed)
+			}
+			if err != nil {
+				return Errorf("Invalid arguments for tool '%s' version %s: %v", ctx.ToolName, version, err)
+			}
+			return handler(ctx, typed)
+		},
+	}
+}
+
+// Deprecate returns the version marked deprecated, with a warning telling
+// callers how to move to a newer one
+func (v ToolVersion) Deprecate(warning string) ToolVersion {
+	v.Deprecated = warning
+	return v
+}
+
+// callVersion runs the handler of the version of the tool's input the call
+// was made with
+func (t *ToolDefinition) callVersion(ctx *ToolContext, input map[string]interface{}) ToolResponse {
+	version, input, err := t.selectVersion(input)
+	if err != nil {
+		return Errorf("Invalid arguments for tool '%s': %v", ctx.ToolName, err)
+	}
+	if version.handler == nil {
+		return Errorf("Tool '%s' version %s has no handler", ctx.ToolName, version.Version)
+	}
+	response := version.handler(ctx, input)
+	if version.Deprecated != "" {
+		response = response.WithMeta(MetaDeprecationKey, map[string]interface{}{
+			"version": version.Version,
+			"current": t.Versions[len(t.Versions)-1].Version,
+			"message": version.Deprecated,
+		})
+	}
+	return response
+}
+
+// selectVersion finds the version handling input, and returns input
+// without the version field
+func (t *ToolDefinition) selectVersion(input map[string]interface{}) (*ToolVersion, map[string]interface{}, error) {
+	if requested, ok := input[InputVersionField]; ok {
+		rest := make(map[string]interface{}, len(input))
+		for k, v := range input {
+			if k != InputVersionField {
+				rest[k] = v
+			}
+		}
+		for i := range t.Versions {
+			if fmt.Sprint(requested) == t.Versions[i].Version {
+				return &t.Versions[i], rest, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("unknown %s %v, expected one of %s", InputVersionField, requested, strings.Join(t.versionNames(), ", "))
+	}
+
+	var newestErr error
+	for i := len(t.Versions) - 1; i >= 0; i-- {
+		err := Validate(t.Versions[i].schema(), input)
+		if err == nil {
+			return &t.Versions[i], input, nil
+		}
+		if newestErr == nil {
+			newestErr = err
+		}
+	}
+	return nil, nil, newestErr
+}
+
+// versionsSchema is the input schema a versioned tool is listed with: any
+// of its versions, newest first, each accepting its own version name in
+// the version field
+func (t *ToolDefinition) versionsSchema() map[string]interface{} {
+	versions := make([]interface{}, 0, len(t.Versions))
+	for i := len(t.Versions) - 1; i >= 0; i-- {
+		v := t.Versions[i]
+		schema := make(map[string]interface{}, len(v.schema())+1)
+		for k, value := range v.schema() {
+			schema[k] = value
+		}
+		properties := make(map[string]interface{})
+		if existing, ok := schema["properties"].(map[string]interface{}); ok {
+			for k, value := range existing {
+				properties[k] = value
+			}
+		}
+		properties[InputVersionField] = map[string]interface{}{
+			"const":       v.Version,
+			"description": "Version of the tool's input",
+		}
+		schema["properties"] = properties
+		if v.Deprecated != "" {
+			schema["deprecated"] = true
+		}
+		versions = append(versions, schema)
+	}
+	return map[string]interface{}{
+		"type":  "object",
+		"anyOf": versions,
+	}
+}
+
+func (t *ToolDefinition) versionNames() []string {
+	names := make([]string, len(t.Versions))
+	for i, v := range t.Versions {
+		names[i] = v.Version
+	}
+	return names
+}
+
+// schema returns the version's input schema, any object when it has none
+func (v *ToolVersion) schema() map[string]interface{} {
+	if v.InputSchema == nil {
+		return map[string]interface{}{"type": "object"}
+	}
+	return v.InputSchema
+}
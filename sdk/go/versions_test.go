@@ -0,0 +1,113 @@
+package ftl
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type searchV1 struct {
+	Q string `json:"q"`
+}
+
+type searchV2 struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+func versionedSearch() ToolDefinition {
+	return ToolDefinition{
+		Versions: []ToolVersion{
+			TypedToolVersion("1", map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{"q": map[string]interface{}{"type": "string"}},
+				"required":             []interface{}{"q"},
+				"additionalProperties": false,
+			}, func(ctx *ToolContext, input searchV1) ToolResponse {
+				return Textf("v1 %s", input.Q)
+			}).Deprecate("send query instead of q"),
+			TypedToolVersion("2", map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{"type": "string"},
+					"limit": map[string]interface{}{"type": "integer"},
+				},
+				"required": []interface{}{"query"},
+			}, func(ctx *ToolContext, input searchV2) ToolResponse {
+				return Textf("v2 %s %d", input.Query, input.Limit)
+			}),
+		},
+	}
+}
+
+func TestToolVersions_Call(t *testing.T) {
+	tool := versionedSearch()
+	call := func(input map[string]interface{}) ToolResponse {
+		return tool.call(&ToolContext{Context: context.Background(), ToolName: "search"}, input)
+	}
+
+	tests := []struct {
+		name       string
+		input      map[string]interface{}
+		want       string
+		deprecated bool
+	}{
+		{"matches newest", map[string]interface{}{"query": "go", "limit": 5}, "v2 go 5", false},
+		{"matches old", map[string]interface{}{"q": "go"}, "v1 go", true},
+		{"named version", map[string]interface{}{InputVersionField: "1", "q": "rust"}, "v1 rust", true},
+		{"named current version", map[string]interface{}{InputVersionField: "2", "query": "zig"}, "v2 zig 0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := call(tt.input)
+			if response.IsError || response.Content[0].Text != tt.want {
+				t.Fatalf("call() = %+v, want %q", response, tt.want)
+			}
+			deprecation, ok := response.Meta[MetaDeprecationKey].(map[string]interface{})
+			if ok != tt.deprecated {
+				t.Fatalf("deprecation = %v, want deprecated %v", response.Meta, tt.deprecated)
+			}
+			if ok && (deprecation["version"] != "1" || deprecation["current"] != "2" || deprecation["message"] != "send query instead of q") {
+				t.Errorf("deprecation = %v", deprecation)
+			}
+		})
+	}
+
+	if response := call(map[string]interface{}{InputVersionField: "3"}); !response.IsError || !strings.Contains(response.Content[0].Text, "expected one of 1, 2") {
+		t.Errorf("unknown version = %+v", response)
+	}
+	if response := call(map[string]interface{}{"limit": 5}); !response.IsError || !strings.Contains(response.Content[0].Text, "query") {
+		t.Errorf("input matching no version = %+v", response)
+	}
+}
+
+func TestToolVersions_Schema(t *testing.T) {
+	tool := versionedSearch()
+	schema := tool.metadata("search").InputSchema
+
+	versions, ok := schema["anyOf"].([]interface{})
+	if !ok || len(versions) != 2 || schema["type"] != "object" {
+		t.Fatalf("schema = %v, want any of two versions", schema)
+	}
+	newest := versions[0].(map[string]interface{})
+	if newest["properties"].(map[string]interface{})[InputVersionField].(map[string]interface{})["const"] != "2" || newest["deprecated"] != nil {
+		t.Errorf("newest version should come first and not be deprecated: %v", newest)
+	}
+	if versions[1].(map[string]interface{})["deprecated"] != true {
+		t.Errorf("version 1 should be deprecated: %v", versions[1])
+	}
+
+	// The listed schema accepts every version, with or without its name
+	for _, input := range []map[string]interface{}{
+		{"query": "go"},
+		{"q": "go", InputVersionField: "1"},
+		{"q": "go"},
+	} {
+		if err := Validate(schema, input); err != nil {
+			t.Errorf("Validate(%v) = %v", input, err)
+		}
+	}
+	if err := Validate(schema, map[string]interface{}{"q": "go", InputVersionField: "2"}); err == nil {
+		t.Error("version 2 input with version 1 fields should be invalid")
+	}
+}
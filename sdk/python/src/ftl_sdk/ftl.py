@@ -25,6 +25,9 @@ from .validation import OutputSchemaGenerator
 T = TypeVar("T")
 ToolFunction = TypeVar("ToolFunction", bound=Callable[..., Any])
 
+# Endpoint the gateway probes to check that a component is up
+HEALTH_PATH = "/.well-known/ftl/health"
+
 
 class FTL:
     """
@@ -396,8 +399,12 @@ class FTL:
                 path = request.uri
                 method = request.method
 
+                # Handle GET /.well-known/ftl/health - report the component is up
+                if method == "GET" and path == HEALTH_PATH:
+                    return Response(200, {"content-type": "application/json"}, b'{"status": "ok"}')
+
                 # Handle GET / - return tool metadata
-                if method == "GET" and (path == "/" or path == ""):
+                elif method == "GET" and (path == "/" or path == ""):
                     metadata: list[dict[str, Any]] = []
                     for tool_name, tool in tools.items():
                         tool_metadata = {
@@ -128,6 +128,14 @@ pub fn tools(input: TokenStream) -> TokenStream {
             let path = req.path();
 
             match req.method() {
+                &Method::Get if path == "/.well-known/ftl/health" => {
+                    // Report the component is up for gateway health checks
+                    Response::builder()
+                        .status(200)
+                        .header("Content-Type", "application/json")
+                        .body(r#"{"status":"ok"}"#)
+                        .build()
+                }
                 &Method::Get if path == "/" => {
                     // Return metadata for all tools
                     let tools = vec![
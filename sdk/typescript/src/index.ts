@@ -159,6 +159,12 @@ export interface ResourceContents {
   blob?: string
 }
 
+/**
+ * Endpoint the gateway probes to check that a component is up.
+ * createTools answers it automatically.
+ */
+export const HEALTH_PATH = '/.well-known/ftl/health'
+
 /**
  * Convenience functions for creating responses
  */
@@ -377,6 +383,14 @@ export function createTools<T extends Record<string, ToolDefinition>>(
     const path = url.pathname
     const { method } = request
 
+    // Handle health check from the gateway
+    if (method === 'GET' && path === HEALTH_PATH) {
+      return new Response(JSON.stringify({ status: 'ok' }), {
+        status: 200,
+        headers: { 'Content-Type': 'application/json' },
+      })
+    }
+
     // Handle metadata request
     if (method === 'GET' && path === '/') {
       const metadata = Object.entries(tools).map(([key, tool]) => ({
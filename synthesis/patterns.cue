@@ -22,15 +22,150 @@ import (
 	// - custom: User-provided auth and policy
 	access:       "public" | "private" | "org" | "custom" | *"public"
 	auth?:        #AuthConfig  // Required only for "custom" access
+	// Regions the platform deploys the app to, e.g. ["us-east-1", "eu-west-1"].
+	// Omitted, the platform deploys to its default region.
+	regions?:     [...string & =~"^[a-z][a-z0-9-]*[0-9]$"]
+	// Components every request passes through, in order, before the
+	// gateway routes it to a tool component
+	middleware?:  [...#Middleware]
+
+	// Service dependencies must name other components of the app
+	_componentIDs: [for c in components {c.id}]
+	_serviceDependencies: {
+		for c in components if c.service_dependencies != _|_ for dep in c.service_dependencies {
+			"component \(c.id) depends on \(dep), which must be another component of the app": true & (dep != c.id && list.Contains(_componentIDs, dep))
+		}
+	}
+
+	// Component and middleware IDs name Spin components and the gateway's
+	// /mcp/x/<id> routes, so they must be unique and must not take the
+	// names of the components FTL adds to every app
+	_reservedIDs: ["mcp-gateway", "mcp-authorizer"]
+	_allIDs: list.Concat([_componentIDs, [if middleware != _|_ for mw in middleware {mw.id}]])
+	_idChecks: {
+		for id in _allIDs {
+			"id \(id) is reserved for the component FTL adds to every app": true & !list.Contains(_reservedIDs, id)
+			"id \(id) is used by more than one component or middleware": true & (len([for other in _allIDs if other == id {other}]) == 1)
+		}
+	}
 }
 
 #Component: {
+	id!: string & =~"^[a-z][a-z0-9-]*$"
+	// Tool components serve MCP tools. Static components serve the files
+	// in dir, such as markdown docs or JSON datasets, as MCP resources
+	// through the gateway, using FTL's static component unless a source
+	// is given.
+	type: "tool" | "static" | *"tool"
+	if type == "tool" {
+		source!: #ComponentSource
+	}
+	if type == "static" {
+		dir!: string & !=""
+		source: #ComponentSource | *#StaticSource
+	}
+	build: #BuildConfig | *{command: "", workdir: "", watch: []}
+	// A build profile derives the command, the files to watch and, for
+	// tool components, the source the command produces
+	if build.profile != _|_ {
+		_profiles: #BuildProfiles & {
+			"id": id
+			if build.flags != _|_ {
+				flags: build.flags
+			}
+		}
+		_profile: _profiles[build.profile]
+		build: command: *_profile.command | string
+		build: watch:   *_profile.watch | [...string]
+		if type == "tool" {
+			if build.workdir != _|_ {
+				source: *"\(build.workdir)/\(_profile.output)" | #ComponentSource
+			}
+			if build.workdir == _|_ {
+				source: *_profile.output | #ComponentSource
+			}
+		}
+	}
+	variables?: {[string]: string}
+	// Input transformations applied by the gateway, keyed by tool name or
+	// "*" for every tool of the component
+	transforms?: {[string]: #ToolTransform}
+	// Timeout and retries of the gateway's calls of the component's tools
+	gateway?: #GatewayRoute
+	// Allow the component's tools to call other tools through the gateway
+	call_tools?: bool
+	// Give the component the default key-value store, where the SDKs keep
+	// responses of idempotent tools
+	idempotency?: bool
+	// Key-value stores and SQLite databases the component uses, by label.
+	// "default" is always available; 'ftl up' configures local stores for
+	// other labels.
+	key_value_stores?: [...#StoreLabel]
+	sqlite_databases?: [...#StoreLabel]
+	// Allow the component's tools to ask the client's model for completions
+	// through the gateway (MCP sampling)
+	sampling?: bool
+	// Allow the component's tools to ask the user for input through the
+	// gateway (MCP elicitation)
+	elicitation?: bool
+	// Other components of the app this component calls. Each is allowed as
+	// an outbound host, and its URL is set in the variable <id>_url, with
+	// hyphens in the ID replaced by underscores.
+	service_dependencies?: [...string]
+	// Secrets the component reads as variables of the same name. Values
+	// are provided by the runtime and never appear in ftl.yaml.
+	secrets?: [...string & =~"^[a-z][a-z0-9_]*$"]
+}
+
+#StoreLabel: string & =~"^[a-z][a-z0-9_-]*$"
+
+// A component in the gateway's request chain, such as a rate limiter or
+// a WAF. It receives each JSON-RPC request and passes it on, rewrites it
+// or rejects it.
+#Middleware: {
 	id!: string & =~"^[a-z][a-z0-9-]*$"
 	source!: #ComponentSource
 	build: #BuildConfig | *{command: "", workdir: "", watch: []}
+	if build.profile != _|_ {
+		_profiles: #BuildProfiles & {
+			"id": id
+			if build.flags != _|_ {
+				flags: build.flags
+			}
+		}
+		_profile: _profiles[build.profile]
+		build: command: *_profile.command | string
+		build: watch:   *_profile.watch | [...string]
+		if build.workdir != _|_ {
+			source: *"\(build.workdir)/\(_profile.output)" | #ComponentSource
+		}
+		if build.workdir == _|_ {
+			source: *_profile.output | #ComponentSource
+		}
+	}
 	variables?: {[string]: string}
 }
 
+// Adapts a tool's arguments before the gateway calls the component.
+// Strings in defaults and set may reference token claims as ${claims.NAME}.
+#ToolTransform: {
+	rename?:   {[string]: string}  // client argument name -> component argument name
+	defaults?: {[string]: _}       // values for arguments the client left out
+	set?:      {[string]: _}       // values that replace the client's
+}
+
+// How the gateway calls a component's tools. Omitted, calls run for as
+// long as the client waits and are not retried.
+#GatewayRoute: {
+	// Seconds a call may run before the gateway fails it
+	timeout_seconds?: int & >0
+	// Times a call that could not reach the component is retried. Calls
+	// that reached it are never retried, as the tool may have run.
+	retries?: int & >=0 & <=5
+	// Milliseconds before the first retry, doubled for each further one
+	backoff_ms?: int & >0
+}
+
 // Component source exactly matches Spin's format - no transformation needed
 #ComponentSource: string | #RegistrySource  // string for local paths, registry for remote
 #RegistrySource: {
@@ -41,12 +176,62 @@ import (
 	version!:  string & =~"^[0-9]+\\.[0-9]+\\.[0-9]+(-[a-zA-Z0-9.-]+)?(\\+[a-zA-Z0-9.-]+)?$"
 }
 
+// FTL's static component, which serves the files of static components
+#StaticSource: #RegistrySource & {
+	registry: "ghcr.io"
+	package:  "fastertools:mcp-static"
+	version:  "0.1.0"
+}
+
 #BuildConfig: {
+	// Language toolchain to build with. The profile derives command and
+	// watch, which override it when set.
+	profile?: #BuildProfileName
+	// Extra flags passed to the profile's compiler
+	flags?: [...string]
 	command!: string
 	workdir?: string
 	watch?: [...string]
 }
 
+#BuildProfileName: "rust" | "go" | "python" | "typescript"
+
+// How each language's toolchain builds a component, given the component's
+// ID and the extra compiler flags. Output is the built component, relative
+// to the build's workdir.
+#BuildProfiles: {
+	id!:    string
+	flags:  [...string] | *[]
+	_flags: strings.Join([for f in flags {" " + f}], "")
+
+	// cargo-component names the component after the crate, whose hyphens
+	// become underscores
+	rust: {
+		command: "cargo component build --release --target wasm32-wasip1\(_flags)"
+		watch: ["src/**/*.rs", "Cargo.toml"]
+		output: "target/wasm32-wasip1/release/\(strings.Replace(id, "-", "_", -1)).wasm"
+	}
+	go: {
+		command: "tinygo build -target=wasip1 -gc=leaking -scheduler=none -no-debug\(_flags) -o main.wasm ."
+		watch: ["*.go", "go.mod"]
+		output: "main.wasm"
+	}
+	// The component's dependencies and componentize-py are installed in a
+	// virtual environment of the component
+	python: {
+		command: "python3 -m venv .venv && .venv/bin/pip install --quiet componentize-py -e . && .venv/bin/componentize-py -w spin-http componentize\(_flags) src.main -p . -o app.wasm"
+		watch: ["src/**/*.py", "pyproject.toml"]
+		output: "app.wasm"
+	}
+	// The package's build script bundles the sources and converts the
+	// bundle with j2w, Spin's wrapper of jco; flags go to j2w
+	typescript: {
+		command: "npm install && npm run build" + [if len(flags) > 0 {" --\(_flags)"}, ""][0]
+		watch: ["src/**/*.ts", "src/**/*.js", "package.json", "tsconfig.json"]
+		output: "dist/\(id).wasm"
+	}
+}
+
 #AuthConfig: {
 	// JWT configuration
 	jwt_issuer!: string
@@ -111,6 +296,16 @@ import (
 		if input.auth != _|_ {
 			auth: input.auth
 		}
+
+		// Pass through regions if present
+		if input.regions != _|_ {
+			regions: input.regions
+		}
+
+		// Pass through middleware if present
+		if input.middleware != _|_ {
+			middleware: input.middleware
+		}
 	}
 	
 	// Transform to Spin manifest
@@ -134,6 +329,42 @@ import (
 	// Store platform versions in local fields for reference
 	_gatewayVersion: platform.gateway_version
 	_authorizerVersion: platform.authorizer_version
+
+	// Secrets referenced by any component, declared once as application
+	// variables the platform provides
+	_secrets: {
+		for comp in input.components if comp.secrets != _|_ for name in comp.secrets {
+			"\(name)": {required: true, secret: true}
+		}
+	}
+
+	// Middleware in request order
+	_middleware: [...#Middleware]
+	if input.middleware != _|_ {
+		_middleware: input.middleware
+	}
+	if input.middleware == _|_ {
+		_middleware: []
+	}
+
+	// Components the gateway lists tools of, and static components it
+	// lists resources of
+	_toolComponents: [for comp in input.components if comp.type == "tool" {comp.id}]
+	_staticComponents: [for comp in input.components if comp.type == "static" {comp.id}]
+
+	// Input transforms for the gateway, keyed by component ID
+	_transforms: {
+		for comp in input.components if comp.transforms != _|_ {
+			"\(comp.id)": comp.transforms
+		}
+	}
+
+	// Call timeouts and retries for the gateway, keyed by component ID
+	_routes: {
+		for comp in input.components if comp.type == "tool" && comp.gateway != _|_ {
+			"\(comp.id)": comp.gateway
+		}
+	}
 	
 	output: {
 		spin_manifest_version: 2
@@ -146,12 +377,16 @@ import (
 			}
 		}
 		
+		if len(_secrets) > 0 {
+			variables: _secrets
+		}
+
 		// Build components map
 		component: {
 			// User components
 			// IMPORTANT: User components are intentionally restricted from accessing:
-			// - key_value_stores: KV access is only granted to platform components
-			// - sqlite_databases: Database access is not exposed to users
+			// - key_value_stores and sqlite_databases: only the stores the
+			//   component declares, plus the default key-value store with idempotency
 			// - ai_models: AI model access is not exposed to users
 			// This ensures proper isolation and prevents resource abuse.
 			// Only the following fields are copied from user configuration:
@@ -163,14 +398,96 @@ import (
 					// Only include build for local sources (string type)
 					if (comp.source & string) != _|_ {
 						if comp.build.command != "" {
-							build: comp.build  
+							build: {
+								command: comp.build.command
+								if comp.build.workdir != _|_ {
+									workdir: comp.build.workdir
+								}
+								if comp.build.watch != _|_ {
+									watch: comp.build.watch
+								}
+							}  
 						}
 					}
 					
+					// Static components serve their directory from the root
+					if comp.type == "static" {
+						files: [{source: comp.dir, destination: "/"}]
+					}
+					
 					if comp.variables != _|_ {
 						variables: comp.variables
 					}
-					// NOTE: No key_value_stores, sqlite_databases, or ai_models
+					if comp.secrets != _|_ {
+						variables: {
+							for name in comp.secrets {
+								"\(name)": "{{ \(name) }}"
+							}
+						}
+					}
+					// Tool calls, sampling and elicitation go through the gateway
+					let _outboundHosts = {
+						if comp.call_tools != _|_ if comp.call_tools {
+							"http://mcp-gateway.spin.internal": true
+						}
+						if comp.sampling != _|_ if comp.sampling {
+							"http://mcp-gateway.spin.internal": true
+						}
+						if comp.elicitation != _|_ if comp.elicitation {
+							"http://mcp-gateway.spin.internal": true
+						}
+						if comp.service_dependencies != _|_ for dep in comp.service_dependencies {
+							"http://\(dep).spin.internal": true
+						}
+					}
+					if len(_outboundHosts) > 0 {
+						allowed_outbound_hosts: [for host, _ in _outboundHosts {host}]
+					}
+					if comp.service_dependencies != _|_ {
+						variables: {
+							for dep in comp.service_dependencies {
+								"\(strings.Replace(dep, "-", "_", -1))_url": "http://\(dep).spin.internal"
+							}
+						}
+					}
+					let _kvStores = {
+						if comp.key_value_stores != _|_ {
+							for label in comp.key_value_stores {"\(label)": true}
+						}
+						if comp.idempotency != _|_ if comp.idempotency {
+							default: true
+						}
+					}
+					if len(_kvStores) > 0 {
+						key_value_stores: [for label, _ in _kvStores {label}]
+					}
+					if comp.sqlite_databases != _|_ if len(comp.sqlite_databases) > 0 {
+						sqlite_databases: comp.sqlite_databases
+					}
+					// NOTE: No ai_models
+				}
+			}
+
+			// Middleware components, restricted like user components
+			for mw in _middleware {
+				"\(mw.id)": {
+					source: mw.source
+					if (mw.source & string) != _|_ {
+						if mw.build.command != "" {
+							build: {
+								command: mw.build.command
+								if mw.build.workdir != _|_ {
+									workdir: mw.build.workdir
+								}
+								if mw.build.watch != _|_ {
+									watch: mw.build.watch
+								}
+							}
+						}
+					}
+					if mw.variables != _|_ {
+						variables: mw.variables
+					}
 				}
 			}
 			
@@ -182,12 +499,31 @@ import (
 					version: _gatewayVersion
 				}
 				allowed_outbound_hosts: ["http://*.spin.internal"]
-				// Add component_names if there are user components
-				if len(input.components) > 0 {
+				// Circuit breaker state for component health
+				key_value_stores: ["default"]
+				if len(_middleware) > 0 {
+					variables: middleware_names: strings.Join([for mw in _middleware {mw.id}], ",")
+				}
+				if len(_staticComponents) > 0 {
+					variables: resource_components: strings.Join(_staticComponents, ",")
+				}
+				// Add component_names if there are tool components
+				if len(_toolComponents) > 0 {
 					variables: {
-						component_names: strings.Join([for c in input.components {c.id}], ",")
+						component_names: strings.Join(_toolComponents, ",")
+						if len(_routes) > 0 {
+							component_routes: json.Marshal(_routes)
+						}
+						if len(_transforms) > 0 {
+							tool_transforms: json.Marshal(_transforms)
+						}
 					}
 				}
+				// Claims and identity headers are only trustworthy once the
+				// authorizer has verified the token
+				if _needsAuth {
+					variables: trust_auth_claims: "true"
+				}
 			}
 			
 			// MCP Authorizer (added when auth is enabled using comprehension)
@@ -280,13 +616,19 @@ import (
 				route: {private: true}
 				component: comp.id
 			}]
+
+			// Middleware routes, reachable only from the gateway
+			_middlewareRoutes: [for mw in _middleware {
+				route: {private: true}
+				component: mw.id
+			}]
 			
 			// Select routes based on access mode
 			if _needsAuth {
-				http: list.Concat([_privateRoutes, _componentRoutes])
+				http: list.Concat([_privateRoutes, _componentRoutes, _middlewareRoutes])
 			}
 			if !_needsAuth {
-				http: list.Concat([_publicRoutes, _componentRoutes])
+				http: list.Concat([_publicRoutes, _componentRoutes, _middlewareRoutes])
 			}
 		}
 	}
@@ -145,3 +145,273 @@ func TestSynthesizer_WithOverrides(t *testing.T) {
 		t.Error("Result should contain authorizer for private app")
 	}
 }
+
+func TestSynthesizer_ToolTransforms(t *testing.T) {
+	yamlInput := `
+name: transform-app
+access: private
+components:
+  - id: search
+    source: ./search.wasm
+    transforms:
+      "*":
+        set:
+          tenant_id: "${claims.org_id}"
+      query:
+        rename:
+          query: q
+        defaults:
+          limit: 10
+  - id: plain
+    source: ./plain.wasm
+`
+
+	synth := NewSynthesizer()
+	manifest, err := synth.SynthesizeYAML([]byte(yamlInput))
+	if err != nil {
+		t.Fatalf("Failed to synthesize with transforms: %v", err)
+	}
+
+	for _, want := range []string{
+		`tool_transforms = '{"search":{"*":{"set":{"tenant_id":"${claims.org_id}"}},"query":{"rename":{"query":"q"},"defaults":{"limit":10}}}}'`,
+		`trust_auth_claims = 'true'`,
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("Missing %s in manifest:\n%s", want, manifest)
+		}
+	}
+
+	// Claims are not trusted when the gateway is public
+	manifest, err = synth.SynthesizeYAML([]byte(strings.Replace(yamlInput, "access: private", "access: public", 1)))
+	if err != nil {
+		t.Fatalf("Failed to synthesize public app: %v", err)
+	}
+	if strings.Contains(manifest, "trust_auth_claims") {
+		t.Error("Public gateway should not trust token claims")
+	}
+
+	// Private apps forward the caller's identity without any transforms
+	manifest, err = synth.SynthesizeYAML([]byte(`
+name: identity-app
+access: private
+components:
+  - id: plain
+    source: ./plain.wasm
+`))
+	if err != nil {
+		t.Fatalf("Failed to synthesize private app: %v", err)
+	}
+	if strings.Contains(manifest, "tool_transforms") || !strings.Contains(manifest, `trust_auth_claims = 'true'`) {
+		t.Errorf("Private app without transforms should trust token claims:\n%s", manifest)
+	}
+}
+
+func TestSynthesizer_GatewayRoutes(t *testing.T) {
+	yamlInput := `
+name: routes-app
+components:
+  - id: search
+    source: ./search.wasm
+    gateway:
+      timeout_seconds: 10
+      retries: 2
+      backoff_ms: 200
+  - id: plain
+    source: ./plain.wasm
+`
+
+	synth := NewSynthesizer()
+	manifest, err := synth.SynthesizeYAML([]byte(yamlInput))
+	if err != nil {
+		t.Fatalf("Failed to synthesize with gateway routes: %v", err)
+	}
+	want := `component_routes = '{"search":{"timeout_seconds":10,"retries":2,"backoff_ms":200}}'`
+	if !strings.Contains(manifest, want) {
+		t.Errorf("Missing %s in manifest:\n%s", want, manifest)
+	}
+
+	// Retries are capped so a down component cannot hold calls for long
+	_, err = synth.SynthesizeYAML([]byte(strings.Replace(yamlInput, "retries: 2", "retries: 9", 1)))
+	if err == nil {
+		t.Error("Expected an error for too many retries")
+	}
+}
+
+func TestSynthesizer_ComponentIDs(t *testing.T) {
+	synth := NewSynthesizer()
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "reserved",
+			input: "name: app\ncomponents:\n  - id: mcp-authorizer\n    source: ./auth.wasm\n",
+			want:  "id mcp-authorizer is reserved",
+		},
+		{
+			name:  "duplicate",
+			input: "name: app\ncomponents:\n  - id: geo\n    source: ./geo.wasm\nmiddleware:\n  - id: geo\n    source: ./limiter.wasm\n",
+			want:  "id geo is used by more than one component or middleware",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := synth.SynthesizeYAML([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SynthesizeYAML() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSynthesizer_Secrets(t *testing.T) {
+	yamlInput := `
+name: secrets-app
+components:
+  - id: billing
+    source: ./billing.wasm
+    secrets: [stripe_key, db_password]
+  - id: reports
+    source: ./reports.wasm
+    secrets: [db_password]
+`
+
+	manifest, err := NewSynthesizer().SynthesizeYAML([]byte(yamlInput))
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	for _, want := range []string{
+		"stripe_key = '{{ stripe_key }}'",
+		"db_password = '{{ db_password }}'",
+		"[variables.stripe_key]",
+		"[variables.db_password]",
+		"secret = true",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("Missing %s:\n%s", want, manifest)
+		}
+	}
+
+	_, err = NewSynthesizer().SynthesizeYAML([]byte(`
+name: secrets-app
+components:
+  - id: billing
+    source: ./billing.wasm
+    secrets: [Stripe-Key]
+`))
+	if err == nil {
+		t.Error("Expected an invalid secret name to be rejected")
+	}
+}
+
+func TestSynthesizer_Middleware(t *testing.T) {
+	manifest, err := NewSynthesizer().SynthesizeYAML([]byte(`
+name: guarded-app
+access: private
+middleware:
+  - id: waf
+    source: ./waf.wasm
+  - id: rate-limiter
+    source:
+      registry: ghcr.io
+      package: acme:rate-limiter
+      version: 1.0.0
+components:
+  - id: tool
+    source: ./tool.wasm
+`))
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	for _, want := range []string{
+		"middleware_names = 'waf,rate-limiter'",
+		"component_names = 'tool'",
+		"[component.waf]",
+		"[component.rate-limiter.source]",
+		"component = 'waf'",
+		"component = 'rate-limiter'",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("Missing %s:\n%s", want, manifest)
+		}
+	}
+
+	// Middleware shares the component namespace
+	_, err = NewSynthesizer().SynthesizeYAML([]byte(`
+name: guarded-app
+middleware:
+  - id: tool
+    source: ./waf.wasm
+components:
+  - id: tool
+    source: ./tool.wasm
+`))
+	if err == nil {
+		t.Error("Expected a middleware ID used by a component to be rejected")
+	}
+}
+
+func TestSynthesizer_NoSecrets(t *testing.T) {
+	manifest, err := NewSynthesizer().SynthesizeYAML([]byte(`
+name: plain-app
+components:
+  - id: tool
+    source: ./tool.wasm
+`))
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+	if strings.Contains(manifest, "[variables]") {
+		t.Errorf("Apps without secrets should not declare variables:\n%s", manifest)
+	}
+}
+
+func TestSynthesizer_StaticComponents(t *testing.T) {
+	manifest, err := NewSynthesizer().SynthesizeYAML([]byte(`
+name: docs-app
+components:
+  - id: weather
+    source: ./weather.wasm
+  - id: docs
+    type: static
+    dir: ./docs
+`))
+	if err != nil {
+		t.Fatalf("Failed to synthesize: %v", err)
+	}
+
+	for _, want := range []string{
+		"component_names = 'weather'",
+		"resource_components = 'docs'",
+		"package = 'fastertools:mcp-static'",
+		"source = './docs'",
+		"destination = '/'",
+		"component = 'docs'",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("Missing %s:\n%s", want, manifest)
+		}
+	}
+
+	_, err = NewSynthesizer().SynthesizeYAML([]byte(`
+name: docs-app
+components:
+  - id: docs
+    type: static
+`))
+	if err == nil {
+		t.Error("Expected a static component without dir to be rejected")
+	}
+
+	_, err = NewSynthesizer().SynthesizeYAML([]byte(`
+name: docs-app
+components:
+  - id: weather
+`))
+	if err == nil {
+		t.Error("Expected a tool component without source to be rejected")
+	}
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ReservedComponentIDs are the IDs of the components FTL adds to every
+// app. The app's own components and middleware cannot use them.
+var ReservedComponentIDs = []string{"mcp-gateway", "mcp-authorizer"}
+
+// checkComponentIDs reports component and middleware IDs that are
+// reserved or used more than once. IDs name the app's Spin components and
+// the gateway's /mcp/x/<id> routes, so a collision would send requests to
+// the wrong component instead of failing.
+func checkComponentIDs(doc interface{}) []error {
+	var errs []error
+	seen := make(map[string]string)
+	for _, scope := range []string{"components[]", "middleware[]"} {
+		for _, obj := range scoped(doc, scope, "") {
+			id, _ := obj.fields["id"].(string)
+			if id == "" {
+				continue
+			}
+			if slices.Contains(ReservedComponentIDs, id) {
+				errs = append(errs, fmt.Errorf("%s: id %q is reserved for the component FTL adds to every app", obj.path, id))
+			}
+			if first, ok := seen[id]; ok {
+				errs = append(errs, fmt.Errorf("%s: id %q is already used by %s", obj.path, id, first))
+				continue
+			}
+			seen[id] = obj.path
+		}
+	}
+	return errs
+}
+
+// CheckToolNames reports tools provided by more than one component, given
+// the tools of each component as listed in its metadata. Tool names are
+// qualified by their component only at the gateway's /mcp root, so tools
+// of the same name are ambiguous to clients of other endpoints.
+func CheckToolNames(tools map[string][]string) error {
+	providers := make(map[string][]string)
+	for component, names := range tools {
+		for _, name := range names {
+			if !slices.Contains(providers[name], component) {
+				providers[name] = append(providers[name], component)
+			}
+		}
+	}
+
+	var names []string
+	for name, components := range providers {
+		if len(components) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		components := providers[name]
+		sort.Strings(components)
+		errs = append(errs, fmt.Errorf("tool %q is provided by more than one component: %s", name, strings.Join(components, ", ")))
+	}
+	return errors.Join(errs...)
+}
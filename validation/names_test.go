@@ -0,0 +1,25 @@
+package validation
+
+import "testing"
+
+func TestCheckToolNames(t *testing.T) {
+	if err := CheckToolNames(map[string][]string{
+		"weather": {"forecast", "alerts"},
+		"geo":     {"geocode"},
+	}); err != nil {
+		t.Fatalf("CheckToolNames() error = %v", err)
+	}
+
+	err := CheckToolNames(map[string][]string{
+		"weather": {"forecast", "lookup"},
+		"geo":     {"lookup"},
+		"search":  {"lookup", "query"},
+	})
+	if err == nil {
+		t.Fatal("CheckToolNames() should fail")
+	}
+	want := `tool "lookup" is provided by more than one component: geo, search, weather`
+	if err.Error() != want {
+		t.Errorf("CheckToolNames() error = %q, want %q", err, want)
+	}
+}
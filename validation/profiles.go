@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+
+	"github.com/fastertools/ftl/synthesis"
+)
+
+// BuildProfile is how a language build profile builds a component
+type BuildProfile struct {
+	Command string   `json:"command"`
+	Watch   []string `json:"watch"`
+	Output  string   `json:"output"` // Built component, relative to the build's workdir
+}
+
+// BuildProfiles are the names of the language build profiles
+var BuildProfiles = []string{"rust", "typescript", "python", "go"}
+
+// BuildProfileFor returns how the named profile builds component id, with
+// extra compiler flags. The profiles are defined by the FTL patterns, so
+// this matches what synthesis derives for a build with the profile.
+func BuildProfileFor(profile, id string, flags []string) (*BuildProfile, error) {
+	ctx := cuecontext.New()
+	patterns := ctx.CompileString(synthesis.GetPatterns(), cue.Filename("patterns.cue"))
+	if patterns.Err() != nil {
+		return nil, fmt.Errorf("failed to compile patterns: %w", patterns.Err())
+	}
+
+	profiles := patterns.LookupPath(cue.ParsePath("#BuildProfiles")).
+		FillPath(cue.ParsePath("id"), id)
+	if flags != nil {
+		profiles = profiles.FillPath(cue.ParsePath("flags"), flags)
+	}
+	value := profiles.LookupPath(cue.MakePath(cue.Str(profile)))
+	if !value.Exists() {
+		return nil, fmt.Errorf("unknown build profile '%s' (available: %v)", profile, BuildProfiles)
+	}
+
+	var build BuildProfile
+	if err := value.Decode(&build); err != nil {
+		return nil, fmt.Errorf("failed to resolve build profile '%s': %w", profile, err)
+	}
+	return &build, nil
+}
+
+// Source returns the path of the component the profile builds, for a build
+// run in workdir
+func (p *BuildProfile) Source(workdir string) string {
+	if workdir == "" {
+		return p.Output
+	}
+	return workdir + "/" + p.Output
+}
+
+// WithoutDerived returns a copy of the component without the build command,
+// watch patterns and local source its build profile derives, as it would be
+// configured. Writers of configuration use it to keep the profile in charge.
+func (c *Component) WithoutDerived() *Component {
+	if c.Build == nil || c.Build.Profile == "" {
+		return c
+	}
+	profile, err := BuildProfileFor(c.Build.Profile, c.ID, c.Build.Flags)
+	if err != nil {
+		return c
+	}
+
+	comp := *c
+	build := *c.Build
+	comp.Build = &build
+	if build.Command == profile.Command {
+		build.Command = ""
+	}
+	if slices.Equal(build.Watch, profile.Watch) {
+		build.Watch = nil
+	}
+	if src, ok := c.Source.(*LocalSource); ok && src.Path == profile.Source(build.Workdir) {
+		comp.Source = nil
+	}
+	return &comp
+}
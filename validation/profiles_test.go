@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildProfileFor(t *testing.T) {
+	rust, err := BuildProfileFor("rust", "my-tool", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "cargo component build --release --target wasm32-wasip1", rust.Command)
+	assert.Equal(t, "target/wasm32-wasip1/release/my_tool.wasm", rust.Output)
+	assert.Equal(t, "my-tool/target/wasm32-wasip1/release/my_tool.wasm", rust.Source("my-tool"))
+
+	goProfile, err := BuildProfileFor("go", "geo", []string{"-opt=2"})
+	require.NoError(t, err)
+	assert.Equal(t, "tinygo build -target=wasip1 -gc=leaking -scheduler=none -no-debug -opt=2 -o main.wasm .", goProfile.Command)
+	assert.Equal(t, []string{"*.go", "go.mod"}, goProfile.Watch)
+	assert.Equal(t, "main.wasm", goProfile.Source(""))
+
+	_, err = BuildProfileFor("cobol", "geo", nil)
+	assert.ErrorContains(t, err, "unknown build profile 'cobol'")
+}
+
+func TestExtractApplication_BuildProfile(t *testing.T) {
+	value, err := New().ValidateYAML([]byte(`
+name: app
+components:
+  - id: derived
+    build:
+      profile: python
+      workdir: derived
+  - id: overridden
+    source: custom.wasm
+    build:
+      profile: typescript
+      flags: ["--aot"]
+      watch: ["src/**"]
+`))
+	require.NoError(t, err)
+	app, err := ExtractApplication(value)
+	require.NoError(t, err)
+
+	derived := app.Components[0]
+	assert.Equal(t, &LocalSource{Path: "derived/app.wasm"}, derived.Source)
+	assert.Equal(t, "python", derived.Build.Profile)
+	assert.Equal(t, "python3 -m venv .venv && .venv/bin/pip install --quiet componentize-py -e . && .venv/bin/componentize-py -w spin-http componentize src.main -p . -o app.wasm", derived.Build.Command)
+	assert.Equal(t, []string{"src/**/*.py", "pyproject.toml"}, derived.Build.Watch)
+
+	overridden := app.Components[1]
+	assert.Equal(t, &LocalSource{Path: "custom.wasm"}, overridden.Source)
+	assert.Equal(t, "npm install && npm run build -- --aot", overridden.Build.Command)
+	assert.Equal(t, []string{"--aot"}, overridden.Build.Flags)
+	assert.Equal(t, []string{"src/**"}, overridden.Build.Watch)
+}
@@ -0,0 +1,213 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ruleKind is the type of constraint a Rule applies
+type ruleKind int
+
+const (
+	requiredIf ruleKind = iota
+	requiredWith
+	requiredTogether
+	exactlyOne
+)
+
+// Rule is a declarative cross-field constraint on FTL configuration that
+// the CUE schema does not express, such as fields that depend on each
+// other. Rules are built with RequiredIf, RequiredWith, RequiredTogether
+// and ExactlyOne.
+type Rule struct {
+	// Scope selects the objects the rule applies to: "" for the
+	// application, a dotted field path for a nested object, and a path
+	// segment suffixed with [] for each element of a list. Values in scope
+	// that are not objects, such as local component sources, are skipped.
+	Scope  string
+	Fields []string
+	When   string
+	Equals string
+	kind   ruleKind
+}
+
+// RequiredIf requires field when the field when equals value
+func RequiredIf(scope, field, when, value string) Rule {
+	return Rule{Scope: scope, Fields: []string{field}, When: when, Equals: value, kind: requiredIf}
+}
+
+// RequiredWith requires field when the field with is set
+func RequiredWith(scope, field, with string) Rule {
+	return Rule{Scope: scope, Fields: []string{field}, When: with, kind: requiredWith}
+}
+
+// RequiredTogether requires all of fields when any of them is set
+func RequiredTogether(scope string, fields ...string) Rule {
+	return Rule{Scope: scope, Fields: fields, kind: requiredTogether}
+}
+
+// ExactlyOne requires exactly one of fields to be set
+func ExactlyOne(scope string, fields ...string) Rule {
+	return Rule{Scope: scope, Fields: fields, kind: exactlyOne}
+}
+
+// Rules are the cross-field constraints every FTL application must meet
+var Rules = []Rule{
+	// Custom access validates tokens from the application's own provider
+	RequiredIf("", "auth", "access", "custom"),
+	RequiredTogether("auth", "jwt_issuer", "jwt_audience"),
+	RequiredWith("auth", "jwt_issuer", "jwt_jwks_uri"),
+	RequiredTogether("components[].source", "registry", "package", "version"),
+	RequiredIf("components[]", "dir", "type", "static"),
+}
+
+// Check applies Rules to configuration, which may be an *Application or
+// any value that marshals to FTL configuration JSON, and checks that its
+// component IDs neither repeat nor take reserved names. All violations
+// are reported together.
+func Check(config interface{}) error {
+	doc, err := decode(config)
+	if err != nil {
+		return err
+	}
+	errs := violations(doc, Rules)
+	errs = append(errs, checkComponentIDs(doc)...)
+	return errors.Join(errs...)
+}
+
+// CheckRules applies rules to configuration. See Check.
+func CheckRules(config interface{}, rules []Rule) error {
+	doc, err := decode(config)
+	if err != nil {
+		return err
+	}
+	return errors.Join(violations(doc, rules)...)
+}
+
+// decode converts configuration to its generic JSON form
+func decode(config interface{}) (interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+	return doc, nil
+}
+
+// violations applies rules to the configuration doc
+func violations(doc interface{}, rules []Rule) []error {
+	var errs []error
+	for _, rule := range rules {
+		for _, obj := range scoped(doc, rule.Scope, "") {
+			if msg := rule.violation(obj.fields); msg != "" {
+				if obj.path != "" {
+					msg = obj.path + ": " + msg
+				}
+				errs = append(errs, errors.New(msg))
+			}
+		}
+	}
+	return errs
+}
+
+// violation describes how fields break the rule, or returns "" if they
+// meet it
+func (r Rule) violation(fields map[string]interface{}) string {
+	switch r.kind {
+	case requiredIf:
+		if value, ok := fields[r.When].(string); ok && value == r.Equals && !isSet(fields[r.Fields[0]]) {
+			return fmt.Sprintf("%s is required when %s is %q", r.Fields[0], r.When, r.Equals)
+		}
+	case requiredWith:
+		if isSet(fields[r.When]) && !isSet(fields[r.Fields[0]]) {
+			return fmt.Sprintf("%s is required when %s is set", r.Fields[0], r.When)
+		}
+	case requiredTogether:
+		var set, missing []string
+		for _, f := range r.Fields {
+			if isSet(fields[f]) {
+				set = append(set, f)
+			} else {
+				missing = append(missing, f)
+			}
+		}
+		if len(set) > 0 && len(missing) > 0 {
+			return fmt.Sprintf("%s required when %s set", describe(missing, "is", "are"), describe(set, "is", "are"))
+		}
+	case exactlyOne:
+		count := 0
+		for _, f := range r.Fields {
+			if isSet(fields[f]) {
+				count++
+			}
+		}
+		if count != 1 {
+			return fmt.Sprintf("exactly one of %s must be set", strings.Join(r.Fields, ", "))
+		}
+	}
+	return ""
+}
+
+// describe lists fields with the verb agreeing with their number
+func describe(fields []string, one, many string) string {
+	if len(fields) == 1 {
+		return fields[0] + " " + one
+	}
+	return strings.Join(fields, " and ") + " " + many
+}
+
+func isSet(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+type scopedObject struct {
+	path   string
+	fields map[string]interface{}
+}
+
+// scoped returns the objects selected by scope within v, located at path
+func scoped(v interface{}, scope, path string) []scopedObject {
+	if scope == "" {
+		if fields, ok := v.(map[string]interface{}); ok {
+			return []scopedObject{{path: path, fields: fields}}
+		}
+		return nil
+	}
+
+	segment, rest, _ := strings.Cut(scope, ".")
+	name, isList := strings.CutSuffix(segment, "[]")
+	fields, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	child := fields[name]
+	childPath := name
+	if path != "" {
+		childPath = path + "." + name
+	}
+
+	if !isList {
+		return scoped(child, rest, childPath)
+	}
+	items, _ := child.([]interface{})
+	var objects []scopedObject
+	for i, item := range items {
+		objects = append(objects, scoped(item, rest, fmt.Sprintf("%s[%d]", childPath, i))...)
+	}
+	return objects
+}
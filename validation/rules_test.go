@@ -0,0 +1,156 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		app  *Application
+		want []string
+	}{
+		{
+			name: "valid",
+			app: &Application{
+				Name:   "app",
+				Access: "custom",
+				Auth:   &AuthConfig{JWTIssuer: "https://auth.example.com", JWTAudience: "api"},
+				Components: []*Component{
+					{ID: "local", Source: &LocalSource{Path: "local.wasm"}},
+					{ID: "remote", Source: &RegistrySource{Registry: "ghcr.io", Package: "ns:remote", Version: "1.0.0"}},
+				},
+			},
+		},
+		{
+			name: "custom access without auth",
+			app:  &Application{Name: "app", Access: "custom"},
+			want: []string{`auth is required when access is "custom"`},
+		},
+		{
+			name: "issuer without audience",
+			app:  &Application{Name: "app", Auth: &AuthConfig{JWTIssuer: "https://auth.example.com"}},
+			want: []string{"auth: jwt_audience is required when jwt_issuer is set"},
+		},
+		{
+			name: "jwks without issuer",
+			app:  &Application{Name: "app", Auth: &AuthConfig{JWTJwksURI: "https://auth.example.com/jwks"}},
+			want: []string{"auth: jwt_issuer is required when jwt_jwks_uri is set"},
+		},
+		{
+			name: "registry source without version",
+			app: &Application{Name: "app", Components: []*Component{
+				{ID: "ok", Source: &LocalSource{Path: "ok.wasm"}},
+				{ID: "remote", Source: &RegistrySource{Registry: "ghcr.io", Package: "ns:remote"}},
+			}},
+			want: []string{"components[1].source: version is required when registry and package are set"},
+		},
+		{
+			name: "static component without dir",
+			app: &Application{Name: "app", Components: []*Component{
+				{ID: "docs", Type: ComponentTypeStatic},
+			}},
+			want: []string{`components[0]: dir is required when type is "static"`},
+		},
+		{
+			name: "reserved component id",
+			app: &Application{Name: "app", Components: []*Component{
+				{ID: "mcp-gateway", Source: &LocalSource{Path: "gateway.wasm"}},
+			}},
+			want: []string{`components[0]: id "mcp-gateway" is reserved`},
+		},
+		{
+			name: "duplicate ids",
+			app: &Application{
+				Name: "app",
+				Components: []*Component{
+					{ID: "geo", Source: &LocalSource{Path: "geo.wasm"}},
+					{ID: "geo", Source: &LocalSource{Path: "geo2.wasm"}},
+				},
+				Middleware: []*Component{
+					{ID: "geo", Source: &LocalSource{Path: "limiter.wasm"}},
+				},
+			},
+			want: []string{
+				`components[1]: id "geo" is already used by components[0]`,
+				`middleware[0]: id "geo" is already used by components[0]`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.app)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Check() should fail")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Check() error = %q, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRules_ExactlyOne(t *testing.T) {
+	rules := []Rule{ExactlyOne("components[]", "url", "path")}
+	doc := map[string]interface{}{
+		"components": []interface{}{
+			map[string]interface{}{"url": "https://example.com/a.wasm"},
+			map[string]interface{}{"url": "https://example.com/b.wasm", "path": "b.wasm"},
+			map[string]interface{}{},
+		},
+	}
+
+	err := CheckRules(doc, rules)
+	if err == nil {
+		t.Fatal("CheckRules() should fail")
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "components[0]") {
+		t.Errorf("components[0] has exactly one field: %v", err)
+	}
+	for _, want := range []string{"components[1]: exactly one of url, path must be set", "components[2]: exactly one"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("CheckRules() error = %q, want %q", msg, want)
+		}
+	}
+}
+
+func TestExtractApplication_ChecksRules(t *testing.T) {
+	value, err := New().ValidateYAML([]byte("name: app\naccess: custom\n"))
+	if err != nil {
+		t.Fatalf("ValidateYAML() error = %v", err)
+	}
+	if _, err := ExtractApplication(value); err == nil || !strings.Contains(err.Error(), "auth is required") {
+		t.Errorf("ExtractApplication() error = %v, want the custom access rule", err)
+	}
+}
+
+func TestExtractApplication_StaticComponent(t *testing.T) {
+	value, err := New().ValidateYAML([]byte("name: app\ncomponents:\n  - id: docs\n    type: static\n    dir: ./docs\n"))
+	if err != nil {
+		t.Fatalf("ValidateYAML() error = %v", err)
+	}
+	app, err := ExtractApplication(value)
+	if err != nil {
+		t.Fatalf("ExtractApplication() error = %v", err)
+	}
+
+	comp := app.Components[0]
+	if !comp.IsStatic() || comp.Dir != "./docs" {
+		t.Errorf("component = %+v, want static with dir ./docs", comp)
+	}
+	src, ok := comp.Source.(*RegistrySource)
+	if !ok || src.Package != "fastertools:mcp-static" {
+		t.Errorf("source = %#v, want the static component", comp.Source)
+	}
+}
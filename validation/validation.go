@@ -116,6 +116,13 @@ func ExtractApplication(v cue.Value) (*Application, error) {
 		app.Access = access
 	}
 
+	regionsIter, _ := v.LookupPath(cue.ParsePath("regions")).List()
+	for regionsIter.Next() {
+		if region, err := regionsIter.Value().String(); err == nil {
+			app.Regions = append(app.Regions, region)
+		}
+	}
+
 	// Extract components
 	componentsIter, err := v.LookupPath(cue.ParsePath("components")).List()
 	if err == nil {
@@ -128,6 +135,18 @@ func ExtractApplication(v cue.Value) (*Application, error) {
 		}
 	}
 
+	// Extract middleware, which shares the shape of components
+	middlewareIter, err := v.LookupPath(cue.ParsePath("middleware")).List()
+	if err == nil {
+		for middlewareIter.Next() {
+			mw, err := extractComponent(middlewareIter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("invalid middleware: %w", err)
+			}
+			app.Middleware = append(app.Middleware, mw)
+		}
+	}
+
 	// Extract auth if present
 	authValue := v.LookupPath(cue.ParsePath("auth"))
 	if authValue.Exists() {
@@ -173,6 +192,10 @@ func ExtractApplication(v cue.Value) (*Application, error) {
 		}
 	}
 
+	if err := Check(app); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
 	return app, nil
 }
 
@@ -185,8 +208,19 @@ func extractComponent(v cue.Value) (*Component, error) {
 		return nil, fmt.Errorf("component missing required field 'id'")
 	}
 
+	if typ, err := v.LookupPath(cue.ParsePath("type")).String(); err == nil {
+		comp.Type = typ
+	}
+	if dir, err := v.LookupPath(cue.ParsePath("dir")).String(); err == nil {
+		comp.Dir = dir
+	}
+
 	// Extract source (can be string or struct)
 	sourceValue := v.LookupPath(cue.ParsePath("source"))
+	if def, ok := sourceValue.Default(); ok {
+		// Static components default to FTL's static component
+		sourceValue = def
+	}
 	if sourceStr, err := sourceValue.String(); err == nil {
 		comp.Source = &LocalSource{Path: sourceStr}
 	} else {
@@ -208,6 +242,15 @@ func extractComponent(v cue.Value) (*Component, error) {
 	buildValue := v.LookupPath(cue.ParsePath("build"))
 	if buildValue.Exists() {
 		build := &BuildConfig{}
+		if profile, err := buildValue.LookupPath(cue.ParsePath("profile")).String(); err == nil {
+			build.Profile = profile
+		}
+		flagsIter, _ := buildValue.LookupPath(cue.ParsePath("flags")).List()
+		for flagsIter.Next() {
+			if flag, err := flagsIter.Value().String(); err == nil {
+				build.Flags = append(build.Flags, flag)
+			}
+		}
 		if cmd, err := buildValue.LookupPath(cue.ParsePath("command")).String(); err == nil {
 			build.Command = cmd
 		}
@@ -235,6 +278,64 @@ func extractComponent(v cue.Value) (*Component, error) {
 		}
 	}
 
+	if callTools, err := v.LookupPath(cue.ParsePath("call_tools")).Bool(); err == nil {
+		comp.CallTools = callTools
+	}
+
+	if sampling, err := v.LookupPath(cue.ParsePath("sampling")).Bool(); err == nil {
+		comp.Sampling = sampling
+	}
+	if elicitation, err := v.LookupPath(cue.ParsePath("elicitation")).Bool(); err == nil {
+		comp.Elicitation = elicitation
+	}
+	if idempotency, err := v.LookupPath(cue.ParsePath("idempotency")).Bool(); err == nil {
+		comp.Idempotency = idempotency
+	}
+
+	kvIter, _ := v.LookupPath(cue.ParsePath("key_value_stores")).List()
+	for kvIter.Next() {
+		if label, err := kvIter.Value().String(); err == nil {
+			comp.KeyValueStores = append(comp.KeyValueStores, label)
+		}
+	}
+	sqliteIter, _ := v.LookupPath(cue.ParsePath("sqlite_databases")).List()
+	for sqliteIter.Next() {
+		if label, err := sqliteIter.Value().String(); err == nil {
+			comp.SQLiteDatabases = append(comp.SQLiteDatabases, label)
+		}
+	}
+
+	depsIter, _ := v.LookupPath(cue.ParsePath("service_dependencies")).List()
+	for depsIter.Next() {
+		if dep, err := depsIter.Value().String(); err == nil {
+			comp.ServiceDependencies = append(comp.ServiceDependencies, dep)
+		}
+	}
+
+	secretsIter, _ := v.LookupPath(cue.ParsePath("secrets")).List()
+	for secretsIter.Next() {
+		if name, err := secretsIter.Value().String(); err == nil {
+			comp.Secrets = append(comp.Secrets, name)
+		}
+	}
+
+	// Extract input transforms
+	transformsValue := v.LookupPath(cue.ParsePath("transforms"))
+	if transformsValue.Exists() {
+		if err := transformsValue.Decode(&comp.Transforms); err != nil {
+			return nil, fmt.Errorf("component %s has invalid transforms: %w", comp.ID, err)
+		}
+	}
+
+	// Extract the gateway's call policy
+	gatewayValue := v.LookupPath(cue.ParsePath("gateway"))
+	if gatewayValue.Exists() {
+		comp.Gateway = &GatewayRoute{}
+		if err := gatewayValue.Decode(comp.Gateway); err != nil {
+			return nil, fmt.Errorf("component %s has invalid gateway settings: %w", comp.ID, err)
+		}
+	}
+
 	return comp, nil
 }
 
@@ -246,16 +347,46 @@ type Application struct {
 	Description string            `json:"description,omitempty"`
 	Access      string            `json:"access,omitempty"`
 	Auth        *AuthConfig       `json:"auth,omitempty"`
+	Regions     []string          `json:"regions,omitempty"`
 	Components  []*Component      `json:"components,omitempty"`
+	Middleware  []*Component      `json:"middleware,omitempty"` // In request order, before tool components
 	Variables   map[string]string `json:"variables,omitempty"`
 }
 
 // Component represents a validated component
 type Component struct {
-	ID        string            `json:"id"`
-	Source    ComponentSource   `json:"-"` // Exclude from automatic JSON marshaling
-	Build     *BuildConfig      `json:"build,omitempty"`
-	Variables map[string]string `json:"variables,omitempty"`
+	ID          string                    `json:"id"`
+	Type        string                    `json:"type,omitempty"` // ComponentTypeTool or ComponentTypeStatic
+	Dir         string                    `json:"dir,omitempty"`  // Directory a static component serves
+	Source      ComponentSource           `json:"-"`              // Exclude from automatic JSON marshaling
+	Build       *BuildConfig              `json:"build,omitempty"`
+	Variables   map[string]string         `json:"variables,omitempty"`
+	Transforms  map[string]*ToolTransform `json:"transforms,omitempty"`  // Keyed by tool name, or "*" for all tools
+	Gateway     *GatewayRoute             `json:"gateway,omitempty"`     // Timeout and retries of the gateway's calls
+	CallTools   bool                      `json:"call_tools,omitempty"`  // Tools may call other tools through the gateway
+	Sampling    bool                      `json:"sampling,omitempty"`    // Tools may request sampling from the client through the gateway
+	Elicitation bool                      `json:"elicitation,omitempty"` // Tools may ask the user for input through the gateway
+	Idempotency bool                      `json:"idempotency,omitempty"` // Component may keep responses in the default key-value store
+	Secrets     []string                  `json:"secrets,omitempty"`     // Platform secrets read as variables of the same name
+
+	KeyValueStores  []string `json:"key_value_stores,omitempty"` // Labels of the key-value stores the component uses
+	SQLiteDatabases []string `json:"sqlite_databases,omitempty"` // Labels of the SQLite databases the component uses
+
+	ServiceDependencies []string `json:"service_dependencies,omitempty"` // Other components of the app the component calls
+}
+
+// Component types
+const (
+	// ComponentTypeTool is a component serving MCP tools
+	ComponentTypeTool = "tool"
+	// ComponentTypeStatic is a component serving the files in its Dir as
+	// MCP resources
+	ComponentTypeStatic = "static"
+)
+
+// IsStatic reports whether the component serves static resources
+func (c *Component) IsStatic() bool {
+	return c.Type == ComponentTypeStatic
 }
 
 // MarshalJSON implements custom JSON marshaling for Component to handle the Source interface
@@ -300,13 +431,31 @@ type RegistrySource struct {
 
 func (RegistrySource) isComponentSource() {}
 
-// BuildConfig represents build configuration
+// BuildConfig represents build configuration. With a Profile, Command and
+// Watch are those the profile derives unless the configuration sets them.
 type BuildConfig struct {
+	Profile string   `json:"profile,omitempty"` // Language build profile, see BuildProfileFor
+	Flags   []string `json:"flags,omitempty"`   // Extra compiler flags of the profile
 	Command string   `json:"command"`
 	Workdir string   `json:"workdir,omitempty"`
 	Watch   []string `json:"watch,omitempty"`
 }
 
+// ToolTransform represents input transformations the gateway applies to
+// a tool's arguments before calling the component
+type ToolTransform struct {
+	Rename   map[string]string      `json:"rename,omitempty"`   // Client argument name -> component argument name
+	Defaults map[string]interface{} `json:"defaults,omitempty"` // Values for missing arguments
+	Set      map[string]interface{} `json:"set,omitempty"`      // Values that override the client's
+}
+
+// GatewayRoute represents how the gateway calls a component's tools
+type GatewayRoute struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"` // Seconds a call may run
+	Retries        int `json:"retries,omitempty"`         // Retries of calls that could not reach the component
+	BackoffMS      int `json:"backoff_ms,omitempty"`      // Milliseconds before the first retry
+}
+
 // AuthConfig represents authentication configuration
 type AuthConfig struct {
 	JWTIssuer   string      `json:"jwt_issuer,omitempty"`